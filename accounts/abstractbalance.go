@@ -156,6 +156,9 @@ func (aB *abstractBalance) debitUsageFromConcrete(usage *utils.Decimal,
 			tCost = utils.SumBig(tCost, rcrntCost)
 		}
 	}
+	if tCost == nil || tCost.Sign() == 0 { // free usage, nothing to pay
+		return
+	}
 	clnedUnts := cloneUnitsFromConcretes(aB.cncrtBlncs)
 	for _, cB := range aB.cncrtBlncs {
 		ev := utils.MapStorage{
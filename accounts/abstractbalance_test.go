@@ -22,7 +22,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/migrator"
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -141,3 +143,78 @@ func TestABDebitUsage(t *testing.T) {
 		t.Errorf("Unexpected units in abstract balance: %s", aB.blnCfg.Units)
 	}
 }
+
+func TestABDebitUsageMigratedAccount(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	tmpCache := engine.Cache
+	defer func() { engine.Cache = tmpCache }()
+	engine.Cache = engine.NewCacheS(cfg, nil, nil)
+	dmMig, err := migrator.NewMigratorDataDB(utils.INTERNAL, utils.EmptyString, utils.EmptyString,
+		utils.EmptyString, utils.EmptyString, utils.EmptyString, utils.MetaMSGPACK, cfg.CacheCfg(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dmMig.DataManager()
+	if err = dm.SetAccount(&engine.Account{
+		ID: "cgrates.org:1001",
+		BalanceMap: map[string]engine.Balances{
+			utils.MetaMonetary: {{ID: "MONETARY", Value: 10}},
+			utils.MetaVoice:    {{ID: "VOICE", Value: float64(time.Minute)}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := migrator.NewMigrator(dmMig, dmMig, nil, nil, false, true, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err, _ = m.Migrate([]string{utils.MetaAccountsToProfiles}); err != nil {
+		t.Fatal(err)
+	}
+	ap, err := dm.GetAccountProfile("cgrates.org", "1001", false, false, utils.NonTransactional)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fltrS := engine.NewFilterS(cfg, nil, dm)
+	cB, err := newBalanceOperator(ap.Balances["MONETARY"], nil, fltrS, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cncrtBlncs := []*concreteBalance{cB.(*concreteBalance)}
+	aB, err := newBalanceOperator(ap.Balances["VOICE"], cncrtBlncs, fltrS, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cgrEv := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		Event:  map[string]interface{}{utils.ToR: utils.MetaVoice},
+	}
+	if _, err = aB.debitUsage(utils.NewDecimal(int64(20*time.Second), 0),
+		time.Now(), cgrEv); err != nil {
+		t.Fatal(err)
+	} else if rcv := ap.Balances["VOICE"].Units; rcv.Compare(utils.NewDecimal(int64(40*time.Second), 0)) != 0 {
+		t.Errorf("Unexpected units in abstract balance: %s", rcv)
+	} else if rcv := ap.Balances["MONETARY"].Units; rcv.Compare(utils.NewDecimal(10, 0)) != 0 {
+		t.Errorf("Unexpected units in concrete balance: %s", rcv)
+	}
+	if _, _, err = cncrtBlncs[0].debitUnits(utils.NewDecimal(4, 0), cgrEv.Tenant,
+		utils.MapStorage{utils.MetaReq: cgrEv.Event}); err != nil {
+		t.Fatal(err)
+	} else if rcv := ap.Balances["MONETARY"].Units; rcv.Compare(utils.NewDecimal(6, 0)) != 0 {
+		t.Errorf("Unexpected units in concrete balance: %s", rcv)
+	}
+	// the free units are debited also without concrete balances
+	if aB, err = newBalanceOperator(ap.Balances["VOICE"], nil, fltrS, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = aB.debitUsage(utils.NewDecimal(int64(10*time.Second), 0),
+		time.Now(), cgrEv); err != nil {
+		t.Fatal(err)
+	} else if rcv := ap.Balances["VOICE"].Units; rcv.Compare(utils.NewDecimal(int64(30*time.Second), 0)) != 0 {
+		t.Errorf("Unexpected units in abstract balance: %s", rcv)
+	}
+	cgrEv.Event[utils.ToR] = utils.MetaSMS // the voice balance does not cover other usage
+	if _, err = aB.debitUsage(utils.NewDecimal(1, 0), time.Now(), cgrEv); err != utils.ErrFilterNotPassingNoCaps {
+		t.Errorf("Expected %v, received %v", utils.ErrFilterNotPassingNoCaps, err)
+	}
+}
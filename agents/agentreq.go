@@ -26,6 +26,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cgrates/cgrates/analyzers"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
@@ -519,6 +520,57 @@ func (ar *AgentRequest) setCGRReply(rply utils.NavigableMapper, errRply error) (
 	return
 }
 
+// newAgentSpan starts the span of a request received by the agent, continuing the traceParent if any
+// the context of the span is added to the opts so the API calls made for the request become its children
+func newAgentSpan(name, traceParent string, opts *utils.OrderedNavigableMap) (span *engine.Span) {
+	if span = engine.Tracer.StartSpan(name, engine.SpanKindServer, traceParent); span != nil {
+		opts.Set(&utils.FullPath{
+			PathItems: utils.PathItems{{Field: utils.OptsTraceParent}},
+			Path:      utils.OptsTraceParent,
+		}, utils.NewNMData(span.TraceParent()))
+	}
+	return
+}
+
+// logAgentMessage pushes the message processed by the agent to AnalyzerS
+// the event and the reply are taken from the last processed request, if any
+func logAgentMessage(anz *analyzers.AnalyzerService, method, enc, from, to string,
+	rawReq, rawRply interface{}, agReq *AgentRequest, err error, sTime time.Time) {
+	if anz == nil {
+		return
+	}
+	msg := &analyzers.AgentMessage{
+		Method:      method,
+		Encoding:    enc,
+		Source:      from,
+		Destination: to,
+		RawRequest:  rawAgentMessage(rawReq),
+		RawReply:    rawAgentMessage(rawRply),
+		Error:       err,
+		StartTime:   sTime,
+		EndTime:     time.Now(),
+	}
+	if agReq != nil {
+		msg.CGREvent = config.NMAsCGREvent(agReq.CGRRequest, agReq.Tenant, utils.NestingSep, agReq.Opts)
+		msg.Reply = config.NMAsMapInterface(agReq.Reply, utils.NestingSep)
+	}
+	go anz.LogAgentMessage(msg)
+}
+
+// rawAgentMessage returns the message as it is dumped by the protocol library
+func rawAgentMessage(msg interface{}) string {
+	switch m := msg.(type) {
+	case nil:
+		return utils.EmptyString
+	case string:
+		return m
+	case fmt.Stringer:
+		return m.String()
+	default:
+		return utils.ToIJSON(m)
+	}
+}
+
 func needsMaxUsage(ralsFlags utils.FlagParams) bool {
 	if len(ralsFlags) == 0 {
 		return false
@@ -22,12 +22,15 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/cgrates/cgrates/analyzers"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
@@ -2216,3 +2219,66 @@ func TestNeedsMaxUsage(t *testing.T) {
 		t.Error("Expected flag to need maxUsage")
 	}
 }
+
+func TestLogAgentMessage(t *testing.T) {
+	// nothing to do without AnalyzerS
+	logAgentMessage(nil, "DiameterAgent.CCR", utils.MetaDiameter, utils.EmptyString,
+		utils.EmptyString, nil, nil, nil, nil, time.Now())
+
+	tmpDir, err := ioutil.TempDir(utils.EmptyString, "analyzers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	cfg := config.NewDefaultCGRConfig()
+	cfg.AnalyzerSCfg().DBPath = tmpDir
+	anz, err := analyzers.NewAnalyzerService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer anz.Shutdown()
+	filterS := engine.NewFilterS(cfg, nil, nil)
+	anz.SetFilterS(filterS)
+
+	agReq := NewAgentRequest(nil, nil, nil, nil, nil, nil, "cgrates.org", "", filterS, nil, nil)
+	agReq.CGRRequest.Set(&utils.FullPath{Path: utils.OriginID, PathItems: utils.PathItems{{Field: utils.OriginID}}}, utils.NewNMData("session1"))
+	agReq.CGRRequest.Set(&utils.FullPath{Path: utils.AccountField, PathItems: utils.PathItems{{Field: utils.AccountField}}}, utils.NewNMData("1001"))
+	agReq.Reply.Set(&utils.FullPath{Path: "Result-Code", PathItems: utils.PathItems{{Field: "Result-Code"}}}, utils.NewNMData(2001))
+
+	m := diam.NewRequest(diam.CreditControl, 4, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("session1"))
+	logAgentMessage(anz, "DiameterAgent.CCR", utils.MetaDiameter, "127.0.0.1:3868", "127.0.0.1:3869",
+		m, "Credit-Control-Answer", agReq, nil, time.Now())
+
+	var reply []map[string]interface{}
+	for i := 0; i < 50 && len(reply) == 0; i++ { // indexed asynchronously
+		time.Sleep(10 * time.Millisecond)
+		if err = anz.V1StringQuery(&analyzers.QueryArgs{
+			HeaderFilters:  "+RequestMethod:DiameterAgent.CCR",
+			ContentFilters: []string{"*string:~*req.Event.Account:1001", "*string:~*rep.Result-Code:2001"},
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(reply) != 1 {
+		t.Fatalf("Expected one message, received: %s", utils.ToJSON(reply))
+	}
+	if reply[0][utils.OriginID] != "session1" ||
+		reply[0][utils.RawRequest] != m.String() ||
+		reply[0][utils.RawReply] != "Credit-Control-Answer" {
+		t.Errorf("Unexpected message: %s", utils.ToJSON(reply[0]))
+	}
+}
+
+func TestRawAgentMessage(t *testing.T) {
+	if rcv := rawAgentMessage(nil); rcv != utils.EmptyString {
+		t.Errorf("Expected empty raw message, received: %q", rcv)
+	}
+	if rcv := rawAgentMessage("INVITE"); rcv != "INVITE" {
+		t.Errorf("Expected %q, received: %q", "INVITE", rcv)
+	}
+	pkt := &radigo.Packet{Code: radigo.AccessRequest, Identifier: 1}
+	if rcv, exp := rawAgentMessage(pkt), utils.ToIJSON(pkt); rcv != exp {
+		t.Errorf("Expected %q, received: %q", exp, rcv)
+	}
+}
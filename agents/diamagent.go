@@ -26,6 +26,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cgrates/cgrates/analyzers"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/sessions"
@@ -46,11 +47,12 @@ const (
 
 // NewDiameterAgent initializes a new DiameterAgent
 func NewDiameterAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
-	connMgr *engine.ConnManager) (*DiameterAgent, error) {
+	connMgr *engine.ConnManager, anz *analyzers.AnalyzerService) (*DiameterAgent, error) {
 	da := &DiameterAgent{
 		cgrCfg:  cgrCfg,
 		filterS: filterS,
 		connMgr: connMgr,
+		anz:     anz,
 		raa:     make(map[string]chan *diam.Message),
 		dpa:     make(map[string]chan *diam.Message),
 		peers:   make(map[string]diam.Conn),
@@ -83,6 +85,7 @@ type DiameterAgent struct {
 	cgrCfg   *config.CGRConfig
 	filterS  *engine.FilterS
 	connMgr  *engine.ConnManager
+	anz      *analyzers.AnalyzerService
 	aReqs    int
 	aReqsLck sync.RWMutex
 	raa      map[string]chan *diam.Message
@@ -259,25 +262,35 @@ func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
 	cgrRplyNM := utils.NavigableMap2{}
 	rply := utils.NewOrderedNavigableMap() // share it among different processors
 	opts := utils.NewOrderedNavigableMap()
+	sTime := time.Now()
+	msgName := utils.DiameterAgent + utils.NestingSep + dCmd.Short + "R"
+	span := newAgentSpan(msgName, utils.EmptyString, opts)
 	var processed bool
+	var lastReq *AgentRequest // the last processed request, indexed in AnalyzerS
 	for _, reqProcessor := range da.cgrCfg.DiameterAgentCfg().RequestProcessors {
+		agReq := NewAgentRequest(
+			diamDP, reqVars, &cgrRplyNM, rply, opts,
+			reqProcessor.Tenant, da.cgrCfg.GeneralCfg().DefaultTenant,
+			utils.FirstNonEmpty(reqProcessor.Timezone,
+				da.cgrCfg.GeneralCfg().DefaultTimezone),
+			da.filterS, nil, nil)
 		var lclProcessed bool
-		lclProcessed, err = da.processRequest(
-			reqProcessor,
-			NewAgentRequest(
-				diamDP, reqVars, &cgrRplyNM, rply, opts,
-				reqProcessor.Tenant, da.cgrCfg.GeneralCfg().DefaultTenant,
-				utils.FirstNonEmpty(reqProcessor.Timezone,
-					da.cgrCfg.GeneralCfg().DefaultTimezone),
-				da.filterS, nil, nil))
-		if lclProcessed {
+		if lclProcessed, err = da.processRequest(reqProcessor, agReq); lclProcessed {
 			processed = lclProcessed
+			lastReq = agReq
 		}
 		if err != nil ||
 			(lclProcessed && !reqProcessor.Flags.GetBool(utils.MetaContinue)) {
 			break
 		}
 	}
+	span.End(err)
+	rplyMsg := diamErr // the message written back on the connection
+	defer func() {
+		logAgentMessage(da.anz, msgName, utils.MetaDiameter,
+			c.RemoteAddr().String(), c.LocalAddr().String(),
+			m, rplyMsg, lastReq, err, sTime)
+	}()
 	if err != nil {
 		utils.Logger.Warning(
 			fmt.Sprintf("<%s> error: %s processing message: %s",
@@ -301,6 +314,7 @@ func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
 		writeOnConn(c, diamErr)
 		return
 	}
+	rplyMsg = a
 	writeOnConn(c, a)
 }
 
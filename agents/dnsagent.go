@@ -22,7 +22,9 @@ import (
 	"crypto/tls"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/cgrates/cgrates/analyzers"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/sessions"
@@ -32,8 +34,8 @@ import (
 
 // NewDNSAgent is the constructor for DNSAgent
 func NewDNSAgent(cgrCfg *config.CGRConfig, fltrS *engine.FilterS,
-	connMgr *engine.ConnManager) (da *DNSAgent, err error) {
-	da = &DNSAgent{cgrCfg: cgrCfg, fltrS: fltrS, connMgr: connMgr}
+	connMgr *engine.ConnManager, anz *analyzers.AnalyzerService) (da *DNSAgent, err error) {
+	da = &DNSAgent{cgrCfg: cgrCfg, fltrS: fltrS, connMgr: connMgr, anz: anz}
 	err = da.initDNSServer()
 	return
 }
@@ -44,6 +46,7 @@ type DNSAgent struct {
 	fltrS   *engine.FilterS   // connection towards FilterS
 	server  *dns.Server
 	connMgr *engine.ConnManager
+	anz     *analyzers.AnalyzerService // indexes the messages, nil if AnalyzerS is disabled
 }
 
 // initDNSServer instantiates the DNS server
@@ -115,27 +118,36 @@ func (da *DNSAgent) handleMessage(w dns.ResponseWriter, req *dns.Msg) {
 	cgrRplyNM := utils.NavigableMap2{}
 	rplyNM := utils.NewOrderedNavigableMap() // share it among different processors
 	opts := utils.NewOrderedNavigableMap()
+	sTime := time.Now()
+	msgName := utils.DNSAgent + utils.NestingSep + dns.TypeToString[req.Question[0].Qtype]
+	span := newAgentSpan(msgName, utils.EmptyString, opts)
 	var processed bool
 	var err error
+	var lastReq *AgentRequest // the last processed request, indexed in AnalyzerS
 	for _, reqProcessor := range da.cgrCfg.DNSAgentCfg().RequestProcessors {
+		agReq := NewAgentRequest(
+			dnsDP, reqVars, &cgrRplyNM, rplyNM,
+			opts, reqProcessor.Tenant,
+			da.cgrCfg.GeneralCfg().DefaultTenant,
+			utils.FirstNonEmpty(da.cgrCfg.DNSAgentCfg().Timezone,
+				da.cgrCfg.GeneralCfg().DefaultTimezone),
+			da.fltrS, nil, nil)
 		var lclProcessed bool
-		lclProcessed, err = da.processRequest(
-			reqProcessor,
-			NewAgentRequest(
-				dnsDP, reqVars, &cgrRplyNM, rplyNM,
-				opts, reqProcessor.Tenant,
-				da.cgrCfg.GeneralCfg().DefaultTenant,
-				utils.FirstNonEmpty(da.cgrCfg.DNSAgentCfg().Timezone,
-					da.cgrCfg.GeneralCfg().DefaultTimezone),
-				da.fltrS, nil, nil))
-		if lclProcessed {
+		if lclProcessed, err = da.processRequest(reqProcessor, agReq); lclProcessed {
 			processed = lclProcessed
+			lastReq = agReq
 		}
 		if err != nil ||
 			(lclProcessed && !reqProcessor.Flags.GetBool(utils.MetaContinue)) {
 			break
 		}
 	}
+	span.End(err)
+	defer func() {
+		logAgentMessage(da.anz, msgName, utils.MetaDNS,
+			w.RemoteAddr().String(), w.LocalAddr().String(),
+			req, rply, lastReq, err, sTime)
+	}()
 	if err != nil {
 		utils.Logger.Warning(
 			fmt.Sprintf("<%s> error: %s processing message: %s from %s",
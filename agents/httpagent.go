@@ -54,6 +54,9 @@ type HTTPAgent struct {
 	sessionConns  []string
 }
 
+// traceParentHeader is the HTTP header carrying the W3C trace context of the request
+const traceParentHeader = "traceparent"
+
 // ServeHTTP implements http.Handler interface
 func (ha *HTTPAgent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	dcdr, err := newHADataProvider(ha.reqPayload, req) // dcdr will provide information from request
@@ -66,6 +69,8 @@ func (ha *HTTPAgent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	cgrRplyNM := utils.NavigableMap2{}
 	rplyNM := utils.NewOrderedNavigableMap()
 	opts := utils.NewOrderedNavigableMap()
+	span := newAgentSpan(utils.HTTPAgent+utils.NestingSep+req.URL.Path,
+		req.Header.Get(traceParentHeader), opts)
 	reqVars := utils.NavigableMap2{utils.RemoteHost: utils.NewNMData(req.RemoteAddr)}
 	for _, reqProcessor := range ha.reqProcessors {
 		agReq := NewAgentRequest(dcdr, reqVars, &cgrRplyNM, rplyNM,
@@ -75,6 +80,7 @@ func (ha *HTTPAgent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			ha.filterS, nil, nil)
 		lclProcessed, err := ha.processRequest(reqProcessor, agReq)
 		if err != nil {
+			span.End(err)
 			utils.Logger.Warning(
 				fmt.Sprintf("<%s> error: %s processing request: %s",
 					utils.HTTPAgent, err.Error(), utils.ToJSON(agReq)))
@@ -87,6 +93,7 @@ func (ha *HTTPAgent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			break
 		}
 	}
+	span.End(nil)
 	encdr, err := newHAReplyEncoder(ha.rplyPayload, w)
 	if err != nil {
 		utils.Logger.Warning(
@@ -20,7 +20,9 @@ package agents
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/cgrates/cgrates/analyzers"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/sessions"
@@ -41,7 +43,7 @@ const (
 )
 
 func NewRadiusAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
-	connMgr *engine.ConnManager) (ra *RadiusAgent, err error) {
+	connMgr *engine.ConnManager, anz *analyzers.AnalyzerService) (ra *RadiusAgent, err error) {
 	dts := make(map[string]*radigo.Dictionary, len(cgrCfg.RadiusAgentCfg().ClientDictionaries))
 	for clntID, dictPath := range cgrCfg.RadiusAgentCfg().ClientDictionaries {
 		utils.Logger.Info(
@@ -52,7 +54,7 @@ func NewRadiusAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 		}
 	}
 	dicts := radigo.NewDictionaries(dts)
-	ra = &RadiusAgent{cgrCfg: cgrCfg, filterS: filterS, connMgr: connMgr}
+	ra = &RadiusAgent{cgrCfg: cgrCfg, filterS: filterS, connMgr: connMgr, anz: anz}
 	secrets := radigo.NewSecrets(cgrCfg.RadiusAgentCfg().ClientSecrets)
 	ra.rsAuth = radigo.NewServer(cgrCfg.RadiusAgentCfg().ListenNet,
 		cgrCfg.RadiusAgentCfg().ListenAuth, secrets, dicts,
@@ -69,6 +71,7 @@ type RadiusAgent struct {
 	cgrCfg  *config.CGRConfig // reference for future config reloads
 	connMgr *engine.ConnManager
 	filterS *engine.FilterS
+	anz     *analyzers.AnalyzerService
 	rsAuth  *radigo.Server
 	rsAcct  *radigo.Server
 }
@@ -82,7 +85,10 @@ func (ra *RadiusAgent) handleAuth(req *radigo.Packet) (rpl *radigo.Packet, err e
 	cgrRplyNM := utils.NavigableMap2{}
 	rplyNM := utils.NewOrderedNavigableMap()
 	opts := utils.NewOrderedNavigableMap()
+	sTime := time.Now()
+	span := newAgentSpan(utils.RadiusAgent+utils.NestingSep+"Auth", utils.EmptyString, opts)
 	var processed bool
+	var lastReq *AgentRequest // the last processed request, indexed in AnalyzerS
 	reqVars := utils.NavigableMap2{utils.RemoteHost: utils.NewNMData(req.RemoteAddr().String())}
 	for _, reqProcessor := range ra.cgrCfg.RadiusAgentCfg().RequestProcessors {
 		agReq := NewAgentRequest(dcdr, reqVars, &cgrRplyNM, rplyNM, opts,
@@ -94,11 +100,22 @@ func (ra *RadiusAgent) handleAuth(req *radigo.Packet) (rpl *radigo.Packet, err e
 		var lclProcessed bool
 		if lclProcessed, err = ra.processRequest(req, reqProcessor, agReq, rpl); lclProcessed {
 			processed = lclProcessed
+			lastReq = agReq
 		}
 		if err != nil || (lclProcessed && !reqProcessor.Flags.GetBool(utils.MetaContinue)) {
 			break
 		}
 	}
+	span.End(err)
+	defer func(err error) {
+		var rawRply interface{}
+		if rpl != nil {
+			rawRply = rpl
+		}
+		logAgentMessage(ra.anz, utils.RadiusAgent+utils.NestingSep+"Auth", utils.MetaRadius,
+			req.RemoteAddr().String(), ra.cgrCfg.RadiusAgentCfg().ListenAuth,
+			req, rawRply, lastReq, err, sTime)
+	}(err)
 
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("<%s> error: <%s> ignoring request: %s",
@@ -127,7 +144,10 @@ func (ra *RadiusAgent) handleAcct(req *radigo.Packet) (rpl *radigo.Packet, err e
 	cgrRplyNM := utils.NavigableMap2{}
 	rplyNM := utils.NewOrderedNavigableMap()
 	opts := utils.NewOrderedNavigableMap()
+	sTime := time.Now()
+	span := newAgentSpan(utils.RadiusAgent+utils.NestingSep+"Acct", utils.EmptyString, opts)
 	var processed bool
+	var lastReq *AgentRequest // the last processed request, indexed in AnalyzerS
 	reqVars := utils.NavigableMap2{utils.RemoteHost: utils.NewNMData(req.RemoteAddr().String())}
 	for _, reqProcessor := range ra.cgrCfg.RadiusAgentCfg().RequestProcessors {
 		agReq := NewAgentRequest(dcdr, reqVars, &cgrRplyNM, rplyNM, opts,
@@ -138,11 +158,22 @@ func (ra *RadiusAgent) handleAcct(req *radigo.Packet) (rpl *radigo.Packet, err e
 		var lclProcessed bool
 		if lclProcessed, err = ra.processRequest(req, reqProcessor, agReq, rpl); lclProcessed {
 			processed = lclProcessed
+			lastReq = agReq
 		}
 		if err != nil || (lclProcessed && !reqProcessor.Flags.GetBool(utils.MetaContinue)) {
 			break
 		}
 	}
+	span.End(err)
+	defer func(err error) {
+		var rawRply interface{}
+		if rpl != nil {
+			rawRply = rpl
+		}
+		logAgentMessage(ra.anz, utils.RadiusAgent+utils.NestingSep+"Acct", utils.MetaRadius,
+			req.RemoteAddr().String(), ra.cgrCfg.RadiusAgentCfg().ListenAcct,
+			req, rawRply, lastReq, err, sTime)
+	}(err)
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("<%s> error: <%s> ignoring request: %s, ",
 			utils.RadiusAgent, err.Error(), utils.ToIJSON(req)))
@@ -25,6 +25,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cgrates/cgrates/analyzers"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/sessions"
@@ -50,11 +51,12 @@ var (
 
 // NewSIPAgent will construct a SIPAgent
 func NewSIPAgent(connMgr *engine.ConnManager, cfg *config.CGRConfig,
-	filterS *engine.FilterS) (sa *SIPAgent, err error) {
+	filterS *engine.FilterS, anz *analyzers.AnalyzerService) (sa *SIPAgent, err error) {
 	sa = &SIPAgent{
 		connMgr: connMgr,
 		filterS: filterS,
 		cfg:     cfg,
+		anz:     anz,
 		ackMap:  make(map[string]chan struct{}),
 	}
 	msgTemplates := sa.cfg.TemplatesCfg()
@@ -79,6 +81,7 @@ type SIPAgent struct {
 	connMgr  *engine.ConnManager
 	filterS  *engine.FilterS
 	cfg      *config.CGRConfig
+	anz      *analyzers.AnalyzerService
 	stopChan chan struct{}
 	ackMap   map[string]chan struct{}
 	ackLocks sync.RWMutex
@@ -291,6 +294,11 @@ func (sa *SIPAgent) answerMessage(messageStr, addr string, write func(ans []byte
 }
 
 func (sa *SIPAgent) handleMessage(sipMessage sipingo.Message, remoteHost string) (sipAnswer sipingo.Message) {
+	sTime := time.Now()
+	var rawReq string // dumped before the message is changed into the reply
+	if sa.anz != nil {
+		rawReq = sipMessage.String()
+	}
 	if sipMessage[userAgentHeader] != "" {
 		sipMessage[userAgentHeader] = fmt.Sprintf("%s@%s", utils.CGRateS, utils.Version)
 	}
@@ -307,6 +315,8 @@ func (sa *SIPAgent) handleMessage(sipMessage sipingo.Message, remoteHost string)
 		utils.RemoteHost: utils.NewNMData(remoteHost),
 		method:           utils.NewNMData(sipMessage.MethodFrom(requestHeader)),
 	}
+	msgName := utils.SIPAgent + utils.NestingSep + sipMessage.MethodFrom(requestHeader)
+	span := newAgentSpan(msgName, utils.EmptyString, opts)
 	// build the negative error answer
 	sErr, err := sipErr(
 		dp, sipMessage.Clone(), reqVars,
@@ -321,6 +331,7 @@ func (sa *SIPAgent) handleMessage(sipMessage sipingo.Message, remoteHost string)
 		return bareSipErr(sipMessage, sipServerErr)
 	}
 
+	var lastReq *AgentRequest // the last processed request, indexed in AnalyzerS
 	for _, reqProcessor := range sa.cfg.SIPAgentCfg().RequestProcessors {
 		agReq := NewAgentRequest(dp, reqVars, &cgrRplyNM, rplyNM,
 			opts, reqProcessor.Tenant, sa.cfg.GeneralCfg().DefaultTenant,
@@ -336,12 +347,23 @@ func (sa *SIPAgent) handleMessage(sipMessage sipingo.Message, remoteHost string)
 		}
 		if lclProcessed {
 			processed = lclProcessed
+			lastReq = agReq
 		}
 		if err != nil ||
 			(lclProcessed && !reqProcessor.Flags.GetBool(utils.MetaContinue)) {
 			break
 		}
 	}
+	span.End(err)
+	defer func() {
+		var rawRply interface{}
+		if len(sipAnswer) != 0 {
+			rawRply = sipAnswer
+		}
+		logAgentMessage(sa.anz, msgName, utils.MetaSIP,
+			remoteHost, sa.cfg.SIPAgentCfg().Listen,
+			rawReq, rawRply, lastReq, err, sTime)
+	}()
 	if err != nil { // write err message on conection 500 Server Error
 		utils.Logger.Warning(
 			fmt.Sprintf("<%s> error: %s processing message: %s from %s",
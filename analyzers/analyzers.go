@@ -130,6 +130,13 @@ func (aS *AnalyzerService) logTrafic(id uint64, method string,
 		NewInfoRPC(id, method, params, result, err, enc, from, to, sTime, eTime))
 }
 
+// LogAgentMessage indexes a message processed by one of the agents
+// the documents are correlated with the API calls through the OriginID of the event
+func (aS *AnalyzerService) LogAgentMessage(msg *AgentMessage) error {
+	return aS.db.Index(utils.ConcatenatedKey(msg.Method, strconv.FormatInt(msg.StartTime.UnixNano(), 10)),
+		NewInfoAgent(msg))
+}
+
 // QueryArgs the structure that we use to filter the API calls
 type QueryArgs struct {
 	// a string based on the query language(https://blevesearch.com/docs/Query-String-Query/) that we send to bleve
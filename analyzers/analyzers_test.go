@@ -401,3 +401,99 @@ func TestAnalyzersV1Search(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestAnalyzersLogAgentMessage(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.AnalyzerSCfg().DBPath = "/tmp/analyzers_agents"
+	if err := os.RemoveAll(cfg.AnalyzerSCfg().DBPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(cfg.AnalyzerSCfg().DBPath, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cfg.AnalyzerSCfg().DBPath)
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	anz, err := NewAnalyzerService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anz.SetFilterS(engine.NewFilterS(cfg, nil, dm))
+	t1 := time.Now()
+	cgrEv := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "EV1",
+		Event: map[string]interface{}{
+			utils.OriginID:     "session1",
+			utils.AccountField: "1001",
+		},
+	}
+	if err = anz.LogAgentMessage(&AgentMessage{
+		Method:      "DiameterAgent.CCR",
+		Encoding:    utils.MetaDiameter,
+		Source:      "127.0.0.1:3868",
+		Destination: "127.0.0.1:3869",
+		RawRequest:  "Credit-Control-Request",
+		RawReply:    "Credit-Control-Answer",
+		CGREvent:    cgrEv,
+		Reply:       map[string]interface{}{"Result-Code": "2001"},
+		StartTime:   t1,
+		EndTime:     t1.Add(time.Millisecond),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err = anz.LogAgentMessage(&AgentMessage{
+		Method:     "DiameterAgent.CCR",
+		Encoding:   utils.MetaDiameter,
+		RawRequest: "Credit-Control-Request",
+		Error:      utils.ErrNotFound,
+		StartTime:  t1.Add(time.Millisecond),
+		EndTime:    t1.Add(2 * time.Millisecond),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err = anz.logTrafic(0, utils.SessionSv1InitiateSession, cgrEv, utils.OK, nil,
+		utils.MetaJSON, "127.0.0.1:5565", "127.0.0.1:2012", t1, t1.Add(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	expRply := []map[string]interface{}{{
+		"RequestDestination": "127.0.0.1:3869",
+		"RequestDuration":    "1ms",
+		"RequestEncoding":    utils.MetaDiameter,
+		"RequestMethod":      "DiameterAgent.CCR",
+		"RequestParams":      json.RawMessage(utils.ToJSON(cgrEv)),
+		"Reply":              json.RawMessage(`{"Result-Code":"2001"}`),
+		"RequestSource":      "127.0.0.1:3868",
+		"RequestStartTime":   t1.UTC().Format(time.RFC3339),
+		"ReplyError":         nil,
+		"OriginID":           "session1",
+		"RawRequest":         "Credit-Control-Request",
+		"RawReply":           "Credit-Control-Answer",
+	}}
+	var reply []map[string]interface{}
+	if err = anz.V1StringQuery(&QueryArgs{HeaderFilters: "+OriginID:session1"}, &reply); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(expRply, reply) {
+		t.Errorf("Expected %s received: %s", utils.ToJSON(expRply), utils.ToJSON(reply))
+	}
+	// the agent message is correlated with the API calls through the event
+	reply = nil
+	if err = anz.V1StringQuery(&QueryArgs{
+		HeaderFilters:  "RequestEncoding:" + utils.MetaDiameter + " RequestEncoding:" + utils.MetaJSON,
+		ContentFilters: []string{"*string:~*req.Event.OriginID:session1"},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	} else if len(reply) != 2 {
+		t.Errorf("Expected 2 hits received: %s", utils.ToJSON(reply))
+	}
+	reply = nil
+	if err = anz.V1StringQuery(&QueryArgs{
+		HeaderFilters:  "RequestEncoding:" + utils.MetaDiameter,
+		ContentFilters: []string{"*string:~*hdr.ReplyError:" + utils.ErrNotFound.Error()},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	} else if len(reply) != 1 || reply[0][utils.RequestParams] == nil ||
+		string(reply[0][utils.RequestParams].(json.RawMessage)) != "{}" {
+		t.Errorf("Unexpected reply: %s", utils.ToJSON(reply))
+	}
+}
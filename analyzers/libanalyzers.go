@@ -78,6 +78,74 @@ type InfoRPC struct {
 	ReplyError    interface{}
 }
 
+// AgentMessage is a message received by one of the agents together with its reply
+type AgentMessage struct {
+	Method      string // the agent and the type of the message, ie: DiameterAgent.CCR
+	Encoding    string // the protocol of the message, ie: *diameter
+	Source      string
+	Destination string
+
+	RawRequest string          // the request as received on the wire
+	RawReply   string          // the reply as sent on the wire
+	CGREvent   *utils.CGREvent // the event built from the request by the last matching processor
+	Reply      interface{}     // the fields populated by the reply templates
+	Error      error
+
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// NewInfoAgent returns the structure indexed for a message processed by an agent
+func NewInfoAgent(msg *AgentMessage) *InfoAgent {
+	var e interface{}
+	if msg.Error != nil {
+		e = msg.Error.Error()
+	}
+	var originID string
+	var params interface{} = make(map[string]interface{}) // not processed, keep it filterable
+	if msg.CGREvent != nil {
+		originID = utils.IfaceAsString(msg.CGREvent.Event[utils.OriginID])
+		params = msg.CGREvent
+	}
+	return &InfoAgent{
+		RequestDuration:  msg.EndTime.Sub(msg.StartTime),
+		RequestStartTime: msg.StartTime,
+
+		RequestEncoding:    msg.Encoding,
+		RequestSource:      msg.Source,
+		RequestDestination: msg.Destination,
+
+		RequestMethod: msg.Method,
+		RequestParams: utils.ToJSON(params),
+		Reply:         utils.ToJSON(msg.Reply),
+		ReplyError:    e,
+
+		OriginID:   originID,
+		RawRequest: msg.RawRequest,
+		RawReply:   msg.RawReply,
+	}
+}
+
+// InfoAgent the structure indexed for the agent messages
+// shares the fields with InfoRPC so both can be queried the same way
+type InfoAgent struct {
+	RequestDuration  time.Duration
+	RequestStartTime time.Time
+
+	RequestEncoding    string
+	RequestSource      string
+	RequestDestination string
+
+	RequestMethod string
+	RequestParams interface{}
+	Reply         interface{}
+	ReplyError    interface{}
+
+	OriginID   string
+	RawRequest string
+	RawReply   string
+}
+
 type rpcAPI struct {
 	ID     uint64      `json:"id"`
 	Method string      `json:"method"`
@@ -190,7 +190,7 @@ func (apierSv1 *APIerSv1) SetAccount(attr *utils.AttrSetAccount, reply *string)
 	}
 	accID := utils.ConcatenatedKey(tnt, attr.Account)
 	dirtyActionPlans := make(map[string]*engine.ActionPlan)
-	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+	_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
 		var ub *engine.Account
 		if bal, _ := apierSv1.DataManager.GetAccount(accID); bal != nil {
 			ub = bal
@@ -199,6 +199,7 @@ func (apierSv1 *APIerSv1) SetAccount(attr *utils.AttrSetAccount, reply *string)
 				ID: accID,
 			}
 		}
+		ub.SetLockTokens(tokens)
 		if attr.ActionPlanID != "" {
 			_, err := guardian.Guardian.Guard(func() (interface{}, error) {
 				acntAPids, err := apierSv1.DataManager.GetAccountActionPlans(accID, false, utils.NonTransactional)
@@ -465,14 +466,9 @@ func (apierSv1 *APIerSv1) modifyBalance(aType string, attr *AttrAddBalance, repl
 		tnt = apierSv1.Config.GeneralCfg().DefaultTenant
 	}
 	accID := utils.ConcatenatedKey(tnt, attr.Account)
-	if _, err = apierSv1.DataManager.GetAccount(accID); err != nil {
-		// create account if does not exist
-		account := &engine.Account{
-			ID: accID,
-		}
-		if err = apierSv1.DataManager.SetAccount(account); err != nil {
-			return
-		}
+	// create account if not exists
+	if err = engine.CreateAccountIfMissing(apierSv1.DataManager, accID); err != nil {
+		return
 	}
 	at := &engine.ActionTiming{}
 	//check if we have extra data
@@ -547,14 +543,9 @@ func (apierSv1 *APIerSv1) SetBalance(attr *utils.AttrSetBalance, reply *string)
 	}
 
 	accID := utils.ConcatenatedKey(tnt, attr.Account)
-	if _, err = apierSv1.DataManager.GetAccount(accID); err != nil {
-		// create account if not exists
-		account := &engine.Account{
-			ID: accID,
-		}
-		if err = apierSv1.DataManager.SetAccount(account); err != nil {
-			return
-		}
+	// create account if not exists
+	if err = engine.CreateAccountIfMissing(apierSv1.DataManager, accID); err != nil {
+		return
 	}
 	at := &engine.ActionTiming{}
 	//check if we have extra data
@@ -613,14 +604,9 @@ func (apierSv1 *APIerSv1) SetBalances(attr *utils.AttrSetBalances, reply *string
 	}
 
 	accID := utils.ConcatenatedKey(tnt, attr.Account)
-	if _, err = apierSv1.DataManager.GetAccount(accID); err != nil {
-		// create account if not exists
-		account := &engine.Account{
-			ID: accID,
-		}
-		if err = apierSv1.DataManager.SetAccount(account); err != nil {
-			return
-		}
+	// create account if not exists
+	if err = engine.CreateAccountIfMissing(apierSv1.DataManager, accID); err != nil {
+		return
 	}
 	for _, bal := range attr.Balances {
 		at := &engine.ActionTiming{}
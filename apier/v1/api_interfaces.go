@@ -277,6 +277,10 @@ type ReplicatorSv1Interface interface {
 	GetActionProfile(tntID *utils.TenantIDWithOpts, reply *engine.ActionProfile) error
 	SetActionProfile(args *engine.ActionProfileWithOpts, reply *string) error
 	RemoveActionProfile(args *utils.TenantIDWithOpts, reply *string) error
+
+	GetPortedNumber(tntID *utils.TenantIDWithOpts, reply *engine.PortedNumber) error
+	SetPortedNumber(args *engine.PortedNumberWithOpts, reply *string) error
+	RemovePortedNumber(args *utils.TenantIDWithOpts, reply *string) error
 }
 
 type ActionSv1Interface interface {
@@ -288,3 +292,9 @@ type ActionSv1Interface interface {
 type AccountSv1Interface interface {
 	Ping(ign *utils.CGREvent, reply *string) error
 }
+
+type InvoiceSv1Interface interface {
+	Ping(ign *utils.CGREvent, reply *string) error
+	GenerateInvoice(args *utils.ArgsGenerateInvoice, reply *engine.Invoice) error
+	GetInvoices(args *utils.InvoicesFilterWithOpts, reply *[]*engine.Invoice) error
+}
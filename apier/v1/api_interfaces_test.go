@@ -128,3 +128,8 @@ func TestActionSv1Interface(t *testing.T) {
 	_ = AccountSv1Interface(NewDispatcherActionSv1(nil))
 	_ = AccountSv1Interface(NewActionSv1(nil))
 }
+
+func TestInvoiceSv1Interface(t *testing.T) {
+	_ = InvoiceSv1Interface(NewDispatcherInvoiceSv1(nil))
+	_ = InvoiceSv1Interface(NewInvoiceSv1(nil))
+}
@@ -0,0 +1,41 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// GetAuditLog returns the recorded API calls matching the filter, ordered by time
+// only the entries recorded with the *stordb audit_storage can be queried
+func (apierSv1 *APIerSv1) GetAuditLog(args *utils.AuditLogFilter, reply *[]*engine.AuditEntry) (err error) {
+	if apierSv1.CdrDb == nil {
+		return utils.NewErrNotConnected(utils.StorDB)
+	}
+	var aes []*engine.AuditEntry
+	if aes, err = apierSv1.CdrDb.GetAuditEntries(args); err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = aes
+	return
+}
@@ -122,6 +122,16 @@ func (cdrSv1 *CDRsV1) GetCDRs(args *utils.RPCCDRsFilterWithOpts, reply *[]*engin
 	return cdrSv1.CDRs.V1GetCDRs(*args, reply)
 }
 
+// GetCDRsAggregates returns the CDRs grouped by the fields in GroupBy together with their summaries
+func (cdrSv1 *CDRsV1) GetCDRsAggregates(args *utils.RPCCDRsAggregationWithOpts, reply *[]*utils.CDRsAggregate) error {
+	return cdrSv1.CDRs.V1GetCDRsAggregates(args, reply)
+}
+
+// GetWholesaleMargins returns the margins of the paired customer and supplier CDRs
+func (cdrSv1 *CDRsV1) GetWholesaleMargins(args *engine.ArgsWholesaleMargins, reply *[]*engine.WholesaleMargin) error {
+	return cdrSv1.CDRs.V1GetWholesaleMargins(args, reply)
+}
+
 func (cdrSv1 *CDRsV1) Ping(ign *utils.CGREvent, reply *string) error {
 	*reply = utils.Pong
 	return nil
@@ -0,0 +1,119 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// SetConfigDB stores the sections in ConfigDB, validating them against the current config first
+// the sections are applied on this engine, the other engines with config_db enabled reloading them on their next sync
+func (apierSv1 *APIerSv1) SetConfigDB(args *config.SetConfigArgs, reply *string) (err error) {
+	if len(args.Config) == 0 {
+		return utils.NewErrMandatoryIeMissing("Config")
+	}
+	sections := make([]string, 0, len(args.Config))
+	sectsJSON := make(map[string]string, len(args.Config))
+	for section, sectCfg := range args.Config {
+		if err = engine.ConfigDBSection(section); err != nil {
+			return
+		}
+		var sectJSON []byte
+		if sectJSON, err = json.Marshal(sectCfg); err != nil {
+			return
+		}
+		if err = engine.ConfigDBFields(section, string(sectJSON)); err != nil {
+			return
+		}
+		sections = append(sections, section)
+		sectsJSON[section] = string(sectJSON)
+	}
+	sort.Strings(sections)
+	var rply string
+	if err = apierSv1.Config.V1SetConfig(&config.SetConfigArgs{
+		Config: args.Config,
+		DryRun: true,
+	}, &rply); err != nil {
+		return
+	}
+	if args.DryRun {
+		*reply = utils.OK
+		return
+	}
+	for _, section := range sections {
+		if _, err = apierSv1.DataManager.SetConfigSection(section, sectsJSON[section]); err != nil {
+			return utils.NewErrServerError(err)
+		}
+	}
+	if err = apierSv1.Config.V1SetConfig(&config.SetConfigArgs{
+		Config: args.Config,
+	}, &rply); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	*reply = utils.OK
+	return
+}
+
+// GetConfigDB returns the section stored in ConfigDB, all the sections if the section is empty
+func (apierSv1 *APIerSv1) GetConfigDB(args *config.SectionWithOpts, reply *[]*engine.ConfigSection) (err error) {
+	var cfgSects []*engine.ConfigSection
+	if args.Section == utils.EmptyString {
+		if cfgSects, err = apierSv1.DataManager.GetConfigSections(); err != nil {
+			if err != utils.ErrNotFound {
+				err = utils.NewErrServerError(err)
+			}
+			return
+		}
+		sort.Slice(cfgSects, func(i, j int) bool {
+			return cfgSects[i].Section < cfgSects[j].Section
+		})
+		*reply = cfgSects
+		return
+	}
+	var cfgSect *engine.ConfigSection
+	if cfgSect, err = apierSv1.DataManager.GetConfigSection(args.Section); err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = []*engine.ConfigSection{cfgSect}
+	return
+}
+
+// RemoveConfigDB removes the section from ConfigDB
+// the engines with config_db enabled restore the section from their config files on the next sync
+func (apierSv1 *APIerSv1) RemoveConfigDB(args *config.SectionWithOpts, reply *string) (err error) {
+	if args.Section == utils.EmptyString {
+		return utils.NewErrMandatoryIeMissing("Section")
+	}
+	if err = apierSv1.DataManager.RemoveConfigSection(args.Section); err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = utils.OK
+	return
+}
@@ -0,0 +1,101 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAPIerSv1ConfigDB(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	apierSv1 := &APIerSv1{
+		DataManager: engine.NewDataManager(engine.NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil),
+		Config:      cfg,
+	}
+	var reply string
+	expected := "section <data_db> can not be stored in ConfigDB"
+	if err := apierSv1.SetConfigDB(&config.SetConfigArgs{
+		Config: map[string]interface{}{
+			config.DATADB_JSN: map[string]interface{}{"db_type": "*mongo"},
+		},
+	}, &reply); err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, received %v", expected, err)
+	}
+	expected = "<AttributeS> process_runs needs to be bigger than 0"
+	if err := apierSv1.SetConfigDB(&config.SetConfigArgs{
+		Config: map[string]interface{}{
+			config.ATTRIBUTE_JSN: map[string]interface{}{"enabled": true, "process_runs": 0},
+		},
+	}, &reply); err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, received %v", expected, err)
+	}
+	if err := apierSv1.SetConfigDB(&config.SetConfigArgs{
+		Config: map[string]interface{}{
+			config.GENERAL_JSN: map[string]interface{}{"node_id": "NODE1"},
+		},
+		DryRun: true,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	var cfgSects []*engine.ConfigSection
+	if err := apierSv1.GetConfigDB(&config.SectionWithOpts{}, &cfgSects); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+
+	if err := apierSv1.SetConfigDB(&config.SetConfigArgs{
+		Config: map[string]interface{}{
+			config.GENERAL_JSN:   map[string]interface{}{"node_id": "NODE1"},
+			config.ATTRIBUTE_JSN: map[string]interface{}{"enabled": true},
+		},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	} else if reply != utils.OK {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	if cfg.GeneralCfg().NodeID != "NODE1" {
+		t.Errorf("Expected %q, received %q", "NODE1", cfg.GeneralCfg().NodeID)
+	}
+	if !cfg.AttributeSCfg().Enabled {
+		t.Error("Expected AttributeS enabled")
+	}
+	if err := apierSv1.GetConfigDB(&config.SectionWithOpts{}, &cfgSects); err != nil {
+		t.Fatal(err)
+	} else if len(cfgSects) != 2 ||
+		cfgSects[0].Section != config.ATTRIBUTE_JSN ||
+		cfgSects[1].Section != config.GENERAL_JSN ||
+		cfgSects[1].Config != `{"node_id":"NODE1"}` ||
+		cfgSects[1].Version != 1 {
+		t.Errorf("Unexpected sections: %s", utils.ToJSON(cfgSects))
+	}
+
+	if err := apierSv1.RemoveConfigDB(&config.SectionWithOpts{}, &reply); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing("Section").Error() {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := apierSv1.RemoveConfigDB(&config.SectionWithOpts{Section: config.GENERAL_JSN}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if err := apierSv1.GetConfigDB(&config.SectionWithOpts{Section: config.GENERAL_JSN}, &cfgSects); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+}
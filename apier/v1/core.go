@@ -50,6 +50,11 @@ func (cS *CoreSv1) Ping(ign *utils.CGREvent, reply *string) error {
 	return nil
 }
 
+// Snapshot writes a new snapshot for the persisted internal DBs
+func (cS *CoreSv1) Snapshot(arg *utils.TenantWithOpts, reply *string) error {
+	return cS.cS.Snapshot(arg, reply)
+}
+
 // Sleep is used to test the concurrent requests mechanism
 func (cS *CoreSv1) Sleep(arg *utils.DurationArgs, reply *string) error {
 	time.Sleep(arg.Duration)
@@ -775,6 +775,14 @@ func (dS *DispatcherSCDRsV1) GetCDRsCount(args *utils.RPCCDRsFilterWithOpts, rep
 	return dS.dS.CDRsV1GetCDRsCount(args, reply)
 }
 
+func (dS *DispatcherSCDRsV1) GetCDRsAggregates(args *utils.RPCCDRsAggregationWithOpts, reply *[]*utils.CDRsAggregate) error {
+	return dS.dS.CDRsV1GetCDRsAggregates(args, reply)
+}
+
+func (dS *DispatcherSCDRsV1) GetWholesaleMargins(args *engine.ArgsWholesaleMargins, reply *[]*engine.WholesaleMargin) error {
+	return dS.dS.CDRsV1GetWholesaleMargins(args, reply)
+}
+
 func (dS *DispatcherSCDRsV1) StoreSessionCost(args *engine.AttrCDRSStoreSMCost, reply *string) error {
 	return dS.dS.CDRsV1StoreSessionCost(args, reply)
 }
@@ -868,6 +876,11 @@ func (dS *DispatcherCoreSv1) Sleep(arg *utils.DurationArgs, reply *string) error
 	return dS.dS.CoreSv1Sleep(arg, reply)
 }
 
+// Snapshot writes a new snapshot for the persisted internal DBs
+func (dS *DispatcherCoreSv1) Snapshot(args *utils.TenantWithOpts, reply *string) error {
+	return dS.dS.CoreSv1Snapshot(args, reply)
+}
+
 func NewDispatcherRALsV1(dps *dispatchers.DispatcherService) *DispatcherRALsV1 {
 	return &DispatcherRALsV1{dS: dps}
 }
@@ -1301,6 +1314,21 @@ func (dS *DispatcherReplicatorSv1) RemoveAccountProfile(args *utils.TenantIDWith
 	return dS.dS.ReplicatorSv1RemoveAccountProfile(args, reply)
 }
 
+// GetPortedNumber .
+func (dS *DispatcherReplicatorSv1) GetPortedNumber(tntID *utils.TenantIDWithOpts, reply *engine.PortedNumber) error {
+	return dS.dS.ReplicatorSv1GetPortedNumber(tntID, reply)
+}
+
+// SetPortedNumber .
+func (dS *DispatcherReplicatorSv1) SetPortedNumber(args *engine.PortedNumberWithOpts, reply *string) error {
+	return dS.dS.ReplicatorSv1SetPortedNumber(args, reply)
+}
+
+// RemovePortedNumber .
+func (dS *DispatcherReplicatorSv1) RemovePortedNumber(args *utils.TenantIDWithOpts, reply *string) error {
+	return dS.dS.ReplicatorSv1RemovePortedNumber(args, reply)
+}
+
 // GetActionProfile .
 func (dS *DispatcherReplicatorSv1) GetActionProfile(tntID *utils.TenantIDWithOpts, reply *engine.ActionProfile) error {
 	return dS.dS.ReplicatorSv1GetActionProfile(tntID, reply)
@@ -1361,3 +1389,27 @@ type DispatcherAccountSv1 struct {
 func (dR *DispatcherAccountSv1) Ping(args *utils.CGREvent, reply *string) error {
 	return dR.dR.AccountSv1Ping(args, reply)
 }
+
+func NewDispatcherInvoiceSv1(dps *dispatchers.DispatcherService) *DispatcherInvoiceSv1 {
+	return &DispatcherInvoiceSv1{dR: dps}
+}
+
+// Exports RPC from InvoiceS
+type DispatcherInvoiceSv1 struct {
+	dR *dispatchers.DispatcherService
+}
+
+// Ping implements InvoiceSv1Ping
+func (dR *DispatcherInvoiceSv1) Ping(args *utils.CGREvent, reply *string) error {
+	return dR.dR.InvoiceSv1Ping(args, reply)
+}
+
+// GenerateInvoice implements InvoiceSv1GenerateInvoice
+func (dR *DispatcherInvoiceSv1) GenerateInvoice(args *utils.ArgsGenerateInvoice, reply *engine.Invoice) error {
+	return dR.dR.InvoiceSv1GenerateInvoice(args, reply)
+}
+
+// GetInvoices implements InvoiceSv1GetInvoices
+func (dR *DispatcherInvoiceSv1) GetInvoices(args *utils.InvoicesFilterWithOpts, reply *[]*engine.Invoice) error {
+	return dR.dR.InvoiceSv1GetInvoices(args, reply)
+}
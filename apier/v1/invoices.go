@@ -0,0 +1,57 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/invoices"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// NewInvoiceSv1 initializes InvoiceSv1
+func NewInvoiceSv1(iS *invoices.InvoiceS) *InvoiceSv1 {
+	return &InvoiceSv1{iS: iS}
+}
+
+// InvoiceSv1 exports RPC from InvoiceS
+type InvoiceSv1 struct {
+	iS *invoices.InvoiceS
+}
+
+// Call implements rpcclient.ClientConnector interface for internal RPC
+func (iSv1 *InvoiceSv1) Call(serviceMethod string,
+	args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(iSv1, serviceMethod, args, reply)
+}
+
+// Ping return pong if the service is active
+func (iSv1 *InvoiceSv1) Ping(ign *utils.CGREvent, reply *string) error {
+	*reply = utils.Pong
+	return nil
+}
+
+// GenerateInvoice builds and stores the invoice of one account for the given billing cycle
+func (iSv1 *InvoiceSv1) GenerateInvoice(args *utils.ArgsGenerateInvoice, reply *engine.Invoice) error {
+	return iSv1.iS.V1GenerateInvoice(args, reply)
+}
+
+// GetInvoices returns the stored invoices matching the filter
+func (iSv1 *InvoiceSv1) GetInvoices(args *utils.InvoicesFilterWithOpts, reply *[]*engine.Invoice) error {
+	return iSv1.iS.V1GetInvoices(args, reply)
+}
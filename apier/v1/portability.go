@@ -0,0 +1,121 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// GetPortedNumber returns the number portability entry of a number
+func (apierSv1 *APIerSv1) GetPortedNumber(arg *utils.TenantID, reply *engine.PortedNumber) error {
+	if missing := utils.MissingStructFields(arg, []string{utils.ID}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	tnt := arg.Tenant
+	if tnt == utils.EmptyString {
+		tnt = apierSv1.Config.GeneralCfg().DefaultTenant
+	}
+	pn, err := apierSv1.DataManager.GetPortedNumber(tnt, arg.ID, true, true, utils.NonTransactional)
+	if err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	*reply = *pn
+	return nil
+}
+
+// GetPortedNumberIDs returns the list of ported numbers registered for a tenant
+func (apierSv1 *APIerSv1) GetPortedNumberIDs(tenantArg *utils.PaginatorWithTenant, pnIDs *[]string) error {
+	tenant := tenantArg.Tenant
+	if tenant == utils.EmptyString {
+		tenant = apierSv1.Config.GeneralCfg().DefaultTenant
+	}
+	prfx := utils.PortedNumberPrefix + tenant + utils.ConcatenatedKeySep
+	keys, err := apierSv1.DataManager.DataDB().GetKeysForPrefix(prfx)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return utils.ErrNotFound
+	}
+	retIDs := make([]string, len(keys))
+	for i, key := range keys {
+		retIDs[i] = key[len(prfx):]
+	}
+	*pnIDs = tenantArg.PaginateStringSlice(retIDs)
+	return nil
+}
+
+type PortedNumberWithCache struct {
+	*engine.PortedNumber
+	Cache *string
+	Opts  map[string]interface{}
+}
+
+// SetPortedNumber adds/updates the number portability entry of a number
+func (apierSv1 *APIerSv1) SetPortedNumber(args *PortedNumberWithCache, reply *string) error {
+	if missing := utils.MissingStructFields(args.PortedNumber, []string{utils.ID}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if args.Tenant == utils.EmptyString {
+		args.Tenant = apierSv1.Config.GeneralCfg().DefaultTenant
+	}
+	if err := apierSv1.DataManager.SetPortedNumber(args.PortedNumber); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	//generate a loadID for CachePortedNumbers and store it in database
+	if err := apierSv1.DataManager.SetLoadIDs(map[string]int64{utils.CachePortedNumbers: time.Now().UnixNano()}); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	//handle caching for PortedNumber
+	if err := apierSv1.CallCache(args.Cache, args.Tenant, utils.CachePortedNumbers,
+		args.TenantID(), nil, nil, args.Opts); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// RemovePortedNumber removes the number portability entry of a number
+func (apierSv1 *APIerSv1) RemovePortedNumber(arg *utils.TenantIDWithCache, reply *string) error {
+	if missing := utils.MissingStructFields(arg, []string{utils.ID}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	tnt := arg.Tenant
+	if tnt == utils.EmptyString {
+		tnt = apierSv1.Config.GeneralCfg().DefaultTenant
+	}
+	if err := apierSv1.DataManager.RemovePortedNumber(tnt,
+		arg.ID, utils.NonTransactional); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	//generate a loadID for CachePortedNumbers and store it in database
+	if err := apierSv1.DataManager.SetLoadIDs(map[string]int64{utils.CachePortedNumbers: time.Now().UnixNano()}); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	//handle caching for PortedNumber
+	if err := apierSv1.CallCache(arg.Cache, tnt, utils.CachePortedNumbers,
+		utils.ConcatenatedKey(tnt, arg.ID), nil, nil, arg.Opts); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	*reply = utils.OK
+	return nil
+}
@@ -177,6 +177,9 @@ func testPrecacheGetCacheStatsAfterRestart(t *testing.T) {
 		utils.CacheDispatcherFilterIndexes:   {},
 		utils.CacheDispatcherProfiles:        {Items: 6},
 		utils.CacheDispatcherHosts:           {Items: 1},
+		utils.CachePortedNumbers:             {},
+		utils.CacheSharedSessions:            {},
+		utils.CacheConfigSections:            {},
 		utils.CacheDispatcherRoutes:          {},
 		utils.CacheDispatcherLoads:           {},
 		utils.CacheDestinations:              {Items: 5},
@@ -245,6 +248,8 @@ func testPrecacheGetCacheStatsAfterRestart(t *testing.T) {
 		utils.CacheTBLTPFilters:                 {},
 		utils.CacheSessionCostsTBL:              {},
 		utils.CacheCDRsTBL:                      {},
+		utils.CacheAuditLogTBL:                  {},
+		utils.CacheInvoicesTBL:                  {},
 		utils.CacheTBLTPRoutes:                  {},
 		utils.CacheTBLTPAttributes:              {},
 		utils.CacheTBLTPChargers:                {},
@@ -304,6 +304,16 @@ func (rplSv1 *ReplicatorSv1) GetAccountProfile(tntID *utils.TenantIDWithOpts, re
 	return nil
 }
 
+// GetPortedNumber returns the number portability entry from the DataDB
+func (rplSv1 *ReplicatorSv1) GetPortedNumber(tntID *utils.TenantIDWithOpts, reply *engine.PortedNumber) error {
+	if rcv, err := rplSv1.dm.DataDB().GetPortedNumberDrv(tntID.Tenant, tntID.ID); err != nil {
+		return err
+	} else {
+		*reply = *rcv
+	}
+	return nil
+}
+
 //GetResourceProfile
 func (rplSv1 *ReplicatorSv1) GetItemLoadIDs(itemID *utils.StringWithOpts, reply *map[string]int64) error {
 	if rcv, err := rplSv1.dm.DataDB().GetItemLoadIDsDrv(itemID.Arg); err != nil {
@@ -545,6 +555,15 @@ func (rplSv1 *ReplicatorSv1) SetAccountProfile(acp *utils.AccountProfileWithOpts
 	return nil
 }
 
+// SetPortedNumber stores the number portability entry in the DataDB
+func (rplSv1 *ReplicatorSv1) SetPortedNumber(pn *engine.PortedNumberWithOpts, reply *string) error {
+	if err := rplSv1.dm.DataDB().SetPortedNumberDrv(pn.PortedNumber); err != nil {
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
+
 // RemoveThreshold
 func (rplSv1 *ReplicatorSv1) RemoveThreshold(args *utils.TenantIDWithOpts, reply *string) error {
 	if err := rplSv1.dm.DataDB().RemoveThresholdDrv(args.Tenant, args.ID); err != nil {
@@ -756,6 +775,15 @@ func (rplSv1 *ReplicatorSv1) RemoveAccountProfile(args *utils.TenantIDWithOpts,
 	return nil
 }
 
+// RemovePortedNumber removes the number portability entry from the DataDB
+func (rplSv1 *ReplicatorSv1) RemovePortedNumber(args *utils.TenantIDWithOpts, reply *string) error {
+	if err := rplSv1.dm.DataDB().RemovePortedNumberDrv(args.Tenant, args.ID); err != nil {
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
+
 func (rplSv1 *ReplicatorSv1) RemoveDispatcherHost(args *utils.TenantIDWithOpts, reply *string) error {
 	if err := rplSv1.dm.DataDB().RemoveDispatcherHostDrv(args.Tenant, args.ID); err != nil {
 		return err
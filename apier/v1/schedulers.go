@@ -135,14 +135,8 @@ func (schdSv1 *SchedulerSv1) ExecuteActionPlans(attr *utils.AttrsExecuteActionPl
 	// try get account
 	// if not exist set in DM
 	accID := utils.ConcatenatedKey(attr.Tenant, attr.AccountID)
-	if _, err = schdSv1.dm.GetAccount(accID); err != nil {
-		// create account if does not exist
-		account := &engine.Account{
-			ID: accID,
-		}
-		if err = schdSv1.dm.SetAccount(account); err != nil {
-			return
-		}
+	if err = engine.CreateAccountIfMissing(schdSv1.dm, accID); err != nil {
+		return
 	}
 	for _, apID := range attr.ActionPlanIDs {
 		apl, err := schdSv1.dm.GetActionPlan(apID, false, utils.NonTransactional)
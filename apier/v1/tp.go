@@ -22,9 +22,11 @@ package v1
 
 import (
 	"encoding/base64"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
@@ -148,6 +150,51 @@ func (apierSv1 *APIerSv1) ExportTPToFolder(attrs *utils.AttrDirExportTP, exporte
 	return nil
 }
 
+// ExportDataDBToFolder exports the live data in DataDB as tariff plan, to be loaded back with cgr-loader
+// the TPid is optional, defaulting to *datadb
+// the rating and accounting data of the classic tariff plan can not be exported, the tables containing it being listed as skipped
+func (apierSv1 *APIerSv1) ExportDataDBToFolder(attrs *utils.AttrDirExportTP, exported *utils.ExportedTPStats) error {
+	tpID := utils.MetaDataDB
+	if attrs.TPid != nil && *attrs.TPid != utils.EmptyString {
+		tpID = *attrs.TPid
+	}
+	dir := apierSv1.Config.GeneralCfg().TpExportPath
+	if attrs.ExportPath != nil {
+		dir = *attrs.ExportPath
+	}
+	fileFormat := utils.CSV
+	if attrs.FileFormat != nil {
+		fileFormat = *attrs.FileFormat
+	}
+	sep := ","
+	if attrs.FieldSeparator != nil {
+		sep = *attrs.FieldSeparator
+	}
+	compress := false
+	if attrs.Compress != nil {
+		compress = *attrs.Compress
+	}
+	dr := engine.NewDataDBLoadReader(apierSv1.DataManager)
+	skipped, err := dr.SkippedTables()
+	if err != nil {
+		return utils.NewErrServerError(err)
+	}
+	if len(skipped) != 0 {
+		utils.Logger.Warning(fmt.Sprintf("<%s> export of DataDB skipped the tables: %s",
+			utils.ApierS, strings.Join(skipped, utils.FieldsSep)))
+	}
+	tpExporter, err := engine.NewTPExporter(dr, tpID, dir, fileFormat, sep, compress)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	}
+	if err := tpExporter.Run(); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	*exported = *tpExporter.ExportStats()
+	exported.SkippedTables = skipped
+	return nil
+}
+
 func (apierSv1 *APIerSv1) ExportTPToZipString(attrs *utils.AttrDirExportTP, reply *string) error {
 	if attrs.TPid == nil || *attrs.TPid == utils.EmptyString {
 		return utils.NewErrMandatoryIeMissing(utils.TPid)
@@ -74,10 +74,11 @@ func (apierSv1 *APIerSv1) AddAccountActionTriggers(attr *AttrAddAccountActionTri
 	}
 	accID := utils.ConcatenatedKey(tnt, attr.Account)
 	var account *engine.Account
-	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+	_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
 		if account, err = apierSv1.DataManager.GetAccount(accID); err != nil {
 			return 0, err
 		}
+		account.SetLockTokens(tokens)
 		if attr.ActionTriggerOverwrite {
 			account.ActionTriggers = make(engine.ActionTriggers, 0)
 		}
@@ -127,13 +128,14 @@ func (apierSv1 *APIerSv1) RemoveAccountActionTriggers(attr *AttrRemoveAccountAct
 		tnt = apierSv1.Config.GeneralCfg().DefaultTenant
 	}
 	accID := utils.ConcatenatedKey(tnt, attr.Account)
-	_, err := guardian.Guardian.Guard(func() (interface{}, error) {
+	_, err := guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
 		var account *engine.Account
 		if acc, err := apierSv1.DataManager.GetAccount(accID); err == nil {
 			account = acc
 		} else {
 			return 0, err
 		}
+		account.SetLockTokens(tokens)
 		var newActionTriggers engine.ActionTriggers
 		for _, at := range account.ActionTriggers {
 			if (attr.UniqueID == "" || at.UniqueID == attr.UniqueID) &&
@@ -146,7 +148,7 @@ func (apierSv1 *APIerSv1) RemoveAccountActionTriggers(attr *AttrRemoveAccountAct
 		account.ActionTriggers = newActionTriggers
 		account.InitCounters()
 		return 0, apierSv1.DataManager.SetAccount(account)
-	}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.AccountPrefix+accID)
 	if err != nil {
 		*reply = err.Error()
 		return err
@@ -173,12 +175,13 @@ func (apierSv1 *APIerSv1) ResetAccountActionTriggers(attr *AttrResetAccountActio
 	}
 	accID := utils.ConcatenatedKey(tnt, attr.Account)
 	var account *engine.Account
-	_, err := guardian.Guardian.Guard(func() (interface{}, error) {
+	_, err := guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
 		if acc, err := apierSv1.DataManager.GetAccount(accID); err == nil {
 			account = acc
 		} else {
 			return 0, err
 		}
+		account.SetLockTokens(tokens)
 		for _, at := range account.ActionTriggers {
 			if (attr.UniqueID == "" || at.UniqueID == attr.UniqueID) &&
 				(attr.GroupID == "" || at.ID == attr.GroupID) {
@@ -361,12 +364,13 @@ func (apierSv1 *APIerSv1) SetAccountActionTriggers(attr *AttrSetAccountActionTri
 	}
 	accID := utils.ConcatenatedKey(tnt, attr.Account)
 	var account *engine.Account
-	_, err := guardian.Guardian.Guard(func() (interface{}, error) {
+	_, err := guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
 		if acc, err := apierSv1.DataManager.GetAccount(accID); err == nil {
 			account = acc
 		} else {
 			return 0, err
 		}
+		account.SetLockTokens(tokens)
 		var foundOne bool
 		for _, at := range account.ActionTriggers {
 			if updated, err := attr.UpdateActionTrigger(at,
@@ -587,11 +591,12 @@ func (apierSv1 *APIerSv1) AddTriggeredAction(attr AttrAddActionTrigger, reply *s
 		at.Balance.SharedGroups = &utils.StringMap{attr.BalanceSharedGroup: true}
 	}
 	acntID := utils.ConcatenatedKey(tnt, attr.Account)
-	_, err := guardian.Guardian.Guard(func() (interface{}, error) {
+	_, err := guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
 		acnt, err := apierSv1.DataManager.GetAccount(acntID)
 		if err != nil {
 			return 0, err
 		}
+		acnt.SetLockTokens(tokens)
 		acnt.ActionTriggers = append(acnt.ActionTriggers, at)
 
 		return 0, apierSv1.DataManager.SetAccount(acnt)
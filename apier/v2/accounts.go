@@ -138,7 +138,7 @@ func (apiv2 *APIerSv2) SetAccount(attr *AttrSetAccount, reply *string) error {
 	dirtyActionPlans := make(map[string]*engine.ActionPlan)
 	var ub *engine.Account
 	var schedNeedsReload bool
-	_, err := guardian.Guardian.Guard(func() (interface{}, error) {
+	_, err := guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
 		if bal, _ := apiv2.DataManager.GetAccount(accID); bal != nil {
 			ub = bal
 		} else { // Not found in db, create it here
@@ -146,6 +146,7 @@ func (apiv2 *APIerSv2) SetAccount(attr *AttrSetAccount, reply *string) error {
 				ID: accID,
 			}
 		}
+		ub.SetLockTokens(tokens)
 		_, err := guardian.Guardian.Guard(func() (interface{}, error) {
 			acntAPids, err := apiv2.DataManager.GetAccountActionPlans(accID, false, utils.NonTransactional)
 			if err != nil && err != utils.ErrNotFound {
@@ -72,7 +72,9 @@ var (
 func startFilterService(filterSChan chan *engine.FilterS, cacheS *engine.CacheS, connMgr *engine.ConnManager, cfg *config.CGRConfig,
 	dm *engine.DataManager) {
 	<-cacheS.GetPrecacheChannel(utils.CacheFilters)
-	filterSChan <- engine.NewFilterS(cfg, connMgr, dm)
+	fltrS := engine.NewFilterS(cfg, connMgr, dm)
+	engine.EventStream.SetFilterS(fltrS)
+	filterSChan <- fltrS
 }
 
 // initCacheS inits the CacheS and starts precaching as well as populating internal channel for RPC conns
@@ -145,7 +147,7 @@ func startRPC(server *cores.Server, internalRaterChan,
 	internalSMGChan, internalAnalyzerSChan, internalDispatcherSChan,
 	internalLoaderSChan, internalRALsv1Chan, internalCacheSChan,
 	internalEEsChan, internalRateSChan, internalActionSChan,
-	internalAccountSChan chan rpcclient.ClientConnector,
+	internalAccountSChan, internalInvoiceSChan chan rpcclient.ClientConnector,
 	shdChan *utils.SyncedChan) {
 	if !cfg.DispatcherSCfg().Enabled {
 		select { // Any of the rpc methods will unlock listening to rpc requests
@@ -183,6 +185,8 @@ func startRPC(server *cores.Server, internalRaterChan,
 			internalActionSChan <- actionS
 		case accountS := <-internalAccountSChan:
 			internalAccountSChan <- accountS
+		case invoiceS := <-internalInvoiceSChan:
+			internalInvoiceSChan <- invoiceS
 		case <-shdChan.Done():
 			return
 		}
@@ -445,6 +449,14 @@ func main() {
 		return
 	}
 
+	var cfgDBS *engine.ConfigDBS
+	if cfg.ConfigSCfg().ConfigDB { // overlay the sections stored in ConfigDB before the config is used
+		if cfgDBS, err = engine.LoadConfigDB(cfg); err != nil {
+			log.Fatalf("Could not load the config from %s: <%s>", utils.ConfigDB, err.Error())
+			return
+		}
+	}
+
 	if *nodeID != utils.EmptyString {
 		cfg.GeneralCfg().NodeID = *nodeID
 	}
@@ -501,6 +513,7 @@ func main() {
 	internalRateSChan := make(chan rpcclient.ClientConnector, 1)
 	internalActionSChan := make(chan rpcclient.ClientConnector, 1)
 	internalAccountSChan := make(chan rpcclient.ClientConnector, 1)
+	internalInvoiceSChan := make(chan rpcclient.ClientConnector, 1)
 
 	// initialize the connManager before creating the DMService
 	// because we need to pass the connection to it
@@ -529,6 +542,7 @@ func main() {
 		utils.ConcatenatedKey(utils.MetaInternal, utils.MetaActions):        internalActionSChan,
 		utils.ConcatenatedKey(utils.MetaInternal, utils.MetaDispatchers):    internalDispatcherSChan,
 		utils.ConcatenatedKey(utils.MetaInternal, utils.MetaAccounts):       internalAccountSChan,
+		utils.ConcatenatedKey(utils.MetaInternal, utils.MetaInvoices):       internalInvoiceSChan,
 	})
 	srvDep := map[string]*sync.WaitGroup{
 		utils.AnalyzerS:       new(sync.WaitGroup),
@@ -565,6 +579,7 @@ func main() {
 		utils.ThresholdS:      new(sync.WaitGroup),
 		utils.ActionS:         new(sync.WaitGroup),
 		utils.AccountS:        new(sync.WaitGroup),
+		utils.InvoiceS:        new(sync.WaitGroup),
 	}
 	gvService := services.NewGlobalVarS(cfg, srvDep)
 	shdWg.Add(1)
@@ -578,6 +593,12 @@ func main() {
 			return
 		}
 	}
+	if cfgDBS != nil {
+		go func() {
+			cfgDBS.ListenAndServe(shdChan.Done())
+			cfgDBS.Close()
+		}()
+	}
 
 	storDBService := services.NewStorDBService(cfg, srvDep)
 	if storDBService.ShouldRun() { // Some services can run without db, ie:  ERs
@@ -587,14 +608,37 @@ func main() {
 		}
 	}
 
+	if cfg.HTTPCfg().HTTPMetricsURL != utils.EmptyString {
+		engine.Metrics = engine.NewMetricsRegistry()
+	}
+	if cfg.HTTPCfg().HTTPEventsURL != utils.EmptyString {
+		engine.EventStream = engine.NewEventStreamS(cfg)
+	}
+	if cfg.CoreSCfg().TraceExporter != utils.EmptyString {
+		if engine.Tracer, err = engine.NewTracerS(cfg); err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> could not start the tracing, error: %s",
+				utils.CoreS, err.Error()))
+			return
+		}
+		shdWg.Add(1)
+		go func() {
+			engine.Tracer.ListenAndServe(shdChan.Done())
+			shdWg.Done()
+		}()
+	}
+
 	// Rpc/http server
 	server := cores.NewServer(caps)
+	server.SetAPIAuth(cfg)
 	if len(cfg.HTTPCfg().DispatchersRegistrarURL) != 0 {
 		server.RegisterHttpFunc(cfg.HTTPCfg().DispatchersRegistrarURL, dispatcherh.Registar)
 	}
 	if cfg.ConfigSCfg().Enabled {
 		server.RegisterHttpFunc(cfg.ConfigSCfg().URL, config.HandlerConfigS)
 	}
+	if engine.EventStream != nil {
+		server.RegisterHttpFunc(cfg.HTTPCfg().HTTPEventsURL, server.EventsHandler)
+	}
 	if *httpPprofPath != utils.EmptyString {
 		server.RegisterProfiler(*httpPprofPath)
 	}
@@ -613,7 +657,7 @@ func main() {
 	}
 
 	// init CoreSv1
-	coreS := services.NewCoreService(cfg, caps, server, internalCoreSv1Chan, anz, srvDep)
+	coreS := services.NewCoreService(cfg, caps, server, internalCoreSv1Chan, connManager, anz, srvDep)
 	shdWg.Add(1)
 	if err := coreS.Start(); err != nil {
 		fmt.Println(err)
@@ -623,6 +667,12 @@ func main() {
 	// init CacheS
 	cacheS := initCacheS(internalCacheSChan, server, dmService.GetDM(), shdChan, anz, coreS.GetCoreS().CapsStats)
 	engine.SetCache(cacheS)
+	// the persisted internal DBs are restored only after the cache is shared
+	if err = engine.RestoreInternalDB(); err != nil {
+		utils.Logger.Crit(fmt.Sprintf("<%s> could not restore the internal DB, error: %s",
+			utils.InternalDB, err.Error()))
+		return
+	}
 
 	// init GuardianSv1
 	initGuardianSv1(internalGuardianSChan, server, anz)
@@ -634,7 +684,7 @@ func main() {
 	dspH := services.NewDispatcherHostsService(cfg, server, connManager, anz, srvDep)
 	chrS := services.NewChargerService(cfg, dmService, cacheS, filterSChan, server,
 		internalChargerSChan, connManager, anz, srvDep)
-	tS := services.NewThresholdService(cfg, dmService, cacheS, filterSChan, server, internalThresholdSChan, anz, srvDep)
+	tS := services.NewThresholdService(cfg, dmService, cacheS, filterSChan, server, internalThresholdSChan, connManager, anz, srvDep)
 	stS := services.NewStatService(cfg, dmService, cacheS, filterSChan, server,
 		internalStatSChan, connManager, anz, srvDep)
 	reS := services.NewResourceService(cfg, dmService, cacheS, filterSChan, server,
@@ -664,22 +714,23 @@ func main() {
 
 	srvManager.AddServices(gvService, attrS, chrS, tS, stS, reS, routeS, schS, rals,
 		apiSv1, apiSv2, cdrS, smg, coreS,
-		services.NewEventReaderService(cfg, filterSChan, shdChan, connManager, srvDep),
-		services.NewDNSAgent(cfg, filterSChan, shdChan, connManager, srvDep),
+		services.NewEventReaderService(cfg, dmService, filterSChan, shdChan, connManager, srvDep),
+		services.NewDNSAgent(cfg, filterSChan, shdChan, connManager, anz, srvDep),
 		services.NewFreeswitchAgent(cfg, shdChan, connManager, srvDep),
 		services.NewKamailioAgent(cfg, shdChan, connManager, srvDep),
-		services.NewAsteriskAgent(cfg, shdChan, connManager, srvDep),              // partial reload
-		services.NewRadiusAgent(cfg, filterSChan, shdChan, connManager, srvDep),   // partial reload
-		services.NewDiameterAgent(cfg, filterSChan, shdChan, connManager, srvDep), // partial reload
-		services.NewHTTPAgent(cfg, filterSChan, server, connManager, srvDep),      // no reload
+		services.NewAsteriskAgent(cfg, shdChan, connManager, srvDep),                   // partial reload
+		services.NewRadiusAgent(cfg, filterSChan, shdChan, connManager, anz, srvDep),   // partial reload
+		services.NewDiameterAgent(cfg, filterSChan, shdChan, connManager, anz, srvDep), // partial reload
+		services.NewHTTPAgent(cfg, filterSChan, server, connManager, srvDep),           // no reload
 		ldrs, anz, dspS, dspH, dmService, storDBService,
 		services.NewEventExporterService(cfg, filterSChan,
 			connManager, server, internalEEsChan, anz, srvDep),
 		services.NewRateService(cfg, cacheS, filterSChan, dmService,
 			server, internalRateSChan, anz, srvDep),
-		services.NewSIPAgent(cfg, filterSChan, shdChan, connManager, srvDep),
+		services.NewSIPAgent(cfg, filterSChan, shdChan, connManager, anz, srvDep),
 		services.NewActionService(cfg, dmService, cacheS, filterSChan, connManager, server, internalActionSChan, anz, srvDep),
 		services.NewAccountService(cfg, dmService, cacheS, filterSChan, connManager, server, internalAccountSChan, anz, srvDep),
+		services.NewInvoiceService(cfg, dmService, storDBService, cacheS, connManager, server, internalInvoiceSChan, anz, srvDep),
 	)
 	srvManager.StartServices()
 	// Start FilterS
@@ -716,6 +767,7 @@ func main() {
 	engine.IntRPC.AddInternalRPCClient(utils.EeSv1, internalEEsChan)
 	engine.IntRPC.AddInternalRPCClient(utils.DispatcherSv1, internalDispatcherSChan)
 	engine.IntRPC.AddInternalRPCClient(utils.AccountSv1, internalAccountSChan)
+	engine.IntRPC.AddInternalRPCClient(utils.InvoiceSv1, internalInvoiceSChan)
 
 	initConfigSv1(internalConfigChan, server, anz)
 
@@ -730,7 +782,7 @@ func main() {
 		internalRouteSChan, internalSessionSChan, internalAnalyzerSChan,
 		internalDispatcherSChan, internalLoaderSChan, internalRALsChan,
 		internalCacheSChan, internalEEsChan, internalRateSChan, internalActionSChan,
-		internalAccountSChan, shdChan)
+		internalAccountSChan, internalInvoiceSChan, shdChan)
 
 	<-shdChan.Done()
 	shtdDone := make(chan struct{})
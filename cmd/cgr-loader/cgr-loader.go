@@ -94,6 +94,8 @@ var (
 		"Enable detailed verbose logging output")
 	dryRun = cgrLoaderFlags.Bool(utils.DryRunCfg, false,
 		"When true will not save loaded data to dataDb but just parse it for consistency and errors.")
+	transactional = cgrLoaderFlags.Bool(utils.TransactionalCfg, false,
+		"When true the profiles are validated and written all together or not at all, the ones written before a failure being restored (not atomic for the concurrent readers, not supported for the legacy rating and accounting data).")
 	fieldSep = cgrLoaderFlags.String(utils.FieldSepCgr, ",",
 		`Separator for csv file (by default "," is used)`)
 
@@ -375,12 +377,19 @@ func main() {
 	}
 
 	if *remove {
+		if *transactional {
+			log.Fatal("Could not delete from database: transactional removal not supported")
+		}
 		if err = tpReader.RemoveFromDatabase(*verbose, *disableReverse); err != nil {
 			log.Fatal("Could not delete from database: ", err)
 		}
 	} else {
 		// write maps to database
-		if err = tpReader.WriteToDatabase(*verbose, *disableReverse); err != nil {
+		writeToDatabase := tpReader.WriteToDatabase
+		if *transactional {
+			writeToDatabase = tpReader.WriteToDatabaseTransactional
+		}
+		if err = writeToDatabase(*verbose, *disableReverse); err != nil {
 			log.Fatal("Could not write to database: ", err)
 		}
 	}
@@ -46,7 +46,7 @@ var (
 		"Configuration directory path.")
 
 	exec = cgrMigratorFlags.String(utils.ExecCgr, utils.EmptyString, "fire up automatic migration "+
-		"<*set_versions|*cost_details|*accounts|*actions|*action_triggers|*action_plans|*shared_groups|*filters|*stordb|*datadb>")
+		"<*set_versions|*cost_details|*accounts|*actions|*action_triggers|*action_plans|*shared_groups|*filters|*rating_to_rateprofiles|*accounts_to_accountprofiles|*actionplans_to_actionprofiles|*stordb|*datadb>")
 	version = cgrMigratorFlags.Bool(utils.ElsVersionLow, false, "prints the application version")
 
 	inDataDBType = cgrMigratorFlags.String(utils.DataDBTypeCgr, dfltCfg.DataDbCfg().DataDbType,
@@ -76,6 +76,14 @@ var (
 	fPath        = cgrTesterFlags.String("file_path", "", "read requests from file with path")
 	reqSep       = cgrTesterFlags.String("req_separator", "\n\n", "separator for requests in file")
 
+	scenarioPath   = cgrTesterFlags.String("scenario_path", "", "Simulate sessions with the Account,Destination pairs from the CSV file with path.")
+	cps            = cgrTesterFlags.Int("cps", 10, "The number of sessions started per second in scenario mode.")
+	updates        = cgrTesterFlags.Int("updates", 1, "The number of updates of each session in scenario mode.")
+	updateInterval = cgrTesterFlags.Duration("update_interval", 0, "The time waited between the session requests in scenario mode.")
+	reqType        = cgrTesterFlags.String("request_type", utils.MetaPrepaid, "The request type of the sessions in scenario mode.")
+	rpcEncoding    = cgrTesterFlags.String(utils.RpcEncodingCgr, utils.MetaJSON, "RPC encoding used in scenario mode <*json|*gob|*birpc_json>.")
+	replyTimeout   = cgrTesterFlags.Duration("reply_timeout", 2*time.Second, "The timeout for the replies in scenario mode.")
+
 	err error
 )
 
@@ -226,6 +234,21 @@ func main() {
 		pprof.StartCPUProfile(f)
 		defer pprof.StopCPUProfile()
 	}
+	if *scenarioPath != "" {
+		usg, err := utils.ParseDurationWithNanosecs(*usage)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sst, err := NewSessionScenarioTester(*scenarioPath, *raterAddress, *rpcEncoding, *replyTimeout,
+			*cps, *runs, *updates, *updateInterval, usg, *tenant, *category, *tor, *reqType)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := sst.Test(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	if *fPath != "" {
 		frt, err := NewFileReaderTester(*fPath, *raterAddress,
 			*parallel, *runs, []byte(*reqSep))
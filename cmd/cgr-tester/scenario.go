@@ -0,0 +1,303 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/cgrates/cgrates/sessions"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+// the order of the methods in the scenario report
+var scenarioMethods = []string{
+	utils.SessionSv1AuthorizeEvent,
+	utils.SessionSv1InitiateSession,
+	utils.SessionSv1UpdateSession,
+	utils.SessionSv1TerminateSession,
+	utils.SessionSv1ProcessCDR,
+}
+
+// scenarioParty is one Account,Destination pair out of the scenario file
+type scenarioParty struct {
+	account     string
+	destination string
+}
+
+// NewSessionScenarioTester constructs the tester, reading the Account,Destination pairs from fPath
+func NewSessionScenarioTester(fPath, cgrAddr, encoding string, replyTimeout time.Duration,
+	cps, runs, updates int, updateInterval, usage time.Duration,
+	tenant, category, tor, reqType string) (sst *SessionScenarioTester, err error) {
+	if cps <= 0 {
+		return nil, fmt.Errorf("invalid cps: %d", cps)
+	}
+	if updates < 0 {
+		return nil, fmt.Errorf("invalid updates: %d", updates)
+	}
+	sst = &SessionScenarioTester{
+		cps:            cps,
+		runs:           runs,
+		updates:        updates,
+		updateInterval: updateInterval,
+		usage:          usage,
+		tenant:         tenant,
+		category:       category,
+		tor:            tor,
+		reqType:        reqType,
+		stats:          make(map[string]*scenarioMethodStats),
+	}
+	for _, method := range scenarioMethods {
+		sst.stats[method] = new(scenarioMethodStats)
+	}
+	if sst.parties, err = readScenarioParties(fPath); err != nil {
+		return nil, err
+	}
+	switch encoding {
+	case utils.MetaJSON, utils.MetaGOB:
+		sst.conn, err = rpcclient.NewRPCClient(utils.TCP, cgrAddr, false, "", "", "", 3, 3,
+			time.Second, replyTimeout, encoding, nil, false)
+	case utils.MetaBiJSON:
+		sst.conn, err = utils.NewBiJSONrpcClient(cgrAddr, map[string]interface{}{
+			utils.SessionSv1DisconnectSession: sst.handleDisconnectSession,
+		})
+	default:
+		err = fmt.Errorf("unsupported encoding: %s", encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// readScenarioParties returns the Account,Destination pairs out of a CSV file
+func readScenarioParties(fPath string) (parties []*scenarioParty, err error) {
+	var f *os.File
+	if f, err = os.Open(fPath); err != nil {
+		return
+	}
+	defer f.Close()
+	csvReader := csv.NewReader(f)
+	csvReader.Comment = utils.CommentChar
+	csvReader.FieldsPerRecord = -1
+	for {
+		var record []string
+		if record, err = csvReader.Read(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("invalid scenario record: %v, expecting: Account,Destination", record)
+		}
+		parties = append(parties, &scenarioParty{account: record[0], destination: record[1]})
+	}
+	if len(parties) == 0 {
+		return nil, fmt.Errorf("no records in scenario file: %s", fPath)
+	}
+	return parties, nil
+}
+
+// SessionScenarioTester simulates full session lifecycles against SessionS at a fixed rate:
+// authorize, initiate, N updates, terminate and process CDR
+type SessionScenarioTester struct {
+	cps            int
+	runs           int
+	updates        int
+	updateInterval time.Duration
+	usage          time.Duration
+	tenant         string
+	category       string
+	tor            string
+	reqType        string
+
+	parties     []*scenarioParty
+	conn        rpcclient.ClientConnector
+	stats       map[string]*scenarioMethodStats // stats per API method
+	completed   int64                           // sessions running all the steps without error
+	disconnects int64                           // sessions disconnected by SessionS over BiRPC
+}
+
+// handleDisconnectSession answers the disconnect requests of SessionS when using BiRPC
+func (sst *SessionScenarioTester) handleDisconnectSession(clnt *rpc2.Client,
+	args *utils.AttrDisconnectSession, reply *string) error {
+	atomic.AddInt64(&sst.disconnects, 1)
+	*reply = utils.OK
+	return nil
+}
+
+// call executes the API method, recording the latency and the outcome
+func (sst *SessionScenarioTester) call(method string, args, reply interface{}) (err error) {
+	start := time.Now()
+	err = sst.conn.Call(method, args, reply)
+	sst.stats[method].record(time.Since(start), err)
+	return
+}
+
+// runSession simulates one session for the party
+// authorize and initiate errors abort the session, the other steps are executed regardless
+func (sst *SessionScenarioTester) runSession(party *scenarioParty) {
+	updateUsage := sst.usage / time.Duration(sst.updates+1) // each step reserves an equal slice of the usage
+	now := time.Now()
+	cgrEv := &utils.CGREvent{
+		Tenant: sst.tenant,
+		ID:     utils.GenUUID(),
+		Time:   &now,
+		Event: map[string]interface{}{
+			utils.Tenant:       sst.tenant,
+			utils.OriginID:     utils.GenUUID(),
+			utils.ToR:          sst.tor,
+			utils.RequestType:  sst.reqType,
+			utils.Category:     sst.category,
+			utils.AccountField: party.account,
+			utils.Subject:      party.account,
+			utils.Destination:  party.destination,
+			utils.SetupTime:    now,
+			utils.AnswerTime:   now,
+			utils.Usage:        sst.usage,
+		},
+	}
+	var authRply sessions.V1AuthorizeReply
+	if sst.call(utils.SessionSv1AuthorizeEvent,
+		sessions.NewV1AuthorizeArgs(false, nil, false, nil, false, nil,
+			false, true, false, false, false, cgrEv, utils.Paginator{}, false),
+		&authRply) != nil {
+		return
+	}
+	cgrEv.Event[utils.Usage] = updateUsage
+	var initRply sessions.V1InitSessionReply
+	if sst.call(utils.SessionSv1InitiateSession,
+		sessions.NewV1InitSessionArgs(false, nil, false, nil, false, nil,
+			false, true, cgrEv, false), &initRply) != nil {
+		return
+	}
+	failed := false
+	for i := 0; i < sst.updates; i++ {
+		time.Sleep(sst.updateInterval)
+		var updtRply sessions.V1UpdateSessionReply
+		if sst.call(utils.SessionSv1UpdateSession,
+			sessions.NewV1UpdateSessionArgs(false, nil, true, cgrEv, false),
+			&updtRply) != nil {
+			failed = true
+		}
+	}
+	time.Sleep(sst.updateInterval)
+	cgrEv.Event[utils.Usage] = sst.usage
+	var rply string
+	if sst.call(utils.SessionSv1TerminateSession,
+		sessions.NewV1TerminateSessionArgs(true, false, false, nil, false, nil, cgrEv, false),
+		&rply) != nil {
+		failed = true
+	}
+	if sst.call(utils.SessionSv1ProcessCDR, cgrEv, &rply) != nil {
+		failed = true
+	}
+	if !failed {
+		atomic.AddInt64(&sst.completed, 1)
+	}
+}
+
+// Test starts the sessions at the configured rate, waits for all of them to finish and logs the report
+func (sst *SessionScenarioTester) Test() (err error) {
+	log.Printf("Starting %d sessions at %d CPS...", sst.runs, sst.cps)
+	var wg sync.WaitGroup
+	ticker := time.NewTicker(time.Second / time.Duration(sst.cps))
+	start := time.Now()
+	for i := 0; i < sst.runs; i++ {
+		if i != 0 {
+			<-ticker.C
+		}
+		wg.Add(1)
+		go func(party *scenarioParty) {
+			sst.runSession(party)
+			wg.Done()
+		}(sst.parties[rand.Intn(len(sst.parties))])
+	}
+	ticker.Stop()
+	dispatched := time.Since(start)
+	wg.Wait()
+	sst.report(dispatched, time.Since(start))
+	return
+}
+
+// report logs the stats per method followed by the totals
+func (sst *SessionScenarioTester) report(dispatched, elapsed time.Duration) {
+	var reqs int
+	for _, method := range scenarioMethods {
+		mS := sst.stats[method]
+		mS.Lock()
+		if len(mS.latencies) == 0 {
+			mS.Unlock()
+			continue
+		}
+		reqs += len(mS.latencies)
+		sort.Slice(mS.latencies, func(i, j int) bool { return mS.latencies[i] < mS.latencies[j] })
+		log.Printf("%s: requests: %d, errors: %d (%.2f%%), p50: %s, p90: %s, p99: %s, max: %s",
+			method, len(mS.latencies), mS.errors,
+			float64(mS.errors)*100/float64(len(mS.latencies)),
+			percentile(mS.latencies, 50), percentile(mS.latencies, 90),
+			percentile(mS.latencies, 99), mS.latencies[len(mS.latencies)-1])
+		mS.Unlock()
+	}
+	completed := atomic.LoadInt64(&sst.completed)
+	var cps float64
+	if sst.runs > 1 { // the first session is started without waiting for the ticker
+		cps = float64(sst.runs-1) / dispatched.Seconds()
+	}
+	log.Printf("Sessions: %d, completed: %d, failed: %d, disconnected: %d",
+		sst.runs, completed, int64(sst.runs)-completed, atomic.LoadInt64(&sst.disconnects))
+	log.Printf("Elapsed: %s, achieved: %.2f CPS, %.2f req/s",
+		elapsed, cps, float64(reqs)/elapsed.Seconds())
+}
+
+// scenarioMethodStats collects the latencies and the errors of one API method
+type scenarioMethodStats struct {
+	sync.Mutex
+	latencies []time.Duration
+	errors    int
+}
+
+func (mS *scenarioMethodStats) record(latency time.Duration, err error) {
+	mS.Lock()
+	mS.latencies = append(mS.latencies, latency)
+	if err != nil {
+		mS.errors++
+	}
+	mS.Unlock()
+}
+
+// percentile returns the nearest-rank percentile out of the sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,104 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{
+		1 * time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond, 4 * time.Millisecond, 5 * time.Millisecond,
+		6 * time.Millisecond, 7 * time.Millisecond, 8 * time.Millisecond, 9 * time.Millisecond, 10 * time.Millisecond,
+	}
+	for _, tc := range []struct {
+		p   float64
+		exp time.Duration
+	}{
+		{p: 0, exp: time.Millisecond},
+		{p: 50, exp: 5 * time.Millisecond},
+		{p: 90, exp: 9 * time.Millisecond},
+		{p: 95, exp: 10 * time.Millisecond},
+		{p: 99, exp: 10 * time.Millisecond},
+		{p: 100, exp: 10 * time.Millisecond},
+	} {
+		if rcv := percentile(sorted, tc.p); rcv != tc.exp {
+			t.Errorf("p%v: expected: %v, received: %v", tc.p, tc.exp, rcv)
+		}
+	}
+	if rcv := percentile([]time.Duration{time.Second}, 99); rcv != time.Second {
+		t.Errorf("expected: %v, received: %v", time.Second, rcv)
+	}
+}
+
+func TestReadScenarioParties(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgr-tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fPath := path.Join(dir, "scenario.csv")
+	if err := ioutil.WriteFile(fPath, []byte(`#Account,Destination
+1001,1002
+1002,1003,extra
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*scenarioParty{
+		{account: "1001", destination: "1002"},
+		{account: "1002", destination: "1003"},
+	}
+	if rcv, err := readScenarioParties(fPath); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("expected: %s, received: %s", partiesString(exp), partiesString(rcv))
+	}
+
+	if err := ioutil.WriteFile(fPath, []byte("1001,1002\n1003\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readScenarioParties(fPath); err == nil {
+		t.Error("expected error for record without destination")
+	}
+
+	if err := ioutil.WriteFile(fPath, []byte("#Account,Destination\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readScenarioParties(fPath); err == nil {
+		t.Error("expected error for file without records")
+	}
+
+	if _, err := readScenarioParties(path.Join(dir, "missing.csv")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func partiesString(parties []*scenarioParty) (s string) {
+	for _, party := range parties {
+		s += fmt.Sprintf("%+v", *party)
+	}
+	return
+}
@@ -24,11 +24,15 @@ import (
 
 // ApierCfg is the configuration of Apier service
 type ApierCfg struct {
-	Enabled         bool
-	CachesConns     []string // connections towards Cache
-	SchedulerConns  []string // connections towards Scheduler
-	AttributeSConns []string // connections towards AttributeS
-	EEsConns        []string // connections towards EEs
+	Enabled          bool
+	CachesConns      []string // connections towards Cache
+	SchedulerConns   []string // connections towards Scheduler
+	AttributeSConns  []string // connections towards AttributeS
+	EEsConns         []string // connections towards EEs
+	Audit            bool     // record the API calls changing the configuration or the profiles
+	AuditAPIs        []string // patterns of the recorded API methods
+	AuditStorage     string   // where the audit entries are recorded: <*stordb|*ees>
+	AuditExporterIDs []string // exporters used by the *ees audit storage
 }
 
 func (aCfg *ApierCfg) loadFromJSONCfg(jsnCfg *ApierJsonCfg) (err error) {
@@ -78,13 +82,29 @@ func (aCfg *ApierCfg) loadFromJSONCfg(jsnCfg *ApierJsonCfg) (err error) {
 			}
 		}
 	}
+	if jsnCfg.Audit != nil {
+		aCfg.Audit = *jsnCfg.Audit
+	}
+	if jsnCfg.Audit_apis != nil {
+		aCfg.AuditAPIs = make([]string, len(*jsnCfg.Audit_apis))
+		copy(aCfg.AuditAPIs, *jsnCfg.Audit_apis)
+	}
+	if jsnCfg.Audit_storage != nil {
+		aCfg.AuditStorage = *jsnCfg.Audit_storage
+	}
+	if jsnCfg.Audit_exporter_ids != nil {
+		aCfg.AuditExporterIDs = make([]string, len(*jsnCfg.Audit_exporter_ids))
+		copy(aCfg.AuditExporterIDs, *jsnCfg.Audit_exporter_ids)
+	}
 	return nil
 }
 
 // AsMapInterface returns the config as a map[string]interface{}
 func (aCfg *ApierCfg) AsMapInterface() (initialMap map[string]interface{}) {
 	initialMap = map[string]interface{}{
-		utils.EnabledCfg: aCfg.Enabled,
+		utils.EnabledCfg:      aCfg.Enabled,
+		utils.AuditCfg:        aCfg.Audit,
+		utils.AuditStorageCfg: aCfg.AuditStorage,
 	}
 	if aCfg.CachesConns != nil {
 		cachesConns := make([]string, len(aCfg.CachesConns))
@@ -126,13 +146,25 @@ func (aCfg *ApierCfg) AsMapInterface() (initialMap map[string]interface{}) {
 		}
 		initialMap[utils.EEsConnsCfg] = eesConns
 	}
+	if aCfg.AuditAPIs != nil {
+		auditAPIs := make([]string, len(aCfg.AuditAPIs))
+		copy(auditAPIs, aCfg.AuditAPIs)
+		initialMap[utils.AuditAPIsCfg] = auditAPIs
+	}
+	if aCfg.AuditExporterIDs != nil {
+		auditExporterIDs := make([]string, len(aCfg.AuditExporterIDs))
+		copy(auditExporterIDs, aCfg.AuditExporterIDs)
+		initialMap[utils.AuditExporterIDsCfg] = auditExporterIDs
+	}
 	return
 }
 
 // Clone returns a deep copy of ApierCfg
 func (aCfg ApierCfg) Clone() (cln *ApierCfg) {
 	cln = &ApierCfg{
-		Enabled: aCfg.Enabled,
+		Enabled:      aCfg.Enabled,
+		Audit:        aCfg.Audit,
+		AuditStorage: aCfg.AuditStorage,
 	}
 	if aCfg.CachesConns != nil {
 		cln.CachesConns = make([]string, len(aCfg.CachesConns))
@@ -158,5 +190,13 @@ func (aCfg ApierCfg) Clone() (cln *ApierCfg) {
 			cln.EEsConns[i] = k
 		}
 	}
+	if aCfg.AuditAPIs != nil {
+		cln.AuditAPIs = make([]string, len(aCfg.AuditAPIs))
+		copy(cln.AuditAPIs, aCfg.AuditAPIs)
+	}
+	if aCfg.AuditExporterIDs != nil {
+		cln.AuditExporterIDs = make([]string, len(aCfg.AuditExporterIDs))
+		copy(cln.AuditExporterIDs, aCfg.AuditExporterIDs)
+	}
 	return
 }
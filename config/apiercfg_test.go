@@ -26,18 +26,26 @@ import (
 
 func TestApierCfgloadFromJsonCfg(t *testing.T) {
 	jsonCfg := &ApierJsonCfg{
-		Enabled:          utils.BoolPointer(false),
-		Caches_conns:     &[]string{utils.MetaInternal, "*conn1"},
-		Scheduler_conns:  &[]string{utils.MetaInternal, "*conn1"},
-		Attributes_conns: &[]string{utils.MetaInternal, "*conn1"},
-		Ees_conns:        &[]string{utils.MetaInternal, "*conn1"},
+		Enabled:            utils.BoolPointer(false),
+		Caches_conns:       &[]string{utils.MetaInternal, "*conn1"},
+		Scheduler_conns:    &[]string{utils.MetaInternal, "*conn1"},
+		Attributes_conns:   &[]string{utils.MetaInternal, "*conn1"},
+		Ees_conns:          &[]string{utils.MetaInternal, "*conn1"},
+		Audit:              utils.BoolPointer(true),
+		Audit_apis:         &[]string{"APIerSv1.Set*"},
+		Audit_storage:      utils.StringPointer(utils.MetaEEs),
+		Audit_exporter_ids: &[]string{"audit_exporter"},
 	}
 	expected := &ApierCfg{
-		Enabled:         false,
-		CachesConns:     []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaCaches), "*conn1"},
-		SchedulerConns:  []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaScheduler), "*conn1"},
-		AttributeSConns: []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaAttributes), "*conn1"},
-		EEsConns:        []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs), "*conn1"},
+		Enabled:          false,
+		CachesConns:      []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaCaches), "*conn1"},
+		SchedulerConns:   []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaScheduler), "*conn1"},
+		AttributeSConns:  []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaAttributes), "*conn1"},
+		EEsConns:         []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs), "*conn1"},
+		Audit:            true,
+		AuditAPIs:        []string{"APIerSv1.Set*"},
+		AuditStorage:     utils.MetaEEs,
+		AuditExporterIDs: []string{"audit_exporter"},
 	}
 	jsnCfg := NewDefaultCGRConfig()
 	if err = jsnCfg.apier.loadFromJSONCfg(jsonCfg); err != nil {
@@ -55,11 +63,15 @@ func TestApierCfgAsMapInterface1(t *testing.T) {
 }`
 	sls := make([]string, 0)
 	eMap := map[string]interface{}{
-		utils.EnabledCfg:         false,
-		utils.CachesConnsCfg:     sls,
-		utils.SchedulerConnsCfg:  sls,
-		utils.AttributeSConnsCfg: sls,
-		utils.EEsConnsCfg:        sls,
+		utils.EnabledCfg:          false,
+		utils.CachesConnsCfg:      sls,
+		utils.SchedulerConnsCfg:   sls,
+		utils.AttributeSConnsCfg:  sls,
+		utils.EEsConnsCfg:         sls,
+		utils.AuditCfg:            false,
+		utils.AuditAPIsCfg:        []string{"APIerSv1.Set*", "APIerSv1.Remove*", "ConfigSv1.SetConfig*", "ConfigSv1.ReloadConfig"},
+		utils.AuditStorageCfg:     utils.MetaStorDB,
+		utils.AuditExporterIDsCfg: sls,
 	}
 	if cgrCfg, err := NewCGRConfigFromJSONStringWithDefaults(cfgJSONStr); err != nil {
 		t.Error(err)
@@ -76,14 +88,22 @@ func TestApierCfgAsMapInterface2(t *testing.T) {
        "ees_conns": ["*internal:*ees", "*conn1"],
        "caches_conns": ["*internal:*caches", "*conn1"],
        "scheduler_conns": ["*internal:*scheduler", "*conn1"],
+       "audit": true,
+       "audit_apis": ["APIerSv1.Set*"],
+       "audit_storage": "*ees",
+       "audit_exporter_ids": ["audit_exporter"],
     },
 }`
 	expectedMap := map[string]interface{}{
-		utils.EnabledCfg:         true,
-		utils.CachesConnsCfg:     []string{utils.MetaInternal, "*conn1"},
-		utils.SchedulerConnsCfg:  []string{utils.MetaInternal, "*conn1"},
-		utils.AttributeSConnsCfg: []string{utils.MetaInternal, "*conn1"},
-		utils.EEsConnsCfg:        []string{utils.MetaInternal, "*conn1"},
+		utils.EnabledCfg:          true,
+		utils.CachesConnsCfg:      []string{utils.MetaInternal, "*conn1"},
+		utils.SchedulerConnsCfg:   []string{utils.MetaInternal, "*conn1"},
+		utils.AttributeSConnsCfg:  []string{utils.MetaInternal, "*conn1"},
+		utils.EEsConnsCfg:         []string{utils.MetaInternal, "*conn1"},
+		utils.AuditCfg:            true,
+		utils.AuditAPIsCfg:        []string{"APIerSv1.Set*"},
+		utils.AuditStorageCfg:     utils.MetaEEs,
+		utils.AuditExporterIDsCfg: []string{"audit_exporter"},
 	}
 	if cgrCfg, err := NewCGRConfigFromJSONStringWithDefaults(myJSONStr); err != nil {
 		t.Error(err)
@@ -94,11 +114,15 @@ func TestApierCfgAsMapInterface2(t *testing.T) {
 
 func TestApierCfgClone(t *testing.T) {
 	sa := &ApierCfg{
-		Enabled:         false,
-		CachesConns:     []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaCaches), "*conn1"},
-		SchedulerConns:  []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaScheduler), "*conn1"},
-		AttributeSConns: []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaAttributes), "*conn1"},
-		EEsConns:        []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs), "*conn1"},
+		Enabled:          false,
+		CachesConns:      []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaCaches), "*conn1"},
+		SchedulerConns:   []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaScheduler), "*conn1"},
+		AttributeSConns:  []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaAttributes), "*conn1"},
+		EEsConns:         []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs), "*conn1"},
+		Audit:            true,
+		AuditAPIs:        []string{"APIerSv1.Set*"},
+		AuditStorage:     utils.MetaStorDB,
+		AuditExporterIDs: []string{"audit_exporter"},
 	}
 	rcv := sa.Clone()
 	if !reflect.DeepEqual(sa, rcv) {
@@ -116,4 +140,10 @@ func TestApierCfgClone(t *testing.T) {
 	if rcv.EEsConns[1] = ""; sa.EEsConns[1] != "*conn1" {
 		t.Errorf("Expected clone to not modify the cloned")
 	}
+	if rcv.AuditAPIs[0] = ""; sa.AuditAPIs[0] != "APIerSv1.Set*" {
+		t.Errorf("Expected clone to not modify the cloned")
+	}
+	if rcv.AuditExporterIDs[0] = ""; sa.AuditExporterIDs[0] != "audit_exporter" {
+		t.Errorf("Expected clone to not modify the cloned")
+	}
 }
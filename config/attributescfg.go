@@ -18,7 +18,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 
 package config
 
-import "github.com/cgrates/cgrates/utils"
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
 
 // AttributeSCfg is the configuration of attribute service
 type AttributeSCfg struct {
@@ -32,6 +36,7 @@ type AttributeSCfg struct {
 	SuffixIndexedFields *[]string
 	ProcessRuns         int
 	NestedFields        bool
+	Lookups             map[string]*AttributeLookupCfg
 }
 
 func (alS *AttributeSCfg) loadFromJSONCfg(jsnCfg *AttributeSJsonCfg) (err error) {
@@ -101,6 +106,19 @@ func (alS *AttributeSCfg) loadFromJSONCfg(jsnCfg *AttributeSJsonCfg) (err error)
 	if jsnCfg.Nested_fields != nil {
 		alS.NestedFields = *jsnCfg.Nested_fields
 	}
+	if jsnCfg.Lookups != nil {
+		if alS.Lookups == nil {
+			alS.Lookups = make(map[string]*AttributeLookupCfg)
+		}
+		for lkID, jsnLk := range jsnCfg.Lookups {
+			if _, has := alS.Lookups[lkID]; !has {
+				alS.Lookups[lkID] = new(AttributeLookupCfg)
+			}
+			if err = alS.Lookups[lkID].loadFromJSONCfg(jsnLk); err != nil {
+				return
+			}
+		}
+	}
 	return
 }
 
@@ -164,6 +182,13 @@ func (alS *AttributeSCfg) AsMapInterface() (initialMP map[string]interface{}) {
 		}
 		initialMP[utils.ApierSConnsCfg] = apierSConns
 	}
+	if alS.Lookups != nil {
+		lookups := make(map[string]interface{})
+		for lkID, lk := range alS.Lookups {
+			lookups[lkID] = lk.AsMapInterface()
+		}
+		initialMP[utils.LookupsCfg] = lookups
+	}
 	return
 }
 
@@ -215,5 +240,100 @@ func (alS AttributeSCfg) Clone() (cln *AttributeSCfg) {
 		}
 		cln.SuffixIndexedFields = &idx
 	}
+	if alS.Lookups != nil {
+		cln.Lookups = make(map[string]*AttributeLookupCfg)
+		for lkID, lk := range alS.Lookups {
+			cln.Lookups[lkID] = lk.Clone()
+		}
+	}
+	return
+}
+
+// AttributeLookupCfg is the configuration of an external lookup used by the *http and *sql attributes
+type AttributeLookupCfg struct {
+	Type     string
+	URL      string   // address queried for *http, connection URL for *sql
+	Query    string   // statement executed for *sql
+	Args     []string // templates for the *sql statement arguments
+	Timeout  time.Duration
+	CacheTTL time.Duration // 0 disables caching of the lookup results
+	Fallback string        // value used when the lookup fails, empty to return the error
+}
+
+func (lk *AttributeLookupCfg) loadFromJSONCfg(jsnCfg *AttrLookupJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Type != nil {
+		lk.Type = *jsnCfg.Type
+	}
+	if jsnCfg.Url != nil {
+		lk.URL = *jsnCfg.Url
+	}
+	if jsnCfg.Query != nil {
+		lk.Query = *jsnCfg.Query
+	}
+	if jsnCfg.Args != nil {
+		lk.Args = make([]string, len(*jsnCfg.Args))
+		for i, arg := range *jsnCfg.Args {
+			lk.Args[i] = arg
+		}
+	}
+	if jsnCfg.Timeout != nil {
+		if lk.Timeout, err = utils.ParseDurationWithNanosecs(*jsnCfg.Timeout); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Cache_ttl != nil {
+		if lk.CacheTTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Cache_ttl); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Fallback != nil {
+		lk.Fallback = *jsnCfg.Fallback
+	}
+	return
+}
+
+// AsMapInterface returns the config as a map[string]interface{}
+func (lk *AttributeLookupCfg) AsMapInterface() (initialMP map[string]interface{}) {
+	initialMP = map[string]interface{}{
+		utils.TypeCfg:     lk.Type,
+		utils.URLCfg:      lk.URL,
+		utils.QueryCfg:    lk.Query,
+		utils.TimeoutCfg:  utils.EmptyString,
+		utils.CacheTTLCfg: utils.EmptyString,
+		utils.FallbackCfg: lk.Fallback,
+	}
+	if lk.Timeout != 0 {
+		initialMP[utils.TimeoutCfg] = lk.Timeout.String()
+	}
+	if lk.CacheTTL != 0 {
+		initialMP[utils.CacheTTLCfg] = lk.CacheTTL.String()
+	}
+	args := make([]string, len(lk.Args))
+	for i, arg := range lk.Args {
+		args[i] = arg
+	}
+	initialMP[utils.ArgsCfg] = args
+	return
+}
+
+// Clone returns a deep copy of AttributeLookupCfg
+func (lk AttributeLookupCfg) Clone() (cln *AttributeLookupCfg) {
+	cln = &AttributeLookupCfg{
+		Type:     lk.Type,
+		URL:      lk.URL,
+		Query:    lk.Query,
+		Timeout:  lk.Timeout,
+		CacheTTL: lk.CacheTTL,
+		Fallback: lk.Fallback,
+	}
+	if lk.Args != nil {
+		cln.Args = make([]string, len(lk.Args))
+		for i, arg := range lk.Args {
+			cln.Args[i] = arg
+		}
+	}
 	return
 }
@@ -36,6 +36,8 @@ type CdrsCfg struct {
 	OnlineCDRExports []string // list of CDRE templates to use for real-time CDR exports
 	SchedulerConns   []string
 	EEsConns         []string
+	CustomerRunID    string // RunID of the customer rating in wholesale mode
+	SupplierRunID    string // RunID of the supplier rating paired with the customer one
 }
 
 // loadFromJSONCfg loads Cdrs config from JsonCfg
@@ -133,6 +135,12 @@ func (cdrscfg *CdrsCfg) loadFromJSONCfg(jsnCdrsCfg *CdrsJsonCfg) (err error) {
 			}
 		}
 	}
+	if jsnCdrsCfg.Customer_run_id != nil {
+		cdrscfg.CustomerRunID = *jsnCdrsCfg.Customer_run_id
+	}
+	if jsnCdrsCfg.Supplier_run_id != nil {
+		cdrscfg.SupplierRunID = *jsnCdrsCfg.Supplier_run_id
+	}
 	return nil
 }
 
@@ -142,6 +150,8 @@ func (cdrscfg *CdrsCfg) AsMapInterface() (initialMP map[string]interface{}) {
 		utils.EnabledCfg:       cdrscfg.Enabled,
 		utils.StoreCdrsCfg:     cdrscfg.StoreCdrs,
 		utils.SMCostRetriesCfg: cdrscfg.SMCostRetries,
+		utils.CustomerRunIDCfg: cdrscfg.CustomerRunID,
+		utils.SupplierRunIDCfg: cdrscfg.SupplierRunID,
 	}
 
 	extraFields := make([]string, len(cdrscfg.ExtraFields))
@@ -236,6 +246,8 @@ func (cdrscfg CdrsCfg) Clone() (cln *CdrsCfg) {
 		ExtraFields:   cdrscfg.ExtraFields.Clone(),
 		StoreCdrs:     cdrscfg.StoreCdrs,
 		SMCostRetries: cdrscfg.SMCostRetries,
+		CustomerRunID: cdrscfg.CustomerRunID,
+		SupplierRunID: cdrscfg.SupplierRunID,
 	}
 	if cdrscfg.ChargerSConns != nil {
 		cln.ChargerSConns = make([]string, len(cdrscfg.ChargerSConns))
@@ -37,6 +37,8 @@ func TestCdrsCfgloadFromJsonCfg(t *testing.T) {
 		Online_cdr_exports:   &[]string{"randomVal"},
 		Scheduler_conns:      &[]string{utils.MetaInternal, "*conn1"},
 		Ees_conns:            &[]string{utils.MetaInternal, "*conn1"},
+		Customer_run_id:      utils.StringPointer(utils.MetaDefault),
+		Supplier_run_id:      utils.StringPointer("supplier"),
 	}
 	expected := &CdrsCfg{
 		Enabled:          true,
@@ -51,6 +53,8 @@ func TestCdrsCfgloadFromJsonCfg(t *testing.T) {
 		SchedulerConns:   []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaScheduler), "*conn1"},
 		EEsConns:         []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs), "*conn1"},
 		ExtraFields:      RSRParsers{},
+		CustomerRunID:    utils.MetaDefault,
+		SupplierRunID:    "supplier",
 	}
 	jsnCfg := NewDefaultCGRConfig()
 	if err = jsnCfg.cdrsCfg.loadFromJSONCfg(jsonCfg); err != nil {
@@ -86,6 +90,8 @@ func TestCdrsCfgAsMapInterface(t *testing.T) {
 		"online_cdr_exports":["http_localhost", "amqp_localhost", "http_test_file"],
 		"scheduler_conns": ["*internal:*scheduler","*conn1"],		
         "ees_conns": ["*internal:*ees","*conn1"],
+		"customer_run_id": "*default",
+		"supplier_run_id": "supplier",
 	},
 }`
 	eMap := map[string]interface{}{
@@ -101,6 +107,8 @@ func TestCdrsCfgAsMapInterface(t *testing.T) {
 		utils.OnlineCDRExportsCfg: []string{"http_localhost", "amqp_localhost", "http_test_file"},
 		utils.SchedulerConnsCfg:   []string{utils.MetaInternal, "*conn1"},
 		utils.EEsConnsCfg:         []string{utils.MetaInternal, "*conn1"},
+		utils.CustomerRunIDCfg:    utils.MetaDefault,
+		utils.SupplierRunIDCfg:    "supplier",
 	}
 	if cgrCfg, err := NewCGRConfigFromJSONStringWithDefaults(cfgJSONStr); err != nil {
 		t.Error(err)
@@ -131,6 +139,8 @@ func TestCdrsCfgAsMapInterface2(t *testing.T) {
 		utils.OnlineCDRExportsCfg: []string{},
 		utils.SchedulerConnsCfg:   []string{},
 		utils.EEsConnsCfg:         []string{"conn1"},
+		utils.CustomerRunIDCfg:    utils.EmptyString,
+		utils.SupplierRunIDCfg:    utils.EmptyString,
 	}
 	if cgrCfg, err := NewCGRConfigFromJSONStringWithDefaults(cfgJSONStr); err != nil {
 		t.Error(err)
@@ -153,6 +163,8 @@ func TestCdrsCfgClone(t *testing.T) {
 		EEsConns:         []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs), "*conn1"},
 		OnlineCDRExports: []string{"randomVal"},
 		ExtraFields:      RSRParsers{},
+		CustomerRunID:    utils.MetaDefault,
+		SupplierRunID:    "supplier",
 	}
 	rcv := ban.Clone()
 	if !reflect.DeepEqual(ban, rcv) {
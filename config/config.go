@@ -200,6 +200,7 @@ func newCGRConfig(config []byte) (cfg *CGRConfig, err error) {
 	cfg.apiBanCfg = new(APIBanCfg)
 	cfg.coreSCfg = new(CoreSCfg)
 	cfg.accountSCfg = new(AccountSCfg)
+	cfg.invoiceSCfg = new(InvoiceSCfg)
 
 	var cgrJSONCfg *CgrJsonCfg
 	if cgrJSONCfg, err = NewCgrJsonCfgFromBytes(config); err != nil {
@@ -334,12 +335,14 @@ type CGRConfig struct {
 	apiBanCfg        *APIBanCfg        // APIBan config
 	coreSCfg         *CoreSCfg         // CoreS config
 	accountSCfg      *AccountSCfg      // AccountS config
+	invoiceSCfg      *InvoiceSCfg      // InvoiceS config
 }
 
 var posibleLoaderTypes = utils.NewStringSet([]string{utils.MetaAttributes,
 	utils.MetaResources, utils.MetaFilters, utils.MetaStats,
 	utils.MetaRoutes, utils.MetaThresholds, utils.MetaChargers,
-	utils.MetaDispatchers, utils.MetaDispatcherHosts, utils.MetaRateProfiles})
+	utils.MetaDispatchers, utils.MetaDispatcherHosts, utils.MetaRateProfiles,
+	utils.MetaPortedNumbers})
 
 var possibleReaderTypes = utils.NewStringSet([]string{utils.MetaFileCSV,
 	utils.MetaKafkajsonMap, utils.MetaFileXML, utils.MetaSQL, utils.MetaFileFWV,
@@ -347,7 +350,8 @@ var possibleReaderTypes = utils.NewStringSet([]string{utils.MetaFileCSV,
 
 var possibleExporterTypes = utils.NewStringSet([]string{utils.MetaFileCSV, utils.MetaNone, utils.MetaFileFWV,
 	utils.MetaHTTPPost, utils.MetaHTTPjsonMap, utils.MetaAMQPjsonMap, utils.MetaAMQPV1jsonMap, utils.MetaSQSjsonMap,
-	utils.MetaKafkajsonMap, utils.MetaS3jsonMap, utils.MetaElastic, utils.MetaVirt, utils.MetaSQL})
+	utils.MetaKafkajsonMap, utils.MetaS3jsonMap, utils.MetaElastic, utils.MetaVirt, utils.MetaSQL,
+	utils.MetaFileTemplate})
 
 // LazySanityCheck used after check config sanity to display warnings related to the config
 func (cfg *CGRConfig) LazySanityCheck() {
@@ -402,7 +406,7 @@ func (cfg *CGRConfig) loadFromJSONCfg(jsnCfg *CgrJsonCfg) (err error) {
 		cfg.loadAnalyzerCgrCfg, cfg.loadApierCfg, cfg.loadErsCfg, cfg.loadEesCfg,
 		cfg.loadRateSCfg, cfg.loadSIPAgentCfg, cfg.loadDispatcherHCfg,
 		cfg.loadConfigSCfg, cfg.loadAPIBanCgrCfg, cfg.loadCoreSCfg, cfg.loadActionSCfg,
-		cfg.loadAccountSCfg} {
+		cfg.loadAccountSCfg, cfg.loadInvoiceSCfg} {
 		if err = loadFunc(jsnCfg); err != nil {
 			return
 		}
@@ -847,6 +851,15 @@ func (cfg *CGRConfig) loadAccountSCfg(jsnCfg *CgrJsonCfg) (err error) {
 	return cfg.accountSCfg.loadFromJSONCfg(jsnActionCfg)
 }
 
+// loadInvoiceSCfg loads the InvoiceS section of the configuration
+func (cfg *CGRConfig) loadInvoiceSCfg(jsnCfg *CgrJsonCfg) (err error) {
+	var jsnInvoiceCfg *InvoiceSJsonCfg
+	if jsnInvoiceCfg, err = jsnCfg.InvoiceSCfgJson(); err != nil {
+		return
+	}
+	return cfg.invoiceSCfg.loadFromJSONCfg(jsnInvoiceCfg)
+}
+
 // SureTaxCfg use locking to retrieve the configuration, possibility later for runtime reload
 func (cfg *CGRConfig) SureTaxCfg() *SureTaxCfg {
 	cfg.lks[SURETAX_JSON].Lock()
@@ -1125,6 +1138,13 @@ func (cfg *CGRConfig) AccountSCfg() *AccountSCfg {
 	return cfg.accountSCfg
 }
 
+// InvoiceSCfg reads the InvoiceS configuration
+func (cfg *CGRConfig) InvoiceSCfg() *InvoiceSCfg {
+	cfg.lks[InvoiceSJson].RLock()
+	defer cfg.lks[InvoiceSJson].RUnlock()
+	return cfg.invoiceSCfg
+}
+
 // SIPAgentCfg reads the Apier configuration
 func (cfg *CGRConfig) SIPAgentCfg() *SIPAgentCfg {
 	cfg.lks[SIPAgentJson].Lock()
@@ -1265,6 +1285,7 @@ func (cfg *CGRConfig) getLoadFunctions() map[string]func(*CgrJsonCfg) error {
 		CoreSCfgJson:       cfg.loadCoreSCfg,
 		ActionSJson:        cfg.loadActionSCfg,
 		AccountSCfgJson:    cfg.loadAccountSCfg,
+		InvoiceSJson:       cfg.loadInvoiceSCfg,
 	}
 }
 
@@ -1427,8 +1448,8 @@ func (cfg *CGRConfig) reloadSections(sections ...string) {
 		RALS_JSN, CDRS_JSN, SessionSJson, ATTRIBUTE_JSN,
 		ChargerSCfgJson, RESOURCES_JSON, STATS_JSON, THRESHOLDS_JSON,
 		RouteSJson, LoaderJson, DispatcherSJson, RateSJson, ApierS, AccountSCfgJson,
-		ActionSJson})
-	subsystemsThatNeedStorDB := utils.NewStringSet([]string{STORDB_JSN, RALS_JSN, CDRS_JSN, ApierS})
+		ActionSJson, InvoiceSJson})
+	subsystemsThatNeedStorDB := utils.NewStringSet([]string{STORDB_JSN, RALS_JSN, CDRS_JSN, ApierS, InvoiceSJson})
 	needsDataDB := false
 	needsStorDB := false
 	for _, section := range sections {
@@ -1522,6 +1543,8 @@ func (cfg *CGRConfig) reloadSections(sections ...string) {
 			cfg.rldChans[AccountSCfgJson] <- struct{}{}
 		case ActionSJson:
 			cfg.rldChans[ActionSJson] <- struct{}{}
+		case InvoiceSJson:
+			cfg.rldChans[InvoiceSJson] <- struct{}{}
 		}
 	}
 	return
@@ -1575,6 +1598,7 @@ func (cfg *CGRConfig) AsMapInterface(separator string) (mp map[string]interface{
 		CoreSCfgJson:       cfg.coreSCfg.AsMapInterface(),
 		ActionSJson:        cfg.actionSCfg.AsMapInterface(),
 		AccountSCfgJson:    cfg.accountSCfg.AsMapInterface(),
+		InvoiceSJson:       cfg.invoiceSCfg.AsMapInterface(),
 	}
 }
 
@@ -1724,6 +1748,8 @@ func (cfg *CGRConfig) V1GetConfig(args *SectionWithOpts, reply *map[string]inter
 		mp = cfg.ActionSCfg().AsMapInterface()
 	case AccountSCfgJson:
 		mp = cfg.AccountSCfg().AsMapInterface()
+	case InvoiceSJson:
+		mp = cfg.InvoiceSCfg().AsMapInterface()
 	default:
 		return errors.New("Invalid section")
 	}
@@ -1872,6 +1898,8 @@ func (cfg *CGRConfig) V1GetConfigAsJSON(args *SectionWithOpts, reply *string) (e
 		mp = cfg.CoreSCfg().AsMapInterface()
 	case AccountSCfgJson:
 		mp = cfg.AccountSCfg().AsMapInterface()
+	case InvoiceSJson:
+		mp = cfg.InvoiceSCfg().AsMapInterface()
 	default:
 		return errors.New("Invalid section")
 	}
@@ -1915,6 +1943,38 @@ func (cfg *CGRConfig) V1SetConfigFromJSON(args *SetConfigFromJSONArgs, reply *st
 	return
 }
 
+// LoadSectionsFromJSON overlays the sections, each one given as JSON, on the config
+// the subsystems of the sections are reloaded only if reload is true
+func (cfg *CGRConfig) LoadSectionsFromJSON(sectionsJSON map[string]string, reload bool) (err error) {
+	if len(sectionsJSON) == 0 {
+		return
+	}
+	sections := make([]string, 0, len(sectionsJSON))
+	jsnCfg := make(map[string]json.RawMessage, len(sectionsJSON))
+	for section, sectJSON := range sectionsJSON {
+		sections = append(sections, section)
+		jsnCfg[section] = json.RawMessage(sectJSON)
+	}
+	var b []byte
+	if b, err = json.Marshal(jsnCfg); err != nil {
+		return
+	}
+	if err = cfg.loadCfgFromJSONWithLocks(bytes.NewBuffer(b), sections); err != nil {
+		return
+	}
+	//  lock all sections
+	cfg.rLockSections()
+	err = cfg.checkConfigSanity()
+	cfg.rUnlockSections() // unlock before checking the error
+	if err != nil {
+		return
+	}
+	if reload {
+		cfg.reloadSections(sections...)
+	}
+	return
+}
+
 // Clone returns a deep copy of CGRConfig
 func (cfg CGRConfig) Clone() (cln *CGRConfig) {
 	cln = &CGRConfig{
@@ -1968,6 +2028,7 @@ func (cfg CGRConfig) Clone() (cln *CGRConfig) {
 		coreSCfg:         cfg.coreSCfg.Clone(),
 		actionSCfg:       cfg.actionSCfg.Clone(),
 		accountSCfg:      cfg.accountSCfg.Clone(),
+		invoiceSCfg:      cfg.invoiceSCfg.Clone(),
 	}
 	cln.initChanels()
 	return
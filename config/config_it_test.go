@@ -469,6 +469,7 @@ func testCGRConfigReloadSessionS(t *testing.T) {
 		SessionIndexes:    utils.StringSet{},
 		ClientProtocol:    1,
 		TerminateAttempts: 5,
+		RegistryLease:     10 * time.Second,
 		AlterableFields:   utils.NewStringSet([]string{}),
 		STIRCfg: &STIRcfg{
 			AllowedAttest:      utils.NewStringSet([]string{utils.MetaAny}),
@@ -835,6 +836,7 @@ func testCGRConfigReloadConfigFromJSONSessionS(t *testing.T) {
 		SessionIndexes:    utils.StringSet{},
 		ClientProtocol:    1,
 		TerminateAttempts: 5,
+		RegistryLease:     10 * time.Second,
 		AlterableFields:   utils.NewStringSet([]string{}),
 		STIRCfg: &STIRcfg{
 			AllowedAttest:      utils.NewStringSet([]string{utils.MetaAny}),
@@ -885,6 +887,7 @@ func testCGRConfigReloadConfigFromStringSessionS(t *testing.T) {
 		SessionIndexes:    utils.StringSet{},
 		ClientProtocol:    1,
 		TerminateAttempts: 5,
+		RegistryLease:     10 * time.Second,
 		AlterableFields:   utils.NewStringSet([]string{}),
 		STIRCfg: &STIRcfg{
 			AllowedAttest:      utils.NewStringSet([]string{utils.MetaAny}),
@@ -898,7 +901,7 @@ func testCGRConfigReloadConfigFromStringSessionS(t *testing.T) {
 	}
 
 	var rcv string
-	expected := `{"sessions":{"alterable_fields":[],"attributes_conns":["*localhost"],"cdrs_conns":["*internal"],"channel_sync_interval":"0","chargers_conns":["*localhost"],"client_protocol":1,"debit_interval":"0","enabled":true,"listen_bijson":"127.0.0.1:2014","min_dur_low_balance":"0","rals_conns":["*internal"],"replication_conns":[],"resources_conns":["*localhost"],"routes_conns":["*localhost"],"scheduler_conns":[],"session_indexes":[],"session_ttl":"0","stats_conns":[],"stir":{"allowed_attest":["*any"],"default_attest":"A","payload_maxduration":"-1","privatekey_path":"","publickey_path":""},"store_session_costs":false,"terminate_attempts":5,"thresholds_conns":[]}}`
+	expected := `{"sessions":{"alterable_fields":[],"attributes_conns":["*localhost"],"cdrs_conns":["*internal"],"channel_sync_interval":"0","chargers_conns":["*localhost"],"client_protocol":1,"debit_interval":"0","enabled":true,"listen_bijson":"127.0.0.1:2014","min_dur_low_balance":"0","rals_conns":["*internal"],"registry_lease":"10s","replication_conns":[],"resources_conns":["*localhost"],"routes_conns":["*localhost"],"scheduler_conns":[],"session_indexes":[],"session_ttl":"0","shared_registry":false,"stats_conns":[],"stir":{"allowed_attest":["*any"],"default_attest":"A","payload_maxduration":"-1","privatekey_path":"","publickey_path":""},"store_session_costs":false,"terminate_attempts":5,"thresholds_conns":[]}}`
 	if err := cfg.V1GetConfigAsJSON(&SectionWithOpts{Section: SessionSJson}, &rcv); err != nil {
 		t.Error(err)
 	} else if expected != rcv {
@@ -937,6 +940,7 @@ func testCGRConfigReloadAll(t *testing.T) {
 		SessionIndexes:    utils.StringSet{},
 		ClientProtocol:    1,
 		TerminateAttempts: 5,
+		RegistryLease:     10 * time.Second,
 		AlterableFields:   utils.NewStringSet([]string{}),
 		STIRCfg: &STIRcfg{
 			AllowedAttest:      utils.NewStringSet([]string{utils.MetaAny}),
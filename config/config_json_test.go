@@ -49,6 +49,8 @@ func TestDfGeneralJsonCfg(t *testing.T) {
 		Connect_timeout:      utils.StringPointer("1s"),
 		Reply_timeout:        utils.StringPointer("2s"),
 		Locking_timeout:      utils.StringPointer("0"),
+		Locking_backend:      utils.StringPointer(utils.MetaInternal),
+		Locking_lease:        utils.StringPointer("10s"),
 		Digest_separator:     utils.StringPointer(","),
 		Digest_equal:         utils.StringPointer(":"),
 		Rsr_separator:        utils.StringPointer(";"),
@@ -67,10 +69,21 @@ func TestDfGeneralJsonCfg(t *testing.T) {
 
 func TestDfCoreSJsonCfg(t *testing.T) {
 	eCfg := &CoreSJsonCfg{
-		Caps:                utils.IntPointer(0),
-		Caps_strategy:       utils.StringPointer(utils.MetaBusy),
-		Caps_stats_interval: utils.StringPointer("0"),
-		Shutdown_timeout:    utils.StringPointer("1s"),
+		Caps:                 utils.IntPointer(0),
+		Caps_strategy:        utils.StringPointer(utils.MetaBusy),
+		Caps_stats_interval:  utils.StringPointer("0"),
+		Shutdown_timeout:     utils.StringPointer("1s"),
+		Sessions_conns:       &[]string{},
+		Stats_conns:          &[]string{},
+		Stat_queue_ids:       &[]string{},
+		Api_auth:             utils.BoolPointer(false),
+		Api_keys:             utils.MapStringStringPointer(map[string]string{}),
+		Jwt_key:              utils.StringPointer(""),
+		Api_roles:            &map[string]*APIRoleJsonCfg{},
+		Trace_exporter:       utils.StringPointer(""),
+		Trace_export_path:    utils.StringPointer("http://127.0.0.1:4318/v1/traces"),
+		Trace_sample_ratio:   utils.Float64Pointer(1),
+		Trace_flush_interval: utils.StringPointer("5s"),
 	}
 	dfCgrJSONCfg, err := NewCgrJsonCfgFromBytes([]byte(CGRATES_CFG_JSON))
 	if err != nil {
@@ -164,6 +177,15 @@ func TestCacheJsonCfg(t *testing.T) {
 			utils.CacheDispatcherHosts: {Limit: utils.IntPointer(-1),
 				Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
 				Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
+			utils.CachePortedNumbers: {Limit: utils.IntPointer(100000),
+				Ttl: utils.StringPointer("24h"), Static_ttl: utils.BoolPointer(false),
+				Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
+			utils.CacheSharedSessions: {Limit: utils.IntPointer(-1),
+				Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+				Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
+			utils.CacheConfigSections: {Limit: utils.IntPointer(-1),
+				Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+				Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 			utils.CacheResourceFilterIndexes: {Limit: utils.IntPointer(-1),
 				Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
 				Replicate: utils.BoolPointer(false)},
@@ -299,6 +321,12 @@ func TestCacheJsonCfg(t *testing.T) {
 			utils.CacheCDRsTBL: {Limit: utils.IntPointer(-1),
 				Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
 				Replicate: utils.BoolPointer(false)},
+			utils.CacheAuditLogTBL: {Limit: utils.IntPointer(-1),
+				Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+				Replicate: utils.BoolPointer(false)},
+			utils.CacheInvoicesTBL: {Limit: utils.IntPointer(-1),
+				Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+				Replicate: utils.BoolPointer(false)},
 			utils.CacheTBLTPRoutes: {Limit: utils.IntPointer(-1),
 				Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
 				Replicate: utils.BoolPointer(false)},
@@ -372,15 +400,19 @@ func TestDfDataDbJsonCfg(t *testing.T) {
 		Replication_conns: &[]string{},
 		Remote_conns:      &[]string{},
 		Opts: map[string]interface{}{
-			utils.RedisSentinelNameCfg:       "",
-			utils.QueryTimeoutCfg:            "10s",
-			utils.RedisClusterCfg:            false,
-			utils.RedisClusterOnDownDelayCfg: "0",
-			utils.RedisClusterSyncCfg:        "5s",
-			utils.RedisTLS:                   false,
-			utils.RedisClientCertificate:     "",
-			utils.RedisClientKey:             "",
-			utils.RedisCACertificate:         "",
+			utils.RedisSentinelNameCfg:        "",
+			utils.QueryTimeoutCfg:             "10s",
+			utils.RedisClusterCfg:             false,
+			utils.RedisClusterOnDownDelayCfg:  "0",
+			utils.RedisClusterSyncCfg:         "5s",
+			utils.RedisTLS:                    false,
+			utils.RedisClientCertificate:      "",
+			utils.RedisClientKey:              "",
+			utils.RedisCACertificate:          "",
+			utils.InternalDumpPathCfg:         "",
+			utils.InternalFsyncCfg:            utils.MetaInterval,
+			utils.InternalFsyncIntervalCfg:    "1s",
+			utils.InternalSnapshotIntervalCfg: "0",
 		},
 		Items: &map[string]*ItemOptJson{
 			utils.MetaAccounts: {
@@ -479,6 +511,10 @@ func TestDfDataDbJsonCfg(t *testing.T) {
 				Replicate: utils.BoolPointer(false),
 				Remote:    utils.BoolPointer(false),
 			},
+			utils.MetaPortedNumbers: {
+				Replicate: utils.BoolPointer(false),
+				Remote:    utils.BoolPointer(false),
+			},
 			utils.MetaChargerProfiles: {
 				Replicate: utils.BoolPointer(false),
 				Remote:    utils.BoolPointer(false),
@@ -519,11 +555,15 @@ func TestDfStorDBJsonCfg(t *testing.T) {
 		String_indexed_fields: &[]string{},
 		Prefix_indexed_fields: &[]string{},
 		Opts: map[string]interface{}{
-			utils.QueryTimeoutCfg:    "10s",
-			utils.MaxOpenConnsCfg:    100.,
-			utils.MaxIdleConnsCfg:    10.,
-			utils.ConnMaxLifetimeCfg: 0.,
-			utils.SSLModeCfg:         utils.PostgressSSLModeDisable,
+			utils.QueryTimeoutCfg:             "10s",
+			utils.MaxOpenConnsCfg:             100.,
+			utils.MaxIdleConnsCfg:             10.,
+			utils.ConnMaxLifetimeCfg:          0.,
+			utils.SSLModeCfg:                  utils.PostgressSSLModeDisable,
+			utils.InternalDumpPathCfg:         "",
+			utils.InternalFsyncCfg:            utils.MetaInterval,
+			utils.InternalFsyncIntervalCfg:    "1s",
+			utils.InternalSnapshotIntervalCfg: "0",
 		},
 		Items: &map[string]*ItemOptJson{
 			utils.CacheTBLTPTimings: {
@@ -626,6 +666,14 @@ func TestDfStorDBJsonCfg(t *testing.T) {
 				Replicate: utils.BoolPointer(false),
 				Remote:    utils.BoolPointer(false),
 			},
+			utils.CacheAuditLogTBL: {
+				Replicate: utils.BoolPointer(false),
+				Remote:    utils.BoolPointer(false),
+			},
+			utils.CacheInvoicesTBL: {
+				Replicate: utils.BoolPointer(false),
+				Remote:    utils.BoolPointer(false),
+			},
 			utils.CacheVersions: {
 				Replicate: utils.BoolPointer(false),
 				Remote:    utils.BoolPointer(false),
@@ -709,6 +757,8 @@ func TestDfCdrsJsonCfg(t *testing.T) {
 		Online_cdr_exports:   &[]string{},
 		Scheduler_conns:      &[]string{},
 		Ees_conns:            &[]string{},
+		Customer_run_id:      utils.StringPointer(""),
+		Supplier_run_id:      utils.StringPointer(""),
 	}
 	dfCgrJSONCfg, err := NewCgrJsonCfgFromBytes([]byte(CGRATES_CFG_JSON))
 	if err != nil {
@@ -741,6 +791,8 @@ func TestSmgJsonCfg(t *testing.T) {
 		Client_protocol:       utils.Float64Pointer(1.0),
 		Channel_sync_interval: utils.StringPointer("0"),
 		Terminate_attempts:    utils.IntPointer(5),
+		Shared_registry:       utils.BoolPointer(false),
+		Registry_lease:        utils.StringPointer("10s"),
 		Alterable_fields:      &[]string{},
 		Stir: &STIRJsonCfg{
 			Allowed_attest:      &[]string{utils.MetaAny},
@@ -944,6 +996,7 @@ func TestDfAttributeServJsonCfg(t *testing.T) {
 		Suffix_indexed_fields: &[]string{},
 		Nested_fields:         utils.BoolPointer(false),
 		Process_runs:          utils.IntPointer(1),
+		Lookups:               map[string]*AttrLookupJsonCfg{},
 	}
 	dfCgrJSONCfg, err := NewCgrJsonCfgFromBytes([]byte(CGRATES_CFG_JSON))
 	if err != nil {
@@ -1048,6 +1101,7 @@ func TestDfThresholdSJsonCfg(t *testing.T) {
 		Prefix_indexed_fields: &[]string{},
 		Suffix_indexed_fields: &[]string{},
 		Nested_fields:         utils.BoolPointer(false),
+		Actions_conns:         &[]string{},
 	}
 	dfCgrJSONCfg, err := NewCgrJsonCfgFromBytes([]byte(CGRATES_CFG_JSON))
 	if err != nil {
@@ -1092,6 +1146,7 @@ func TestDfLoaderJsonCfg(t *testing.T) {
 			Enabled:         utils.BoolPointer(false),
 			Tenant:          utils.StringPointer(""),
 			Dry_run:         utils.BoolPointer(false),
+			Transactional:   utils.BoolPointer(false),
 			Run_delay:       utils.StringPointer("0"),
 			Lock_filename:   utils.StringPointer(".cgr.lck"),
 			Caches_conns:    &[]string{utils.MetaInternal},
@@ -1772,6 +1827,30 @@ func TestDfLoaderJsonCfg(t *testing.T) {
 							Value: utils.StringPointer("~*req.16")},
 					},
 				},
+				{
+					Type:      utils.StringPointer(utils.MetaPortedNumbers),
+					File_name: utils.StringPointer(utils.PortedNumbersCsv),
+					Fields: &[]*FcTemplateJsonCfg{
+						{Tag: utils.StringPointer("Tenant"),
+							Path:      utils.StringPointer("Tenant"),
+							Type:      utils.StringPointer(utils.MetaVariable),
+							Value:     utils.StringPointer("~*req.0"),
+							Mandatory: utils.BoolPointer(true)},
+						{Tag: utils.StringPointer("ID"),
+							Path:      utils.StringPointer("ID"),
+							Type:      utils.StringPointer(utils.MetaVariable),
+							Value:     utils.StringPointer("~*req.1"),
+							Mandatory: utils.BoolPointer(true)},
+						{Tag: utils.StringPointer("RoutingNumber"),
+							Path:  utils.StringPointer("RoutingNumber"),
+							Type:  utils.StringPointer(utils.MetaVariable),
+							Value: utils.StringPointer("~*req.2")},
+						{Tag: utils.StringPointer("Carrier"),
+							Path:  utils.StringPointer("Carrier"),
+							Type:  utils.StringPointer(utils.MetaVariable),
+							Value: utils.StringPointer("~*req.3")},
+					},
+				},
 			},
 		},
 	}
@@ -1852,6 +1931,8 @@ func TestDfHttpJsonCfg(t *testing.T) {
 		Ws_url:                    utils.StringPointer("/ws"),
 		Freeswitch_cdrs_url:       utils.StringPointer("/freeswitch_json"),
 		Http_Cdrs:                 utils.StringPointer("/cdr_http"),
+		Metrics_url:               utils.StringPointer(""),
+		Events_url:                utils.StringPointer(""),
 		Use_basic_auth:            utils.BoolPointer(false),
 		Auth_users:                utils.MapStringStringPointer(map[string]string{}),
 		Client_opts: map[string]interface{}{
@@ -2009,11 +2090,15 @@ func TestDfAnalyzerCfg(t *testing.T) {
 
 func TestDfApierCfg(t *testing.T) {
 	eCfg := &ApierJsonCfg{
-		Enabled:          utils.BoolPointer(false),
-		Caches_conns:     &[]string{utils.MetaInternal},
-		Scheduler_conns:  &[]string{},
-		Attributes_conns: &[]string{},
-		Ees_conns:        &[]string{},
+		Enabled:            utils.BoolPointer(false),
+		Caches_conns:       &[]string{utils.MetaInternal},
+		Scheduler_conns:    &[]string{},
+		Attributes_conns:   &[]string{},
+		Ees_conns:          &[]string{},
+		Audit:              utils.BoolPointer(false),
+		Audit_apis:         &[]string{"APIerSv1.Set*", "APIerSv1.Remove*", "ConfigSv1.SetConfig*", "ConfigSv1.ReloadConfig"},
+		Audit_storage:      utils.StringPointer(utils.MetaStorDB),
+		Audit_exporter_ids: &[]string{},
 	}
 	dfCgrJSONCfg, err := NewCgrJsonCfgFromBytes([]byte(CGRATES_CFG_JSON))
 	if err != nil {
@@ -2054,6 +2139,9 @@ func TestDfEventReaderCfg(t *testing.T) {
 	eCfg := &ERsJsonCfg{
 		Enabled:        utils.BoolPointer(false),
 		Sessions_conns: &[]string{utils.MetaInternal},
+		Ees_conns:      &[]string{},
+		Dedup_store:    utils.StringPointer(utils.MetaInternal),
+		Dedup_ttl:      utils.StringPointer("1h"),
 		Readers: &[]*EventReaderJsonCfg{
 			{
 				Id:                      utils.StringPointer(utils.MetaDefault),
@@ -2072,6 +2160,8 @@ func TestDfEventReaderCfg(t *testing.T) {
 				Flags:                   &[]string{},
 				Fields:                  &cdrFields,
 				Cache_dump_fields:       &[]*FcTemplateJsonCfg{},
+				Dedup_id:                utils.StringPointer(utils.EmptyString),
+				Dedup_ee_ids:            &[]string{},
 				Opts:                    make(map[string]interface{}),
 			},
 		},
@@ -2435,3 +2525,22 @@ func TestDfActionSJsonCfg(t *testing.T) {
 		t.Errorf("\n Expected <%+v>,\nReceived:<%+v>", utils.ToJSON(eCfg), utils.ToJSON(cfg))
 	}
 }
+
+func TestDfInvoiceSJsonCfg(t *testing.T) {
+	eCfg := &InvoiceSJsonCfg{
+		Enabled:       utils.BoolPointer(false),
+		Run_ids:       &[]string{utils.MetaDefault},
+		Number_prefix: utils.StringPointer(utils.EmptyString),
+		Ees_conns:     &[]string{},
+		Exporter_ids:  &[]string{},
+	}
+	dfCgrJSONCfg, err := NewCgrJsonCfgFromBytes([]byte(CGRATES_CFG_JSON))
+	if err != nil {
+		t.Error(err)
+	}
+	if cfg, err := dfCgrJSONCfg.InvoiceSCfgJson(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("\n Expected <%+v>,\nReceived:<%+v>", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
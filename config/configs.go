@@ -22,15 +22,18 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
 
 // ConfigSCfg config for listening over http
 type ConfigSCfg struct {
-	Enabled bool
-	URL     string
-	RootDir string
+	Enabled              bool
+	URL                  string
+	RootDir              string
+	ConfigDB             bool          // overlay the sections stored in DataDB
+	ConfigDBSyncInterval time.Duration // check for changes in ConfigDB, 0 to rely only on the DataDB notifications
 }
 
 // loadFromJSONCfg loads Database config from JsonCfg
@@ -47,6 +50,14 @@ func (cScfg *ConfigSCfg) loadFromJSONCfg(jsnCfg *ConfigSCfgJson) (err error) {
 	if jsnCfg.Root_dir != nil {
 		cScfg.RootDir = *jsnCfg.Root_dir
 	}
+	if jsnCfg.Config_db != nil {
+		cScfg.ConfigDB = *jsnCfg.Config_db
+	}
+	if jsnCfg.Config_db_sync_interval != nil {
+		if cScfg.ConfigDBSyncInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Config_db_sync_interval); err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -111,9 +122,14 @@ func handleConfigSFile(path string, w http.ResponseWriter) {
 // AsMapInterface returns the config as a map[string]interface{}
 func (cScfg *ConfigSCfg) AsMapInterface() (initialMP map[string]interface{}) {
 	initialMP = map[string]interface{}{
-		utils.EnabledCfg: cScfg.Enabled,
-		utils.URLCfg:     cScfg.URL,
-		utils.RootDirCfg: cScfg.RootDir,
+		utils.EnabledCfg:              cScfg.Enabled,
+		utils.URLCfg:                  cScfg.URL,
+		utils.RootDirCfg:              cScfg.RootDir,
+		utils.ConfigDBCfg:             cScfg.ConfigDB,
+		utils.ConfigDBSyncIntervalCfg: "0",
+	}
+	if cScfg.ConfigDBSyncInterval != 0 {
+		initialMP[utils.ConfigDBSyncIntervalCfg] = cScfg.ConfigDBSyncInterval.String()
 	}
 	return
 }
@@ -124,5 +140,8 @@ func (cScfg *ConfigSCfg) Clone() *ConfigSCfg {
 		Enabled: cScfg.Enabled,
 		URL:     cScfg.URL,
 		RootDir: cScfg.RootDir,
+
+		ConfigDB:             cScfg.ConfigDB,
+		ConfigDBSyncInterval: cScfg.ConfigDBSyncInterval,
 	}
 }
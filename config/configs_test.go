@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -31,11 +32,16 @@ func TestConfigsloadFromJsonCfg(t *testing.T) {
 		Enabled:  utils.BoolPointer(true),
 		Url:      utils.StringPointer("/randomURL/"),
 		Root_dir: utils.StringPointer("/randomPath/"),
+
+		Config_db:               utils.BoolPointer(true),
+		Config_db_sync_interval: utils.StringPointer("1s"),
 	}
 	expectedCfg := &ConfigSCfg{
-		Enabled: true,
-		URL:     "/randomURL/",
-		RootDir: "/randomPath/",
+		Enabled:              true,
+		URL:                  "/randomURL/",
+		RootDir:              "/randomPath/",
+		ConfigDB:             true,
+		ConfigDBSyncInterval: time.Second,
 	}
 	cgrCfg := NewDefaultCGRConfig()
 	if err := cgrCfg.configSCfg.loadFromJSONCfg(jsonCfgs); err != nil {
@@ -50,13 +56,17 @@ func TestConfigsAsMapInterface(t *testing.T) {
       "configs": {
           "enabled": true,
           "url": "",
-          "root_dir": "/var/spool/cgrates/configs"
+          "root_dir": "/var/spool/cgrates/configs",
+          "config_db": true,
+          "config_db_sync_interval": "0",
       },
 }`
 	eMap := map[string]interface{}{
-		utils.EnabledCfg: true,
-		utils.URLCfg:     "",
-		utils.RootDirCfg: "/var/spool/cgrates/configs",
+		utils.EnabledCfg:              true,
+		utils.URLCfg:                  "",
+		utils.RootDirCfg:              "/var/spool/cgrates/configs",
+		utils.ConfigDBCfg:             true,
+		utils.ConfigDBSyncIntervalCfg: "0",
 	}
 	if cgrCfg, err := NewCGRConfigFromJSONStringWithDefaults(cfgsJSONStr); err != nil {
 		t.Error(err)
@@ -70,9 +80,11 @@ func TestConfigsAsMapInterface2(t *testing.T) {
       "configs":{}
 }`
 	eMap := map[string]interface{}{
-		utils.EnabledCfg: false,
-		utils.URLCfg:     "/configs/",
-		utils.RootDirCfg: "/var/spool/cgrates/configs",
+		utils.EnabledCfg:              false,
+		utils.URLCfg:                  "/configs/",
+		utils.RootDirCfg:              "/var/spool/cgrates/configs",
+		utils.ConfigDBCfg:             false,
+		utils.ConfigDBSyncIntervalCfg: "5s",
 	}
 	if cgrCfg, err := NewCGRConfigFromJSONStringWithDefaults(cfgsJSONStr); err != nil {
 		t.Error(err)
@@ -100,9 +112,11 @@ func TestNewCGRConfigFromPathWithoutEnv(t *testing.T) {
 
 func TestConfigSCfgClone(t *testing.T) {
 	cS := &ConfigSCfg{
-		Enabled: true,
-		URL:     "/randomURL/",
-		RootDir: "/randomPath/",
+		Enabled:              true,
+		URL:                  "/randomURL/",
+		RootDir:              "/randomPath/",
+		ConfigDB:             true,
+		ConfigDBSyncInterval: time.Second,
 	}
 	rcv := cS.Clone()
 	if !reflect.DeepEqual(cS, rcv) {
@@ -21,6 +21,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"strings"
 
 	"github.com/cgrates/cgrates/utils"
@@ -148,6 +149,14 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 		if cfg.sessionSCfg.TerminateAttempts < 1 {
 			return fmt.Errorf("<%s> 'terminate_attempts' should be at least 1", utils.SessionS)
 		}
+		if cfg.sessionSCfg.SharedRegistry {
+			if cfg.sessionSCfg.RegistryLease <= 0 {
+				return fmt.Errorf("<%s> the registry_lease needs to be positive, received: %s", utils.SessionS, cfg.sessionSCfg.RegistryLease)
+			}
+			if cfg.dataDbCfg.DataDbType == utils.INTERNAL {
+				return fmt.Errorf("<%s> the shared_registry can not be used with %s DataDB", utils.SessionS, utils.MetaInternal)
+			}
+		}
 		for _, connID := range cfg.sessionSCfg.ChargerSConns {
 			if strings.HasPrefix(connID, utils.MetaInternal) && !cfg.chargerSCfg.Enabled {
 				return fmt.Errorf("<%s> not enabled but requested by <%s> component", utils.ChargerS, utils.SessionS)
@@ -428,6 +437,20 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 		if cfg.attributeSCfg.ProcessRuns < 1 {
 			return fmt.Errorf("<%s> process_runs needs to be bigger than 0", utils.AttributeS)
 		}
+		for lkID, lk := range cfg.attributeSCfg.Lookups {
+			switch lk.Type {
+			case utils.MetaHTTP:
+			case utils.MetaSQL:
+				if lk.Query == utils.EmptyString {
+					return fmt.Errorf("<%s> empty query for lookup with ID: %s", utils.AttributeS, lkID)
+				}
+			default:
+				return fmt.Errorf("<%s> unsupported type: %s for lookup with ID: %s", utils.AttributeS, lk.Type, lkID)
+			}
+			if lk.URL == utils.EmptyString {
+				return fmt.Errorf("<%s> empty url for lookup with ID: %s", utils.AttributeS, lkID)
+			}
+		}
 	}
 	if cfg.chargerSCfg.Enabled {
 		for _, connID := range cfg.chargerSCfg.AttributeSConns {
@@ -461,6 +484,17 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 			}
 		}
 	}
+	// ThresholdS checks
+	if cfg.thresholdSCfg.Enabled {
+		for _, connID := range cfg.thresholdSCfg.ActionSConns {
+			if strings.HasPrefix(connID, utils.MetaInternal) && !cfg.actionSCfg.Enabled {
+				return fmt.Errorf("<%s> not enabled but requested by <%s> component", utils.ActionS, utils.ThresholdS)
+			}
+			if _, has := cfg.rpcConns[connID]; !has && !strings.HasPrefix(connID, utils.MetaInternal) {
+				return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.ThresholdS, connID)
+			}
+		}
+	}
 	// RouteS checks
 	if cfg.routeSCfg.Enabled {
 		for _, connID := range cfg.routeSCfg.AttributeSConns {
@@ -533,10 +567,29 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 				return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.ERs, connID)
 			}
 		}
+		for _, connID := range cfg.ersCfg.EEsConns {
+			if strings.HasPrefix(connID, utils.MetaInternal) && !cfg.eesCfg.Enabled {
+				return fmt.Errorf("<%s> not enabled but requested by <%s> component", utils.EEs, utils.ERs)
+			}
+			if _, has := cfg.rpcConns[connID]; !has && !strings.HasPrefix(connID, utils.MetaInternal) {
+				return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.ERs, connID)
+			}
+		}
 		for _, rdr := range cfg.ersCfg.Readers {
 			if !possibleReaderTypes.Has(rdr.Type) {
 				return fmt.Errorf("<%s> unsupported data type: %s for reader with ID: %s", utils.ERs, rdr.Type, rdr.ID)
 			}
+			if len(rdr.DedupID) != 0 &&
+				cfg.ersCfg.DedupStore != utils.MetaInternal &&
+				cfg.ersCfg.DedupStore != utils.MetaDataDB {
+				return fmt.Errorf("<%s> unsupported dedup_store: <%s> for reader with ID: %s", utils.ERs, cfg.ersCfg.DedupStore, rdr.ID)
+			}
+			if len(rdr.DedupID) != 0 && cfg.ersCfg.DedupTTL <= 0 {
+				return fmt.Errorf("<%s> dedup_ttl should be greater than 0 for reader with ID: %s", utils.ERs, rdr.ID)
+			}
+			if len(rdr.DedupEEIDs) != 0 && len(cfg.ersCfg.EEsConns) == 0 {
+				return fmt.Errorf("<%s> dedup_ee_ids requires ees_conns for reader with ID: %s", utils.ERs, rdr.ID)
+			}
 
 			switch rdr.Type {
 			case utils.MetaFileCSV, utils.MetaPartialCSV, utils.MetaFlatstore:
@@ -606,6 +659,13 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 				if len(exp.ContentFields()) == 0 {
 					return fmt.Errorf("<%s> empty content fields for exporter with ID: %s", utils.EEs, exp.ID)
 				}
+			case utils.MetaFileTemplate:
+				if _, err := os.Stat(exp.ExportPath); err != nil && os.IsNotExist(err) {
+					return fmt.Errorf("<%s> nonexistent folder: %s for exporter with ID: %s", utils.EEs, exp.ExportPath, exp.ID)
+				}
+				if _, has := exp.Opts[utils.TemplatePath]; !has {
+					return fmt.Errorf("<%s> empty %s for exporter with ID: %s", utils.EEs, utils.TemplatePath, exp.ID)
+				}
 			}
 			for _, field := range exp.Fields {
 				if field.Type != utils.MetaNone && field.Path == utils.EmptyString {
@@ -634,6 +694,22 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 			return fmt.Errorf("<%s> the StoreInterval field needs to be -1 when DataBD is *internal, received : %d", utils.ThresholdS, cfg.thresholdSCfg.StoreInterval)
 		}
 	}
+	switch cfg.generalCfg.LockingBackend {
+	case utils.MetaInternal:
+	case utils.MetaRedis:
+		if cfg.dataDbCfg.DataDbType != utils.Redis {
+			return fmt.Errorf("<%s> %s locking_backend requires a %s DataDB", GENERAL_JSN, utils.MetaRedis, utils.Redis)
+		}
+		if isCluster, _ := utils.IfaceAsBool(cfg.dataDbCfg.Opts[utils.RedisClusterCfg]); isCluster ||
+			utils.IfaceAsString(cfg.dataDbCfg.Opts[utils.RedisSentinelNameCfg]) != utils.EmptyString {
+			return fmt.Errorf("<%s> %s locking_backend does not support redis cluster or sentinel", GENERAL_JSN, utils.MetaRedis)
+		}
+		if cfg.generalCfg.LockingLease <= 0 {
+			return fmt.Errorf("<%s> the locking_lease needs to be positive, received: %s", GENERAL_JSN, cfg.generalCfg.LockingLease)
+		}
+	default:
+		return fmt.Errorf("<%s> unsupported locking_backend: %s", GENERAL_JSN, cfg.generalCfg.LockingBackend)
+	}
 	for item, val := range cfg.dataDbCfg.Items {
 		if val.Remote == true && len(cfg.dataDbCfg.RmtConns) == 0 {
 			return fmt.Errorf("remote connections required by: <%s>", item)
@@ -659,6 +735,36 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 			return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.APIerSv1, connID)
 		}
 	}
+	if cfg.apier.Audit {
+		switch cfg.apier.AuditStorage {
+		case utils.MetaStorDB:
+		case utils.MetaEEs:
+			if len(cfg.apier.EEsConns) == 0 {
+				return fmt.Errorf("<%s> the %s audit_storage requires ees_conns", utils.APIerSv1, utils.MetaEEs)
+			}
+		default:
+			return fmt.Errorf("<%s> unsupported audit_storage <%s>", utils.APIerSv1, cfg.apier.AuditStorage)
+		}
+		for _, pattern := range cfg.apier.AuditAPIs {
+			if _, err := path.Match(pattern, utils.EmptyString); err != nil {
+				return fmt.Errorf("<%s> invalid audit API pattern <%s>", utils.APIerSv1, pattern)
+			}
+		}
+	}
+	// InvoiceS sanity checks
+	if cfg.invoiceSCfg.Enabled {
+		if len(cfg.invoiceSCfg.RunIDs) == 0 {
+			return fmt.Errorf("<%s> no %s defined", utils.InvoiceS, utils.RunIDsCfg)
+		}
+		for _, connID := range cfg.invoiceSCfg.EEsConns {
+			if strings.HasPrefix(connID, utils.MetaInternal) && !cfg.eesCfg.Enabled {
+				return fmt.Errorf("<%s> not enabled but requested by <%s> component", utils.EEs, utils.InvoiceS)
+			}
+			if _, has := cfg.rpcConns[connID]; !has && !strings.HasPrefix(connID, utils.MetaInternal) {
+				return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.InvoiceS, connID)
+			}
+		}
+	}
 	// Dispatcher sanity check
 	if cfg.dispatcherSCfg.Enabled {
 		for _, connID := range cfg.dispatcherSCfg.AttributeSConns {
@@ -761,6 +867,69 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 			return fmt.Errorf("<%s> the CleanupInterval needs to be bigger than 0", utils.AnalyzerS)
 		}
 	}
+	// CoreS metrics checks
+	for _, connID := range cfg.coreSCfg.SessionSConns {
+		if strings.HasPrefix(connID, utils.MetaInternal) && !cfg.sessionSCfg.Enabled {
+			return fmt.Errorf("<%s> not enabled but requested by <%s> component", utils.SessionS, utils.CoreS)
+		}
+		if _, has := cfg.rpcConns[connID]; !has && !strings.HasPrefix(connID, utils.MetaInternal) {
+			return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.CoreS, connID)
+		}
+	}
+	for _, connID := range cfg.coreSCfg.StatSConns {
+		if strings.HasPrefix(connID, utils.MetaInternal) && !cfg.statsCfg.Enabled {
+			return fmt.Errorf("<%s> not enabled but requested by <%s> component", utils.StatService, utils.CoreS)
+		}
+		if _, has := cfg.rpcConns[connID]; !has && !strings.HasPrefix(connID, utils.MetaInternal) {
+			return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.CoreS, connID)
+		}
+	}
+	// CoreS API authorization checks
+	if cfg.coreSCfg.APIAuth {
+		if len(cfg.coreSCfg.APIKeys) == 0 && cfg.coreSCfg.JWTKey == utils.EmptyString {
+			return fmt.Errorf("<%s> the api_auth requires api_keys or jwt_key", utils.CoreS)
+		}
+		for key, role := range cfg.coreSCfg.APIKeys {
+			if _, has := cfg.coreSCfg.APIRoles[role]; !has {
+				return fmt.Errorf("<%s> role <%s> of API key <%s> not defined", utils.CoreS, role, key)
+			}
+		}
+		for name, role := range cfg.coreSCfg.APIRoles {
+			for _, pattern := range append(append([]string{}, role.Allow...), role.Deny...) {
+				if _, err := path.Match(pattern, utils.EmptyString); err != nil {
+					return fmt.Errorf("<%s> invalid API pattern <%s> for role <%s>", utils.CoreS, pattern, name)
+				}
+			}
+		}
+	}
+	// CoreS tracing checks
+	if cfg.coreSCfg.TraceExporter != utils.EmptyString {
+		if cfg.coreSCfg.TraceExporter != utils.MetaOTLP &&
+			cfg.coreSCfg.TraceExporter != utils.MetaFile {
+			return fmt.Errorf("<%s> unsupported trace_exporter <%s>", utils.CoreS, cfg.coreSCfg.TraceExporter)
+		}
+		if cfg.coreSCfg.TraceExportPath == utils.EmptyString {
+			return fmt.Errorf("<%s> the trace_exporter requires trace_export_path", utils.CoreS)
+		}
+		if cfg.coreSCfg.TraceSampleRatio < 0 || cfg.coreSCfg.TraceSampleRatio > 1 {
+			return fmt.Errorf("<%s> the trace_sample_ratio needs to be between 0 and 1, received: %v",
+				utils.CoreS, cfg.coreSCfg.TraceSampleRatio)
+		}
+		if cfg.coreSCfg.TraceFlushInterval <= 0 {
+			return fmt.Errorf("<%s> the trace_flush_interval needs to be positive, received: %s",
+				utils.CoreS, cfg.coreSCfg.TraceFlushInterval)
+		}
+	}
+	// ConfigDB checks
+	if cfg.configSCfg.ConfigDB {
+		if cfg.configSCfg.ConfigDBSyncInterval < 0 {
+			return fmt.Errorf("<%s> the config_db_sync_interval needs to be positive", utils.ConfigDB)
+		}
+		// the config is loaded before starting the DataDB so the in-process one can not be used
+		if cfg.dataDbCfg.DataDbType == utils.INTERNAL {
+			return fmt.Errorf("<%s> %s DataDB not supported", utils.ConfigDB, utils.MetaInternal)
+		}
+	}
 
 	return nil
 }
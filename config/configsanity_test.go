@@ -19,6 +19,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -705,6 +706,25 @@ func TestConfigSanityAttributesCfg(t *testing.T) {
 	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
 		t.Errorf("Expecting: %+q  received: %+q", expected, err)
 	}
+	cfg.attributeSCfg.ProcessRuns = 1
+	cfg.attributeSCfg.Lookups = map[string]*AttributeLookupCfg{
+		"LNP": {Type: utils.MetaSQL, URL: "*mysql://127.0.0.1:3306/lnp"},
+	}
+	expected = "<AttributeS> empty query for lookup with ID: LNP"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.attributeSCfg.Lookups["LNP"].Type = "*ldap"
+	expected = "<AttributeS> unsupported type: *ldap for lookup with ID: LNP"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.attributeSCfg.Lookups["LNP"].Type = utils.MetaHTTP
+	cfg.attributeSCfg.Lookups["LNP"].URL = utils.EmptyString
+	expected = "<AttributeS> empty url for lookup with ID: LNP"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
 }
 
 func TestConfigSanityChargerS(t *testing.T) {
@@ -759,6 +779,23 @@ func TestConfigSanityStatS(t *testing.T) {
 	}
 }
 
+func TestConfigSanityThresholdS(t *testing.T) {
+	cfg = NewDefaultCGRConfig()
+	cfg.thresholdSCfg = &ThresholdSCfg{
+		Enabled:      true,
+		ActionSConns: []string{utils.MetaInternal},
+	}
+	expected := "<ActionS> not enabled but requested by <ThresholdS> component"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.thresholdSCfg.ActionSConns = []string{"test"}
+	expected = "<ThresholdS> connection with id: <test> not defined"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+}
+
 func TestConfigSanityRouteS(t *testing.T) {
 	cfg = NewDefaultCGRConfig()
 	cfg.routeSCfg.Enabled = true
@@ -936,6 +973,23 @@ func TestConfigSanityEventReader(t *testing.T) {
 		t.Errorf("Expecting: %+q  received: %+q", expected, err)
 	}
 
+	cfg.ersCfg.Readers[0] = &EventReaderCfg{
+		ID:       "test6",
+		Type:     utils.MetaKafkajsonMap,
+		FieldSep: utils.InInFieldSep,
+		DedupID:  NewRSRParsersMustCompile("~*req.OriginID", utils.InfieldSep),
+	}
+	cfg.ersCfg.DedupStore = "*unsupported"
+	expected = "<ERs> unsupported dedup_store: <*unsupported> for reader with ID: test6"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.ersCfg.DedupStore = utils.MetaInternal
+	expected = "<ERs> dedup_ttl should be greater than 0 for reader with ID: test6"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+
 	cfg.ersCfg = &ERsCfg{
 		Enabled: true,
 		Readers: []*EventReaderCfg{
@@ -1024,6 +1078,17 @@ func TestConfigSanityEventExporter(t *testing.T) {
 	if err := cfg.CheckConfigSanity(); err == nil || err.Error() != expected {
 		t.Errorf("Expecting: %+q  received: %+q", expected, err)
 	}
+
+	cfg.eesCfg.Exporters[0].Type = utils.MetaFileTemplate
+	expected = "<EEs> nonexistent folder: randomPath for exporter with ID: "
+	if err := cfg.CheckConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.eesCfg.Exporters[0].ExportPath = "/"
+	expected = "<EEs> empty templatePath for exporter with ID: "
+	if err := cfg.CheckConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
 }
 
 func TestConfigSanityCache(t *testing.T) {
@@ -1159,6 +1224,33 @@ func TestConfigSanityAnalyzer(t *testing.T) {
 	}
 }
 
+func TestConfigSanityCoreS(t *testing.T) {
+	cfg := NewDefaultCGRConfig()
+
+	cfg.coreSCfg.SessionSConns = []string{utils.MetaInternal}
+	expected := "<SessionS> not enabled but requested by <CoreS> component"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.SessionSConns = []string{"test"}
+	expected = "<CoreS> connection with id: <test> not defined"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.SessionSConns = []string{}
+
+	cfg.coreSCfg.StatSConns = []string{utils.MetaInternal}
+	expected = "<StatS> not enabled but requested by <CoreS> component"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.StatSConns = []string{"test"}
+	expected = "<CoreS> connection with id: <test> not defined"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+}
+
 func TestConfigSanityDataDB(t *testing.T) {
 	cfg = NewDefaultCGRConfig()
 	cfg.dataDbCfg.DataDbType = utils.INTERNAL
@@ -1252,6 +1344,53 @@ func TestConfigSanityAPIer(t *testing.T) {
 	}
 }
 
+func TestConfigSanityAPIerAudit(t *testing.T) {
+	cfg = NewDefaultCGRConfig()
+	cfg.apier.Audit = true
+	if err := cfg.checkConfigSanity(); err != nil {
+		t.Error(err)
+	}
+	cfg.apier.AuditStorage = utils.MetaEEs
+	expected := "<APIerSv1> the *ees audit_storage requires ees_conns"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.apier.AuditStorage = utils.MetaFileCSV
+	expected = "<APIerSv1> unsupported audit_storage <*file_csv>"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.apier.AuditStorage = utils.MetaStorDB
+	cfg.apier.AuditAPIs = []string{"APIerSv1.Set["}
+	expected = "<APIerSv1> invalid audit API pattern <APIerSv1.Set[>"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+}
+
+func TestConfigSanityInvoiceS(t *testing.T) {
+	cfg = NewDefaultCGRConfig()
+	cfg.invoiceSCfg.Enabled = true
+	if err := cfg.checkConfigSanity(); err != nil {
+		t.Error(err)
+	}
+	cfg.invoiceSCfg.EEsConns = []string{utils.MetaInternal}
+	expected := "<EEs> not enabled but requested by <InvoiceS> component"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.invoiceSCfg.EEsConns = []string{"test"}
+	expected = "<InvoiceS> connection with id: <test> not defined"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.invoiceSCfg.RunIDs = nil
+	expected = "<InvoiceS> no run_ids defined"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+}
+
 func TestConfigSanityDispatcher(t *testing.T) {
 	cfg = NewDefaultCGRConfig()
 	cfg.dispatcherSCfg = &DispatcherSCfg{
@@ -1387,3 +1526,119 @@ func TestCheckConfigSanity(t *testing.T) {
 		t.Errorf("Expecting: %+q  received: %+q", expected, err)
 	}
 }
+
+func TestConfigSanityLockingBackend(t *testing.T) {
+	cfg := NewDefaultCGRConfig()
+	cfg.generalCfg.LockingBackend = "*unsupported"
+	expected := "<general> unsupported locking_backend: *unsupported"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.generalCfg.LockingBackend = utils.MetaRedis
+	cfg.generalCfg.LockingLease = 0
+	expected = "<general> the locking_lease needs to be positive, received: 0s"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.dataDbCfg.Opts[utils.RedisSentinelNameCfg] = "sentinel1"
+	expected = "<general> *redis locking_backend does not support redis cluster or sentinel"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.dataDbCfg.DataDbType = utils.Mongo
+	expected = "<general> *redis locking_backend requires a redis DataDB"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+}
+
+func TestConfigSanitySessionSSharedRegistry(t *testing.T) {
+	cfg := NewDefaultCGRConfig()
+	cfg.sessionSCfg.Enabled = true
+	cfg.sessionSCfg.SharedRegistry = true
+	cfg.dataDbCfg.DataDbType = utils.INTERNAL
+	expected := "<SessionS> the shared_registry can not be used with *internal DataDB"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.sessionSCfg.RegistryLease = 0
+	expected = "<SessionS> the registry_lease needs to be positive, received: 0s"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+}
+
+func TestConfigSanityCoreSAPIAuth(t *testing.T) {
+	cfg := NewDefaultCGRConfig()
+	cfg.coreSCfg.APIAuth = true
+	expected := "<CoreS> the api_auth requires api_keys or jwt_key"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.APIKeys = map[string]string{"key1": "reseller"}
+	expected = "<CoreS> role <reseller> of API key <key1> not defined"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.APIRoles = map[string]*APIRole{
+		"reseller": {Allow: []string{"APIerSv1.[Get*"}},
+	}
+	expected = "<CoreS> invalid API pattern <APIerSv1.[Get*> for role <reseller>"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.APIRoles["reseller"].Allow = []string{"APIerSv1.Get*"}
+	if err := cfg.checkConfigSanity(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConfigSanityCoreSTracing(t *testing.T) {
+	cfg := NewDefaultCGRConfig()
+	cfg.coreSCfg.TraceExporter = "*zipkin"
+	expected := "<CoreS> unsupported trace_exporter <*zipkin>"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.TraceExporter = utils.MetaFile
+	cfg.coreSCfg.TraceExportPath = utils.EmptyString
+	expected = "<CoreS> the trace_exporter requires trace_export_path"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.TraceExportPath = "/tmp/traces.json"
+	cfg.coreSCfg.TraceSampleRatio = 1.5
+	expected = "<CoreS> the trace_sample_ratio needs to be between 0 and 1, received: 1.5"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.TraceSampleRatio = 0.1
+	cfg.coreSCfg.TraceFlushInterval = 0
+	expected = "<CoreS> the trace_flush_interval needs to be positive, received: 0s"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.coreSCfg.TraceFlushInterval = time.Second
+	if err := cfg.checkConfigSanity(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConfigSanityConfigDB(t *testing.T) {
+	cfg := NewDefaultCGRConfig()
+	cfg.configSCfg.ConfigDB = true
+	if err := cfg.checkConfigSanity(); err != nil {
+		t.Error(err)
+	}
+	cfg.configSCfg.ConfigDBSyncInterval = -time.Second
+	expected := "<ConfigDB> the config_db_sync_interval needs to be positive"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+	cfg.configSCfg.ConfigDBSyncInterval = 0
+	cfg.dataDbCfg.DataDbType = utils.INTERNAL
+	expected = "<ConfigDB> *internal DataDB not supported"
+	if err := cfg.checkConfigSanity(); err == nil || err.Error() != expected {
+		t.Errorf("Expecting: %+q  received: %+q", expected, err)
+	}
+}
@@ -26,10 +26,68 @@ import (
 
 // CoreSCfg the config for the coreS
 type CoreSCfg struct {
-	Caps              int
-	CapsStrategy      string
-	CapsStatsInterval time.Duration
-	ShutdownTimeout   time.Duration
+	Caps               int
+	CapsStrategy       string
+	CapsStatsInterval  time.Duration
+	ShutdownTimeout    time.Duration
+	SessionSConns      []string            // used by the metrics handler to query the active sessions
+	StatSConns         []string            // used by the metrics handler to query the StatQueues
+	StatQueueIDs       []string            // StatQueues exported as metrics, all for the default tenant if empty
+	APIAuth            bool                // authorize the API calls on all the listeners
+	APIKeys            map[string]string   // API keys with the name of their role
+	JWTKey             string              // secret validating the HS256 JWTs
+	APIRoles           map[string]*APIRole // the API methods and tenants allowed for each role
+	TraceExporter      string              // where the spans are exported: <""|*otlp|*file>
+	TraceExportPath    string              // the OTLP/HTTP URL or the path of the file
+	TraceSampleRatio   float64             // ratio of the new traces which are sampled
+	TraceFlushInterval time.Duration       // interval of exporting the spans
+}
+
+// APIRole restricts the API methods and the tenants accessible with the credentials of the role
+type APIRole struct {
+	Allow   []string // API methods allowed, * as wildcard, eg: APIerSv1.Get*
+	Deny    []string // API methods denied, checked before the allowed ones
+	Tenants []string // tenants accessible, all of them if empty
+}
+
+func (r *APIRole) loadFromJSONCfg(jsnCfg *APIRoleJsonCfg) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Allow != nil {
+		r.Allow = cloneAPIRoleSlice(*jsnCfg.Allow)
+	}
+	if jsnCfg.Deny != nil {
+		r.Deny = cloneAPIRoleSlice(*jsnCfg.Deny)
+	}
+	if jsnCfg.Tenants != nil {
+		r.Tenants = cloneAPIRoleSlice(*jsnCfg.Tenants)
+	}
+}
+
+// cloneAPIRoleSlice returns a copy of the slice, empty if nil
+func cloneAPIRoleSlice(sl []string) (cln []string) {
+	cln = make([]string, len(sl))
+	copy(cln, sl)
+	return
+}
+
+// AsMapInterface returns the config as a map[string]interface{}
+func (r *APIRole) AsMapInterface() map[string]interface{} {
+	return map[string]interface{}{
+		utils.AllowCfg:   cloneAPIRoleSlice(r.Allow),
+		utils.DenyCfg:    cloneAPIRoleSlice(r.Deny),
+		utils.TenantsCfg: cloneAPIRoleSlice(r.Tenants),
+	}
+}
+
+// Clone returns a deep copy of APIRole
+func (r APIRole) Clone() *APIRole {
+	return &APIRole{
+		Allow:   cloneAPIRoleSlice(r.Allow),
+		Deny:    cloneAPIRoleSlice(r.Deny),
+		Tenants: cloneAPIRoleSlice(r.Tenants),
+	}
 }
 
 func (cS *CoreSCfg) loadFromJSONCfg(jsnCfg *CoreSJsonCfg) (err error) {
@@ -52,16 +110,87 @@ func (cS *CoreSCfg) loadFromJSONCfg(jsnCfg *CoreSJsonCfg) (err error) {
 			return
 		}
 	}
+	if jsnCfg.Sessions_conns != nil {
+		cS.SessionSConns = make([]string, len(*jsnCfg.Sessions_conns))
+		for i, fID := range *jsnCfg.Sessions_conns {
+			// if we have the connection internal we change the name so we can have internal rpc for each subsystem
+			cS.SessionSConns[i] = fID
+			if fID == utils.MetaInternal {
+				cS.SessionSConns[i] = utils.ConcatenatedKey(utils.MetaInternal, utils.MetaSessionS)
+			}
+		}
+	}
+	if jsnCfg.Stats_conns != nil {
+		cS.StatSConns = make([]string, len(*jsnCfg.Stats_conns))
+		for i, fID := range *jsnCfg.Stats_conns {
+			cS.StatSConns[i] = fID
+			if fID == utils.MetaInternal {
+				cS.StatSConns[i] = utils.ConcatenatedKey(utils.MetaInternal, utils.MetaStatS)
+			}
+		}
+	}
+	if jsnCfg.Stat_queue_ids != nil {
+		cS.StatQueueIDs = make([]string, len(*jsnCfg.Stat_queue_ids))
+		for i, sqID := range *jsnCfg.Stat_queue_ids {
+			cS.StatQueueIDs[i] = sqID
+		}
+	}
+	if jsnCfg.Api_auth != nil {
+		cS.APIAuth = *jsnCfg.Api_auth
+	}
+	if jsnCfg.Api_keys != nil {
+		cS.APIKeys = make(map[string]string)
+		for key, role := range *jsnCfg.Api_keys {
+			cS.APIKeys[key] = role
+		}
+	}
+	if jsnCfg.Jwt_key != nil {
+		cS.JWTKey = *jsnCfg.Jwt_key
+	}
+	if jsnCfg.Api_roles != nil {
+		if cS.APIRoles == nil {
+			cS.APIRoles = make(map[string]*APIRole)
+		}
+		for name, jsnRole := range *jsnCfg.Api_roles {
+			role, has := cS.APIRoles[name]
+			if !has {
+				role = new(APIRole)
+				cS.APIRoles[name] = role
+			}
+			role.loadFromJSONCfg(jsnRole)
+		}
+	}
+	if jsnCfg.Trace_exporter != nil {
+		cS.TraceExporter = *jsnCfg.Trace_exporter
+	}
+	if jsnCfg.Trace_export_path != nil {
+		cS.TraceExportPath = *jsnCfg.Trace_export_path
+	}
+	if jsnCfg.Trace_sample_ratio != nil {
+		cS.TraceSampleRatio = *jsnCfg.Trace_sample_ratio
+	}
+	if jsnCfg.Trace_flush_interval != nil {
+		if cS.TraceFlushInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Trace_flush_interval); err != nil {
+			return
+		}
+	}
 	return
 }
 
 // AsMapInterface returns the config as a map[string]interface{}
+// with the JWT key and the API keys masked
 func (cS *CoreSCfg) AsMapInterface() map[string]interface{} {
 	mp := map[string]interface{}{
-		utils.CapsCfg:              cS.Caps,
-		utils.CapsStrategyCfg:      cS.CapsStrategy,
-		utils.CapsStatsIntervalCfg: cS.CapsStatsInterval.String(),
-		utils.ShutdownTimeoutCfg:   cS.ShutdownTimeout.String(),
+		utils.CapsCfg:               cS.Caps,
+		utils.CapsStrategyCfg:       cS.CapsStrategy,
+		utils.CapsStatsIntervalCfg:  cS.CapsStatsInterval.String(),
+		utils.ShutdownTimeoutCfg:    cS.ShutdownTimeout.String(),
+		utils.APIAuthCfg:            cS.APIAuth,
+		utils.JWTKeyCfg:             utils.MaskSecret(cS.JWTKey),
+		utils.TraceExporterCfg:      cS.TraceExporter,
+		utils.TraceExportPathCfg:    cS.TraceExportPath,
+		utils.TraceSampleRatioCfg:   cS.TraceSampleRatio,
+		utils.TraceFlushIntervalCfg: cS.TraceFlushInterval.String(),
 	}
 	if cS.CapsStatsInterval == 0 {
 		mp[utils.CapsStatsIntervalCfg] = "0"
@@ -69,15 +198,96 @@ func (cS *CoreSCfg) AsMapInterface() map[string]interface{} {
 	if cS.ShutdownTimeout == 0 {
 		mp[utils.ShutdownTimeoutCfg] = "0"
 	}
+	if cS.TraceFlushInterval == 0 {
+		mp[utils.TraceFlushIntervalCfg] = "0"
+	}
+	if cS.SessionSConns != nil {
+		sessionSConns := make([]string, len(cS.SessionSConns))
+		for i, item := range cS.SessionSConns {
+			sessionSConns[i] = item
+			if item == utils.ConcatenatedKey(utils.MetaInternal, utils.MetaSessionS) {
+				sessionSConns[i] = utils.MetaInternal
+			}
+		}
+		mp[utils.SessionSConnsCfg] = sessionSConns
+	}
+	if cS.StatSConns != nil {
+		statSConns := make([]string, len(cS.StatSConns))
+		for i, item := range cS.StatSConns {
+			statSConns[i] = item
+			if item == utils.ConcatenatedKey(utils.MetaInternal, utils.MetaStatS) {
+				statSConns[i] = utils.MetaInternal
+			}
+		}
+		mp[utils.StatSConnsCfg] = statSConns
+	}
+	if cS.StatQueueIDs != nil {
+		statQueueIDs := make([]string, len(cS.StatQueueIDs))
+		for i, item := range cS.StatQueueIDs {
+			statQueueIDs[i] = item
+		}
+		mp[utils.StatQueueIDsCfg] = statQueueIDs
+	}
+	if cS.APIKeys != nil { // the keys are secrets, only their fingerprints are returned
+		apiKeys := make(map[string]interface{})
+		for key, role := range cS.APIKeys {
+			apiKeys[utils.MaskSecret(key)] = role
+		}
+		mp[utils.APIKeysCfg] = apiKeys
+	}
+	if cS.APIRoles != nil {
+		apiRoles := make(map[string]interface{})
+		for name, role := range cS.APIRoles {
+			apiRoles[name] = role.AsMapInterface()
+		}
+		mp[utils.APIRolesCfg] = apiRoles
+	}
 	return mp
 }
 
 // Clone returns a deep copy of CoreSCfg
-func (cS CoreSCfg) Clone() *CoreSCfg {
-	return &CoreSCfg{
-		Caps:              cS.Caps,
-		CapsStrategy:      cS.CapsStrategy,
-		CapsStatsInterval: cS.CapsStatsInterval,
-		ShutdownTimeout:   cS.ShutdownTimeout,
+func (cS CoreSCfg) Clone() (cln *CoreSCfg) {
+	cln = &CoreSCfg{
+		Caps:               cS.Caps,
+		CapsStrategy:       cS.CapsStrategy,
+		CapsStatsInterval:  cS.CapsStatsInterval,
+		ShutdownTimeout:    cS.ShutdownTimeout,
+		APIAuth:            cS.APIAuth,
+		JWTKey:             cS.JWTKey,
+		TraceExporter:      cS.TraceExporter,
+		TraceExportPath:    cS.TraceExportPath,
+		TraceSampleRatio:   cS.TraceSampleRatio,
+		TraceFlushInterval: cS.TraceFlushInterval,
+	}
+	if cS.SessionSConns != nil {
+		cln.SessionSConns = make([]string, len(cS.SessionSConns))
+		for i, sConn := range cS.SessionSConns {
+			cln.SessionSConns[i] = sConn
+		}
+	}
+	if cS.StatSConns != nil {
+		cln.StatSConns = make([]string, len(cS.StatSConns))
+		for i, sConn := range cS.StatSConns {
+			cln.StatSConns[i] = sConn
+		}
 	}
+	if cS.StatQueueIDs != nil {
+		cln.StatQueueIDs = make([]string, len(cS.StatQueueIDs))
+		for i, sqID := range cS.StatQueueIDs {
+			cln.StatQueueIDs[i] = sqID
+		}
+	}
+	if cS.APIKeys != nil {
+		cln.APIKeys = make(map[string]string)
+		for key, role := range cS.APIKeys {
+			cln.APIKeys[key] = role
+		}
+	}
+	if cS.APIRoles != nil {
+		cln.APIRoles = make(map[string]*APIRole)
+		for name, role := range cS.APIRoles {
+			cln.APIRoles[name] = role.Clone()
+		}
+	}
+	return
 }
@@ -42,13 +42,40 @@ func TestCoreSloadFromJsonCfg(t *testing.T) {
 		"cores": {
 			"caps": 10,							// maximum concurrent request allowed ( 0 to disabled )
 			"caps_strategy": "*busy",			// strategy in case in case of concurrent requests reached	
-			"caps_stats_interval": "0"			// the interval we sample for caps stats ( 0 to disabled )
+			"caps_stats_interval": "0",			// the interval we sample for caps stats ( 0 to disabled )
+			"sessions_conns": ["*internal"],
+			"stats_conns": ["*internal", "*conn1"],
+			"stat_queue_ids": ["Stats1", "cgrates.net:Stats2"],
+			"api_auth": true,
+			"api_keys": {"key1": "reseller"},
+			"jwt_key": "secret",
+			"api_roles": {"reseller": {"allow": ["APIerSv1.Get*"], "tenants": ["cgrates.org"]}},
+			"trace_exporter": "*otlp",
+			"trace_export_path": "http://127.0.0.1:4318/v1/traces",
+			"trace_sample_ratio": 0.5,
+			"trace_flush_interval": "1s"
 		},
 }`
 	expected = CoreSCfg{
 		Caps:              10,
 		CapsStrategy:      utils.MetaBusy,
 		CapsStatsInterval: 0,
+		SessionSConns:     []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaSessionS)},
+		StatSConns:        []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaStatS), "*conn1"},
+		StatQueueIDs:      []string{"Stats1", "cgrates.net:Stats2"},
+		APIAuth:           true,
+		APIKeys:           map[string]string{"key1": "reseller"},
+		JWTKey:            "secret",
+		APIRoles: map[string]*APIRole{
+			"reseller": {
+				Allow:   []string{"APIerSv1.Get*"},
+				Tenants: []string{"cgrates.org"},
+			},
+		},
+		TraceExporter:      utils.MetaOTLP,
+		TraceExportPath:    "http://127.0.0.1:4318/v1/traces",
+		TraceSampleRatio:   0.5,
+		TraceFlushInterval: time.Second,
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromBytes([]byte(cfgJSONStr)); err != nil {
 		t.Error(err)
@@ -73,6 +100,12 @@ func TestCoreSloadFromJsonCfg(t *testing.T) {
 	if err = alS.loadFromJSONCfg(coresJSONCfg); err == nil || err.Error() != expErr {
 		t.Errorf("Expected error: %s,received: %v", expErr, err)
 	}
+	coresJSONCfg = &CoreSJsonCfg{
+		Trace_flush_interval: utils.StringPointer("1ss"),
+	}
+	if err = alS.loadFromJSONCfg(coresJSONCfg); err == nil || err.Error() != expErr {
+		t.Errorf("Expected error: %s,received: %v", expErr, err)
+	}
 }
 
 func TestCoreSAsMapInterface(t *testing.T) {
@@ -82,14 +115,40 @@ func TestCoreSAsMapInterface(t *testing.T) {
 			"caps": 0,							// maximum concurrent request allowed ( 0 to disabled )
 			"caps_strategy": "*busy",			// strategy in case in case of concurrent requests reached	
 			"caps_stats_interval": "0",			// the interval we sample for caps stats ( 0 to disabled )
-			"shutdown_timeout": "0"				// the interval we sample for caps stats ( 0 to disabled )
+			"shutdown_timeout": "0",				// the interval we sample for caps stats ( 0 to disabled )
+			"sessions_conns": ["*internal"],
+			"stats_conns": ["*internal", "*conn1"],
+			"stat_queue_ids": ["Stats1"],
+			"api_keys": {"key1": "reseller"},
+			"api_roles": {"reseller": {"deny": ["APIerSv1.Set*"]}},
+			"jwt_key": "secret",
+			"trace_exporter": "*file",
+			"trace_export_path": "/tmp/traces.json",
+			"trace_sample_ratio": 1
 		},
 }`
 	eMap := map[string]interface{}{
-		utils.CapsCfg:              0,
-		utils.CapsStrategyCfg:      utils.MetaBusy,
-		utils.CapsStatsIntervalCfg: "0",
-		utils.ShutdownTimeoutCfg:   "0",
+		utils.CapsCfg:               0,
+		utils.CapsStrategyCfg:       utils.MetaBusy,
+		utils.CapsStatsIntervalCfg:  "0",
+		utils.ShutdownTimeoutCfg:    "0",
+		utils.SessionSConnsCfg:      []string{utils.MetaInternal},
+		utils.StatSConnsCfg:         []string{utils.MetaInternal, "*conn1"},
+		utils.StatQueueIDsCfg:       []string{"Stats1"},
+		utils.APIAuthCfg:            false,
+		utils.APIKeysCfg:            map[string]interface{}{utils.MaskSecret("key1"): "reseller"},
+		utils.JWTKeyCfg:             utils.MaskSecret("secret"),
+		utils.TraceExporterCfg:      utils.MetaFile,
+		utils.TraceExportPathCfg:    "/tmp/traces.json",
+		utils.TraceSampleRatioCfg:   1.,
+		utils.TraceFlushIntervalCfg: "0",
+		utils.APIRolesCfg: map[string]interface{}{
+			"reseller": map[string]interface{}{
+				utils.AllowCfg:   []string{},
+				utils.DenyCfg:    []string{"APIerSv1.Set*"},
+				utils.TenantsCfg: []string{},
+			},
+		},
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromBytes([]byte(cfgJSONStr)); err != nil {
 		t.Error(err)
@@ -102,6 +161,15 @@ func TestCoreSAsMapInterface(t *testing.T) {
 	}
 	eMap[utils.CapsStatsIntervalCfg] = "1s"
 	eMap[utils.ShutdownTimeoutCfg] = "1s"
+	delete(eMap, utils.SessionSConnsCfg)
+	delete(eMap, utils.StatSConnsCfg)
+	delete(eMap, utils.StatQueueIDsCfg)
+	delete(eMap, utils.APIKeysCfg)
+	delete(eMap, utils.APIRolesCfg)
+	eMap[utils.JWTKeyCfg] = utils.EmptyString
+	eMap[utils.TraceExporterCfg] = utils.EmptyString
+	eMap[utils.TraceExportPathCfg] = utils.EmptyString
+	eMap[utils.TraceSampleRatioCfg] = 0.
 	alS = CoreSCfg{
 		Caps:              0,
 		CapsStatsInterval: time.Second,
@@ -119,6 +187,21 @@ func TestCoreSCfgClone(t *testing.T) {
 		CapsStatsInterval: time.Second,
 		ShutdownTimeout:   time.Second,
 		CapsStrategy:      utils.MetaBusy,
+		SessionSConns:     []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaSessionS)},
+		StatSConns:        []string{"*conn1"},
+		StatQueueIDs:      []string{"Stats1"},
+		APIAuth:           true,
+		APIKeys:           map[string]string{"key1": "reseller"},
+		JWTKey:            "secret",
+		TraceExporter:     utils.MetaOTLP,
+		TraceSampleRatio:  0.5,
+		APIRoles: map[string]*APIRole{
+			"reseller": {
+				Allow:   []string{"APIerSv1.Get*"},
+				Deny:    []string{},
+				Tenants: []string{"cgrates.org"},
+			},
+		},
 	}
 	rcv := cS.Clone()
 	if !reflect.DeepEqual(cS, rcv) {
@@ -127,4 +210,10 @@ func TestCoreSCfgClone(t *testing.T) {
 	if rcv.Caps = 1; cS.Caps != 0 {
 		t.Errorf("Expected clone to not modify the cloned")
 	}
+	if rcv.StatQueueIDs[0] = "Stats2"; cS.StatQueueIDs[0] != "Stats1" {
+		t.Errorf("Expected clone to not modify the cloned")
+	}
+	if rcv.APIRoles["reseller"].Tenants[0] = "cgrates.net"; cS.APIRoles["reseller"].Tenants[0] != "cgrates.org" {
+		t.Errorf("Expected clone to not modify the cloned")
+	}
 }
@@ -28,6 +28,9 @@ import (
 type ERsCfg struct {
 	Enabled       bool
 	SessionSConns []string
+	EEsConns      []string      // connections towards EEs, used to export the duplicated events
+	DedupStore    string        // where the deduplication IDs are kept <*internal|*datadb>
+	DedupTTL      time.Duration // interval to remember the processed event IDs
 	Readers       []*EventReaderCfg
 }
 
@@ -48,6 +51,23 @@ func (erS *ERsCfg) loadFromJSONCfg(jsnCfg *ERsJsonCfg, msgTemplates map[string][
 			}
 		}
 	}
+	if jsnCfg.Ees_conns != nil {
+		erS.EEsConns = make([]string, len(*jsnCfg.Ees_conns))
+		for i, fID := range *jsnCfg.Ees_conns {
+			erS.EEsConns[i] = fID
+			if fID == utils.MetaInternal {
+				erS.EEsConns[i] = utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs)
+			}
+		}
+	}
+	if jsnCfg.Dedup_store != nil {
+		erS.DedupStore = *jsnCfg.Dedup_store
+	}
+	if jsnCfg.Dedup_ttl != nil {
+		if erS.DedupTTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Dedup_ttl); err != nil {
+			return
+		}
+	}
 	return erS.appendERsReaders(jsnCfg.Readers, msgTemplates, sep, dfltRdrCfg)
 }
 
@@ -88,11 +108,19 @@ func (erS *ERsCfg) Clone() (cln *ERsCfg) {
 	cln = &ERsCfg{
 		Enabled:       erS.Enabled,
 		SessionSConns: make([]string, len(erS.SessionSConns)),
+		DedupStore:    erS.DedupStore,
+		DedupTTL:      erS.DedupTTL,
 		Readers:       make([]*EventReaderCfg, len(erS.Readers)),
 	}
 	for idx, sConn := range erS.SessionSConns {
 		cln.SessionSConns[idx] = sConn
 	}
+	if erS.EEsConns != nil {
+		cln.EEsConns = make([]string, len(erS.EEsConns))
+		for idx, eConn := range erS.EEsConns {
+			cln.EEsConns[idx] = eConn
+		}
+	}
 	for idx, rdr := range erS.Readers {
 		cln.Readers[idx] = rdr.Clone()
 	}
@@ -102,7 +130,12 @@ func (erS *ERsCfg) Clone() (cln *ERsCfg) {
 // AsMapInterface returns the config as a map[string]interface{}
 func (erS *ERsCfg) AsMapInterface(separator string) (initialMP map[string]interface{}) {
 	initialMP = map[string]interface{}{
-		utils.EnabledCfg: erS.Enabled,
+		utils.EnabledCfg:    erS.Enabled,
+		utils.DedupStoreCfg: erS.DedupStore,
+		utils.DedupTTLCfg:   "0",
+	}
+	if erS.DedupTTL != 0 {
+		initialMP[utils.DedupTTLCfg] = erS.DedupTTL.String()
 	}
 	if erS.SessionSConns != nil {
 		sessionSConns := make([]string, len(erS.SessionSConns))
@@ -114,6 +147,16 @@ func (erS *ERsCfg) AsMapInterface(separator string) (initialMP map[string]interf
 		}
 		initialMP[utils.SessionSConnsCfg] = sessionSConns
 	}
+	if erS.EEsConns != nil {
+		eesConns := make([]string, len(erS.EEsConns))
+		for i, item := range erS.EEsConns {
+			eesConns[i] = item
+			if item == utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs) {
+				eesConns[i] = utils.MetaInternal
+			}
+		}
+		initialMP[utils.EEsConnsCfg] = eesConns
+	}
 	if erS.Readers != nil {
 		readers := make([]map[string]interface{}, len(erS.Readers))
 		for i, item := range erS.Readers {
@@ -146,6 +189,8 @@ type EventReaderCfg struct {
 	PartialCacheExpiryAction string
 	Fields                   []*FCTemplate
 	CacheDumpFields          []*FCTemplate
+	DedupID                  RSRParsers // builds the event ID used for deduplication, empty to disable it
+	DedupEEIDs               []string   // exporters receiving the duplicated events
 }
 
 func (er *EventReaderCfg) loadFromJSONCfg(jsnCfg *EventReaderJsonCfg, msgTemplates map[string][]*FCTemplate, sep string) (err error) {
@@ -232,6 +277,17 @@ func (er *EventReaderCfg) loadFromJSONCfg(jsnCfg *EventReaderJsonCfg, msgTemplat
 			er.CacheDumpFields = tpls
 		}
 	}
+	if jsnCfg.Dedup_id != nil {
+		if er.DedupID, err = NewRSRParsers(*jsnCfg.Dedup_id, sep); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Dedup_ee_ids != nil {
+		er.DedupEEIDs = make([]string, len(*jsnCfg.Dedup_ee_ids))
+		for i, eeID := range *jsnCfg.Dedup_ee_ids {
+			er.DedupEEIDs[i] = eeID
+		}
+	}
 	if jsnCfg.Opts != nil {
 		for k, v := range jsnCfg.Opts {
 			er.Opts[k] = v
@@ -258,8 +314,15 @@ func (er EventReaderCfg) Clone() (cln *EventReaderCfg) {
 		FailedCallsPrefix:        er.FailedCallsPrefix,
 		PartialCacheExpiryAction: er.PartialCacheExpiryAction,
 		PartialRecordCache:       er.PartialRecordCache,
+		DedupID:                  er.DedupID.Clone(),
 		Opts:                     make(map[string]interface{}),
 	}
+	if er.DedupEEIDs != nil {
+		cln.DedupEEIDs = make([]string, len(er.DedupEEIDs))
+		for idx, eeID := range er.DedupEEIDs {
+			cln.DedupEEIDs[idx] = eeID
+		}
+	}
 	if er.Filters != nil {
 		cln.Filters = make([]string, len(er.Filters))
 		for idx, val := range er.Filters {
@@ -305,6 +368,8 @@ func (er *EventReaderCfg) AsMapInterface(separator string) (initialMP map[string
 		utils.OptsCfg:                     er.Opts,
 		utils.PartialRecordCacheCfg:       "0",
 		utils.RunDelayCfg:                 "0",
+		utils.DedupIDCfg:                  er.DedupID.GetRule(separator),
+		utils.DedupEEIDsCfg:               er.DedupEEIDs,
 	}
 	if flags := er.Flags.SliceFlags(); flags != nil {
 		initialMP[utils.FlagsCfg] = flags
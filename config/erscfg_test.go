@@ -63,6 +63,9 @@ func TestERSClone(t *testing.T) {
 	expectedERsCfg := &ERsCfg{
 		Enabled:       true,
 		SessionSConns: []string{"*internal:*sessions"},
+		EEsConns:      []string{},
+		DedupStore:    utils.MetaInternal,
+		DedupTTL:      time.Hour,
 		Readers: []*EventReaderCfg{
 			{
 				ID:               utils.MetaDefault,
@@ -103,6 +106,7 @@ func TestERSClone(t *testing.T) {
 						Value: NewRSRParsersMustCompile("~*req.13", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 			{
@@ -132,6 +136,7 @@ func TestERSClone(t *testing.T) {
 					{Tag: utils.ToR, Path: utils.MetaCgreq + utils.NestingSep + utils.ToR, Type: utils.MetaVariable,
 						Value: NewRSRParsersMustCompile("~*req.2", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
+				DedupEEIDs: []string{},
 				Opts: map[string]interface{}{
 					utils.MetaDefault: "randomVal",
 				},
@@ -214,6 +219,9 @@ func TestERSLoadFromjsonCfg(t *testing.T) {
 	expectedERsCfg := &ERsCfg{
 		Enabled:       true,
 		SessionSConns: []string{"conn1", "conn3"},
+		EEsConns:      []string{},
+		DedupStore:    utils.MetaInternal,
+		DedupTTL:      time.Hour,
 		Readers: []*EventReaderCfg{
 			{
 				ID:               utils.MetaDefault,
@@ -254,6 +262,7 @@ func TestERSLoadFromjsonCfg(t *testing.T) {
 						Value: NewRSRParsersMustCompile("~*req.13", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 			{
@@ -295,6 +304,7 @@ func TestERSLoadFromjsonCfg(t *testing.T) {
 						Value: NewRSRParsersMustCompile("~*req.13", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 		},
@@ -435,6 +445,9 @@ func TestERSloadFromJsonCase3(t *testing.T) {
 	expectedERsCfg := &ERsCfg{
 		Enabled:       true,
 		SessionSConns: []string{"*conn1"},
+		EEsConns:      []string{},
+		DedupStore:    utils.MetaInternal,
+		DedupTTL:      time.Hour,
 		Readers: []*EventReaderCfg{
 			{
 				ID:               utils.MetaDefault,
@@ -475,6 +488,7 @@ func TestERSloadFromJsonCase3(t *testing.T) {
 						Value: NewRSRParsersMustCompile("~*req.13", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 			{
@@ -503,6 +517,7 @@ func TestERSloadFromJsonCase3(t *testing.T) {
 					},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 		},
@@ -566,6 +581,9 @@ func TestERSloadFromJsonCase4(t *testing.T) {
 	expectedERsCfg := &ERsCfg{
 		Enabled:       true,
 		SessionSConns: []string{"*conn1"},
+		EEsConns:      []string{},
+		DedupStore:    utils.MetaInternal,
+		DedupTTL:      time.Hour,
 		Readers: []*EventReaderCfg{
 			{
 				ID:               utils.MetaDefault,
@@ -606,6 +624,7 @@ func TestERSloadFromJsonCase4(t *testing.T) {
 						Value: NewRSRParsersMustCompile("~*req.13", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 			{
@@ -634,7 +653,8 @@ func TestERSloadFromJsonCase4(t *testing.T) {
 						Value: NewRSRParsersMustCompile("~*req.OrderID", utils.InfieldSep),
 					},
 				},
-				Opts: make(map[string]interface{}),
+				DedupEEIDs: []string{},
+				Opts:       make(map[string]interface{}),
 			},
 		},
 	}
@@ -685,6 +705,9 @@ func TestEventReaderSameID(t *testing.T) {
 	expectedERsCfg := &ERsCfg{
 		Enabled:       true,
 		SessionSConns: []string{"conn1"},
+		EEsConns:      []string{},
+		DedupStore:    utils.MetaInternal,
+		DedupTTL:      time.Hour,
 		Readers: []*EventReaderCfg{
 			{
 				ID:               utils.MetaDefault,
@@ -725,6 +748,7 @@ func TestEventReaderSameID(t *testing.T) {
 						Value: NewRSRParsersMustCompile("~*req.13", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 			{
@@ -747,6 +771,7 @@ func TestEventReaderSameID(t *testing.T) {
 						Value: NewRSRParsersMustCompile("CustomValue2", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 		},
@@ -817,6 +842,9 @@ func TestERsCfgAsMapInterfaceCase1(t *testing.T) {
 	eMap := map[string]interface{}{
 		utils.EnabledCfg:       true,
 		utils.SessionSConnsCfg: []string{"conn1", "conn3"},
+		utils.EEsConnsCfg:      []string{},
+		utils.DedupStoreCfg:    utils.MetaInternal,
+		utils.DedupTTLCfg:      "1h0m0s",
 		utils.ReadersCfg: []map[string]interface{}{
 			{
 				utils.FiltersCfg:                  []string{},
@@ -850,7 +878,9 @@ func TestERsCfgAsMapInterfaceCase1(t *testing.T) {
 					{utils.MandatoryCfg: true, utils.PathCfg: "*cgreq.AnswerTime", utils.TagCfg: "AnswerTime", utils.TypeCfg: "*variable", utils.ValueCfg: "~*req.12"},
 					{utils.MandatoryCfg: true, utils.PathCfg: "*cgreq.Usage", utils.TagCfg: "Usage", utils.TypeCfg: "*variable", utils.ValueCfg: "~*req.13"},
 				},
-				utils.OptsCfg: make(map[string]interface{}),
+				utils.DedupIDCfg:    "",
+				utils.DedupEEIDsCfg: []string{},
+				utils.OptsCfg:       make(map[string]interface{}),
 			},
 			{
 				utils.CacheDumpFieldsCfg:    []map[string]interface{}{},
@@ -884,6 +914,8 @@ func TestERsCfgAsMapInterfaceCase1(t *testing.T) {
 				utils.TenantCfg:                   "~*req.Destination1",
 				utils.TimezoneCfg:                 "",
 				utils.XMLRootPathCfg:              []string{""},
+				utils.DedupIDCfg:                  "",
+				utils.DedupEEIDsCfg:               []string{},
 				utils.OptsCfg:                     make(map[string]interface{}),
 			},
 		},
@@ -921,6 +953,9 @@ func TestERSCfgAsMapInterfaceCase2(t *testing.T) {
 	eMap := map[string]interface{}{
 		utils.EnabledCfg:       true,
 		utils.SessionSConnsCfg: []string{"conn1", "conn3"},
+		utils.EEsConnsCfg:      []string{},
+		utils.DedupStoreCfg:    utils.MetaInternal,
+		utils.DedupTTLCfg:      "1h0m0s",
 		utils.ReadersCfg: []map[string]interface{}{
 			{
 				utils.FiltersCfg:                  []string{},
@@ -954,7 +989,9 @@ func TestERSCfgAsMapInterfaceCase2(t *testing.T) {
 					{utils.MandatoryCfg: true, utils.PathCfg: "*cgreq.AnswerTime", utils.TagCfg: "AnswerTime", utils.TypeCfg: "*variable", utils.ValueCfg: "~*req.12"},
 					{utils.MandatoryCfg: true, utils.PathCfg: "*cgreq.Usage", utils.TagCfg: "Usage", utils.TypeCfg: "*variable", utils.ValueCfg: "~*req.13"},
 				},
-				utils.OptsCfg: make(map[string]interface{}),
+				utils.DedupIDCfg:    "",
+				utils.DedupEEIDsCfg: []string{},
+				utils.OptsCfg:       make(map[string]interface{}),
 			},
 			{
 				utils.CacheDumpFieldsCfg: []map[string]interface{}{
@@ -990,6 +1027,8 @@ func TestERSCfgAsMapInterfaceCase2(t *testing.T) {
 				utils.TenantCfg:                   "~*req.Destination1",
 				utils.TimezoneCfg:                 "",
 				utils.XMLRootPathCfg:              []string{""},
+				utils.DedupIDCfg:                  "",
+				utils.DedupEEIDsCfg:               []string{},
 				utils.OptsCfg:                     make(map[string]interface{}),
 			},
 		},
@@ -1040,6 +1079,9 @@ func TestERsloadFromJsonCfg(t *testing.T) {
 	expectedERsCfg := &ERsCfg{
 		Enabled:       true,
 		SessionSConns: []string{"*conn1"},
+		EEsConns:      []string{},
+		DedupStore:    utils.MetaInternal,
+		DedupTTL:      time.Hour,
 		Readers: []*EventReaderCfg{
 			{
 				ID:               utils.MetaDefault,
@@ -1080,6 +1122,7 @@ func TestERsloadFromJsonCfg(t *testing.T) {
 						Value: NewRSRParsersMustCompile("~*req.13", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 			{
@@ -1110,6 +1153,7 @@ func TestERsloadFromJsonCfg(t *testing.T) {
 						Value: NewRSRParsersMustCompile("CustomValue2", utils.InfieldSep), Mandatory: true, Layout: time.RFC3339},
 				},
 				CacheDumpFields: make([]*FCTemplate, 0),
+				DedupEEIDs:      []string{},
 				Opts:            make(map[string]interface{}),
 			},
 		},
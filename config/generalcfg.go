@@ -46,6 +46,8 @@ type GeneralCfg struct {
 	ConnectTimeout   time.Duration // timeout for RPC connection attempts
 	ReplyTimeout     time.Duration // timeout replies if not reaching back
 	LockingTimeout   time.Duration // locking mechanism timeout to avoid deadlocks
+	LockingBackend   string        // backend sharing the locks with other engines <*internal|*redis>
+	LockingLease     time.Duration // lease of the distributed locks, renewed while held
 	MinCallDuration  time.Duration
 	MaxCallDuration  time.Duration
 	DigestSeparator  string //
@@ -135,6 +137,14 @@ func (gencfg *GeneralCfg) loadFromJSONCfg(jsnGeneralCfg *GeneralJsonCfg) (err er
 			return err
 		}
 	}
+	if jsnGeneralCfg.Locking_backend != nil {
+		gencfg.LockingBackend = *jsnGeneralCfg.Locking_backend
+	}
+	if jsnGeneralCfg.Locking_lease != nil {
+		if gencfg.LockingLease, err = utils.ParseDurationWithNanosecs(*jsnGeneralCfg.Locking_lease); err != nil {
+			return err
+		}
+	}
 	if jsnGeneralCfg.Digest_separator != nil {
 		gencfg.DigestSeparator = *jsnGeneralCfg.Digest_separator
 	}
@@ -173,7 +183,9 @@ func (gencfg *GeneralCfg) AsMapInterface() (initialMP map[string]interface{}) {
 		utils.DigestEqualCfg:      gencfg.DigestEqual,
 		utils.RSRSepCfg:           gencfg.RSRSep,
 		utils.MaxParallelConnsCfg: gencfg.MaxParallelConns,
+		utils.LockingBackendCfg:   gencfg.LockingBackend,
 		utils.LockingTimeoutCfg:   "0",
+		utils.LockingLeaseCfg:     "0",
 		utils.FailedPostsTTLCfg:   "0",
 		utils.ConnectTimeoutCfg:   "0",
 		utils.ReplyTimeoutCfg:     "0",
@@ -185,6 +197,10 @@ func (gencfg *GeneralCfg) AsMapInterface() (initialMP map[string]interface{}) {
 		initialMP[utils.LockingTimeoutCfg] = gencfg.LockingTimeout.String()
 	}
 
+	if gencfg.LockingLease != 0 {
+		initialMP[utils.LockingLeaseCfg] = gencfg.LockingLease.String()
+	}
+
 	if gencfg.FailedPostsTTL != 0 {
 		initialMP[utils.FailedPostsTTLCfg] = gencfg.FailedPostsTTL.String()
 	}
@@ -228,6 +244,8 @@ func (gencfg GeneralCfg) Clone() *GeneralCfg {
 		ConnectTimeout:   gencfg.ConnectTimeout,
 		ReplyTimeout:     gencfg.ReplyTimeout,
 		LockingTimeout:   gencfg.LockingTimeout,
+		LockingBackend:   gencfg.LockingBackend,
+		LockingLease:     gencfg.LockingLease,
 		MinCallDuration:  gencfg.MinCallDuration,
 		MaxCallDuration:  gencfg.MaxCallDuration,
 		DigestSeparator:  gencfg.DigestSeparator,
@@ -46,6 +46,8 @@ func TestGeneralCfgloadFromJsonCfg(t *testing.T) {
 		Digest_separator:     utils.StringPointer(","),
 		Digest_equal:         utils.StringPointer(":"),
 		Failed_posts_ttl:     utils.StringPointer("2"),
+		Locking_backend:      utils.StringPointer(utils.MetaRedis),
+		Locking_lease:        utils.StringPointer("5s"),
 	}
 
 	expected := &GeneralCfg{
@@ -73,6 +75,8 @@ func TestGeneralCfgloadFromJsonCfg(t *testing.T) {
 		RSRSep:           ";",
 		DefaultCaching:   utils.MetaReload,
 		FailedPostsTTL:   2,
+		LockingBackend:   utils.MetaRedis,
+		LockingLease:     5 * time.Second,
 	}
 	jsnCfg := NewDefaultCGRConfig()
 	if err = jsnCfg.generalCfg.loadFromJSONCfg(cfgJSON); err != nil {
@@ -132,6 +136,14 @@ func TestGeneralParseDurationCfgloadFromJsonCfg(t *testing.T) {
 		t.Errorf("Expected %+v, received %v", expected, err)
 	}
 
+	cfgJSON6 := &GeneralJsonCfg{
+		Locking_lease: utils.StringPointer("1ss"),
+	}
+	jsonCfg = NewDefaultCGRConfig()
+	if err = jsonCfg.generalCfg.loadFromJSONCfg(cfgJSON6); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %v", expected, err)
+	}
+
 }
 
 func TestGeneralCfgAsMapInterface(t *testing.T) {
@@ -184,6 +196,8 @@ func TestGeneralCfgAsMapInterface(t *testing.T) {
 		utils.ConnectTimeoutCfg:   "1s",
 		utils.ReplyTimeoutCfg:     "2s",
 		utils.LockingTimeoutCfg:   "1s",
+		utils.LockingBackendCfg:   utils.MetaInternal,
+		utils.LockingLeaseCfg:     "10s",
 		utils.DigestSeparatorCfg:  ",",
 		utils.DigestEqualCfg:      ":",
 		utils.RSRSepCfg:           ";",
@@ -228,6 +242,8 @@ func TestGeneralCfgAsMapInterface1(t *testing.T) {
 		utils.ConnectTimeoutCfg:   "0",
 		utils.ReplyTimeoutCfg:     "0",
 		utils.LockingTimeoutCfg:   "0",
+		utils.LockingBackendCfg:   utils.MetaInternal,
+		utils.LockingLeaseCfg:     "10s",
 		utils.MinCallDurationCfg:  "1s",
 		utils.MaxCallDurationCfg:  "0",
 		utils.DigestSeparatorCfg:  ",",
@@ -268,6 +284,8 @@ func TestGeneralCfgClone(t *testing.T) {
 		RSRSep:           ";",
 		DefaultCaching:   utils.MetaReload,
 		FailedPostsTTL:   2,
+		LockingBackend:   utils.MetaRedis,
+		LockingLease:     5 * time.Second,
 	}
 	rcv := ban.Clone()
 	if !reflect.DeepEqual(ban, rcv) {
@@ -29,6 +29,8 @@ type HTTPCfg struct {
 	HTTPWSURL               string            // WebSocket relative URL ("" to disable)
 	HTTPFreeswitchCDRsURL   string            // Freeswitch CDRS relative URL ("" to disable)
 	HTTPCDRsURL             string            // CDRS relative URL ("" to disable)
+	HTTPMetricsURL          string            // Prometheus metrics relative URL ("" to disable)
+	HTTPEventsURL           string            // live events stream relative URL ("" to disable)
 	HTTPUseBasicAuth        bool              // Use basic auth for HTTP API
 	HTTPAuthUsers           map[string]string // Basic auth user:password map (base64 passwords)
 	ClientOpts              map[string]interface{}
@@ -54,6 +56,12 @@ func (httpcfg *HTTPCfg) loadFromJSONCfg(jsnHTTPCfg *HTTPJsonCfg) (err error) {
 	if jsnHTTPCfg.Http_Cdrs != nil {
 		httpcfg.HTTPCDRsURL = *jsnHTTPCfg.Http_Cdrs
 	}
+	if jsnHTTPCfg.Metrics_url != nil {
+		httpcfg.HTTPMetricsURL = *jsnHTTPCfg.Metrics_url
+	}
+	if jsnHTTPCfg.Events_url != nil {
+		httpcfg.HTTPEventsURL = *jsnHTTPCfg.Events_url
+	}
 	if jsnHTTPCfg.Use_basic_auth != nil {
 		httpcfg.HTTPUseBasicAuth = *jsnHTTPCfg.Use_basic_auth
 	}
@@ -76,6 +84,8 @@ func (httpcfg *HTTPCfg) AsMapInterface() (initialMP map[string]interface{}) {
 		utils.HTTPWSURLCfg:               httpcfg.HTTPWSURL,
 		utils.HTTPFreeswitchCDRsURLCfg:   httpcfg.HTTPFreeswitchCDRsURL,
 		utils.HTTPCDRsURLCfg:             httpcfg.HTTPCDRsURL,
+		utils.HTTPMetricsURLCfg:          httpcfg.HTTPMetricsURL,
+		utils.HTTPEventsURLCfg:           httpcfg.HTTPEventsURL,
 		utils.HTTPUseBasicAuthCfg:        httpcfg.HTTPUseBasicAuth,
 		utils.HTTPAuthUsersCfg:           httpcfg.HTTPAuthUsers,
 		utils.HTTPClientOptsCfg:          httpcfg.ClientOpts,
@@ -91,6 +101,8 @@ func (httpcfg HTTPCfg) Clone() (cln *HTTPCfg) {
 		HTTPWSURL:               httpcfg.HTTPWSURL,
 		HTTPFreeswitchCDRsURL:   httpcfg.HTTPFreeswitchCDRsURL,
 		HTTPCDRsURL:             httpcfg.HTTPCDRsURL,
+		HTTPMetricsURL:          httpcfg.HTTPMetricsURL,
+		HTTPEventsURL:           httpcfg.HTTPEventsURL,
 		HTTPUseBasicAuth:        httpcfg.HTTPUseBasicAuth,
 		HTTPAuthUsers:           make(map[string]string),
 		ClientOpts:              make(map[string]interface{}),
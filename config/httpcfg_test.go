@@ -31,6 +31,8 @@ func TestHTTPCfgloadFromJsonCfg(t *testing.T) {
 		Dispatchers_registrar_url: utils.StringPointer("/randomUrl"),
 		Freeswitch_cdrs_url:       utils.StringPointer("/freeswitch_json"),
 		Http_Cdrs:                 utils.StringPointer("/cdr_http"),
+		Metrics_url:               utils.StringPointer("/prometheus"),
+		Events_url:                utils.StringPointer("/events"),
 		Use_basic_auth:            utils.BoolPointer(false),
 		Auth_users:                utils.MapStringStringPointer(map[string]string{}),
 	}
@@ -40,6 +42,8 @@ func TestHTTPCfgloadFromJsonCfg(t *testing.T) {
 		DispatchersRegistrarURL: "/randomUrl",
 		HTTPFreeswitchCDRsURL:   "/freeswitch_json",
 		HTTPCDRsURL:             "/cdr_http",
+		HTTPMetricsURL:          "/prometheus",
+		HTTPEventsURL:           "/events",
 		HTTPUseBasicAuth:        false,
 		HTTPAuthUsers:           map[string]string{},
 		ClientOpts: map[string]interface{}{
@@ -77,6 +81,8 @@ func TestHTTPCfgAsMapInterface(t *testing.T) {
 		utils.HTTPWSURLCfg:               "/ws",
 		utils.HTTPFreeswitchCDRsURLCfg:   "/freeswitch_json",
 		utils.HTTPCDRsURLCfg:             "/cdr_http",
+		utils.HTTPMetricsURLCfg:          "",
+		utils.HTTPEventsURLCfg:           "",
 		utils.HTTPUseBasicAuthCfg:        false,
 		utils.HTTPAuthUsersCfg:           map[string]string{},
 		utils.HTTPClientOptsCfg: map[string]interface{}{
@@ -118,6 +124,8 @@ func TestHTTPCfgAsMapInterface1(t *testing.T) {
 		utils.HTTPWSURLCfg:               "",
 		utils.HTTPFreeswitchCDRsURLCfg:   "/freeswitch_json",
 		utils.HTTPCDRsURLCfg:             "/cdr_http",
+		utils.HTTPMetricsURLCfg:          "",
+		utils.HTTPEventsURLCfg:           "",
 		utils.HTTPUseBasicAuthCfg:        true,
 		utils.HTTPAuthUsersCfg: map[string]string{
 			"user1": "authenticated",
@@ -154,6 +162,8 @@ func TestHTTPCfgClone(t *testing.T) {
 		DispatchersRegistrarURL: "/randomUrl",
 		HTTPFreeswitchCDRsURL:   "/freeswitch_json",
 		HTTPCDRsURL:             "/cdr_http",
+		HTTPMetricsURL:          "/metrics",
+		HTTPEventsURL:           "/events",
 		HTTPUseBasicAuth:        false,
 		HTTPAuthUsers: map[string]string{
 			"user": "pass",
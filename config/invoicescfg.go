@@ -0,0 +1,111 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import "github.com/cgrates/cgrates/utils"
+
+// InvoiceSCfg is the configuration of InvoiceS
+type InvoiceSCfg struct {
+	Enabled      bool
+	RunIDs       []string // the CDR runs billed on the invoices
+	NumberPrefix string   // prefix of the invoice IDs, followed by the invoice number
+	EEsConns     []string // connections towards EEs, rendering the invoices
+	ExporterIDs  []string // exporters rendering the generated invoices
+}
+
+func (iCfg *InvoiceSCfg) loadFromJSONCfg(jsnCfg *InvoiceSJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Enabled != nil {
+		iCfg.Enabled = *jsnCfg.Enabled
+	}
+	if jsnCfg.Run_ids != nil {
+		iCfg.RunIDs = make([]string, len(*jsnCfg.Run_ids))
+		copy(iCfg.RunIDs, *jsnCfg.Run_ids)
+	}
+	if jsnCfg.Number_prefix != nil {
+		iCfg.NumberPrefix = *jsnCfg.Number_prefix
+	}
+	if jsnCfg.Ees_conns != nil {
+		iCfg.EEsConns = make([]string, len(*jsnCfg.Ees_conns))
+		for idx, connID := range *jsnCfg.Ees_conns {
+			// if we have the connection internal we change the name so we can have internal rpc for each subsystem
+			iCfg.EEsConns[idx] = connID
+			if connID == utils.MetaInternal {
+				iCfg.EEsConns[idx] = utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs)
+			}
+		}
+	}
+	if jsnCfg.Exporter_ids != nil {
+		iCfg.ExporterIDs = make([]string, len(*jsnCfg.Exporter_ids))
+		copy(iCfg.ExporterIDs, *jsnCfg.Exporter_ids)
+	}
+	return
+}
+
+// AsMapInterface returns the config as a map[string]interface{}
+func (iCfg *InvoiceSCfg) AsMapInterface() (initialMP map[string]interface{}) {
+	initialMP = map[string]interface{}{
+		utils.EnabledCfg:      iCfg.Enabled,
+		utils.NumberPrefixCfg: iCfg.NumberPrefix,
+	}
+	if iCfg.RunIDs != nil {
+		runIDs := make([]string, len(iCfg.RunIDs))
+		copy(runIDs, iCfg.RunIDs)
+		initialMP[utils.RunIDsCfg] = runIDs
+	}
+	if iCfg.EEsConns != nil {
+		eesConns := make([]string, len(iCfg.EEsConns))
+		for i, item := range iCfg.EEsConns {
+			eesConns[i] = item
+			if item == utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs) {
+				eesConns[i] = utils.MetaInternal
+			}
+		}
+		initialMP[utils.EEsConnsCfg] = eesConns
+	}
+	if iCfg.ExporterIDs != nil {
+		exporterIDs := make([]string, len(iCfg.ExporterIDs))
+		copy(exporterIDs, iCfg.ExporterIDs)
+		initialMP[utils.ExporterIDsCfg] = exporterIDs
+	}
+	return
+}
+
+// Clone returns a deep copy of InvoiceSCfg
+func (iCfg InvoiceSCfg) Clone() (cln *InvoiceSCfg) {
+	cln = &InvoiceSCfg{
+		Enabled:      iCfg.Enabled,
+		NumberPrefix: iCfg.NumberPrefix,
+	}
+	if iCfg.RunIDs != nil {
+		cln.RunIDs = make([]string, len(iCfg.RunIDs))
+		copy(cln.RunIDs, iCfg.RunIDs)
+	}
+	if iCfg.EEsConns != nil {
+		cln.EEsConns = make([]string, len(iCfg.EEsConns))
+		copy(cln.EEsConns, iCfg.EEsConns)
+	}
+	if iCfg.ExporterIDs != nil {
+		cln.ExporterIDs = make([]string, len(iCfg.ExporterIDs))
+		copy(cln.ExporterIDs, iCfg.ExporterIDs)
+	}
+	return
+}
@@ -0,0 +1,97 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestInvoiceSCfgLoadFromJSONCfg(t *testing.T) {
+	jsonCfg := &InvoiceSJsonCfg{
+		Enabled:       utils.BoolPointer(true),
+		Run_ids:       &[]string{utils.MetaDefault, "*customer"},
+		Number_prefix: utils.StringPointer("INV"),
+		Ees_conns:     &[]string{utils.MetaInternal, "*conn1"},
+		Exporter_ids:  &[]string{"INVOICES_HTML"},
+	}
+	expected := &InvoiceSCfg{
+		Enabled:      true,
+		RunIDs:       []string{utils.MetaDefault, "*customer"},
+		NumberPrefix: "INV",
+		EEsConns:     []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaEEs), "*conn1"},
+		ExporterIDs:  []string{"INVOICES_HTML"},
+	}
+	jsnCfg := NewDefaultCGRConfig()
+	if err = jsnCfg.invoiceSCfg.loadFromJSONCfg(jsonCfg); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, jsnCfg.invoiceSCfg) {
+		t.Errorf("\nExpecting <%+v>,\n Received <%+v>", utils.ToJSON(expected), utils.ToJSON(jsnCfg.invoiceSCfg))
+	}
+}
+
+func TestInvoiceSCfgAsMapInterface(t *testing.T) {
+	cfgJSONStr := `{
+"invoices": {
+	"enabled": true,
+	"run_ids": ["*default"],
+	"number_prefix": "INV",
+	"ees_conns": ["*internal"],
+	"exporter_ids": ["INVOICES_HTML"],
+},
+}`
+
+	eMap := map[string]interface{}{
+		utils.EnabledCfg:      true,
+		utils.RunIDsCfg:       []string{utils.MetaDefault},
+		utils.NumberPrefixCfg: "INV",
+		utils.EEsConnsCfg:     []string{utils.MetaInternal},
+		utils.ExporterIDsCfg:  []string{"INVOICES_HTML"},
+	}
+	if cgrCfg, err := NewCGRConfigFromJSONStringWithDefaults(cfgJSONStr); err != nil {
+		t.Error(err)
+	} else if rcv := cgrCfg.invoiceSCfg.AsMapInterface(); !reflect.DeepEqual(eMap, rcv) {
+		t.Errorf("Expected: %+v\n Received: %+v", utils.ToJSON(eMap), utils.ToJSON(rcv))
+	}
+}
+
+func TestInvoiceSCfgClone(t *testing.T) {
+	iCfg := &InvoiceSCfg{
+		Enabled:      true,
+		RunIDs:       []string{utils.MetaDefault},
+		NumberPrefix: "INV",
+		EEsConns:     []string{"*conn1"},
+		ExporterIDs:  []string{"INVOICES_HTML"},
+	}
+	rcv := iCfg.Clone()
+	if !reflect.DeepEqual(iCfg, rcv) {
+		t.Errorf("\nExpected: %+v\nReceived: %+v", utils.ToJSON(iCfg), utils.ToJSON(rcv))
+	}
+	if rcv.RunIDs[0] = ""; iCfg.RunIDs[0] != utils.MetaDefault {
+		t.Errorf("Expected clone to not modify the cloned")
+	}
+	if rcv.EEsConns[0] = ""; iCfg.EEsConns[0] != "*conn1" {
+		t.Errorf("Expected clone to not modify the cloned")
+	}
+	if rcv.ExporterIDs[0] = ""; iCfg.ExporterIDs[0] != "INVOICES_HTML" {
+		t.Errorf("Expected clone to not modify the cloned")
+	}
+}
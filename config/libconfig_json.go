@@ -45,6 +45,8 @@ type GeneralJsonCfg struct {
 	Max_call_duration    *string
 	Reply_timeout        *string
 	Locking_timeout      *string
+	Locking_backend      *string
+	Locking_lease        *string
 	Digest_separator     *string
 	Digest_equal         *string
 	Rsr_separator        *string
@@ -68,6 +70,8 @@ type HTTPJsonCfg struct {
 	Ws_url                    *string
 	Freeswitch_cdrs_url       *string
 	Http_Cdrs                 *string
+	Metrics_url               *string
+	Events_url                *string
 	Use_basic_auth            *bool
 	Auth_users                *map[string]string
 	Client_opts               map[string]interface{}
@@ -151,12 +155,17 @@ type CdrsJsonCfg struct {
 	Online_cdr_exports   *[]string
 	Scheduler_conns      *[]string
 	Ees_conns            *[]string
+	Customer_run_id      *string
+	Supplier_run_id      *string
 }
 
 // EventReaderSJsonCfg contains the configuration of EventReaderService
 type ERsJsonCfg struct {
 	Enabled        *bool
 	Sessions_conns *[]string
+	Ees_conns      *[]string
+	Dedup_store    *string
+	Dedup_ttl      *string
 	Readers        *[]*EventReaderJsonCfg
 }
 
@@ -182,6 +191,8 @@ type EventReaderJsonCfg struct {
 	Partial_cache_expiry_action *string
 	Fields                      *[]*FcTemplateJsonCfg
 	Cache_dump_fields           *[]*FcTemplateJsonCfg
+	Dedup_id                    *string
+	Dedup_ee_ids                *[]string
 }
 
 // EEsJsonCfg contains the configuration of EventExporterService
@@ -234,6 +245,8 @@ type SessionSJsonCfg struct {
 	Client_protocol        *float64
 	Channel_sync_interval  *string
 	Terminate_attempts     *int
+	Shared_registry        *bool
+	Registry_lease         *string
 	Alterable_fields       *[]string
 	Min_dur_low_balance    *string
 	Scheduler_conns        *[]string
@@ -401,6 +414,18 @@ type AttributeSJsonCfg struct {
 	Suffix_indexed_fields *[]string
 	Nested_fields         *bool // applies when indexed fields is not defined
 	Process_runs          *int
+	Lookups               map[string]*AttrLookupJsonCfg
+}
+
+// AttrLookupJsonCfg is the external lookup used by the *http and *sql attributes
+type AttrLookupJsonCfg struct {
+	Type      *string
+	Url       *string
+	Query     *string
+	Args      *[]string
+	Timeout   *string
+	Cache_ttl *string
+	Fallback  *string
 }
 
 // ChargerSJsonCfg service config section
@@ -448,6 +473,7 @@ type ThresholdSJsonCfg struct {
 	Prefix_indexed_fields *[]string
 	Suffix_indexed_fields *[]string
 	Nested_fields         *bool // applies when indexed fields is not defined
+	Actions_conns         *[]string
 }
 
 // Route service config section
@@ -477,6 +503,7 @@ type LoaderJsonCfg struct {
 	Enabled         *bool
 	Tenant          *string
 	Dry_run         *bool
+	Transactional   *bool
 	Run_delay       *string
 	Lock_filename   *string
 	Caches_conns    *[]string
@@ -610,11 +637,15 @@ type AnalyzerSJsonCfg struct {
 }
 
 type ApierJsonCfg struct {
-	Enabled          *bool
-	Caches_conns     *[]string
-	Scheduler_conns  *[]string
-	Attributes_conns *[]string
-	Ees_conns        *[]string
+	Enabled            *bool
+	Caches_conns       *[]string
+	Scheduler_conns    *[]string
+	Attributes_conns   *[]string
+	Ees_conns          *[]string
+	Audit              *bool
+	Audit_apis         *[]string
+	Audit_storage      *string
+	Audit_exporter_ids *[]string
 }
 
 type STIRJsonCfg struct {
@@ -652,9 +683,11 @@ type SIPAgentJsonCfg struct {
 }
 
 type ConfigSCfgJson struct {
-	Enabled  *bool
-	Url      *string
-	Root_dir *string
+	Enabled                 *bool
+	Url                     *string
+	Root_dir                *string
+	Config_db               *bool
+	Config_db_sync_interval *string
 }
 
 type APIBanJsonCfg struct {
@@ -663,10 +696,28 @@ type APIBanJsonCfg struct {
 }
 
 type CoreSJsonCfg struct {
-	Caps                *int
-	Caps_strategy       *string
-	Caps_stats_interval *string
-	Shutdown_timeout    *string
+	Caps                 *int
+	Caps_strategy        *string
+	Caps_stats_interval  *string
+	Shutdown_timeout     *string
+	Sessions_conns       *[]string
+	Stats_conns          *[]string
+	Stat_queue_ids       *[]string
+	Api_auth             *bool
+	Api_keys             *map[string]string
+	Jwt_key              *string
+	Api_roles            *map[string]*APIRoleJsonCfg
+	Trace_exporter       *string
+	Trace_export_path    *string
+	Trace_sample_ratio   *float64
+	Trace_flush_interval *string
+}
+
+// APIRoleJsonCfg the role of the API credentials
+type APIRoleJsonCfg struct {
+	Allow   *[]string
+	Deny    *[]string
+	Tenants *[]string
 }
 
 // Action service config section
@@ -693,3 +744,12 @@ type AccountSJsonCfg struct {
 	Suffix_indexed_fields *[]string
 	Nested_fields         *bool // applies when indexed fields is not defined
 }
+
+// Invoice service config section
+type InvoiceSJsonCfg struct {
+	Enabled       *bool
+	Run_ids       *[]string
+	Number_prefix *string
+	Ees_conns     *[]string
+	Exporter_ids  *[]string
+}
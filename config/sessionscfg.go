@@ -107,6 +107,8 @@ type SessionSCfg struct {
 	ClientProtocol      float64
 	ChannelSyncInterval time.Duration
 	TerminateAttempts   int
+	SharedRegistry      bool
+	RegistryLease       time.Duration
 	AlterableFields     utils.StringSet
 	MinDurLowBalance    time.Duration
 	SchedulerConns      []string
@@ -267,6 +269,14 @@ func (scfg *SessionSCfg) loadFromJSONCfg(jsnCfg *SessionSJsonCfg) (err error) {
 	if jsnCfg.Terminate_attempts != nil {
 		scfg.TerminateAttempts = *jsnCfg.Terminate_attempts
 	}
+	if jsnCfg.Shared_registry != nil {
+		scfg.SharedRegistry = *jsnCfg.Shared_registry
+	}
+	if jsnCfg.Registry_lease != nil {
+		if scfg.RegistryLease, err = utils.ParseDurationWithNanosecs(*jsnCfg.Registry_lease); err != nil {
+			return err
+		}
+	}
 	if jsnCfg.Alterable_fields != nil {
 		scfg.AlterableFields = utils.NewStringSet(*jsnCfg.Alterable_fields)
 	}
@@ -298,6 +308,8 @@ func (scfg *SessionSCfg) AsMapInterface() (initialMP map[string]interface{}) {
 		utils.SessionIndexesCfg:      scfg.SessionIndexes.AsSlice(),
 		utils.ClientProtocolCfg:      scfg.ClientProtocol,
 		utils.TerminateAttemptsCfg:   scfg.TerminateAttempts,
+		utils.SharedRegistryCfg:      scfg.SharedRegistry,
+		utils.RegistryLeaseCfg:       "0",
 		utils.AlterableFieldsCfg:     scfg.AlterableFields.AsSlice(),
 		utils.STIRCfg:                scfg.STIRCfg.AsMapInterface(),
 		utils.MinDurLowBalanceCfg:    "0",
@@ -329,6 +341,9 @@ func (scfg *SessionSCfg) AsMapInterface() (initialMP map[string]interface{}) {
 	if scfg.MinDurLowBalance != 0 {
 		initialMP[utils.MinDurLowBalanceCfg] = scfg.MinDurLowBalance.String()
 	}
+	if scfg.RegistryLease != 0 {
+		initialMP[utils.RegistryLeaseCfg] = scfg.RegistryLease.String()
+	}
 	if scfg.ChargerSConns != nil {
 		chargerSConns := make([]string, len(scfg.ChargerSConns))
 		for i, item := range scfg.ChargerSConns {
@@ -433,6 +448,8 @@ func (scfg SessionSCfg) Clone() (cln *SessionSCfg) {
 		ClientProtocol:      scfg.ClientProtocol,
 		ChannelSyncInterval: scfg.ChannelSyncInterval,
 		TerminateAttempts:   scfg.TerminateAttempts,
+		SharedRegistry:      scfg.SharedRegistry,
+		RegistryLease:       scfg.RegistryLease,
 		MinDurLowBalance:    scfg.MinDurLowBalance,
 
 		SessionIndexes:  scfg.SessionIndexes.Clone(),
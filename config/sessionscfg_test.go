@@ -81,6 +81,8 @@ func TestSessionSCfgloadFromJsonCfgCase1(t *testing.T) {
 		Client_protocol:       utils.Float64Pointer(2.5),
 		Channel_sync_interval: utils.StringPointer("10"),
 		Terminate_attempts:    utils.IntPointer(6),
+		Shared_registry:       utils.BoolPointer(true),
+		Registry_lease:        utils.StringPointer("30s"),
 		Alterable_fields:      &[]string{},
 		Min_dur_low_balance:   utils.StringPointer("1"),
 		Scheduler_conns:       &[]string{utils.MetaInternal, "*conn1"},
@@ -111,6 +113,8 @@ func TestSessionSCfgloadFromJsonCfgCase1(t *testing.T) {
 		ClientProtocol:      2.5,
 		ChannelSyncInterval: 10,
 		TerminateAttempts:   6,
+		SharedRegistry:      true,
+		RegistryLease:       30 * time.Second,
 		AlterableFields:     utils.StringSet{},
 		MinDurLowBalance:    1,
 		SchedulerConns:      []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaScheduler), "*conn1"},
@@ -243,6 +247,7 @@ func TestSessionSCfgloadFromJsonCfgCase10(t *testing.T) {
 		ClientProtocol:      1.0,
 		ChannelSyncInterval: 0,
 		TerminateAttempts:   5,
+		RegistryLease:       10 * time.Second,
 		AlterableFields:     utils.StringSet{},
 		MinDurLowBalance:    0,
 		SchedulerConns:      []string{},
@@ -317,6 +322,8 @@ func TestSessionSCfgAsMapInterfaceCase1(t *testing.T) {
 		utils.ClientProtocolCfg:      1.0,
 		utils.ChannelSyncIntervalCfg: "1s",
 		utils.TerminateAttemptsCfg:   5,
+		utils.SharedRegistryCfg:      false,
+		utils.RegistryLeaseCfg:       "10s",
 		utils.MinDurLowBalanceCfg:    "0",
 		utils.AlterableFieldsCfg:     []string{},
 		utils.STIRCfg: map[string]interface{}{
@@ -355,6 +362,8 @@ func TestSessionSCfgAsMapInterfaceCase2(t *testing.T) {
             "min_dur_low_balance": "1s",
 			"client_protocol": 2.0,
 			"terminate_attempts": 10,
+			"shared_registry": true,
+			"registry_lease": "30s",
 			"stir": {
 				"allowed_attest": ["any1","any2"],
 				"payload_maxduration": "1s",
@@ -385,6 +394,8 @@ func TestSessionSCfgAsMapInterfaceCase2(t *testing.T) {
 		utils.ClientProtocolCfg:      2.0,
 		utils.ChannelSyncIntervalCfg: "0",
 		utils.TerminateAttemptsCfg:   10,
+		utils.SharedRegistryCfg:      true,
+		utils.RegistryLeaseCfg:       "30s",
 		utils.AlterableFieldsCfg:     []string{},
 		utils.STIRCfg: map[string]interface{}{
 			utils.AllowedAtestCfg:       []string{"any1", "any2"},
@@ -33,6 +33,7 @@ func TestThresholdSCfgloadFromJsonCfgCase1(t *testing.T) {
 		Prefix_indexed_fields: &[]string{"*req.index1"},
 		Suffix_indexed_fields: &[]string{"*req.index1"},
 		Nested_fields:         utils.BoolPointer(true),
+		Actions_conns:         &[]string{utils.MetaInternal, "*conn1"},
 	}
 	expected := &ThresholdSCfg{
 		Enabled:             true,
@@ -42,6 +43,7 @@ func TestThresholdSCfgloadFromJsonCfgCase1(t *testing.T) {
 		PrefixIndexedFields: &[]string{"*req.index1"},
 		SuffixIndexedFields: &[]string{"*req.index1"},
 		NestedFields:        true,
+		ActionSConns:        []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaActions), "*conn1"},
 	}
 	jsonCfg := NewDefaultCGRConfig()
 	if err = jsonCfg.thresholdSCfg.loadFromJSONCfg(cfgJSON); err != nil {
@@ -73,6 +75,7 @@ func TestThresholdSCfgAsMapInterfaceCase1(t *testing.T) {
 		utils.PrefixIndexedFieldsCfg: []string{},
 		utils.SuffixIndexedFieldsCfg: []string{},
 		utils.NestedFieldsCfg:        false,
+		utils.ActionSConnsCfg:        []string{},
 	}
 	if cgrCfg, err := NewCGRConfigFromJSONStringWithDefaults(cfgJSONStr); err != nil {
 		t.Error(err)
@@ -91,6 +94,7 @@ func TestThresholdSCfgAsMapInterfaceCase2(t *testing.T) {
 			"prefix_indexed_fields": ["*req.prefix","*req.indexed","*req.fields"],	
             "suffix_indexed_fields": ["*req.suffix_indexed_fields1", "*req.suffix_indexed_fields2"],		
 			"nested_fields": true,					
+			"actions_conns": ["*internal", "*conn1"],
 		},		
 }`
 	eMap := map[string]interface{}{
@@ -101,6 +105,7 @@ func TestThresholdSCfgAsMapInterfaceCase2(t *testing.T) {
 		utils.PrefixIndexedFieldsCfg: []string{"*req.prefix", "*req.indexed", "*req.fields"},
 		utils.SuffixIndexedFieldsCfg: []string{"*req.suffix_indexed_fields1", "*req.suffix_indexed_fields2"},
 		utils.NestedFieldsCfg:        true,
+		utils.ActionSConnsCfg:        []string{utils.MetaInternal, "*conn1"},
 	}
 	if cgrCfg, err := NewCGRConfigFromJSONStringWithDefaults(cfgJSONStr); err != nil {
 		t.Error(err)
@@ -117,6 +122,7 @@ func TestThresholdSCfgClone(t *testing.T) {
 		PrefixIndexedFields: &[]string{"*req.index1"},
 		SuffixIndexedFields: &[]string{"*req.index1"},
 		NestedFields:        true,
+		ActionSConns:        []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaActions)},
 	}
 	rcv := ban.Clone()
 	if !reflect.DeepEqual(ban, rcv) {
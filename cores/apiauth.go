@@ -0,0 +1,432 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"fmt"
+	"net/http"
+	"net/rpc"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// newAPIAuth returns the authorization of the API calls, nil if disabled
+func newAPIAuth(cfg *config.CGRConfig) *apiAuth {
+	if !cfg.CoreSCfg().APIAuth {
+		return nil
+	}
+	return &apiAuth{cfg: cfg}
+}
+
+// apiAuth authorizes the API calls based on the roles of the API keys and JWTs
+type apiAuth struct {
+	cfg *config.CGRConfig
+}
+
+// apiIdentity is the owner of the credentials used by the API calls
+type apiIdentity struct {
+	subject string // the JWT subject or the masked API key, used in the logs
+	role    string
+}
+
+// identify returns the identity of the credentials, either an API key or a JWT
+func (a *apiAuth) identify(cred string) (id *apiIdentity, err error) {
+	if cred == utils.EmptyString {
+		return nil, utils.ErrNotAuthenticated
+	}
+	coreSCfg := a.cfg.CoreSCfg()
+	if role, has := coreSCfg.APIKeys[cred]; has {
+		return &apiIdentity{subject: maskAPIKey(cred), role: role}, nil
+	}
+	if coreSCfg.JWTKey == utils.EmptyString {
+		return nil, utils.ErrUnknownApiKey
+	}
+	var claims jwt.MapClaims
+	if claims, err = parseJWT(cred, coreSCfg.JWTKey); err != nil {
+		return nil, utils.ErrUnknownApiKey
+	}
+	id = &apiIdentity{
+		subject: utils.IfaceAsString(claims["sub"]),
+		role:    utils.IfaceAsString(claims["role"]),
+	}
+	return
+}
+
+// authorize checks the API method and the tenant against the role of the identity
+func (a *apiAuth) authorize(id *apiIdentity, method, tnt string, hasTnt bool) (err error) {
+	role, has := a.cfg.CoreSCfg().APIRoles[id.role]
+	if !has {
+		return utils.ErrUnauthorizedApi
+	}
+	for _, pattern := range role.Deny {
+		if matched, _ := path.Match(pattern, method); matched {
+			return utils.ErrUnauthorizedApi
+		}
+	}
+	var allowed bool
+	for _, pattern := range role.Allow {
+		if allowed, _ = path.Match(pattern, method); allowed {
+			break
+		}
+	}
+	if !allowed {
+		return utils.ErrUnauthorizedApi
+	}
+	if len(role.Tenants) == 0 {
+		return
+	}
+	if !hasTnt { // the roles restricted to tenants can call only the APIs with a tenant
+		return utils.ErrUnauthorizedApi
+	}
+	if tnt == utils.EmptyString {
+		tnt = a.cfg.GeneralCfg().DefaultTenant
+	}
+	if !utils.SliceHasMember(role.Tenants, tnt) {
+		return utils.ErrUnauthorizedApi
+	}
+	return
+}
+
+// authorizeCall authorizes one API call with the credentials from its options
+// or with the identity authenticated on the connection, returning the identity used
+func (a *apiAuth) authorizeCall(id *apiIdentity, method, tnt string, hasTnt bool,
+	opts map[string]interface{}, remote string) (callID *apiIdentity, err error) {
+	if cred, has := opts[utils.OptsAuthorization]; has {
+		if id, err = a.identify(utils.IfaceAsString(cred)); err != nil {
+			a.auditDenied(nil, method, tnt, remote, err)
+			return
+		}
+	}
+	if id == nil {
+		err = utils.ErrNotAuthenticated
+	} else {
+		err = a.authorize(id, method, tnt, hasTnt)
+	}
+	if err != nil {
+		a.auditDenied(id, method, tnt, remote, err)
+	}
+	return id, err
+}
+
+// authorizeHTTP authorizes the HTTP request as a call of the method, with the credentials from its headers
+func (a *apiAuth) authorizeHTTP(r *http.Request, method, tnt string, hasTnt bool) (err error) {
+	var id *apiIdentity
+	if id, err = a.identify(httpCredential(r)); err == nil {
+		err = a.authorize(id, method, tnt, hasTnt)
+	}
+	if err != nil {
+		a.auditDenied(id, method, tnt, r.RemoteAddr, err)
+	}
+	return
+}
+
+// auditDenied logs the denied API calls
+func (a *apiAuth) auditDenied(id *apiIdentity, method, tnt, remote string, err error) {
+	subject, role := utils.MetaNone, utils.MetaNone
+	if id != nil {
+		subject, role = id.subject, id.role
+	}
+	utils.Logger.Warning(
+		fmt.Sprintf("<%s> denied API call <%s> on tenant <%s> from <%s>, subject: <%s>, role: <%s>, error: <%s>",
+			utils.CoreS, method, tnt, remote, subject, role, err.Error()))
+}
+
+// parseJWT validates the HS256 token, including its expiry, returning the claims
+func parseJWT(tkn, key string) (claims jwt.MapClaims, err error) {
+	var t *jwt.Token
+	if t, err = jwt.Parse(tkn, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(key), nil
+	}); err != nil {
+		return
+	}
+	return t.Claims.(jwt.MapClaims), nil
+}
+
+// maskAPIKey hides most of the API key before logging it
+func maskAPIKey(key string) string {
+	if len(key) <= 4 {
+		return strings.Repeat("*", len(key))
+	}
+	return key[:4] + strings.Repeat("*", len(key)-4)
+}
+
+// httpCredential returns the credentials from the headers of the HTTP request
+// either as bearer in Authorization header or as X-API-Key header
+func httpCredential(r *http.Request) (cred string) {
+	if cred = r.Header.Get("X-API-Key"); cred != utils.EmptyString {
+		return
+	}
+	if authHdr := strings.SplitN(r.Header.Get("Authorization"), " ", 2); len(authHdr) == 2 &&
+		strings.EqualFold(authHdr[0], "Bearer") {
+		cred = authHdr[1]
+	}
+	return
+}
+
+// apiCallArgs returns the tenant and the options from the arguments of an API call
+// hasTnt is false if the arguments do not have a Tenant field
+func apiCallArgs(args interface{}) (tnt string, hasTnt bool, opts map[string]interface{}) {
+	v, isStruct := argsStruct(args)
+	if !isStruct {
+		return
+	}
+	if fld, has := structField(v, utils.Tenant); has && fld.Kind() == reflect.String {
+		tnt, hasTnt = fld.String(), true
+	}
+	if fld, has := structField(v, utils.Opts); has && fld.Kind() == reflect.Map && !fld.IsNil() {
+		opts, _ = fld.Interface().(map[string]interface{})
+	}
+	return
+}
+
+// argsStruct returns the struct behind the arguments of an API call
+func argsStruct(args interface{}) (v reflect.Value, isStruct bool) {
+	v = reflect.ValueOf(args)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct
+}
+
+// structField returns the field by name, including the promoted ones
+// has is false if the field is missing or promoted through a nil pointer
+func structField(v reflect.Value, name string) (fld reflect.Value, has bool) {
+	sf, has := v.Type().FieldByName(name)
+	if !has {
+		return
+	}
+	fld = v
+	for _, idx := range sf.Index {
+		if fld.Kind() == reflect.Ptr {
+			if fld.IsNil() {
+				return fld, false
+			}
+			fld = fld.Elem()
+		}
+		fld = fld.Field(idx)
+	}
+	return
+}
+
+// newAuthServerCodec authorizes the API calls read by the codec
+// the credentials are sent in the *authorization option of each call,
+// once per connection with CoreSv1.Authenticate or in the headers of the HTTP requests
+func newAuthServerCodec(sc rpc.ServerCodec, auth *apiAuth, conn conn) rpc.ServerCodec {
+	if auth == nil {
+		return sc
+	}
+	c := &authServerCodec{
+		sc:   sc,
+		auth: auth,
+	}
+	if from := conn.RemoteAddr(); from != nil {
+		c.remote = from.String()
+	}
+	if hc, canCast := conn.(httpConn); canCast {
+		if cred := httpCredential(hc.Request()); cred != utils.EmptyString {
+			var err error
+			if c.id, err = auth.identify(cred); err != nil {
+				auth.auditDenied(nil, utils.MetaHTTP, utils.EmptyString, c.remote, err)
+			}
+		}
+	}
+	return c
+}
+
+// httpConn is implemented by the connections over HTTP and WebSocket
+type httpConn interface {
+	Request() *http.Request
+}
+
+type authServerCodec struct {
+	sc     rpc.ServerCodec
+	auth   *apiAuth
+	remote string
+	id     *apiIdentity // authenticated on the connection
+	method string       // the method of the request being read
+	callID *apiIdentity // the identity used by the request being read
+	wrLk   sync.Mutex   // the authentication replies are written outside the rpc.Server
+}
+
+func (c *authServerCodec) ReadRequestHeader(r *rpc.Request) (err error) {
+	for {
+		if err = c.sc.ReadRequestHeader(r); err != nil ||
+			r.ServiceMethod != utils.CoreSv1Authenticate {
+			c.method = r.ServiceMethod
+			return
+		}
+		if err = c.authenticate(r); err != nil {
+			return
+		}
+	}
+}
+
+// authenticate answers the CoreSv1.Authenticate requests, keeping the identity for the next calls
+func (c *authServerCodec) authenticate(r *rpc.Request) (err error) {
+	rsp := &rpc.Response{ServiceMethod: r.ServiceMethod, Seq: r.Seq}
+	var reply interface{} = utils.OK
+	var args utils.TenantWithOpts
+	if err = c.sc.ReadRequestBody(&args); err != nil {
+		rsp.Error, reply = err.Error(), struct{}{}
+	} else if id, err := c.auth.identify(utils.IfaceAsString(args.Opts[utils.OptsAuthorization])); err != nil {
+		c.auth.auditDenied(nil, r.ServiceMethod, args.Tenant, c.remote, err)
+		rsp.Error, reply = err.Error(), struct{}{}
+	} else {
+		c.id = id
+	}
+	c.wrLk.Lock()
+	err = c.sc.WriteResponse(rsp, reply)
+	c.wrLk.Unlock()
+	return
+}
+
+func (c *authServerCodec) ReadRequestBody(x interface{}) (err error) {
+	if err = c.sc.ReadRequestBody(x); err != nil ||
+		x == nil { // body discarded by the rpc.Server
+		return
+	}
+	tnt, hasTnt, opts := apiCallArgs(x)
+	c.callID, err = c.auth.authorizeCall(c.id, c.method, tnt, hasTnt, opts, c.remote)
+	delete(opts, utils.OptsAuthorization) // do not pass the credentials further
+	return
+}
+
+func (c *authServerCodec) WriteResponse(r *rpc.Response, x interface{}) (err error) {
+	c.wrLk.Lock()
+	err = c.sc.WriteResponse(r, x)
+	c.wrLk.Unlock()
+	return
+}
+
+func (c *authServerCodec) Close() error { return c.sc.Close() }
+
+// newAuthBiRPCCodec authorizes the API calls received over BiRPC
+// the calls made by the engine towards the client are not affected
+// argType returns the type of the arguments expected by the handler of the method, nil if not handled
+func newAuthBiRPCCodec(cdc rpc2.Codec, auth *apiAuth, remote string,
+	argType func(method string) reflect.Type) rpc2.Codec {
+	if auth == nil {
+		return cdc
+	}
+	return &authBiRPCCodec{
+		Codec:   cdc,
+		auth:    auth,
+		remote:  remote,
+		argType: argType,
+	}
+}
+
+type authBiRPCCodec struct {
+	rpc2.Codec
+	auth    *apiAuth
+	remote  string
+	argType func(method string) reflect.Type
+	id      *apiIdentity // authenticated on the connection
+	callID  *apiIdentity // the identity used by the request being read
+
+	denied func(method, tnt string, args interface{}, id *apiIdentity, err error) // optional, called for the denied requests
+}
+
+// ReadHeader answers the denied requests without passing them to the handlers
+func (c *authBiRPCCodec) ReadHeader(req *rpc2.Request, resp *rpc2.Response) (err error) {
+	for {
+		if err = c.Codec.ReadHeader(req, resp); err != nil ||
+			req.Method == utils.EmptyString { // response to a request sent by the engine
+			return
+		}
+		args := c.newCallArgs(req.Method) // the JSON params are kept for the handler until the next header
+		var tnt string
+		var hasTnt bool
+		var opts map[string]interface{}
+		if c.Codec.ReadRequestBody(args) == nil {
+			if mp, isMap := args.(*map[string]interface{}); isMap { // no handler, the tenant is only logged
+				tnt = utils.IfaceAsString((*mp)[utils.Tenant])
+				opts, _ = (*mp)[utils.Opts].(map[string]interface{})
+			} else {
+				tnt, hasTnt, opts = apiCallArgs(args)
+			}
+		}
+		var authErr error
+		reply := interface{}(utils.OK)
+		c.callID = nil
+		if req.Method == utils.CoreSv1Authenticate {
+			var id *apiIdentity
+			if id, authErr = c.auth.identify(utils.IfaceAsString(opts[utils.OptsAuthorization])); authErr != nil {
+				c.auth.auditDenied(nil, req.Method, tnt, c.remote, authErr)
+			} else {
+				c.id = id
+			}
+		} else if c.callID, authErr = c.auth.authorizeCall(c.id, req.Method, tnt, hasTnt, opts, c.remote); authErr == nil {
+			return
+		}
+		if authErr != nil && c.denied != nil {
+			delete(opts, utils.OptsAuthorization) // do not pass the credentials further
+			c.denied(req.Method, tnt, args, c.callID, authErr)
+		}
+		if req.Seq == 0 { // notification, no reply expected
+			continue
+		}
+		rsp := &rpc2.Response{Seq: req.Seq}
+		if authErr != nil {
+			rsp.Error, reply = authErr.Error(), rsp
+		}
+		if err = c.Codec.WriteResponse(rsp, reply); err != nil {
+			return
+		}
+	}
+}
+
+// newCallArgs returns the arguments of the method handler, as the rpc2.Client builds them
+// a map is returned for the methods without handler, ie: CoreSv1.Authenticate
+func (c *authBiRPCCodec) newCallArgs(method string) interface{} {
+	var argType reflect.Type
+	if c.argType != nil {
+		argType = c.argType(method)
+	}
+	if argType == nil {
+		return new(map[string]interface{})
+	}
+	if argType.Kind() == reflect.Ptr {
+		return reflect.New(argType.Elem()).Interface()
+	}
+	return reflect.New(argType).Interface()
+}
+
+// ReadRequestBody removes the credentials from the arguments passed to the handler
+func (c *authBiRPCCodec) ReadRequestBody(x interface{}) (err error) {
+	if err = c.Codec.ReadRequestBody(x); err != nil {
+		return
+	}
+	_, _, opts := apiCallArgs(x)
+	delete(opts, utils.OptsAuthorization)
+	return
+}
@@ -0,0 +1,277 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+	rpc2_jsonrpc "github.com/cenkalti/rpc2/jsonrpc"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/dgrijalva/jwt-go"
+)
+
+func newTestAPIAuth() *apiAuth {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.CoreSCfg().APIAuth = true
+	cfg.CoreSCfg().APIKeys = map[string]string{
+		"adminKey":    "admin",
+		"resellerKey": "reseller",
+	}
+	cfg.CoreSCfg().JWTKey = "secret"
+	cfg.CoreSCfg().APIRoles = map[string]*config.APIRole{
+		"admin": {Allow: []string{"*"}},
+		"reseller": {
+			Allow:   []string{"TestSv1.*"},
+			Deny:    []string{"TestSv1.Set*"},
+			Tenants: []string{"cgrates.org", "reseller.org"},
+		},
+	}
+	return newAPIAuth(cfg)
+}
+
+func newTestJWT(t *testing.T, method jwt.SigningMethod, key interface{}, role string, exp time.Time) string {
+	tkn, err := jwt.NewWithClaims(method, jwt.MapClaims{
+		"sub":  "portal",
+		"role": role,
+		"exp":  exp.Unix(),
+	}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tkn
+}
+
+func TestAPIAuthIdentify(t *testing.T) {
+	auth := newTestAPIAuth()
+	if id, err := auth.identify("resellerKey"); err != nil {
+		t.Error(err)
+	} else if id.role != "reseller" || id.subject != "rese*******" {
+		t.Errorf("Unexpected identity: %+v", id)
+	}
+	if _, err := auth.identify(utils.EmptyString); err != utils.ErrNotAuthenticated {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotAuthenticated, err)
+	}
+	if _, err := auth.identify("unknownKey"); err != utils.ErrUnknownApiKey {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrUnknownApiKey, err)
+	}
+	tkn := newTestJWT(t, jwt.SigningMethodHS256, []byte("secret"), "reseller", time.Now().Add(time.Hour))
+	if id, err := auth.identify(tkn); err != nil {
+		t.Error(err)
+	} else if id.role != "reseller" || id.subject != "portal" {
+		t.Errorf("Unexpected identity: %+v", id)
+	}
+	tkn = newTestJWT(t, jwt.SigningMethodHS256, []byte("secret"), "reseller", time.Now().Add(-time.Minute))
+	if _, err := auth.identify(tkn); err != utils.ErrUnknownApiKey {
+		t.Errorf("Expected error for the expired token: %v, received: %v", utils.ErrUnknownApiKey, err)
+	}
+	tkn = newTestJWT(t, jwt.SigningMethodHS256, []byte("other"), "reseller", time.Now().Add(time.Hour))
+	if _, err := auth.identify(tkn); err != utils.ErrUnknownApiKey {
+		t.Errorf("Expected error for the wrong signature: %v, received: %v", utils.ErrUnknownApiKey, err)
+	}
+	tkn = newTestJWT(t, jwt.SigningMethodHS512, []byte("secret"), "reseller", time.Now().Add(time.Hour))
+	if _, err := auth.identify(tkn); err != utils.ErrUnknownApiKey {
+		t.Errorf("Expected error for the signing method: %v, received: %v", utils.ErrUnknownApiKey, err)
+	}
+}
+
+func TestAPIAuthAuthorize(t *testing.T) {
+	auth := newTestAPIAuth()
+	admin := &apiIdentity{role: "admin"}
+	reseller := &apiIdentity{role: "reseller"}
+	for _, tc := range []struct {
+		id     *apiIdentity
+		method string
+		tnt    string
+		hasTnt bool
+		err    error
+	}{
+		{id: admin, method: "APIerSv1.SetAccount", hasTnt: true},
+		{id: admin, method: utils.CoreSv1Status},
+		{id: reseller, method: "TestSv1.GetValue", tnt: "reseller.org", hasTnt: true},
+		{id: reseller, method: "TestSv1.GetValue", hasTnt: true}, // the default tenant
+		{id: reseller, method: "TestSv1.GetValue", tnt: "other.org", hasTnt: true, err: utils.ErrUnauthorizedApi},
+		{id: reseller, method: "TestSv1.GetValue", err: utils.ErrUnauthorizedApi},
+		{id: reseller, method: "TestSv1.SetValue", tnt: "cgrates.org", hasTnt: true, err: utils.ErrUnauthorizedApi},
+		{id: reseller, method: "APIerSv1.GetAccount", tnt: "cgrates.org", hasTnt: true, err: utils.ErrUnauthorizedApi},
+		{id: &apiIdentity{role: "missing"}, method: "TestSv1.GetValue", err: utils.ErrUnauthorizedApi},
+	} {
+		if err := auth.authorize(tc.id, tc.method, tc.tnt, tc.hasTnt); err != tc.err {
+			t.Errorf("For role <%s> calling <%s> on <%s> expected: %v, received: %v",
+				tc.id.role, tc.method, tc.tnt, tc.err, err)
+		}
+	}
+}
+
+func TestAPIAuthCallArgs(t *testing.T) {
+	opts := map[string]interface{}{utils.OptsAuthorization: "adminKey"}
+	if tnt, hasTnt, rcv := apiCallArgs(&utils.TenantIDWithOpts{
+		TenantID: &utils.TenantID{Tenant: "cgrates.org"},
+		Opts:     opts,
+	}); tnt != "cgrates.org" || !hasTnt || rcv[utils.OptsAuthorization] != "adminKey" {
+		t.Errorf("Unexpected args: %q %v %v", tnt, hasTnt, rcv)
+	}
+	if _, hasTnt, _ := apiCallArgs(&utils.TenantIDWithOpts{}); hasTnt { // nil embedded CGREvent
+		t.Error("Expected no tenant")
+	}
+	if _, hasTnt, rcv := apiCallArgs(utils.StringPointer("cgrates.org")); hasTnt || rcv != nil {
+		t.Error("Expected no tenant and options")
+	}
+	req, _ := http.NewRequest(http.MethodPost, "/jsonrpc", nil)
+	req.Header.Set("Authorization", "Bearer resellerKey")
+	if cred := httpCredential(req); cred != "resellerKey" {
+		t.Errorf("Unexpected credential: %q", cred)
+	}
+	req.Header.Set("X-API-Key", "adminKey")
+	if cred := httpCredential(req); cred != "adminKey" {
+		t.Errorf("Unexpected credential: %q", cred)
+	}
+}
+
+type authTestService struct{}
+
+func (authTestService) GetValue(args *utils.TenantWithOpts, reply *string) error {
+	if _, has := args.Opts[utils.OptsAuthorization]; has {
+		return errors.New("credentials passed to the API")
+	}
+	*reply = args.Tenant
+	return nil
+}
+
+func (authTestService) SetValue(args *utils.TenantWithOpts, reply *string) error {
+	*reply = utils.OK
+	return nil
+}
+
+func TestAuthServerCodec(t *testing.T) {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("TestSv1", new(authTestService)); err != nil {
+		t.Fatal(err)
+	}
+	srvConn, clntConn := net.Pipe()
+	go srv.ServeCodec(newCapsJSONCodec(srvConn, engine.NewCaps(0, utils.MetaBusy), nil, newTestAPIAuth(), nil))
+	clnt := jsonrpc.NewClient(clntConn)
+	defer clnt.Close()
+
+	var reply string
+	if err := clnt.Call("TestSv1.GetValue", &utils.TenantWithOpts{Tenant: "cgrates.org"},
+		&reply); err == nil || err.Error() != utils.ErrNotAuthenticated.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotAuthenticated, err)
+	}
+	if err := clnt.Call("TestSv1.GetValue", &utils.TenantWithOpts{
+		Tenant: "reseller.org",
+		Opts:   map[string]interface{}{utils.OptsAuthorization: "resellerKey"},
+	}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != "reseller.org" {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	if err := clnt.Call(utils.CoreSv1Authenticate, &utils.TenantWithOpts{
+		Opts: map[string]interface{}{utils.OptsAuthorization: "unknownKey"},
+	}, &reply); err == nil || err.Error() != utils.ErrUnknownApiKey.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrUnknownApiKey, err)
+	}
+	if err := clnt.Call(utils.CoreSv1Authenticate, &utils.TenantWithOpts{
+		Opts: map[string]interface{}{utils.OptsAuthorization: "resellerKey"},
+	}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	// authenticated on the connection
+	if err := clnt.Call("TestSv1.GetValue", &utils.TenantWithOpts{Tenant: "cgrates.org"},
+		&reply); err != nil {
+		t.Error(err)
+	}
+	if err := clnt.Call("TestSv1.GetValue", &utils.TenantWithOpts{Tenant: "other.org"},
+		&reply); err == nil || err.Error() != utils.ErrUnauthorizedApi.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrUnauthorizedApi, err)
+	}
+	if err := clnt.Call("TestSv1.SetValue", &utils.TenantWithOpts{Tenant: "cgrates.org"},
+		&reply); err == nil || err.Error() != utils.ErrUnauthorizedApi.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrUnauthorizedApi, err)
+	}
+	// the credentials of the call override the ones of the connection
+	if err := clnt.Call("TestSv1.SetValue", &utils.TenantWithOpts{
+		Tenant: "other.org",
+		Opts:   map[string]interface{}{utils.OptsAuthorization: "adminKey"},
+	}, &reply); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAuthBiRPCCodec(t *testing.T) {
+	s := NewServer(nil)
+	s.BiRPCRegisterName("TestSv1.GetValue", func(_ *rpc2.Client, args *utils.TenantWithOpts, reply *string) error {
+		return new(authTestService).GetValue(args, reply)
+	})
+	s.BiRPCRegisterName("TestSv1.GetNoTenant", func(_ *rpc2.Client, args *utils.AttrLoadTpFromFolder, reply *string) error {
+		*reply = utils.OK
+		return nil
+	})
+	srvConn, clntConn := net.Pipe()
+	go s.birpcSrv.ServeCodec(newAuthBiRPCCodec(rpc2_jsonrpc.NewJSONCodec(srvConn), newTestAPIAuth(), "pipe", s.biRPCArgType))
+	clnt := rpc2.NewClientWithCodec(rpc2_jsonrpc.NewJSONCodec(clntConn))
+	go clnt.Run()
+	defer clnt.Close()
+
+	var reply string
+	if err := clnt.Call("TestSv1.GetValue", &utils.TenantWithOpts{Tenant: "cgrates.org"},
+		&reply); err == nil || err.Error() != utils.ErrNotAuthenticated.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotAuthenticated, err)
+	}
+	tkn := newTestJWT(t, jwt.SigningMethodHS256, []byte("secret"), "reseller", time.Now().Add(time.Hour))
+	if err := clnt.Call(utils.CoreSv1Authenticate, &utils.TenantWithOpts{
+		Opts: map[string]interface{}{utils.OptsAuthorization: tkn},
+	}, &reply); err != nil {
+		t.Error(err)
+	}
+	if err := clnt.Call("TestSv1.GetValue", &utils.TenantWithOpts{Tenant: "other.org"},
+		&reply); err == nil || err.Error() != utils.ErrUnauthorizedApi.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrUnauthorizedApi, err)
+	}
+	if err := clnt.Call("TestSv1.GetValue", &utils.TenantWithOpts{
+		Tenant: "reseller.org",
+		Opts:   map[string]interface{}{utils.OptsAuthorization: tkn},
+	}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != "reseller.org" {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	// the tenant is checked only on the arguments of the handler, not on any field received
+	if err := clnt.Call("TestSv1.GetNoTenant", map[string]interface{}{
+		utils.Tenant: "cgrates.org",
+		utils.Opts:   map[string]interface{}{utils.OptsAuthorization: tkn},
+	}, &reply); err == nil || err.Error() != utils.ErrUnauthorizedApi.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrUnauthorizedApi, err)
+	}
+	if err := clnt.Call("TestSv1.GetNoTenant", &utils.AttrLoadTpFromFolder{
+		Opts: map[string]interface{}{utils.OptsAuthorization: "adminKey"},
+	}, &reply); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"fmt"
+	"net/rpc"
+	"reflect"
+	"sync"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// newAuditServerCodec records the audited API calls read by the codec
+// the codec wraps the authorization one in order to record the identity of the caller
+func newAuditServerCodec(sc rpc.ServerCodec, adt *engine.AuditS, conn conn) rpc.ServerCodec {
+	if adt == nil {
+		return sc
+	}
+	c := &auditServerCodec{
+		sc:      sc,
+		adt:     adt,
+		entries: make(map[uint64]*engine.AuditEntry),
+	}
+	if from := conn.RemoteAddr(); from != nil {
+		c.remote = from.String()
+	}
+	return c
+}
+
+type auditServerCodec struct {
+	sc     rpc.ServerCodec
+	adt    *engine.AuditS
+	remote string
+	method string // the method of the request being read
+	seq    uint64 // the sequence of the request being read
+
+	entries map[uint64]*engine.AuditEntry // the audited requests waiting for their response
+	eLk     sync.Mutex
+}
+
+func (c *auditServerCodec) ReadRequestHeader(r *rpc.Request) (err error) {
+	if err = c.sc.ReadRequestHeader(r); err != nil {
+		return
+	}
+	c.method, c.seq = r.ServiceMethod, r.Seq
+	return
+}
+
+// ReadRequestBody starts the audit entry, the denied requests are recorded as well
+func (c *auditServerCodec) ReadRequestBody(x interface{}) (err error) {
+	err = c.sc.ReadRequestBody(x)
+	if x == nil || // body discarded by the rpc.Server
+		!c.adt.Audited(c.method) {
+		return
+	}
+	tnt, itmID := auditCallArgs(x)
+	ae := c.adt.NewAuditEntry(c.method, tnt, itmID, x)
+	ae.RemoteAddr = c.remote
+	if ac, canCast := c.sc.(*authServerCodec); canCast && ac.callID != nil {
+		ae.Subject, ae.Role = ac.callID.subject, ac.callID.role
+	}
+	c.eLk.Lock()
+	c.entries[c.seq] = ae
+	c.eLk.Unlock()
+	return
+}
+
+// WriteResponse records the audit entry before replying
+func (c *auditServerCodec) WriteResponse(r *rpc.Response, x interface{}) (err error) {
+	c.eLk.Lock()
+	ae, has := c.entries[r.Seq]
+	delete(c.entries, r.Seq)
+	c.eLk.Unlock()
+	if has {
+		recordAuditEntry(c.adt, ae, r.Error)
+	}
+	return c.sc.WriteResponse(r, x)
+}
+
+func (c *auditServerCodec) Close() error { return c.sc.Close() }
+
+// newAuditBiRPCCodec records the audited API calls received over BiRPC
+// the codec wraps the authorization one in order to record the identity of the caller
+// and the denied requests, answered by the authorization codec
+func newAuditBiRPCCodec(cdc rpc2.Codec, adt *engine.AuditS, remote string) rpc2.Codec {
+	if adt == nil {
+		return cdc
+	}
+	c := &auditBiRPCCodec{
+		Codec:   cdc,
+		adt:     adt,
+		remote:  remote,
+		entries: make(map[uint64]*engine.AuditEntry),
+	}
+	if ac, canCast := cdc.(*authBiRPCCodec); canCast {
+		ac.denied = c.recordDenied
+	}
+	return c
+}
+
+type auditBiRPCCodec struct {
+	rpc2.Codec
+	adt    *engine.AuditS
+	remote string
+	method string // the method of the request being read, empty for the responses
+	seq    uint64 // the sequence of the request being read
+
+	entries map[uint64]*engine.AuditEntry // the audited requests waiting for their response
+	eLk     sync.Mutex
+}
+
+func (c *auditBiRPCCodec) ReadHeader(req *rpc2.Request, resp *rpc2.Response) (err error) {
+	if err = c.Codec.ReadHeader(req, resp); err != nil {
+		return
+	}
+	c.method, c.seq = req.Method, req.Seq
+	return
+}
+
+// ReadRequestBody starts the audit entry of the requests received from the client
+func (c *auditBiRPCCodec) ReadRequestBody(x interface{}) (err error) {
+	err = c.Codec.ReadRequestBody(x)
+	if x == nil || // body discarded by the rpc2.Client
+		c.method == utils.EmptyString ||
+		!c.adt.Audited(c.method) {
+		return
+	}
+	tnt, itmID := auditCallArgs(x)
+	ae := c.adt.NewAuditEntry(c.method, tnt, itmID, x)
+	ae.RemoteAddr = c.remote
+	if ac, canCast := c.Codec.(*authBiRPCCodec); canCast && ac.callID != nil {
+		ae.Subject, ae.Role = ac.callID.subject, ac.callID.role
+	}
+	if c.seq == 0 { // notification, no response to wait for
+		recordAuditEntry(c.adt, ae, utils.EmptyString)
+		return
+	}
+	c.eLk.Lock()
+	c.entries[c.seq] = ae
+	c.eLk.Unlock()
+	return
+}
+
+// WriteResponse records the audit entry before replying
+func (c *auditBiRPCCodec) WriteResponse(r *rpc2.Response, x interface{}) (err error) {
+	c.eLk.Lock()
+	ae, has := c.entries[r.Seq]
+	delete(c.entries, r.Seq)
+	c.eLk.Unlock()
+	if has {
+		recordAuditEntry(c.adt, ae, r.Error)
+	}
+	return c.Codec.WriteResponse(r, x)
+}
+
+// recordDenied records the requests denied by the authorization codec
+func (c *auditBiRPCCodec) recordDenied(method, tnt string, args interface{}, id *apiIdentity, authErr error) {
+	if !c.adt.Audited(method) {
+		return
+	}
+	_, itmID := auditCallArgs(args)
+	ae := c.adt.NewAuditEntry(method, tnt, itmID, args)
+	ae.RemoteAddr = c.remote
+	if id != nil {
+		ae.Subject, ae.Role = id.subject, id.role
+	}
+	recordAuditEntry(c.adt, ae, authErr.Error())
+}
+
+// recordAuditEntry records the audit entry, logging the failures
+func recordAuditEntry(adt *engine.AuditS, ae *engine.AuditEntry, errStr string) {
+	if err := adt.RecordAuditEntry(ae, errStr); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> failed recording the audit entry of <%s> from <%s>, error: <%s>",
+				utils.CoreS, ae.Method, ae.RemoteAddr, err.Error()))
+	}
+}
+
+// auditCallArgs returns the tenant and the ID of the item from the arguments of an API call
+func auditCallArgs(args interface{}) (tnt, itmID string) {
+	tnt, _, _ = apiCallArgs(args)
+	v, isStruct := argsStruct(args)
+	if !isStruct {
+		return
+	}
+	for _, fldName := range []string{utils.ID, utils.AccountField} {
+		if fld, has := structField(v, fldName); has && fld.Kind() == reflect.String {
+			return tnt, fld.String()
+		}
+	}
+	return
+}
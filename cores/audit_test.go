@@ -0,0 +1,210 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"strings"
+	"testing"
+
+	"github.com/cenkalti/rpc2"
+	rpc2_jsonrpc "github.com/cenkalti/rpc2/jsonrpc"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+type auditTestService struct {
+	dm *engine.DataManager
+}
+
+func (s *auditTestService) SetFilter(args *engine.FilterWithOpts, reply *string) (err error) {
+	if err = s.dm.SetFilter(args.Filter, false); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+func (s *auditTestService) GetFilter(args *utils.TenantIDWithOpts, reply *engine.Filter) (err error) {
+	var fltr *engine.Filter
+	if fltr, err = s.dm.GetFilter(args.Tenant, args.ID, false, false, utils.NonTransactional); err != nil {
+		return
+	}
+	*reply = *fltr
+	return
+}
+
+func TestAuditCallArgs(t *testing.T) {
+	if tnt, itmID := auditCallArgs(&engine.FilterWithOpts{
+		Filter: &engine.Filter{Tenant: "cgrates.org", ID: "FLTR_1"},
+	}); tnt != "cgrates.org" || itmID != "FLTR_1" {
+		t.Errorf("Unexpected tenant: %q and ID: %q", tnt, itmID)
+	}
+	if tnt, itmID := auditCallArgs(&utils.AttrSetAccount{Tenant: "cgrates.org", Account: "1001"}); tnt != "cgrates.org" || itmID != "1001" {
+		t.Errorf("Unexpected tenant: %q and ID: %q", tnt, itmID)
+	}
+	if tnt, itmID := auditCallArgs(utils.StringPointer("value")); tnt != utils.EmptyString || itmID != utils.EmptyString {
+		t.Errorf("Unexpected tenant: %q and ID: %q", tnt, itmID)
+	}
+}
+
+func TestAuditServerCodec(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.ApierCfg().Audit = true
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	storDB := engine.NewInternalDB(nil, nil, false)
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("APIerSv1", &auditTestService{dm: dm}); err != nil {
+		t.Fatal(err)
+	}
+	srvConn, clntConn := net.Pipe()
+	go srv.ServeCodec(newCapsJSONCodec(srvConn, engine.NewCaps(0, utils.MetaBusy), nil,
+		newTestAPIAuth(), engine.NewAuditS(cfg, dm, storDB, nil)))
+	clnt := jsonrpc.NewClient(clntConn)
+	defer clnt.Close()
+
+	var reply string
+	if err := clnt.Call("APIerSv1.SetFilter", &engine.FilterWithOpts{
+		Filter: &engine.Filter{
+			Tenant: "cgrates.org",
+			ID:     "FLTR_AUDIT",
+			Rules: []*engine.FilterRule{{
+				Type:    utils.MetaString,
+				Element: "~*req.Account",
+				Values:  []string{"1001"},
+			}},
+		},
+		Opts: map[string]interface{}{utils.OptsAuthorization: "adminKey"},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	// the read only calls are not audited
+	var fltr engine.Filter
+	if err := clnt.Call("APIerSv1.GetFilter", &utils.TenantIDWithOpts{
+		TenantID: &utils.TenantID{Tenant: "cgrates.org", ID: "FLTR_AUDIT"},
+		Opts:     map[string]interface{}{utils.OptsAuthorization: "adminKey"},
+	}, &fltr); err != nil {
+		t.Fatal(err)
+	}
+	// the denied calls are audited as well
+	if err := clnt.Call("APIerSv1.SetFilter", &engine.FilterWithOpts{
+		Filter: &engine.Filter{Tenant: "cgrates.org", ID: "FLTR_AUDIT"},
+		Opts:   map[string]interface{}{utils.OptsAuthorization: "resellerKey"},
+	}, &reply); err == nil || err.Error() != utils.ErrUnauthorizedApi.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrUnauthorizedApi, err)
+	}
+
+	aes, err := storDB.GetAuditEntries(&utils.AuditLogFilter{ItemIDs: []string{"cgrates.org:FLTR_AUDIT"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aes) != 2 {
+		t.Fatalf("Expected 2 entries, received %s", utils.ToJSON(aes))
+	}
+	if aes[0].Method != "APIerSv1.SetFilter" ||
+		aes[0].Subject != maskAPIKey("adminKey") ||
+		aes[0].Role != "admin" ||
+		aes[0].RemoteAddr != "pipe" ||
+		aes[0].Error != utils.EmptyString ||
+		aes[0].Before != utils.EmptyString ||
+		aes[0].After == utils.EmptyString ||
+		len(aes[0].Diff) == 0 {
+		t.Errorf("Unexpected entry: %s", utils.ToJSON(aes[0]))
+	}
+	if aes[1].Subject != maskAPIKey("resellerKey") ||
+		aes[1].Error != utils.ErrUnauthorizedApi.Error() ||
+		aes[1].Before != aes[0].After ||
+		len(aes[1].Diff) != 0 {
+		t.Errorf("Unexpected entry: %s", utils.ToJSON(aes[1]))
+	}
+}
+
+func TestAuditBiRPCCodec(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.ApierCfg().Audit = true
+	tmpCache := engine.Cache
+	defer func() { engine.Cache = tmpCache }()
+	engine.Cache = engine.NewCacheS(cfg, nil, nil)
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	storDB := engine.NewInternalDB(nil, nil, false)
+	s := NewServer(nil)
+	s.BiRPCRegisterName("APIerSv1.SetFilter", func(_ *rpc2.Client, args *engine.FilterWithOpts, reply *string) error {
+		return (&auditTestService{dm: dm}).SetFilter(args, reply)
+	})
+	srvConn, clntConn := net.Pipe()
+	go s.birpcSrv.ServeCodec(newAuditBiRPCCodec(newAuthBiRPCCodec(rpc2_jsonrpc.NewJSONCodec(srvConn),
+		newTestAPIAuth(), "pipe", s.biRPCArgType), engine.NewAuditS(cfg, dm, storDB, nil), "pipe"))
+	clnt := rpc2.NewClientWithCodec(rpc2_jsonrpc.NewJSONCodec(clntConn))
+	go clnt.Run()
+	defer clnt.Close()
+
+	var reply string
+	if err := clnt.Call("APIerSv1.SetFilter", &engine.FilterWithOpts{
+		Filter: &engine.Filter{
+			Tenant: "cgrates.org",
+			ID:     "FLTR_AUDIT",
+			Rules: []*engine.FilterRule{{
+				Type:    utils.MetaString,
+				Element: "~*req.Account",
+				Values:  []string{"1001"},
+			}},
+		},
+		Opts: map[string]interface{}{utils.OptsAuthorization: "adminKey"},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	// the denied calls are audited as well
+	if err := clnt.Call("APIerSv1.SetFilter", &engine.FilterWithOpts{
+		Filter: &engine.Filter{Tenant: "cgrates.org", ID: "FLTR_AUDIT"},
+		Opts:   map[string]interface{}{utils.OptsAuthorization: "resellerKey"},
+	}, &reply); err == nil || err.Error() != utils.ErrUnauthorizedApi.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrUnauthorizedApi, err)
+	}
+
+	aes, err := storDB.GetAuditEntries(&utils.AuditLogFilter{Methods: []string{"APIerSv1.SetFilter"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aes) != 2 {
+		t.Fatalf("Expected 2 entries, received %s", utils.ToJSON(aes))
+	}
+	for _, ae := range aes {
+		switch ae.Subject {
+		case maskAPIKey("adminKey"):
+			if ae.Role != "admin" ||
+				ae.RemoteAddr != "pipe" ||
+				ae.Error != utils.EmptyString ||
+				ae.After == utils.EmptyString {
+				t.Errorf("Unexpected entry: %s", utils.ToJSON(ae))
+			}
+		case maskAPIKey("resellerKey"):
+			if ae.Tenant != "cgrates.org" ||
+				ae.ItemID != "cgrates.org:FLTR_AUDIT" ||
+				ae.Error != utils.ErrUnauthorizedApi.Error() ||
+				strings.Contains(ae.Args, "resellerKey") {
+				t.Errorf("Unexpected entry: %s", utils.ToJSON(ae))
+			}
+		default:
+			t.Errorf("Unexpected entry: %s", utils.ToJSON(ae))
+		}
+	}
+}
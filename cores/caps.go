@@ -36,8 +36,10 @@ type conn interface {
 	RemoteAddr() net.Addr
 }
 
-func newCapsGOBCodec(conn conn, caps *engine.Caps, anz *analyzers.AnalyzerService) (r rpc.ServerCodec) {
-	r = newCapsServerCodec(newGobServerCodec(conn), caps)
+func newCapsGOBCodec(conn conn, caps *engine.Caps, anz *analyzers.AnalyzerService, auth *apiAuth,
+	adt *engine.AuditS) (r rpc.ServerCodec) {
+	r = newCapsServerCodec(newMetricsServerCodec(newTraceServerCodec(newAuditServerCodec(
+		newAuthServerCodec(newGobServerCodec(conn), auth, conn), adt, conn), conn)), caps)
 	if anz != nil {
 		from := conn.RemoteAddr()
 		var fromstr string
@@ -54,8 +56,10 @@ func newCapsGOBCodec(conn conn, caps *engine.Caps, anz *analyzers.AnalyzerServic
 	return
 }
 
-func newCapsJSONCodec(conn conn, caps *engine.Caps, anz *analyzers.AnalyzerService) (r rpc.ServerCodec) {
-	r = newCapsServerCodec(jsonrpc.NewServerCodec(conn), caps)
+func newCapsJSONCodec(conn conn, caps *engine.Caps, anz *analyzers.AnalyzerService, auth *apiAuth,
+	adt *engine.AuditS) (r rpc.ServerCodec) {
+	r = newCapsServerCodec(newMetricsServerCodec(newTraceServerCodec(newAuditServerCodec(
+		newAuthServerCodec(jsonrpc.NewServerCodec(conn), auth, conn), adt, conn), conn)), caps)
 	if anz != nil {
 		from := conn.RemoteAddr()
 		var fromstr string
@@ -27,19 +27,24 @@ import (
 	"github.com/cgrates/cgrates/utils"
 )
 
-func NewCoreService(cfg *config.CGRConfig, caps *engine.Caps, stopChan chan struct{}) *CoreService {
+func NewCoreService(cfg *config.CGRConfig, caps *engine.Caps, connMgr *engine.ConnManager,
+	stopChan chan struct{}) *CoreService {
 	var st *engine.CapsStats
 	if caps.IsLimited() && cfg.CoreSCfg().CapsStatsInterval != 0 {
 		st = engine.NewCapsStats(cfg.CoreSCfg().CapsStatsInterval, caps, stopChan)
 	}
 	return &CoreService{
 		cfg:       cfg,
+		caps:      caps,
+		connMgr:   connMgr,
 		CapsStats: st,
 	}
 }
 
 type CoreService struct {
 	cfg       *config.CGRConfig
+	caps      *engine.Caps
+	connMgr   *engine.ConnManager
 	CapsStats *engine.CapsStats
 }
 
@@ -67,3 +72,12 @@ func (cS *CoreService) Status(arg *utils.TenantWithOpts, reply *map[string]inter
 	*reply = response
 	return
 }
+
+// Snapshot writes a new snapshot for the persisted internal DBs
+func (cS *CoreService) Snapshot(arg *utils.TenantWithOpts, reply *string) (err error) {
+	if err = engine.SnapshotInternalDB(); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
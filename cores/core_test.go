@@ -36,9 +36,10 @@ func TestNewCoreService(t *testing.T) {
 	sts := engine.NewCapsStats(cfgDflt.CoreSCfg().CapsStatsInterval, caps, stopChan)
 	expected := &CoreService{
 		cfg:       cfgDflt,
+		caps:      caps,
 		CapsStats: sts,
 	}
-	rcv := NewCoreService(cfgDflt, caps, stopChan)
+	rcv := NewCoreService(cfgDflt, caps, nil, stopChan)
 	if !reflect.DeepEqual(expected, rcv) {
 		t.Errorf("Expected %+v, received %+v", utils.ToJSON(expected), utils.ToJSON(rcv))
 	}
@@ -53,7 +54,7 @@ func TestCoreServiceStatus(t *testing.T) {
 	caps := engine.NewCaps(1, utils.MetaBusy)
 	stopChan := make(chan struct{}, 1)
 
-	cores := NewCoreService(cfgDflt, caps, stopChan)
+	cores := NewCoreService(cfgDflt, caps, nil, stopChan)
 	args := &utils.TenantWithOpts{
 		Tenant: "cgrates.org",
 		Opts:   map[string]interface{}{},
@@ -91,3 +92,12 @@ func TestCoreServiceStatus(t *testing.T) {
 
 	utils.GitLastLog = ""
 }
+
+func TestCoreServiceSnapshot(t *testing.T) {
+	cfgDflt := config.NewDefaultCGRConfig()
+	cores := NewCoreService(cfgDflt, engine.NewCaps(1, utils.MetaBusy), nil, make(chan struct{}))
+	var reply string
+	if err := cores.Snapshot(new(utils.TenantWithOpts), &reply); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+}
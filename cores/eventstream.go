@@ -0,0 +1,97 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// streamKeepAlive is the interval of the comments sent to keep the idle streams open
+var streamKeepAlive = 30 * time.Second
+
+// EventsHandler streams the live events as Server-Sent Events
+// the subscription is defined by the tenant, types and filters query parameters
+func (s *Server) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	if engine.EventStream == nil {
+		http.Error(w, utils.ErrNotImplemented.Error(), http.StatusNotFound)
+		return
+	}
+	flusher, canFlush := w.(http.Flusher)
+	if !canFlush {
+		http.Error(w, utils.ErrNotImplemented.Error(), http.StatusInternalServerError)
+		return
+	}
+	qry := r.URL.Query()
+	tnt := utils.FirstNonEmpty(qry.Get(utils.StreamTenant),
+		config.CgrConfig().GeneralCfg().DefaultTenant)
+	if s.auth != nil {
+		if err := s.auth.authorizeHTTP(r, utils.CoreSv1StreamEvents, tnt, true); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+	sub, err := engine.EventStream.Subscribe(tnt, qry[utils.StreamTypes], qry[utils.StreamFilters])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer engine.EventStream.Unsubscribe(sub)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err = fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case ev := <-sub.Events:
+			var pass bool
+			if pass, err = engine.EventStream.Pass(sub, ev); err != nil {
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", err.Error())
+				flusher.Flush()
+				return
+			} else if !pass {
+				continue
+			}
+			var data []byte
+			if data, err = json.Marshal(ev); err != nil {
+				utils.Logger.Warning(fmt.Sprintf("<%s> failed encoding the event: %s, error: <%s>",
+					utils.CoreS, utils.ToJSON(ev), err.Error()))
+				continue
+			}
+			if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestEventsHandler(t *testing.T) {
+	s := NewServer(nil)
+	srv := httptest.NewServer(http.HandlerFunc(s.EventsHandler))
+	defer srv.Close()
+	defer func() { engine.EventStream = nil }()
+
+	if rply, err := http.Get(srv.URL); err != nil {
+		t.Fatal(err)
+	} else if rply.Body.Close(); rply.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status %d, received: %d", http.StatusNotFound, rply.StatusCode)
+	}
+
+	engine.EventStream = engine.NewEventStreamS(config.NewDefaultCGRConfig())
+	if rply, err := http.Get(srv.URL + "?" + url.Values{
+		utils.StreamFilters: {"*string:~*req.Account"}}.Encode()); err != nil {
+		t.Fatal(err)
+	} else if rply.Body.Close(); rply.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status %d, received: %d", http.StatusBadRequest, rply.StatusCode)
+	}
+
+	rply, err := http.Get(srv.URL + "?" + url.Values{
+		utils.StreamTypes:   {utils.MetaCDRProcessed},
+		utils.StreamFilters: {"*string:~*req.Account:1001"}}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rply.Body.Close()
+	if rply.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, received: %d", http.StatusOK, rply.StatusCode)
+	}
+	if ct := rply.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Unexpected content type: %q", ct)
+	}
+	engine.EventStream.Publish(utils.MetaSessionStarted, "cgrates.org", map[string]interface{}{utils.AccountField: "1001"})
+	engine.EventStream.Publish(utils.MetaCDRProcessed, "cgrates.org", map[string]interface{}{utils.AccountField: "1002"})
+	engine.EventStream.Publish(utils.MetaCDRProcessed, "cgrates.org", map[string]interface{}{utils.AccountField: "1001"})
+
+	rdr := bufio.NewReader(rply.Body)
+	if line, err := rdr.ReadString('\n'); err != nil {
+		t.Fatal(err)
+	} else if line != "event: *cdr_processed\n" {
+		t.Errorf("Unexpected line: %q", line)
+	}
+	if line, err := rdr.ReadString('\n'); err != nil {
+		t.Fatal(err)
+	} else if exp := `"Event":{"Account":"1001"}}`; len(line) < len(exp)+1 ||
+		line[len(line)-len(exp)-1:] != exp+"\n" {
+		t.Errorf("Unexpected line: %q", line)
+	}
+}
+
+func TestEventsHandlerAuth(t *testing.T) {
+	s := NewServer(nil)
+	s.auth = newTestAPIAuth()
+	srv := httptest.NewServer(http.HandlerFunc(s.EventsHandler))
+	defer srv.Close()
+	engine.EventStream = engine.NewEventStreamS(config.NewDefaultCGRConfig())
+	defer func() { engine.EventStream = nil }()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"?"+utils.StreamTenant+"=cgrates.org", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{utils.EmptyString, "unknownKey", "resellerKey"} {
+		req.Header.Set("X-API-Key", key)
+		if rply, err := http.DefaultClient.Do(req); err != nil {
+			t.Fatal(err)
+		} else if rply.Body.Close(); rply.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status %d for %q, received: %d", http.StatusUnauthorized, key, rply.StatusCode)
+		}
+	}
+	req.Header.Set("X-API-Key", "adminKey")
+	rply, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rply.Body.Close()
+	if rply.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, received: %d", http.StatusOK, rply.StatusCode)
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"fmt"
+	"net/http"
+	"net/rpc"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/ltcache"
+)
+
+func newMetricsServerCodec(sc rpc.ServerCodec) rpc.ServerCodec {
+	if engine.Metrics == nil {
+		return sc
+	}
+	return &metricsServerCodec{
+		sc:   sc,
+		reqs: make(map[uint64]*metricsRequest),
+	}
+}
+
+// metricsRequest keeps the API details until the response is written
+type metricsRequest struct {
+	method    string
+	startTime time.Time
+}
+
+// metricsServerCodec measures the API calls for the metrics handler
+type metricsServerCodec struct {
+	sc     rpc.ServerCodec
+	reqs   map[uint64]*metricsRequest
+	reqsLk sync.Mutex
+}
+
+func (c *metricsServerCodec) ReadRequestHeader(r *rpc.Request) (err error) {
+	if err = c.sc.ReadRequestHeader(r); err != nil {
+		return
+	}
+	c.reqsLk.Lock()
+	c.reqs[r.Seq] = &metricsRequest{
+		method:    r.ServiceMethod,
+		startTime: time.Now(),
+	}
+	c.reqsLk.Unlock()
+	return
+}
+
+func (c *metricsServerCodec) ReadRequestBody(x interface{}) error {
+	return c.sc.ReadRequestBody(x)
+}
+
+func (c *metricsServerCodec) WriteResponse(r *rpc.Response, x interface{}) error {
+	c.reqsLk.Lock()
+	req, has := c.reqs[r.Seq]
+	delete(c.reqs, r.Seq)
+	c.reqsLk.Unlock()
+	observeAPICall(req, has, r.Error)
+	return c.sc.WriteResponse(r, x)
+}
+
+func (c *metricsServerCodec) Close() error { return c.sc.Close() }
+
+// newMetricsBiRPCCodec measures the API calls received over BiRPC
+// the codec is wrapped by the authorization one in order to measure the denied calls as well
+// the calls made by the engine towards the client and the notifications are not measured
+func newMetricsBiRPCCodec(cdc rpc2.Codec) rpc2.Codec {
+	if engine.Metrics == nil {
+		return cdc
+	}
+	return &metricsBiRPCCodec{
+		Codec: cdc,
+		reqs:  make(map[uint64]*metricsRequest),
+	}
+}
+
+// metricsBiRPCCodec measures the BiRPC API calls for the metrics handler
+type metricsBiRPCCodec struct {
+	rpc2.Codec
+	reqs   map[uint64]*metricsRequest
+	reqsLk sync.Mutex
+}
+
+func (c *metricsBiRPCCodec) ReadHeader(req *rpc2.Request, resp *rpc2.Response) (err error) {
+	if err = c.Codec.ReadHeader(req, resp); err != nil ||
+		req.Method == utils.EmptyString || // response to a request sent by the engine
+		req.Seq == 0 { // notification, no reply to measure
+		return
+	}
+	c.reqsLk.Lock()
+	c.reqs[req.Seq] = &metricsRequest{
+		method:    req.Method,
+		startTime: time.Now(),
+	}
+	c.reqsLk.Unlock()
+	return
+}
+
+func (c *metricsBiRPCCodec) WriteResponse(r *rpc2.Response, x interface{}) error {
+	c.reqsLk.Lock()
+	req, has := c.reqs[r.Seq]
+	delete(c.reqs, r.Seq)
+	c.reqsLk.Unlock()
+	observeAPICall(req, has, r.Error)
+	return c.Codec.WriteResponse(r, x)
+}
+
+// observeAPICall records the duration and the error of the API call
+func observeAPICall(req *metricsRequest, has bool, errStr string) {
+	if !has {
+		return
+	}
+	engine.Metrics.ObserveDuration(utils.MetricAPICallDuration, time.Since(req.startTime),
+		utils.MetricMethod, req.method)
+	if errStr != utils.EmptyString {
+		engine.Metrics.IncCounter(utils.MetricAPICallErrors, utils.MetricMethod, req.method)
+	}
+}
+
+// MetricsHandler exports the engine metrics in the Prometheus text format
+func (cS *CoreService) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	mr := engine.NewMetricsRegistry()
+	cS.collectMetrics(mr)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := mr.WriteMetrics(w); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> failed writing the metrics, error: <%s>",
+			utils.CoreS, err.Error()))
+		return
+	}
+	if engine.Metrics == nil {
+		return
+	}
+	if err := engine.Metrics.WriteMetrics(w); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> failed writing the metrics, error: <%s>",
+			utils.CoreS, err.Error()))
+	}
+}
+
+// collectMetrics populates the registry with the metrics gathered at scrape time
+func (cS *CoreService) collectMetrics(mr *engine.MetricsRegistry) {
+	memstats := new(runtime.MemStats)
+	runtime.ReadMemStats(memstats)
+	mr.SetGauge(utils.MetricGoroutines, float64(runtime.NumGoroutine()))
+	mr.SetGauge(utils.MetricMemoryHeapAllocs, float64(memstats.HeapAlloc))
+	if cS.caps != nil && cS.caps.IsLimited() {
+		mr.SetGauge(utils.MetricCapsAllocated, float64(cS.caps.Allocated()))
+		mr.SetGauge(utils.MetricCapsLimit, float64(cS.cfg.CoreSCfg().Caps))
+	}
+	if cS.CapsStats != nil {
+		mr.SetGauge(utils.MetricCapsPeak, float64(cS.CapsStats.GetPeak()))
+		mr.SetGauge(utils.MetricCapsAverage,
+			cS.CapsStats.GetAverage(cS.cfg.GeneralCfg().RoundingDecimals))
+	}
+	if engine.Cache != nil {
+		var chStats map[string]*ltcache.CacheStats
+		if err := engine.Cache.V1GetCacheStats(new(utils.AttrCacheIDsWithOpts), &chStats); err == nil {
+			for chID, chStat := range chStats {
+				mr.SetGauge(utils.MetricCacheItems, float64(chStat.Items), utils.MetricPartition, chID)
+			}
+		}
+		hits, misses := engine.Cache.GetHits()
+		for chID, cnt := range hits {
+			mr.AddCounter(utils.MetricCacheHits, float64(cnt), utils.MetricPartition, chID)
+		}
+		for chID, cnt := range misses {
+			mr.AddCounter(utils.MetricCacheMisses, float64(cnt), utils.MetricPartition, chID)
+		}
+	}
+	if len(cS.cfg.CoreSCfg().SessionSConns) != 0 {
+		var sCnt int
+		if err := cS.connMgr.Call(cS.cfg.CoreSCfg().SessionSConns, nil,
+			utils.SessionSv1GetActiveSessionsCount, new(utils.SessionFilter), &sCnt); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> failed querying the active sessions, error: <%s>",
+				utils.CoreS, err.Error()))
+		} else {
+			mr.SetGauge(utils.MetricSessionsActive, float64(sCnt))
+		}
+	}
+	if len(cS.cfg.CoreSCfg().StatSConns) != 0 {
+		cS.collectStatQueueMetrics(mr)
+	}
+}
+
+// collectStatQueueMetrics exports the metrics of the configured StatQueues as gauges
+func (cS *CoreService) collectStatQueueMetrics(mr *engine.MetricsRegistry) {
+	dfltTnt := cS.cfg.GeneralCfg().DefaultTenant
+	sqIDs := make([]*utils.TenantID, 0, len(cS.cfg.CoreSCfg().StatQueueIDs))
+	for _, sqID := range cS.cfg.CoreSCfg().StatQueueIDs {
+		tntID := utils.NewTenantID(sqID)
+		if tntID.Tenant == utils.EmptyString {
+			tntID.Tenant = dfltTnt
+		}
+		sqIDs = append(sqIDs, tntID)
+	}
+	if len(sqIDs) == 0 {
+		var qIDs []string
+		if err := cS.connMgr.Call(cS.cfg.CoreSCfg().StatSConns, nil,
+			utils.StatSv1GetQueueIDs, &utils.TenantWithOpts{Tenant: dfltTnt}, &qIDs); err != nil {
+			if err.Error() != utils.ErrNotFound.Error() {
+				utils.Logger.Warning(fmt.Sprintf("<%s> failed querying the StatQueue IDs, error: <%s>",
+					utils.CoreS, err.Error()))
+			}
+			return
+		}
+		for _, qID := range qIDs {
+			sqIDs = append(sqIDs, &utils.TenantID{Tenant: dfltTnt, ID: qID})
+		}
+	}
+	for _, tntID := range sqIDs {
+		var metrics map[string]float64
+		if err := cS.connMgr.Call(cS.cfg.CoreSCfg().StatSConns, nil,
+			utils.StatSv1GetQueueFloatMetrics, &utils.TenantIDWithOpts{TenantID: tntID}, &metrics); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> failed querying the StatQueue <%s>, error: <%s>",
+				utils.CoreS, tntID.TenantID(), err.Error()))
+			continue
+		}
+		for metricID, val := range metrics {
+			mr.SetGauge(utils.MetricStatQueueMetric, val,
+				utils.MetricTenant, tntID.Tenant, utils.MetricQueue, tntID.ID, utils.MetricID, metricID)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc"
+	"strings"
+	"testing"
+
+	"github.com/cenkalti/rpc2"
+	rpc2_jsonrpc "github.com/cenkalti/rpc2/jsonrpc"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestMetricsServerCodec(t *testing.T) {
+	engine.Metrics = engine.NewMetricsRegistry()
+	defer func() { engine.Metrics = nil }()
+	c := newMetricsServerCodec(new(mockServerCodec))
+	r := new(rpc.Request)
+	if err := c.ReadRequestHeader(r); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WriteResponse(&rpc.Response{Seq: r.Seq, Error: "NOT_FOUND"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := engine.Metrics.WriteMetrics(&sb); err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{
+		`cgrates_api_call_duration_seconds_count{method="CoreSv1.Ping"} 1`,
+		`cgrates_api_call_errors_total{method="CoreSv1.Ping"} 1`,
+	} {
+		if !strings.Contains(sb.String(), exp) {
+			t.Errorf("Expected %q in:\n%s", exp, sb.String())
+		}
+	}
+}
+
+func TestMetricsBiRPCCodec(t *testing.T) {
+	engine.Metrics = engine.NewMetricsRegistry()
+	defer func() { engine.Metrics = nil }()
+	s := NewServer(nil)
+	s.BiRPCRegisterName("TestSv1.GetValue", func(_ *rpc2.Client, args *utils.TenantWithOpts, reply *string) error {
+		return new(authTestService).GetValue(args, reply)
+	})
+	srvConn, clntConn := net.Pipe()
+	go s.birpcSrv.ServeCodec(newAuthBiRPCCodec(newMetricsBiRPCCodec(rpc2_jsonrpc.NewJSONCodec(srvConn)),
+		newTestAPIAuth(), "pipe", s.biRPCArgType))
+	clnt := rpc2.NewClientWithCodec(rpc2_jsonrpc.NewJSONCodec(clntConn))
+	go clnt.Run()
+	defer clnt.Close()
+
+	var reply string
+	if err := clnt.Call("TestSv1.GetValue", &utils.TenantWithOpts{Tenant: "cgrates.org"},
+		&reply); err == nil || err.Error() != utils.ErrNotAuthenticated.Error() {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotAuthenticated, err)
+	}
+	if err := clnt.Call("TestSv1.GetValue", &utils.TenantWithOpts{
+		Tenant: "cgrates.org",
+		Opts:   map[string]interface{}{utils.OptsAuthorization: "adminKey"},
+	}, &reply); err != nil {
+		t.Error(err)
+	}
+	var sb strings.Builder
+	if err := engine.Metrics.WriteMetrics(&sb); err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{
+		`cgrates_api_call_duration_seconds_count{method="TestSv1.GetValue"} 2`,
+		`cgrates_api_call_errors_total{method="TestSv1.GetValue"} 1`,
+	} {
+		if !strings.Contains(sb.String(), exp) {
+			t.Errorf("Expected %q in:\n%s", exp, sb.String())
+		}
+	}
+}
+
+func TestMetricsHTTPRequest(t *testing.T) {
+	engine.Metrics = engine.NewMetricsRegistry()
+	defer func() { engine.Metrics = nil }()
+	rpc.RegisterName("MetricsTestSv1", new(authTestService)) // already registered if the test is repeated
+	s := NewServer(engine.NewCaps(0, utils.MetaBusy))
+	rec := httptest.NewRecorder()
+	s.handleRequest(rec, httptest.NewRequest(http.MethodPost, "/jsonrpc",
+		strings.NewReader(`{"method":"MetricsTestSv1.GetValue","params":[{"Tenant":"cgrates.org"}],"id":1}`)))
+	if !strings.Contains(rec.Body.String(), `"result":"cgrates.org"`) {
+		t.Errorf("Unexpected reply: %s", rec.Body.String())
+	}
+	var sb strings.Builder
+	if err := engine.Metrics.WriteMetrics(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if exp := `cgrates_api_call_duration_seconds_count{method="MetricsTestSv1.GetValue"} 1`; !strings.Contains(sb.String(), exp) {
+		t.Errorf("Expected %q in:\n%s", exp, sb.String())
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.CoreSCfg().Caps = 2
+	caps := engine.NewCaps(2, utils.MetaBusy)
+	caps.Allocate()
+	cS := NewCoreService(cfg, caps, nil, nil)
+
+	rec := httptest.NewRecorder()
+	cS.MetricsHandler(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	rcv := rec.Body.String()
+	for _, exp := range []string{
+		"# TYPE cgrates_caps_allocated gauge\ncgrates_caps_allocated 1\n",
+		"cgrates_caps_limit 2\n",
+		"# TYPE cgrates_goroutines gauge\n",
+		`cgrates_cache_items{partition="*attribute_profiles"} 0`,
+		`cgrates_cache_hits_total{partition="*attribute_profiles"} `,
+	} {
+		if !strings.Contains(rcv, exp) {
+			t.Errorf("Expected %q in:\n%s", exp, rcv)
+		}
+	}
+}
@@ -37,6 +37,7 @@ import (
 
 	rpc2_jsonrpc "github.com/cenkalti/rpc2/jsonrpc"
 	"github.com/cgrates/cgrates/analyzers"
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
 
@@ -58,17 +59,30 @@ type Server struct {
 	rpcEnabled      bool
 	httpEnabled     bool
 	birpcSrv        *rpc2.Server
-	stopbiRPCServer chan struct{} // used in order to fully stop the biRPC
+	birpcArgTypes   map[string]reflect.Type // the arguments of the BiRPC handlers, used by the authorization
+	stopbiRPCServer chan struct{}           // used in order to fully stop the biRPC
 	httpsMux        *http.ServeMux
 	httpMux         *http.ServeMux
 	caps            *engine.Caps
 	anz             *analyzers.AnalyzerService
+	auth            *apiAuth
+	audit           *engine.AuditS
 }
 
 func (s *Server) SetAnalyzer(anz *analyzers.AnalyzerService) {
 	s.anz = anz
 }
 
+// SetAPIAuth enables the authorization of the API calls on all the listeners
+func (s *Server) SetAPIAuth(cfg *config.CGRConfig) {
+	s.auth = newAPIAuth(cfg)
+}
+
+// SetAuditS enables the audit of the API calls received on the RPC listeners, nil to disable it
+func (s *Server) SetAuditS(adt *engine.AuditS) {
+	s.audit = adt
+}
+
 func (s *Server) RpcRegister(rcvr interface{}) {
 	utils.RegisterRpcParams(utils.EmptyString, rcvr)
 	rpc.Register(rcvr)
@@ -97,6 +111,20 @@ func (s *Server) RegisterHttpFunc(pattern string, handler func(http.ResponseWrit
 	s.Unlock()
 }
 
+// RegisterHttpAuthFunc registers the handler behind the API authorization
+// the requests being authorized as calls of the method, without tenant
+func (s *Server) RegisterHttpAuthFunc(pattern, method string, handler func(http.ResponseWriter, *http.Request)) {
+	s.RegisterHttpFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if s.auth != nil {
+			if err := s.auth.authorizeHTTP(r, method, utils.EmptyString, false); err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		handler(w, r)
+	})
+}
+
 func (s *Server) RegisterHttpHandler(pattern string, handler http.Handler) {
 	if s.httpMux != nil {
 		s.httpMux.Handle(pattern, handler)
@@ -120,6 +148,7 @@ func (s *Server) BiRPCRegisterName(method string, handlerFunc interface{}) {
 		s.Unlock()
 	}
 	s.birpcSrv.Handle(method, handlerFunc)
+	s.setBiRPCArgType(method, reflect.TypeOf(handlerFunc))
 }
 
 func (s *Server) BiRPCRegister(rcvr interface{}) {
@@ -136,11 +165,12 @@ func (s *Server) BiRPCRegister(rcvr interface{}) {
 		method := rcvType.Method(i)
 		if method.Name != "Call" {
 			s.birpcSrv.Handle("SMGenericV1."+method.Name, method.Func.Interface())
+			s.setBiRPCArgType("SMGenericV1."+method.Name, method.Type)
 		}
 	}
 }
 
-func (s *Server) serveCodec(addr, codecName string, newCodec func(conn conn, caps *engine.Caps, anz *analyzers.AnalyzerService) rpc.ServerCodec,
+func (s *Server) serveCodec(addr, codecName string, newCodec func(conn conn, caps *engine.Caps, anz *analyzers.AnalyzerService, auth *apiAuth, adt *engine.AuditS) rpc.ServerCodec,
 	shdChan *utils.SyncedChan) {
 	s.RLock()
 	enabled := s.rpcEnabled
@@ -159,7 +189,7 @@ func (s *Server) serveCodec(addr, codecName string, newCodec func(conn conn, cap
 	s.accept(l, codecName, newCodec, shdChan)
 }
 
-func (s *Server) accept(l net.Listener, codecName string, newCodec func(conn conn, caps *engine.Caps, anz *analyzers.AnalyzerService) rpc.ServerCodec,
+func (s *Server) accept(l net.Listener, codecName string, newCodec func(conn conn, caps *engine.Caps, anz *analyzers.AnalyzerService, auth *apiAuth, adt *engine.AuditS) rpc.ServerCodec,
 	shdChan *utils.SyncedChan) {
 	errCnt := 0
 	var lastErrorTime time.Time
@@ -179,7 +209,7 @@ func (s *Server) accept(l net.Listener, codecName string, newCodec func(conn con
 			}
 			continue
 		}
-		go rpc.ServeCodec(newCodec(conn, s.caps, s.anz))
+		go rpc.ServeCodec(newCodec(conn, s.caps, s.anz, s.auth, s.audit))
 	}
 }
 
@@ -196,7 +226,7 @@ func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	rmtIP, _ := utils.GetRemoteIP(r)
 	rmtAddr, _ := net.ResolveIPAddr(utils.EmptyString, rmtIP)
-	res := newRPCRequest(r.Body, rmtAddr, s.caps, s.anz).Call()
+	res := newRPCRequest(r, rmtAddr, s.caps, s.anz, s.auth, s.audit).Call()
 	io.Copy(w, res)
 }
 
@@ -248,7 +278,7 @@ func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string,
 		s.Unlock()
 		utils.Logger.Info("<HTTP> enabling handler for WebSocket connections")
 		wsHandler := websocket.Handler(func(ws *websocket.Conn) {
-			rpc.ServeCodec(newCapsJSONCodec(ws, s.caps, s.anz))
+			rpc.ServeCodec(newCapsJSONCodec(ws, s.caps, s.anz, s.auth, s.audit))
 		})
 		if useBasicAuth {
 			s.httpMux.HandleFunc(wsRPCURL, use(wsHandler.ServeHTTP, basicAuth(userList)))
@@ -269,6 +299,27 @@ func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string,
 	}
 }
 
+// setBiRPCArgType saves the type of the arguments out of the handler function type
+func (s *Server) setBiRPCArgType(method string, fnType reflect.Type) {
+	if fnType.Kind() != reflect.Func || fnType.NumIn() < 2 {
+		return
+	}
+	s.Lock()
+	if s.birpcArgTypes == nil {
+		s.birpcArgTypes = make(map[string]reflect.Type)
+	}
+	s.birpcArgTypes[method] = fnType.In(fnType.NumIn() - 2) // the handlers end with args and reply
+	s.Unlock()
+}
+
+// biRPCArgType returns the type of the arguments expected by the BiRPC handler, nil if not handled
+func (s *Server) biRPCArgType(method string) (argType reflect.Type) {
+	s.RLock()
+	argType = s.birpcArgTypes[method]
+	s.RUnlock()
+	return
+}
+
 // ServeBiJSON create a gorutine to listen and serve as BiRPC server
 func (s *Server) ServeBiJSON(addr string, onConn func(*rpc2.Client), onDis func(*rpc2.Client)) (err error) {
 	s.RLock()
@@ -297,7 +348,10 @@ func (s *Server) ServeBiJSON(addr string, onConn func(*rpc2.Client), onDis func(
 				log.Fatal(err)
 				return // stop if we get Accept error
 			}
-			go s.birpcSrv.ServeCodec(rpc2_jsonrpc.NewJSONCodec(conn))
+			rmtAddr := conn.RemoteAddr().String()
+			go s.birpcSrv.ServeCodec(newAuditBiRPCCodec(newAuthBiRPCCodec(
+				newMetricsBiRPCCodec(rpc2_jsonrpc.NewJSONCodec(conn)), // measures the calls denied as well
+				s.auth, rmtAddr, s.biRPCArgType), s.audit, rmtAddr))
 		}
 	}(lBiJSON)
 	<-s.stopbiRPCServer // wait until server is stoped to close the listener
@@ -318,16 +372,23 @@ type rpcRequest struct {
 	remoteAddr net.Addr
 	caps       *engine.Caps
 	anzWarpper *analyzers.AnalyzerService
+	httpReq    *http.Request // used for the authorization headers
+	auth       *apiAuth
+	audit      *engine.AuditS
 }
 
 // newRPCRequest returns a new rpcRequest.
-func newRPCRequest(r io.ReadCloser, remoteAddr net.Addr, caps *engine.Caps, anz *analyzers.AnalyzerService) *rpcRequest {
+func newRPCRequest(r *http.Request, remoteAddr net.Addr, caps *engine.Caps, anz *analyzers.AnalyzerService,
+	auth *apiAuth, adt *engine.AuditS) *rpcRequest {
 	return &rpcRequest{
-		r:          r,
+		r:          r.Body,
 		rw:         new(bytes.Buffer),
 		remoteAddr: remoteAddr,
 		caps:       caps,
 		anzWarpper: anz,
+		httpReq:    r,
+		auth:       auth,
+		audit:      adt,
 	}
 }
 
@@ -350,9 +411,14 @@ func (r *rpcRequest) Close() error {
 	return r.r.Close()
 }
 
+// Request returns the HTTP request carrying the RPC request
+func (r *rpcRequest) Request() *http.Request {
+	return r.httpReq
+}
+
 // Call invokes the RPC request, waits for it to complete, and returns the results.
 func (r *rpcRequest) Call() io.Reader {
-	rpc.ServeCodec(newCapsJSONCodec(r, r.caps, r.anzWarpper))
+	rpc.ServeCodec(newCapsJSONCodec(r, r.caps, r.anzWarpper, r.auth, r.audit))
 	return r.rw
 }
 
@@ -435,7 +501,7 @@ func (s *Server) ServeGOBTLS(addr, serverCrt, serverKey, caCert string,
 			}
 			continue
 		}
-		go rpc.ServeCodec(newCapsGOBCodec(conn, s.caps, s.anz))
+		go rpc.ServeCodec(newCapsGOBCodec(conn, s.caps, s.anz, s.auth, s.audit))
 	}
 }
 
@@ -478,7 +544,7 @@ func (s *Server) ServeJSONTLS(addr, serverCrt, serverKey, caCert string,
 			}
 			continue
 		}
-		go rpc.ServeCodec(newCapsJSONCodec(conn, s.caps, s.anz))
+		go rpc.ServeCodec(newCapsJSONCodec(conn, s.caps, s.anz, s.auth, s.audit))
 	}
 }
 
@@ -508,7 +574,7 @@ func (s *Server) ServeHTTPTLS(addr, serverCrt, serverKey, caCert string, serverP
 		s.Unlock()
 		utils.Logger.Info("<HTTPS> enabling handler for WebSocket connections")
 		wsHandler := websocket.Handler(func(ws *websocket.Conn) {
-			rpc.ServeCodec(newCapsJSONCodec(ws, s.caps, s.anz))
+			rpc.ServeCodec(newCapsJSONCodec(ws, s.caps, s.anz, s.auth, s.audit))
 		})
 		if useBasicAuth {
 			s.httpsMux.HandleFunc(wsRPCURL, use(wsHandler.ServeHTTP, basicAuth(userList)))
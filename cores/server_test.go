@@ -125,6 +125,40 @@ func TestRegisterHttpFunc(t *testing.T) {
 	rcv.StopBiRPC()
 }
 
+func TestRegisterHttpAuthFunc(t *testing.T) {
+	s := NewServer(nil)
+	s.RegisterHttpAuthFunc("/metrics", utils.CoreSv1Metrics, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(s.httpMux)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rply, err := http.DefaultClient.Do(req); err != nil { // without auth the handler is open
+		t.Fatal(err)
+	} else if rply.Body.Close(); rply.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, received: %d", http.StatusOK, rply.StatusCode)
+	}
+	s.auth = newTestAPIAuth()
+	for _, key := range []string{utils.EmptyString, "unknownKey", "resellerKey"} {
+		req.Header.Set("X-API-Key", key)
+		if rply, err := http.DefaultClient.Do(req); err != nil {
+			t.Fatal(err)
+		} else if rply.Body.Close(); rply.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status %d for %q, received: %d", http.StatusUnauthorized, key, rply.StatusCode)
+		}
+	}
+	req.Header.Set("X-API-Key", "adminKey")
+	if rply, err := http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	} else if rply.Body.Close(); rply.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, received: %d", http.StatusOK, rply.StatusCode)
+	}
+}
+
 func TestBiRPCRegisterName(t *testing.T) {
 	cfgDflt := config.NewDefaultCGRConfig()
 	cfgDflt.CoreSCfg().CapsStatsInterval = 1
@@ -0,0 +1,90 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"net/rpc"
+	"sync"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// newTraceServerCodec starts a server span for each API call read by the codec
+// the span continues the trace received in the *traceparent option and replaces it
+// so the calls made while serving the request become its children
+func newTraceServerCodec(sc rpc.ServerCodec, conn conn) rpc.ServerCodec {
+	if engine.Tracer == nil {
+		return sc
+	}
+	c := &traceServerCodec{
+		sc:    sc,
+		spans: make(map[uint64]*engine.Span),
+	}
+	if from := conn.RemoteAddr(); from != nil {
+		c.remote = from.String()
+	}
+	return c
+}
+
+type traceServerCodec struct {
+	sc      rpc.ServerCodec
+	remote  string
+	method  string // the method of the request being read
+	seq     uint64 // the sequence of the request being read
+	spans   map[uint64]*engine.Span
+	spansLk sync.Mutex
+}
+
+func (c *traceServerCodec) ReadRequestHeader(r *rpc.Request) (err error) {
+	if err = c.sc.ReadRequestHeader(r); err != nil {
+		return
+	}
+	c.method, c.seq = r.ServiceMethod, r.Seq
+	return
+}
+
+func (c *traceServerCodec) ReadRequestBody(x interface{}) (err error) {
+	if err = c.sc.ReadRequestBody(x); x == nil { // body discarded by the rpc.Server
+		return
+	}
+	_, _, opts := apiCallArgs(x)
+	span := engine.Tracer.StartSpan(c.method, engine.SpanKindServer,
+		utils.IfaceAsString(opts[utils.OptsTraceParent]))
+	span.SetRPCAttributes(c.method)
+	span.SetAttribute(utils.TraceNetPeerName, c.remote)
+	if opts != nil {
+		opts[utils.OptsTraceParent] = span.TraceParent()
+	}
+	c.spansLk.Lock()
+	c.spans[c.seq] = span
+	c.spansLk.Unlock()
+	return
+}
+
+func (c *traceServerCodec) WriteResponse(r *rpc.Response, x interface{}) error {
+	c.spansLk.Lock()
+	span := c.spans[r.Seq]
+	delete(c.spans, r.Seq)
+	c.spansLk.Unlock()
+	span.EndWithError(r.Error)
+	return c.sc.WriteResponse(r, x)
+}
+
+func (c *traceServerCodec) Close() error { return c.sc.Close() }
@@ -0,0 +1,139 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cores
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+type tracingTestService struct {
+	traceParent string
+}
+
+func (s *tracingTestService) ProcessEvent(args *utils.CGREvent, reply *string) (err error) {
+	s.traceParent = utils.IfaceAsString(args.Opts[utils.OptsTraceParent])
+	if args.ID == utils.EmptyString {
+		return utils.NewErrMandatoryIeMissing(utils.ID)
+	}
+	*reply = utils.OK
+	return
+}
+
+func TestTraceServerCodec(t *testing.T) {
+	if sc := newTraceServerCodec(nil, nil); sc != nil {
+		t.Errorf("Expected the codec unchanged when tracing is disabled, received %T", sc)
+	}
+	tmpDir, err := ioutil.TempDir(utils.EmptyString, "tracing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	cfg := config.NewDefaultCGRConfig()
+	cfg.CoreSCfg().TraceExporter = utils.MetaFile
+	cfg.CoreSCfg().TraceExportPath = path.Join(tmpDir, "traces.json")
+	if engine.Tracer, err = engine.NewTracerS(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { engine.Tracer = nil }()
+
+	srvc := new(tracingTestService)
+	srv := rpc.NewServer()
+	if err = srv.RegisterName("TestSv1", srvc); err != nil {
+		t.Fatal(err)
+	}
+	srvConn, clntConn := net.Pipe()
+	go srv.ServeCodec(newCapsJSONCodec(srvConn, engine.NewCaps(0, utils.MetaBusy), nil, nil, nil))
+	clnt := jsonrpc.NewClient(clntConn)
+	defer clnt.Close()
+
+	callerTP := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	var reply string
+	if err = clnt.Call("TestSv1.ProcessEvent", &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "EV1",
+		Opts:   map[string]interface{}{utils.OptsTraceParent: callerTP},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	// the service continues the trace from the server span
+	srvcTP := srvc.traceParent
+	if srvcTP == callerTP ||
+		!strings.HasPrefix(srvcTP, "00-4bf92f3577b34da6a3ce929d0e0e4736-") {
+		t.Errorf("Unexpected traceparent: %q", srvcTP)
+	}
+	if err = clnt.Call("TestSv1.ProcessEvent", &utils.CGREvent{Tenant: "cgrates.org"}, &reply); err == nil {
+		t.Error("Expected error")
+	}
+	if err = engine.Tracer.Export(); err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadFile(cfg.CoreSCfg().TraceExportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var traces struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+					Kind         int    `json:"kind"`
+					Status       struct {
+						Message string `json:"message"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err = json.Unmarshal(body, &traces); err != nil {
+		t.Fatal(err)
+	}
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, received: %s", body)
+	}
+	if spans[0].Name != "TestSv1.ProcessEvent" ||
+		spans[0].Kind != engine.SpanKindServer ||
+		spans[0].TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" ||
+		spans[0].ParentSpanID != "00f067aa0ba902b7" ||
+		!strings.Contains(srvcTP, spans[0].SpanID) ||
+		spans[0].Status.Message != utils.EmptyString {
+		t.Errorf("Unexpected span: %s", utils.ToJSON(spans[0]))
+	}
+	// a new trace is started without context
+	if spans[1].TraceID == spans[0].TraceID ||
+		spans[1].ParentSpanID != utils.EmptyString ||
+		spans[1].Status.Message != utils.NewErrMandatoryIeMissing(utils.ID).Error() {
+		t.Errorf("Unexpected span: %s", utils.ToJSON(spans[1]))
+	}
+}
@@ -50,3 +50,43 @@ CREATE TABLE session_costs (
   KEY run_origin_idx (run_id, origin_id),
   KEY deleted_at_idx (deleted_at)
 );
+
+DROP TABLE IF EXISTS audit_log;
+CREATE TABLE audit_log (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  audit_id varchar(40) NOT NULL,
+  method varchar(128) NOT NULL,
+  subject varchar(128) NOT NULL,
+  role varchar(64) NOT NULL,
+  remote_addr varchar(64) NOT NULL,
+  tenant varchar(64) NOT NULL,
+  item_id varchar(256) NOT NULL,
+  args MEDIUMTEXT,
+  before_snapshot MEDIUMTEXT,
+  after_snapshot MEDIUMTEXT,
+  diff MEDIUMTEXT,
+  error text,
+  created_at TIMESTAMP NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY auditid (audit_id),
+  KEY item_idx (tenant, item_id),
+  KEY created_at_idx (created_at)
+);
+
+DROP TABLE IF EXISTS invoices;
+CREATE TABLE invoices (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  tenant varchar(64) NOT NULL,
+  invoice_id varchar(64) NOT NULL,
+  number int(11) NOT NULL,
+  account varchar(128) NOT NULL,
+  cycle_start TIMESTAMP NULL,
+  cycle_end TIMESTAMP NULL,
+  line_items MEDIUMTEXT,
+  total DECIMAL(20,4),
+  created_at TIMESTAMP NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY invoiceid (tenant, invoice_id),
+  UNIQUE KEY invoice_number (tenant, number),
+  UNIQUE KEY account_cycle (tenant, account, cycle_start)
+);
@@ -57,3 +57,44 @@ DROP INDEX IF EXISTS run_origin_sessionscost_idx;
 CREATE INDEX run_origin_sessionscost_idx ON session_costs (run_id, origin_id);
 DROP INDEX IF EXISTS deleted_at_sessionscost_idx;
 CREATE INDEX deleted_at_sessionscost_idx ON session_costs (deleted_at);
+
+DROP TABLE IF EXISTS audit_log;
+CREATE TABLE audit_log (
+  id SERIAL PRIMARY KEY,
+  audit_id VARCHAR(40) NOT NULL,
+  method VARCHAR(128) NOT NULL,
+  subject VARCHAR(128) NOT NULL,
+  role VARCHAR(64) NOT NULL,
+  remote_addr VARCHAR(64) NOT NULL,
+  tenant VARCHAR(64) NOT NULL,
+  item_id VARCHAR(256) NOT NULL,
+  args TEXT,
+  before_snapshot TEXT,
+  after_snapshot TEXT,
+  diff TEXT,
+  error TEXT,
+  created_at TIMESTAMP WITH TIME ZONE,
+  UNIQUE (audit_id)
+);
+DROP INDEX IF EXISTS item_auditlog_idx;
+CREATE INDEX item_auditlog_idx ON audit_log (tenant, item_id);
+DROP INDEX IF EXISTS created_at_auditlog_idx;
+CREATE INDEX created_at_auditlog_idx ON audit_log (created_at);
+
+DROP TABLE IF EXISTS invoices;
+CREATE TABLE invoices (
+  id SERIAL PRIMARY KEY,
+  tenant VARCHAR(64) NOT NULL,
+  invoice_id VARCHAR(64) NOT NULL,
+  number INTEGER NOT NULL,
+  account VARCHAR(128) NOT NULL,
+  cycle_start TIMESTAMP WITH TIME ZONE,
+  cycle_end TIMESTAMP WITH TIME ZONE,
+  line_items TEXT,
+  total NUMERIC(20,4),
+  created_at TIMESTAMP WITH TIME ZONE,
+  UNIQUE (tenant, invoice_id),
+  UNIQUE (tenant, number)
+);
+DROP INDEX IF EXISTS account_cycle_invoices_idx;
+CREATE UNIQUE INDEX account_cycle_invoices_idx ON invoices (tenant, account, cycle_start);
@@ -188,6 +188,9 @@ func testDspChcPrecacheStatus(t *testing.T) {
 		utils.CacheChargerProfiles:              utils.MetaReady,
 		utils.CacheDispatcherProfiles:           utils.MetaReady,
 		utils.CacheDispatcherHosts:              utils.MetaReady,
+		utils.CachePortedNumbers:                utils.MetaReady,
+		utils.CacheSharedSessions:               utils.MetaReady,
+		utils.CacheConfigSections:               utils.MetaReady,
 		utils.CacheDiameterMessages:             utils.MetaReady,
 		utils.CacheAttributeFilterIndexes:       utils.MetaReady,
 		utils.CacheResourceFilterIndexes:        utils.MetaReady,
@@ -238,6 +241,8 @@ func testDspChcPrecacheStatus(t *testing.T) {
 		utils.CacheTBLTPFilters:          utils.MetaReady,
 		utils.CacheSessionCostsTBL:       utils.MetaReady,
 		utils.CacheCDRsTBL:               utils.MetaReady,
+		utils.CacheAuditLogTBL:           utils.MetaReady,
+		utils.CacheInvoicesTBL:           utils.MetaReady,
 		utils.CacheTBLTPRoutes:           utils.MetaReady,
 		utils.CacheTBLTPAttributes:       utils.MetaReady,
 		utils.CacheTBLTPChargers:         utils.MetaReady,
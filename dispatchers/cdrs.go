@@ -81,6 +81,42 @@ func (dS *DispatcherService) CDRsV1GetCDRsCount(args *utils.RPCCDRsFilterWithOpt
 	}, utils.MetaCDRs, utils.CDRsV1GetCDRsCount, args, reply)
 }
 
+// CDRsV1GetCDRsAggregates groups the cdrs that match the filter
+func (dS *DispatcherService) CDRsV1GetCDRsAggregates(args *utils.RPCCDRsAggregationWithOpts, reply *[]*utils.CDRsAggregate) (err error) {
+	tnt := dS.cfg.GeneralCfg().DefaultTenant
+	if args.Tenant != utils.EmptyString {
+		tnt = args.Tenant
+	}
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if err = dS.authorize(utils.CDRsV1GetCDRsAggregates, tnt,
+			utils.IfaceAsString(args.Opts[utils.OptsAPIKey]), utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	return dS.Dispatch(&utils.CGREvent{
+		Tenant: tnt,
+		Opts:   args.Opts,
+	}, utils.MetaCDRs, utils.CDRsV1GetCDRsAggregates, args, reply)
+}
+
+// CDRsV1GetWholesaleMargins returns the margins of the paired CDRs that match the filter
+func (dS *DispatcherService) CDRsV1GetWholesaleMargins(args *engine.ArgsWholesaleMargins, reply *[]*engine.WholesaleMargin) (err error) {
+	tnt := dS.cfg.GeneralCfg().DefaultTenant
+	if args.Tenant != utils.EmptyString {
+		tnt = args.Tenant
+	}
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if err = dS.authorize(utils.CDRsV1GetWholesaleMargins, tnt,
+			utils.IfaceAsString(args.Opts[utils.OptsAPIKey]), utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	return dS.Dispatch(&utils.CGREvent{
+		Tenant: tnt,
+		Opts:   args.Opts,
+	}, utils.MetaCDRs, utils.CDRsV1GetWholesaleMargins, args, reply)
+}
+
 func (dS *DispatcherService) CDRsV1StoreSessionCost(args *engine.AttrCDRSStoreSMCost, reply *string) (err error) {
 	tnt := dS.cfg.GeneralCfg().DefaultTenant
 	if args.Tenant != utils.EmptyString {
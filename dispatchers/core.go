@@ -73,3 +73,21 @@ func (dS *DispatcherService) CoreSv1Sleep(args *utils.DurationArgs,
 		Opts:   args.Opts,
 	}, utils.MetaCore, utils.CoreSv1Sleep, args, reply)
 }
+
+func (dS *DispatcherService) CoreSv1Snapshot(args *utils.TenantWithOpts,
+	reply *string) (err error) {
+	tnt := dS.cfg.GeneralCfg().DefaultTenant
+	if args.Tenant != utils.EmptyString {
+		tnt = args.Tenant
+	}
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if err = dS.authorize(utils.CoreSv1Snapshot, tnt,
+			utils.IfaceAsString(args.Opts[utils.OptsAPIKey]), utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	return dS.Dispatch(&utils.CGREvent{
+		Tenant: tnt,
+		Opts:   args.Opts,
+	}, utils.MetaCore, utils.CoreSv1Snapshot, args, reply)
+}
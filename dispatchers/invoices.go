@@ -0,0 +1,69 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func (dS *DispatcherService) InvoiceSv1Ping(args *utils.CGREvent, rpl *string) (err error) {
+	if args == nil {
+		args = new(utils.CGREvent)
+	}
+	args.Tenant = utils.FirstNonEmpty(args.Tenant, dS.cfg.GeneralCfg().DefaultTenant)
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if err = dS.authorize(utils.InvoiceSv1Ping, args.Tenant,
+			utils.IfaceAsString(args.Opts[utils.OptsAPIKey]), args.Time); err != nil {
+			return
+		}
+	}
+	return dS.Dispatch(args, utils.InvoiceS, utils.InvoiceSv1Ping, args, rpl)
+}
+
+func (dS *DispatcherService) InvoiceSv1GenerateInvoice(args *utils.ArgsGenerateInvoice, reply *engine.Invoice) (err error) {
+	tnt := utils.FirstNonEmpty(args.Tenant, dS.cfg.GeneralCfg().DefaultTenant)
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if err = dS.authorize(utils.InvoiceSv1GenerateInvoice, tnt,
+			utils.IfaceAsString(args.Opts[utils.OptsAPIKey]), utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	return dS.Dispatch(&utils.CGREvent{
+		Tenant: tnt,
+		ID:     args.Account,
+		Opts:   args.Opts,
+	}, utils.InvoiceS, utils.InvoiceSv1GenerateInvoice, args, reply)
+}
+
+func (dS *DispatcherService) InvoiceSv1GetInvoices(args *utils.InvoicesFilterWithOpts, reply *[]*engine.Invoice) (err error) {
+	tnt := utils.FirstNonEmpty(args.Tenant, dS.cfg.GeneralCfg().DefaultTenant)
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if err = dS.authorize(utils.InvoiceSv1GetInvoices, tnt,
+			utils.IfaceAsString(args.Opts[utils.OptsAPIKey]), utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	return dS.Dispatch(&utils.CGREvent{
+		Tenant: tnt,
+		Opts:   args.Opts,
+	}, utils.InvoiceS, utils.InvoiceSv1GetInvoices, args, reply)
+}
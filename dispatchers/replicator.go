@@ -1467,3 +1467,57 @@ func (dS *DispatcherService) ReplicatorSv1RemoveAccountProfile(args *utils.Tenan
 		Opts:   args.Opts,
 	}, utils.MetaReplicator, utils.ReplicatorSv1RemoveAccountProfile, args, rpl)
 }
+
+func (dS *DispatcherService) ReplicatorSv1GetPortedNumber(args *utils.TenantIDWithOpts, reply *engine.PortedNumber) (err error) {
+	tnt := dS.cfg.GeneralCfg().DefaultTenant
+	if args.TenantID != nil && args.TenantID.Tenant != utils.EmptyString {
+		tnt = args.TenantID.Tenant
+	}
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if err = dS.authorize(utils.ReplicatorSv1GetPortedNumber, tnt,
+			utils.IfaceAsString(args.Opts[utils.OptsAPIKey]), utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	return dS.Dispatch(&utils.CGREvent{
+		Tenant: tnt,
+		ID:     args.ID,
+		Opts:   args.Opts,
+	}, utils.MetaReplicator, utils.ReplicatorSv1GetPortedNumber, args, reply)
+}
+
+func (dS *DispatcherService) ReplicatorSv1SetPortedNumber(args *engine.PortedNumberWithOpts, rpl *string) (err error) {
+	if args == nil {
+		args = &engine.PortedNumberWithOpts{
+			PortedNumber: &engine.PortedNumber{},
+		}
+	}
+	args.Tenant = utils.FirstNonEmpty(args.Tenant, dS.cfg.GeneralCfg().DefaultTenant)
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if err = dS.authorize(utils.ReplicatorSv1SetPortedNumber, args.Tenant,
+			utils.IfaceAsString(args.Opts[utils.OptsAPIKey]), utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	return dS.Dispatch(&utils.CGREvent{
+		Tenant: args.Tenant,
+		Opts:   args.Opts,
+	}, utils.MetaReplicator, utils.ReplicatorSv1SetPortedNumber, args, rpl)
+}
+
+func (dS *DispatcherService) ReplicatorSv1RemovePortedNumber(args *utils.TenantIDWithOpts, rpl *string) (err error) {
+	if args == nil {
+		args = &utils.TenantIDWithOpts{}
+	}
+	args.Tenant = utils.FirstNonEmpty(args.Tenant, dS.cfg.GeneralCfg().DefaultTenant)
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if err = dS.authorize(utils.ReplicatorSv1RemovePortedNumber, args.Tenant,
+			utils.IfaceAsString(args.Opts[utils.OptsAPIKey]), utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	return dS.Dispatch(&utils.CGREvent{
+		Tenant: args.Tenant,
+		Opts:   args.Opts,
+	}, utils.MetaReplicator, utils.ReplicatorSv1RemovePortedNumber, args, rpl)
+}
@@ -46,6 +46,8 @@ func NewEventExporter(cgrCfg *config.CGRConfig, cfgIdx int, filterS *engine.Filt
 		return NewFileCSVee(cgrCfg, cfgIdx, filterS, dc)
 	case utils.MetaFileFWV:
 		return NewFileFWVee(cgrCfg, cfgIdx, filterS, dc)
+	case utils.MetaFileTemplate:
+		return NewFileTemplateEe(cgrCfg, cfgIdx, filterS, dc)
 	case utils.MetaHTTPPost:
 		return NewHTTPPostEe(cgrCfg, cfgIdx, filterS, dc)
 	case utils.MetaHTTPjsonMap:
@@ -215,12 +215,16 @@ func (eeS *EventExporterS) V1ProcessEvent(cgrEv *utils.CGREventWithEeIDs, rply *
 					utils.EventExporterS, ee.ID()))
 		}
 		go func(evict, sync bool, ee EventExporter) {
+			status := utils.MetricProcessed
 			if err := ee.ExportEvent(cgrEv.CGREvent); err != nil {
 				utils.Logger.Warning(
 					fmt.Sprintf("<%s> with id <%s>, error: <%s>",
 						utils.EventExporterS, ee.ID(), err.Error()))
 				withErr = true
+				status = utils.MetricFailed
 			}
+			engine.Metrics.IncCounter(utils.MetricEEsEvents,
+				utils.MetricExporter, ee.ID(), utils.MetricStatus, status)
 			if evict {
 				ee.OnEvicted("", nil) // so we can close ie the file
 			}
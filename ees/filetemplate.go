@@ -0,0 +1,131 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ees
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewFileTemplateEe(cgrCfg *config.CGRConfig, cfgIdx int, filterS *engine.FilterS,
+	dc utils.MapStorage) (fTmpl *FileTemplateEe, err error) {
+	fTmpl = &FileTemplateEe{id: cgrCfg.EEsCfg().Exporters[cfgIdx].ID,
+		cgrCfg: cgrCfg, cfgIdx: cfgIdx, filterS: filterS, dc: dc}
+	err = fTmpl.init()
+	return
+}
+
+// eventTemplate is implemented by both text/template and html/template
+type eventTemplate interface {
+	Execute(wr io.Writer, data interface{}) error
+}
+
+// FileTemplateEe implements EventExporter interface, rendering each event into its own file
+// out of a Go template (html/template for .html files, text/template otherwise)
+type FileTemplateEe struct {
+	id      string
+	cgrCfg  *config.CGRConfig
+	cfgIdx  int // index of config instance within EEsCfg.Exporters
+	filterS *engine.FilterS
+	tmpl    eventTemplate
+	ext     string // extension of the template, used for the exported files
+	sync.RWMutex
+	dc utils.MapStorage
+}
+
+// init will parse the template out of the templatePath option
+func (fTmpl *FileTemplateEe) init() (err error) {
+	iface, has := fTmpl.cgrCfg.EEsCfg().Exporters[fTmpl.cfgIdx].Opts[utils.TemplatePath]
+	if !has {
+		return utils.NewErrMandatoryIeMissing(utils.TemplatePath)
+	}
+	tmplPath := utils.IfaceAsString(iface)
+	fTmpl.ext = path.Ext(tmplPath)
+	fMap := map[string]interface{}{"json": utils.ToJSON}
+	switch strings.ToLower(fTmpl.ext) {
+	case utils.HTMLSuffix, utils.HTMSuffix:
+		fTmpl.tmpl, err = htmltemplate.New(path.Base(tmplPath)).Funcs(fMap).ParseFiles(tmplPath)
+	default:
+		fTmpl.tmpl, err = texttemplate.New(path.Base(tmplPath)).Funcs(fMap).ParseFiles(tmplPath)
+	}
+	return
+}
+
+// ID returns the identificator of this exporter
+func (fTmpl *FileTemplateEe) ID() string {
+	return fTmpl.id
+}
+
+// OnEvicted implements EventExporter, doing the cleanup before exit
+func (fTmpl *FileTemplateEe) OnEvicted(_ string, _ interface{}) {
+	return
+}
+
+// fileNameSeps would let the events write their files outside of the export path
+const fileNameSeps = "/\\\x00"
+
+// ExportEvent implements EventExporter
+func (fTmpl *FileTemplateEe) ExportEvent(cgrEv *utils.CGREvent) (err error) {
+	fTmpl.Lock()
+	defer func() {
+		if err != nil {
+			fTmpl.dc[utils.NegativeExports].(utils.StringSet).Add(cgrEv.ID)
+		} else {
+			fTmpl.dc[utils.PositiveExports].(utils.StringSet).Add(cgrEv.ID)
+		}
+		fTmpl.Unlock()
+	}()
+	fTmpl.dc[utils.NumberOfEvents] = fTmpl.dc[utils.NumberOfEvents].(int64) + 1
+
+	tnt := utils.FirstNonEmpty(cgrEv.Tenant, fTmpl.cgrCfg.GeneralCfg().DefaultTenant)
+	if strings.ContainsAny(tnt, fileNameSeps) || strings.ContainsAny(cgrEv.ID, fileNameSeps) {
+		return fmt.Errorf("tenant <%s> with ID <%s> not allowed in the file name", tnt, cgrEv.ID)
+	}
+	filePath := path.Join(fTmpl.cgrCfg.EEsCfg().Exporters[fTmpl.cfgIdx].ExportPath,
+		strings.Join([]string{fTmpl.id, tnt, cgrEv.ID}, utils.Underline)+fTmpl.ext) // the IDs are unique only per tenant
+	var file *os.File
+	if file, err = os.Create(filePath); err != nil {
+		return
+	}
+	if err = fTmpl.tmpl.Execute(file, cgrEv.Event); err != nil {
+		file.Close()
+		return
+	}
+	if err = file.Close(); err != nil {
+		return
+	}
+	fTmpl.dc[utils.ExportPath] = filePath
+	updateEEMetrics(fTmpl.dc, cgrEv.Event, utils.FirstNonEmpty(fTmpl.cgrCfg.EEsCfg().Exporters[fTmpl.cfgIdx].Timezone,
+		fTmpl.cgrCfg.GeneralCfg().DefaultTimezone))
+	return
+}
+
+func (fTmpl *FileTemplateEe) GetMetrics() utils.MapStorage {
+	return fTmpl.dc.Clone()
+}
@@ -0,0 +1,99 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ees
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestFileTemplateEeExportEvent(t *testing.T) {
+	dir, err := ioutil.TempDir(utils.EmptyString, "TestFileTemplateEe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, tmpl := range []struct{ name, content string }{
+		{"invoice.html", `<p>{{.ID}}</p>{{range .Lines}}<p>{{.Description}}</p>{{end}}`},
+		{"invoice.json", `{{json .}}`},
+	} {
+		if err := ioutil.WriteFile(path.Join(dir, tmpl.name), []byte(tmpl.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cgrCfg := config.NewDefaultCGRConfig()
+	cgrCfg.EEsCfg().Exporters[0].ID = "INV"
+	cgrCfg.EEsCfg().Exporters[0].Type = utils.MetaFileTemplate
+	cgrCfg.EEsCfg().Exporters[0].ExportPath = dir
+	if _, err := NewEventExporter(cgrCfg, 0, nil); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing(utils.TemplatePath).Error() {
+		t.Errorf("Expected error, received: %v", err)
+	}
+
+	cgrEv := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "INV000001",
+		Event: map[string]interface{}{
+			utils.ID:    "INV000001",
+			utils.Lines: []map[string]interface{}{{"Description": "<b>AP_SUBSCRIPTION:FEE</b>"}},
+			utils.Total: 9.99,
+		},
+	}
+	cgrCfg.EEsCfg().Exporters[0].Opts[utils.TemplatePath] = path.Join(dir, "invoice.html")
+	ee, err := NewEventExporter(cgrCfg, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ee.ExportEvent(cgrEv); err != nil {
+		t.Fatal(err)
+	}
+	exp := "<p>INV000001</p><p>&lt;b&gt;AP_SUBSCRIPTION:FEE&lt;/b&gt;</p>"
+	if rcv, err := ioutil.ReadFile(path.Join(dir, "INV_cgrates.org_INV000001.html")); err != nil {
+		t.Error(err)
+	} else if string(rcv) != exp {
+		t.Errorf("Expected %q, received: %q", exp, string(rcv))
+	}
+	if nrEvs := ee.GetMetrics()[utils.NumberOfEvents]; nrEvs != int64(1) {
+		t.Errorf("Expected 1 event, received: %v", nrEvs)
+	}
+
+	cgrCfg.EEsCfg().Exporters[0].Opts[utils.TemplatePath] = path.Join(dir, "invoice.json")
+	if ee, err = NewEventExporter(cgrCfg, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := ee.ExportEvent(cgrEv); err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := ioutil.ReadFile(path.Join(dir, "INV_cgrates.org_INV000001.json")); err != nil {
+		t.Error(err)
+	} else if string(rcv) != utils.ToJSON(cgrEv.Event) {
+		t.Errorf("Expected %q, received: %q", utils.ToJSON(cgrEv.Event), string(rcv))
+	}
+
+	cgrEv.ID = "../INV000001"
+	expErr := "tenant <cgrates.org> with ID <../INV000001> not allowed in the file name"
+	if err := ee.ExportEvent(cgrEv); err == nil || err.Error() != expErr {
+		t.Errorf("Expected %q, received: %v", expErr, err)
+	}
+}
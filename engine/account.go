@@ -43,6 +43,31 @@ type Account struct {
 	Disabled          bool
 	UpdateTime        time.Time
 	executingTriggers bool
+	lkTokens          map[string]int64 // fencing tokens of the locks held while processing the account, checked when stored
+}
+
+// SetLockTokens records the fencing tokens passed by guardian.GuardWithTokens to the handler processing the account
+func (acc *Account) SetLockTokens(tokens map[string]int64) {
+	if acc.lkTokens == nil {
+		acc.lkTokens = make(map[string]int64)
+	}
+	for lkID, token := range tokens {
+		acc.lkTokens[lkID] = token
+	}
+}
+
+// CreateAccountIfMissing stores an empty account with the accID if not already in DataDB
+// the account is locked so the write is fenced as the other account updates
+func CreateAccountIfMissing(dm *DataManager, accID string) (err error) {
+	_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+		if _, err := dm.GetAccount(accID); err == nil {
+			return nil, nil
+		}
+		acc := &Account{ID: accID}
+		acc.SetLockTokens(tokens)
+		return nil, dm.SetAccount(acc)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.AccountPrefix+accID)
+	return
 }
 
 type AccountWithOpts struct {
@@ -19,9 +19,11 @@ package engine
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/cgrates/cgrates/guardian"
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -2369,3 +2371,62 @@ func BenchmarkGetSecondsForPrefix(b *testing.B) {
 		ub1.getCreditForPrefix(cd)
 	}
 }
+
+type dLockerMock struct {
+	mux  sync.Mutex
+	held map[string]int64
+}
+
+func (dl *dLockerMock) Lock(lkID string) (int64, error) {
+	dl.mux.Lock()
+	defer dl.mux.Unlock()
+	dl.held[lkID] = 1
+	return 1, nil
+}
+
+func (dl *dLockerMock) Unlock(lkID string, token int64) error {
+	dl.mux.Lock()
+	defer dl.mux.Unlock()
+	delete(dl.held, lkID)
+	return nil
+}
+
+func (dl *dLockerMock) Validate(lkID string, token int64) error {
+	dl.mux.Lock()
+	defer dl.mux.Unlock()
+	if dl.held[lkID] != token {
+		return utils.ErrLockLost
+	}
+	return nil
+}
+
+func TestSetAccountLockLost(t *testing.T) {
+	dl := &dLockerMock{held: make(map[string]int64)}
+	guardian.Guardian.SetDistributedLocker(dl)
+	defer guardian.Guardian.SetDistributedLocker(nil)
+	acc := &Account{ID: "cgrates.org:lockLost"}
+	if err := dm.SetAccount(acc); err != utils.ErrLockLost {
+		t.Errorf("Expected %+v for a write without lock, received %+v", utils.ErrLockLost, err)
+	}
+	if _, err := guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+		acc.SetLockTokens(tokens)
+		return nil, dm.SetAccount(acc)
+	}, 0, utils.AccountPrefix+acc.ID); err != nil {
+		t.Error(err)
+	}
+	if _, err := guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+		dl.mux.Lock()
+		dl.held[utils.AccountPrefix+acc.ID] = 2 // lease expired and the lock was taken by other engine
+		dl.mux.Unlock()
+		disabled := &Account{ID: acc.ID, Disabled: true}
+		disabled.SetLockTokens(tokens)
+		return nil, dm.SetAccount(disabled)
+	}, 0, utils.AccountPrefix+acc.ID); err != utils.ErrLockLost {
+		t.Errorf("Expected %+v, received %+v", utils.ErrLockLost, err)
+	}
+	if rcv, err := dm.GetAccount(acc.ID); err != nil {
+		t.Error(err)
+	} else if rcv.Disabled {
+		t.Error("account written without holding the lock")
+	}
+}
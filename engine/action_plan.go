@@ -202,7 +202,7 @@ func (at *ActionTiming) Execute(successActions, failedActions chan *Action) (err
 	}
 	var partialyExecuted bool
 	for accID := range at.accountIDs {
-		_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
 			acc, err := dm.GetAccount(accID)
 			if err != nil { // create account
 				if err != utils.ErrNotFound {
@@ -214,6 +214,7 @@ func (at *ActionTiming) Execute(successActions, failedActions chan *Action) (err
 					ID: accID,
 				}
 			}
+			acc.SetLockTokens(tokens)
 			transactionFailed := false
 			removeAccountActionFound := false
 			for _, a := range aac {
@@ -32,10 +32,15 @@ import (
 // NewAttributeService returns a new AttributeService
 func NewAttributeService(dm *DataManager, filterS *FilterS,
 	cgrcfg *config.CGRConfig) (*AttributeService, error) {
+	lookups, err := newAttributeLookups(cgrcfg.AttributeSCfg().Lookups, cgrcfg.GeneralCfg().RSRSep)
+	if err != nil {
+		return nil, err
+	}
 	return &AttributeService{
 		dm:      dm,
 		filterS: filterS,
 		cgrcfg:  cgrcfg,
+		lookups: lookups,
 	}, nil
 }
 
@@ -44,11 +49,18 @@ type AttributeService struct {
 	dm      *DataManager
 	filterS *FilterS
 	cgrcfg  *config.CGRConfig
+	lookups map[string]*attributeLookup // used by the *http and *sql attributes
 }
 
 // Shutdown is called to shutdown the service
 func (alS *AttributeService) Shutdown() (err error) {
 	utils.Logger.Info(fmt.Sprintf("<%s> shutdown initialized", utils.AttributeS))
+	for lkID, lk := range alS.lookups {
+		if errClose := lk.close(); errClose != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> failed closing lookup <%s>: %s",
+				utils.AttributeS, lkID, errClose.Error()))
+		}
+	}
 	utils.Logger.Info(fmt.Sprintf("<%s> shutdown complete", utils.AttributeS))
 	return
 }
@@ -374,6 +386,20 @@ func (alS *AttributeService) processEvent(tnt string, args *AttrArgsProcessEvent
 				return
 			}
 			substitute = pathVal + val
+		case utils.MetaHTTP, utils.MetaSQL:
+			substitute, err = alS.lookupValue(attribute.Type, attribute.Value, dynDP)
+		case utils.MetaLNP:
+			var number string
+			if number, err = attribute.Value.ParseDataProvider(dynDP); err != nil {
+				rply = nil
+				return
+			}
+			var pn *PortedNumber
+			if pn, err = lookupPortedNumber(alS.dm, tnt, number); err != nil {
+				rply = nil
+				return
+			}
+			substitute = pn.RoutingNumber
 		default: // backwards compatible in case that Type is empty
 			substitute, err = attribute.Value.ParseDataProvider(dynDP)
 		}
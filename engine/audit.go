@@ -0,0 +1,357 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// AuditEntry records one API call changing the configuration or the profiles
+type AuditEntry struct {
+	ID         string
+	Time       time.Time
+	Method     string
+	Subject    string // owner of the credentials, empty if the API authorization is disabled
+	Role       string
+	RemoteAddr string
+	Tenant     string
+	ItemID     string // tenant:ID of the modified item
+	Args       string // the call arguments as JSON, not recorded for the config APIs
+	Before     string // the item before the call as JSON
+	After      string // the item after the call as JSON
+	Diff       []*AuditDiff
+	Error      string
+
+	itmID  string      // the ID of the item without tenant
+	before interface{} // the snapshot before the call
+}
+
+// AuditDiff is one field changed by the audited call
+type AuditDiff struct {
+	Path   string
+	Before interface{}
+	After  interface{}
+}
+
+// AsCGREvent converts the entry into a CGREvent, used by the *ees audit storage
+func (ae *AuditEntry) AsCGREvent(dfltTnt string) *utils.CGREvent {
+	tnt := ae.Tenant
+	if tnt == utils.EmptyString {
+		tnt = dfltTnt
+	}
+	return &utils.CGREvent{
+		Tenant: tnt,
+		ID:     ae.ID,
+		Time:   utils.TimePointer(ae.Time),
+		Event: map[string]interface{}{
+			utils.ID:         ae.ID,
+			utils.Time:       ae.Time,
+			utils.Method:     ae.Method,
+			utils.Subject:    ae.Subject,
+			utils.Role:       ae.Role,
+			utils.RemoteAddr: ae.RemoteAddr,
+			utils.Tenant:     ae.Tenant,
+			utils.ItemID:     ae.ItemID,
+			utils.Args:       ae.Args,
+			utils.Before:     ae.Before,
+			utils.After:      ae.After,
+			utils.Diff:       utils.ToJSON(ae.Diff),
+			utils.Error:      ae.Error,
+		},
+		Opts: make(map[string]interface{}),
+	}
+}
+
+// auditItems returns the items modified by the APIerSv1.Set*/Remove* methods, by the name of the item
+var auditItems = map[string]func(dm *DataManager, tnt, id string) (interface{}, error){
+	"AttributeProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetAttributeProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"ChargerProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetChargerProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"Filter": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetFilter(tnt, id, false, false, utils.NonTransactional)
+	},
+	"ResourceProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetResourceProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"StatQueueProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetStatQueueProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"ThresholdProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetThresholdProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"RouteProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetRouteProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"DispatcherProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetDispatcherProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"DispatcherHost": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetDispatcherHost(tnt, id, false, false, utils.NonTransactional)
+	},
+	"RateProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetRateProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"ActionProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetActionProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"AccountProfile": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetAccountProfile(tnt, id, false, false, utils.NonTransactional)
+	},
+	"Account": func(dm *DataManager, tnt, id string) (interface{}, error) {
+		return dm.GetAccount(utils.ConcatenatedKey(tnt, id))
+	},
+}
+
+// auditMaskedFields are the config fields not recorded in clear
+var auditMaskedFields = []string{"password", utils.JWTKeyCfg, utils.APIKeysCfg}
+
+const auditMaskedValue = utils.MaskedSecret
+
+// NewAuditS returns the audit of the API calls
+func NewAuditS(cfg *config.CGRConfig, dm *DataManager, storDB StorDB, connMgr *ConnManager) *AuditS {
+	return &AuditS{
+		cfg:     cfg,
+		dm:      dm,
+		storDB:  storDB,
+		connMgr: connMgr,
+	}
+}
+
+// AuditS records the API calls changing the configuration or the profiles
+type AuditS struct {
+	cfg     *config.CGRConfig
+	dm      *DataManager
+	storDB  StorDB
+	connMgr *ConnManager
+}
+
+// Audited returns true if the calls of the method are recorded
+func (aS *AuditS) Audited(method string) bool {
+	for _, pattern := range aS.cfg.ApierCfg().AuditAPIs {
+		if matched, _ := path.Match(pattern, method); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// NewAuditEntry starts the entry of an audited call, taking the snapshot of the item before the call
+// the tenant and the ID of the item are the ones in the call arguments
+func (aS *AuditS) NewAuditEntry(method, tnt, itmID string, args interface{}) (ae *AuditEntry) {
+	ae = &AuditEntry{
+		ID:     utils.GenUUID(),
+		Time:   time.Now(),
+		Method: method,
+		Tenant: tnt,
+		itmID:  itmID,
+	}
+	if isConfigAPI(method) {
+		ae.before = aS.snapshot(ae)
+		return
+	}
+	ae.Args = utils.ToJSON(args)
+	if ae.itmID != utils.EmptyString {
+		if ae.Tenant == utils.EmptyString {
+			ae.Tenant = aS.cfg.GeneralCfg().DefaultTenant
+		}
+		ae.ItemID = utils.ConcatenatedKey(ae.Tenant, ae.itmID)
+	}
+	ae.before = aS.snapshot(ae)
+	return
+}
+
+// RecordAuditEntry completes the entry with the snapshot after the call and records it
+func (aS *AuditS) RecordAuditEntry(ae *AuditEntry, callErr string) (err error) {
+	ae.Error = callErr
+	after := aS.snapshot(ae)
+	ae.Diff = auditDiff(ae.before, after)
+	if isConfigAPI(ae.Method) {
+		maskAuditDiff(ae)
+	} else {
+		if ae.before != nil {
+			ae.Before = utils.ToJSON(ae.before)
+		}
+		if after != nil {
+			ae.After = utils.ToJSON(after)
+		}
+	}
+	if aS.cfg.ApierCfg().AuditStorage == utils.MetaEEs {
+		var rply map[string]map[string]interface{}
+		return aS.connMgr.Call(aS.cfg.ApierCfg().EEsConns, nil, utils.EeSv1ProcessEvent,
+			&utils.CGREventWithEeIDs{
+				EeIDs:    aS.cfg.ApierCfg().AuditExporterIDs,
+				CGREvent: ae.AsCGREvent(aS.cfg.GeneralCfg().DefaultTenant),
+			}, &rply)
+	}
+	if aS.storDB == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	return aS.storDB.SetAuditEntry(ae)
+}
+
+// snapshot returns the item changed by the call in its JSON form, the whole config for the config APIs
+// nil if the item is not found or the method is not changing a known item
+func (aS *AuditS) snapshot(ae *AuditEntry) interface{} {
+	if isConfigAPI(ae.Method) {
+		return auditSnapshotValue(aS.cfg.AsMapInterface(aS.cfg.GeneralCfg().RSRSep))
+	}
+	if aS.dm == nil || ae.itmID == utils.EmptyString {
+		return nil
+	}
+	getItem, has := auditItems[auditItemName(ae.Method)]
+	if !has {
+		return nil
+	}
+	itm, err := getItem(aS.dm, ae.Tenant, ae.itmID)
+	if err != nil {
+		return nil
+	}
+	return auditSnapshotValue(itm)
+}
+
+// auditSnapshotValue decouples the snapshot from the item, converting it through JSON
+func auditSnapshotValue(itm interface{}) (val interface{}) {
+	if err := json.Unmarshal([]byte(utils.ToJSON(itm)), &val); err != nil {
+		return nil
+	}
+	return
+}
+
+// isConfigAPI returns true for the ConfigSv1 methods
+func isConfigAPI(method string) bool {
+	return strings.HasPrefix(method, utils.ConfigSv1+utils.NestingSep)
+}
+
+// auditItemName returns the name of the item from the Set<Item> and Remove<Item> methods
+func auditItemName(method string) string {
+	if idx := strings.Index(method, utils.NestingSep); idx != -1 {
+		method = method[idx+1:]
+	}
+	for _, prfx := range []string{"Set", "Remove"} {
+		if strings.HasPrefix(method, prfx) {
+			return strings.TrimPrefix(method, prfx)
+		}
+	}
+	return utils.EmptyString
+}
+
+// auditDiff returns the fields changed between the two snapshots
+func auditDiff(before, after interface{}) (diff []*AuditDiff) {
+	bFlds := make(map[string]interface{})
+	aFlds := make(map[string]interface{})
+	if before != nil {
+		flattenAuditValue(before, utils.EmptyString, bFlds)
+	}
+	if after != nil {
+		flattenAuditValue(after, utils.EmptyString, aFlds)
+	}
+	paths := make(utils.StringSet)
+	for fldPath := range bFlds {
+		paths.Add(fldPath)
+	}
+	for fldPath := range aFlds {
+		paths.Add(fldPath)
+	}
+	for _, fldPath := range paths.AsOrderedSlice() {
+		if !reflect.DeepEqual(bFlds[fldPath], aFlds[fldPath]) {
+			diff = append(diff, &AuditDiff{
+				Path:   fldPath,
+				Before: bFlds[fldPath],
+				After:  aFlds[fldPath],
+			})
+		}
+	}
+	return
+}
+
+// flattenAuditValue populates the flds with the leaf values of the snapshot, by their path
+func flattenAuditValue(val interface{}, fldPath string, flds map[string]interface{}) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			flds[fldPath] = v
+			return
+		}
+		for key, fldVal := range v {
+			if fldPath == utils.EmptyString {
+				flattenAuditValue(fldVal, key, flds)
+				continue
+			}
+			flattenAuditValue(fldVal, fldPath+utils.NestingSep+key, flds)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			flds[fldPath] = v
+			return
+		}
+		for i, fldVal := range v {
+			flattenAuditValue(fldVal, fldPath+utils.IdxStart+strconv.Itoa(i)+utils.IdxEnd, flds)
+		}
+	default:
+		flds[fldPath] = v
+	}
+}
+
+// maskAuditDiff hides the values of the sensitive config fields
+// the path is cut after the masked field since the API keys are part of it
+func maskAuditDiff(ae *AuditEntry) {
+	var diff []*AuditDiff
+	masked := make(utils.StringSet)
+	for _, fldDiff := range ae.Diff {
+		maskedPath := maskedAuditPath(fldDiff.Path)
+		if maskedPath == utils.EmptyString {
+			diff = append(diff, fldDiff)
+			continue
+		}
+		if masked.Has(maskedPath) {
+			continue
+		}
+		masked.Add(maskedPath)
+		diff = append(diff, &AuditDiff{
+			Path:   maskedPath,
+			Before: auditMaskedValue,
+			After:  auditMaskedValue,
+		})
+	}
+	ae.Diff = diff
+}
+
+// maskedAuditPath returns the path up to the sensitive field, empty if the path is not sensitive
+func maskedAuditPath(fldPath string) string {
+	flds := strings.Split(fldPath, utils.NestingSep)
+	for i, fld := range flds {
+		for _, masked := range auditMaskedFields {
+			if strings.Contains(fld, masked) {
+				return strings.Join(flds[:i+1], utils.NestingSep)
+			}
+		}
+	}
+	return utils.EmptyString
+}
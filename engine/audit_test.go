@@ -0,0 +1,217 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAuditItemName(t *testing.T) {
+	for method, exp := range map[string]string{
+		"APIerSv1.SetAttributeProfile": "AttributeProfile",
+		"APIerSv1.RemoveFilter":        "Filter",
+		"APIerSv1.GetFilter":           utils.EmptyString,
+		"SetAccount":                   "Account",
+	} {
+		if rcv := auditItemName(method); rcv != exp {
+			t.Errorf("Expected %q for %q, received %q", exp, method, rcv)
+		}
+	}
+}
+
+func TestAuditDiff(t *testing.T) {
+	before := auditSnapshotValue(&Filter{
+		Tenant: "cgrates.org",
+		ID:     "FLTR_1",
+		Rules: []*FilterRule{{
+			Type:    utils.MetaString,
+			Element: "~*req.Account",
+			Values:  []string{"1001"},
+		}},
+	})
+	after := auditSnapshotValue(&Filter{
+		Tenant: "cgrates.org",
+		ID:     "FLTR_1",
+		Rules: []*FilterRule{{
+			Type:    utils.MetaString,
+			Element: "~*req.Account",
+			Values:  []string{"1001", "1002"},
+		}},
+	})
+	exp := []*AuditDiff{{Path: "Rules[0].Values[1]", After: "1002"}}
+	if rcv := auditDiff(before, after); !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(exp), utils.ToJSON(rcv))
+	}
+	exp = []*AuditDiff{
+		{Path: "ID", Before: "FLTR_1"},
+		{Path: "Rules[0].Element", Before: "~*req.Account"},
+		{Path: "Rules[0].Type", Before: utils.MetaString},
+		{Path: "Rules[0].Values[0]", Before: "1001"},
+		{Path: "Tenant", Before: "cgrates.org"},
+	}
+	if rcv := auditDiff(before, nil); !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(exp), utils.ToJSON(rcv))
+	}
+	if rcv := auditDiff(before, before); len(rcv) != 0 {
+		t.Errorf("Expected no diff, received %s", utils.ToJSON(rcv))
+	}
+}
+
+func TestAuditMaskDiff(t *testing.T) {
+	ae := &AuditEntry{
+		Diff: []*AuditDiff{
+			{Path: "cores.api_keys.key1.role", After: "admin"},
+			{Path: "cores.api_keys.key2.role", After: "readonly"},
+			{Path: "general.node_id", Before: "node1", After: "node2"},
+			{Path: "stor_db.db_password", Before: "", After: "secret"},
+		},
+	}
+	exp := []*AuditDiff{
+		{Path: "cores.api_keys", Before: auditMaskedValue, After: auditMaskedValue},
+		{Path: "general.node_id", Before: "node1", After: "node2"},
+		{Path: "stor_db.db_password", Before: auditMaskedValue, After: auditMaskedValue},
+	}
+	if maskAuditDiff(ae); !reflect.DeepEqual(exp, ae.Diff) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(exp), utils.ToJSON(ae.Diff))
+	}
+}
+
+func TestAuditSRecordEntry(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.ApierCfg().Audit = true
+	dm := NewDataManager(NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	aS := NewAuditS(cfg, dm, NewInternalDB(nil, nil, false), nil)
+	for method, exp := range map[string]bool{
+		"APIerSv1.SetFilter":           true,
+		"APIerSv1.RemoveFilter":        true,
+		"APIerSv1.GetFilter":           false,
+		"ConfigSv1.SetConfigFromJSON":  true,
+		utils.ConfigSv1GetConfig:       false,
+		"APIerSv2.SetAttributeProfile": false,
+	} {
+		if rcv := aS.Audited(method); rcv != exp {
+			t.Errorf("Expected %v for %q, received %v", exp, method, rcv)
+		}
+	}
+
+	fltr := &Filter{
+		Tenant: "cgrates.org",
+		ID:     "FLTR_AUDIT",
+		Rules: []*FilterRule{{
+			Type:    utils.MetaString,
+			Element: "~*req.Account",
+			Values:  []string{"1001"},
+		}},
+	}
+	ae := aS.NewAuditEntry("APIerSv1.SetFilter", utils.EmptyString, fltr.ID, fltr)
+	if err := dm.SetFilter(fltr, false); err != nil {
+		t.Fatal(err)
+	}
+	ae.Subject = "audit_user"
+	if err := aS.RecordAuditEntry(ae, utils.EmptyString); err != nil {
+		t.Fatal(err)
+	}
+	ae2 := aS.NewAuditEntry("APIerSv1.RemoveFilter", "cgrates.org", fltr.ID, &utils.TenantID{ID: fltr.ID})
+	if err := dm.RemoveFilter(fltr.Tenant, fltr.ID, utils.NonTransactional, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := aS.RecordAuditEntry(ae2, utils.EmptyString); err != nil {
+		t.Fatal(err)
+	}
+
+	rcv, err := aS.storDB.GetAuditEntries(&utils.AuditLogFilter{
+		ItemIDs: []string{"cgrates.org:FLTR_AUDIT"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rcv) != 2 {
+		t.Fatalf("Expected 2 entries, received %s", utils.ToJSON(rcv))
+	}
+	if rcv[0].ID != ae.ID || rcv[1].ID != ae2.ID {
+		t.Errorf("Unexpected order of the entries: %s", utils.ToJSON(rcv))
+	}
+	if rcv[0].Tenant != "cgrates.org" ||
+		rcv[0].Subject != "audit_user" ||
+		rcv[0].Before != utils.EmptyString ||
+		rcv[0].After == utils.EmptyString ||
+		rcv[0].Args != utils.ToJSON(fltr) ||
+		len(rcv[0].Diff) == 0 {
+		t.Errorf("Unexpected entry: %s", utils.ToJSON(rcv[0]))
+	}
+	if rcv[1].Before != rcv[0].After ||
+		rcv[1].After != utils.EmptyString {
+		t.Errorf("Unexpected entry: %s", utils.ToJSON(rcv[1]))
+	}
+
+	if rcv, err = aS.storDB.GetAuditEntries(&utils.AuditLogFilter{
+		ItemIDs:   []string{"cgrates.org:FLTR_AUDIT"},
+		Methods:   []string{"APIerSv1.RemoveFilter"},
+		Paginator: utils.Paginator{Limit: utils.IntPointer(1)},
+	}); err != nil {
+		t.Fatal(err)
+	} else if len(rcv) != 1 || rcv[0].ID != ae2.ID {
+		t.Errorf("Unexpected entries: %s", utils.ToJSON(rcv))
+	}
+	if _, err = aS.storDB.GetAuditEntries(&utils.AuditLogFilter{
+		ItemIDs: []string{"cgrates.org:FLTR_AUDIT"},
+		Time:    utils.TimeInterval{Begin: utils.TimePointer(time.Now().Add(time.Hour))},
+	}); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestAuditSRecordConfigEntry(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.ApierCfg().Audit = true
+	aS := NewAuditS(cfg, nil, NewInternalDB(nil, nil, false), nil)
+	ae := aS.NewAuditEntry(utils.ConfigSv1SetConfig, utils.EmptyString, utils.EmptyString, map[string]interface{}{})
+	oldNodeID := cfg.GeneralCfg().NodeID
+	cfg.GeneralCfg().NodeID = "audit_node"
+	if err := aS.RecordAuditEntry(ae, utils.EmptyString); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*AuditDiff{{Path: "general.node_id", Before: oldNodeID, After: "audit_node"}}
+	if !reflect.DeepEqual(exp, ae.Diff) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(exp), utils.ToJSON(ae.Diff))
+	}
+	if ae.Args != utils.EmptyString || ae.Before != utils.EmptyString || ae.After != utils.EmptyString {
+		t.Errorf("Unexpected entry: %s", utils.ToJSON(ae))
+	}
+}
+
+func TestAuditEntryAsCGREvent(t *testing.T) {
+	ae := &AuditEntry{
+		ID:     "AUDIT_1",
+		Time:   time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+		Method: "APIerSv1.SetFilter",
+		Diff:   []*AuditDiff{{Path: "ID", After: "FLTR_1"}},
+	}
+	ev := ae.AsCGREvent("cgrates.org")
+	if ev.Tenant != "cgrates.org" || ev.ID != ae.ID ||
+		ev.Event[utils.Method] != ae.Method ||
+		ev.Event[utils.Diff] != utils.ToJSON(ae.Diff) {
+		t.Errorf("Unexpected event: %s", utils.ToJSON(ev))
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"net/url"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cgrates/cgrates/config"
@@ -88,11 +89,58 @@ func init() {
 
 	gob.Register(new(HTTPPosterRequest))
 
+	// InternalDB dump
+	gob.Register(Versions{})
+	gob.Register(new(Destination))
+	gob.Register(new(RatingPlan))
+	gob.Register(new(RatingProfile))
+	gob.Register(Actions{})
+	gob.Register(new(SharedGroup))
+	gob.Register(ActionTriggers{})
+	gob.Register(new(ActionPlan))
+	gob.Register(new(Account))
+	gob.Register(new(ChargerProfile))
+	gob.Register(new(DispatcherProfile))
+	gob.Register(new(CDR))
+	gob.Register(new(SMCost))
+	gob.Register(new(AuditEntry))
+	gob.Register(new(Invoice))
+	gob.Register(new(PortedNumber))
+	gob.Register(new(SharedSession))
+	gob.Register(new(ConfigSection))
+	gob.Register(new(utils.TPTiming))
+	gob.Register(new(utils.AccountProfile))
+	gob.Register(new(utils.ApierTPTiming))
+	gob.Register(new(utils.TPDestination))
+	gob.Register(new(utils.TPRateRALs))
+	gob.Register(new(utils.TPDestinationRate))
+	gob.Register(new(utils.TPRatingPlan))
+	gob.Register(new(utils.TPRatingProfile))
+	gob.Register(new(utils.TPSharedGroups))
+	gob.Register(new(utils.TPActions))
+	gob.Register(new(utils.TPActionPlan))
+	gob.Register(new(utils.TPActionTriggers))
+	gob.Register(new(utils.TPAccountActions))
+	gob.Register(new(utils.TPResourceProfile))
+	gob.Register(new(utils.TPStatProfile))
+	gob.Register(new(utils.TPThresholdProfile))
+	gob.Register(new(utils.TPFilterProfile))
+	gob.Register(new(utils.TPRouteProfile))
+	gob.Register(new(utils.TPAttributeProfile))
+	gob.Register(new(utils.TPChargerProfile))
+	gob.Register(new(utils.TPDispatcherProfile))
+	gob.Register(new(utils.TPDispatcherHost))
+	gob.Register(new(utils.TPRateProfile))
+	gob.Register(new(utils.TPActionProfile))
+	gob.Register(new(utils.TPAccountProfile))
+
 	gob.Register([]interface{}{})
 	gob.Register([]map[string]interface{}{})
 	gob.Register(map[string]interface{}{})
 	gob.Register(map[string][]map[string]interface{}{})
 	gob.Register(map[string]string{})
+	gob.Register(map[string]int64{})
+	gob.Register([]string{})
 	gob.Register(time.Duration(0))
 	gob.Register(time.Time{})
 	gob.Register(url.Values{})
@@ -141,19 +189,28 @@ func NewCacheS(cfg *config.CGRConfig, dm *DataManager, cpS *CapsStats) (c *Cache
 		dm:      dm,
 		pcItems: make(map[string]chan struct{}),
 		tCache:  ltcache.NewTransCache(tCache),
+		hits:    make(map[string]*cacheHits),
 	}
 	for cacheID := range cfg.CacheCfg().Partitions {
 		c.pcItems[cacheID] = make(chan struct{})
+		c.hits[cacheID] = new(cacheHits)
 	}
 	return
 }
 
+// cacheHits counts the hits and misses of a cache partition
+type cacheHits struct {
+	hits   uint64
+	misses uint64
+}
+
 // CacheS deals with cache preload and other cache related tasks/APIs
 type CacheS struct {
 	cfg     *config.CGRConfig
 	dm      *DataManager
 	pcItems map[string]chan struct{} // signal precaching
 	tCache  *ltcache.TransCache
+	hits    map[string]*cacheHits // populated on init, read only afterwards
 }
 
 // Set is an exported method from TransCache
@@ -177,8 +234,27 @@ func (chS *CacheS) HasItem(chID, itmID string) (has bool) {
 }
 
 // Get is an exported method from TransCache
-func (chS *CacheS) Get(chID, itmID string) (interface{}, bool) {
-	return chS.tCache.Get(chID, itmID)
+func (chS *CacheS) Get(chID, itmID string) (itm interface{}, has bool) {
+	itm, has = chS.tCache.Get(chID, itmID)
+	if ch, canCount := chS.hits[chID]; canCount {
+		if has {
+			atomic.AddUint64(&ch.hits, 1)
+		} else {
+			atomic.AddUint64(&ch.misses, 1)
+		}
+	}
+	return
+}
+
+// GetHits returns the number of hits and misses for each partition
+func (chS *CacheS) GetHits() (hits, misses map[string]uint64) {
+	hits = make(map[string]uint64, len(chS.hits))
+	misses = make(map[string]uint64, len(chS.hits))
+	for chID, ch := range chS.hits {
+		hits[chID] = atomic.LoadUint64(&ch.hits)
+		misses[chID] = atomic.LoadUint64(&ch.misses)
+	}
+	return
 }
 
 // GetItemIDs is an exported method from TransCache
@@ -746,7 +746,7 @@ func (cd *CallDescriptor) debit(account *Account, dryRun bool, goNegative bool)
 		if len(roundIncrements) != 0 {
 			rcd := cc.CreateCallDescriptor()
 			rcd.Increments = roundIncrements
-			rcd.refundRounding()
+			rcd.refundRounding(account.lkTokens)
 		}
 	}
 	//log.Printf("OUT CC: ", cc)
@@ -755,12 +755,13 @@ func (cd *CallDescriptor) debit(account *Account, dryRun bool, goNegative bool)
 
 func (cd *CallDescriptor) Debit() (cc *CallCost, err error) {
 	cd.account = nil // make sure it's not cached
-	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+	_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (iface interface{}, err error) {
 		// lock all group members
 		account, err := cd.getAccount()
 		if err != nil {
 			return nil, err
 		}
+		account.SetLockTokens(tokens)
 		initialAcnt := account.AsAccountSummary()
 		acntIDs, sgerr := account.GetUniqueSharedGroupMembers(cd)
 		if sgerr != nil {
@@ -772,7 +773,8 @@ func (cd *CallDescriptor) Debit() (cc *CallCost, err error) {
 				lkIDs = append(lkIDs, utils.AccountPrefix+acntID)
 			}
 		}
-		_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+		_, err = guardian.Guardian.GuardWithTokens(func(grpTokens map[string]int64) (iface interface{}, err error) {
+			account.SetLockTokens(grpTokens)
 			cc, err = cd.debit(account, cd.DryRun, !cd.DenyNegativeAccount)
 			if err == nil {
 				cc.AccountSummary = cd.AccountSummary(initialAcnt)
@@ -790,11 +792,12 @@ func (cd *CallDescriptor) Debit() (cc *CallCost, err error) {
 // by the GetMaxSessionDuration method. The amount filed has to be filled in call descriptor.
 func (cd *CallDescriptor) MaxDebit() (cc *CallCost, err error) {
 	cd.account = nil // make sure it's not cached
-	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+	_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (iface interface{}, err error) {
 		account, err := cd.getAccount()
 		if err != nil {
 			return nil, err
 		}
+		account.SetLockTokens(tokens)
 		initialAcnt := account.AsAccountSummary()
 		acntIDs, err := account.GetUniqueSharedGroupMembers(cd)
 		if err != nil {
@@ -806,7 +809,8 @@ func (cd *CallDescriptor) MaxDebit() (cc *CallCost, err error) {
 				lkIDs = append(lkIDs, utils.AccountPrefix+acntID)
 			}
 		}
-		_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+		_, err = guardian.Guardian.GuardWithTokens(func(grpTokens map[string]int64) (iface interface{}, err error) {
+			account.SetLockTokens(grpTokens)
 			remainingDuration, err := cd.getMaxSessionDuration(account)
 			if err != nil && cd.GetDuration() > 0 {
 				return nil, err
@@ -849,9 +853,9 @@ func (cd *CallDescriptor) MaxDebit() (cc *CallCost, err error) {
 	return cc, err
 }
 
-// refundIncrements has no locks
+// refundIncrements has no locks, the lkTokens are the fencing tokens of the locks taken by the caller
 // returns the updated account referenced by the CallDescriptor
-func (cd *CallDescriptor) refundIncrements() (acnt *Account, err error) {
+func (cd *CallDescriptor) refundIncrements(lkTokens map[string]int64) (acnt *Account, err error) {
 	accountsCache := make(map[string]*Account)
 	for _, increment := range cd.Increments {
 		// work around for the refund from CDRServer:
@@ -865,6 +869,7 @@ func (cd *CallDescriptor) refundIncrements() (acnt *Account, err error) {
 		if !found {
 			if acc, err := dm.GetAccount(increment.BalanceInfo.AccountID); err == nil && acc != nil {
 				account = acc
+				account.SetLockTokens(lkTokens)
 				accountsCache[increment.BalanceInfo.AccountID] = account
 				// will save the account only once at the end of the function
 				defer dm.SetAccount(account)
@@ -912,14 +917,14 @@ func (cd *CallDescriptor) RefundIncrements() (acnt *Account, err error) {
 			accMap[utils.AccountPrefix+increment.BalanceInfo.AccountID] = true
 		}
 	}
-	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
-		acnt, err = cd.refundIncrements()
+	_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (iface interface{}, err error) {
+		acnt, err = cd.refundIncrements(tokens)
 		return
 	}, config.CgrConfig().GeneralCfg().LockingTimeout, accMap.Slice()...)
 	return
 }
 
-func (cd *CallDescriptor) refundRounding() (err error) {
+func (cd *CallDescriptor) refundRounding(lkTokens map[string]int64) (err error) {
 	// get account list for locking
 	// all must be locked in order to use cache
 	accountsCache := make(map[string]*Account)
@@ -928,6 +933,7 @@ func (cd *CallDescriptor) refundRounding() (err error) {
 		if !found {
 			if acc, err := dm.GetAccount(increment.BalanceInfo.AccountID); err == nil && acc != nil {
 				account = acc
+				account.SetLockTokens(lkTokens)
 				accountsCache[increment.BalanceInfo.AccountID] = account
 				// will save the account only once at the end of the function
 				defer dm.SetAccount(account)
@@ -955,8 +961,8 @@ func (cd *CallDescriptor) RefundRounding() (err error) {
 	for _, inc := range cd.Increments {
 		accMap[utils.AccountPrefix+inc.BalanceInfo.AccountID] = true
 	}
-	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
-		err = cd.refundRounding()
+	_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (iface interface{}, err error) {
+		err = cd.refundRounding(tokens)
 		return
 	}, config.CgrConfig().GeneralCfg().LockingTimeout, accMap.Slice()...)
 	return
@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -129,8 +131,8 @@ func (cdrS *CDRServer) ListenAndServe(stopChan chan struct{}) {
 
 // RegisterHandlersToServer is called by cgr-engine to register HTTP URL handlers
 func (cdrS *CDRServer) RegisterHandlersToServer(server utils.Server) {
-	server.RegisterHttpFunc(cdrS.cgrCfg.HTTPCfg().HTTPCDRsURL, cdrS.cgrCdrHandler)
-	server.RegisterHttpFunc(cdrS.cgrCfg.HTTPCfg().HTTPFreeswitchCDRsURL, cdrS.fsCdrHandler)
+	server.RegisterHttpAuthFunc(cdrS.cgrCfg.HTTPCfg().HTTPCDRsURL, utils.CDRsV1ProcessHTTPCDR, cdrS.cgrCdrHandler)
+	server.RegisterHttpAuthFunc(cdrS.cgrCfg.HTTPCfg().HTTPFreeswitchCDRsURL, utils.CDRsV1ProcessHTTPCDR, cdrS.fsCdrHandler)
 }
 
 // storeSMCost will store a SMCost
@@ -546,6 +548,9 @@ func (cdrS *CDRServer) processEvent(ev *utils.CGREvent,
 			}
 		}
 	}
+	if ralS {
+		cdrS.pairWholesaleCDRs(cdrs, cgrEvs)
+	}
 	if store {
 		refundCDRCosts := func() { // will be used to refund all CDRs on errors
 			for _, cdr := range cdrs { // refund what we have charged since duplicates are not allowed
@@ -637,10 +642,123 @@ func (cdrS *CDRServer) processEvent(ev *utils.CGREvent,
 			Flags: procFlgs[i].AsSlice(),
 			Event: cgrEv.Event,
 		}
+		EventStream.Publish(utils.MetaCDRProcessed, cgrEv.Tenant, cgrEv.Event)
+	}
+	return
+}
+
+// pairWholesaleCDRs populates the customer and the supplier CDRs of the same call with
+// the wholesale fields, the events being updated together with their CDRs
+// the CDRs without counterpart in the event are paired with the ones stored by previous events
+func (cdrS *CDRServer) pairWholesaleCDRs(cdrs []*CDR, cgrEvs []*utils.CGREvent) {
+	custRunID := cdrS.cgrCfg.CdrsCfg().CustomerRunID
+	suppRunID := cdrS.cgrCfg.CdrsCfg().SupplierRunID
+	if custRunID == utils.EmptyString || suppRunID == utils.EmptyString {
+		return
+	}
+	custIdx := make(map[string]int)
+	suppIdx := make(map[string]int)
+	for i, cdr := range cdrs {
+		switch cdr.RunID {
+		case custRunID:
+			custIdx[cdr.CGRID] = i
+		case suppRunID:
+			suppIdx[cdr.CGRID] = i
+		}
+	}
+	var unpaired []int
+	for cgrID, i := range custIdx {
+		j, has := suppIdx[cgrID]
+		if !has {
+			unpaired = append(unpaired, i)
+			continue
+		}
+		delete(suppIdx, cgrID)
+		wsFlds := cdrS.wholesaleFields(cdrs[i], cdrs[j])
+		setWholesaleFields(cdrs[i], cgrEvs[i], wsFlds)
+		setWholesaleFields(cdrs[j], cgrEvs[j], wsFlds)
+	}
+	for _, j := range suppIdx {
+		unpaired = append(unpaired, j)
+	}
+	cdrS.pairStoredWholesaleCDRs(cdrs, cgrEvs, unpaired)
+}
+
+// pairStoredWholesaleCDRs pairs the CDRs with the ones of the other run stored for the same calls
+// the stored CDRs are queried together, only the paired ones being updated
+func (cdrS *CDRServer) pairStoredWholesaleCDRs(cdrs []*CDR, cgrEvs []*utils.CGREvent, unpaired []int) {
+	if cdrS.cdrDb == nil || len(unpaired) == 0 {
+		return
+	}
+	custRunID := cdrS.cgrCfg.CdrsCfg().CustomerRunID
+	cgrIDs := make([]string, len(unpaired))
+	unpairedIdx := make(map[string]int, len(unpaired))
+	for k, i := range unpaired {
+		cgrIDs[k] = cdrs[i].CGRID
+		unpairedIdx[utils.ConcatenatedKey(cdrs[i].CGRID, cdrs[i].RunID)] = i
+	}
+	storedCDRs, _, err := cdrS.cdrDb.GetCDRs(&utils.CDRsFilter{CGRIDs: cgrIDs,
+		RunIDs: []string{custRunID, cdrS.cgrCfg.CdrsCfg().SupplierRunID}}, false)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: <%s> querying the wholesale pairs of the CDRs with CGRIDs %+v",
+					utils.CDRs, err.Error(), cgrIDs))
+		}
+		return
+	}
+	for _, storedCDR := range storedCDRs {
+		pairRunID := custRunID
+		if storedCDR.RunID == custRunID {
+			pairRunID = cdrS.cgrCfg.CdrsCfg().SupplierRunID
+		}
+		i, has := unpairedIdx[utils.ConcatenatedKey(storedCDR.CGRID, pairRunID)]
+		if !has { // the stored CDR of the same run as the one in the event
+			continue
+		}
+		custCDR, suppCDR := cdrs[i], storedCDR
+		if storedCDR.RunID == custRunID {
+			custCDR, suppCDR = storedCDR, cdrs[i]
+		}
+		wsFlds := cdrS.wholesaleFields(custCDR, suppCDR)
+		setWholesaleFields(cdrs[i], cgrEvs[i], wsFlds)
+		setWholesaleFields(storedCDR, nil, wsFlds)
+		if err = cdrS.cdrDb.SetCDR(storedCDR, true); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: <%s> updating CDR %+v",
+					utils.CDRs, err.Error(), utils.ToJSON(storedCDR)))
+		}
+	}
+}
+
+// wholesaleFields returns the fields pairing the customer and the supplier CDRs
+func (cdrS *CDRServer) wholesaleFields(custCDR, suppCDR *CDR) (wsFlds map[string]string) {
+	wsFlds = map[string]string{
+		utils.Customer: custCDR.Account,
+		utils.Supplier: suppCDR.Account,
+	}
+	if custCDR.Cost != -1 && suppCDR.Cost != -1 { // the margin is computed only for the rated CDRs
+		wsFlds[utils.CustomerCost] = strconv.FormatFloat(custCDR.Cost, 'f', -1, 64)
+		wsFlds[utils.SupplierCost] = strconv.FormatFloat(suppCDR.Cost, 'f', -1, 64)
+		wsFlds[utils.Margin] = strconv.FormatFloat(utils.Round(custCDR.Cost-suppCDR.Cost,
+			cdrS.cgrCfg.GeneralCfg().RoundingDecimals, utils.MetaRoundingMiddle), 'f', -1, 64)
 	}
 	return
 }
 
+// setWholesaleFields populates the CDR and its event, if any, with the wholesale fields
+func setWholesaleFields(cdr *CDR, cgrEv *utils.CGREvent, wsFlds map[string]string) {
+	if cdr.ExtraFields == nil {
+		cdr.ExtraFields = make(map[string]string)
+	}
+	for fld, val := range wsFlds {
+		cdr.ExtraFields[fld] = val
+		if cgrEv != nil {
+			cgrEv.Event[fld] = val
+		}
+	}
+}
+
 // Call implements the rpcclient.ClientConnector interface
 func (cdrS *CDRServer) Call(serviceMethod string, args interface{}, reply interface{}) error {
 	parts := strings.Split(serviceMethod, ".")
@@ -1102,6 +1220,121 @@ func (cdrS *CDRServer) V1GetCDRs(args utils.RPCCDRsFilterWithOpts, cdrs *[]*CDR)
 	return nil
 }
 
+// V1GetCDRsAggregates groups the CDRs matching the filter, summarizing each group
+func (cdrS *CDRServer) V1GetCDRsAggregates(args *utils.RPCCDRsAggregationWithOpts, reply *[]*utils.CDRsAggregate) error {
+	grps, err := utils.NewCDRsGroupBy(args.GroupBy)
+	if err != nil {
+		return err
+	}
+	if err = utils.CheckCDRsSumFields(args.SumFields); err != nil {
+		return err
+	}
+	if args.RPCCDRsFilter == nil {
+		args.RPCCDRsFilter = new(utils.RPCCDRsFilter)
+	}
+	cdrsFltr, err := args.AsCDRsFilter(cdrS.cgrCfg.GeneralCfg().DefaultTimezone)
+	if err != nil {
+		if err.Error() != utils.NotFoundCaps {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	aggrs, err := cdrS.cdrDb.GetCDRsAggregates(cdrsFltr, grps, args.SumFields)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = aggrs
+	return nil
+}
+
+// ArgsWholesaleMargins selects the CDRs of the margin report and the way they are grouped
+type ArgsWholesaleMargins struct {
+	utils.RPCCDRsFilterWithOpts
+	GroupBy string // <""|*customer|*supplier|*destination>, all the CDRs in one group if empty
+}
+
+// WholesaleMargin is the margin of one group of paired CDRs
+type WholesaleMargin struct {
+	Group        string
+	CDRs         int64
+	Usage        time.Duration
+	CustomerCost float64
+	SupplierCost float64
+	Margin       float64
+}
+
+// V1GetWholesaleMargins returns the margins of the paired customer CDRs matching the filter
+func (cdrS *CDRServer) V1GetWholesaleMargins(args *ArgsWholesaleMargins, reply *[]*WholesaleMargin) (err error) {
+	custRunID := cdrS.cgrCfg.CdrsCfg().CustomerRunID
+	if custRunID == utils.EmptyString ||
+		cdrS.cgrCfg.CdrsCfg().SupplierRunID == utils.EmptyString {
+		return utils.ErrNotImplemented
+	}
+	// all the CDRs have the same RunID so grouping by it puts them in one group
+	grpBy := utils.RunID
+	switch args.GroupBy {
+	case utils.EmptyString:
+	case utils.MetaCustomer:
+		grpBy = utils.Customer
+	case utils.MetaSupplier:
+		grpBy = utils.Supplier
+	case utils.MetaDestination:
+		grpBy = utils.Destination
+	default:
+		return utils.ErrPrefixNotErrNotImplemented(args.GroupBy)
+	}
+	grps, err := utils.NewCDRsGroupBy([]string{grpBy})
+	if err != nil {
+		return utils.NewErrServerError(err)
+	}
+	if args.RPCCDRsFilter == nil {
+		args.RPCCDRsFilter = new(utils.RPCCDRsFilter)
+	}
+	cdrsFltr, err := args.AsCDRsFilter(cdrS.cgrCfg.GeneralCfg().DefaultTimezone)
+	if err != nil {
+		if err.Error() != utils.NotFoundCaps {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	cdrsFltr.RunIDs = []string{custRunID}
+	extraFlds := make(map[string]string, len(cdrsFltr.ExtraFields)+1)
+	for fld, val := range cdrsFltr.ExtraFields {
+		extraFlds[fld] = val
+	}
+	extraFlds[utils.Margin] = utils.MetaExists // not paired or not rated otherwise
+	cdrsFltr.ExtraFields = extraFlds
+	aggrs, err := cdrS.cdrDb.GetCDRsAggregates(cdrsFltr, grps,
+		[]string{utils.CustomerCost, utils.SupplierCost, utils.Margin})
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	rndDec := cdrS.cgrCfg.GeneralCfg().RoundingDecimals
+	margins := make([]*WholesaleMargin, len(aggrs))
+	for i, aggr := range aggrs { // the sums are rounded once, not accumulating the rounding errors
+		margins[i] = &WholesaleMargin{
+			Group:        aggr.Group[grpBy],
+			CDRs:         aggr.Count,
+			Usage:        aggr.Usage,
+			CustomerCost: utils.Round(aggr.Sums[utils.CustomerCost], rndDec, utils.MetaRoundingMiddle),
+			SupplierCost: utils.Round(aggr.Sums[utils.SupplierCost], rndDec, utils.MetaRoundingMiddle),
+			Margin:       utils.Round(aggr.Sums[utils.Margin], rndDec, utils.MetaRoundingMiddle),
+		}
+		if args.GroupBy == utils.EmptyString {
+			margins[i].Group = utils.EmptyString
+		}
+	}
+	sort.Slice(margins, func(i, j int) bool { return margins[i].Group < margins[j].Group })
+	*reply = margins
+	return
+}
+
 // V1CountCDRs counts CDRs from DB
 func (cdrS *CDRServer) V1CountCDRs(args *utils.RPCCDRsFilterWithOpts, cnt *int64) error {
 	cdrsFltr, err := args.AsCDRsFilter(cdrS.cgrCfg.GeneralCfg().DefaultTimezone)
@@ -0,0 +1,367 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestCDRsPairWholesaleCDRs(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cdrS := &CDRServer{cgrCfg: cfg}
+	newCDRs := func() []*CDR {
+		return []*CDR{
+			{CGRID: "call1", RunID: utils.MetaDefault, Account: "cust1", Cost: 0.3},
+			{CGRID: "call1", RunID: "supplier", Account: "supp1", Cost: 0.1},
+			{CGRID: "call2", RunID: utils.MetaDefault, Account: "cust2", Cost: 0.2},
+			{CGRID: "call2", RunID: "supplier", Account: "supp2", Cost: -1},
+			{CGRID: "call3", RunID: utils.MetaDefault, Account: "cust1", Cost: 0.5},
+		}
+	}
+	cdrs := newCDRs()
+	cgrEvs := make([]*utils.CGREvent, len(cdrs))
+	for i, cdr := range cdrs {
+		cgrEvs[i] = cdr.AsCGREvent()
+	}
+	cdrS.pairWholesaleCDRs(cdrs, cgrEvs)
+	if !reflect.DeepEqual(cdrs, newCDRs()) {
+		t.Errorf("Expected the CDRs unchanged when disabled, received: %s", utils.ToJSON(cdrs))
+	}
+
+	cfg.CdrsCfg().CustomerRunID = utils.MetaDefault
+	cfg.CdrsCfg().SupplierRunID = "supplier"
+	cdrS.pairWholesaleCDRs(cdrs, cgrEvs)
+	eFlds := map[string]string{
+		utils.Customer:     "cust1",
+		utils.Supplier:     "supp1",
+		utils.CustomerCost: "0.3",
+		utils.SupplierCost: "0.1",
+		utils.Margin:       "0.2",
+	}
+	for i := 0; i < 2; i++ {
+		if !reflect.DeepEqual(eFlds, cdrs[i].ExtraFields) {
+			t.Errorf("Expected %s, received: %s", utils.ToJSON(eFlds), utils.ToJSON(cdrs[i].ExtraFields))
+		}
+		if cgrEvs[i].Event[utils.Margin] != "0.2" {
+			t.Errorf("Expected the event updated, received: %s", utils.ToJSON(cgrEvs[i].Event))
+		}
+	}
+	eFlds = map[string]string{ // supplier not rated
+		utils.Customer: "cust2",
+		utils.Supplier: "supp2",
+	}
+	for i := 2; i < 4; i++ {
+		if !reflect.DeepEqual(eFlds, cdrs[i].ExtraFields) {
+			t.Errorf("Expected %s, received: %s", utils.ToJSON(eFlds), utils.ToJSON(cdrs[i].ExtraFields))
+		}
+	}
+	if cdrs[4].ExtraFields != nil {
+		t.Errorf("Expected the unpaired CDR unchanged, received: %s", utils.ToJSON(cdrs[4].ExtraFields))
+	}
+}
+
+func TestCDRsPairStoredWholesaleCDRs(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.CdrsCfg().CustomerRunID = utils.MetaDefault
+	cfg.CdrsCfg().SupplierRunID = "supplier"
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	Cache = NewCacheS(cfg, nil, nil)
+	cdrS := &CDRServer{cgrCfg: cfg, cdrDb: NewInternalDB(nil, nil, false)}
+	if err := cdrS.cdrDb.SetCDR(&CDR{CGRID: "call1", RunID: "supplier",
+		Account: "supp1", Cost: 0.1}, false); err != nil {
+		t.Fatal(err)
+	}
+	cdrs := []*CDR{
+		{CGRID: "call1", RunID: utils.MetaDefault, Account: "cust1", Cost: 0.3},
+		{CGRID: "call2", RunID: utils.MetaDefault, Account: "cust2", Cost: 0.2}, // supplier CDR not received yet
+	}
+	cgrEvs := []*utils.CGREvent{cdrs[0].AsCGREvent(), cdrs[1].AsCGREvent()}
+	cdrS.pairWholesaleCDRs(cdrs, cgrEvs)
+	eFlds := map[string]string{
+		utils.Customer:     "cust1",
+		utils.Supplier:     "supp1",
+		utils.CustomerCost: "0.3",
+		utils.SupplierCost: "0.1",
+		utils.Margin:       "0.2",
+	}
+	if !reflect.DeepEqual(eFlds, cdrs[0].ExtraFields) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(eFlds), utils.ToJSON(cdrs[0].ExtraFields))
+	}
+	if cgrEvs[0].Event[utils.Margin] != "0.2" {
+		t.Errorf("Expected the event updated, received: %s", utils.ToJSON(cgrEvs[0].Event))
+	}
+	if storedCDRs, _, err := cdrS.cdrDb.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{"call1"},
+		RunIDs: []string{"supplier"}}, false); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eFlds, storedCDRs[0].ExtraFields) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(eFlds), utils.ToJSON(storedCDRs[0].ExtraFields))
+	}
+	if len(cdrs[1].ExtraFields) != 0 {
+		t.Errorf("Expected the unpaired CDR unchanged, received: %s", utils.ToJSON(cdrs[1].ExtraFields))
+	}
+
+	if err := cdrS.cdrDb.SetCDR(cdrs[1], false); err != nil {
+		t.Fatal(err)
+	}
+	cdrs = []*CDR{{CGRID: "call2", RunID: "supplier", Account: "supp2", Cost: 0.15}}
+	cgrEvs = []*utils.CGREvent{cdrs[0].AsCGREvent()}
+	cdrS.pairWholesaleCDRs(cdrs, cgrEvs)
+	eFlds = map[string]string{
+		utils.Customer:     "cust2",
+		utils.Supplier:     "supp2",
+		utils.CustomerCost: "0.2",
+		utils.SupplierCost: "0.15",
+		utils.Margin:       "0.05",
+	}
+	if !reflect.DeepEqual(eFlds, cdrs[0].ExtraFields) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(eFlds), utils.ToJSON(cdrs[0].ExtraFields))
+	}
+	if storedCDRs, _, err := cdrS.cdrDb.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{"call2"},
+		RunIDs: []string{utils.MetaDefault}}, false); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eFlds, storedCDRs[0].ExtraFields) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(eFlds), utils.ToJSON(storedCDRs[0].ExtraFields))
+	}
+}
+
+func TestCDRsV1GetWholesaleMargins(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	Cache = NewCacheS(cfg, nil, nil)
+	cdrS := &CDRServer{cgrCfg: cfg, cdrDb: NewInternalDB(nil, nil, false)}
+	var reply []*WholesaleMargin
+	if err := cdrS.V1GetWholesaleMargins(new(ArgsWholesaleMargins), &reply); err != utils.ErrNotImplemented {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotImplemented, err)
+	}
+	cfg.CdrsCfg().CustomerRunID = utils.MetaDefault
+	cfg.CdrsCfg().SupplierRunID = "supplier"
+	if err := cdrS.V1GetWholesaleMargins(&ArgsWholesaleMargins{GroupBy: "*account"},
+		&reply); err == nil || err.Error() != "NOT_IMPLEMENTED:*account" {
+		t.Errorf("Expected error: NOT_IMPLEMENTED:*account, received: %v", err)
+	}
+
+	for _, cdr := range []*CDR{
+		{CGRID: "call1", RunID: utils.MetaDefault, Destination: "4986", Usage: time.Minute,
+			ExtraFields: map[string]string{utils.Customer: "cust1", utils.Supplier: "supp1",
+				utils.CustomerCost: "0.3", utils.SupplierCost: "0.1", utils.Margin: "0.2"}},
+		{CGRID: "call1", RunID: "supplier", Destination: "4986", Usage: time.Minute,
+			ExtraFields: map[string]string{utils.Customer: "cust1", utils.Supplier: "supp1",
+				utils.CustomerCost: "0.3", utils.SupplierCost: "0.1", utils.Margin: "0.2"}},
+		{CGRID: "call2", RunID: utils.MetaDefault, Destination: "4986", Usage: 2 * time.Minute,
+			ExtraFields: map[string]string{utils.Customer: "cust2", utils.Supplier: "supp1",
+				utils.CustomerCost: "0.6", utils.SupplierCost: "0.25", utils.Margin: "0.35"}},
+		{CGRID: "call3", RunID: utils.MetaDefault, Destination: "4475", Usage: time.Minute,
+			ExtraFields: map[string]string{utils.Customer: "cust1", utils.Supplier: "supp2",
+				utils.CustomerCost: "0.4", utils.SupplierCost: "0.5", utils.Margin: "-0.1"}},
+		{CGRID: "call4", RunID: utils.MetaDefault, Destination: "4475", Usage: time.Minute}, // not paired
+	} {
+		if err := cdrS.cdrDb.SetCDR(cdr, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := cdrS.V1GetWholesaleMargins(&ArgsWholesaleMargins{GroupBy: utils.MetaSupplier}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*WholesaleMargin{
+		{Group: "supp1", CDRs: 2, Usage: 3 * time.Minute, CustomerCost: 0.9, SupplierCost: 0.35, Margin: 0.55},
+		{Group: "supp2", CDRs: 1, Usage: time.Minute, CustomerCost: 0.4, SupplierCost: 0.5, Margin: -0.1},
+	}
+	if !reflect.DeepEqual(exp, reply) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(exp), utils.ToJSON(reply))
+	}
+
+	if err := cdrS.V1GetWholesaleMargins(&ArgsWholesaleMargins{GroupBy: utils.MetaCustomer}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	exp = []*WholesaleMargin{
+		{Group: "cust1", CDRs: 2, Usage: 2 * time.Minute, CustomerCost: 0.7, SupplierCost: 0.6, Margin: 0.1},
+		{Group: "cust2", CDRs: 1, Usage: 2 * time.Minute, CustomerCost: 0.6, SupplierCost: 0.25, Margin: 0.35},
+	}
+	if !reflect.DeepEqual(exp, reply) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(exp), utils.ToJSON(reply))
+	}
+
+	if err := cdrS.V1GetWholesaleMargins(&ArgsWholesaleMargins{
+		RPCCDRsFilterWithOpts: utils.RPCCDRsFilterWithOpts{
+			RPCCDRsFilter: &utils.RPCCDRsFilter{DestinationPrefixes: []string{"49"}},
+		},
+		GroupBy: utils.MetaDestination,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	exp = []*WholesaleMargin{
+		{Group: "4986", CDRs: 2, Usage: 3 * time.Minute, CustomerCost: 0.9, SupplierCost: 0.35, Margin: 0.55},
+	}
+	if !reflect.DeepEqual(exp, reply) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(exp), utils.ToJSON(reply))
+	}
+
+	for _, cgrID := range []string{"call5", "call6", "call7"} { // costs lost if rounded on each CDR
+		if err := cdrS.cdrDb.SetCDR(&CDR{CGRID: cgrID, RunID: utils.MetaDefault, Destination: "3312", Usage: time.Second,
+			ExtraFields: map[string]string{utils.Customer: "cust3", utils.Supplier: "supp3",
+				utils.CustomerCost: "0.000004", utils.SupplierCost: "0", utils.Margin: "0.000004"}}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cdrS.V1GetWholesaleMargins(&ArgsWholesaleMargins{
+		RPCCDRsFilterWithOpts: utils.RPCCDRsFilterWithOpts{
+			RPCCDRsFilter: &utils.RPCCDRsFilter{DestinationPrefixes: []string{"33"}},
+		},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	exp = []*WholesaleMargin{
+		{CDRs: 3, Usage: 3 * time.Second, CustomerCost: 0.00001, Margin: 0.00001},
+	}
+	if !reflect.DeepEqual(exp, reply) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(exp), utils.ToJSON(reply))
+	}
+}
+
+func TestCDRsV1GetCDRsAggregates(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	Cache = NewCacheS(cfg, nil, nil)
+	cdrS := &CDRServer{cgrCfg: cfg, cdrDb: NewInternalDB(nil, nil, false)}
+	var reply []*utils.CDRsAggregate
+	if err := cdrS.V1GetCDRsAggregates(&utils.RPCCDRsAggregationWithOpts{GroupBy: []string{utils.Usage}},
+		&reply); err == nil || err.Error() != `GroupBy: "Usage" not supported` {
+		t.Errorf("Expected error, received: %v", err)
+	}
+	if err := cdrS.V1GetCDRsAggregates(&utils.RPCCDRsAggregationWithOpts{GroupBy: []string{utils.AccountField}},
+		&reply); err != utils.ErrNotFound {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotFound, err)
+	}
+	aTime := time.Date(2020, 12, 1, 10, 30, 0, 0, time.UTC)
+	for _, cdr := range []*CDR{
+		{CGRID: "call1", RunID: utils.MetaDefault, Account: "1001", Destination: "4986517174963",
+			AnswerTime: aTime, Usage: time.Minute, Cost: 0.1},
+		{CGRID: "call2", RunID: utils.MetaDefault, Account: "1001", Destination: "4986517174964",
+			AnswerTime: aTime.Add(time.Hour), Usage: 3 * time.Minute, Cost: 0.3},
+		{CGRID: "call3", RunID: utils.MetaDefault, Account: "1001", Destination: "4475",
+			AnswerTime: aTime.Add(24 * time.Hour), Usage: 2 * time.Minute, Cost: 0.2},
+		{CGRID: "call4", RunID: utils.MetaDefault, Account: "1002", Destination: "4986517174963",
+			AnswerTime: aTime, Usage: 10 * time.Second, Cost: 0.05},
+		{CGRID: "call4", RunID: "supplier", Account: "1002", Destination: "4986517174963",
+			AnswerTime: aTime, Usage: 10 * time.Second, Cost: 0.01},
+		{CGRID: "call5", RunID: utils.MetaDefault, Account: "1002", Destination: "4986517174965",
+			AnswerTime: aTime, Usage: 20 * time.Second, Cost: -1}, // not rated
+	} {
+		if err := cdrS.cdrDb.SetCDR(cdr, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := cdrS.V1GetCDRsAggregates(&utils.RPCCDRsAggregationWithOpts{
+		RPCCDRsFilter: &utils.RPCCDRsFilter{RunIDs: []string{utils.MetaDefault}},
+		GroupBy:       []string{utils.AccountField, "Destination;4"},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*utils.CDRsAggregate{
+		{Group: map[string]string{utils.AccountField: "1001", "Destination;4": "4475"},
+			Count: 1, Usage: 2 * time.Minute, MinUsage: 2 * time.Minute, MaxUsage: 2 * time.Minute,
+			Cost: 0.2, MinCost: 0.2, MaxCost: 0.2},
+		{Group: map[string]string{utils.AccountField: "1001", "Destination;4": "4986"},
+			Count: 2, Usage: 4 * time.Minute, MinUsage: time.Minute, MaxUsage: 3 * time.Minute,
+			Cost: 0.4, MinCost: 0.1, MaxCost: 0.3},
+		{Group: map[string]string{utils.AccountField: "1002", "Destination;4": "4986"},
+			Count: 2, Usage: 30 * time.Second, MinUsage: 10 * time.Second, MaxUsage: 20 * time.Second,
+			Cost: 0.05, MinCost: 0.05, MaxCost: 0.05},
+	}
+	if !reflect.DeepEqual(exp, reply) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(exp), utils.ToJSON(reply))
+	}
+
+	if err := cdrS.V1GetCDRsAggregates(&utils.RPCCDRsAggregationWithOpts{
+		RPCCDRsFilter: &utils.RPCCDRsFilter{
+			RunIDs: []string{utils.MetaDefault},
+			Paginator: utils.Paginator{
+				Limit:  utils.IntPointer(2),
+				Offset: utils.IntPointer(1),
+			},
+		},
+		GroupBy: []string{"AnswerTime;*hourly"},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	exp = []*utils.CDRsAggregate{
+		{Group: map[string]string{"AnswerTime;*hourly": "2020-12-01T11"},
+			Count: 1, Usage: 3 * time.Minute, MinUsage: 3 * time.Minute, MaxUsage: 3 * time.Minute,
+			Cost: 0.3, MinCost: 0.3, MaxCost: 0.3},
+		{Group: map[string]string{"AnswerTime;*hourly": "2020-12-02T10"},
+			Count: 1, Usage: 2 * time.Minute, MinUsage: 2 * time.Minute, MaxUsage: 2 * time.Minute,
+			Cost: 0.2, MinCost: 0.2, MaxCost: 0.2},
+	}
+	if !reflect.DeepEqual(exp, reply) {
+		t.Errorf("Expected %s, received: %s", utils.ToJSON(exp), utils.ToJSON(reply))
+	}
+
+	if err := cdrS.V1GetCDRsAggregates(&utils.RPCCDRsAggregationWithOpts{
+		GroupBy: []string{"AnswerTime;*daily", utils.RunID},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply) != 3 ||
+		!reflect.DeepEqual(reply[0].Group, map[string]string{"AnswerTime;*daily": "2020-12-01", utils.RunID: utils.MetaDefault}) ||
+		reply[0].Count != 4 ||
+		!reflect.DeepEqual(reply[1].Group, map[string]string{"AnswerTime;*daily": "2020-12-01", utils.RunID: "supplier"}) ||
+		!reflect.DeepEqual(reply[2].Group, map[string]string{"AnswerTime;*daily": "2020-12-02", utils.RunID: utils.MetaDefault}) {
+		t.Errorf("Unexpected aggregates: %s", utils.ToJSON(reply))
+	}
+
+	if err := cdrS.V1GetCDRsAggregates(&utils.RPCCDRsAggregationWithOpts{
+		GroupBy:   []string{utils.RunID},
+		SumFields: []string{"Cost.Extra"},
+	}, &reply); err == nil || err.Error() != `SumField: "Cost.Extra" not supported` {
+		t.Errorf("Expected error, received: %v", err)
+	}
+	for _, cdr := range []*CDR{
+		{CGRID: "call6", RunID: "carrier", Account: "1003", ExtraFields: map[string]string{"Carrier": "c1", "Fee": "0.5"}},
+		{CGRID: "call7", RunID: "carrier", Account: "1003", ExtraFields: map[string]string{"Carrier": "c1", "Fee": "1.25"}},
+		{CGRID: "call8", RunID: "carrier", Account: "1003", ExtraFields: map[string]string{"Carrier": "c2", "Fee": "free"}},
+	} {
+		if err := cdrS.cdrDb.SetCDR(cdr, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cdrS.V1GetCDRsAggregates(&utils.RPCCDRsAggregationWithOpts{
+		RPCCDRsFilter: &utils.RPCCDRsFilter{RunIDs: []string{"carrier"}},
+		GroupBy:       []string{"Carrier"},
+		SumFields:     []string{"Fee"},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply) != 2 ||
+		reply[0].Group["Carrier"] != "c1" || reply[0].Count != 2 ||
+		!reflect.DeepEqual(reply[0].Sums, map[string]float64{"Fee": 1.75}) ||
+		reply[1].Group["Carrier"] != "c2" || reply[1].Count != 1 ||
+		!reflect.DeepEqual(reply[1].Sums, map[string]float64{"Fee": 0}) { // the values that are not numbers are left out
+		t.Errorf("Unexpected aggregates: %s", utils.ToJSON(reply))
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// ConfigSection is one section of the configuration stored in ConfigDB
+type ConfigSection struct {
+	Section   string
+	Version   int64 // incremented on each change of the section
+	UpdatedAt time.Time
+	Config    string // the section as JSON
+}
+
+// configDBExcludedSections can not be stored in ConfigDB since they are needed before connecting to it
+var configDBExcludedSections = utils.NewStringSet([]string{config.DATADB_JSN, config.ConfigSJson})
+
+// ConfigDBSection returns an error if the section can not be stored in ConfigDB
+func ConfigDBSection(section string) (err error) {
+	if configDBExcludedSections.Has(section) {
+		return fmt.Errorf("section <%s> can not be stored in %s", section, utils.ConfigDB)
+	}
+	return
+}
+
+// configDBExcludedFields are the secrets not stored in ConfigDB, its sections being readable over the API
+var configDBExcludedFields = map[string][]string{
+	config.CoreSCfgJson: {utils.JWTKeyCfg, utils.APIKeysCfg},
+}
+
+// ConfigDBFields returns an error if the section as JSON has fields that can not be stored in ConfigDB
+func ConfigDBFields(section, sectJSON string) (err error) {
+	flds, has := configDBExcludedFields[section]
+	if !has {
+		return
+	}
+	var sectCfg map[string]json.RawMessage
+	if err = json.Unmarshal([]byte(sectJSON), &sectCfg); err != nil {
+		return
+	}
+	for _, fld := range flds {
+		if _, has := sectCfg[fld]; has {
+			return fmt.Errorf("field <%s> of section <%s> can not be stored in %s", fld, section, utils.ConfigDB)
+		}
+	}
+	return
+}
+
+// configSectionRetries limits the writes of a section changed meanwhile by other engines
+const configSectionRetries = 5
+
+// mergeConfigSectionJSON merges the fields of cfgJSON over the ones of the stored section
+// the objects are merged recursively, the other values(ie: lists) being replaced
+func mergeConfigSectionJSON(storedJSON, cfgJSON string) (merged string, err error) {
+	var stored, cfg interface{}
+	if err = json.Unmarshal([]byte(storedJSON), &stored); err != nil {
+		return
+	}
+	if err = json.Unmarshal([]byte(cfgJSON), &cfg); err != nil {
+		return
+	}
+	var mrgd []byte
+	if mrgd, err = json.Marshal(mergeConfigValues(stored, cfg)); err != nil {
+		return
+	}
+	return string(mrgd), nil
+}
+
+// mergeConfigValues returns the JSON value cfg merged over stored
+func mergeConfigValues(stored, cfg interface{}) interface{} {
+	storedMp, isMap := stored.(map[string]interface{})
+	if !isMap {
+		return cfg
+	}
+	cfgMp, isMap := cfg.(map[string]interface{})
+	if !isMap {
+		return cfg
+	}
+	for fld, val := range cfgMp {
+		storedMp[fld] = mergeConfigValues(storedMp[fld], val)
+	}
+	return storedMp
+}
+
+// ConfigSectionsNotifier is implemented by the DataDBs pushing the changes of the config sections to the engines
+// only RedisStorage implements it, with the other DataDBs(ie: MongoStorage) the changes are found only by the periodic sync
+type ConfigSectionsNotifier interface {
+	PublishConfigSections() error
+	SubscribeConfigSections(stopChan <-chan struct{}) (<-chan struct{}, error)
+}
+
+// NewConfigDBS returns the ConfigDBS which overlays the sections stored in ConfigDB on the config
+func NewConfigDBS(cfg *config.CGRConfig, dm *DataManager) *ConfigDBS {
+	return &ConfigDBS{
+		cfg:      cfg,
+		dm:       dm,
+		versions: make(map[string]*ConfigSection),
+	}
+}
+
+// LoadConfigDB overlays the sections stored in ConfigDB on the config over a dedicated DataDB connection
+// used at start, before the config is used, the ConfigDBS returned keeping it in sync afterwards
+func LoadConfigDB(cfg *config.CGRConfig) (cS *ConfigDBS, err error) {
+	var d DataDB
+	if d, err = NewDataDBConn(cfg.DataDbCfg().DataDbType,
+		cfg.DataDbCfg().DataDbHost, cfg.DataDbCfg().DataDbPort,
+		cfg.DataDbCfg().DataDbName, cfg.DataDbCfg().DataDbUser,
+		cfg.DataDbCfg().DataDbPass, cfg.GeneralCfg().DBDataEncoding,
+		cfg.DataDbCfg().Opts); err != nil {
+		return
+	}
+	cS = NewConfigDBS(cfg, NewDataManager(d, cfg.CacheCfg(), nil))
+	if err = cS.Load(); err != nil {
+		d.Close()
+		return nil, err
+	}
+	return
+}
+
+// ConfigDBS keeps the config in sync with the sections stored in ConfigDB
+type ConfigDBS struct {
+	sync.Mutex // one sync at the time
+	cfg        *config.CGRConfig
+	dm         *DataManager
+	versions   map[string]*ConfigSection // the sections applied on the config
+}
+
+// Load overlays the sections stored in ConfigDB on the config, without reloading the subsystems
+// used at start, before the subsystems are started
+func (cS *ConfigDBS) Load() (err error) {
+	return cS.sync(false)
+}
+
+// Close closes the DataDB connection
+func (cS *ConfigDBS) Close() {
+	cS.dm.DataDB().Close()
+}
+
+// ListenAndServe reloads the sections changed in ConfigDB when notified by the DataDB
+// and on every config_db_sync_interval if set
+func (cS *ConfigDBS) ListenAndServe(stopChan <-chan struct{}) {
+	var notifyChan <-chan struct{}
+	if notifier, canNotify := cS.dm.DataDB().(ConfigSectionsNotifier); canNotify {
+		var err error
+		if notifyChan, err = notifier.SubscribeConfigSections(stopChan); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> failed subscribing to the config changes, error: <%s>",
+					utils.ConfigDB, err.Error()))
+		}
+	}
+	var tickChan <-chan time.Time
+	if syncIntvl := cS.cfg.ConfigSCfg().ConfigDBSyncInterval; syncIntvl > 0 {
+		ticker := time.NewTicker(syncIntvl)
+		defer ticker.Stop()
+		tickChan = ticker.C
+	}
+	if notifyChan == nil && tickChan == nil {
+		return // the sections are loaded only at start
+	}
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-notifyChan:
+		case <-tickChan:
+		}
+		if err := cS.sync(true); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> failed syncing the config, error: <%s>",
+					utils.ConfigDB, err.Error()))
+		}
+	}
+}
+
+// sync applies the sections changed in ConfigDB since the last sync
+// the sections removed from ConfigDB are restored from the config files
+func (cS *ConfigDBS) sync(reload bool) (err error) {
+	cS.Lock()
+	defer cS.Unlock()
+	var cfgSects []*ConfigSection
+	if cfgSects, err = cS.dm.GetConfigSections(); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		err = nil
+	}
+	stored := make(utils.StringSet)
+	changed := make(map[string]string)
+	for _, cfgSect := range cfgSects {
+		if ConfigDBSection(cfgSect.Section) != nil ||
+			ConfigDBFields(cfgSect.Section, cfgSect.Config) != nil {
+			continue
+		}
+		stored.Add(cfgSect.Section)
+		if prev, has := cS.versions[cfgSect.Section]; has &&
+			prev.Version == cfgSect.Version &&
+			prev.Config == cfgSect.Config {
+			continue
+		}
+		changed[cfgSect.Section] = cfgSect.Config
+		cS.versions[cfgSect.Section] = cfgSect // not retried on error, only the next version is
+	}
+	if len(changed) != 0 {
+		sections := make([]string, 0, len(changed))
+		for section := range changed {
+			sections = append(sections, section)
+		}
+		sort.Strings(sections)
+		if err = cS.cfg.LoadSectionsFromJSON(changed, reload); err != nil {
+			return fmt.Errorf("failed loading sections <%s>: %s",
+				strings.Join(sections, utils.FieldsSep), err.Error())
+		}
+		utils.Logger.Info(fmt.Sprintf("<%s> loaded sections <%s>",
+			utils.ConfigDB, strings.Join(sections, utils.FieldsSep)))
+	}
+	for section := range cS.versions {
+		if stored.Has(section) {
+			continue
+		}
+		delete(cS.versions, section)
+		var rply string
+		if err = cS.cfg.V1ReloadConfig(&config.ReloadArgs{
+			Path:    cS.cfg.ConfigPath,
+			Section: section,
+		}, &rply); err != nil {
+			return fmt.Errorf("failed restoring section <%s> from <%s>: %s",
+				section, cS.cfg.ConfigPath, err.Error())
+		}
+		utils.Logger.Info(fmt.Sprintf("<%s> section <%s> removed, restored from <%s>",
+			utils.ConfigDB, section, cS.cfg.ConfigPath))
+	}
+	return
+}
@@ -0,0 +1,260 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestDataManagerConfigSections(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	dm := NewDataManager(NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	if _, err := dm.GetConfigSections(); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+	if cfgSect, err := dm.SetConfigSection(config.GENERAL_JSN, `{"node_id":"NODE1"}`); err != nil {
+		t.Fatal(err)
+	} else if cfgSect.Version != 1 {
+		t.Errorf("Expected version 1, received %v", cfgSect.Version)
+	}
+	if cfgSect, err := dm.SetConfigSection(config.GENERAL_JSN, `{"node_id":"NODE2"}`); err != nil {
+		t.Fatal(err)
+	} else if cfgSect.Version != 2 {
+		t.Errorf("Expected version 2, received %v", cfgSect.Version)
+	}
+	if cfgSect, err := dm.GetConfigSection(config.GENERAL_JSN); err != nil {
+		t.Fatal(err)
+	} else if cfgSect.Version != 2 || cfgSect.Config != `{"node_id":"NODE2"}` {
+		t.Errorf("Unexpected section: %s", utils.ToJSON(cfgSect))
+	}
+	if cfgSect, err := dm.SetConfigSection(config.GENERAL_JSN, `{"locking_timeout":"1s"}`); err != nil {
+		t.Fatal(err)
+	} else if cfgSect.Version != 3 || cfgSect.Config != `{"locking_timeout":"1s","node_id":"NODE2"}` {
+		t.Errorf("Unexpected section: %s", utils.ToJSON(cfgSect))
+	}
+	// written meanwhile by another engine
+	if err := dm.DataDB().SetConfigSectionDrv(&ConfigSection{Section: config.GENERAL_JSN,
+		Version: 3, Config: `{"node_id":"NODE3"}`}); err != utils.ErrExists {
+		t.Errorf("Expected %v, received %v", utils.ErrExists, err)
+	}
+	if err := dm.RemoveConfigSection(config.GENERAL_JSN); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.RemoveConfigSection(config.GENERAL_JSN); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+	if _, err := dm.GetConfigSection(config.GENERAL_JSN); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestMergeConfigSectionJSON(t *testing.T) {
+	if rcv, err := mergeConfigSectionJSON(
+		`{"enabled":true,"opts":{"a":1,"b":2},"ids":["id1","id2"]}`,
+		`{"opts":{"b":3},"ids":["id3"],"process_runs":2}`); err != nil {
+		t.Error(err)
+	} else if exp := `{"enabled":true,"ids":["id3"],"opts":{"a":1,"b":3},"process_runs":2}`; rcv != exp {
+		t.Errorf("Expected %s, received %s", exp, rcv)
+	}
+	if _, err := mergeConfigSectionJSON(`{"enabled":true}`, `{"enabled":`); err == nil {
+		t.Error("Expected error for invalid section")
+	}
+}
+
+func TestConfigDBSection(t *testing.T) {
+	if err := ConfigDBSection(config.GENERAL_JSN); err != nil {
+		t.Error(err)
+	}
+	expected := "section <data_db> can not be stored in ConfigDB"
+	if err := ConfigDBSection(config.DATADB_JSN); err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, received %v", expected, err)
+	}
+}
+
+func TestConfigDBFields(t *testing.T) {
+	if err := ConfigDBFields(config.CoreSCfgJson, `{"caps":10}`); err != nil {
+		t.Error(err)
+	}
+	if err := ConfigDBFields(config.GENERAL_JSN, `{"jwt_key":"secret"}`); err != nil {
+		t.Error(err)
+	}
+	expected := "field <jwt_key> of section <cores> can not be stored in ConfigDB"
+	if err := ConfigDBFields(config.CoreSCfgJson, `{"caps":10,"jwt_key":"secret"}`); err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, received %v", expected, err)
+	}
+	expected = "field <api_keys> of section <cores> can not be stored in ConfigDB"
+	if err := ConfigDBFields(config.CoreSCfgJson, `{"api_keys":{"key1":"admin"}}`); err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, received %v", expected, err)
+	}
+}
+
+func TestConfigDBSSync(t *testing.T) {
+	cfgDir, err := ioutil.TempDir(utils.EmptyString, "configdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cfgDir)
+	if err = ioutil.WriteFile(path.Join(cfgDir, "cgrates.json"),
+		[]byte(`{"general": {"node_id": "FILE_NODE"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.NewCGRConfigFromPath(cfgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbType := cfg.DataDbCfg().DataDbType
+	dm := NewDataManager(NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	cS := NewConfigDBS(cfg, dm)
+	if err = cS.Load(); err != nil { // nothing stored
+		t.Fatal(err)
+	}
+
+	if _, err = dm.SetConfigSection(config.GENERAL_JSN, `{"node_id":"CFGDB_NODE"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err = dm.DataDB().SetConfigSectionDrv(&ConfigSection{ // ignored since needed before connecting to ConfigDB
+		Section: config.DATADB_JSN,
+		Version: 1,
+		Config:  `{"db_type":"*mongo"}`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err = cS.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GeneralCfg().NodeID != "CFGDB_NODE" {
+		t.Errorf("Expected %q, received %q", "CFGDB_NODE", cfg.GeneralCfg().NodeID)
+	}
+	if cfg.DataDbCfg().DataDbType != dbType {
+		t.Errorf("Expected %q, received %q", dbType, cfg.DataDbCfg().DataDbType)
+	}
+
+	if _, err = dm.SetConfigSection(config.ATTRIBUTE_JSN, `{"enabled":true}`); err != nil {
+		t.Fatal(err)
+	}
+	if err = cS.sync(true); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.AttributeSCfg().Enabled {
+		t.Error("Expected AttributeS enabled")
+	}
+	select {
+	case <-cfg.GetReloadChan(config.ATTRIBUTE_JSN):
+	case <-time.After(10 * time.Millisecond):
+		t.Error("Expected AttributeS reload")
+	}
+	<-cfg.GetReloadChan(config.DATADB_JSN)
+
+	// removed sections are restored from the config files
+	if err = dm.RemoveConfigSection(config.GENERAL_JSN); err != nil {
+		t.Fatal(err)
+	}
+	if err = cS.sync(true); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GeneralCfg().NodeID != "FILE_NODE" {
+		t.Errorf("Expected %q, received %q", "FILE_NODE", cfg.GeneralCfg().NodeID)
+	}
+	if !cfg.AttributeSCfg().Enabled {
+		t.Error("Expected AttributeS enabled")
+	}
+
+	if _, err = dm.SetConfigSection(config.ATTRIBUTE_JSN, `{"process_runs":"a"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err = cS.sync(true); err == nil {
+		t.Error("Expected error for invalid section")
+	}
+	if err = cS.sync(true); err != nil { // not retried until the next version
+		t.Error(err)
+	}
+}
+
+type configNotifierMock struct {
+	*InternalDB
+	notifyChan chan struct{}
+}
+
+func (dbM *configNotifierMock) PublishConfigSections() error {
+	dbM.notifyChan <- struct{}{}
+	return nil
+}
+
+func (dbM *configNotifierMock) SubscribeConfigSections(<-chan struct{}) (<-chan struct{}, error) {
+	return dbM.notifyChan, nil
+}
+
+func TestConfigDBSListenAndServe(t *testing.T) {
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	cfg := config.NewDefaultCGRConfig()
+	cfg.ConfigSCfg().ConfigDBSyncInterval = 0 // only on notifications
+	Cache = NewCacheS(cfg, nil, nil)
+	dm := NewDataManager(&configNotifierMock{
+		InternalDB: NewInternalDB(nil, nil, true),
+		notifyChan: make(chan struct{}, 1),
+	}, cfg.CacheCfg(), nil)
+	cS := NewConfigDBS(cfg, dm)
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	go cS.ListenAndServe(stopChan)
+	if _, err := dm.SetConfigSection(config.ATTRIBUTE_JSN, `{"enabled":true}`); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-cfg.GetReloadChan(config.ATTRIBUTE_JSN):
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Expected AttributeS reload")
+	}
+	if !cfg.AttributeSCfg().Enabled {
+		t.Error("Expected AttributeS enabled")
+	}
+}
+
+func TestLoadConfigDB(t *testing.T) {
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	cfg := config.NewDefaultCGRConfig()
+	Cache = NewCacheS(cfg, nil, nil)
+	dm := NewDataManager(NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	if _, err := dm.SetConfigSection(config.GENERAL_JSN, `{"node_id":"CFGDB_NODE"}`); err != nil {
+		t.Fatal(err)
+	}
+	cfg.DataDbCfg().DataDbType = utils.INTERNAL // shares the Cache with the one above
+	cS, err := LoadConfigDB(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cS.Close()
+	if cfg.GeneralCfg().NodeID != "CFGDB_NODE" {
+		t.Errorf("Expected %q, received %q", "CFGDB_NODE", cfg.GeneralCfg().NodeID)
+	}
+	cfg.DataDbCfg().DataDbType = "unknown"
+	expected := "unsupported db_type <unknown>"
+	if _, err = LoadConfigDB(cfg); err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, received %v", expected, err)
+	}
+}
@@ -131,6 +131,9 @@ func (cM *ConnManager) Call(connIDs []string, biRPCClient rpcclient.ClientConnec
 	if len(connIDs) == 0 {
 		return utils.NewErrMandatoryIeMissing("connIDs")
 	}
+	var span *Span
+	span, arg = Tracer.StartCallSpan(method, arg)
+	defer func() { span.End(err) }()
 	var conn rpcclient.ClientConnector
 	for _, connID := range connIDs {
 		if conn, err = cM.getConn(connID, biRPCClient); err != nil {
@@ -139,6 +142,7 @@ func (cM *ConnManager) Call(connIDs []string, biRPCClient rpcclient.ClientConnec
 		if err = conn.Call(method, arg, reply); rpcclient.IsNetworkError(err) {
 			continue
 		} else {
+			span.SetAttribute(utils.TraceConnID, connID)
 			return
 		}
 	}
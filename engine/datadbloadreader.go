@@ -0,0 +1,399 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"github.com/cgrates/cgrates/utils"
+)
+
+// NewDataDBLoadReader returns a LoadReader over the live data in DataDB
+func NewDataDBLoadReader(dm *DataManager) *DataDBLoadReader {
+	return &DataDBLoadReader{dm: dm}
+}
+
+// DataDBLoadReader exposes the content of DataDB as tariff plan data so it can be exported with the TPExporter
+// the TPid is only set on the returned items, the tenant and ID filter the items if provided
+// the rating and accounting data of the classic tariff plan (except destinations and timings) can not be exported,
+// being skipped and listed by SkippedTables
+type DataDBLoadReader struct {
+	dm *DataManager
+}
+
+// unsupportedData are the tables of the classic tariff plan together with the prefix of the items they are stored into
+var unsupportedData = []struct {
+	name   string
+	prefix string
+}{
+	{utils.RatingPlans, utils.RatingPlanPrefix}, // includes the Rates and DestinationRates
+	{utils.RatingProfiles, utils.RatingProfilePrefix},
+	{utils.SharedGroups, utils.SharedGroupPrefix},
+	{utils.Actions, utils.ActionPrefix},
+	{utils.ActionPlans, utils.ActionPlanPrefix},
+	{utils.ActionTriggers, utils.ActionTriggerPrefix},
+	{utils.AccountActions, utils.AccountPrefix},
+}
+
+// SkippedTables returns the tables found in DataDB that can not be exported
+// their readers return NOT_FOUND so they are skipped without stopping the export of the other tables
+func (dr *DataDBLoadReader) SkippedTables() (names []string, err error) {
+	for _, data := range unsupportedData {
+		var has bool
+		if has, err = dr.hasPrefix(data.prefix); err != nil {
+			return
+		}
+		if has {
+			names = append(names, data.name)
+		}
+	}
+	return
+}
+
+// hasPrefix returns true if DataDB contains items stored with the prefix
+func (dr *DataDBLoadReader) hasPrefix(prefix string) (has bool, err error) {
+	var keys []string
+	if keys, err = dr.dm.DataDB().GetKeysForPrefix(prefix); err != nil {
+		return
+	}
+	return len(keys) != 0, nil
+}
+
+// tenantIDs returns the tenant and ID of the items stored with the prefix
+func (dr *DataDBLoadReader) tenantIDs(prefix, tenant, id string) (tntIDs []*utils.TenantID, err error) {
+	var keys []string
+	if keys, err = dr.dm.DataDB().GetKeysForPrefix(prefix); err != nil {
+		return
+	}
+	for _, key := range keys {
+		tntID := utils.NewTenantID(key[len(prefix):])
+		if (tenant != utils.EmptyString && tntID.Tenant != tenant) ||
+			(id != utils.EmptyString && tntID.ID != id) {
+			continue
+		}
+		tntIDs = append(tntIDs, tntID)
+	}
+	if len(tntIDs) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+// ids returns the IDs of the items stored with the prefix
+func (dr *DataDBLoadReader) ids(prefix, id string) (ids []string, err error) {
+	var keys []string
+	if keys, err = dr.dm.DataDB().GetKeysForPrefix(prefix); err != nil {
+		return
+	}
+	for _, key := range keys {
+		if id != utils.EmptyString && key[len(prefix):] != id {
+			continue
+		}
+		ids = append(ids, key[len(prefix):])
+	}
+	if len(ids) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTpIds(string) ([]string, error) {
+	return nil, utils.ErrNotImplemented
+}
+
+func (dr *DataDBLoadReader) GetTpTableIds(string, string, utils.TPDistinctIds,
+	map[string]string, *utils.PaginatorWithSearch) ([]string, error) {
+	return nil, utils.ErrNotImplemented
+}
+
+func (dr *DataDBLoadReader) GetTPTimings(tpid, id string) (tms []*utils.ApierTPTiming, err error) {
+	var ids []string
+	if ids, err = dr.ids(utils.TimingsPrefix, id); err != nil {
+		return
+	}
+	for _, tmID := range ids {
+		var tm *utils.TPTiming
+		if tm, err = dr.dm.GetTiming(tmID, true, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tms = append(tms, &utils.ApierTPTiming{
+			TPid:      tpid,
+			ID:        tm.ID,
+			Years:     tm.Years.Serialize(utils.InfieldSep),
+			Months:    tm.Months.Serialize(utils.InfieldSep),
+			MonthDays: tm.MonthDays.Serialize(utils.InfieldSep),
+			WeekDays:  tm.WeekDays.Serialize(utils.InfieldSep),
+			Time:      tm.StartTime,
+		})
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPDestinations(tpid, id string) (dsts []*utils.TPDestination, err error) {
+	var ids []string
+	if ids, err = dr.ids(utils.DestinationPrefix, id); err != nil {
+		return
+	}
+	for _, dstID := range ids {
+		var dst *Destination
+		if dst, err = dr.dm.GetDestination(dstID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		dsts = append(dsts, &utils.TPDestination{TPid: tpid, ID: dst.Id, Prefixes: dst.Prefixes})
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPRates(string, string) ([]*utils.TPRateRALs, error) {
+	return nil, utils.ErrNotFound
+}
+
+func (dr *DataDBLoadReader) GetTPDestinationRates(string, string, *utils.Paginator) ([]*utils.TPDestinationRate, error) {
+	return nil, utils.ErrNotFound
+}
+
+func (dr *DataDBLoadReader) GetTPRatingPlans(string, string, *utils.Paginator) ([]*utils.TPRatingPlan, error) {
+	return nil, utils.ErrNotFound
+}
+
+func (dr *DataDBLoadReader) GetTPRatingProfiles(*utils.TPRatingProfile) ([]*utils.TPRatingProfile, error) {
+	return nil, utils.ErrNotFound
+}
+
+func (dr *DataDBLoadReader) GetTPSharedGroups(string, string) ([]*utils.TPSharedGroups, error) {
+	return nil, utils.ErrNotFound
+}
+
+func (dr *DataDBLoadReader) GetTPActions(string, string) ([]*utils.TPActions, error) {
+	return nil, utils.ErrNotFound
+}
+
+func (dr *DataDBLoadReader) GetTPActionPlans(string, string) ([]*utils.TPActionPlan, error) {
+	return nil, utils.ErrNotFound
+}
+
+func (dr *DataDBLoadReader) GetTPActionTriggers(string, string) ([]*utils.TPActionTriggers, error) {
+	return nil, utils.ErrNotFound
+}
+
+func (dr *DataDBLoadReader) GetTPAccountActions(*utils.TPAccountActions) ([]*utils.TPAccountActions, error) {
+	return nil, utils.ErrNotFound
+}
+
+func (dr *DataDBLoadReader) GetTPResources(tpid, tenant, id string) (tps []*utils.TPResourceProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.ResourceProfilesPrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *ResourceProfile
+		if prf, err = dr.dm.GetResourceProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := ResourceProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPStats(tpid, tenant, id string) (tps []*utils.TPStatProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.StatQueueProfilePrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *StatQueueProfile
+		if prf, err = dr.dm.GetStatQueueProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := StatQueueProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPThresholds(tpid, tenant, id string) (tps []*utils.TPThresholdProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.ThresholdProfilePrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *ThresholdProfile
+		if prf, err = dr.dm.GetThresholdProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := ThresholdProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPFilters(tpid, tenant, id string) (tps []*utils.TPFilterProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.FilterPrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *Filter
+		if prf, err = dr.dm.GetFilter(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := FilterToTPFilter(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPRoutes(tpid, tenant, id string) (tps []*utils.TPRouteProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.RouteProfilePrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *RouteProfile
+		if prf, err = dr.dm.GetRouteProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := RouteProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPAttributes(tpid, tenant, id string) (tps []*utils.TPAttributeProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.AttributeProfilePrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *AttributeProfile
+		if prf, err = dr.dm.GetAttributeProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := AttributeProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPChargers(tpid, tenant, id string) (tps []*utils.TPChargerProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.ChargerProfilePrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *ChargerProfile
+		if prf, err = dr.dm.GetChargerProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := ChargerProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPDispatcherProfiles(tpid, tenant, id string) (tps []*utils.TPDispatcherProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.DispatcherProfilePrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *DispatcherProfile
+		if prf, err = dr.dm.GetDispatcherProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := DispatcherProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPDispatcherHosts(tpid, tenant, id string) (tps []*utils.TPDispatcherHost, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.DispatcherHostPrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *DispatcherHost
+		if prf, err = dr.dm.GetDispatcherHost(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := DispatcherHostToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPRateProfiles(tpid, tenant, id string) (tps []*utils.TPRateProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.RateProfilePrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *RateProfile
+		if prf, err = dr.dm.GetRateProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := RateProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPActionProfiles(tpid, tenant, id string) (tps []*utils.TPActionProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.ActionProfilePrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *ActionProfile
+		if prf, err = dr.dm.GetActionProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := ActionProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
+
+func (dr *DataDBLoadReader) GetTPAccountProfiles(tpid, tenant, id string) (tps []*utils.TPAccountProfile, err error) {
+	var tntIDs []*utils.TenantID
+	if tntIDs, err = dr.tenantIDs(utils.AccountProfilePrefix, tenant, id); err != nil {
+		return
+	}
+	for _, tntID := range tntIDs {
+		var prf *utils.AccountProfile
+		if prf, err = dr.dm.GetAccountProfile(tntID.Tenant, tntID.ID, false, false, utils.NonTransactional); err != nil {
+			return nil, err
+		}
+		tp := AccountProfileToAPI(prf)
+		tp.TPid = tpid
+		tps = append(tps, tp)
+	}
+	return
+}
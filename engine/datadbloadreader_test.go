@@ -0,0 +1,144 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"io/ioutil"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestDataDBLoadReaderExport(t *testing.T) {
+	tmpCache := Cache
+	defer func() {
+		Cache = tmpCache
+	}()
+	cfg := config.NewDefaultCGRConfig()
+	Cache = NewCacheS(cfg, nil, nil)
+	dm := NewDataManager(NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	if err := dm.SetDestination(&Destination{Id: "DST_1002", Prefixes: []string{"1002", "1003"}},
+		utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetResourceProfile(&ResourceProfile{
+		Tenant:       "cgrates.org",
+		ID:           "RES_1",
+		FilterIDs:    []string{"*string:~*req.Account:1001"},
+		Limit:        2,
+		ThresholdIDs: []string{utils.MetaNone},
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetResourceProfile(&ResourceProfile{
+		Tenant:       "itsyscom.com",
+		ID:           "RES_2",
+		Limit:        1,
+		ThresholdIDs: []string{utils.MetaNone},
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+	dr := NewDataDBLoadReader(dm)
+
+	expDst := []*utils.TPDestination{{TPid: utils.MetaDataDB, ID: "DST_1002", Prefixes: []string{"1002", "1003"}}}
+	if rcv, err := dr.GetTPDestinations(utils.MetaDataDB, utils.EmptyString); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expDst, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expDst), utils.ToJSON(rcv))
+	}
+	if rcv, err := dr.GetTPResources(utils.MetaDataDB, "cgrates.org", utils.EmptyString); err != nil {
+		t.Error(err)
+	} else if len(rcv) != 1 || rcv[0].ID != "RES_1" || rcv[0].TPid != utils.MetaDataDB {
+		t.Errorf("Unexpected resources: %s", utils.ToJSON(rcv))
+	}
+	if _, err := dr.GetTPResources(utils.MetaDataDB, "cgrates.org", "RES_2"); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	if _, err := dr.GetTPRateProfiles(utils.MetaDataDB, utils.EmptyString, utils.EmptyString); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+
+	expPath := t.TempDir()
+	tpExp, err := NewTPExporter(dr, utils.MetaDataDB, expPath, utils.CSV, utils.FieldsSep, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tpExp.Run(); err != nil {
+		t.Fatal(err)
+	}
+	exported := tpExp.ExportStats().ExportedFiles
+	sort.Strings(exported)
+	if expFiles := []string{utils.DestinationsCsv, utils.ResourcesCsv}; !reflect.DeepEqual(expFiles, exported) {
+		t.Errorf("Expected %+v, received %+v", expFiles, exported)
+	}
+	if content, err := ioutil.ReadFile(path.Join(expPath, utils.DestinationsCsv)); err != nil {
+		t.Error(err)
+	} else if exp := "DST_1002,1002\nDST_1002,1003\n"; string(content) != exp {
+		t.Errorf("Expected %q, received %q", exp, string(content))
+	}
+}
+
+func TestDataDBLoadReaderSkippedTables(t *testing.T) {
+	tmpCache := Cache
+	defer func() {
+		Cache = tmpCache
+	}()
+	cfg := config.NewDefaultCGRConfig()
+	Cache = NewCacheS(cfg, nil, nil)
+	dm := NewDataManager(NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	dr := NewDataDBLoadReader(dm)
+	if skipped, err := dr.SkippedTables(); err != nil {
+		t.Error(err)
+	} else if len(skipped) != 0 {
+		t.Errorf("Unexpected skipped tables: %+v", skipped)
+	}
+
+	if err := dm.SetSharedGroup(&SharedGroup{Id: "SG_1"}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetAccount(&Account{ID: "cgrates.org:1001"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetDestination(&Destination{Id: "DST_1002", Prefixes: []string{"1002"}},
+		utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if skipped, err := dr.SkippedTables(); err != nil {
+		t.Error(err)
+	} else if exp := []string{utils.SharedGroups, utils.AccountActions}; !reflect.DeepEqual(exp, skipped) {
+		t.Errorf("Expected %+v, received %+v", exp, skipped)
+	}
+	if _, err := dr.GetTPSharedGroups(utils.MetaDataDB, utils.EmptyString); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	tpExp, err := NewTPExporter(dr, utils.MetaDataDB, t.TempDir(), utils.CSV, utils.FieldsSep, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the skipped tables do not stop the export of the supported ones
+	if err := tpExp.Run(); err != nil {
+		t.Error(err)
+	} else if exp := []string{utils.DestinationsCsv}; !reflect.DeepEqual(exp, tpExp.ExportStats().ExportedFiles) {
+		t.Errorf("Expected %+v, received %+v", exp, tpExp.ExportStats().ExportedFiles)
+	}
+}
@@ -20,6 +20,7 @@ package engine
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -385,6 +386,18 @@ func (dbM *DataDBMock) RemoveDispatcherHostDrv(string, string) error {
 	return utils.ErrNotImplemented
 }
 
+func (dbM *DataDBMock) GetPortedNumberDrv(string, string) (*PortedNumber, error) {
+	return nil, utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) SetPortedNumberDrv(*PortedNumber) error {
+	return utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) RemovePortedNumberDrv(string, string) error {
+	return utils.ErrNotImplemented
+}
+
 func (dbM *DataDBMock) GetRateProfileDrv(string, string) (*RateProfile, error) {
 	return nil, utils.ErrNotImplemented
 }
@@ -421,6 +434,46 @@ func (dbM *DataDBMock) RemoveAccountProfileDrv(string, string) error {
 	return utils.ErrNotImplemented
 }
 
+func (dbM *DataDBMock) SetDedupIDDrv(string, time.Duration) error {
+	return utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) RemoveDedupIDDrv(string) error {
+	return utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) GetSharedSessionsDrv() ([]*SharedSession, error) {
+	return nil, utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) SetSharedSessionDrv(*SharedSession) error {
+	return utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) ClaimSharedSessionDrv(string, string, time.Time) (*SharedSession, error) {
+	return nil, utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) RemoveSharedSessionDrv(string, string) error {
+	return utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) GetConfigSectionsDrv() ([]*ConfigSection, error) {
+	return nil, utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) GetConfigSectionDrv(string) (*ConfigSection, error) {
+	return nil, utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) SetConfigSectionDrv(*ConfigSection) error {
+	return utils.ErrNotImplemented
+}
+
+func (dbM *DataDBMock) RemoveConfigSectionDrv(string) error {
+	return utils.ErrNotImplemented
+}
+
 func (dbM *DataDBMock) SetVersions(vrs Versions, overwrite bool) (err error) {
 	return utils.ErrNotImplemented
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/cgrates/baningo"
 	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
 	"github.com/cgrates/cgrates/utils"
 	"github.com/cgrates/ltcache"
 )
@@ -65,6 +66,7 @@ var (
 		utils.ChargerProfilePrefix:          {},
 		utils.DispatcherProfilePrefix:       {},
 		utils.DispatcherHostPrefix:          {},
+		utils.PortedNumberPrefix:            {},
 		utils.RateProfilePrefix:             {},
 		utils.ActionProfilePrefix:           {},
 		utils.AttributeFilterIndexes:        {},
@@ -224,6 +226,9 @@ func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached b
 		case utils.DispatcherHostPrefix:
 			tntID := utils.NewTenantID(dataID)
 			_, err = dm.GetDispatcherHost(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+		case utils.PortedNumberPrefix:
+			tntID := utils.NewTenantID(dataID)
+			_, err = dm.GetPortedNumber(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
 		case utils.RateProfilePrefix:
 			tntID := utils.NewTenantID(dataID)
 			_, err = dm.GetRateProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
@@ -634,6 +639,11 @@ func (dm *DataManager) SetAccount(acc *Account) (err error) {
 		err = utils.ErrNoDatabaseConn
 		return
 	}
+	// make sure the account lock was not lost while processing it
+	if err = guardian.Guardian.CheckToken(utils.AccountPrefix+acc.ID,
+		acc.lkTokens[utils.AccountPrefix+acc.ID]); err != nil {
+		return
+	}
 	if err = dm.dataDB.SetAccountDrv(acc); err != nil {
 		return
 	}
@@ -3121,6 +3131,113 @@ func (dm *DataManager) RemoveDispatcherHost(tenant, id string,
 	return
 }
 
+// GetPortedNumber returns the number portability entry of the number
+func (dm *DataManager) GetPortedNumber(tenant, id string, cacheRead, cacheWrite bool,
+	transactionID string) (pn *PortedNumber, err error) {
+	tntID := utils.ConcatenatedKey(tenant, id)
+	if cacheRead {
+		if x, ok := Cache.Get(utils.CachePortedNumbers, tntID); ok {
+			if x == nil {
+				return nil, utils.ErrNotFound
+			}
+			return x.(*PortedNumber), nil
+		}
+	}
+	if dm == nil {
+		err = utils.ErrNoDatabaseConn
+		return
+	}
+	pn, err = dm.dataDB.GetPortedNumberDrv(tenant, id)
+	if err != nil {
+		if itm := config.CgrConfig().DataDbCfg().Items[utils.MetaPortedNumbers]; err == utils.ErrNotFound && itm.Remote {
+			if err = dm.connMgr.Call(config.CgrConfig().DataDbCfg().RmtConns, nil,
+				utils.ReplicatorSv1GetPortedNumber,
+				&utils.TenantIDWithOpts{
+					TenantID: &utils.TenantID{Tenant: tenant, ID: id},
+					Opts: map[string]interface{}{
+						utils.OptsAPIKey:  itm.APIKey,
+						utils.OptsRouteID: itm.RouteID,
+					}}, &pn); err == nil {
+				err = dm.dataDB.SetPortedNumberDrv(pn)
+			}
+		}
+		if err != nil {
+			err = utils.CastRPCErr(err)
+			if err == utils.ErrNotFound && cacheWrite {
+				if errCh := Cache.Set(utils.CachePortedNumbers, tntID, nil, nil,
+					cacheCommit(transactionID), transactionID); errCh != nil {
+					return nil, errCh
+				}
+			}
+			return nil, err
+		}
+	}
+	if cacheWrite {
+		if err = Cache.Set(utils.CachePortedNumbers, tntID, pn, nil,
+			cacheCommit(transactionID), transactionID); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// SetPortedNumber stores the number portability entry
+func (dm *DataManager) SetPortedNumber(pn *PortedNumber) (err error) {
+	if dm == nil {
+		err = utils.ErrNoDatabaseConn
+		return
+	}
+	if err = dm.DataDB().SetPortedNumberDrv(pn); err != nil {
+		return
+	}
+	if itm := config.CgrConfig().DataDbCfg().Items[utils.MetaPortedNumbers]; itm.Replicate {
+		var reply string
+		if err = dm.connMgr.Call(config.CgrConfig().DataDbCfg().RplConns, nil,
+			utils.ReplicatorSv1SetPortedNumber,
+			&PortedNumberWithOpts{
+				PortedNumber: pn,
+				Opts: map[string]interface{}{
+					utils.OptsAPIKey:  itm.APIKey,
+					utils.OptsRouteID: itm.RouteID,
+				}}, &reply); err != nil {
+			err = utils.CastRPCErr(err)
+			return
+		}
+	}
+	return
+}
+
+// RemovePortedNumber removes the number portability entry
+func (dm *DataManager) RemovePortedNumber(tenant, id string,
+	transactionID string) (err error) {
+	if dm == nil {
+		err = utils.ErrNoDatabaseConn
+		return
+	}
+	oldPn, err := dm.GetPortedNumber(tenant, id, true, false, utils.NonTransactional)
+	if err != nil && err != utils.ErrNotFound {
+		return err
+	}
+	if err = dm.DataDB().RemovePortedNumberDrv(tenant, id); err != nil {
+		return
+	}
+	if oldPn == nil {
+		return utils.ErrNotFound
+	}
+	if itm := config.CgrConfig().DataDbCfg().Items[utils.MetaPortedNumbers]; itm.Replicate {
+		var reply string
+		dm.connMgr.Call(config.CgrConfig().DataDbCfg().RplConns, nil,
+			utils.ReplicatorSv1RemovePortedNumber,
+			&utils.TenantIDWithOpts{
+				TenantID: &utils.TenantID{Tenant: tenant, ID: id},
+				Opts: map[string]interface{}{
+					utils.OptsAPIKey:  itm.APIKey,
+					utils.OptsRouteID: itm.RouteID,
+				}}, &reply)
+	}
+	return
+}
+
 func (dm *DataManager) GetItemLoadIDs(itemIDPrefix string, cacheWrite bool) (loadIDs map[string]int64, err error) {
 	if dm == nil {
 		err = utils.ErrNoDatabaseConn
@@ -3918,3 +4035,141 @@ func (dm *DataManager) RemoveAccountProfile(tenant, id string,
 	}
 	return
 }
+
+// InitDedupIDs prepares the DataDB to store the deduplication IDs
+// Mongo needs the unique index rejecting the active IDs, missing on the databases created before it
+func (dm *DataManager) InitDedupIDs() (err error) {
+	if dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	if ms, isMongo := dm.DataDB().(*MongoStorage); isMongo {
+		return ms.ensureDedupIndexes()
+	}
+	return
+}
+
+// SetDedupID stores the ID for ttl, returning utils.ErrExists if the ID is already stored
+func (dm *DataManager) SetDedupID(id string, ttl time.Duration) (err error) {
+	if dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	return dm.DataDB().SetDedupIDDrv(id, ttl)
+}
+
+// RemoveDedupID removes the ID from the deduplication store
+func (dm *DataManager) RemoveDedupID(id string) (err error) {
+	if dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	return dm.DataDB().RemoveDedupIDDrv(id)
+}
+
+// GetSharedSessions returns all the sessions in the shared registry
+func (dm *DataManager) GetSharedSessions() (sss []*SharedSession, err error) {
+	if dm == nil {
+		return nil, utils.ErrNoDatabaseConn
+	}
+	return dm.DataDB().GetSharedSessionsDrv()
+}
+
+// SetSharedSession stores the session on behalf of ss.NodeID
+// returns utils.ErrExists if the session is owned by another node with an active lease
+func (dm *DataManager) SetSharedSession(ss *SharedSession) (err error) {
+	if dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	return dm.DataDB().SetSharedSessionDrv(ss)
+}
+
+// ClaimSharedSession moves the ownership of the session to nodeID if the lease of the previous owner expired
+// returns utils.ErrNotFound if the session is missing or its lease is still active
+func (dm *DataManager) ClaimSharedSession(cgrID, nodeID string, expiry time.Time) (ss *SharedSession, err error) {
+	if dm == nil {
+		return nil, utils.ErrNoDatabaseConn
+	}
+	return dm.DataDB().ClaimSharedSessionDrv(cgrID, nodeID, expiry)
+}
+
+// RemoveSharedSession removes the session from the shared registry if owned by nodeID
+func (dm *DataManager) RemoveSharedSession(cgrID, nodeID string) (err error) {
+	if dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	return dm.DataDB().RemoveSharedSessionDrv(cgrID, nodeID)
+}
+
+// GetConfigSections returns all the config sections stored in ConfigDB
+func (dm *DataManager) GetConfigSections() (cfgSects []*ConfigSection, err error) {
+	if dm == nil {
+		return nil, utils.ErrNoDatabaseConn
+	}
+	if cfgSects, err = dm.DataDB().GetConfigSectionsDrv(); err != nil {
+		return
+	}
+	if len(cfgSects) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+// GetConfigSection returns the config section stored in ConfigDB
+func (dm *DataManager) GetConfigSection(section string) (cfgSect *ConfigSection, err error) {
+	if dm == nil {
+		return nil, utils.ErrNoDatabaseConn
+	}
+	return dm.DataDB().GetConfigSectionDrv(section)
+}
+
+// SetConfigSection merges the section with the one stored in ConfigDB, incrementing its version
+// the version is checked by the DataDB on write so the changes done meanwhile by other engines are merged, not lost
+func (dm *DataManager) SetConfigSection(section, cfgJSON string) (cfgSect *ConfigSection, err error) {
+	if dm == nil {
+		return nil, utils.ErrNoDatabaseConn
+	}
+	for i := 0; i < configSectionRetries; i++ {
+		cfgSect = &ConfigSection{
+			Section:   section,
+			Version:   1,
+			UpdatedAt: time.Now(),
+			Config:    cfgJSON,
+		}
+		var prev *ConfigSection
+		if prev, err = dm.DataDB().GetConfigSectionDrv(section); err != nil {
+			if err != utils.ErrNotFound {
+				return nil, err
+			}
+		} else {
+			cfgSect.Version = prev.Version + 1
+			if cfgSect.Config, err = mergeConfigSectionJSON(prev.Config, cfgJSON); err != nil {
+				return nil, err
+			}
+		}
+		if err = dm.DataDB().SetConfigSectionDrv(cfgSect); err != utils.ErrExists {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = dm.publishConfigSections()
+	return
+}
+
+// RemoveConfigSection removes the section from ConfigDB
+func (dm *DataManager) RemoveConfigSection(section string) (err error) {
+	if dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	if err = dm.DataDB().RemoveConfigSectionDrv(section); err != nil {
+		return
+	}
+	return dm.publishConfigSections()
+}
+
+// publishConfigSections notifies the engines about the changed config sections if the DataDB supports it
+func (dm *DataManager) publishConfigSections() (err error) {
+	if notifier, canNotify := dm.DataDB().(ConfigSectionsNotifier); canNotify {
+		err = notifier.PublishConfigSections()
+	}
+	return
+}
@@ -165,7 +165,12 @@ func (dH *DispatcherHost) Call(serviceMethod string, args interface{}, reply int
 		}
 
 	}
-	return dH.rpcConn.Call(serviceMethod, args, reply)
+	var span *Span
+	span, args = Tracer.StartCallSpan(serviceMethod, args)
+	span.SetAttribute(utils.TraceDispatcherHost, dH.TenantID())
+	err = dH.rpcConn.Call(serviceMethod, args, reply)
+	span.End(err)
+	return
 }
 
 type DispatcherHostIDs []string
@@ -34,6 +34,7 @@ func newDynamicDP(resConns, stsConns, apiConns []string,
 		tenant:    tenant,
 		initialDP: initialDP,
 		cache:     utils.MapStorage{},
+		lookups:   make(map[string]interface{}),
 	}
 }
 
@@ -44,7 +45,8 @@ type dynamicDP struct {
 	tenant    string
 	initialDP utils.DataProvider
 
-	cache utils.MapStorage
+	cache   utils.MapStorage
+	lookups map[string]interface{} // replies of the *http and *sql attribute lookups done for the event
 }
 
 func (dDP *dynamicDP) String() string { return dDP.initialDP.String() }
@@ -131,6 +133,18 @@ func (dDP *dynamicDP) fieldAsInterface(fldPath []string) (val interface{}, err e
 		}
 		dDP.cache.Set(fldPath[:2], dp)
 		return dp.FieldAsInterface(fldPath[2:])
+	case utils.MetaLNP:
+		// sample of fieldName ~*lnp.<~*req.Destination>
+		// or ~*lnp.<~*req.Destination>.Carrier
+		// not cached since the DataManager caches the entries
+		pn, err := lookupPortedNumber(dm, dDP.tenant, fldPath[1])
+		if err != nil {
+			return nil, err
+		}
+		if len(fldPath) == 2 {
+			return pn.RoutingNumber, nil
+		}
+		return config.NewObjectDP(pn).FieldAsInterface(fldPath[2:])
 	case utils.MetaAsm:
 		// sample of fieldName ~*asm.BalanceSummaries.HolidayBalance.Value
 		stringReq, err := dDP.initialDP.FieldAsString([]string{utils.MetaReq})
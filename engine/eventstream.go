@@ -0,0 +1,180 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// EventStream distributes the live events published by the subsystems to the subscribers
+// it is nil(disabled) unless the events handler is configured
+var EventStream *EventStreamS
+
+// eventSubscriptionQueue is the number of events buffered for one subscriber
+// the events are dropped for the subscribers that do not keep up
+const eventSubscriptionQueue = 512
+
+// NewEventStreamS returns a new EventStreamS
+// only the inline filters are used until the FilterS is set
+func NewEventStreamS(cfg *config.CGRConfig) *EventStreamS {
+	return &EventStreamS{
+		fltrS: NewFilterS(cfg, nil, nil),
+		subs:  make(map[*EventSubscription]struct{}),
+	}
+}
+
+// EventStreamS keeps the subscriptions to the live events
+type EventStreamS struct {
+	sync.RWMutex
+	fltrS *FilterS
+	subs  map[*EventSubscription]struct{}
+}
+
+// StreamEvent is a live event sent to the subscribers
+type StreamEvent struct {
+	Type   string
+	Tenant string
+	Time   time.Time
+	Event  map[string]interface{}
+}
+
+// EventSubscription receives the live events of one tenant matching its types and filters
+type EventSubscription struct {
+	Tenant  string
+	Types   utils.StringSet // all the types if empty
+	Filters []string
+	Events  chan *StreamEvent
+}
+
+// SetFilterS sets the FilterS used to check the events, including the filter profiles
+func (es *EventStreamS) SetFilterS(fltrS *FilterS) {
+	if es == nil {
+		return
+	}
+	es.Lock()
+	es.fltrS = fltrS
+	es.Unlock()
+}
+
+// Subscribe returns a new subscription, checking the inline filters
+func (es *EventStreamS) Subscribe(tnt string, types, filters []string) (sub *EventSubscription, err error) {
+	for _, fltrID := range filters {
+		if !strings.HasPrefix(fltrID, utils.Meta) {
+			continue
+		}
+		if _, err = NewFilterFromInline(tnt, fltrID); err != nil {
+			return
+		}
+	}
+	sub = &EventSubscription{
+		Tenant:  tnt,
+		Types:   utils.NewStringSet(types),
+		Filters: filters,
+		Events:  make(chan *StreamEvent, eventSubscriptionQueue),
+	}
+	es.Lock()
+	es.subs[sub] = struct{}{}
+	es.Unlock()
+	return
+}
+
+// Unsubscribe removes the subscription and closes its events channel
+func (es *EventStreamS) Unsubscribe(sub *EventSubscription) {
+	es.Lock()
+	if _, has := es.subs[sub]; has {
+		delete(es.subs, sub)
+		close(sub.Events)
+	}
+	es.Unlock()
+}
+
+// Publish sends the event to the subscriptions of the tenant interested in its type
+// the event is deep copied so the caller can change it, including its nested maps, afterwards
+func (es *EventStreamS) Publish(evType, tnt string, ev map[string]interface{}) {
+	if es == nil {
+		return
+	}
+	es.RLock()
+	defer es.RUnlock()
+	var sEv *StreamEvent
+	for sub := range es.subs {
+		if sub.Tenant != tnt ||
+			(sub.Types.Size() != 0 && !sub.Types.Has(evType)) {
+			continue
+		}
+		if sEv == nil {
+			sEv = &StreamEvent{
+				Type:   evType,
+				Tenant: tnt,
+				Time:   time.Now(),
+				Event:  cloneStreamEvent(ev),
+			}
+		}
+		select {
+		case sub.Events <- sEv:
+		default: // slow subscriber
+		}
+	}
+}
+
+// cloneStreamEvent deep copies the maps and slices of the event, read by the subscribers in their own goroutines
+func cloneStreamEvent(ev map[string]interface{}) (cln map[string]interface{}) {
+	cln = make(map[string]interface{}, len(ev))
+	for k, v := range ev {
+		cln[k] = cloneStreamValue(v)
+	}
+	return
+}
+
+func cloneStreamValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return cloneStreamEvent(val)
+	case MapEvent:
+		return MapEvent(cloneStreamEvent(val))
+	case []interface{}:
+		cln := make([]interface{}, len(val))
+		for i, itm := range val {
+			cln[i] = cloneStreamValue(itm)
+		}
+		return cln
+	case []string:
+		return append(make([]string, 0, len(val)), val...)
+	case map[string]string:
+		cln := make(map[string]string, len(val))
+		for k, itm := range val {
+			cln[k] = itm
+		}
+		return cln
+	}
+	return v
+}
+
+// Pass checks the event against the filters of the subscription
+func (es *EventStreamS) Pass(sub *EventSubscription, ev *StreamEvent) (bool, error) {
+	es.RLock()
+	fltrS := es.fltrS
+	es.RUnlock()
+	return fltrS.Pass(sub.Tenant, sub.Filters, utils.MapStorage{utils.MetaReq: ev.Event})
+}
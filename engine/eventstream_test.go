@@ -0,0 +1,127 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestEventStreamPublish(t *testing.T) {
+	var es *EventStreamS
+	es.Publish(utils.MetaCDRProcessed, "cgrates.org", nil) // disabled
+	es.SetFilterS(nil)
+
+	es = NewEventStreamS(config.NewDefaultCGRConfig())
+	if _, err := es.Subscribe("cgrates.org", nil, []string{"*string:~*req.Account"}); err == nil {
+		t.Error("Expected error for the invalid inline filter")
+	}
+	cdrSub, err := es.Subscribe("cgrates.org", []string{utils.MetaCDRProcessed},
+		[]string{"*string:~*req.Account:1001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	allSub, err := es.Subscribe("cgrates.org", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherTntSub, err := es.Subscribe("itsyscom.com", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ev := map[string]interface{}{utils.AccountField: "1001"}
+	es.Publish(utils.MetaCDRProcessed, "cgrates.org", ev)
+	ev[utils.AccountField] = "1002" // the published event is a copy
+	es.Publish(utils.MetaCDRProcessed, "cgrates.org", ev)
+	es.Publish(utils.MetaThresholdHit, "cgrates.org", map[string]interface{}{utils.ThresholdID: "TH1"})
+
+	if len(cdrSub.Events) != 2 {
+		t.Fatalf("Expected 2 events, received: %d", len(cdrSub.Events))
+	}
+	sEv := <-cdrSub.Events
+	if sEv.Type != utils.MetaCDRProcessed || sEv.Tenant != "cgrates.org" ||
+		!reflect.DeepEqual(sEv.Event, map[string]interface{}{utils.AccountField: "1001"}) {
+		t.Errorf("Unexpected event: %s", utils.ToJSON(sEv))
+	}
+	if pass, err := es.Pass(cdrSub, sEv); err != nil || !pass {
+		t.Errorf("Expected the event to pass, received: %v, %v", pass, err)
+	}
+	if pass, err := es.Pass(cdrSub, <-cdrSub.Events); err != nil || pass {
+		t.Errorf("Expected the event to not pass, received: %v, %v", pass, err)
+	}
+	if len(allSub.Events) != 3 {
+		t.Errorf("Expected 3 events, received: %d", len(allSub.Events))
+	}
+	if len(otherTntSub.Events) != 0 {
+		t.Errorf("Expected no events, received: %d", len(otherTntSub.Events))
+	}
+
+	// slow subscribers lose the events instead of blocking the publishers
+	for i := 0; i < eventSubscriptionQueue; i++ {
+		es.Publish(utils.MetaStatUpdated, "cgrates.org", nil)
+	}
+	if len(allSub.Events) != eventSubscriptionQueue {
+		t.Errorf("Expected %d events, received: %d", eventSubscriptionQueue, len(allSub.Events))
+	}
+
+	es.Unsubscribe(allSub)
+	es.Unsubscribe(allSub)
+	if _, has := es.subs[allSub]; has {
+		t.Error("Expected the subscription removed")
+	}
+	for range allSub.Events { // drained until closed
+	}
+}
+
+func TestEventStreamPublishNested(t *testing.T) {
+	es := NewEventStreamS(config.NewDefaultCGRConfig())
+	sub, err := es.Subscribe("cgrates.org", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cgrEv := map[string]interface{}{
+		utils.AccountField: "1001",
+		"Tags":             []string{"tag1"},
+		"Items":            []interface{}{map[string]interface{}{"Item": "1"}},
+	}
+	es.Publish(utils.MetaThresholdHit, "cgrates.org", map[string]interface{}{
+		utils.ThresholdID: "TH1",
+		utils.Event:       cgrEv,
+	})
+	// the caller keeps changing its event after publishing it
+	cgrEv[utils.AccountField] = "1002"
+	cgrEv["Tags"].([]string)[0] = "tag2"
+	cgrEv["Items"].([]interface{})[0].(map[string]interface{})["Item"] = "2"
+
+	exp := map[string]interface{}{
+		utils.ThresholdID: "TH1",
+		utils.Event: map[string]interface{}{
+			utils.AccountField: "1001",
+			"Tags":             []string{"tag1"},
+			"Items":            []interface{}{map[string]interface{}{"Item": "1"}},
+		},
+	}
+	if sEv := <-sub.Events; !reflect.DeepEqual(exp, sEv.Event) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(exp), utils.ToJSON(sEv.Event))
+	}
+}
@@ -38,7 +38,7 @@ func MatchingItemIDsForEvent(ev utils.MapStorage, stringFldIDs, prefixFldIDs, su
 	}
 	// Guard will protect the function with automatic locking
 	lockID := utils.CacheInstanceToPrefix[cacheID] + itemIDPrefix
-	guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
+	if _, lkErr := guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
 		if !indexedSelects {
 			var keysWithID []string
 			if keysWithID, err = dm.DataDB().GetKeysForPrefix(utils.CacheIndexesToPrefix[cacheID]); err != nil {
@@ -95,7 +95,9 @@ func MatchingItemIDsForEvent(ev utils.MapStorage, stringFldIDs, prefixFldIDs, su
 			}
 		}
 		return
-	}, config.CgrConfig().GeneralCfg().LockingTimeout, lockID)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, lockID); lkErr != nil {
+		return nil, lkErr
+	}
 	if len(itemIDs) == 0 {
 		return nil, utils.ErrNotFound
 	}
@@ -0,0 +1,73 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// Invoice is the bill of one account for one billing cycle
+type Invoice struct {
+	Tenant     string
+	ID         string // the number prefix followed by the number
+	Number     int64  // sequential within the tenant
+	Account    string
+	CycleStart time.Time
+	CycleEnd   time.Time
+	CreatedAt  time.Time
+	Lines      []*InvoiceLine
+	Total      float64 // sum of the *usage and *recurring lines
+}
+
+// InvoiceLine is one line item of the invoice
+type InvoiceLine struct {
+	Type        string // <*usage|*recurring|*balance>
+	Description string
+	Quantity    int64         // number of CDRs or of fee occurrences
+	Usage       time.Duration // the total usage of the CDRs
+	Amount      float64
+}
+
+// TenantID returns the concatenated key between tenant and ID
+func (inv *Invoice) TenantID() string {
+	return utils.ConcatenatedKey(inv.Tenant, inv.ID)
+}
+
+// AsCGREvent converts the invoice into a CGREvent, rendered by EEs
+func (inv *Invoice) AsCGREvent() *utils.CGREvent {
+	return &utils.CGREvent{
+		Tenant: inv.Tenant,
+		ID:     inv.ID,
+		Time:   utils.TimePointer(inv.CreatedAt),
+		Event: map[string]interface{}{
+			utils.Tenant:       inv.Tenant,
+			utils.ID:           inv.ID,
+			utils.Number:       inv.Number,
+			utils.AccountField: inv.Account,
+			utils.CycleStart:   inv.CycleStart,
+			utils.CycleEnd:     inv.CycleEnd,
+			utils.CreatedAt:    inv.CreatedAt,
+			utils.Lines:        inv.Lines,
+			utils.Total:        inv.Total,
+		},
+		Opts: make(map[string]interface{}),
+	}
+}
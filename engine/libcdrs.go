@@ -0,0 +1,156 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// cdrGroupValue returns the value of the CDR for one of the GroupBy items
+func cdrGroupValue(cdr *CDR, grp *utils.CDRsGroupBy) string {
+	if grp.ExtraField {
+		return cdr.ExtraFields[grp.Field]
+	}
+	switch grp.Field {
+	case utils.Tenant:
+		return cdr.Tenant
+	case utils.RunID:
+		return cdr.RunID
+	case utils.ToR:
+		return cdr.ToR
+	case utils.RequestType:
+		return cdr.RequestType
+	case utils.Category:
+		return cdr.Category
+	case utils.AccountField:
+		return cdr.Account
+	case utils.Subject:
+		return cdr.Subject
+	case utils.OriginHost:
+		return cdr.OriginHost
+	case utils.Source:
+		return cdr.Source
+	case utils.CostSource:
+		return cdr.CostSource
+	case utils.Destination:
+		if grp.Length != 0 && len(cdr.Destination) > grp.Length {
+			return cdr.Destination[:grp.Length]
+		}
+		return cdr.Destination
+	case utils.SetupTime:
+		return cdrGroupTime(cdr.SetupTime, grp.Period)
+	case utils.AnswerTime:
+		return cdrGroupTime(cdr.AnswerTime, grp.Period)
+	}
+	return utils.EmptyString
+}
+
+// cdrGroupTime formats the time truncated to the period in UTC, as the StorDBs do
+func cdrGroupTime(t time.Time, period string) string {
+	if t.IsZero() {
+		return utils.EmptyString
+	}
+	if period == utils.MetaHourly {
+		return t.UTC().Format("2006-01-02T15")
+	}
+	return t.UTC().Format("2006-01-02")
+}
+
+// aggregateCDRs groups the CDRs in memory, for the StorDBs not able to aggregate them
+// the groups are sorted on their values and paginated
+// the sumFlds are extra fields, their values that are not numbers being left out of the sums
+func aggregateCDRs(cdrs []*CDR, grps []*utils.CDRsGroupBy, sumFlds []string,
+	pgnt utils.Paginator) (aggrs []*utils.CDRsAggregate) {
+	aggrIdx := make(map[string]*utils.CDRsAggregate)
+	rated := make(utils.StringSet) // the groups with rated CDRs
+	grpKeys := make([]string, 0)
+	for _, cdr := range cdrs {
+		vals := make([]string, len(grps))
+		for i, grp := range grps {
+			vals[i] = cdrGroupValue(cdr, grp)
+		}
+		grpKey := fmt.Sprintf("%q", vals) // the values may contain any separator
+		aggr, has := aggrIdx[grpKey]
+		if !has {
+			aggr = &utils.CDRsAggregate{
+				Group:    make(map[string]string),
+				MinUsage: cdr.Usage,
+				MaxUsage: cdr.Usage,
+			}
+			for i, grp := range grps {
+				aggr.Group[grp.Key] = vals[i]
+			}
+			if len(sumFlds) != 0 {
+				aggr.Sums = make(map[string]float64, len(sumFlds))
+				for _, fld := range sumFlds {
+					aggr.Sums[fld] = 0
+				}
+			}
+			aggrIdx[grpKey] = aggr
+			grpKeys = append(grpKeys, grpKey)
+		}
+		aggr.Count++
+		aggr.Usage += cdr.Usage
+		if cdr.Usage < aggr.MinUsage {
+			aggr.MinUsage = cdr.Usage
+		}
+		if cdr.Usage > aggr.MaxUsage {
+			aggr.MaxUsage = cdr.Usage
+		}
+		for _, fld := range sumFlds {
+			if val, err := strconv.ParseFloat(cdr.ExtraFields[fld], 64); err == nil {
+				aggr.Sums[fld] += val
+			}
+		}
+		if cdr.Cost == -1 { // unrated CDRs are left out of the cost aggregates
+			continue
+		}
+		if !rated.Has(grpKey) {
+			rated.Add(grpKey)
+			aggr.MinCost, aggr.MaxCost = cdr.Cost, cdr.Cost
+		}
+		aggr.Cost += cdr.Cost
+		if cdr.Cost < aggr.MinCost {
+			aggr.MinCost = cdr.Cost
+		}
+		if cdr.Cost > aggr.MaxCost {
+			aggr.MaxCost = cdr.Cost
+		}
+	}
+	sort.Slice(grpKeys, func(i, j int) bool {
+		for _, grp := range grps {
+			iVal, jVal := aggrIdx[grpKeys[i]].Group[grp.Key], aggrIdx[grpKeys[j]].Group[grp.Key]
+			if iVal != jVal {
+				return iVal < jVal
+			}
+		}
+		return false
+	})
+	grpKeys = pgnt.PaginateStringSlice(grpKeys)
+	aggrs = make([]*utils.CDRsAggregate, len(grpKeys))
+	for i, grpKey := range grpKeys {
+		aggrs[i] = aggrIdx[grpKey]
+	}
+	return
+}
@@ -519,6 +519,9 @@ func GetDefaultEmptyCacheStats() map[string]*ltcache.CacheStats {
 		utils.CacheDispatcherFilterIndexes:      {},
 		utils.CacheDispatcherProfiles:           {},
 		utils.CacheDispatcherHosts:              {},
+		utils.CachePortedNumbers:                {},
+		utils.CacheSharedSessions:               {},
+		utils.CacheConfigSections:               {},
 		utils.CacheDispatcherRoutes:             {},
 		utils.CacheDispatcherLoads:              {},
 		utils.CacheDispatchers:                  {},
@@ -581,6 +584,8 @@ func GetDefaultEmptyCacheStats() map[string]*ltcache.CacheStats {
 		utils.CacheTBLTPFilters:          {},
 		utils.CacheSessionCostsTBL:       {},
 		utils.CacheCDRsTBL:               {},
+		utils.CacheAuditLogTBL:           {},
+		utils.CacheInvoicesTBL:           {},
 		utils.CacheTBLTPRoutes:           {},
 		utils.CacheTBLTPAttributes:       {},
 		utils.CacheTBLTPChargers:         {},
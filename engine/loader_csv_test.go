@@ -25,6 +25,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -1673,3 +1674,41 @@ func TestLoadAccountProfiles(t *testing.T) {
 			utils.ToJSON(expected), utils.ToJSON(csvr.accountProfiles[accPrfKey]))
 	}
 }
+
+func TestLoadWriteToDatabaseTransactionalLegacy(t *testing.T) {
+	expected := "transactional load not supported for: Destinations, Timings, RatingPlans, RatingProfiles, SharedGroups, Actions, ActionPlans, ActionTriggers, AccountActions"
+	if err := csvr.WriteToDatabaseTransactional(false, false); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	if csvr.txn != nil {
+		t.Error("Expected the transaction to be dropped")
+	}
+}
+
+func TestLoadWriteToDatabaseRateLimit(t *testing.T) {
+	dm := NewDataManager(NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	tpr, err := NewTpReader(dm.DataDB(), NewStringCSVStorage(utils.CSVSep,
+		"", "", "", "", "", "", "", "", "", "", "", `
+#Tenant[0],Id[1],FilterIDs[2],ActivationInterval[3],TTL[4],Limit[5],AllocationMessage[6],Blocker[7],Stored[8],Weight[9],Thresholds[10]
+cgrates.org,ResRate,*string:~*req.Account:1001,,,10/1s,,false,true,10,
+`, "", "", "", "", "", "", "", "", "", "", ""), testTPID, "", nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tpr.LoadResourceProfiles(); err != nil {
+		t.Fatal(err)
+	}
+	if err = tpr.WriteToDatabase(false, false); err != nil {
+		t.Fatal(err)
+	}
+	rp, err := dm.GetResourceProfile("cgrates.org", "ResRate", false, false, utils.NonTransactional)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rp.Limit != 10 || rp.LimitInterval != time.Second {
+		t.Errorf("Expected limit 10/1s, received: %v/%v", rp.Limit, rp.LimitInterval)
+	}
+	if _, err = dm.GetResource("cgrates.org", "ResRate", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+}
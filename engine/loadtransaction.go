@@ -0,0 +1,477 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// NewLoadTransaction returns a LoadTransaction writing into the DataManager
+func NewLoadTransaction(dm *DataManager) *LoadTransaction {
+	return &LoadTransaction{
+		dm:      dm,
+		staged:  make(map[string]utils.StringSet),
+		removed: make(map[string]utils.StringSet),
+	}
+}
+
+// LoadTransaction stages the loaded profiles so they can be validated
+// and written into DataDB all together or not at all
+// the commit is not atomic: the items are written one by one, the readers seeing part of the load
+// until the commit ends, and the items written before a crash of the engine are not restored
+type LoadTransaction struct {
+	dm      *DataManager
+	items   []*stagedItem
+	staged  map[string]utils.StringSet // map[prefix]tenantIDs, used to solve the references
+	removed map[string]utils.StringSet // map[prefix]tenantIDs, the references to them are broken
+}
+
+// stagedItem is one profile waiting for the commit
+type stagedItem struct {
+	prefix  string
+	tntID   *utils.TenantID
+	profile interface{}
+	store   func() error // writes the profile together with the items depending on it
+	remove  bool         // the store function removes the item instead of writing it
+}
+
+// priority returns the commit order of the item
+func (sI *stagedItem) priority() int {
+	if sI.remove {
+		return removePriority(sI.prefix)
+	}
+	return commitPriority(sI.prefix)
+}
+
+// references returns the IDs referenced by the profile indexed on the prefix of the referenced item
+func (sI *stagedItem) references() (refs map[string][]string) {
+	refs = make(map[string][]string)
+	switch prf := sI.profile.(type) {
+	case *AttributeProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+		for _, attr := range prf.Attributes {
+			refs[utils.FilterPrefix] = append(refs[utils.FilterPrefix], attr.FilterIDs...)
+		}
+	case *ResourceProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+	case *StatQueueProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+		for _, metric := range prf.Metrics {
+			refs[utils.FilterPrefix] = append(refs[utils.FilterPrefix], metric.FilterIDs...)
+		}
+	case *ThresholdProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+	case *RouteProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+		for _, route := range prf.Routes {
+			refs[utils.FilterPrefix] = append(refs[utils.FilterPrefix], route.FilterIDs...)
+		}
+	case *ChargerProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+		refs[utils.AttributeProfilePrefix] = prf.AttributeIDs
+	case *DispatcherProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+		for _, host := range prf.Hosts {
+			refs[utils.FilterPrefix] = append(refs[utils.FilterPrefix], host.FilterIDs...)
+		}
+	case *RateProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+		for _, rate := range prf.Rates {
+			refs[utils.FilterPrefix] = append(refs[utils.FilterPrefix], rate.FilterIDs...)
+		}
+	case *ActionProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+		for _, act := range prf.Actions {
+			refs[utils.FilterPrefix] = append(refs[utils.FilterPrefix], act.FilterIDs...)
+		}
+	case *utils.AccountProfile:
+		refs[utils.FilterPrefix] = prf.FilterIDs
+		for _, blnc := range prf.Balances {
+			refs[utils.FilterPrefix] = append(refs[utils.FilterPrefix], blnc.FilterIDs...)
+			refs[utils.AttributeProfilePrefix] = append(refs[utils.AttributeProfilePrefix], blnc.AttributeIDs...)
+			refs[utils.RateProfilePrefix] = append(refs[utils.RateProfilePrefix], blnc.RateProfileIDs...)
+		}
+	}
+	return
+}
+
+// Stage adds the profile to the transaction
+// the store function is called on commit to write the profile
+// the resources, stat queues and thresholds are accepted for the case they are written separately
+func (lt *LoadTransaction) Stage(profile interface{}, store func() error) (err error) {
+	var prefix string
+	tntID := new(utils.TenantID)
+	switch prf := profile.(type) {
+	case *Filter:
+		prefix, tntID.Tenant, tntID.ID = utils.FilterPrefix, prf.Tenant, prf.ID
+	case *AttributeProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.AttributeProfilePrefix, prf.Tenant, prf.ID
+	case *ResourceProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.ResourceProfilesPrefix, prf.Tenant, prf.ID
+	case *StatQueueProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.StatQueueProfilePrefix, prf.Tenant, prf.ID
+	case *ThresholdProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.ThresholdProfilePrefix, prf.Tenant, prf.ID
+	case *RouteProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.RouteProfilePrefix, prf.Tenant, prf.ID
+	case *ChargerProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.ChargerProfilePrefix, prf.Tenant, prf.ID
+	case *DispatcherProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.DispatcherProfilePrefix, prf.Tenant, prf.ID
+	case *DispatcherHost:
+		prefix, tntID.Tenant, tntID.ID = utils.DispatcherHostPrefix, prf.Tenant, prf.ID
+	case *PortedNumber:
+		prefix, tntID.Tenant, tntID.ID = utils.PortedNumberPrefix, prf.Tenant, prf.ID
+	case *RateProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.RateProfilePrefix, prf.Tenant, prf.ID
+	case *ActionProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.ActionProfilePrefix, prf.Tenant, prf.ID
+	case *utils.AccountProfile:
+		prefix, tntID.Tenant, tntID.ID = utils.AccountProfilePrefix, prf.Tenant, prf.ID
+	case *Resource:
+		prefix, tntID.Tenant, tntID.ID = utils.ResourcesPrefix, prf.Tenant, prf.ID
+	case *StatQueue:
+		prefix, tntID.Tenant, tntID.ID = utils.StatQueuePrefix, prf.Tenant, prf.ID
+	case *Threshold:
+		prefix, tntID.Tenant, tntID.ID = utils.ThresholdPrefix, prf.Tenant, prf.ID
+	default:
+		return fmt.Errorf("unsupported profile type: %T", profile)
+	}
+	if _, has := lt.staged[prefix]; !has {
+		lt.staged[prefix] = make(utils.StringSet)
+	}
+	lt.staged[prefix].Add(tntID.TenantID())
+	lt.items = append(lt.items, &stagedItem{
+		prefix:  prefix,
+		tntID:   tntID,
+		profile: profile,
+		store:   store,
+	})
+	return
+}
+
+// StageRemove adds the removal of the item to the transaction
+// the remove function is called on commit and the item is restored on rollback
+func (lt *LoadTransaction) StageRemove(prefix string, tntID *utils.TenantID, remove func() error) (err error) {
+	if !removablePrefixes.Has(prefix) {
+		return fmt.Errorf("unsupported prefix: <%s>", prefix)
+	}
+	if _, has := lt.removed[prefix]; !has {
+		lt.removed[prefix] = make(utils.StringSet)
+	}
+	lt.removed[prefix].Add(tntID.TenantID())
+	lt.items = append(lt.items, &stagedItem{
+		prefix: prefix,
+		tntID:  tntID,
+		store:  remove,
+		remove: true,
+	})
+	return
+}
+
+// removablePrefixes are the prefixes of the items that can be restored by the rollback
+var removablePrefixes = utils.NewStringSet([]string{
+	utils.FilterPrefix, utils.AttributeProfilePrefix, utils.ResourceProfilesPrefix,
+	utils.ResourcesPrefix, utils.StatQueueProfilePrefix, utils.StatQueuePrefix,
+	utils.ThresholdProfilePrefix, utils.ThresholdPrefix, utils.RouteProfilePrefix,
+	utils.ChargerProfilePrefix, utils.DispatcherProfilePrefix, utils.DispatcherHostPrefix,
+	utils.PortedNumberPrefix, utils.RateProfilePrefix, utils.ActionProfilePrefix,
+	utils.AccountProfilePrefix,
+})
+
+// Len returns the number of staged items
+func (lt *LoadTransaction) Len() int {
+	return len(lt.items)
+}
+
+// Discard drops the staged items
+func (lt *LoadTransaction) Discard() {
+	lt.items = nil
+	lt.staged = make(map[string]utils.StringSet)
+	lt.removed = make(map[string]utils.StringSet)
+}
+
+// Validate checks if the references of the staged profiles are found
+// between the staged profiles or in DataDB
+func (lt *LoadTransaction) Validate() (err error) {
+	for _, itm := range lt.items {
+		for prefix, ids := range itm.references() {
+			for _, id := range ids {
+				if err = lt.checkReference(itm.tntID.Tenant, prefix, id); err != nil {
+					return fmt.Errorf("broken reference to %s: <%s> for item with ID: <%s>, error: %s",
+						referenceNames[prefix], id, itm.tntID.TenantID(), err.Error())
+				}
+			}
+		}
+	}
+	return
+}
+
+// referenceNames is used to compose the validation errors
+var referenceNames = map[string]string{
+	utils.FilterPrefix:           "filter",
+	utils.AttributeProfilePrefix: "attribute profile",
+	utils.RateProfilePrefix:      "rate profile",
+}
+
+// checkReference returns an error if the referenced item is not found
+func (lt *LoadTransaction) checkReference(tenant, prefix, id string) (err error) {
+	if strings.HasPrefix(id, utils.Meta) {
+		if prefix == utils.FilterPrefix { // inline filter
+			_, err = NewFilterFromInline(tenant, id)
+		}
+		return // other references like *none or *constant are not stored
+	}
+	tntID := utils.ConcatenatedKey(tenant, id)
+	if lt.staged[prefix].Has(tntID) {
+		return
+	}
+	if lt.removed[prefix].Has(tntID) {
+		return utils.ErrNotFound
+	}
+	var has bool
+	if has, err = lt.dm.DataDB().HasDataDrv(prefix, id, tenant); err != nil {
+		return
+	}
+	if !has {
+		return utils.ErrNotFound
+	}
+	return
+}
+
+// commitPriority makes sure the referenced profiles are written first
+func commitPriority(prefix string) int {
+	switch prefix {
+	case utils.FilterPrefix:
+		return 0
+	case utils.AttributeProfilePrefix, utils.RateProfilePrefix:
+		return 1
+	case utils.ResourcesPrefix, utils.StatQueuePrefix, utils.ThresholdPrefix:
+		return 3 // after their profiles
+	}
+	return 2
+}
+
+// removePriority makes sure the removals are done after the writes
+// and the referenced items are removed last
+func removePriority(prefix string) int {
+	return 10 - commitPriority(prefix)
+}
+
+// PartialCommitError is returned by Commit if the rollback could not restore all the items,
+// DataDB being left with part of the load
+type PartialCommitError struct {
+	Err         error    // the error stopping the commit
+	NotRestored []string // the items left as written by the commit
+}
+
+func (pcErr *PartialCommitError) Error() string {
+	return fmt.Sprintf("%s, partially committed, not restored: %s",
+		pcErr.Err.Error(), strings.Join(pcErr.NotRestored, utils.FieldsSep))
+}
+
+// Commit validates the staged profiles and writes them into DataDB
+// on error the profiles already written are restored to their previous state,
+// the ones failing to be restored being listed by a *PartialCommitError
+func (lt *LoadTransaction) Commit() (err error) {
+	if lt.dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	if err = lt.Validate(); err != nil {
+		return
+	}
+	items := make([]*stagedItem, len(lt.items))
+	copy(items, lt.items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].priority() < items[j].priority()
+	})
+	restores := make([]func() error, 0, len(items))
+	for i, itm := range items {
+		var restore func() error
+		if restore, err = lt.backup(itm); err != nil {
+			return lt.rollback(items[:i], restores,
+				fmt.Errorf("failed reading the previous version of <%s>, error: %s",
+					itm.tntID.TenantID(), err.Error()))
+		}
+		restores = append(restores, restore) // the store can fail after partially writing the item
+		if err = itm.store(); err != nil {
+			action := "storing"
+			if itm.remove {
+				action = "removing"
+			}
+			return lt.rollback(items[:i+1], restores,
+				fmt.Errorf("failed %s <%s>, error: %s",
+					action, itm.tntID.TenantID(), err.Error()))
+		}
+	}
+	lt.Discard()
+	return
+}
+
+// rollback restores in reverse order the items written by commit
+// returning the commit error, wrapped in a *PartialCommitError if some items are not restored
+func (lt *LoadTransaction) rollback(items []*stagedItem, restores []func() error, cmtErr error) error {
+	var notRestored []string
+	for i := len(restores) - 1; i >= 0; i-- {
+		if err := restores[i](); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> failed rolling back <%s> from the load, error: %s",
+				utils.DataManager, items[i].tntID.TenantID(), err.Error()))
+			notRestored = append(notRestored, items[i].tntID.TenantID())
+		}
+	}
+	if len(notRestored) != 0 {
+		return &PartialCommitError{Err: cmtErr, NotRestored: notRestored}
+	}
+	return cmtErr
+}
+
+// restoreFunc returns the function restoring the item based on the error received when queried
+func restoreFunc(err error, set, remove func() error) (func() error, error) {
+	if err == nil {
+		return set, nil
+	}
+	if err != utils.ErrNotFound {
+		return nil, err
+	}
+	return func() (err error) {
+		if err = remove(); err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}, nil
+}
+
+// backup returns the function restoring the item as it is before the commit
+func (lt *LoadTransaction) backup(itm *stagedItem) (restore func() error, err error) {
+	tnt, id := itm.tntID.Tenant, itm.tntID.ID
+	switch itm.prefix {
+	case utils.FilterPrefix:
+		old, err := lt.dm.GetFilter(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetFilter(old, true) },
+			func() error { return lt.dm.RemoveFilter(tnt, id, utils.NonTransactional, true) })
+	case utils.AttributeProfilePrefix:
+		old, err := lt.dm.GetAttributeProfile(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetAttributeProfile(old, true) },
+			func() error { return lt.dm.RemoveAttributeProfile(tnt, id, utils.NonTransactional, true) })
+	case utils.ResourceProfilesPrefix:
+		old, err := lt.dm.GetResourceProfile(tnt, id, false, false, utils.NonTransactional)
+		if restore, err = restoreFunc(err,
+			func() error { return lt.dm.SetResourceProfile(old, true) },
+			func() error { return lt.dm.RemoveResourceProfile(tnt, id, utils.NonTransactional, true) }); err != nil {
+			return nil, err
+		}
+		restoreRs, err := lt.backup(&stagedItem{prefix: utils.ResourcesPrefix, tntID: itm.tntID})
+		return chainRestores(restoreRs, restore), err
+	case utils.ResourcesPrefix:
+		old, err := lt.dm.GetResource(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetResource(old, nil, 0, true) },
+			func() error { return lt.dm.RemoveResource(tnt, id, utils.NonTransactional) })
+	case utils.StatQueueProfilePrefix:
+		old, err := lt.dm.GetStatQueueProfile(tnt, id, false, false, utils.NonTransactional)
+		if restore, err = restoreFunc(err,
+			func() error { return lt.dm.SetStatQueueProfile(old, true) },
+			func() error { return lt.dm.RemoveStatQueueProfile(tnt, id, utils.NonTransactional, true) }); err != nil {
+			return nil, err
+		}
+		restoreSq, err := lt.backup(&stagedItem{prefix: utils.StatQueuePrefix, tntID: itm.tntID})
+		return chainRestores(restoreSq, restore), err
+	case utils.StatQueuePrefix:
+		old, err := lt.dm.GetStatQueue(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetStatQueue(old, nil, 0, nil, 0, true) },
+			func() error { return lt.dm.RemoveStatQueue(tnt, id, utils.NonTransactional) })
+	case utils.ThresholdProfilePrefix:
+		old, err := lt.dm.GetThresholdProfile(tnt, id, false, false, utils.NonTransactional)
+		if restore, err = restoreFunc(err,
+			func() error { return lt.dm.SetThresholdProfile(old, true) },
+			func() error { return lt.dm.RemoveThresholdProfile(tnt, id, utils.NonTransactional, true) }); err != nil {
+			return nil, err
+		}
+		restoreTh, err := lt.backup(&stagedItem{prefix: utils.ThresholdPrefix, tntID: itm.tntID})
+		return chainRestores(restoreTh, restore), err
+	case utils.ThresholdPrefix:
+		old, err := lt.dm.GetThreshold(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetThreshold(old, 0, true) },
+			func() error { return lt.dm.RemoveThreshold(tnt, id, utils.NonTransactional) })
+	case utils.RouteProfilePrefix:
+		old, err := lt.dm.GetRouteProfile(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetRouteProfile(old, true) },
+			func() error { return lt.dm.RemoveRouteProfile(tnt, id, utils.NonTransactional, true) })
+	case utils.ChargerProfilePrefix:
+		old, err := lt.dm.GetChargerProfile(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetChargerProfile(old, true) },
+			func() error { return lt.dm.RemoveChargerProfile(tnt, id, utils.NonTransactional, true) })
+	case utils.DispatcherProfilePrefix:
+		old, err := lt.dm.GetDispatcherProfile(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetDispatcherProfile(old, true) },
+			func() error { return lt.dm.RemoveDispatcherProfile(tnt, id, utils.NonTransactional, true) })
+	case utils.DispatcherHostPrefix:
+		old, err := lt.dm.GetDispatcherHost(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetDispatcherHost(old) },
+			func() error { return lt.dm.RemoveDispatcherHost(tnt, id, utils.NonTransactional) })
+	case utils.PortedNumberPrefix:
+		old, err := lt.dm.GetPortedNumber(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetPortedNumber(old) },
+			func() error { return lt.dm.RemovePortedNumber(tnt, id, utils.NonTransactional) })
+	case utils.RateProfilePrefix:
+		old, err := lt.dm.GetRateProfile(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetRateProfile(old, true) },
+			func() error { return lt.dm.RemoveRateProfile(tnt, id, utils.NonTransactional, true) })
+	case utils.ActionProfilePrefix:
+		old, err := lt.dm.GetActionProfile(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetActionProfile(old, true) },
+			func() error { return lt.dm.RemoveActionProfile(tnt, id, utils.NonTransactional, true) })
+	case utils.AccountProfilePrefix:
+		old, err := lt.dm.GetAccountProfile(tnt, id, false, false, utils.NonTransactional)
+		return restoreFunc(err,
+			func() error { return lt.dm.SetAccountProfile(old, true) },
+			func() error { return lt.dm.RemoveAccountProfile(tnt, id, utils.NonTransactional, true) })
+	}
+	return nil, fmt.Errorf("unsupported prefix: <%s>", itm.prefix)
+}
+
+// chainRestores executes the restores in order, stopping on the first error
+func chainRestores(restores ...func() error) func() error {
+	return func() (err error) {
+		for _, restore := range restores {
+			if restore == nil {
+				continue
+			}
+			if err = restore(); err != nil {
+				return
+			}
+		}
+		return
+	}
+}
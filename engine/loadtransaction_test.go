@@ -0,0 +1,288 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestLoadTransactionValidate(t *testing.T) {
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	Cache = NewCacheS(config.CgrConfig(), nil, nil)
+	dm := NewDataManager(NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	if err := dm.SetFilter(&Filter{Tenant: "cgrates.org", ID: "FLTR_DB",
+		Rules: []*FilterRule{{Type: utils.MetaString, Element: "~*req.Account", Values: []string{"1001"}}}},
+		true); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetRateProfile(&RateProfile{Tenant: "cgrates.org", ID: "RP_DB"}, false); err != nil {
+		t.Fatal(err)
+	}
+	lt := NewLoadTransaction(dm)
+	if err := lt.Stage(&Filter{Tenant: "cgrates.org", ID: "FLTR_STAGED"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.Stage(&AttributeProfile{Tenant: "cgrates.org", ID: "ATTR_1",
+		FilterIDs: []string{"FLTR_DB", "FLTR_STAGED", "*string:~*req.Account:1001"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.Stage(&ChargerProfile{Tenant: "cgrates.org", ID: "CHRG_1",
+		AttributeIDs: []string{"ATTR_1", utils.MetaNone}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := lt.Stage(&utils.AccountProfile{Tenant: "cgrates.org", ID: "ACC_1",
+		Balances: map[string]*utils.Balance{
+			"B1": {ID: "B1", RateProfileIDs: []string{"RP_DB", "RP_MISSING"}},
+		}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := "broken reference to rate profile: <RP_MISSING> for item with ID: <cgrates.org:ACC_1>, error: NOT_FOUND"
+	if err := lt.Validate(); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	if err := lt.Commit(); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	if _, err := dm.GetAttributeProfile("cgrates.org", "ATTR_1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	expected = "unsupported profile type: string"
+	if err := lt.Stage("ATTR_1", nil); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	if err := NewLoadTransaction(nil).Commit(); err != utils.ErrNoDatabaseConn {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNoDatabaseConn, err)
+	}
+}
+
+func TestLoadTransactionCommitRollback(t *testing.T) {
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	Cache = NewCacheS(config.CgrConfig(), nil, nil)
+	dm := NewDataManager(NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	oldThPrf := &ThresholdProfile{Tenant: "cgrates.org", ID: "TH_1", MaxHits: 1}
+	if err := dm.SetThresholdProfile(oldThPrf, true); err != nil {
+		t.Fatal(err)
+	}
+	oldTh := &Threshold{Tenant: "cgrates.org", ID: "TH_1", Hits: 1}
+	if err := dm.SetThreshold(oldTh, 0, true); err != nil {
+		t.Fatal(err)
+	}
+
+	lt := NewLoadTransaction(dm)
+	fltr := &Filter{Tenant: "cgrates.org", ID: "FLTR_1",
+		Rules: []*FilterRule{{Type: utils.MetaString, Element: "~*req.Account", Values: []string{"1001"}}}}
+	thPrf := &ThresholdProfile{Tenant: "cgrates.org", ID: "TH_1", FilterIDs: []string{"FLTR_1"}, MaxHits: 10}
+	th := &Threshold{Tenant: "cgrates.org", ID: "TH_1"}
+	// staged in reverse order to check that the filters are written first
+	if err := lt.Stage(th, func() error { return dm.SetThreshold(th, 0, true) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.Stage(thPrf, func() error { return dm.SetThresholdProfile(thPrf, true) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.Stage(fltr, func() error { return dm.SetFilter(fltr, true) }); err != nil {
+		t.Fatal(err)
+	}
+	attrPrf := &AttributeProfile{Tenant: "cgrates.org", ID: "ATTR_1"}
+	if err := lt.Stage(attrPrf, func() error {
+		return errors.New("can't store")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "failed storing <cgrates.org:ATTR_1>, error: can't store"
+	if err := lt.Commit(); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	// everything should be as before the commit
+	if _, err := dm.GetFilter("cgrates.org", "FLTR_1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	if rcv, err := dm.GetThresholdProfile("cgrates.org", "TH_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(oldThPrf, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(oldThPrf), utils.ToJSON(rcv))
+	}
+	if rcv, err := dm.GetThreshold("cgrates.org", "TH_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if rcv.Hits != 1 {
+		t.Errorf("Expected the old threshold, received %s", utils.ToJSON(rcv))
+	}
+
+	lt.Discard()
+	if err := lt.Stage(thPrf, func() error { return dm.SetThresholdProfile(thPrf, true) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.Stage(fltr, func() error { return dm.SetFilter(fltr, true) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if lt.Len() != 0 {
+		t.Errorf("Expected the staged profiles to be dropped after commit, received: %d", lt.Len())
+	}
+	if rcv, err := dm.GetThresholdProfile("cgrates.org", "TH_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(thPrf, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(thPrf), utils.ToJSON(rcv))
+	}
+}
+
+func TestLoadTransactionPartialCommit(t *testing.T) {
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	Cache = NewCacheS(config.CgrConfig(), nil, nil)
+	dm := NewDataManager(NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	fltr := &Filter{Tenant: "cgrates.org", ID: "FLTR_1",
+		Rules: []*FilterRule{{Type: utils.MetaString, Element: "~*req.Account", Values: []string{"1001"}}}}
+	if err := dm.SetFilter(fltr, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetThresholdProfile(&ThresholdProfile{Tenant: "cgrates.org", ID: "TH_1",
+		FilterIDs: []string{"FLTR_1"}, MaxHits: 1}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	lt := NewLoadTransaction(dm)
+	thPrf := &ThresholdProfile{Tenant: "cgrates.org", ID: "TH_1", MaxHits: 10}
+	if err := lt.Stage(thPrf, func() error { return dm.SetThresholdProfile(thPrf, true) }); err != nil {
+		t.Fatal(err)
+	}
+	// the filter is removed outside the transaction so the old threshold profile can not be restored
+	if err := lt.Stage(&Threshold{Tenant: "cgrates.org", ID: "TH_1"}, func() error {
+		if err := dm.RemoveFilter("cgrates.org", "FLTR_1", utils.NonTransactional, false); err != nil {
+			return err
+		}
+		return errors.New("can't store")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	err := lt.Commit()
+	pcErr, canCast := err.(*PartialCommitError)
+	if !canCast {
+		t.Fatalf("Expected *PartialCommitError, received %+v", err)
+	}
+	if exp := []string{"cgrates.org:TH_1"}; !reflect.DeepEqual(exp, pcErr.NotRestored) {
+		t.Errorf("Expected %+v, received %+v", exp, pcErr.NotRestored)
+	}
+	if expected := "failed storing <cgrates.org:TH_1>, error: can't store"; pcErr.Err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, pcErr.Err)
+	}
+	if rcv, err := dm.GetThresholdProfile("cgrates.org", "TH_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(thPrf, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(thPrf), utils.ToJSON(rcv))
+	}
+}
+
+func TestLoadTransactionStageRemove(t *testing.T) {
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	Cache = NewCacheS(config.CgrConfig(), nil, nil)
+	dm := NewDataManager(NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	fltr := &Filter{Tenant: "cgrates.org", ID: "FLTR_1",
+		Rules: []*FilterRule{{Type: utils.MetaString, Element: "~*req.Account", Values: []string{"1001"}}}}
+	if err := dm.SetFilter(fltr, true); err != nil {
+		t.Fatal(err)
+	}
+	attrPrf := &AttributeProfile{Tenant: "cgrates.org", ID: "ATTR_1", FilterIDs: []string{"FLTR_1"}}
+	if err := dm.SetAttributeProfile(attrPrf, true); err != nil {
+		t.Fatal(err)
+	}
+	fltrTntID := &utils.TenantID{Tenant: "cgrates.org", ID: "FLTR_1"}
+	attrTntID := &utils.TenantID{Tenant: "cgrates.org", ID: "ATTR_1"}
+	removeFltr := func() error { return dm.RemoveFilter("cgrates.org", "FLTR_1", utils.NonTransactional, true) }
+	removeAttr := func() error {
+		return dm.RemoveAttributeProfile("cgrates.org", "ATTR_1", utils.NonTransactional, true)
+	}
+
+	lt := NewLoadTransaction(dm)
+	// the removed filter can not be referenced by the staged profiles
+	if err := lt.StageRemove(utils.FilterPrefix, fltrTntID, removeFltr); err != nil {
+		t.Fatal(err)
+	}
+	chrgPrf := &ChargerProfile{Tenant: "cgrates.org", ID: "CHRG_1", FilterIDs: []string{"FLTR_1"}}
+	if err := lt.Stage(chrgPrf, func() error { return dm.SetChargerProfile(chrgPrf, true) }); err != nil {
+		t.Fatal(err)
+	}
+	expected := "broken reference to filter: <FLTR_1> for item with ID: <cgrates.org:CHRG_1>, error: NOT_FOUND"
+	if err := lt.Commit(); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+
+	// the removals are rolled back if a later item fails
+	lt.Discard()
+	if err := lt.StageRemove(utils.FilterPrefix, fltrTntID, removeFltr); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.StageRemove(utils.AttributeProfilePrefix, attrTntID, removeAttr); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.StageRemove(utils.ChargerProfilePrefix, &utils.TenantID{Tenant: "cgrates.org", ID: "CHRG_2"},
+		func() error { return errors.New("can't remove") }); err != nil {
+		t.Fatal(err)
+	}
+	expected = "failed removing <cgrates.org:CHRG_2>, error: can't remove"
+	if err := lt.Commit(); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	if rcv, err := dm.GetFilter("cgrates.org", "FLTR_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(fltr, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(fltr), utils.ToJSON(rcv))
+	}
+	if rcv, err := dm.GetAttributeProfile("cgrates.org", "ATTR_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(attrPrf, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(attrPrf), utils.ToJSON(rcv))
+	}
+
+	// the filter is removed after the profile referencing it
+	lt.Discard()
+	if err := lt.StageRemove(utils.FilterPrefix, fltrTntID, removeFltr); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.StageRemove(utils.AttributeProfilePrefix, attrTntID, removeAttr); err != nil {
+		t.Fatal(err)
+	}
+	if err := lt.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dm.GetFilter("cgrates.org", "FLTR_1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	if _, err := dm.GetAttributeProfile("cgrates.org", "ATTR_1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+
+	expected = "unsupported prefix: <tmg_>"
+	if err := lt.StageRemove(utils.TimingsPrefix, &utils.TenantID{ID: "TM_1"}, nil); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+}
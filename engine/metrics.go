@@ -0,0 +1,249 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// Metrics is the registry used by the subsystems to publish their counters
+// it is nil(disabled) unless the metrics handler is configured
+var Metrics *MetricsRegistry
+
+// metricsHelp contains the type and the description of the known metrics
+var metricsHelp = map[string][2]string{
+	utils.MetricAPICallDuration:  {utils.MetricSummary, "Duration of the API calls in seconds"},
+	utils.MetricAPICallErrors:    {utils.MetricCounter, "Number of API calls that returned error"},
+	utils.MetricCapsAllocated:    {utils.MetricGauge, "Number of API calls currently allocated by caps"},
+	utils.MetricCapsLimit:        {utils.MetricGauge, "Maximum number of API calls allowed by caps"},
+	utils.MetricCapsPeak:         {utils.MetricGauge, "Peak of the allocated caps"},
+	utils.MetricCapsAverage:      {utils.MetricGauge, "Average of the allocated caps"},
+	utils.MetricCacheItems:       {utils.MetricGauge, "Number of items in the cache partition"},
+	utils.MetricCacheHits:        {utils.MetricCounter, "Number of cache hits for the partition"},
+	utils.MetricCacheMisses:      {utils.MetricCounter, "Number of cache misses for the partition"},
+	utils.MetricSessionsActive:   {utils.MetricGauge, "Number of active sessions"},
+	utils.MetricERsEvents:        {utils.MetricCounter, "Number of events read by ERs"},
+	utils.MetricERsDuplicates:    {utils.MetricCounter, "Number of duplicated events dropped by ERs"},
+	utils.MetricEEsEvents:        {utils.MetricCounter, "Number of events exported by EEs"},
+	utils.MetricStatQueueMetric:  {utils.MetricGauge, "Value of the StatQueue metric"},
+	utils.MetricGoroutines:       {utils.MetricGauge, "Number of active goroutines"},
+	utils.MetricMemoryHeapAllocs: {utils.MetricGauge, "Bytes of allocated heap objects"},
+}
+
+// metricsMaxSeries limits the label sets kept for one metric, the label values
+// come also from the clients(ie: API methods) so they can not grow unbounded
+const metricsMaxSeries = 1000
+
+// NewMetricsRegistry returns a new MetricsRegistry
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{maxSeries: metricsMaxSeries}
+}
+
+// MetricsRegistry keeps the metric samples indexed on name and labels
+// the samples are updated atomically so the subsystems do not share a lock
+type MetricsRegistry struct {
+	metrics   sync.Map // map[string]*metricSeries
+	maxSeries int32
+}
+
+// metricSeries keeps the samples of one metric indexed on labels
+type metricSeries struct {
+	samples sync.Map // map[string]*metricSample
+	cnt     int32    // number of samples, limited by maxSeries
+}
+
+// metricSample is the value of a metric for a label set
+type metricSample struct {
+	value uint64 // bits of the value of the counter or gauge, sum for the summaries
+	count uint64 // number of observations for the summaries
+}
+
+// add adds val to the sample value
+func (ms *metricSample) add(val float64) {
+	for {
+		old := atomic.LoadUint64(&ms.value)
+		if atomic.CompareAndSwapUint64(&ms.value, old,
+			math.Float64bits(math.Float64frombits(old)+val)) {
+			return
+		}
+	}
+}
+
+// set replaces the sample value with val
+func (ms *metricSample) set(val float64) {
+	atomic.StoreUint64(&ms.value, math.Float64bits(val))
+}
+
+// load returns the sample value
+func (ms *metricSample) load() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&ms.value))
+}
+
+// getSample returns the sample for name and labels, creating it if needed
+// once the metric reaches maxSeries the new label sets are counted with
+// all the label values replaced by *other
+func (mr *MetricsRegistry) getSample(name string, lbls []string) *metricSample {
+	srs, has := mr.metrics.Load(name)
+	if !has {
+		srs, _ = mr.metrics.LoadOrStore(name, new(metricSeries))
+	}
+	series := srs.(*metricSeries)
+	lblsStr := metricLabels(lbls)
+	if smpl, has := series.samples.Load(lblsStr); has {
+		return smpl.(*metricSample)
+	}
+	if atomic.AddInt32(&series.cnt, 1) > mr.maxSeries {
+		atomic.AddInt32(&series.cnt, -1)
+		lblsStr = metricLabels(otherMetricLabels(lbls))
+	}
+	smpl, loaded := series.samples.LoadOrStore(lblsStr, new(metricSample))
+	if loaded {
+		atomic.AddInt32(&series.cnt, -1)
+	}
+	return smpl.(*metricSample)
+}
+
+// otherMetricLabels returns the label keys with all the values set to *other
+func otherMetricLabels(lbls []string) (othr []string) {
+	othr = make([]string, len(lbls))
+	for i := range lbls {
+		othr[i] = lbls[i]
+		if i%2 == 1 {
+			othr[i] = utils.MetricOther
+		}
+	}
+	return
+}
+
+// IncCounter increments with one the counter identified by name and labels
+// the labels are given as key, value pairs
+func (mr *MetricsRegistry) IncCounter(name string, lbls ...string) {
+	mr.AddCounter(name, 1, lbls...)
+}
+
+// AddCounter adds val to the counter identified by name and labels
+func (mr *MetricsRegistry) AddCounter(name string, val float64, lbls ...string) {
+	if mr == nil {
+		return
+	}
+	mr.getSample(name, lbls).add(val)
+}
+
+// SetGauge sets the value of the gauge identified by name and labels
+func (mr *MetricsRegistry) SetGauge(name string, val float64, lbls ...string) {
+	if mr == nil {
+		return
+	}
+	mr.getSample(name, lbls).set(val)
+}
+
+// ObserveDuration adds a new observation to the summary identified by name and labels
+func (mr *MetricsRegistry) ObserveDuration(name string, d time.Duration, lbls ...string) {
+	if mr == nil {
+		return
+	}
+	smpl := mr.getSample(name, lbls)
+	smpl.add(d.Seconds())
+	atomic.AddUint64(&smpl.count, 1)
+}
+
+// WriteMetrics writes the metrics in the Prometheus text exposition format
+func (mr *MetricsRegistry) WriteMetrics(w io.Writer) (err error) {
+	series := make(map[string]*metricSeries)
+	names := make([]string, 0)
+	mr.metrics.Range(func(name, srs interface{}) bool {
+		names = append(names, name.(string))
+		series[name.(string)] = srs.(*metricSeries)
+		return true
+	})
+	sort.Strings(names)
+	for _, name := range names {
+		typ := utils.MetricUntyped
+		if hlp, has := metricsHelp[name]; has {
+			typ = hlp[0]
+			if _, err = fmt.Fprintf(w, "# HELP %s %s\n", name, hlp[1]); err != nil {
+				return
+			}
+		}
+		if _, err = fmt.Fprintf(w, "# TYPE %s %s\n", name, typ); err != nil {
+			return
+		}
+		samples := make(map[string]*metricSample)
+		lblsStrs := make([]string, 0)
+		series[name].samples.Range(func(lblsStr, smpl interface{}) bool {
+			lblsStrs = append(lblsStrs, lblsStr.(string))
+			samples[lblsStr.(string)] = smpl.(*metricSample)
+			return true
+		})
+		sort.Strings(lblsStrs)
+		for _, lblsStr := range lblsStrs {
+			smpl := samples[lblsStr]
+			if typ != utils.MetricSummary {
+				if _, err = fmt.Fprintf(w, "%s%s %s\n", name, lblsStr, formatMetricValue(smpl.load())); err != nil {
+					return
+				}
+				continue
+			}
+			if _, err = fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n",
+				name, lblsStr, formatMetricValue(smpl.load()),
+				name, lblsStr, atomic.LoadUint64(&smpl.count)); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// metricLabels returns the label set out of the key, value pairs
+func metricLabels(lbls []string) string {
+	if len(lbls) < 2 {
+		return utils.EmptyString
+	}
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i := 0; i+1 < len(lbls); i += 2 {
+		if i != 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(lbls[i])
+		sb.WriteString(`="`)
+		sb.WriteString(metricLabelReplacer.Replace(lbls[i+1]))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// metricLabelReplacer escapes the label values
+var metricLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatMetricValue returns the value as expected by Prometheus
+func formatMetricValue(val float64) string {
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
@@ -0,0 +1,119 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestMetricsRegistryWriteMetrics(t *testing.T) {
+	mr := NewMetricsRegistry()
+	mr.IncCounter(utils.MetricERsEvents, utils.MetricReader, "rdr2", utils.MetricStatus, utils.MetricFailed)
+	mr.IncCounter(utils.MetricERsEvents, utils.MetricReader, "rdr1", utils.MetricStatus, utils.MetricProcessed)
+	mr.IncCounter(utils.MetricERsEvents, utils.MetricReader, "rdr1", utils.MetricStatus, utils.MetricProcessed)
+	mr.ObserveDuration(utils.MetricAPICallDuration, 500*time.Millisecond, utils.MetricMethod, utils.CoreSv1Status)
+	mr.ObserveDuration(utils.MetricAPICallDuration, time.Second, utils.MetricMethod, utils.CoreSv1Status)
+	mr.SetGauge(utils.MetricGoroutines, 10)
+	mr.SetGauge(utils.MetricGoroutines, 12)
+	mr.SetGauge("custom_metric", 1.5, "label", "with \"quotes\"")
+	exp := `# HELP cgrates_api_call_duration_seconds Duration of the API calls in seconds
+# TYPE cgrates_api_call_duration_seconds summary
+cgrates_api_call_duration_seconds_sum{method="CoreSv1.Status"} 1.5
+cgrates_api_call_duration_seconds_count{method="CoreSv1.Status"} 2
+# HELP cgrates_ers_events_total Number of events read by ERs
+# TYPE cgrates_ers_events_total counter
+cgrates_ers_events_total{reader="rdr1",status="processed"} 2
+cgrates_ers_events_total{reader="rdr2",status="failed"} 1
+# HELP cgrates_goroutines Number of active goroutines
+# TYPE cgrates_goroutines gauge
+cgrates_goroutines 12
+# TYPE custom_metric untyped
+custom_metric{label="with \"quotes\""} 1.5
+`
+	var buf bytes.Buffer
+	if err := mr.WriteMetrics(&buf); err != nil {
+		t.Error(err)
+	} else if rcv := buf.String(); rcv != exp {
+		t.Errorf("Expected: %s\nReceived: %s", exp, rcv)
+	}
+}
+
+func TestMetricsRegistryNil(t *testing.T) {
+	var mr *MetricsRegistry
+	mr.IncCounter(utils.MetricERsEvents, utils.MetricReader, "rdr1")
+	mr.SetGauge(utils.MetricGoroutines, 1)
+	mr.ObserveDuration(utils.MetricAPICallDuration, time.Second)
+}
+
+func TestMetricsRegistryMaxSeries(t *testing.T) {
+	mr := NewMetricsRegistry()
+	mr.maxSeries = 2
+	mr.IncCounter(utils.MetricAPICallErrors, utils.MetricMethod, "Method1")
+	mr.IncCounter(utils.MetricAPICallErrors, utils.MetricMethod, "Method2")
+	mr.IncCounter(utils.MetricAPICallErrors, utils.MetricMethod, "Method3")
+	mr.IncCounter(utils.MetricAPICallErrors, utils.MetricMethod, "Method4")
+	mr.IncCounter(utils.MetricAPICallErrors, utils.MetricMethod, "Method1")
+	exp := `# HELP cgrates_api_call_errors_total Number of API calls that returned error
+# TYPE cgrates_api_call_errors_total counter
+cgrates_api_call_errors_total{method="*other"} 2
+cgrates_api_call_errors_total{method="Method1"} 2
+cgrates_api_call_errors_total{method="Method2"} 1
+`
+	var buf bytes.Buffer
+	if err := mr.WriteMetrics(&buf); err != nil {
+		t.Error(err)
+	} else if rcv := buf.String(); rcv != exp {
+		t.Errorf("Expected: %s\nReceived: %s", exp, rcv)
+	}
+}
+
+func TestMetricsRegistryConcurrent(t *testing.T) {
+	mr := NewMetricsRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				mr.IncCounter(utils.MetricAPICallErrors, utils.MetricMethod, utils.CoreSv1Status)
+				mr.ObserveDuration(utils.MetricAPICallDuration, 500*time.Millisecond, utils.MetricMethod, utils.CoreSv1Status)
+			}
+		}()
+	}
+	wg.Wait()
+	exp := `# HELP cgrates_api_call_duration_seconds Duration of the API calls in seconds
+# TYPE cgrates_api_call_duration_seconds summary
+cgrates_api_call_duration_seconds_sum{method="CoreSv1.Status"} 500
+cgrates_api_call_duration_seconds_count{method="CoreSv1.Status"} 1000
+# HELP cgrates_api_call_errors_total Number of API calls that returned error
+# TYPE cgrates_api_call_errors_total counter
+cgrates_api_call_errors_total{method="CoreSv1.Status"} 1000
+`
+	var buf bytes.Buffer
+	if err := mr.WriteMetrics(&buf); err != nil {
+		t.Error(err)
+	} else if rcv := buf.String(); rcv != exp {
+		t.Errorf("Expected: %s\nReceived: %s", exp, rcv)
+	}
+}
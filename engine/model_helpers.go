@@ -1210,13 +1210,25 @@ func APItoResource(tpRL *utils.TPResourceProfile, timezone string) (rp *Resource
 		}
 	}
 	if tpRL.Limit != utils.EmptyString {
-		if rp.Limit, err = strconv.ParseFloat(tpRL.Limit, 64); err != nil {
+		if rp.Limit, rp.LimitInterval, err = parseResourceLimit(tpRL.Limit); err != nil {
 			return nil, err
 		}
 	}
 	return rp, nil
 }
 
+// parseResourceLimit parses the limit of the resource, <limit>/<interval> for rate limits
+func parseResourceLimit(limit string) (lmt float64, intvl time.Duration, err error) {
+	if idx := strings.Index(limit, utils.Slash); idx != -1 {
+		if intvl, err = utils.ParseDurationWithNanosecs(limit[idx+1:]); err != nil {
+			return
+		}
+		limit = limit[:idx]
+	}
+	lmt, err = strconv.ParseFloat(limit, 64)
+	return
+}
+
 func ResourceProfileToAPI(rp *ResourceProfile) (tpRL *utils.TPResourceProfile) {
 	tpRL = &utils.TPResourceProfile{
 		Tenant:             rp.Tenant,
@@ -1233,6 +1245,9 @@ func ResourceProfileToAPI(rp *ResourceProfile) (tpRL *utils.TPResourceProfile) {
 	if rp.UsageTTL != time.Duration(0) {
 		tpRL.UsageTTL = rp.UsageTTL.String()
 	}
+	if rp.LimitInterval != time.Duration(0) {
+		tpRL.Limit += utils.Slash + rp.LimitInterval.String()
+	}
 	for i, fli := range rp.FilterIDs {
 		tpRL.FilterIDs[i] = fli
 	}
@@ -1907,6 +1907,37 @@ func TestResourceProfileToAPI(t *testing.T) {
 	}
 }
 
+func TestAPItoResourceLimitInterval(t *testing.T) {
+	tpRL := &utils.TPResourceProfile{
+		Tenant: "cgrates.org",
+		TPid:   testTPID,
+		ID:     "RES_CPS",
+		Limit:  "10/1s",
+	}
+	eRL := &ResourceProfile{
+		Tenant:        "cgrates.org",
+		ID:            "RES_CPS",
+		FilterIDs:     []string{},
+		ThresholdIDs:  []string{},
+		Limit:         10,
+		LimitInterval: time.Second,
+	}
+	rl, err := APItoResource(tpRL, "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(eRL, rl) {
+		t.Errorf("Expecting: %+v, received: %+v", utils.ToJSON(eRL), utils.ToJSON(rl))
+	}
+	if rcv := ResourceProfileToAPI(rl); rcv.Limit != tpRL.Limit {
+		t.Errorf("Expecting: %q, received: %q", tpRL.Limit, rcv.Limit)
+	}
+	tpRL.Limit = "10/a"
+	if _, err := APItoResource(tpRL, "UTC"); err == nil {
+		t.Error("Expected error for invalid limit interval")
+	}
+}
+
 func TestAPItoModelResource(t *testing.T) {
 	tpRL := &utils.TPResourceProfile{
 		Tenant:             "cgrates.org",
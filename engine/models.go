@@ -398,6 +398,44 @@ func (t SessionCostsSQL) TableName() string {
 	return utils.SessionCostsTBL
 }
 
+type AuditLogSQL struct {
+	ID             int64
+	AuditID        string
+	Method         string
+	Subject        string
+	Role           string
+	RemoteAddr     string
+	Tenant         string
+	ItemID         string
+	Args           string
+	BeforeSnapshot string
+	AfterSnapshot  string
+	Diff           string
+	Error          string
+	CreatedAt      time.Time
+}
+
+func (t AuditLogSQL) TableName() string {
+	return utils.AuditLogTBL
+}
+
+type InvoiceSQL struct {
+	ID         int64
+	Tenant     string
+	InvoiceID  string
+	Number     int64
+	Account    string
+	CycleStart time.Time
+	CycleEnd   time.Time
+	LineItems  string
+	Total      float64
+	CreatedAt  time.Time
+}
+
+func (t InvoiceSQL) TableName() string {
+	return utils.InvoicesTBL
+}
+
 type TBLVersion struct {
 	ID      uint
 	Item    string
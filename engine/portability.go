@@ -0,0 +1,55 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"github.com/cgrates/cgrates/utils"
+)
+
+// PortedNumber is one entry of the number portability database
+type PortedNumber struct {
+	Tenant        string
+	ID            string // the ported number
+	RoutingNumber string // the number used to route and rate towards the recipient network
+	Carrier       string // the recipient network
+}
+
+// PortedNumberWithOpts is used in replicatorV1 for the number portability entries
+type PortedNumberWithOpts struct {
+	*PortedNumber
+	Opts map[string]interface{}
+}
+
+// TenantID returns the concatenated key between tenant and ID
+func (pn *PortedNumber) TenantID() string {
+	return utils.ConcatenatedKey(pn.Tenant, pn.ID)
+}
+
+// lookupPortedNumber returns the portability entry of the number,
+// the number routed as it is if it was not ported
+func lookupPortedNumber(dm *DataManager, tnt, number string) (pn *PortedNumber, err error) {
+	if pn, err = dm.GetPortedNumber(tnt, number, true, true,
+		utils.NonTransactional); err == utils.ErrNotFound {
+		return &PortedNumber{Tenant: tnt, ID: number, RoutingNumber: number}, nil
+	}
+	if err == nil && pn.RoutingNumber == utils.EmptyString { // only the carrier changed
+		pn = &PortedNumber{Tenant: pn.Tenant, ID: pn.ID, RoutingNumber: pn.ID, Carrier: pn.Carrier}
+	}
+	return
+}
@@ -0,0 +1,172 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestDMPortedNumber(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	dm := NewDataManager(NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	pn := &PortedNumber{
+		Tenant:        "cgrates.org",
+		ID:            "+4917612345678",
+		RoutingNumber: "D262+4917612345678",
+		Carrier:       "CARRIER2",
+	}
+	if _, err := dm.GetPortedNumber(pn.Tenant, pn.ID, false, false,
+		utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err := dm.SetPortedNumber(pn); err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := dm.GetPortedNumber(pn.Tenant, pn.ID, false, false,
+		utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(pn, rcv) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(pn), utils.ToJSON(rcv))
+	}
+	if err := dm.RemovePortedNumber(pn.Tenant, pn.ID, utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+	if _, err := dm.GetPortedNumber(pn.Tenant, pn.ID, false, false,
+		utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err := dm.RemovePortedNumber(pn.Tenant, pn.ID, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestLookupPortedNumber(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	dm := NewDataManager(NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	if err := dm.SetPortedNumber(&PortedNumber{Tenant: "cgrates.org", ID: "+4917611111111",
+		Carrier: "CARRIER2"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		number   string
+		expected *PortedNumber
+	}{
+		{number: "+4917600000000", // not ported
+			expected: &PortedNumber{Tenant: "cgrates.org", ID: "+4917600000000",
+				RoutingNumber: "+4917600000000"}},
+		{number: "+4917611111111", // only the carrier changed
+			expected: &PortedNumber{Tenant: "cgrates.org", ID: "+4917611111111",
+				RoutingNumber: "+4917611111111", Carrier: "CARRIER2"}},
+	} {
+		if rcv, err := lookupPortedNumber(dm, "cgrates.org", tc.number); err != nil {
+			t.Errorf("%s: %v", tc.number, err)
+		} else if !reflect.DeepEqual(tc.expected, rcv) {
+			t.Errorf("%s: expected %s, received %s", tc.number, utils.ToJSON(tc.expected), utils.ToJSON(rcv))
+		}
+	}
+	if _, err := lookupPortedNumber(nil, "cgrates.org", "+4917622222222"); err != utils.ErrNoDatabaseConn {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNoDatabaseConn, err)
+	}
+}
+
+func TestDynamicDPFieldAsInterfaceLNP(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	dmLNP := NewDataManager(NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	oldDm := dm
+	SetDataStorage(dmLNP)
+	defer SetDataStorage(oldDm)
+	if err := dmLNP.SetPortedNumber(&PortedNumber{Tenant: "cgrates.org", ID: "+4917633333333",
+		RoutingNumber: "D262+4917633333333", Carrier: "CARRIER2"}); err != nil {
+		t.Fatal(err)
+	}
+	dDP := newDynamicDP(nil, nil, nil, "cgrates.org", utils.MapStorage{})
+	for _, tc := range []struct {
+		fldPath  []string
+		expected interface{}
+	}{
+		{fldPath: []string{utils.MetaLNP, "+4917633333333"}, expected: "D262+4917633333333"},
+		{fldPath: []string{utils.MetaLNP, "+4917633333333", utils.Carrier}, expected: "CARRIER2"},
+		{fldPath: []string{utils.MetaLNP, "+4917644444444"}, expected: "+4917644444444"},
+		{fldPath: []string{utils.MetaLNP, "+4917644444444", utils.Carrier}, expected: utils.EmptyString},
+	} {
+		if rcv, err := dDP.FieldAsInterface(tc.fldPath); err != nil {
+			t.Errorf("%s: %v", tc.fldPath, err)
+		} else if rcv != tc.expected {
+			t.Errorf("%s: expected %q, received %q", tc.fldPath, tc.expected, rcv)
+		}
+	}
+}
+
+func TestProcessAttributeLNP(t *testing.T) {
+	defaultCfg := config.NewDefaultCGRConfig()
+	defaultCfg.AttributeSCfg().ProcessRuns = 1
+	dmAtr = NewDataManager(NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	attrService, _ = NewAttributeService(dmAtr, &FilterS{dm: dmAtr, cfg: defaultCfg}, defaultCfg)
+	if err := dmAtr.SetPortedNumber(&PortedNumber{Tenant: "cgrates.org", ID: "+4917655555555",
+		RoutingNumber: "D262+4917655555555", Carrier: "CARRIER2"}); err != nil {
+		t.Fatal(err)
+	}
+	attrPrf := &AttributeProfile{
+		Tenant:   "cgrates.org",
+		ID:       "ATTR_LNP",
+		Contexts: []string{utils.MetaAny},
+		Attributes: []*Attribute{
+			{
+				Path:  utils.MetaReq + utils.NestingSep + utils.Destination,
+				Type:  utils.MetaLNP,
+				Value: config.NewRSRParsersMustCompile("~*req.Destination", utils.InfieldSep),
+			},
+		},
+		Weight: 10,
+	}
+	if err := dmAtr.SetAttributeProfile(attrPrf, true); err != nil {
+		t.Fatal(err)
+	}
+	for number, expected := range map[string]string{
+		"+4917655555555": "D262+4917655555555",
+		"+4917666666666": "+4917666666666", // not ported
+	} {
+		ev := &AttrArgsProcessEvent{
+			CGREvent: &utils.CGREvent{
+				Tenant: "cgrates.org",
+				ID:     "TestProcessAttributeLNP",
+				Event: map[string]interface{}{
+					utils.Destination: number,
+				},
+			},
+		}
+		eNM := utils.MapStorage{
+			utils.MetaReq:  ev.CGREvent.Event,
+			utils.MetaOpts: ev.Opts,
+			utils.MetaVars: utils.MapStorage{
+				utils.ProcessRuns: utils.NewNMData(0),
+			},
+		}
+		if rcv, err := attrService.processEvent(ev.Tenant, ev, eNM,
+			newDynamicDP(nil, nil, nil, "cgrates.org", eNM), utils.EmptyString); err != nil {
+			t.Errorf("%s: %v", number, err)
+		} else if rcv.CGREvent.Event[utils.Destination] != expected {
+			t.Errorf("%s: expected %q, received %q", number, expected, rcv.CGREvent.Event[utils.Destination])
+		}
+	}
+}
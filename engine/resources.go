@@ -43,6 +43,7 @@ type ResourceProfile struct {
 	ActivationInterval *utils.ActivationInterval // time when this resource becomes active and expires
 	UsageTTL           time.Duration             // auto-expire the usage after this duration
 	Limit              float64                   // limit value
+	LimitInterval      time.Duration             // when set, Limit applies to the usages allocated within this sliding interval
 	AllocationMessage  string                    // message returned by the winning resource on allocation
 	Blocker            bool                      // blocker flag to stop processing on filters matched
 	Stored             bool
@@ -110,6 +111,12 @@ func (r *Resource) TenantID() string {
 	return utils.ConcatenatedKey(r.Tenant, r.ID)
 }
 
+// isRateLimited returns true if the Limit applies to the usages allocated within LimitInterval
+// the usages of such resources are not given back on release, only expiring with the interval
+func (r *Resource) isRateLimited() bool {
+	return r.rPrf != nil && r.rPrf.LimitInterval > 0
+}
+
 // removeExpiredUnits removes units which are expired from the resource
 func (r *Resource) removeExpiredUnits() {
 	var firstActive int
@@ -163,9 +170,15 @@ func (r *Resource) TotalUsage() (tU float64) {
 // recordUsage records a new usage
 func (r *Resource) recordUsage(ru *ResourceUsage) (err error) {
 	if _, hasID := r.Usages[ru.ID]; hasID {
+		if r.isRateLimited() {
+			return // already counted within the interval
+		}
 		return fmt.Errorf("duplicate resource usage with id: %s", ru.TenantID())
 	}
-	if r.ttl != nil && *r.ttl != -1 {
+	if r.isRateLimited() {
+		ru = ru.Clone()
+		ru.ExpiryTime = time.Now().Add(r.rPrf.LimitInterval)
+	} else if r.ttl != nil && *r.ttl != -1 {
 		if *r.ttl == 0 {
 			return // no recording for ttl of 0
 		}
@@ -232,8 +245,12 @@ func (rs Resources) recordUsage(ru *ResourceUsage) (err error) {
 }
 
 // clearUsage gives back the units to the pool
+// the rate limited resources keep the usages until the end of their interval
 func (rs Resources) clearUsage(ruTntID string) (err error) {
 	for _, r := range rs {
+		if r.isRateLimited() {
+			continue
+		}
 		if errClear := r.clearUsage(ruTntID); errClear != nil &&
 			r.ttl != nil && *r.ttl != 0 { // we only consider not found error in case of ttl different than 0
 			utils.Logger.Warning(fmt.Sprintf("<%s>, clear ruID: %s, err: %s", utils.ResourceS, ruTntID, errClear.Error()))
@@ -276,11 +293,12 @@ func (rs Resources) allocateResource(ru *ResourceUsage, dryRun bool) (alcMessage
 		return "", utils.ErrResourceUnavailable
 	}
 	lockIDs := utils.PrefixSliceItems(rs.tenatIDs(), utils.ResourcesPrefix)
-	guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
+	if _, lkErr := guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
 		// Simulate resource usage
 		for _, r := range rs {
 			r.removeExpiredUnits()
-			if _, hasID := r.Usages[ru.ID]; hasID && !dryRun { // update
+			_, hasID := r.Usages[ru.ID]
+			if hasID && !dryRun && !r.isRateLimited() { // update
 				if err = r.clearUsage(ru.ID); err != nil {
 					return
 				}
@@ -289,7 +307,8 @@ func (rs Resources) allocateResource(ru *ResourceUsage, dryRun bool) (alcMessage
 				err = fmt.Errorf("empty configuration for resourceID: %s", r.TenantID())
 				return
 			}
-			if r.rPrf.Limit >= r.totalUsage()+ru.Units || r.rPrf.Limit == -1 {
+			if (hasID && r.isRateLimited()) || // already counted within the interval
+				r.rPrf.Limit >= r.totalUsage()+ru.Units || r.rPrf.Limit == -1 {
 				if alcMessage == "" {
 					if r.rPrf.AllocationMessage != "" {
 						alcMessage = r.rPrf.AllocationMessage
@@ -308,7 +327,9 @@ func (rs Resources) allocateResource(ru *ResourceUsage, dryRun bool) (alcMessage
 		}
 		err = rs.recordUsage(ru)
 		return
-	}, config.CgrConfig().GeneralCfg().LockingTimeout, lockIDs...)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, lockIDs...); lkErr != nil {
+		return "", lkErr
+	}
 	return
 }
 
@@ -348,11 +369,24 @@ func (rS *ResourceService) Shutdown() error {
 }
 
 // StoreResource stores the resource in DB and corrects dirty flag
+// the resource is locked while stored so the write can be fenced with the token of the lock
 func (rS *ResourceService) StoreResource(r *Resource) (err error) {
 	if r.dirty == nil || !*r.dirty {
 		return
 	}
-	if err = rS.dm.SetResource(r, nil, 0, true); err != nil {
+	lkID := utils.ResourcesPrefix + r.TenantID()
+	_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+		return nil, rS.storeResource(r, tokens[lkID])
+	}, rS.cgrcfg.GeneralCfg().LockingTimeout, lkID)
+	return
+}
+
+// storeResource writes the resource if the lock is still owned by the lkToken
+func (rS *ResourceService) storeResource(r *Resource, lkToken int64) (err error) {
+	if err = guardian.Guardian.CheckToken(utils.ResourcesPrefix+r.TenantID(), lkToken); err == nil {
+		err = rS.dm.SetResource(r, nil, 0, true)
+	}
+	if err != nil {
 		utils.Logger.Warning(
 			fmt.Sprintf("<ResourceS> failed saving Resource with ID: %s, error: %s",
 				r.ID, err.Error()))
@@ -489,7 +523,7 @@ func (rS *ResourceService) matchingResourcesForEvent(tnt string, ev *utils.CGREv
 		}
 	}
 	lockIDs := utils.PrefixSliceItems(rs.IDs(), utils.ResourcesPrefix)
-	guardian.Guardian.Guard(func() (gIface interface{}, gErr error) {
+	if _, lkErr := guardian.Guardian.Guard(func() (gIface interface{}, gErr error) {
 		for resName := range rIDs {
 			var rPrf *ResourceProfile
 			if rPrf, err = rS.dm.GetResourceProfile(tnt, resName,
@@ -527,7 +561,9 @@ func (rS *ResourceService) matchingResourcesForEvent(tnt string, ev *utils.CGREv
 			matchingResources[rPrf.ID] = r
 		}
 		return
-	}, config.CgrConfig().GeneralCfg().LockingTimeout, lockIDs...)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, lockIDs...); lkErr != nil {
+		err = lkErr
+	}
 	if err != nil {
 		if isCached {
 			if errCh := Cache.Remove(utils.CacheEventResources, evUUID,
@@ -139,6 +139,7 @@ func (sg *SharedGroup) GetBalances(destination, category, balanceType string, ub
 			if nUb == nil || nUb.Disabled {
 				continue
 			}
+			nUb.lkTokens = ub.lkTokens // the members are locked together with the initiating account
 		}
 		//sg.members = append(sg.members, nUb)
 		sb := nUb.getBalancesForPrefix(destination, category, balanceType, sg.Id, aTime)
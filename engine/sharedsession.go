@@ -0,0 +1,37 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"time"
+)
+
+// SharedSession is an active session stored in the shared registry of SessionS
+// the owner node keeps renewing the Expiry, once expired the session can be claimed by another node
+type SharedSession struct {
+	CGRID   string
+	NodeID  string    // the node owning the session
+	Expiry  time.Time // end of the owner lease
+	Session []byte    // the session state, encoded by SessionS
+}
+
+// expired returns true if the owner lease ended before now
+func (ss *SharedSession) expired(now time.Time) bool {
+	return !now.Before(ss.Expiry)
+}
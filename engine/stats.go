@@ -96,16 +96,13 @@ func (sS *StatService) storeStats() {
 		if sID == "" {
 			break // no more keys, backup completed
 		}
-		guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
-			if sqIf, ok := Cache.Get(utils.CacheStatQueues, sID); !ok || sqIf == nil {
-				utils.Logger.Warning(
-					fmt.Sprintf("<%s> failed retrieving from cache stat queue with ID: %s",
-						utils.StatService, sID))
-			} else if err := sS.StoreStatQueue(sqIf.(*StatQueue)); err != nil {
-				failedSqIDs = append(failedSqIDs, sID) // record failure so we can schedule it for next backup
-			}
-			return
-		}, sS.cgrcfg.GeneralCfg().LockingTimeout, utils.StatQueuePrefix+sID)
+		if sqIf, ok := Cache.Get(utils.CacheStatQueues, sID); !ok || sqIf == nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> failed retrieving from cache stat queue with ID: %s",
+					utils.StatService, sID))
+		} else if err := sS.StoreStatQueue(sqIf.(*StatQueue)); err != nil {
+			failedSqIDs = append(failedSqIDs, sID) // record failure so we can schedule it for next backup
+		}
 		// randomize the CPU load and give up thread control
 		runtime.Gosched()
 	}
@@ -117,11 +114,24 @@ func (sS *StatService) storeStats() {
 }
 
 // StoreStatQueue stores the statQueue in DB and corrects dirty flag
+// the statQueue is locked while stored so the write can be fenced with the token of the lock
 func (sS *StatService) StoreStatQueue(sq *StatQueue) (err error) {
 	if sq.dirty == nil || !*sq.dirty {
 		return
 	}
-	if err = sS.dm.SetStatQueue(sq, nil, 0, nil, 0, true); err != nil {
+	lkID := utils.StatQueuePrefix + sq.TenantID()
+	_, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+		return nil, sS.storeStatQueueLocked(sq, tokens[lkID])
+	}, sS.cgrcfg.GeneralCfg().LockingTimeout, lkID)
+	return
+}
+
+// storeStatQueueLocked writes the statQueue if the lock is still owned by the lkToken
+func (sS *StatService) storeStatQueueLocked(sq *StatQueue, lkToken int64) (err error) {
+	if err = guardian.Guardian.CheckToken(utils.StatQueuePrefix+sq.TenantID(), lkToken); err == nil {
+		err = sS.dm.SetStatQueue(sq, nil, 0, nil, 0, true)
+	}
+	if err != nil {
 		utils.Logger.Warning(
 			fmt.Sprintf("<StatS> failed saving StatQueue with ID: %s, error: %s",
 				sq.TenantID(), err.Error()))
@@ -175,10 +185,12 @@ func (sS *StatService) matchingStatQueuesForEvent(tnt string, statsIDs []string,
 		}
 		var sq *StatQueue
 		lkID := utils.StatQueuePrefix + utils.ConcatenatedKey(sqPrfl.Tenant, sqPrfl.ID)
-		guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
+		if _, lkErr := guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
 			sq, err = sS.dm.GetStatQueue(sqPrfl.Tenant, sqPrfl.ID, true, true, "")
 			return
-		}, sS.cgrcfg.GeneralCfg().LockingTimeout, lkID)
+		}, sS.cgrcfg.GeneralCfg().LockingTimeout, lkID); lkErr != nil {
+			err = lkErr
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -252,10 +264,12 @@ func (sS *StatService) getStatQueue(tnt, id string) (sq *StatQueue, err error) {
 	}
 	lkID := utils.StatQueuePrefix + sq.TenantID()
 	var removed int
-	guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
+	if _, lkErr := guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
 		removed, err = sq.remExpired()
 		return
-	}, sS.cgrcfg.GeneralCfg().LockingTimeout, lkID)
+	}, sS.cgrcfg.GeneralCfg().LockingTimeout, lkID); lkErr != nil {
+		err = lkErr
+	}
 	if err != nil || removed == 0 {
 		return
 	}
@@ -300,10 +314,12 @@ func (sS *StatService) processEvent(tnt string, args *StatsArgsProcessEvent) (st
 	for _, sq := range matchSQs {
 		stsIDs = append(stsIDs, sq.ID)
 		lkID := utils.StatQueuePrefix + sq.TenantID()
-		guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
+		if _, lkErr := guardian.Guardian.Guard(func() (gRes interface{}, gErr error) {
 			err = sq.ProcessEvent(tnt, args.ID, sS.filterS, evNm)
 			return
-		}, sS.cgrcfg.GeneralCfg().LockingTimeout, lkID)
+		}, sS.cgrcfg.GeneralCfg().LockingTimeout, lkID); lkErr != nil {
+			err = lkErr
+		}
 		if err != nil {
 			utils.Logger.Warning(
 				fmt.Sprintf("<StatS> Queue: %s, ignoring event: %s, error: %s",
@@ -311,6 +327,13 @@ func (sS *StatService) processEvent(tnt string, args *StatsArgsProcessEvent) (st
 			withErrors = true
 		}
 		sS.storeStatQueue(sq)
+		if EventStream != nil {
+			stEv := map[string]interface{}{utils.StatID: sq.ID}
+			for metricID, metric := range sq.SQMetrics {
+				stEv[metricID] = metric.GetValue(sS.cgrcfg.GeneralCfg().RoundingDecimals)
+			}
+			EventStream.Publish(utils.MetaStatUpdated, sq.Tenant, stEv)
+		}
 		if len(sS.cgrcfg.StatSCfg().ThresholdSConns) != 0 {
 			var thIDs []string
 			if len(sq.sqPrfl.ThresholdIDs) != 0 {
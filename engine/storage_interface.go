@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 	"github.com/cgrates/ugocodec/codec"
@@ -129,6 +130,9 @@ type DataDB interface {
 	GetDispatcherHostDrv(string, string) (*DispatcherHost, error)
 	SetDispatcherHostDrv(*DispatcherHost) error
 	RemoveDispatcherHostDrv(string, string) error
+	GetPortedNumberDrv(string, string) (*PortedNumber, error)
+	SetPortedNumberDrv(*PortedNumber) error
+	RemovePortedNumberDrv(string, string) error
 	GetRateProfileDrv(string, string) (*RateProfile, error)
 	SetRateProfileDrv(*RateProfile) error
 	RemoveRateProfileDrv(string, string) error
@@ -138,6 +142,16 @@ type DataDB interface {
 	GetAccountProfileDrv(string, string) (*utils.AccountProfile, error)
 	SetAccountProfileDrv(profile *utils.AccountProfile) error
 	RemoveAccountProfileDrv(string, string) error
+	SetDedupIDDrv(string, time.Duration) error
+	RemoveDedupIDDrv(string) error
+	GetSharedSessionsDrv() ([]*SharedSession, error)
+	SetSharedSessionDrv(*SharedSession) error
+	ClaimSharedSessionDrv(cgrID, nodeID string, expiry time.Time) (*SharedSession, error)
+	RemoveSharedSessionDrv(cgrID, nodeID string) error
+	GetConfigSectionsDrv() ([]*ConfigSection, error)
+	GetConfigSectionDrv(string) (*ConfigSection, error)
+	SetConfigSectionDrv(*ConfigSection) error
+	RemoveConfigSectionDrv(string) error
 }
 
 type StorDB interface {
@@ -154,6 +168,11 @@ type CdrStorage interface {
 	RemoveSMCost(*SMCost) error
 	RemoveSMCosts(qryFltr *utils.SMCostFilter) error
 	GetCDRs(*utils.CDRsFilter, bool) ([]*CDR, int64, error)
+	GetCDRsAggregates(*utils.CDRsFilter, []*utils.CDRsGroupBy, []string) ([]*utils.CDRsAggregate, error)
+	SetAuditEntry(*AuditEntry) error
+	GetAuditEntries(*utils.AuditLogFilter) ([]*AuditEntry, error)
+	SetInvoice(*Invoice) error
+	GetInvoices(*utils.InvoicesFilter) ([]*Invoice, error)
 }
 
 type LoadStorage interface {
@@ -39,6 +39,14 @@ type InternalDB struct {
 	indexedFieldsMutex  sync.RWMutex   // used for reload
 	cnter               *utils.Counter // used for OrderID for cdr
 	ms                  Marshaler
+	dedupIDs            map[string]time.Time // expiry time of the deduplication IDs
+	dedupSweep          time.Time            // next time the expired deduplication IDs are removed
+	dedupMux            sync.Mutex
+	sharedSessionsMux   sync.Mutex    // keeps the checks on the shared sessions atomic with their writes
+	invoicesMux         sync.Mutex    // keeps the invoice numbers unique
+	configSectionsMux   sync.Mutex    // keeps the checks on the config section versions atomic with their writes
+	dump                *internalDump // persists the writes on disk, nil if disabled
+	isDataDB            bool          // selects the cache partitions cleared on Flush
 }
 
 // NewInternalDB constructs an InternalDB
@@ -49,6 +57,23 @@ func NewInternalDB(stringIndexedFields, prefixIndexedFields []string, isDataDB b
 		prefixIndexedFields: prefixIndexedFields,
 		cnter:               utils.NewCounter(time.Now().UnixNano(), 0),
 		ms:                  ms,
+		dedupIDs:            make(map[string]time.Time),
+		isDataDB:            isDataDB,
+	}
+	return
+}
+
+// NewInternalDBWithDump constructs an InternalDB that persists its content in dumpPath
+func NewInternalDBWithDump(stringIndexedFields, prefixIndexedFields []string, isDataDB bool,
+	dumpPath, fsync string, fsyncInterval, snapshotInterval time.Duration) (iDB *InternalDB, err error) {
+	iDB = NewInternalDB(stringIndexedFields, prefixIndexedFields, isDataDB)
+	dbName := utils.StorDB
+	if isDataDB {
+		dbName = utils.DataDB
+	}
+	if iDB.dump, err = newInternalDump(dumpPath, dbName, fsync,
+		fsyncInterval, snapshotInterval); err != nil {
+		return nil, err
 	}
 	return
 }
@@ -67,13 +92,24 @@ func (iDB *InternalDB) SetPrefixIndexedFields(prefixIndexedFields []string) {
 	iDB.indexedFieldsMutex.Unlock()
 }
 
-// Close only to implement Storage interface
-func (iDB *InternalDB) Close() {}
+// Close stops the persistence of the DB if enabled
+func (iDB *InternalDB) Close() {
+	if iDB.dump == nil {
+		return
+	}
+	if err := iDB.dump.close(); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> failed closing the dump, error: <%s>",
+			utils.InternalDB, err.Error()))
+	}
+}
 
-// Flush clears the cache
+// Flush clears the cache partitions of the DB
 func (iDB *InternalDB) Flush(string) error {
-	Cache.Clear(nil)
-	return nil
+	chIDs := utils.StorDBPartitions.AsSlice()
+	if iDB.isDataDB {
+		chIDs = utils.DataDBPartitions.AsSlice()
+	}
+	return iDB.cacheClear(chIDs)
 }
 
 // SelectDatabase only to implement Storage interface
@@ -103,7 +139,7 @@ func (iDB *InternalDB) RemoveKeysForPrefix(prefix string) (err error) {
 		return
 	}
 	for _, key := range keys {
-		Cache.RemoveWithoutReplicate(utils.CacheReverseDestinations, key,
+		iDB.cacheRemove(utils.CacheReverseDestinations, key,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -132,7 +168,7 @@ func (iDB *InternalDB) SetVersions(vrs Versions, overwrite bool) (err error) {
 	}
 	x, ok := Cache.Get(utils.CacheVersions, utils.VersionName)
 	if !ok || x == nil {
-		Cache.SetWithoutReplicate(utils.CacheVersions, utils.VersionName, vrs, nil,
+		iDB.cacheSet(utils.CacheVersions, utils.VersionName, vrs, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 		return
 	}
@@ -140,7 +176,7 @@ func (iDB *InternalDB) SetVersions(vrs Versions, overwrite bool) (err error) {
 	for key, val := range vrs {
 		provVrs[key] = val
 	}
-	Cache.SetWithoutReplicate(utils.CacheVersions, utils.VersionName, provVrs, nil,
+	iDB.cacheSet(utils.CacheVersions, utils.VersionName, provVrs, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -156,11 +192,11 @@ func (iDB *InternalDB) RemoveVersions(vrs Versions) (err error) {
 		for key := range vrs {
 			delete(internalVersions, key)
 		}
-		Cache.SetWithoutReplicate(utils.CacheVersions, utils.VersionName, internalVersions, nil,
+		iDB.cacheSet(utils.CacheVersions, utils.VersionName, internalVersions, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 		return
 	}
-	Cache.RemoveWithoutReplicate(utils.CacheVersions, utils.VersionName,
+	iDB.cacheRemove(utils.CacheVersions, utils.VersionName,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -173,6 +209,9 @@ func (iDB *InternalDB) GetStorageType() string {
 // IsDBEmpty returns true if the cache is empty
 func (iDB *InternalDB) IsDBEmpty() (isEmpty bool, err error) {
 	for cacheInstance := range utils.CacheInstanceToPrefix {
+		if !utils.DataDBPartitions.Has(cacheInstance) { // ie: *apiban is only cached
+			continue
+		}
 		if len(Cache.GetItemIDs(cacheInstance, utils.EmptyString)) != 0 {
 			return
 		}
@@ -189,7 +228,8 @@ func (iDB *InternalDB) HasDataDrv(category, subject, tenant string) (bool, error
 	case utils.ResourcesPrefix, utils.ResourceProfilesPrefix, utils.StatQueuePrefix,
 		utils.StatQueueProfilePrefix, utils.ThresholdPrefix, utils.ThresholdProfilePrefix,
 		utils.FilterPrefix, utils.RouteProfilePrefix, utils.AttributeProfilePrefix,
-		utils.ChargerProfilePrefix, utils.DispatcherProfilePrefix, utils.DispatcherHostPrefix:
+		utils.ChargerProfilePrefix, utils.DispatcherProfilePrefix, utils.DispatcherHostPrefix,
+		utils.RateProfilePrefix:
 		return Cache.HasItem(utils.CachePrefixToInstance[category], utils.ConcatenatedKey(tenant, subject)), nil
 	}
 	return false, errors.New("Unsupported HasData category")
@@ -204,13 +244,13 @@ func (iDB *InternalDB) GetRatingPlanDrv(id string) (rp *RatingPlan, err error) {
 }
 
 func (iDB *InternalDB) SetRatingPlanDrv(rp *RatingPlan) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheRatingPlans, rp.Id, rp, nil,
+	iDB.cacheSet(utils.CacheRatingPlans, rp.Id, rp, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveRatingPlanDrv(id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheRatingPlans, id,
+	iDB.cacheRemove(utils.CacheRatingPlans, id,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -224,13 +264,13 @@ func (iDB *InternalDB) GetRatingProfileDrv(id string) (rp *RatingProfile, err er
 }
 
 func (iDB *InternalDB) SetRatingProfileDrv(rp *RatingProfile) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheRatingProfiles, rp.Id, rp, nil,
+	iDB.cacheSet(utils.CacheRatingProfiles, rp.Id, rp, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveRatingProfileDrv(id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheRatingProfiles, id,
+	iDB.cacheRemove(utils.CacheRatingProfiles, id,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -243,13 +283,13 @@ func (iDB *InternalDB) GetDestinationDrv(key, _ string) (dest *Destination, err
 }
 
 func (iDB *InternalDB) SetDestinationDrv(dest *Destination, transactionID string) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheDestinations, dest.Id, dest, nil,
+	iDB.cacheSet(utils.CacheDestinations, dest.Id, dest, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveDestinationDrv(destID string, transactionID string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheDestinations, destID,
+	iDB.cacheRemove(utils.CacheDestinations, destID,
 		cacheCommit(transactionID), transactionID)
 	return
 }
@@ -266,10 +306,10 @@ func (iDB *InternalDB) RemoveReverseDestinationDrv(dstID, prfx, transactionID st
 	mpRevDst := utils.NewStringSet(revDst)
 	mpRevDst.Remove(dstID)
 	if mpRevDst.Size() != 0 {
-		Cache.SetWithoutReplicate(utils.CacheReverseDestinations, prfx, mpRevDst.AsSlice(), nil,
+		iDB.cacheSet(utils.CacheReverseDestinations, prfx, mpRevDst.AsSlice(), nil,
 			cacheCommit(transactionID), transactionID)
 	} else {
-		Cache.RemoveWithoutReplicate(utils.CacheReverseDestinations, prfx,
+		iDB.cacheRemove(utils.CacheReverseDestinations, prfx,
 			cacheCommit(transactionID), transactionID)
 	}
 	return
@@ -286,7 +326,7 @@ func (iDB *InternalDB) SetReverseDestinationDrv(destID string, prefixes []string
 		mpRevDst := utils.NewStringSet(revDst)
 		mpRevDst.Add(destID)
 		// for ReverseDestination we will use Groups
-		Cache.SetWithoutReplicate(utils.CacheReverseDestinations, p, mpRevDst.AsSlice(), nil,
+		iDB.cacheSet(utils.CacheReverseDestinations, p, mpRevDst.AsSlice(), nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -309,13 +349,13 @@ func (iDB *InternalDB) GetActionsDrv(id string) (acts Actions, err error) {
 }
 
 func (iDB *InternalDB) SetActionsDrv(id string, acts Actions) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheActions, id, acts, nil,
+	iDB.cacheSet(utils.CacheActions, id, acts, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveActionsDrv(id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheActions, id,
+	iDB.cacheRemove(utils.CacheActions, id,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -328,13 +368,13 @@ func (iDB *InternalDB) GetSharedGroupDrv(id string) (sh *SharedGroup, err error)
 }
 
 func (iDB *InternalDB) SetSharedGroupDrv(sh *SharedGroup) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheSharedGroups, sh.Id, sh, nil,
+	iDB.cacheSet(utils.CacheSharedGroups, sh.Id, sh, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveSharedGroupDrv(id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheSharedGroups, id,
+	iDB.cacheRemove(utils.CacheSharedGroups, id,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -347,13 +387,13 @@ func (iDB *InternalDB) GetActionTriggersDrv(id string) (at ActionTriggers, err e
 }
 
 func (iDB *InternalDB) SetActionTriggersDrv(id string, at ActionTriggers) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheActionTriggers, id, at, nil,
+	iDB.cacheSet(utils.CacheActionTriggers, id, at, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveActionTriggersDrv(id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheActionTriggers, id,
+	iDB.cacheRemove(utils.CacheActionTriggers, id,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -370,7 +410,7 @@ func (iDB *InternalDB) SetActionPlanDrv(key string, ats *ActionPlan,
 	overwrite bool, transactionID string) (err error) {
 	cCommit := cacheCommit(transactionID)
 	if len(ats.ActionTimings) == 0 {
-		Cache.RemoveWithoutReplicate(utils.CacheActionPlans, key,
+		iDB.cacheRemove(utils.CacheActionPlans, key,
 			cCommit, transactionID)
 		return
 	}
@@ -386,13 +426,13 @@ func (iDB *InternalDB) SetActionPlanDrv(key string, ats *ActionPlan,
 			}
 		}
 	}
-	Cache.SetWithoutReplicate(utils.CacheActionPlans, key, ats, nil,
+	iDB.cacheSet(utils.CacheActionPlans, key, ats, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveActionPlanDrv(key string, transactionID string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheActionPlans, key, cacheCommit(transactionID), transactionID)
+	iDB.cacheRemove(utils.CacheActionPlans, key, cacheCommit(transactionID), transactionID)
 	return
 }
 
@@ -436,14 +476,14 @@ func (iDB *InternalDB) SetAccountActionPlansDrv(acntID string, apIDs []string, o
 			}
 		}
 	}
-	Cache.SetWithoutReplicate(utils.CacheAccountActionPlans, acntID, apIDs, nil,
+	iDB.cacheSet(utils.CacheAccountActionPlans, acntID, apIDs, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemAccountActionPlansDrv(acntID string, apIDs []string) (err error) {
 	if len(apIDs) == 0 {
-		Cache.RemoveWithoutReplicate(utils.CacheAccountActionPlans, acntID,
+		iDB.cacheRemove(utils.CacheAccountActionPlans, acntID,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 		return
 	}
@@ -460,11 +500,11 @@ func (iDB *InternalDB) RemAccountActionPlansDrv(acntID string, apIDs []string) (
 		i++
 	}
 	if len(oldaPlIDs) == 0 {
-		Cache.RemoveWithoutReplicate(utils.CacheAccountActionPlans, acntID,
+		iDB.cacheRemove(utils.CacheAccountActionPlans, acntID,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 		return
 	}
-	Cache.SetWithoutReplicate(utils.CacheAccountActionPlans, acntID, oldaPlIDs, nil,
+	iDB.cacheSet(utils.CacheAccountActionPlans, acntID, oldaPlIDs, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -510,13 +550,13 @@ func (iDB *InternalDB) SetAccountDrv(acc *Account) (err error) {
 		}
 	}
 	acc.UpdateTime = time.Now()
-	Cache.SetWithoutReplicate(utils.CacheAccounts, acc.ID, acc, nil,
+	iDB.cacheSet(utils.CacheAccounts, acc.ID, acc, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveAccountDrv(id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheAccounts, id,
+	iDB.cacheRemove(utils.CacheAccounts, id,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -529,13 +569,13 @@ func (iDB *InternalDB) GetResourceProfileDrv(tenant, id string) (rp *ResourcePro
 }
 
 func (iDB *InternalDB) SetResourceProfileDrv(rp *ResourceProfile) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheResourceProfiles, rp.TenantID(), rp, nil,
+	iDB.cacheSet(utils.CacheResourceProfiles, rp.TenantID(), rp, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveResourceProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheResourceProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheResourceProfiles, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -548,13 +588,13 @@ func (iDB *InternalDB) GetResourceDrv(tenant, id string) (r *Resource, err error
 }
 
 func (iDB *InternalDB) SetResourceDrv(r *Resource) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheResources, r.TenantID(), r, nil,
+	iDB.cacheSet(utils.CacheResources, r.TenantID(), r, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveResourceDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheResources, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheResources, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -568,13 +608,13 @@ func (iDB *InternalDB) GetTimingDrv(id string) (tmg *utils.TPTiming, err error)
 }
 
 func (iDB *InternalDB) SetTimingDrv(timing *utils.TPTiming) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheTimings, timing.ID, timing, nil,
+	iDB.cacheSet(utils.CacheTimings, timing.ID, timing, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveTimingDrv(id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheTimings, id,
+	iDB.cacheRemove(utils.CacheTimings, id,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -596,13 +636,13 @@ func (iDB *InternalDB) GetStatQueueProfileDrv(tenant string, id string) (sq *Sta
 
 }
 func (iDB *InternalDB) SetStatQueueProfileDrv(sq *StatQueueProfile) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheStatQueueProfiles, sq.TenantID(), sq, nil,
+	iDB.cacheSet(utils.CacheStatQueueProfiles, sq.TenantID(), sq, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemStatQueueProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheStatQueueProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheStatQueueProfiles, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -621,12 +661,12 @@ func (iDB *InternalDB) SetStatQueueDrv(ssq *StoredStatQueue, sq *StatQueue) (err
 			return
 		}
 	}
-	Cache.SetWithoutReplicate(utils.CacheStatQueues, utils.ConcatenatedKey(sq.Tenant, sq.ID), sq, nil,
+	iDB.cacheSet(utils.CacheStatQueues, utils.ConcatenatedKey(sq.Tenant, sq.ID), sq, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 func (iDB *InternalDB) RemStatQueueDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheStatQueues, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheStatQueues, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -640,13 +680,13 @@ func (iDB *InternalDB) GetThresholdProfileDrv(tenant, id string) (tp *ThresholdP
 }
 
 func (iDB *InternalDB) SetThresholdProfileDrv(tp *ThresholdProfile) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheThresholdProfiles, tp.TenantID(), tp, nil,
+	iDB.cacheSet(utils.CacheThresholdProfiles, tp.TenantID(), tp, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemThresholdProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheThresholdProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheThresholdProfiles, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -660,13 +700,13 @@ func (iDB *InternalDB) GetThresholdDrv(tenant, id string) (th *Threshold, err er
 }
 
 func (iDB *InternalDB) SetThresholdDrv(th *Threshold) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheThresholds, th.TenantID(), th, nil,
+	iDB.cacheSet(utils.CacheThresholds, th.TenantID(), th, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveThresholdDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheThresholds, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheThresholds, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -684,13 +724,13 @@ func (iDB *InternalDB) SetFilterDrv(fltr *Filter) (err error) {
 	if err = fltr.Compile(); err != nil {
 		return
 	}
-	Cache.SetWithoutReplicate(utils.CacheFilters, fltr.TenantID(), fltr, nil,
+	iDB.cacheSet(utils.CacheFilters, fltr.TenantID(), fltr, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveFilterDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheFilters, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheFilters, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -707,13 +747,13 @@ func (iDB *InternalDB) SetRouteProfileDrv(spp *RouteProfile) (err error) {
 	if err = spp.Compile(); err != nil {
 		return
 	}
-	Cache.SetWithoutReplicate(utils.CacheRouteProfiles, spp.TenantID(), spp, nil,
+	iDB.cacheSet(utils.CacheRouteProfiles, spp.TenantID(), spp, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveRouteProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheRouteProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheRouteProfiles, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -730,13 +770,13 @@ func (iDB *InternalDB) SetAttributeProfileDrv(attr *AttributeProfile) (err error
 	if err = attr.Compile(); err != nil {
 		return
 	}
-	Cache.SetWithoutReplicate(utils.CacheAttributeProfiles, attr.TenantID(), attr, nil,
+	iDB.cacheSet(utils.CacheAttributeProfiles, attr.TenantID(), attr, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveAttributeProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheAttributeProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheAttributeProfiles, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -750,13 +790,13 @@ func (iDB *InternalDB) GetChargerProfileDrv(tenant, id string) (ch *ChargerProfi
 }
 
 func (iDB *InternalDB) SetChargerProfileDrv(chr *ChargerProfile) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheChargerProfiles, chr.TenantID(), chr, nil,
+	iDB.cacheSet(utils.CacheChargerProfiles, chr.TenantID(), chr, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveChargerProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheChargerProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheChargerProfiles, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -770,13 +810,13 @@ func (iDB *InternalDB) GetDispatcherProfileDrv(tenant, id string) (dpp *Dispatch
 }
 
 func (iDB *InternalDB) SetDispatcherProfileDrv(dpp *DispatcherProfile) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheDispatcherProfiles, dpp.TenantID(), dpp, nil,
+	iDB.cacheSet(utils.CacheDispatcherProfiles, dpp.TenantID(), dpp, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveDispatcherProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheDispatcherProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheDispatcherProfiles, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -794,7 +834,7 @@ func (iDB *InternalDB) GetItemLoadIDsDrv(itemIDPrefix string) (loadIDs map[strin
 }
 
 func (iDB *InternalDB) SetLoadIDsDrv(loadIDs map[string]int64) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheLoadIDs, utils.LoadIDs, loadIDs, nil,
+	iDB.cacheSet(utils.CacheLoadIDs, utils.LoadIDs, loadIDs, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -808,13 +848,33 @@ func (iDB *InternalDB) GetDispatcherHostDrv(tenant, id string) (dpp *DispatcherH
 }
 
 func (iDB *InternalDB) SetDispatcherHostDrv(dpp *DispatcherHost) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheDispatcherHosts, dpp.TenantID(), dpp, nil,
+	iDB.cacheSet(utils.CacheDispatcherHosts, dpp.TenantID(), dpp, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveDispatcherHostDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheDispatcherHosts, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheDispatcherHosts, utils.ConcatenatedKey(tenant, id),
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
+
+func (iDB *InternalDB) GetPortedNumberDrv(tenant, id string) (pn *PortedNumber, err error) {
+	x, ok := Cache.Get(utils.CachePortedNumbers, utils.ConcatenatedKey(tenant, id))
+	if !ok || x == nil {
+		return nil, utils.ErrNotFound
+	}
+	return x.(*PortedNumber), nil
+}
+
+func (iDB *InternalDB) SetPortedNumberDrv(pn *PortedNumber) (err error) {
+	iDB.cacheSet(utils.CachePortedNumbers, pn.TenantID(), pn, nil,
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
+
+func (iDB *InternalDB) RemovePortedNumberDrv(tenant, id string) (err error) {
+	iDB.cacheRemove(utils.CachePortedNumbers, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -831,13 +891,13 @@ func (iDB *InternalDB) SetRateProfileDrv(rpp *RateProfile) (err error) {
 	if err = rpp.Compile(); err != nil {
 		return
 	}
-	Cache.SetWithoutReplicate(utils.CacheRateProfiles, rpp.TenantID(), rpp, nil,
+	iDB.cacheSet(utils.CacheRateProfiles, rpp.TenantID(), rpp, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveRateProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheRateProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheRateProfiles, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -851,13 +911,13 @@ func (iDB *InternalDB) GetActionProfileDrv(tenant, id string) (ap *ActionProfile
 }
 
 func (iDB *InternalDB) SetActionProfileDrv(ap *ActionProfile) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheActionProfiles, ap.TenantID(), ap, nil,
+	iDB.cacheSet(utils.CacheActionProfiles, ap.TenantID(), ap, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveActionProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheActionProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheActionProfiles, utils.ConcatenatedKey(tenant, id),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -903,10 +963,10 @@ func (iDB *InternalDB) SetIndexesDrv(idxItmType, tntCtx string,
 			if !ok || x == nil {
 				continue
 			}
-			Cache.RemoveWithoutReplicate(idxItmType, dbKey,
+			iDB.cacheRemove(idxItmType, dbKey,
 				cacheCommit(utils.NonTransactional), utils.NonTransactional)
 			key := strings.TrimSuffix(strings.TrimPrefix(dbKey, "tmp_"), utils.ConcatenatedKeySep+transactionID)
-			Cache.SetWithoutReplicate(idxItmType, key, x, []string{tntCtx},
+			iDB.cacheSet(idxItmType, key, x, []string{tntCtx},
 				cacheCommit(utils.NonTransactional), utils.NonTransactional)
 		}
 		return
@@ -917,11 +977,11 @@ func (iDB *InternalDB) SetIndexesDrv(idxItmType, tntCtx string,
 			dbKey = "tmp_" + utils.ConcatenatedKey(dbKey, transactionID)
 		}
 		if len(indx) == 0 {
-			Cache.SetWithoutReplicate(idxItmType, dbKey, nil, []string{tntCtx},
+			iDB.cacheSet(idxItmType, dbKey, nil, []string{tntCtx},
 				cacheCommit(utils.NonTransactional), utils.NonTransactional)
 			continue
 		}
-		Cache.SetWithoutReplicate(idxItmType, dbKey, indx, []string{tntCtx},
+		iDB.cacheSet(idxItmType, dbKey, indx, []string{tntCtx},
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -929,10 +989,10 @@ func (iDB *InternalDB) SetIndexesDrv(idxItmType, tntCtx string,
 
 func (iDB *InternalDB) RemoveIndexesDrv(idxItmType, tntCtx, idxKey string) (err error) {
 	if idxKey == utils.EmptyString {
-		Cache.tCache.RemoveGroup(idxItmType, tntCtx, true, utils.EmptyString)
+		iDB.cacheRemoveGroup(idxItmType, tntCtx, true, utils.EmptyString)
 		return
 	}
-	Cache.RemoveWithoutReplicate(idxItmType, utils.ConcatenatedKey(tntCtx, idxKey), cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	iDB.cacheRemove(idxItmType, utils.ConcatenatedKey(tntCtx, idxKey), cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
@@ -945,13 +1005,155 @@ func (iDB *InternalDB) GetAccountProfileDrv(tenant, id string) (ap *utils.Accoun
 }
 
 func (iDB *InternalDB) SetAccountProfileDrv(ap *utils.AccountProfile) (err error) {
-	Cache.SetWithoutReplicate(utils.CacheAccountProfiles, ap.TenantID(), ap, nil,
+	iDB.cacheSet(utils.CacheAccountProfiles, ap.TenantID(), ap, nil,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
 
 func (iDB *InternalDB) RemoveAccountProfileDrv(tenant, id string) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheAccountProfiles, utils.ConcatenatedKey(tenant, id),
+	iDB.cacheRemove(utils.CacheAccountProfiles, utils.ConcatenatedKey(tenant, id),
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
+
+// SetDedupIDDrv stores the ID for ttl, returning utils.ErrExists if it is already stored
+func (iDB *InternalDB) SetDedupIDDrv(id string, ttl time.Duration) (err error) {
+	now := time.Now()
+	iDB.dedupMux.Lock()
+	defer iDB.dedupMux.Unlock()
+	if now.After(iDB.dedupSweep) { // remove the expired IDs at most once per ttl
+		for dedupID, expiry := range iDB.dedupIDs {
+			if !now.Before(expiry) {
+				delete(iDB.dedupIDs, dedupID)
+			}
+		}
+		iDB.dedupSweep = now.Add(ttl)
+	}
+	if expiry, has := iDB.dedupIDs[id]; has && now.Before(expiry) {
+		return utils.ErrExists
+	}
+	iDB.dedupIDs[id] = now.Add(ttl)
+	return
+}
+
+func (iDB *InternalDB) RemoveDedupIDDrv(id string) (err error) {
+	iDB.dedupMux.Lock()
+	delete(iDB.dedupIDs, id)
+	iDB.dedupMux.Unlock()
+	return
+}
+
+// getSharedSession returns a copy of the session stored in the Cache
+func (iDB *InternalDB) getSharedSession(cgrID string) (ss *SharedSession, has bool) {
+	x, ok := Cache.Get(utils.CacheSharedSessions, cgrID)
+	if !ok || x == nil {
+		return
+	}
+	cln := *x.(*SharedSession)
+	return &cln, true
+}
+
+func (iDB *InternalDB) GetSharedSessionsDrv() (sss []*SharedSession, err error) {
+	iDB.sharedSessionsMux.Lock()
+	cgrIDs := Cache.GetItemIDs(utils.CacheSharedSessions, utils.EmptyString)
+	sss = make([]*SharedSession, 0, len(cgrIDs))
+	for _, cgrID := range cgrIDs {
+		if ss, has := iDB.getSharedSession(cgrID); has {
+			sss = append(sss, ss)
+		}
+	}
+	iDB.sharedSessionsMux.Unlock()
+	return
+}
+
+// SetSharedSessionDrv stores the session, returning utils.ErrExists if owned by another node with an active lease
+func (iDB *InternalDB) SetSharedSessionDrv(ss *SharedSession) (err error) {
+	iDB.sharedSessionsMux.Lock()
+	defer iDB.sharedSessionsMux.Unlock()
+	if prev, has := iDB.getSharedSession(ss.CGRID); has &&
+		prev.NodeID != ss.NodeID && !prev.expired(time.Now()) {
+		return utils.ErrExists
+	}
+	cln := *ss
+	iDB.cacheSet(utils.CacheSharedSessions, ss.CGRID, &cln, nil,
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
+
+// ClaimSharedSessionDrv takes the ownership of an expired session, returning utils.ErrNotFound otherwise
+func (iDB *InternalDB) ClaimSharedSessionDrv(cgrID, nodeID string, expiry time.Time) (ss *SharedSession, err error) {
+	iDB.sharedSessionsMux.Lock()
+	defer iDB.sharedSessionsMux.Unlock()
+	prev, has := iDB.getSharedSession(cgrID)
+	if !has || !prev.expired(time.Now()) {
+		return nil, utils.ErrNotFound
+	}
+	prev.NodeID = nodeID
+	prev.Expiry = expiry
+	iDB.cacheSet(utils.CacheSharedSessions, cgrID, prev, nil,
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	cln := *prev
+	return &cln, nil
+}
+
+func (iDB *InternalDB) RemoveSharedSessionDrv(cgrID, nodeID string) (err error) {
+	iDB.sharedSessionsMux.Lock()
+	if ss, has := iDB.getSharedSession(cgrID); has && ss.NodeID == nodeID {
+		iDB.cacheRemove(utils.CacheSharedSessions, cgrID,
+			cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	}
+	iDB.sharedSessionsMux.Unlock()
+	return
+}
+
+func (iDB *InternalDB) GetConfigSectionsDrv() (cfgSects []*ConfigSection, err error) {
+	for _, section := range Cache.GetItemIDs(utils.CacheConfigSections, utils.EmptyString) {
+		var cfgSect *ConfigSection
+		if cfgSect, err = iDB.GetConfigSectionDrv(section); err != nil {
+			if err == utils.ErrNotFound { // removed in the meantime
+				err = nil
+				continue
+			}
+			return
+		}
+		cfgSects = append(cfgSects, cfgSect)
+	}
+	return
+}
+
+func (iDB *InternalDB) GetConfigSectionDrv(section string) (cfgSect *ConfigSection, err error) {
+	x, ok := Cache.Get(utils.CacheConfigSections, section)
+	if !ok || x == nil {
+		return nil, utils.ErrNotFound
+	}
+	cln := *x.(*ConfigSection)
+	return &cln, nil
+}
+
+// SetConfigSectionDrv stores the section if the stored version is the previous one, returning utils.ErrExists otherwise
+func (iDB *InternalDB) SetConfigSectionDrv(cfgSect *ConfigSection) (err error) {
+	iDB.configSectionsMux.Lock()
+	defer iDB.configSectionsMux.Unlock()
+	var prevVersion int64
+	if x, ok := Cache.Get(utils.CacheConfigSections, cfgSect.Section); ok && x != nil {
+		prevVersion = x.(*ConfigSection).Version
+	}
+	if prevVersion != cfgSect.Version-1 {
+		return utils.ErrExists
+	}
+	cln := *cfgSect
+	iDB.cacheSet(utils.CacheConfigSections, cfgSect.Section, &cln, nil,
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
+
+func (iDB *InternalDB) RemoveConfigSectionDrv(section string) (err error) {
+	iDB.configSectionsMux.Lock()
+	defer iDB.configSectionsMux.Unlock()
+	if _, has := Cache.Get(utils.CacheConfigSections, section); !has {
+		return utils.ErrNotFound
+	}
+	iDB.cacheRemove(utils.CacheConfigSections, section,
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
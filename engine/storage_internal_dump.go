@@ -0,0 +1,421 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// operations persisted for the InternalDB
+const (
+	dumpOpSet         = "set"
+	dumpOpRemove      = "remove"
+	dumpOpRemoveGroup = "remove_group"
+	dumpOpClear       = "clear"
+)
+
+// dumpRecord is one write operation of the InternalDB
+// for dumpOpRemoveGroup the ItemID is the group ID
+// for dumpOpClear the CacheIDs are the cleared partitions(all if empty)
+type dumpRecord struct {
+	Op       string
+	CacheID  string
+	ItemID   string
+	GroupIDs []string
+	CacheIDs []string
+	Value    interface{}
+}
+
+// dumpState is the content of the InternalDB built out of the dumpRecords
+type dumpState map[string]map[string]*dumpRecord
+
+// apply updates the state with the record
+func (ds dumpState) apply(rcd *dumpRecord) {
+	switch rcd.Op {
+	case dumpOpSet:
+		if _, has := ds[rcd.CacheID]; !has {
+			ds[rcd.CacheID] = make(map[string]*dumpRecord)
+		}
+		ds[rcd.CacheID][rcd.ItemID] = rcd
+	case dumpOpRemove:
+		delete(ds[rcd.CacheID], rcd.ItemID)
+	case dumpOpRemoveGroup:
+		for itmID, itm := range ds[rcd.CacheID] {
+			if utils.IsSliceMember(itm.GroupIDs, rcd.ItemID) {
+				delete(ds[rcd.CacheID], itmID)
+			}
+		}
+	case dumpOpClear:
+		if len(rcd.CacheIDs) == 0 {
+			for chID := range ds {
+				delete(ds, chID)
+			}
+			return
+		}
+		for _, chID := range rcd.CacheIDs {
+			delete(ds, chID)
+		}
+	}
+}
+
+// newInternalDump opens the snapshot and the write-ahead log of the InternalDB found in dumpPath
+// the previous content is compacted into a new snapshot so the log starts empty
+func newInternalDump(dumpPath, dbName, fsync string,
+	fsyncInterval, snapshotInterval time.Duration) (iDump *internalDump, err error) {
+	switch fsync {
+	case utils.MetaAlways, utils.MetaInterval, utils.MetaNone:
+	default:
+		return nil, fmt.Errorf("unsupported %s: <%s>", utils.InternalFsyncCfg, fsync)
+	}
+	if err = os.MkdirAll(dumpPath, 0755); err != nil {
+		return
+	}
+	iDump = &internalDump{
+		snapshotPath:     path.Join(dumpPath, dbName+utils.SnapshotSuffix),
+		walPath:          path.Join(dumpPath, dbName+utils.WALSuffix),
+		fsync:            fsync,
+		fsyncInterval:    fsyncInterval,
+		snapshotInterval: snapshotInterval,
+		stopChan:         make(chan struct{}),
+	}
+	if err = iDump.snapshot(); err != nil {
+		return nil, err
+	}
+	if fsync == utils.MetaInterval && fsyncInterval > 0 {
+		go iDump.syncLoop()
+	}
+	if snapshotInterval > 0 {
+		go iDump.snapshotLoop()
+	}
+	return
+}
+
+// internalDump persists the InternalDB on disk as a snapshot and a write-ahead log
+type internalDump struct {
+	sync.Mutex
+	snapshotPath     string
+	walPath          string
+	fsync            string
+	fsyncInterval    time.Duration
+	snapshotInterval time.Duration
+
+	wal      *os.File
+	walBuf   bytes.Buffer
+	dirty    bool // the log has writes not synced on disk
+	stopChan chan struct{}
+	stopped  bool
+}
+
+// write appends the record to the write-ahead log
+func (iDump *internalDump) write(rcd *dumpRecord) (err error) {
+	iDump.Lock()
+	defer iDump.Unlock()
+	if iDump.wal == nil {
+		return
+	}
+	if err = writeDumpRecord(iDump.wal, &iDump.walBuf, rcd); err != nil {
+		return
+	}
+	if iDump.fsync == utils.MetaAlways {
+		return iDump.wal.Sync()
+	}
+	iDump.dirty = true
+	return
+}
+
+// snapshot compacts the previous snapshot and the write-ahead log into a new snapshot
+// and starts a new empty log
+func (iDump *internalDump) snapshot() (err error) {
+	iDump.Lock()
+	defer iDump.Unlock()
+	if iDump.stopped {
+		return
+	}
+	var ds dumpState
+	if ds, err = iDump.readState(); err != nil {
+		return
+	}
+	tmpPath := iDump.snapshotPath + utils.TmpSuffix
+	var f *os.File
+	if f, err = os.Create(tmpPath); err != nil {
+		return
+	}
+	w := bufio.NewWriter(f)
+	var buf bytes.Buffer
+	for _, itms := range ds {
+		for _, rcd := range itms {
+			if err = writeDumpRecord(w, &buf, rcd); err != nil {
+				f.Close()
+				return
+			}
+		}
+	}
+	if err = w.Flush(); err != nil {
+		f.Close()
+		return
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+	if err = os.Rename(tmpPath, iDump.snapshotPath); err != nil {
+		return
+	}
+	if iDump.wal != nil {
+		iDump.wal.Close()
+	}
+	iDump.wal, err = os.OpenFile(iDump.walPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	iDump.dirty = false
+	return
+}
+
+// restore loads the persisted content in the Cache
+func (iDump *internalDump) restore() (err error) {
+	iDump.Lock()
+	defer iDump.Unlock()
+	var ds dumpState
+	if ds, err = iDump.readState(); err != nil {
+		return
+	}
+	for chID, itms := range ds {
+		for itmID, rcd := range itms {
+			Cache.SetWithoutReplicate(chID, itmID, rcd.Value, rcd.GroupIDs,
+				cacheCommit(utils.NonTransactional), utils.NonTransactional)
+		}
+	}
+	return
+}
+
+// readState builds the state out of the snapshot and the write-ahead log
+// should be called under lock
+func (iDump *internalDump) readState() (ds dumpState, err error) {
+	ds = make(dumpState)
+	if iDump.wal != nil {
+		if err = iDump.wal.Sync(); err != nil {
+			return
+		}
+	}
+	for _, fPath := range []string{iDump.snapshotPath, iDump.walPath} {
+		if err = readDumpFile(fPath, ds); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// syncLoop flushes periodically the write-ahead log on disk
+func (iDump *internalDump) syncLoop() {
+	for {
+		select {
+		case <-iDump.stopChan:
+			return
+		case <-time.After(iDump.fsyncInterval):
+			iDump.Lock()
+			if iDump.dirty && iDump.wal != nil {
+				if err := iDump.wal.Sync(); err != nil {
+					utils.Logger.Warning(fmt.Sprintf("<%s> failed syncing <%s>, error: <%s>",
+						utils.InternalDB, iDump.walPath, err.Error()))
+				}
+				iDump.dirty = false
+			}
+			iDump.Unlock()
+		}
+	}
+}
+
+// snapshotLoop takes periodically a new snapshot
+func (iDump *internalDump) snapshotLoop() {
+	for {
+		select {
+		case <-iDump.stopChan:
+			return
+		case <-time.After(iDump.snapshotInterval):
+			if err := iDump.snapshot(); err != nil {
+				utils.Logger.Warning(fmt.Sprintf("<%s> failed writing the snapshot <%s>, error: <%s>",
+					utils.InternalDB, iDump.snapshotPath, err.Error()))
+			}
+		}
+	}
+}
+
+// close syncs the write-ahead log and stops the dump
+func (iDump *internalDump) close() (err error) {
+	iDump.Lock()
+	defer iDump.Unlock()
+	if iDump.stopped {
+		return
+	}
+	iDump.stopped = true
+	close(iDump.stopChan)
+	if iDump.wal == nil {
+		return
+	}
+	if err = iDump.wal.Sync(); err != nil {
+		iDump.wal.Close()
+	} else {
+		err = iDump.wal.Close()
+	}
+	iDump.wal = nil
+	return
+}
+
+// writeDumpRecord writes the gob encoded record prefixed by its length
+// each record is encoded on its own so the files can be appended
+func writeDumpRecord(w io.Writer, buf *bytes.Buffer, rcd *dumpRecord) (err error) {
+	buf.Reset()
+	buf.Write(make([]byte, 4)) // room for the length
+	if err = gob.NewEncoder(buf).Encode(rcd); err != nil {
+		return
+	}
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b, uint32(len(b)-4))
+	_, err = w.Write(b)
+	return
+}
+
+// readDumpFile applies the records found in the file to the state
+// a truncated record at the end of the file(partial write) is ignored
+func readDumpFile(fPath string, ds dumpState) (err error) {
+	var f *os.File
+	if f, err = os.Open(fPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	lenBuf := make([]byte, 4)
+	for {
+		if _, err = io.ReadFull(r, lenBuf); err != nil {
+			break
+		}
+		rcdBytes := make([]byte, binary.BigEndian.Uint32(lenBuf))
+		if _, err = io.ReadFull(r, rcdBytes); err != nil {
+			break
+		}
+		rcd := new(dumpRecord)
+		if err = gob.NewDecoder(bytes.NewReader(rcdBytes)).Decode(rcd); err != nil {
+			return fmt.Errorf("corrupted record in <%s>, error: <%s>", fPath, err.Error())
+		}
+		ds.apply(rcd)
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+	return
+}
+
+// SnapshotInternalDB writes a new snapshot for the persisted InternalDBs used as DataDB and StorDB
+func SnapshotInternalDB() (err error) {
+	iDBs := persistedInternalDBs()
+	if len(iDBs) == 0 {
+		return utils.ErrNotFound
+	}
+	for _, iDB := range iDBs {
+		if err = iDB.dump.snapshot(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// RestoreInternalDB loads the content of the persisted InternalDBs in the Cache
+// should be called after the Cache used by the engine is set
+func RestoreInternalDB() (err error) {
+	for _, iDB := range persistedInternalDBs() {
+		if err = iDB.dump.restore(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// persistedInternalDBs returns the DataDB and the StorDB if they are persisted InternalDBs
+func persistedInternalDBs() (iDBs []*InternalDB) {
+	var dbs []interface{}
+	if dm != nil {
+		dbs = append(dbs, dm.DataDB())
+	}
+	if cdrStorage != nil {
+		dbs = append(dbs, cdrStorage)
+	}
+	for _, db := range dbs {
+		if iDB, canCast := db.(*InternalDB); canCast && iDB.dump != nil &&
+			(len(iDBs) == 0 || iDBs[0] != iDB) {
+			iDBs = append(iDBs, iDB)
+		}
+	}
+	return
+}
+
+// cacheSet stores the item in the Cache and persists the write
+func (iDB *InternalDB) cacheSet(chID, itmID string, value interface{},
+	groupIDs []string, commit bool, transID string) {
+	Cache.SetWithoutReplicate(chID, itmID, value, groupIDs, commit, transID)
+	iDB.persist(&dumpRecord{Op: dumpOpSet, CacheID: chID, ItemID: itmID,
+		GroupIDs: groupIDs, Value: value})
+}
+
+// cacheRemove removes the item from the Cache and persists the removal
+func (iDB *InternalDB) cacheRemove(chID, itmID string, commit bool, transID string) {
+	Cache.RemoveWithoutReplicate(chID, itmID, commit, transID)
+	iDB.persist(&dumpRecord{Op: dumpOpRemove, CacheID: chID, ItemID: itmID})
+}
+
+// cacheRemoveGroup removes the group from the Cache and persists the removal
+func (iDB *InternalDB) cacheRemoveGroup(chID, grpID string, commit bool, transID string) {
+	Cache.tCache.RemoveGroup(chID, grpID, commit, transID)
+	iDB.persist(&dumpRecord{Op: dumpOpRemoveGroup, CacheID: chID, ItemID: grpID})
+}
+
+// cacheClear clears the partitions from the Cache and persists the operation
+func (iDB *InternalDB) cacheClear(chIDs []string) (err error) {
+	Cache.Clear(chIDs)
+	if iDB.dump == nil {
+		return
+	}
+	return iDB.dump.write(&dumpRecord{Op: dumpOpClear, CacheIDs: chIDs})
+}
+
+// persist writes the record in the dump if enabled
+func (iDB *InternalDB) persist(rcd *dumpRecord) {
+	if iDB.dump == nil {
+		return
+	}
+	if err := iDB.dump.write(rcd); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> failed persisting the %s on <%s> for item <%s>, error: <%s>",
+			utils.InternalDB, rcd.Op, rcd.CacheID, rcd.ItemID, err.Error()))
+	}
+}
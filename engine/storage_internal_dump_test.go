@@ -0,0 +1,256 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestInternalDumpRestore(t *testing.T) {
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	Cache = NewCacheS(config.CgrConfig(), nil, nil)
+	dumpPath := t.TempDir()
+	iDB, err := NewInternalDBWithDump(nil, nil, true, dumpPath, utils.MetaAlways, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := &Account{
+		ID: "cgrates.org:1001",
+		BalanceMap: map[string]Balances{
+			utils.MetaMonetary: {{ID: "b1", Value: 10}},
+		},
+	}
+	if err = iDB.SetAccountDrv(acc); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := NewStatSum(1, "~*req.Cost", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sq := &StatQueue{
+		Tenant:    "cgrates.org",
+		ID:        "SQ1",
+		SQItems:   []SQItem{{EventID: "ev1"}},
+		SQMetrics: map[string]StatMetric{utils.MetaSum + utils.HashtagSep + "~*req.Cost": sum},
+	}
+	if err = iDB.SetStatQueueDrv(nil, sq); err != nil {
+		t.Fatal(err)
+	}
+	th := &Threshold{Tenant: "cgrates.org", ID: "TH1", Hits: 2}
+	if err = iDB.SetThresholdDrv(th); err != nil {
+		t.Fatal(err)
+	}
+	if err = iDB.SetThresholdDrv(&Threshold{Tenant: "cgrates.org", ID: "TH2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = iDB.RemoveThresholdDrv("cgrates.org", "TH2"); err != nil {
+		t.Fatal(err)
+	}
+	if err = iDB.SetIndexesDrv(utils.CacheThresholdFilterIndexes, "cgrates.org",
+		map[string]utils.StringSet{"*string:*req.Account:1001": {"TH1": {}}},
+		true, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err = iDB.SetIndexesDrv(utils.CacheStatFilterIndexes, "cgrates.org",
+		map[string]utils.StringSet{"*string:*req.Account:1001": {"SQ1": {}}},
+		true, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err = iDB.RemoveIndexesDrv(utils.CacheStatFilterIndexes, "cgrates.org", utils.EmptyString); err != nil {
+		t.Fatal(err)
+	}
+	ss := &SharedSession{CGRID: "cgrID1", NodeID: "node1",
+		Expiry: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Session: []byte("session")}
+	if err = iDB.SetSharedSessionDrv(ss); err != nil {
+		t.Fatal(err)
+	}
+	cfgSect := &ConfigSection{Section: "general", Version: 1,
+		UpdatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Config: `{"node_id":"node1"}`}
+	if err = iDB.SetConfigSectionDrv(cfgSect); err != nil {
+		t.Fatal(err)
+	}
+	// snapshot in the middle so the restore uses both the snapshot and the log
+	if err = iDB.dump.snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if err = iDB.SetVersions(CurrentDataDBVersions(), true); err != nil {
+		t.Fatal(err)
+	}
+	iDB.Close()
+
+	Cache = NewCacheS(config.CgrConfig(), nil, nil)
+	if iDB, err = NewInternalDBWithDump(nil, nil, true, dumpPath, utils.MetaNone, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer iDB.Close()
+	if err = iDB.dump.restore(); err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := iDB.GetAccountDrv(acc.ID); err != nil {
+		t.Error(err)
+	} else if rcv.BalanceMap[utils.MetaMonetary][0].Value != 10 {
+		t.Errorf("Unexpected account: %s", utils.ToJSON(rcv))
+	}
+	if rcv, err := iDB.GetStatQueueDrv("cgrates.org", "SQ1"); err != nil {
+		t.Error(err)
+	} else if len(rcv.SQItems) != 1 || len(rcv.SQMetrics) != 1 {
+		t.Errorf("Unexpected StatQueue: %s", utils.ToJSON(rcv))
+	}
+	if rcv, err := iDB.GetThresholdDrv("cgrates.org", "TH1"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(th, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(th), utils.ToJSON(rcv))
+	}
+	if _, err := iDB.GetThresholdDrv("cgrates.org", "TH2"); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+	if rcv, err := iDB.GetIndexesDrv(utils.CacheThresholdFilterIndexes, "cgrates.org",
+		utils.EmptyString); err != nil {
+		t.Error(err)
+	} else if exp := map[string]utils.StringSet{"*string:*req.Account:1001": {"TH1": {}}}; !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(exp), utils.ToJSON(rcv))
+	}
+	if _, err := iDB.GetIndexesDrv(utils.CacheStatFilterIndexes, "cgrates.org",
+		utils.EmptyString); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+	if rcv, err := iDB.GetSharedSessionsDrv(); err != nil {
+		t.Error(err)
+	} else if exp := []*SharedSession{ss}; !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(exp), utils.ToJSON(rcv))
+	}
+	if rcv, err := iDB.GetConfigSectionDrv(cfgSect.Section); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(cfgSect, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(cfgSect), utils.ToJSON(rcv))
+	}
+	if rcv, err := iDB.GetVersions(utils.EmptyString); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(CurrentDataDBVersions(), rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(CurrentDataDBVersions()), utils.ToJSON(rcv))
+	}
+	if fi, err := os.Stat(path.Join(dumpPath, utils.DataDB+utils.WALSuffix)); err != nil {
+		t.Error(err)
+	} else if fi.Size() != 0 {
+		t.Errorf("Expected the log to be compacted at startup, size: %d", fi.Size())
+	}
+}
+
+func TestInternalDumpTruncatedLog(t *testing.T) {
+	dumpPath := t.TempDir()
+	iDump, err := newInternalDump(dumpPath, utils.StorDB, utils.MetaInterval, time.Millisecond, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = iDump.write(&dumpRecord{Op: dumpOpSet, CacheID: utils.CacheCDRsTBL,
+		ItemID: "cdr1", GroupIDs: []string{"grp1"}, Value: &CDR{CGRID: "cdr1"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err = iDump.write(&dumpRecord{Op: dumpOpSet, CacheID: utils.CacheCDRsTBL,
+		ItemID: "cdr2", GroupIDs: []string{"grp2"}, Value: &CDR{CGRID: "cdr2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err = iDump.close(); err != nil {
+		t.Fatal(err)
+	}
+	// simulate a partial write of the last record
+	walPath := path.Join(dumpPath, utils.StorDB+utils.WALSuffix)
+	fi, err := os.Stat(walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Truncate(walPath, fi.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+	ds := make(dumpState)
+	if err = readDumpFile(walPath, ds); err != nil {
+		t.Fatal(err)
+	}
+	if len(ds[utils.CacheCDRsTBL]) != 1 || ds[utils.CacheCDRsTBL]["cdr1"] == nil {
+		t.Errorf("Unexpected state: %s", utils.ToJSON(ds))
+	}
+	ds.apply(&dumpRecord{Op: dumpOpRemoveGroup, CacheID: utils.CacheCDRsTBL, ItemID: "grp1"})
+	if len(ds[utils.CacheCDRsTBL]) != 0 {
+		t.Errorf("Unexpected state: %s", utils.ToJSON(ds))
+	}
+}
+
+func TestInternalDumpUnsupportedFsync(t *testing.T) {
+	if _, err := newInternalDump(t.TempDir(), utils.DataDB, "*unsupported", 0, 0); err == nil ||
+		err.Error() != "unsupported internal_fsync: <*unsupported>" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestInternalDumpFlush(t *testing.T) {
+	tmpCache := Cache
+	defer func() { Cache = tmpCache }()
+	Cache = NewCacheS(config.CgrConfig(), nil, nil)
+	dumpPath := t.TempDir()
+	iDB, err := NewInternalDBWithDump(nil, nil, true, dumpPath, utils.MetaAlways, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = iDB.SetThresholdDrv(&Threshold{Tenant: "cgrates.org", ID: "TH1"}); err != nil {
+		t.Fatal(err)
+	}
+	Cache.SetWithoutReplicate(utils.CacheCDRsTBL, "cdr1", &CDR{CGRID: "cdr1"}, nil, true, utils.NonTransactional)
+	if err = iDB.Flush(utils.EmptyString); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = iDB.GetThresholdDrv("cgrates.org", "TH1"); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+	if _, has := Cache.Get(utils.CacheCDRsTBL, "cdr1"); !has {
+		t.Error("Expected the partitions outside DataDB to be kept")
+	}
+	if err = iDB.SetThresholdDrv(&Threshold{Tenant: "cgrates.org", ID: "TH2"}); err != nil {
+		t.Fatal(err)
+	}
+	// the log can no longer be written
+	iDB.dump.wal.Close()
+	if err = iDB.Flush(utils.EmptyString); err == nil {
+		t.Error("Expected the error of the log to be returned")
+	}
+	iDB.dump.wal = nil
+	iDB.Close()
+
+	Cache = NewCacheS(config.CgrConfig(), nil, nil)
+	if iDB, err = NewInternalDBWithDump(nil, nil, true, dumpPath, utils.MetaNone, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer iDB.Close()
+	if err = iDB.dump.restore(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = iDB.GetThresholdDrv("cgrates.org", "TH1"); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received %v", utils.ErrNotFound, err)
+	}
+	if _, err = iDB.GetThresholdDrv("cgrates.org", "TH2"); err != nil {
+		t.Error(err)
+	}
+}
@@ -654,7 +654,7 @@ func (iDB *InternalDB) RemTpData(table, tpid string, args map[string]string) (er
 	}
 	ids := Cache.GetItemIDs(utils.CacheStorDBPartitions[table], key)
 	for _, id := range ids {
-		Cache.RemoveWithoutReplicate(utils.CacheStorDBPartitions[table], id,
+		iDB.cacheRemove(utils.CacheStorDBPartitions[table], id,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -665,7 +665,7 @@ func (iDB *InternalDB) SetTPTimings(timings []*utils.ApierTPTiming) (err error)
 		return nil
 	}
 	for _, timing := range timings {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPTimings, utils.ConcatenatedKey(timing.TPid, timing.ID), timing, nil,
+		iDB.cacheSet(utils.CacheTBLTPTimings, utils.ConcatenatedKey(timing.TPid, timing.ID), timing, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -675,7 +675,7 @@ func (iDB *InternalDB) SetTPDestinations(dests []*utils.TPDestination) (err erro
 		return nil
 	}
 	for _, destination := range dests {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPDestinations, utils.ConcatenatedKey(destination.TPid, destination.ID), destination, nil,
+		iDB.cacheSet(utils.CacheTBLTPDestinations, utils.ConcatenatedKey(destination.TPid, destination.ID), destination, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -686,7 +686,7 @@ func (iDB *InternalDB) SetTPRates(rates []*utils.TPRateRALs) (err error) {
 		return nil
 	}
 	for _, rate := range rates {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPRates, utils.ConcatenatedKey(rate.TPid, rate.ID), rate, nil,
+		iDB.cacheSet(utils.CacheTBLTPRates, utils.ConcatenatedKey(rate.TPid, rate.ID), rate, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -697,7 +697,7 @@ func (iDB *InternalDB) SetTPDestinationRates(dRates []*utils.TPDestinationRate)
 		return nil
 	}
 	for _, dRate := range dRates {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPDestinationRates, utils.ConcatenatedKey(dRate.TPid, dRate.ID), dRate, nil,
+		iDB.cacheSet(utils.CacheTBLTPDestinationRates, utils.ConcatenatedKey(dRate.TPid, dRate.ID), dRate, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -708,7 +708,7 @@ func (iDB *InternalDB) SetTPRatingPlans(ratingPlans []*utils.TPRatingPlan) (err
 		return nil
 	}
 	for _, rPlan := range ratingPlans {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPRatingPlans, utils.ConcatenatedKey(rPlan.TPid, rPlan.ID), rPlan, nil,
+		iDB.cacheSet(utils.CacheTBLTPRatingPlans, utils.ConcatenatedKey(rPlan.TPid, rPlan.ID), rPlan, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -719,7 +719,7 @@ func (iDB *InternalDB) SetTPRatingProfiles(ratingProfiles []*utils.TPRatingProfi
 		return nil
 	}
 	for _, rProfile := range ratingProfiles {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPRatingProfiles, utils.ConcatenatedKey(rProfile.TPid,
+		iDB.cacheSet(utils.CacheTBLTPRatingProfiles, utils.ConcatenatedKey(rProfile.TPid,
 			rProfile.LoadId, rProfile.Tenant, rProfile.Category, rProfile.Subject), rProfile, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
@@ -731,7 +731,7 @@ func (iDB *InternalDB) SetTPSharedGroups(groups []*utils.TPSharedGroups) (err er
 		return nil
 	}
 	for _, group := range groups {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPSharedGroups, utils.ConcatenatedKey(group.TPid, group.ID), group, nil,
+		iDB.cacheSet(utils.CacheTBLTPSharedGroups, utils.ConcatenatedKey(group.TPid, group.ID), group, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -742,7 +742,7 @@ func (iDB *InternalDB) SetTPActions(acts []*utils.TPActions) (err error) {
 		return nil
 	}
 	for _, action := range acts {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPActions, utils.ConcatenatedKey(action.TPid, action.ID), action, nil,
+		iDB.cacheSet(utils.CacheTBLTPActions, utils.ConcatenatedKey(action.TPid, action.ID), action, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -753,7 +753,7 @@ func (iDB *InternalDB) SetTPActionPlans(aPlans []*utils.TPActionPlan) (err error
 		return nil
 	}
 	for _, aPlan := range aPlans {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPActionPlans, utils.ConcatenatedKey(aPlan.TPid, aPlan.ID), aPlan, nil,
+		iDB.cacheSet(utils.CacheTBLTPActionPlans, utils.ConcatenatedKey(aPlan.TPid, aPlan.ID), aPlan, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -764,7 +764,7 @@ func (iDB *InternalDB) SetTPActionTriggers(aTriggers []*utils.TPActionTriggers)
 		return nil
 	}
 	for _, aTrigger := range aTriggers {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPActionTriggers, utils.ConcatenatedKey(aTrigger.TPid, aTrigger.ID), aTrigger, nil,
+		iDB.cacheSet(utils.CacheTBLTPActionTriggers, utils.ConcatenatedKey(aTrigger.TPid, aTrigger.ID), aTrigger, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -775,7 +775,7 @@ func (iDB *InternalDB) SetTPAccountActions(accActions []*utils.TPAccountActions)
 		return nil
 	}
 	for _, accAction := range accActions {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPAccountActions, utils.ConcatenatedKey(accAction.TPid,
+		iDB.cacheSet(utils.CacheTBLTPAccountActions, utils.ConcatenatedKey(accAction.TPid,
 			accAction.LoadId, accAction.Tenant, accAction.Account), accAction, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
@@ -787,7 +787,7 @@ func (iDB *InternalDB) SetTPResources(resources []*utils.TPResourceProfile) (err
 		return nil
 	}
 	for _, resource := range resources {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPResources, utils.ConcatenatedKey(resource.TPid, resource.Tenant, resource.ID), resource, nil,
+		iDB.cacheSet(utils.CacheTBLTPResources, utils.ConcatenatedKey(resource.TPid, resource.Tenant, resource.ID), resource, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -797,7 +797,7 @@ func (iDB *InternalDB) SetTPStats(stats []*utils.TPStatProfile) (err error) {
 		return nil
 	}
 	for _, stat := range stats {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPStats, utils.ConcatenatedKey(stat.TPid, stat.Tenant, stat.ID), stat, nil,
+		iDB.cacheSet(utils.CacheTBLTPStats, utils.ConcatenatedKey(stat.TPid, stat.Tenant, stat.ID), stat, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -808,7 +808,7 @@ func (iDB *InternalDB) SetTPThresholds(thresholds []*utils.TPThresholdProfile) (
 	}
 
 	for _, threshold := range thresholds {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPThresholds, utils.ConcatenatedKey(threshold.TPid, threshold.Tenant, threshold.ID), threshold, nil,
+		iDB.cacheSet(utils.CacheTBLTPThresholds, utils.ConcatenatedKey(threshold.TPid, threshold.Tenant, threshold.ID), threshold, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -819,7 +819,7 @@ func (iDB *InternalDB) SetTPFilters(filters []*utils.TPFilterProfile) (err error
 	}
 
 	for _, filter := range filters {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPFilters, utils.ConcatenatedKey(filter.TPid, filter.Tenant, filter.ID), filter, nil,
+		iDB.cacheSet(utils.CacheTBLTPFilters, utils.ConcatenatedKey(filter.TPid, filter.Tenant, filter.ID), filter, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -830,7 +830,7 @@ func (iDB *InternalDB) SetTPRoutes(routes []*utils.TPRouteProfile) (err error) {
 		return nil
 	}
 	for _, route := range routes {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPRoutes, utils.ConcatenatedKey(route.TPid, route.Tenant, route.ID), route, nil,
+		iDB.cacheSet(utils.CacheTBLTPRoutes, utils.ConcatenatedKey(route.TPid, route.Tenant, route.ID), route, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -842,7 +842,7 @@ func (iDB *InternalDB) SetTPAttributes(attributes []*utils.TPAttributeProfile) (
 	}
 
 	for _, attribute := range attributes {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPAttributes, utils.ConcatenatedKey(attribute.TPid, attribute.Tenant, attribute.ID), attribute, nil,
+		iDB.cacheSet(utils.CacheTBLTPAttributes, utils.ConcatenatedKey(attribute.TPid, attribute.Tenant, attribute.ID), attribute, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -853,7 +853,7 @@ func (iDB *InternalDB) SetTPChargers(cpps []*utils.TPChargerProfile) (err error)
 	}
 
 	for _, cpp := range cpps {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPChargers, utils.ConcatenatedKey(cpp.TPid, cpp.Tenant, cpp.ID), cpp, nil,
+		iDB.cacheSet(utils.CacheTBLTPChargers, utils.ConcatenatedKey(cpp.TPid, cpp.Tenant, cpp.ID), cpp, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -864,7 +864,7 @@ func (iDB *InternalDB) SetTPDispatcherProfiles(dpps []*utils.TPDispatcherProfile
 	}
 
 	for _, dpp := range dpps {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPDispatchers, utils.ConcatenatedKey(dpp.TPid, dpp.Tenant, dpp.ID), dpp, nil,
+		iDB.cacheSet(utils.CacheTBLTPDispatchers, utils.ConcatenatedKey(dpp.TPid, dpp.Tenant, dpp.ID), dpp, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -874,7 +874,7 @@ func (iDB *InternalDB) SetTPDispatcherHosts(dpps []*utils.TPDispatcherHost) (err
 		return nil
 	}
 	for _, dpp := range dpps {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPDispatcherHosts, utils.ConcatenatedKey(dpp.TPid, dpp.Tenant, dpp.ID), dpp, nil,
+		iDB.cacheSet(utils.CacheTBLTPDispatcherHosts, utils.ConcatenatedKey(dpp.TPid, dpp.Tenant, dpp.ID), dpp, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -885,7 +885,7 @@ func (iDB *InternalDB) SetTPRateProfiles(tpPrfs []*utils.TPRateProfile) (err err
 		return nil
 	}
 	for _, tpPrf := range tpPrfs {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPRateProfiles, utils.ConcatenatedKey(tpPrf.TPid, tpPrf.Tenant, tpPrf.ID), tpPrf, nil,
+		iDB.cacheSet(utils.CacheTBLTPRateProfiles, utils.ConcatenatedKey(tpPrf.TPid, tpPrf.Tenant, tpPrf.ID), tpPrf, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -896,7 +896,7 @@ func (iDB *InternalDB) SetTPActionProfiles(tpPrfs []*utils.TPActionProfile) (err
 		return nil
 	}
 	for _, tpPrf := range tpPrfs {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPActionProfiles, utils.ConcatenatedKey(tpPrf.TPid, tpPrf.Tenant, tpPrf.ID), tpPrf, nil,
+		iDB.cacheSet(utils.CacheTBLTPActionProfiles, utils.ConcatenatedKey(tpPrf.TPid, tpPrf.Tenant, tpPrf.ID), tpPrf, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -907,7 +907,7 @@ func (iDB *InternalDB) SetTPAccountProfiles(tpPrfs []*utils.TPAccountProfile) (e
 		return nil
 	}
 	for _, tpPrf := range tpPrfs {
-		Cache.SetWithoutReplicate(utils.CacheTBLTPAccountProfiles, utils.ConcatenatedKey(tpPrf.TPid, tpPrf.Tenant, tpPrf.ID), tpPrf, nil,
+		iDB.cacheSet(utils.CacheTBLTPAccountProfiles, utils.ConcatenatedKey(tpPrf.TPid, tpPrf.Tenant, tpPrf.ID), tpPrf, nil,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return
@@ -957,14 +957,14 @@ func (iDB *InternalDB) SetCDR(cdr *CDR, allowUpdate bool) (err error) {
 	}
 	iDB.indexedFieldsMutex.RUnlock()
 
-	Cache.SetWithoutReplicate(utils.CacheCDRsTBL, cdrKey, cdr, idxs.AsSlice(),
+	iDB.cacheSet(utils.CacheCDRsTBL, cdrKey, cdr, idxs.AsSlice(),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 
 	return
 }
 
 func (iDB *InternalDB) RemoveSMCost(smc *SMCost) (err error) {
-	Cache.RemoveWithoutReplicate(utils.CacheSessionCostsTBL, utils.ConcatenatedKey(smc.CGRID, smc.RunID, smc.OriginHost, smc.OriginID),
+	iDB.cacheRemove(utils.CacheSessionCostsTBL, utils.ConcatenatedKey(smc.CGRID, smc.RunID, smc.OriginHost, smc.OriginID),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return
 }
@@ -1045,12 +1045,29 @@ func (iDB *InternalDB) RemoveSMCosts(qryFltr *utils.SMCostFilter) error {
 	}
 
 	for key := range smMpIDs {
-		Cache.RemoveWithoutReplicate(utils.CacheSessionCostsTBL, key,
+		iDB.cacheRemove(utils.CacheSessionCostsTBL, key,
 			cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	}
 	return nil
 }
 
+// GetCDRsAggregates groups in memory the CDRs matching the filter
+func (iDB *InternalDB) GetCDRsAggregates(qryFltr *utils.CDRsFilter, grps []*utils.CDRsGroupBy,
+	sumFlds []string) (aggrs []*utils.CDRsAggregate, err error) {
+	fltr := *qryFltr // the pagination applies to the groups
+	fltr.Paginator = utils.Paginator{}
+	fltr.OrderBy = utils.EmptyString
+	fltr.Count = false
+	var cdrs []*CDR
+	if cdrs, _, err = iDB.GetCDRs(&fltr, false); err != nil {
+		return
+	}
+	if aggrs = aggregateCDRs(cdrs, grps, sumFlds, qryFltr.Paginator); len(aggrs) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
 // GetCDRs returns the CDRs from  DB based on given filters
 func (iDB *InternalDB) GetCDRs(filter *utils.CDRsFilter, remove bool) (cdrs []*CDR, count int64, err error) {
 	// filterPair used only for GetCDRs for internalDB
@@ -1489,7 +1506,7 @@ func (iDB *InternalDB) GetCDRs(filter *utils.CDRsFilter, remove bool) (cdrs []*C
 	}
 	if remove {
 		for _, cdr := range cdrs {
-			Cache.RemoveWithoutReplicate(utils.CacheCDRsTBL, utils.ConcatenatedKey(cdr.CGRID, cdr.RunID, cdr.OriginID),
+			iDB.cacheRemove(utils.CacheCDRsTBL, utils.ConcatenatedKey(cdr.CGRID, cdr.RunID, cdr.OriginID),
 				cacheCommit(utils.NonTransactional), utils.NonTransactional)
 		}
 		return nil, 0, nil
@@ -1563,7 +1580,184 @@ func (iDB *InternalDB) SetSMCost(smCost *SMCost) (err error) {
 	idxs.Add(utils.ConcatenatedKey(utils.OriginHost, smCost.OriginHost))
 	idxs.Add(utils.ConcatenatedKey(utils.OriginID, smCost.OriginID))
 	idxs.Add(utils.ConcatenatedKey(utils.CostSource, smCost.CostSource))
-	Cache.SetWithoutReplicate(utils.CacheSessionCostsTBL, utils.ConcatenatedKey(smCost.CGRID, smCost.RunID, smCost.OriginHost, smCost.OriginID), smCost, idxs.AsSlice(),
+	iDB.cacheSet(utils.CacheSessionCostsTBL, utils.ConcatenatedKey(smCost.CGRID, smCost.RunID, smCost.OriginHost, smCost.OriginID), smCost, idxs.AsSlice(),
 		cacheCommit(utils.NonTransactional), utils.NonTransactional)
 	return err
 }
+
+// SetAuditEntry records one audit entry, indexed by the filtered fields
+func (iDB *InternalDB) SetAuditEntry(ae *AuditEntry) (err error) {
+	idxs := make(utils.StringSet)
+	idxs.Add(utils.ConcatenatedKey(utils.Method, ae.Method))
+	idxs.Add(utils.ConcatenatedKey(utils.Subject, ae.Subject))
+	idxs.Add(utils.ConcatenatedKey(utils.Tenant, ae.Tenant))
+	idxs.Add(utils.ConcatenatedKey(utils.ItemID, ae.ItemID))
+	iDB.cacheSet(utils.CacheAuditLogTBL, ae.ID, ae, idxs.AsSlice(),
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
+
+// GetAuditEntries returns the audit entries matching the filter, ordered by time
+func (iDB *InternalDB) GetAuditEntries(qryFltr *utils.AuditLogFilter) (aes []*AuditEntry, err error) {
+	var aeMpIDs utils.StringMap
+	for _, fltrSlc := range []struct {
+		key string
+		ids []string
+	}{
+		{utils.Method, qryFltr.Methods},
+		{utils.Subject, qryFltr.Subjects},
+		{utils.Tenant, qryFltr.Tenants},
+		{utils.ItemID, qryFltr.ItemIDs},
+	} {
+		if len(fltrSlc.ids) == 0 {
+			continue
+		}
+		grpMpIDs := make(utils.StringMap)
+		for _, id := range fltrSlc.ids {
+			for _, grpID := range Cache.tCache.GetGroupItemIDs(utils.CacheAuditLogTBL, utils.ConcatenatedKey(fltrSlc.key, id)) {
+				grpMpIDs[grpID] = true
+			}
+		}
+		if aeMpIDs == nil {
+			aeMpIDs = grpMpIDs
+			continue
+		}
+		for id := range aeMpIDs {
+			if !grpMpIDs.HasKey(id) {
+				delete(aeMpIDs, id)
+			}
+		}
+	}
+	if aeMpIDs == nil {
+		aeMpIDs = utils.StringMapFromSlice(Cache.GetItemIDs(utils.CacheAuditLogTBL, utils.EmptyString))
+	}
+	for id := range aeMpIDs {
+		x, ok := Cache.Get(utils.CacheAuditLogTBL, id)
+		if !ok || x == nil {
+			continue
+		}
+		ae := x.(*AuditEntry)
+		if qryFltr.Time.Begin != nil && ae.Time.Before(*qryFltr.Time.Begin) ||
+			qryFltr.Time.End != nil && !ae.Time.Before(*qryFltr.Time.End) {
+			continue
+		}
+		aes = append(aes, ae)
+	}
+	if len(aes) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	sort.Slice(aes, func(i, j int) bool {
+		return aes[i].Time.Before(aes[j].Time)
+	})
+	var limit, offset int
+	if qryFltr.Paginator.Limit != nil && *qryFltr.Paginator.Limit > 0 {
+		limit = *qryFltr.Paginator.Limit
+	}
+	if qryFltr.Paginator.Offset != nil && *qryFltr.Paginator.Offset > 0 {
+		offset = *qryFltr.Paginator.Offset
+	}
+	if offset >= len(aes) {
+		return nil, utils.ErrNotFound
+	}
+	aes = aes[offset:]
+	if limit != 0 && limit < len(aes) {
+		aes = aes[:limit]
+	}
+	return
+}
+
+// SetInvoice records one invoice, indexed by the filtered fields
+// returns ErrExists if the number or the cycle of the account are already used within the tenant
+func (iDB *InternalDB) SetInvoice(inv *Invoice) (err error) {
+	iDB.invoicesMux.Lock()
+	defer iDB.invoicesMux.Unlock()
+	for _, id := range Cache.tCache.GetGroupItemIDs(utils.CacheInvoicesTBL,
+		utils.ConcatenatedKey(utils.Tenant, inv.Tenant)) {
+		x, ok := Cache.Get(utils.CacheInvoicesTBL, id)
+		if !ok || x == nil {
+			continue
+		}
+		if stored := x.(*Invoice); stored.Number == inv.Number ||
+			(stored.Account == inv.Account && stored.CycleStart.Equal(inv.CycleStart)) {
+			return utils.ErrExists
+		}
+	}
+	idxs := make(utils.StringSet)
+	idxs.Add(utils.ConcatenatedKey(utils.ID, inv.ID))
+	idxs.Add(utils.ConcatenatedKey(utils.Tenant, inv.Tenant))
+	idxs.Add(utils.ConcatenatedKey(utils.AccountField, inv.Account))
+	iDB.cacheSet(utils.CacheInvoicesTBL, inv.TenantID(), inv, idxs.AsSlice(),
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
+
+// GetInvoices returns the invoices matching the filter, the last numbered first
+func (iDB *InternalDB) GetInvoices(qryFltr *utils.InvoicesFilter) (invs []*Invoice, err error) {
+	var invMpIDs utils.StringMap
+	for _, fltrSlc := range []struct {
+		key string
+		ids []string
+	}{
+		{utils.ID, qryFltr.IDs},
+		{utils.Tenant, qryFltr.Tenants},
+		{utils.AccountField, qryFltr.Accounts},
+	} {
+		if len(fltrSlc.ids) == 0 {
+			continue
+		}
+		grpMpIDs := make(utils.StringMap)
+		for _, id := range fltrSlc.ids {
+			for _, grpID := range Cache.tCache.GetGroupItemIDs(utils.CacheInvoicesTBL, utils.ConcatenatedKey(fltrSlc.key, id)) {
+				grpMpIDs[grpID] = true
+			}
+		}
+		if invMpIDs == nil {
+			invMpIDs = grpMpIDs
+			continue
+		}
+		for id := range invMpIDs {
+			if !grpMpIDs.HasKey(id) {
+				delete(invMpIDs, id)
+			}
+		}
+	}
+	if invMpIDs == nil {
+		invMpIDs = utils.StringMapFromSlice(Cache.GetItemIDs(utils.CacheInvoicesTBL, utils.EmptyString))
+	}
+	for id := range invMpIDs {
+		x, ok := Cache.Get(utils.CacheInvoicesTBL, id)
+		if !ok || x == nil {
+			continue
+		}
+		inv := x.(*Invoice)
+		if qryFltr.Cycle.Begin != nil && !inv.CycleEnd.After(*qryFltr.Cycle.Begin) ||
+			qryFltr.Cycle.End != nil && !inv.CycleStart.Before(*qryFltr.Cycle.End) {
+			continue
+		}
+		invs = append(invs, inv)
+	}
+	if len(invs) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	sort.Slice(invs, func(i, j int) bool {
+		if invs[i].Number != invs[j].Number {
+			return invs[i].Number > invs[j].Number
+		}
+		return invs[i].Tenant < invs[j].Tenant
+	})
+	var limit, offset int
+	if qryFltr.Paginator.Limit != nil && *qryFltr.Paginator.Limit > 0 {
+		limit = *qryFltr.Paginator.Limit
+	}
+	if qryFltr.Paginator.Offset != nil && *qryFltr.Paginator.Offset > 0 {
+		offset = *qryFltr.Paginator.Offset
+	}
+	if offset >= len(invs) {
+		return nil, utils.ErrNotFound
+	}
+	invs = invs[offset:]
+	if limit != 0 && limit < len(invs) {
+		invs = invs[:limit]
+	}
+	return
+}
@@ -80,6 +80,10 @@ const (
 	ColApp  = "action_profiles"
 	ColLID  = "load_ids"
 	ColAnp  = "account_profiles"
+	ColDdp  = "dedup_ids"
+	ColSsn  = "shared_sessions"
+	ColCfg  = "config_sections"
+	ColLnp  = "ported_numbers"
 )
 
 var (
@@ -253,6 +257,35 @@ func (ms *MongoStorage) query(argfunc func(ctx mongo.SessionContext) error) (err
 	return ms.client.UseSession(ctxSession, argfunc)
 }
 
+// isMongoDuplicateKey returns true if the write was rejected by an unique index
+// mirrors mongo.IsDuplicateKeyError which is not available in the driver version used
+func isMongoDuplicateKey(err error) bool {
+	switch e := err.(type) {
+	case mongo.WriteException:
+		for _, we := range e.WriteErrors {
+			if isMongoDuplicateKeyCode(we.Code) {
+				return true
+			}
+		}
+		return e.WriteConcernError != nil && isMongoDuplicateKeyCode(e.WriteConcernError.Code)
+	case mongo.BulkWriteException:
+		for _, we := range e.WriteErrors {
+			if isMongoDuplicateKeyCode(we.Code) {
+				return true
+			}
+		}
+		return e.WriteConcernError != nil && isMongoDuplicateKeyCode(e.WriteConcernError.Code)
+	case mongo.CommandError:
+		return isMongoDuplicateKeyCode(int(e.Code))
+	}
+	return false
+}
+
+// isMongoDuplicateKeyCode returns true for the error codes of the duplicated keys
+func isMongoDuplicateKeyCode(code int) bool {
+	return code == 11000 || code == 11001 || code == 12582
+}
+
 // IsDataDB returns if the storeage is used for DataDb
 func (ms *MongoStorage) IsDataDB() bool {
 	return ms.storageType == utils.DataDB
@@ -281,6 +314,25 @@ func (ms *MongoStorage) enusureIndex(colName string, uniq bool, keys ...string)
 	})
 }
 
+// ensureTTLIndex makes mongo remove the documents once the time in key is reached
+func (ms *MongoStorage) ensureTTLIndex(colName, key string) error {
+	return ms.query(func(sctx mongo.SessionContext) error {
+		_, err := ms.getCol(colName).Indexes().CreateOne(sctx, mongo.IndexModel{
+			Keys:    bson.M{key: 1},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		})
+		return err
+	})
+}
+
+// ensureDedupIndexes creates the indexes of the deduplication IDs, the existing ones being kept
+func (ms *MongoStorage) ensureDedupIndexes() (err error) {
+	if err = ms.enusureIndex(ColDdp, true, "key"); err != nil {
+		return
+	}
+	return ms.ensureTTLIndex(ColDdp, "expiry")
+}
+
 func (ms *MongoStorage) dropAllIndexesForCol(colName string) error {
 	return ms.query(func(sctx mongo.SessionContext) error {
 		col := ms.getCol(colName)
@@ -316,7 +368,7 @@ func (ms *MongoStorage) ensureIndexesForCol(col string) (err error) { // exporte
 		if err = ms.enusureIndex(col, true, "key"); err != nil {
 			return
 		}
-	case ColRsP, ColRes, ColSqs, ColSqp, ColTps, ColThs, ColRts, ColAttr, ColFlt, ColCpp, ColDpp, ColDph, ColRpp, ColApp, ColAnp:
+	case ColRsP, ColRes, ColSqs, ColSqp, ColTps, ColThs, ColRts, ColAttr, ColFlt, ColCpp, ColDpp, ColDph, ColRpp, ColApp, ColAnp, ColLnp:
 		if err = ms.enusureIndex(col, true, "tenant", "id"); err != nil {
 			return
 		}
@@ -324,6 +376,18 @@ func (ms *MongoStorage) ensureIndexesForCol(col string) (err error) { // exporte
 		if err = ms.enusureIndex(col, true, "id"); err != nil {
 			return
 		}
+	case ColDdp:
+		if err = ms.ensureDedupIndexes(); err != nil {
+			return
+		}
+	case ColSsn:
+		if err = ms.enusureIndex(col, true, "cgrid"); err != nil {
+			return
+		}
+	case ColCfg:
+		if err = ms.enusureIndex(col, true, "section"); err != nil {
+			return
+		}
 		//StorDB
 	case utils.TBLTPTimings, utils.TBLTPDestinations,
 		utils.TBLTPDestinationRates, utils.TBLTPRatingPlans,
@@ -363,6 +427,23 @@ func (ms *MongoStorage) ensureIndexesForCol(col string) (err error) { // exporte
 			OriginIDLow); err != nil {
 			return
 		}
+	case utils.AuditLogTBL:
+		if err = ms.enusureIndex(col, false, "tenant", "itemid"); err != nil {
+			return
+		}
+		if err = ms.enusureIndex(col, false, "time"); err != nil {
+			return
+		}
+	case utils.InvoicesTBL:
+		if err = ms.enusureIndex(col, true, "tenant", "id"); err != nil {
+			return
+		}
+		if err = ms.enusureIndex(col, true, "tenant", "number"); err != nil {
+			return
+		}
+		if err = ms.enusureIndex(col, true, "tenant", "account", "cyclestart"); err != nil {
+			return
+		}
 	}
 	return
 }
@@ -381,7 +462,7 @@ func (ms *MongoStorage) EnsureIndexes(cols ...string) (err error) {
 		for _, col := range []string{ColAct, ColApl, ColAAp, ColAtr,
 			ColRpl, ColDst, ColRds, ColLht, ColIndx, ColRsP, ColRes, ColSqs, ColSqp,
 			ColTps, ColThs, ColRts, ColAttr, ColFlt, ColCpp, ColDpp, ColRpp, ColApp,
-			ColRpf, ColShg, ColAcc, ColAnp} {
+			ColRpf, ColShg, ColAcc, ColAnp, ColDdp, ColSsn, ColCfg, ColLnp} {
 			if err = ms.ensureIndexesForCol(col); err != nil {
 				return
 			}
@@ -393,7 +474,8 @@ func (ms *MongoStorage) EnsureIndexes(cols ...string) (err error) {
 			utils.TBLTPSharedGroups, utils.TBLTPActions,
 			utils.TBLTPActionPlans, utils.TBLTPActionTriggers,
 			utils.TBLTPStats, utils.TBLTPResources,
-			utils.TBLTPRatingProfiles, utils.CDRsTBL, utils.SessionCostsTBL} {
+			utils.TBLTPRatingProfiles, utils.CDRsTBL, utils.SessionCostsTBL,
+			utils.AuditLogTBL, utils.InvoicesTBL} {
 			if err = ms.ensureIndexesForCol(col); err != nil {
 				return
 			}
@@ -646,6 +728,8 @@ func (ms *MongoStorage) GetKeysForPrefix(prefix string) (result []string, err er
 			result, err = ms.getField2(sctx, ColAnp, utils.AccountProfilePrefix, subject, tntID)
 		case utils.DispatcherHostPrefix:
 			result, err = ms.getField2(sctx, ColDph, utils.DispatcherHostPrefix, subject, tntID)
+		case utils.PortedNumberPrefix:
+			result, err = ms.getField2(sctx, ColLnp, utils.PortedNumberPrefix, subject, tntID)
 		case utils.AttributeFilterIndexes:
 			result, err = ms.getField3(sctx, ColIndx, utils.AttributeFilterIndexes, "key")
 		case utils.ResourceFilterIndexes:
@@ -1969,6 +2053,42 @@ func (ms *MongoStorage) RemoveDispatcherHostDrv(tenant, id string) (err error) {
 	})
 }
 
+func (ms *MongoStorage) GetPortedNumberDrv(tenant, id string) (pn *PortedNumber, err error) {
+	pn = new(PortedNumber)
+	err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(ColLnp).FindOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if err := cur.Decode(pn); err != nil {
+			pn = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (ms *MongoStorage) SetPortedNumberDrv(pn *PortedNumber) (err error) {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(ColLnp).UpdateOne(sctx, bson.M{"tenant": pn.Tenant, "id": pn.ID},
+			bson.M{"$set": pn},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemovePortedNumberDrv(tenant, id string) (err error) {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(ColLnp).DeleteOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
+
 func (ms *MongoStorage) GetItemLoadIDsDrv(itemIDPrefix string) (loadIDs map[string]int64, err error) {
 	fop := options.FindOne()
 	if itemIDPrefix != "" {
@@ -2241,3 +2361,157 @@ func (ms *MongoStorage) RemoveAccountProfileDrv(tenant, id string) (err error) {
 		return err
 	})
 }
+
+// SetDedupIDDrv stores the ID for ttl, returning utils.ErrExists if it is already stored
+func (ms *MongoStorage) SetDedupIDDrv(id string, ttl time.Duration) (err error) {
+	now := time.Now()
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		// the upsert matches only the expired IDs, the unique index rejects the active ones
+		_, err = ms.getCol(ColDdp).UpdateOne(sctx, bson.M{"key": id, "expiry": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"key": id, "expiry": now.Add(ttl)}},
+			options.Update().SetUpsert(true),
+		)
+		if isMongoDuplicateKey(err) {
+			err = utils.ErrExists
+		}
+		return
+	})
+}
+
+func (ms *MongoStorage) RemoveDedupIDDrv(id string) (err error) {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(ColDdp).DeleteOne(sctx, bson.M{"key": id})
+		return
+	})
+}
+
+func (ms *MongoStorage) GetSharedSessionsDrv() (sss []*SharedSession, err error) {
+	err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(ColSsn).Find(sctx, bson.D{})
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var ss SharedSession
+			if err := cur.Decode(&ss); err != nil {
+				return err
+			}
+			sss = append(sss, &ss)
+		}
+		return cur.Close(sctx)
+	})
+	return
+}
+
+// SetSharedSessionDrv stores the session, returning utils.ErrExists if owned by another node with an active lease
+func (ms *MongoStorage) SetSharedSessionDrv(ss *SharedSession) (err error) {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		// the upsert matches only the sessions we can overwrite, the unique index rejects the other ones
+		_, err = ms.getCol(ColSsn).UpdateOne(sctx,
+			bson.M{"cgrid": ss.CGRID, "$or": bson.A{
+				bson.M{"nodeid": ss.NodeID},
+				bson.M{"expiry": bson.M{"$lte": time.Now()}},
+			}},
+			bson.M{"$set": ss},
+			options.Update().SetUpsert(true),
+		)
+		if isMongoDuplicateKey(err) {
+			err = utils.ErrExists
+		}
+		return
+	})
+}
+
+// ClaimSharedSessionDrv takes the ownership of an expired session, returning utils.ErrNotFound otherwise
+func (ms *MongoStorage) ClaimSharedSessionDrv(cgrID, nodeID string, expiry time.Time) (ss *SharedSession, err error) {
+	if err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(ColSsn).FindOneAndUpdate(sctx,
+			bson.M{"cgrid": cgrID, "expiry": bson.M{"$lte": time.Now()}},
+			bson.M{"$set": bson.M{"nodeid": nodeID, "expiry": expiry}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		)
+		if err := cur.Decode(&ss); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (ms *MongoStorage) RemoveSharedSessionDrv(cgrID, nodeID string) (err error) {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(ColSsn).DeleteOne(sctx, bson.M{"cgrid": cgrID, "nodeid": nodeID})
+		return
+	})
+}
+
+func (ms *MongoStorage) GetConfigSectionsDrv() (cfgSects []*ConfigSection, err error) {
+	err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(ColCfg).Find(sctx, bson.D{})
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var cfgSect ConfigSection
+			if err := cur.Decode(&cfgSect); err != nil {
+				return err
+			}
+			cfgSects = append(cfgSects, &cfgSect)
+		}
+		return cur.Close(sctx)
+	})
+	return
+}
+
+func (ms *MongoStorage) GetConfigSectionDrv(section string) (cfgSect *ConfigSection, err error) {
+	cfgSect = new(ConfigSection)
+	err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(ColCfg).FindOne(sctx, bson.M{"section": section})
+		if err := cur.Decode(cfgSect); err != nil {
+			cfgSect = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+// SetConfigSectionDrv stores the section if the stored version is the previous one, returning utils.ErrExists otherwise
+func (ms *MongoStorage) SetConfigSectionDrv(cfgSect *ConfigSection) (err error) {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		// only the first version is inserted, the unique index rejecting it if the section was stored meanwhile
+		var ur *mongo.UpdateResult
+		if ur, err = ms.getCol(ColCfg).UpdateOne(sctx,
+			bson.M{"section": cfgSect.Section, "version": cfgSect.Version - 1},
+			bson.M{"$set": cfgSect},
+			options.Update().SetUpsert(cfgSect.Version == 1),
+		); err != nil {
+			if isMongoDuplicateKey(err) {
+				err = utils.ErrExists
+			}
+			return
+		}
+		if ur.MatchedCount == 0 && ur.UpsertedCount == 0 {
+			return utils.ErrExists
+		}
+		return
+	})
+}
+
+func (ms *MongoStorage) RemoveConfigSectionDrv(section string) (err error) {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(ColCfg).DeleteOne(sctx, bson.M{"section": section})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
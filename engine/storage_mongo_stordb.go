@@ -932,6 +932,99 @@ func (ms *MongoStorage) RemoveSMCosts(qryFltr *utils.SMCostFilter) error {
 	})
 }
 
+// SetAuditEntry records one audit entry in the audit_log collection
+func (ms *MongoStorage) SetAuditEntry(ae *AuditEntry) error {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(utils.AuditLogTBL).InsertOne(sctx, ae)
+		return err
+	})
+}
+
+// GetAuditEntries returns the audit entries matching the filter, ordered by time
+func (ms *MongoStorage) GetAuditEntries(qryFltr *utils.AuditLogFilter) (aes []*AuditEntry, err error) {
+	filters := bson.M{
+		"method":  bson.M{"$in": qryFltr.Methods},
+		"subject": bson.M{"$in": qryFltr.Subjects},
+		"tenant":  bson.M{"$in": qryFltr.Tenants},
+		"itemid":  bson.M{"$in": qryFltr.ItemIDs},
+		"time":    bson.M{"$gte": qryFltr.Time.Begin, "$lt": qryFltr.Time.End},
+	}
+	ms.cleanEmptyFilters(filters)
+	fop := options.Find().SetSort(bson.M{"time": 1})
+	if qryFltr.Paginator.Limit != nil {
+		fop = fop.SetLimit(int64(*qryFltr.Paginator.Limit))
+	}
+	if qryFltr.Paginator.Offset != nil {
+		fop = fop.SetSkip(int64(*qryFltr.Paginator.Offset))
+	}
+	err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(utils.AuditLogTBL).Find(sctx, filters, fop)
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var ae AuditEntry
+			if err := cur.Decode(&ae); err != nil {
+				return err
+			}
+			aes = append(aes, &ae)
+		}
+		if len(aes) == 0 {
+			return utils.ErrNotFound
+		}
+		return cur.Close(sctx)
+	})
+	return
+}
+
+// SetInvoice records one invoice in the invoices collection
+func (ms *MongoStorage) SetInvoice(inv *Invoice) error {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(utils.InvoicesTBL).InsertOne(sctx, inv)
+		if isMongoDuplicateKey(err) { // the number or the cycle of the account were taken meanwhile
+			return utils.ErrExists
+		}
+		return err
+	})
+}
+
+// GetInvoices returns the invoices matching the filter, the last numbered first
+func (ms *MongoStorage) GetInvoices(qryFltr *utils.InvoicesFilter) (invs []*Invoice, err error) {
+	filters := bson.M{
+		"id":         bson.M{"$in": qryFltr.IDs},
+		"tenant":     bson.M{"$in": qryFltr.Tenants},
+		"account":    bson.M{"$in": qryFltr.Accounts},
+		"cycleend":   bson.M{"$gt": qryFltr.Cycle.Begin},
+		"cyclestart": bson.M{"$lt": qryFltr.Cycle.End},
+	}
+	ms.cleanEmptyFilters(filters)
+	fop := options.Find().SetSort(bson.D{{Key: "number", Value: -1}, {Key: "tenant", Value: 1}})
+	if qryFltr.Paginator.Limit != nil {
+		fop = fop.SetLimit(int64(*qryFltr.Paginator.Limit))
+	}
+	if qryFltr.Paginator.Offset != nil {
+		fop = fop.SetSkip(int64(*qryFltr.Paginator.Offset))
+	}
+	err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(utils.InvoicesTBL).Find(sctx, filters, fop)
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var inv Invoice
+			if err := cur.Decode(&inv); err != nil {
+				return err
+			}
+			invs = append(invs, &inv)
+		}
+		if len(invs) == 0 {
+			return utils.ErrNotFound
+		}
+		return cur.Close(sctx)
+	})
+	return
+}
+
 func (ms *MongoStorage) SetCDR(cdr *CDR, allowUpdate bool) error {
 	if cdr.OrderID == 0 {
 		cdr.OrderID = ms.cnter.Next()
@@ -944,7 +1037,7 @@ func (ms *MongoStorage) SetCDR(cdr *CDR, allowUpdate bool) error {
 			return
 		}
 		_, err = ms.getCol(ColCDRs).InsertOne(sctx, cdr)
-		if err != nil && strings.Contains(err.Error(), "E11000") { // Mongo returns E11000 when key is duplicated
+		if isMongoDuplicateKey(err) {
 			err = utils.ErrExists
 		}
 		return
@@ -983,19 +1076,19 @@ func (ms *MongoStorage) cleanEmptyFilters(filters bson.M) {
 	}
 }
 
-//  _, err := col(ColCDRs).UpdateAll(bson.M{CGRIDLow: bson.M{"$in": cgrIds}}, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
-func (ms *MongoStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR, int64, error) {
+// cdrsFilter returns the query selecting the CDRs matching the filter
+func (ms *MongoStorage) cdrsFilter(qryFltr *utils.CDRsFilter) (bson.M, error) {
 	var minUsage, maxUsage *time.Duration
 	if len(qryFltr.MinUsage) != 0 {
 		if parsed, err := utils.ParseDurationWithNanosecs(qryFltr.MinUsage); err != nil {
-			return nil, 0, err
+			return nil, err
 		} else {
 			minUsage = &parsed
 		}
 	}
 	if len(qryFltr.MaxUsage) != 0 {
 		if parsed, err := utils.ParseDurationWithNanosecs(qryFltr.MaxUsage); err != nil {
-			return nil, 0, err
+			return nil, err
 		} else {
 			maxUsage = &parsed
 		}
@@ -1096,6 +1189,15 @@ func (ms *MongoStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	}
 	//file.WriteString(fmt.Sprintf("AFTER: %v\n", utils.ToIJSON(filters)))
 	//file.Close()
+	return filters, nil
+}
+
+//  _, err := col(ColCDRs).UpdateAll(bson.M{CGRIDLow: bson.M{"$in": cgrIds}}, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+func (ms *MongoStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR, int64, error) {
+	filters, err := ms.cdrsFilter(qryFltr)
+	if err != nil {
+		return nil, 0, err
+	}
 	if remove {
 		var chgd int64
 		err := ms.query(func(sctx mongo.SessionContext) (err error) {
@@ -1152,7 +1254,7 @@ func (ms *MongoStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	}
 	// Execute query
 	var cdrs []*CDR
-	err := ms.query(func(sctx mongo.SessionContext) (err error) {
+	err = ms.query(func(sctx mongo.SessionContext) (err error) {
 		cur, err := ms.getCol(ColCDRs).Find(sctx, filters, fop)
 		if err != nil {
 			return err
@@ -1175,6 +1277,112 @@ func (ms *MongoStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	return cdrs, 0, err
 }
 
+// GetCDRsAggregates groups the CDRs matching the filter using the aggregation pipeline
+func (ms *MongoStorage) GetCDRsAggregates(qryFltr *utils.CDRsFilter, grps []*utils.CDRsGroupBy,
+	sumFlds []string) (aggrs []*utils.CDRsAggregate, err error) {
+	var filters bson.M
+	if filters, err = ms.cdrsFilter(qryFltr); err != nil {
+		return
+	}
+	grpID := make(bson.M, len(grps))
+	srt := make(bson.D, len(grps))
+	for i, grp := range grps {
+		var expr interface{} = "$" + strings.ToLower(grp.Field)
+		if grp.ExtraField {
+			expr = "$extrafields." + grp.Field
+		}
+		if grp.Length != 0 {
+			expr = bson.M{"$substrCP": bson.A{expr, 0, grp.Length}}
+		} else if grp.Period != utils.EmptyString {
+			format := "%Y-%m-%d"
+			if grp.Period == utils.MetaHourly {
+				format = "%Y-%m-%dT%H"
+			}
+			expr = bson.M{"$dateToString": bson.M{"format": format, "date": expr}}
+		}
+		key := fmt.Sprintf("grp%d", i)
+		grpID[key] = expr
+		srt[i] = bson.E{Key: "_id." + key, Value: 1}
+	}
+	// the unrated CDRs(cost -1) are left out of the cost aggregates
+	ratedCost := bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$" + CostLow, -1}}, nil, "$" + CostLow}}
+	grpStage := bson.M{
+		"_id":      grpID,
+		"count":    bson.M{"$sum": 1},
+		"usage":    bson.M{"$sum": "$" + UsageLow},
+		"minusage": bson.M{"$min": "$" + UsageLow},
+		"maxusage": bson.M{"$max": "$" + UsageLow},
+		"cost":     bson.M{"$sum": ratedCost},
+		"mincost":  bson.M{"$min": ratedCost},
+		"maxcost":  bson.M{"$max": ratedCost},
+	}
+	// the extra fields are kept as strings, the ones that are not numbers count as 0
+	for i, fld := range sumFlds {
+		grpStage[fmt.Sprintf("sum%d", i)] = bson.M{"$sum": bson.M{"$convert": bson.M{
+			"input": "$extrafields." + fld, "to": "double", "onError": 0., "onNull": 0.}}}
+	}
+	pipeline := bson.A{
+		bson.M{"$match": filters},
+		bson.M{"$group": grpStage},
+		bson.M{"$sort": srt},
+	}
+	if qryFltr.Paginator.Offset != nil {
+		pipeline = append(pipeline, bson.M{"$skip": *qryFltr.Paginator.Offset})
+	}
+	if qryFltr.Paginator.Limit != nil {
+		pipeline = append(pipeline, bson.M{"$limit": *qryFltr.Paginator.Limit})
+	}
+	err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(ColCDRs).Aggregate(sctx, pipeline)
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var rcv struct {
+				ID       map[string]interface{} `bson:"_id"`
+				Sums     bson.M                 `bson:",inline"`
+				Count    int64
+				Usage    int64
+				MinUsage int64
+				MaxUsage int64
+				Cost     float64
+				MinCost  float64
+				MaxCost  float64
+			}
+			if err = cur.Decode(&rcv); err != nil {
+				return err
+			}
+			aggr := &utils.CDRsAggregate{
+				Group:    make(map[string]string),
+				Count:    rcv.Count,
+				Usage:    time.Duration(rcv.Usage),
+				MinUsage: time.Duration(rcv.MinUsage),
+				MaxUsage: time.Duration(rcv.MaxUsage),
+				Cost:     rcv.Cost,
+				MinCost:  rcv.MinCost,
+				MaxCost:  rcv.MaxCost,
+			}
+			for i, grp := range grps {
+				aggr.Group[grp.Key] = utils.IfaceAsString(rcv.ID[fmt.Sprintf("grp%d", i)])
+			}
+			if len(sumFlds) != 0 {
+				aggr.Sums = make(map[string]float64, len(sumFlds))
+				for i, fld := range sumFlds {
+					if aggr.Sums[fld], err = utils.IfaceAsFloat64(rcv.Sums[fmt.Sprintf("sum%d", i)]); err != nil {
+						return err
+					}
+				}
+			}
+			aggrs = append(aggrs, aggr)
+		}
+		if len(aggrs) == 0 {
+			return utils.ErrNotFound
+		}
+		return cur.Close(sctx)
+	})
+	return
+}
+
 func (ms *MongoStorage) SetTPStats(tpSTs []*utils.TPStatProfile) (err error) {
 	if len(tpSTs) == 0 {
 		return
@@ -27,14 +27,18 @@ import (
 	"gorm.io/gorm"
 )
 
+// mySQLConnString returns the DSN used to connect to MySQL
+func mySQLConnString(host, port, name, user, password string) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&loc=Local&parseTime=true&sql_mode='ALLOW_INVALID_DATES'", user, password, host, port, name)
+}
+
 type MySQLStorage struct {
 	SQLStorage
 }
 
 func NewMySQLStorage(host, port, name, user, password string,
 	maxConn, maxIdleConn, connMaxLifetime int) (*SQLStorage, error) {
-	connectString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&loc=Local&parseTime=true&sql_mode='ALLOW_INVALID_DATES'", user, password, host, port, name)
-	db, err := gorm.Open(mysql.Open(connectString), &gorm.Config{AllowGlobalUpdate: true})
+	db, err := gorm.Open(mysql.Open(mySQLConnString(host, port, name, user, password)), &gorm.Config{AllowGlobalUpdate: true})
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +99,28 @@ func (self *MySQLStorage) notExtraFieldsValueQry(field, value string) string {
 	return fmt.Sprintf(" extra_fields NOT LIKE '%%\"%s\":\"%s\"%%'", field, value)
 }
 
+// timeGroupQry truncates the column in UTC
+// the datetime columns hold the local time of the engine(loc=Local) so they are converted first
+// from the session time zone, which follows the offset of each value over the DST changes
+func (self *MySQLStorage) timeGroupQry(column, period string) string {
+	column = fmt.Sprintf("CONVERT_TZ(%s, @@session.time_zone, '+00:00')", column)
+	if period == utils.MetaHourly {
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%dT%%H')", column)
+	}
+	return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d')", column)
+}
+
+// extraFieldQry extracts the field from the JSON kept in extra_fields
+func (self *MySQLStorage) extraFieldQry(field string) string {
+	return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(extra_fields, '$.\"%s\"'))", field)
+}
+
+// extraFieldSumQry sums the field from extra_fields, the values that are not numbers count as 0
+func (self *MySQLStorage) extraFieldSumQry(field string) string {
+	return fmt.Sprintf("SUM(CASE WHEN %s REGEXP '^-?[0-9]+(\\\\.[0-9]+)?([eE][-+]?[0-9]+)?$' THEN CAST(%s AS DECIMAL(65,10)) END)",
+		self.extraFieldQry(field), self.extraFieldQry(field))
+}
+
 func (self *MySQLStorage) GetStorageType() string {
 	return utils.MySQL
 }
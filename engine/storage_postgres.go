@@ -29,8 +29,7 @@ import (
 
 // NewPostgresStorage returns the posgres storDB
 func NewPostgresStorage(host, port, name, user, password, sslmode string, maxConn, maxIdleConn, connMaxLifetime int) (*SQLStorage, error) {
-	connectString := fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=%s", host, port, name, user, password, sslmode)
-	db, err := gorm.Open(postgres.Open(connectString), &gorm.Config{AllowGlobalUpdate: true})
+	db, err := gorm.Open(postgres.Open(postgresConnString(host, port, name, user, password, sslmode)), &gorm.Config{AllowGlobalUpdate: true})
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +53,11 @@ func NewPostgresStorage(host, port, name, user, password, sslmode string, maxCon
 	}, nil
 }
 
+// postgresConnString returns the DSN used to connect to PostgreSQL
+func postgresConnString(host, port, name, user, password, sslmode string) string {
+	return fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=%s", host, port, name, user, password, sslmode)
+}
+
 type PostgresStorage struct {
 	SQLStorage
 }
@@ -97,6 +101,26 @@ func (self *PostgresStorage) notExtraFieldsValueQry(field, value string) string
 	return fmt.Sprintf(" NOT (extra_fields ?'%s' AND (extra_fields ->> '%s') = '%s')", field, field, value)
 }
 
+// timeGroupQry truncates the column in UTC, independent of the session time zone
+func (self *PostgresStorage) timeGroupQry(column, period string) string {
+	column = fmt.Sprintf("(%s AT TIME ZONE 'UTC')", column)
+	if period == utils.MetaHourly {
+		return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD\"T\"HH24')", column)
+	}
+	return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", column)
+}
+
+// extraFieldQry extracts the field from the extra_fields jsonb
+func (self *PostgresStorage) extraFieldQry(field string) string {
+	return fmt.Sprintf("(extra_fields ->> '%s')", field)
+}
+
+// extraFieldSumQry sums the field from extra_fields, the values that are not numbers being left out
+func (self *PostgresStorage) extraFieldSumQry(field string) string {
+	return fmt.Sprintf("SUM(CASE WHEN %s ~ '^-?[0-9]+(\\.[0-9]+)?([eE][-+]?[0-9]+)?$' THEN CAST(%s AS DOUBLE PRECISION) END)",
+		self.extraFieldQry(field), self.extraFieldQry(field))
+}
+
 func (self *PostgresStorage) GetStorageType() string {
 	return utils.Postgres
 }
@@ -37,8 +37,10 @@ import (
 )
 
 type RedisStorage struct {
-	client radix.Client
-	ms     Marshaler
+	client  radix.Client
+	ms      Marshaler
+	address string         // used by the subscriptions
+	dial    radix.ConnFunc // dials the dedicated connections of the subscriptions
 }
 
 // Redis commands
@@ -66,18 +68,64 @@ const (
 	redis_HGET     = "HGET"
 	redis_RENAME   = "RENAME"
 	redis_HMSET    = "HMSET"
+	redis_PUBLISH  = "PUBLISH"
+
+	redis_NX = "NX"
+	redis_PX = "PX"
+)
+
+// the shared sessions are stored as hashes with the expiry in milliseconds so it can be compared inside the scripts
+var (
+	// stores the session if not owned by another node with an active lease
+	redisSetSharedSessionScript = radix.NewEvalScript(1, `local owner = redis.call("hget", KEYS[1], "NodeID")
+if owner and owner ~= ARGV[1] and tonumber(redis.call("hget", KEYS[1], "Expiry")) > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call("hset", KEYS[1], "NodeID", ARGV[1], "Expiry", ARGV[3], "Session", ARGV[4])
+return 1`)
+	// moves the ownership if the lease expired, returning the session
+	redisClaimSharedSessionScript = radix.NewEvalScript(1, `local expiry = redis.call("hget", KEYS[1], "Expiry")
+if not expiry or tonumber(expiry) > tonumber(ARGV[2]) then
+	return false
+end
+redis.call("hset", KEYS[1], "NodeID", ARGV[1], "Expiry", ARGV[3])
+return redis.call("hget", KEYS[1], "Session")`)
+	// removes the session only if owned by the node
+	redisRemoveSharedSessionScript = radix.NewEvalScript(1, `if redis.call("hget", KEYS[1], "NodeID") == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+	// stores the config section only if the stored version is the previous one
+	redisSetConfigSectionScript = radix.NewEvalScript(1, `if tonumber(redis.call("hget", KEYS[1], "Version") or "0") ~= tonumber(ARGV[1]) - 1 then
+	return 0
+end
+redis.call("hset", KEYS[1], "Version", ARGV[1], "Section", ARGV[2])
+return 1`)
 )
 
 func NewRedisStorage(address string, db int, user, pass, mrshlerStr string,
 	maxConns int, sentinelName string, isCluster bool, clusterSync,
 	clusterOnDownDelay time.Duration, tlsConn bool,
 	tlsClientCert, tlsClientKey, tlsCACert string) (rs *RedisStorage, err error) {
-	rs = new(RedisStorage)
+	rs = &RedisStorage{address: address}
 	if rs.ms, err = NewMarshaler(mrshlerStr); err != nil {
 		rs = nil
 		return
 	}
+	if rs.client, rs.dial, err = newRedisClient(address, db, user, pass, maxConns, sentinelName,
+		isCluster, clusterSync, clusterOnDownDelay, tlsConn,
+		tlsClientCert, tlsClientKey, tlsCACert); err != nil {
+		rs = nil
+	}
+	return
+}
 
+// newRedisClient connects to Redis as standalone, over sentinel or as cluster
+// authDial connects without selecting the DB, for the connections outside of the client(ie: subscriptions)
+func newRedisClient(address string, db int, user, pass string,
+	maxConns int, sentinelName string, isCluster bool, clusterSync,
+	clusterOnDownDelay time.Duration, tlsConn bool,
+	tlsClientCert, tlsClientKey, tlsCACert string) (client radix.Client, authDial radix.ConnFunc, err error) {
 	dialOpts := []radix.DialOpt{
 		radix.DialSelectDB(db),
 	}
@@ -126,34 +174,25 @@ func NewRedisStorage(address string, db int, user, pass, mrshlerStr string,
 	dialFuncAuthOnly := func(network, addr string) (radix.Conn, error) {
 		return radix.Dial(network, addr, dialOpts[1:]...)
 	}
+	authDial = dialFuncAuthOnly
 	switch {
 	case isCluster:
-		if rs.client, err = radix.NewCluster(utils.InfieldSplit(address),
+		client, err = radix.NewCluster(utils.InfieldSplit(address),
 			radix.ClusterSyncEvery(clusterSync),
 			radix.ClusterOnDownDelayActionsBy(clusterOnDownDelay),
 			radix.ClusterPoolFunc(func(network, addr string) (radix.Client, error) {
 				// in cluster enviorment do not select the DB as we expect to have only one DB
 				return radix.NewPool(network, addr, maxConns, radix.PoolConnFunc(dialFuncAuthOnly))
-			})); err != nil {
-			rs = nil
-			return
-		}
+			}))
 	case sentinelName != utils.EmptyString:
-		if rs.client, err = radix.NewSentinel(sentinelName, utils.InfieldSplit(address),
+		client, err = radix.NewSentinel(sentinelName, utils.InfieldSplit(address),
 			radix.SentinelConnFunc(dialFuncAuthOnly),
 			radix.SentinelPoolFunc(func(network, addr string) (radix.Client, error) {
 				return radix.NewPool(network, addr, maxConns, radix.PoolConnFunc(dialFunc))
-			})); err != nil {
-			rs = nil
-			return
-		}
+			}))
 	default:
-		if rs.client, err = radix.NewPool(utils.TCP, address, maxConns, radix.PoolConnFunc(dialFunc)); err != nil {
-			rs = nil
-			return
-		}
+		client, err = radix.NewPool(utils.TCP, address, maxConns, radix.PoolConnFunc(dialFunc))
 	}
-
 	return
 }
 
@@ -1200,6 +1239,30 @@ func (rs *RedisStorage) RemoveDispatcherHostDrv(tenant, id string) (err error) {
 	return rs.Cmd(nil, redis_DEL, utils.DispatcherHostPrefix+utils.ConcatenatedKey(tenant, id))
 }
 
+func (rs *RedisStorage) GetPortedNumberDrv(tenant, id string) (pn *PortedNumber, err error) {
+	var values []byte
+	if err = rs.Cmd(&values, redis_GET, utils.PortedNumberPrefix+utils.ConcatenatedKey(tenant, id)); err != nil {
+		return
+	} else if len(values) == 0 {
+		err = utils.ErrNotFound
+		return
+	}
+	err = rs.ms.Unmarshal(values, &pn)
+	return
+}
+
+func (rs *RedisStorage) SetPortedNumberDrv(pn *PortedNumber) (err error) {
+	var result []byte
+	if result, err = rs.ms.Marshal(pn); err != nil {
+		return
+	}
+	return rs.Cmd(nil, redis_SET, utils.PortedNumberPrefix+utils.ConcatenatedKey(pn.Tenant, pn.ID), string(result))
+}
+
+func (rs *RedisStorage) RemovePortedNumberDrv(tenant, id string) (err error) {
+	return rs.Cmd(nil, redis_DEL, utils.PortedNumberPrefix+utils.ConcatenatedKey(tenant, id))
+}
+
 func (rs *RedisStorage) GetStorageType() string {
 	return utils.Redis
 }
@@ -1383,3 +1446,205 @@ func (rs *RedisStorage) SetAccountProfileDrv(ap *utils.AccountProfile) (err erro
 func (rs *RedisStorage) RemoveAccountProfileDrv(tenant, id string) (err error) {
 	return rs.Cmd(nil, redis_DEL, utils.AccountProfilePrefix+utils.ConcatenatedKey(tenant, id))
 }
+
+// SetDedupIDDrv stores the ID for ttl, returning utils.ErrExists if it is already stored
+func (rs *RedisStorage) SetDedupIDDrv(id string, ttl time.Duration) (err error) {
+	var rply string
+	if err = rs.Cmd(&rply, redis_SET, utils.DedupIDPrefix+id, utils.EmptyString,
+		redis_NX, redis_PX, strconv.FormatInt(ttl.Milliseconds(), 10)); err != nil {
+		return
+	}
+	if rply == utils.EmptyString { // nil reply means the key was not set
+		return utils.ErrExists
+	}
+	return
+}
+
+func (rs *RedisStorage) RemoveDedupIDDrv(id string) (err error) {
+	return rs.Cmd(nil, redis_DEL, utils.DedupIDPrefix+id)
+}
+
+func (rs *RedisStorage) GetSharedSessionsDrv() (sss []*SharedSession, err error) {
+	var keys []string
+	if keys, err = rs.GetKeysForPrefix(utils.SharedSessionPrefix); err != nil {
+		return
+	}
+	for _, key := range keys {
+		var mp map[string]string
+		if err = rs.Cmd(&mp, redis_HGETALL, key); err != nil {
+			return
+		}
+		if len(mp) == 0 { // removed in the meantime
+			continue
+		}
+		var expiry int64
+		if expiry, err = strconv.ParseInt(mp["Expiry"], 10, 64); err != nil {
+			return
+		}
+		sss = append(sss, &SharedSession{
+			CGRID:   key[len(utils.SharedSessionPrefix):],
+			NodeID:  mp["NodeID"],
+			Expiry:  time.Unix(0, expiry*int64(time.Millisecond)),
+			Session: []byte(mp["Session"]),
+		})
+	}
+	return
+}
+
+// SetSharedSessionDrv stores the session, returning utils.ErrExists if owned by another node with an active lease
+func (rs *RedisStorage) SetSharedSessionDrv(ss *SharedSession) (err error) {
+	var set int
+	if err = rs.client.Do(redisSetSharedSessionScript.Cmd(&set, utils.SharedSessionPrefix+ss.CGRID,
+		ss.NodeID, strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10),
+		strconv.FormatInt(ss.Expiry.UnixNano()/int64(time.Millisecond), 10), string(ss.Session))); err != nil {
+		return
+	}
+	if set == 0 {
+		return utils.ErrExists
+	}
+	return
+}
+
+// ClaimSharedSessionDrv takes the ownership of an expired session, returning utils.ErrNotFound otherwise
+func (rs *RedisStorage) ClaimSharedSessionDrv(cgrID, nodeID string, expiry time.Time) (ss *SharedSession, err error) {
+	var sess []byte
+	mn := radix.MaybeNil{Rcv: &sess}
+	if err = rs.client.Do(redisClaimSharedSessionScript.Cmd(&mn, utils.SharedSessionPrefix+cgrID,
+		nodeID, strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10),
+		strconv.FormatInt(expiry.UnixNano()/int64(time.Millisecond), 10))); err != nil {
+		return
+	}
+	if mn.Nil {
+		return nil, utils.ErrNotFound
+	}
+	return &SharedSession{
+		CGRID:   cgrID,
+		NodeID:  nodeID,
+		Expiry:  expiry,
+		Session: sess,
+	}, nil
+}
+
+func (rs *RedisStorage) RemoveSharedSessionDrv(cgrID, nodeID string) (err error) {
+	return rs.client.Do(redisRemoveSharedSessionScript.Cmd(nil, utils.SharedSessionPrefix+cgrID, nodeID))
+}
+
+func (rs *RedisStorage) GetConfigSectionsDrv() (cfgSects []*ConfigSection, err error) {
+	var keys []string
+	if keys, err = rs.GetKeysForPrefix(utils.ConfigSectionPrefix); err != nil {
+		return
+	}
+	for _, key := range keys {
+		var cfgSect *ConfigSection
+		if cfgSect, err = rs.GetConfigSectionDrv(key[len(utils.ConfigSectionPrefix):]); err != nil {
+			if err == utils.ErrNotFound { // removed in the meantime
+				err = nil
+				continue
+			}
+			return
+		}
+		cfgSects = append(cfgSects, cfgSect)
+	}
+	return
+}
+
+func (rs *RedisStorage) GetConfigSectionDrv(section string) (cfgSect *ConfigSection, err error) {
+	var values []byte
+	if err = rs.Cmd(&values, redis_HGET, utils.ConfigSectionPrefix+section, "Section"); err != nil {
+		return
+	} else if len(values) == 0 {
+		err = utils.ErrNotFound
+		return
+	}
+	err = rs.ms.Unmarshal(values, &cfgSect)
+	return
+}
+
+// SetConfigSectionDrv stores the section if the stored version is the previous one, returning utils.ErrExists otherwise
+// the sections are stored as hashes with the version apart so it can be compared inside the script
+func (rs *RedisStorage) SetConfigSectionDrv(cfgSect *ConfigSection) (err error) {
+	var result []byte
+	if result, err = rs.ms.Marshal(cfgSect); err != nil {
+		return
+	}
+	var set int
+	if err = rs.client.Do(redisSetConfigSectionScript.Cmd(&set, utils.ConfigSectionPrefix+cfgSect.Section,
+		strconv.FormatInt(cfgSect.Version, 10), string(result))); err != nil {
+		return
+	}
+	if set == 0 {
+		return utils.ErrExists
+	}
+	return
+}
+
+func (rs *RedisStorage) RemoveConfigSectionDrv(section string) (err error) {
+	var n int
+	if err = rs.Cmd(&n, redis_DEL, utils.ConfigSectionPrefix+section); err != nil {
+		return
+	}
+	if n == 0 {
+		return utils.ErrNotFound
+	}
+	return
+}
+
+// PublishConfigSections notifies the subscribed engines that the config sections changed
+func (rs *RedisStorage) PublishConfigSections() (err error) {
+	return rs.Cmd(nil, redis_PUBLISH, utils.ConfigDBChannel, utils.EmptyString)
+}
+
+// SubscribeConfigSections returns the channel notified on the changes of the config sections until stopChan is closed
+// the notifications received while the previous one is not consumed are merged
+func (rs *RedisStorage) SubscribeConfigSections(stopChan <-chan struct{}) (notifyChan <-chan struct{}, err error) {
+	var ps radix.PubSubConn
+	if ps, err = radix.PersistentPubSubWithOpts(utils.TCP, rs.address,
+		radix.PersistentPubSubConnFunc(rs.pubSubConn)); err != nil {
+		return
+	}
+	msgChan := make(chan radix.PubSubMessage)
+	if err = ps.Subscribe(msgChan, utils.ConfigDBChannel); err != nil {
+		ps.Close()
+		return
+	}
+	nChan := make(chan struct{}, 1)
+	go func() {
+		for {
+			select {
+			case <-stopChan:
+				closed := make(chan struct{})
+				go func() {
+					ps.Close()
+					close(closed)
+				}()
+				for { // keep reading so the connection is not blocked while closing
+					select {
+					case <-msgChan:
+					case <-closed:
+						return
+					}
+				}
+			case <-msgChan:
+				select {
+				case nChan <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return nChan, nil
+}
+
+// pubSubConn dials the connection of the subscriptions
+// on sentinel the current primary is used, on cluster any node since the messages are sent to all of them
+func (rs *RedisStorage) pubSubConn(network, addr string) (radix.Conn, error) {
+	switch client := rs.client.(type) {
+	case *radix.Sentinel:
+		addr, _ = client.Addrs()
+	case *radix.Cluster:
+		if topo := client.Topo(); len(topo) != 0 {
+			addr = topo[0].Addr
+		}
+	}
+	return rs.dial(network, addr)
+}
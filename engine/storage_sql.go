@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,6 +38,9 @@ type SQLImpl interface {
 	extraFieldsValueQry(string, string) string
 	notExtraFieldsExistsQry(string) string
 	notExtraFieldsValueQry(string, string) string
+	timeGroupQry(string, string) string
+	extraFieldQry(string) string
+	extraFieldSumQry(string) string
 }
 
 type SQLStorage struct {
@@ -105,7 +109,7 @@ func (sqls *SQLStorage) IsDBEmpty() (resp bool, err error) {
 		utils.TBLTPAccountActions, utils.TBLTPResources, utils.TBLTPStats, utils.TBLTPThresholds,
 		utils.TBLTPFilters, utils.SessionCostsTBL, utils.CDRsTBL, utils.TBLTPActionPlans,
 		utils.TBLVersions, utils.TBLTPRoutes, utils.TBLTPAttributes, utils.TBLTPChargers,
-		utils.TBLTPDispatchers, utils.TBLTPDispatcherHosts,
+		utils.TBLTPDispatchers, utils.TBLTPDispatcherHosts, utils.AuditLogTBL, utils.InvoicesTBL,
 	}
 	for _, tbl := range tbls {
 		if sqls.db.Migrator().HasTable(tbl) {
@@ -936,6 +940,164 @@ func (sqls *SQLStorage) GetSMCosts(cgrid, runid, originHost, originIDPrefix stri
 	return smCosts, nil
 }
 
+// SetAuditEntry records one audit entry in the audit_log table
+func (sqls *SQLStorage) SetAuditEntry(ae *AuditEntry) error {
+	tx := sqls.db.Begin()
+	al := &AuditLogSQL{
+		AuditID:        ae.ID,
+		Method:         ae.Method,
+		Subject:        ae.Subject,
+		Role:           ae.Role,
+		RemoteAddr:     ae.RemoteAddr,
+		Tenant:         ae.Tenant,
+		ItemID:         ae.ItemID,
+		Args:           ae.Args,
+		BeforeSnapshot: ae.Before,
+		AfterSnapshot:  ae.After,
+		Diff:           utils.ToJSON(ae.Diff),
+		Error:          ae.Error,
+		CreatedAt:      ae.Time,
+	}
+	if err := tx.Save(al).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// GetAuditEntries returns the audit entries matching the filter, ordered by time
+func (sqls *SQLStorage) GetAuditEntries(qryFltr *utils.AuditLogFilter) (aes []*AuditEntry, err error) {
+	q := sqls.db.Table(utils.AuditLogTBL)
+	if len(qryFltr.Methods) != 0 {
+		q = q.Where("method in (?)", qryFltr.Methods)
+	}
+	if len(qryFltr.Subjects) != 0 {
+		q = q.Where("subject in (?)", qryFltr.Subjects)
+	}
+	if len(qryFltr.Tenants) != 0 {
+		q = q.Where("tenant in (?)", qryFltr.Tenants)
+	}
+	if len(qryFltr.ItemIDs) != 0 {
+		q = q.Where("item_id in (?)", qryFltr.ItemIDs)
+	}
+	if qryFltr.Time.Begin != nil {
+		q = q.Where("created_at >= ?", qryFltr.Time.Begin)
+	}
+	if qryFltr.Time.End != nil {
+		q = q.Where("created_at < ?", qryFltr.Time.End)
+	}
+	if qryFltr.Paginator.Limit != nil {
+		q = q.Limit(*qryFltr.Paginator.Limit)
+	}
+	if qryFltr.Paginator.Offset != nil {
+		q = q.Offset(*qryFltr.Paginator.Offset)
+	}
+	results := make([]*AuditLogSQL, 0)
+	if err = q.Order("created_at").Find(&results).Error; err != nil {
+		return
+	}
+	if len(results) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	aes = make([]*AuditEntry, len(results))
+	for i, result := range results {
+		aes[i] = &AuditEntry{
+			ID:         result.AuditID,
+			Time:       result.CreatedAt,
+			Method:     result.Method,
+			Subject:    result.Subject,
+			Role:       result.Role,
+			RemoteAddr: result.RemoteAddr,
+			Tenant:     result.Tenant,
+			ItemID:     result.ItemID,
+			Args:       result.Args,
+			Before:     result.BeforeSnapshot,
+			After:      result.AfterSnapshot,
+			Error:      result.Error,
+		}
+		if err = json.Unmarshal([]byte(result.Diff), &aes[i].Diff); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// SetInvoice records one invoice in the invoices table
+func (sqls *SQLStorage) SetInvoice(inv *Invoice) error {
+	tx := sqls.db.Begin()
+	invSQL := &InvoiceSQL{
+		Tenant:     inv.Tenant,
+		InvoiceID:  inv.ID,
+		Number:     inv.Number,
+		Account:    inv.Account,
+		CycleStart: inv.CycleStart,
+		CycleEnd:   inv.CycleEnd,
+		LineItems:  utils.ToJSON(inv.Lines),
+		Total:      inv.Total,
+		CreatedAt:  inv.CreatedAt,
+	}
+	if err := tx.Save(invSQL).Error; err != nil {
+		tx.Rollback()
+		if strings.Contains(err.Error(), "1062") || strings.Contains(err.Error(), "duplicate key") { // the number or the cycle of the account were taken meanwhile
+			return utils.ErrExists
+		}
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// GetInvoices returns the invoices matching the filter, the last numbered first
+func (sqls *SQLStorage) GetInvoices(qryFltr *utils.InvoicesFilter) (invs []*Invoice, err error) {
+	q := sqls.db.Table(utils.InvoicesTBL)
+	if len(qryFltr.IDs) != 0 {
+		q = q.Where("invoice_id in (?)", qryFltr.IDs)
+	}
+	if len(qryFltr.Tenants) != 0 {
+		q = q.Where("tenant in (?)", qryFltr.Tenants)
+	}
+	if len(qryFltr.Accounts) != 0 {
+		q = q.Where("account in (?)", qryFltr.Accounts)
+	}
+	if qryFltr.Cycle.Begin != nil {
+		q = q.Where("cycle_end > ?", qryFltr.Cycle.Begin)
+	}
+	if qryFltr.Cycle.End != nil {
+		q = q.Where("cycle_start < ?", qryFltr.Cycle.End)
+	}
+	if qryFltr.Paginator.Limit != nil {
+		q = q.Limit(*qryFltr.Paginator.Limit)
+	}
+	if qryFltr.Paginator.Offset != nil {
+		q = q.Offset(*qryFltr.Paginator.Offset)
+	}
+	results := make([]*InvoiceSQL, 0)
+	if err = q.Order("number desc, tenant").Find(&results).Error; err != nil {
+		return
+	}
+	if len(results) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	invs = make([]*Invoice, len(results))
+	for i, result := range results {
+		invs[i] = &Invoice{
+			Tenant:     result.Tenant,
+			ID:         result.InvoiceID,
+			Number:     result.Number,
+			Account:    result.Account,
+			CycleStart: result.CycleStart,
+			CycleEnd:   result.CycleEnd,
+			CreatedAt:  result.CreatedAt,
+			Total:      result.Total,
+		}
+		if err = json.Unmarshal([]byte(result.LineItems), &invs[i].Lines); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
 func (sqls *SQLStorage) SetCDR(cdr *CDR, allowUpdate bool) error {
 	tx := sqls.db.Begin()
 	cdrSQL := cdr.AsCDRsql()
@@ -962,10 +1124,9 @@ func (sqls *SQLStorage) SetCDR(cdr *CDR, allowUpdate bool) error {
 	return nil
 }
 
-// GetCDRs has ability to remove the selected CDRs, count them or simply return them
-// qryFltr.Unscoped will ignore soft deletes or delete records permanently
-func (sqls *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR, int64, error) {
-	var cdrs []*CDR
+// cdrsQuery returns the query selecting the CDRs matching the filter
+// qryFltr.Unscoped will ignore soft deletes
+func (sqls *SQLStorage) cdrsQuery(qryFltr *utils.CDRsFilter) (*gorm.DB, error) {
 	q := sqls.db.Table(utils.CDRsTBL)
 	if qryFltr.Unscoped {
 		q = q.Unscoped()
@@ -1129,36 +1290,10 @@ func (sqls *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	if qryFltr.UpdatedAtEnd != nil && !qryFltr.UpdatedAtEnd.IsZero() {
 		q = q.Where("updated_at < ?", qryFltr.UpdatedAtEnd)
 	}
-	if qryFltr.OrderBy != "" {
-		var orderVal string
-		separateVals := strings.Split(qryFltr.OrderBy, utils.InfieldSep)
-		switch separateVals[0] {
-		case utils.OrderID:
-			orderVal = "id"
-		case utils.AnswerTime:
-			orderVal = "answer_time"
-		case utils.SetupTime:
-			orderVal = "setup_time"
-		case utils.Usage:
-			if sqls.db.Dialector.Name() == utils.MySQL {
-				orderVal = "`usage`"
-			} else {
-				orderVal = "usage"
-			}
-		case utils.Cost:
-			orderVal = "cost"
-		default:
-			return nil, 0, fmt.Errorf("Invalid value : %s", separateVals[0])
-		}
-		if len(separateVals) == 2 && separateVals[1] == "desc" {
-			orderVal += " DESC"
-		}
-		q = q.Order(orderVal)
-	}
 	if len(qryFltr.MinUsage) != 0 {
 		minUsage, err := utils.ParseDurationWithNanosecs(qryFltr.MinUsage)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		if sqls.db.Dialector.Name() == utils.MySQL { // MySQL needs escaping for usage
 			q = q.Where("`usage` >= ?", minUsage.Nanoseconds())
@@ -1169,7 +1304,7 @@ func (sqls *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	if len(qryFltr.MaxUsage) != 0 {
 		maxUsage, err := utils.ParseDurationWithNanosecs(qryFltr.MaxUsage)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		if sqls.db.Dialector.Name() == utils.MySQL { // MySQL needs escaping for usage
 			q = q.Where("`usage` < ?", maxUsage.Nanoseconds())
@@ -1194,6 +1329,43 @@ func (sqls *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 			q = q.Where(fmt.Sprintf("( cost IS NULL OR cost < %f )", *qryFltr.MaxCost))
 		}
 	}
+	return q, nil
+}
+
+// GetCDRs has ability to remove the selected CDRs, count them or simply return them
+// qryFltr.Unscoped will ignore soft deletes or delete records permanently
+func (sqls *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR, int64, error) {
+	var cdrs []*CDR
+	q, err := sqls.cdrsQuery(qryFltr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if qryFltr.OrderBy != "" {
+		var orderVal string
+		separateVals := strings.Split(qryFltr.OrderBy, utils.InfieldSep)
+		switch separateVals[0] {
+		case utils.OrderID:
+			orderVal = "id"
+		case utils.AnswerTime:
+			orderVal = "answer_time"
+		case utils.SetupTime:
+			orderVal = "setup_time"
+		case utils.Usage:
+			if sqls.db.Dialector.Name() == utils.MySQL {
+				orderVal = "`usage`"
+			} else {
+				orderVal = "usage"
+			}
+		case utils.Cost:
+			orderVal = "cost"
+		default:
+			return nil, 0, fmt.Errorf("Invalid value : %s", separateVals[0])
+		}
+		if len(separateVals) == 2 && separateVals[1] == "desc" {
+			orderVal += " DESC"
+		}
+		q = q.Order(orderVal)
+	}
 	if qryFltr.Paginator.Limit != nil {
 		q = q.Limit(*qryFltr.Paginator.Limit)
 	}
@@ -1233,6 +1405,122 @@ func (sqls *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	return cdrs, 0, nil
 }
 
+// cdrsSQLColumns are the columns of the fields grouping the CDRs
+var cdrsSQLColumns = map[string]string{
+	utils.Tenant:       "tenant",
+	utils.RunID:        "run_id",
+	utils.ToR:          "tor",
+	utils.RequestType:  "request_type",
+	utils.Category:     "category",
+	utils.AccountField: "account",
+	utils.Subject:      "subject",
+	utils.OriginHost:   "origin_host",
+	utils.Source:       "source",
+	utils.CostSource:   "cost_source",
+	utils.Destination:  "destination",
+	utils.SetupTime:    "setup_time",
+	utils.AnswerTime:   "answer_time",
+}
+
+// GetCDRsAggregates groups the CDRs matching the filter using GROUP BY
+func (sqls *SQLStorage) GetCDRsAggregates(qryFltr *utils.CDRsFilter, grps []*utils.CDRsGroupBy,
+	sumFlds []string) (aggrs []*utils.CDRsAggregate, err error) {
+	var q *gorm.DB
+	if q, err = sqls.cdrsQuery(qryFltr); err != nil {
+		return
+	}
+	usageCol := "usage"
+	if sqls.db.Dialector.Name() == utils.MySQL { // MySQL needs escaping for usage
+		usageCol = "`usage`"
+	}
+	slct := make([]string, 0, len(grps)+len(sumFlds)+7)
+	grpCols := make([]string, len(grps))
+	for i, grp := range grps {
+		col := cdrsSQLColumns[grp.Field]
+		if grp.ExtraField {
+			col = sqls.SQLImpl.extraFieldQry(grp.Field)
+		}
+		if grp.Length != 0 {
+			col = fmt.Sprintf("SUBSTR(%s, 1, %d)", col, grp.Length)
+		} else if grp.Period != utils.EmptyString {
+			col = sqls.SQLImpl.timeGroupQry(col, grp.Period)
+		}
+		grpCols[i] = fmt.Sprintf("grp%d", i)
+		slct = append(slct, col+" AS "+grpCols[i])
+	}
+	slct = append(slct, "COUNT(*)",
+		"SUM("+usageCol+")", "MIN("+usageCol+")", "MAX("+usageCol+")",
+		// the unrated CDRs(cost -1) are left out of the cost aggregates
+		"SUM(CASE WHEN cost <> -1 THEN cost END)", "MIN(CASE WHEN cost <> -1 THEN cost END)",
+		"MAX(CASE WHEN cost <> -1 THEN cost END)")
+	for _, fld := range sumFlds {
+		slct = append(slct, sqls.SQLImpl.extraFieldSumQry(fld))
+	}
+	q = q.Select(strings.Join(slct, ", ")).
+		Group(strings.Join(grpCols, ", ")).
+		Order(strings.Join(grpCols, ", "))
+	if qryFltr.Paginator.Limit != nil {
+		q = q.Limit(*qryFltr.Paginator.Limit)
+	}
+	if qryFltr.Paginator.Offset != nil {
+		q = q.Offset(*qryFltr.Paginator.Offset)
+	}
+	rows, err := q.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		grpVals := make([]sql.NullString, len(grps))
+		var usage, minUsage, maxUsage sql.NullString // the sums of the usage may overflow the float64 precision
+		var cost, minCost, maxCost sql.NullFloat64
+		aggr := &utils.CDRsAggregate{Group: make(map[string]string)}
+		sums := make([]sql.NullFloat64, len(sumFlds))
+		dest := make([]interface{}, 0, len(grps)+len(sumFlds)+7)
+		for i := range grpVals {
+			dest = append(dest, &grpVals[i])
+		}
+		dest = append(dest, &aggr.Count, &usage, &minUsage, &maxUsage, &cost, &minCost, &maxCost)
+		for i := range sums {
+			dest = append(dest, &sums[i])
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, grp := range grps {
+			aggr.Group[grp.Key] = grpVals[i].String
+		}
+		for _, dur := range []struct {
+			val *sql.NullString
+			rcv *time.Duration
+		}{{&usage, &aggr.Usage}, {&minUsage, &aggr.MinUsage}, {&maxUsage, &aggr.MaxUsage}} {
+			if !dur.val.Valid {
+				continue
+			}
+			var nanos int64
+			if nanos, err = strconv.ParseInt(dur.val.String, 10, 64); err != nil {
+				return nil, err
+			}
+			*dur.rcv = time.Duration(nanos)
+		}
+		aggr.Cost, aggr.MinCost, aggr.MaxCost = cost.Float64, minCost.Float64, maxCost.Float64
+		if len(sumFlds) != 0 {
+			aggr.Sums = make(map[string]float64, len(sumFlds))
+			for i, fld := range sumFlds {
+				aggr.Sums[fld] = sums[i].Float64
+			}
+		}
+		aggrs = append(aggrs, aggr)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(aggrs) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
 func (sqls *SQLStorage) GetTPDestinations(tpid, id string) (uTPDsts []*utils.TPDestination, err error) {
 	var tpDests DestinationMdls
 	q := sqls.db.Where("tpid = ?", tpid)
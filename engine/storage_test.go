@@ -18,10 +18,12 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/cgrates/cgrates/utils"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func TestMsgpackStructsAdded(t *testing.T) {
@@ -362,3 +364,22 @@ func BenchmarkMarshallerBincStoreRestore(b *testing.B) {
 		ms.Unmarshal(result, ub1)
 	}
 }
+
+func TestIsMongoDuplicateKey(t *testing.T) {
+	if !isMongoDuplicateKey(mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000,
+		Message: "E11000 duplicate key error collection"}}}) {
+		t.Error("Expected duplicate key for the write exception")
+	}
+	if !isMongoDuplicateKey(mongo.CommandError{Code: 11000}) {
+		t.Error("Expected duplicate key for the command error")
+	}
+	if isMongoDuplicateKey(mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 121}}}) {
+		t.Error("Unexpected duplicate key for the validation error")
+	}
+	if isMongoDuplicateKey(errors.New("E11000 duplicate key error collection")) {
+		t.Error("Unexpected duplicate key for the error not returned by Mongo")
+	}
+	if isMongoDuplicateKey(nil) {
+		t.Error("Unexpected duplicate key for no error")
+	}
+}
@@ -25,45 +25,83 @@ import (
 	"time"
 
 	"github.com/cgrates/cgrates/utils"
+	"github.com/mediocregopher/radix/v3"
 )
 
 // Various helpers to deal with database
 
+// redisOpts are the Redis connection options out of the DataDB config
+type redisOpts struct {
+	address            string
+	dbNo               int
+	sentinelName       string
+	isCluster          bool
+	clusterSync        time.Duration
+	clusterOnDownDelay time.Duration
+	tlsConn            bool
+	tlsClientCert      string
+	tlsClientKey       string
+	tlsCACert          string
+}
+
+func newRedisOpts(host, port, name string, opts map[string]interface{}) (rdsOpts *redisOpts, err error) {
+	rdsOpts = &redisOpts{
+		address:       host,
+		sentinelName:  utils.IfaceAsString(opts[utils.RedisSentinelNameCfg]),
+		tlsClientCert: utils.IfaceAsString(opts[utils.RedisClientCertificate]),
+		tlsClientKey:  utils.IfaceAsString(opts[utils.RedisClientKey]),
+		tlsCACert:     utils.IfaceAsString(opts[utils.RedisCACertificate]),
+	}
+	if rdsOpts.dbNo, err = strconv.Atoi(name); err != nil {
+		utils.Logger.Crit("Redis db name must be an integer!")
+		return nil, err
+	}
+	if port != "" && strings.Index(host, ":") == -1 {
+		rdsOpts.address += ":" + port
+	}
+	if rdsOpts.isCluster, err = utils.IfaceAsBool(opts[utils.RedisClusterCfg]); err != nil {
+		return nil, err
+	}
+	if rdsOpts.clusterSync, err = utils.IfaceAsDuration(opts[utils.RedisClusterSyncCfg]); err != nil {
+		return nil, err
+	}
+	if rdsOpts.clusterOnDownDelay, err = utils.IfaceAsDuration(opts[utils.RedisClusterOnDownDelayCfg]); err != nil {
+		return nil, err
+	}
+	if rdsOpts.tlsConn, err = utils.IfaceAsBool(opts[utils.RedisTLS]); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// NewRedisDataDBClient connects to the Redis DataDB with the same options as NewDataDBConn
+// used by the components sharing the DataDB outside of the DataManager(ie: the distributed locks)
+func NewRedisDataDBClient(host, port, name, user, pass string,
+	opts map[string]interface{}) (client radix.Client, err error) {
+	var rdsOpts *redisOpts
+	if rdsOpts, err = newRedisOpts(host, port, name, opts); err != nil {
+		return
+	}
+	client, _, err = newRedisClient(rdsOpts.address, rdsOpts.dbNo, user, pass,
+		utils.RedisMaxConns, rdsOpts.sentinelName,
+		rdsOpts.isCluster, rdsOpts.clusterSync, rdsOpts.clusterOnDownDelay, rdsOpts.tlsConn,
+		rdsOpts.tlsClientCert, rdsOpts.tlsClientKey, rdsOpts.tlsCACert)
+	return
+}
+
 // NewDataDBConn creates a DataDB connection
 func NewDataDBConn(dbType, host, port, name, user,
 	pass, marshaler string, opts map[string]interface{}) (d DataDB, err error) {
 	switch dbType {
 	case utils.Redis:
-		var dbNo int
-		dbNo, err = strconv.Atoi(name)
-		if err != nil {
-			utils.Logger.Crit("Redis db name must be an integer!")
-			return
-		}
-		if port != "" && strings.Index(host, ":") == -1 {
-			host += ":" + port
-		}
-		var isCluster bool
-		if isCluster, err = utils.IfaceAsBool(opts[utils.RedisClusterCfg]); err != nil {
-			return
-		}
-		var clusterSync, clusterOnDownDelay time.Duration
-		if clusterSync, err = utils.IfaceAsDuration(opts[utils.RedisClusterSyncCfg]); err != nil {
-			return
-		}
-		if clusterOnDownDelay, err = utils.IfaceAsDuration(opts[utils.RedisClusterOnDownDelayCfg]); err != nil {
+		var rdsOpts *redisOpts
+		if rdsOpts, err = newRedisOpts(host, port, name, opts); err != nil {
 			return
 		}
-		var hasTlsConn bool
-		if hasTlsConn, err = utils.IfaceAsBool(opts[utils.RedisTLS]); err != nil {
-			return
-		}
-		d, err = NewRedisStorage(host, dbNo, user, pass, marshaler,
-			utils.RedisMaxConns, utils.IfaceAsString(opts[utils.RedisSentinelNameCfg]),
-			isCluster, clusterSync, clusterOnDownDelay, hasTlsConn,
-			utils.IfaceAsString(opts[utils.RedisClientCertificate]),
-			utils.IfaceAsString(opts[utils.RedisClientKey]),
-			utils.IfaceAsString(opts[utils.RedisCACertificate]))
+		d, err = NewRedisStorage(rdsOpts.address, rdsOpts.dbNo, user, pass, marshaler,
+			utils.RedisMaxConns, rdsOpts.sentinelName,
+			rdsOpts.isCluster, rdsOpts.clusterSync, rdsOpts.clusterOnDownDelay, rdsOpts.tlsConn,
+			rdsOpts.tlsClientCert, rdsOpts.tlsClientKey, rdsOpts.tlsCACert)
 	case utils.Mongo:
 		var ttl time.Duration
 		if ttl, err = utils.IfaceAsDuration(opts[utils.QueryTimeoutCfg]); err != nil {
@@ -71,7 +109,7 @@ func NewDataDBConn(dbType, host, port, name, user,
 		}
 		d, err = NewMongoStorage(host, port, name, user, pass, marshaler, utils.DataDB, nil, ttl)
 	case utils.INTERNAL:
-		d = NewInternalDB(nil, nil, true)
+		d, err = newInternalDBFromOpts(nil, nil, true, opts)
 	default:
 		err = fmt.Errorf("unsupported db_type <%s>", dbType)
 	}
@@ -115,7 +153,7 @@ func NewStorDBConn(dbType, host, port, name, user, pass, marshaler string,
 		}
 		db, err = NewMySQLStorage(host, port, name, user, pass, int(maxConn), int(maxIdleConn), int(connMaxLifetime))
 	case utils.INTERNAL:
-		db = NewInternalDB(stringIndexedFields, prefixIndexedFields, false)
+		db, err = newInternalDBFromOpts(stringIndexedFields, prefixIndexedFields, false, opts)
 	default:
 		err = fmt.Errorf("unknown db '%s' valid options are [%s, %s, %s, %s]",
 			dbType, utils.MySQL, utils.Mongo, utils.Postgres, utils.INTERNAL)
@@ -123,6 +161,24 @@ func NewStorDBConn(dbType, host, port, name, user, pass, marshaler string,
 	return
 }
 
+// newInternalDBFromOpts returns the InternalDB, persisted on disk if the dump path is configured
+func newInternalDBFromOpts(stringIndexedFields, prefixIndexedFields []string,
+	isDataDB bool, opts map[string]interface{}) (iDB *InternalDB, err error) {
+	dumpPath := utils.IfaceAsString(opts[utils.InternalDumpPathCfg])
+	if dumpPath == utils.EmptyString {
+		return NewInternalDB(stringIndexedFields, prefixIndexedFields, isDataDB), nil
+	}
+	var fsyncInterval, snapshotInterval time.Duration
+	if fsyncInterval, err = utils.IfaceAsDuration(opts[utils.InternalFsyncIntervalCfg]); err != nil {
+		return
+	}
+	if snapshotInterval, err = utils.IfaceAsDuration(opts[utils.InternalSnapshotIntervalCfg]); err != nil {
+		return
+	}
+	return NewInternalDBWithDump(stringIndexedFields, prefixIndexedFields, isDataDB, dumpPath,
+		utils.IfaceAsString(opts[utils.InternalFsyncCfg]), fsyncInterval, snapshotInterval)
+}
+
 // SMCost stores one Cost coming from SM
 type SMCost struct {
 	CGRID       string
@@ -0,0 +1,66 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestNewRedisOpts(t *testing.T) {
+	expected := &redisOpts{
+		address:            "127.0.0.1:6379",
+		dbNo:               10,
+		sentinelName:       "redis-cluster",
+		isCluster:          true,
+		clusterSync:        5 * time.Second,
+		clusterOnDownDelay: 10 * time.Millisecond,
+		tlsConn:            true,
+		tlsClientCert:      "client.crt",
+		tlsClientKey:       "client.key",
+		tlsCACert:          "ca.crt",
+	}
+	opts := map[string]interface{}{
+		utils.RedisSentinelNameCfg:       "redis-cluster",
+		utils.RedisClusterCfg:            true,
+		utils.RedisClusterSyncCfg:        "5s",
+		utils.RedisClusterOnDownDelayCfg: "10ms",
+		utils.RedisTLS:                   true,
+		utils.RedisClientCertificate:     "client.crt",
+		utils.RedisClientKey:             "client.key",
+		utils.RedisCACertificate:         "ca.crt",
+	}
+	if rcv, err := newRedisOpts("127.0.0.1", "6379", "10", opts); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, rcv) {
+		t.Errorf("Expected %+v, received %+v", expected, rcv)
+	}
+	// the port is not added if already in host
+	if rcv, err := newRedisOpts("127.0.0.1:6380", "6379", "10", opts); err != nil {
+		t.Error(err)
+	} else if rcv.address != "127.0.0.1:6380" {
+		t.Errorf("Expected 127.0.0.1:6380, received %s", rcv.address)
+	}
+	if _, err := newRedisOpts("127.0.0.1", "6379", "cgrates", opts); err == nil {
+		t.Error("Expected error for the DB name which is not a number")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"runtime"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,6 +30,9 @@ import (
 	"github.com/cgrates/cgrates/utils"
 )
 
+// actionProfileIDPrefix marks the ActionIDs executed as ActionProfiles by ActionS
+const actionProfileIDPrefix = utils.MetaActions + utils.InInFieldSep
+
 // ThresholdWithCache used in api to determine how to reload cache
 type ThresholdWithCache struct {
 	*ThresholdProfile
@@ -86,7 +90,9 @@ func (t *Threshold) TenantID() string {
 
 // ProcessEvent processes an ThresholdEvent
 // concurrentActions limits the number of simultaneous action sets executed
-func (t *Threshold) ProcessEvent(args *ThresholdsArgsProcessEvent, dm *DataManager) (err error) {
+// the ActionIDs prefixed with *actions: are executed as ActionProfiles over the actionSConns
+func (t *Threshold) ProcessEvent(args *ThresholdsArgsProcessEvent, dm *DataManager,
+	connMgr *ConnManager, actionSConns []string) (err error) {
 	if t.Snooze.After(time.Now()) || // snoozed, not executing actions
 		t.Hits < t.tPrfl.MinHits || // number of hits was not met, will not execute actions
 		(t.tPrfl.MaxHits != -1 &&
@@ -104,7 +110,12 @@ func (t *Threshold) ProcessEvent(args *ThresholdsArgsProcessEvent, dm *DataManag
 		tntAcnt = utils.ConcatenatedKey(args.Tenant, acnt)
 	}
 
+	var actPrfIDs []string
 	for _, actionSetID := range t.tPrfl.ActionIDs {
+		if strings.HasPrefix(actionSetID, actionProfileIDPrefix) {
+			actPrfIDs = append(actPrfIDs, strings.TrimPrefix(actionSetID, actionProfileIDPrefix))
+			continue
+		}
 		at := &ActionTiming{
 			Uuid:      utils.GenUUID(),
 			ActionsID: actionSetID,
@@ -124,6 +135,37 @@ func (t *Threshold) ProcessEvent(args *ThresholdsArgsProcessEvent, dm *DataManag
 			err = utils.ErrPartiallyExecuted
 		}
 	}
+	if len(actPrfIDs) == 0 {
+		return
+	}
+	if len(actionSConns) == 0 {
+		utils.Logger.Warning(fmt.Sprintf("<ThresholdS> no connection to ActionS for executing the ActionProfiles: %s",
+			utils.ToJSON(actPrfIDs)))
+		return utils.ErrPartiallyExecuted
+	}
+	actArgs := &utils.ArgActionSv1ScheduleActions{
+		CGREvent:         args.CGREvent,
+		ActionProfileIDs: actPrfIDs,
+	}
+	if t.tPrfl.Async {
+		actArgs.CGREvent = args.CGREvent.Clone()
+		go func() {
+			var rply string
+			if errExec := connMgr.Call(actionSConns, nil, utils.ActionSv1ExecuteActions,
+				actArgs, &rply); errExec != nil {
+				utils.Logger.Warning(fmt.Sprintf("<ThresholdS> failed executing the ActionProfiles: %s, error: %s",
+					utils.ToJSON(actPrfIDs), errExec.Error()))
+			}
+		}()
+		return
+	}
+	var rply string
+	if errExec := connMgr.Call(actionSConns, nil, utils.ActionSv1ExecuteActions,
+		actArgs, &rply); errExec != nil {
+		utils.Logger.Warning(fmt.Sprintf("<ThresholdS> failed executing the ActionProfiles: %s, error: %s",
+			utils.ToJSON(actPrfIDs), errExec.Error()))
+		err = utils.ErrPartiallyExecuted
+	}
 	return
 }
 
@@ -136,10 +178,12 @@ func (ts Thresholds) Sort() {
 }
 
 // NewThresholdService the constructor for ThresoldS service
-func NewThresholdService(dm *DataManager, cgrcfg *config.CGRConfig, filterS *FilterS) (tS *ThresholdService, err error) {
+func NewThresholdService(dm *DataManager, cgrcfg *config.CGRConfig, filterS *FilterS,
+	connMgr *ConnManager) (tS *ThresholdService, err error) {
 	return &ThresholdService{dm: dm,
 		cgrcfg:      cgrcfg,
 		filterS:     filterS,
+		connMgr:     connMgr,
 		stopBackup:  make(chan struct{}),
 		loopStoped:  make(chan struct{}),
 		storedTdIDs: make(utils.StringSet),
@@ -151,6 +195,7 @@ type ThresholdService struct {
 	dm          *DataManager
 	cgrcfg      *config.CGRConfig
 	filterS     *FilterS
+	connMgr     *ConnManager
 	stopBackup  chan struct{}
 	loopStoped  chan struct{}
 	storedTdIDs utils.StringSet // keep a record of stats which need saving, map[statsTenantID]bool
@@ -339,7 +384,7 @@ func (tS *ThresholdService) processEvent(tnt string, args *ThresholdsArgsProcess
 	for _, t := range matchTs {
 		tIDs = append(tIDs, t.ID)
 		t.Hits++
-		err = t.ProcessEvent(args, tS.dm)
+		err = t.ProcessEvent(args, tS.dm, tS.connMgr, tS.cgrcfg.ThresholdSCfg().ActionSConns)
 		if err != nil {
 			utils.Logger.Warning(
 				fmt.Sprintf("<ThresholdService> threshold: %s, ignoring event: %s, error: %s",
@@ -347,6 +392,13 @@ func (tS *ThresholdService) processEvent(tnt string, args *ThresholdsArgsProcess
 			withErrors = true
 			continue
 		}
+		if EventStream != nil {
+			EventStream.Publish(utils.MetaThresholdHit, t.Tenant, map[string]interface{}{
+				utils.ThresholdID: t.ID,
+				utils.Hits:        t.Hits,
+				utils.Event:       args.CGREvent.Event,
+			})
+		}
 		if t.dirty == nil || t.Hits == t.tPrfl.MaxHits { // one time threshold
 			if err = tS.dm.RemoveThreshold(t.Tenant, t.ID, utils.NonTransactional); err != nil {
 				utils.Logger.Warning(
@@ -24,6 +24,7 @@ import (
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
 )
 
 func TestThresholdsSort(t *testing.T) {
@@ -293,7 +294,7 @@ func TestThresholdsmatchingThresholdsForEvent(t *testing.T) {
 	defaultCfg.ThresholdSCfg().StoreInterval = 0
 	defaultCfg.ThresholdSCfg().StringIndexedFields = nil
 	defaultCfg.ThresholdSCfg().PrefixIndexedFields = nil
-	thServ, err = NewThresholdService(dmTH, defaultCfg, &FilterS{dm: dmTH, cfg: defaultCfg})
+	thServ, err = NewThresholdService(dmTH, defaultCfg, &FilterS{dm: dmTH, cfg: defaultCfg}, nil)
 	if err != nil {
 		t.Errorf("Error: %+v", err)
 	}
@@ -503,7 +504,7 @@ func TestThresholdsProcessEvent(t *testing.T) {
 	defaultCfg.ThresholdSCfg().StoreInterval = 0
 	defaultCfg.ThresholdSCfg().StringIndexedFields = nil
 	defaultCfg.ThresholdSCfg().PrefixIndexedFields = nil
-	thServ, err = NewThresholdService(dmTH, defaultCfg, &FilterS{dm: dmTH, cfg: defaultCfg})
+	thServ, err = NewThresholdService(dmTH, defaultCfg, &FilterS{dm: dmTH, cfg: defaultCfg}, nil)
 	if err != nil {
 		t.Errorf("Error: %+v", err)
 	}
@@ -704,7 +705,7 @@ func TestThresholdsVerifyIfExecuted(t *testing.T) {
 	defaultCfg.ThresholdSCfg().StoreInterval = 0
 	defaultCfg.ThresholdSCfg().StringIndexedFields = nil
 	defaultCfg.ThresholdSCfg().PrefixIndexedFields = nil
-	thServ, err = NewThresholdService(dmTH, defaultCfg, &FilterS{dm: dmTH, cfg: defaultCfg})
+	thServ, err = NewThresholdService(dmTH, defaultCfg, &FilterS{dm: dmTH, cfg: defaultCfg}, nil)
 	if err != nil {
 		t.Errorf("Error: %+v", err)
 	}
@@ -925,7 +926,7 @@ func TestThresholdsProcessEvent2(t *testing.T) {
 	defaultCfg.ThresholdSCfg().StoreInterval = 0
 	defaultCfg.ThresholdSCfg().StringIndexedFields = nil
 	defaultCfg.ThresholdSCfg().PrefixIndexedFields = nil
-	thServ, err = NewThresholdService(dmTH, defaultCfg, &FilterS{dm: dmTH, cfg: defaultCfg})
+	thServ, err = NewThresholdService(dmTH, defaultCfg, &FilterS{dm: dmTH, cfg: defaultCfg}, nil)
 	if err != nil {
 		t.Errorf("Error: %+v", err)
 	}
@@ -1068,3 +1069,59 @@ func TestThresholdsProcessEvent2(t *testing.T) {
 		}
 	}
 }
+
+type thActionSMock struct {
+	args *utils.ArgActionSv1ScheduleActions
+}
+
+func (r *thActionSMock) Call(method string, args interface{}, rply interface{}) error {
+	if method != utils.ActionSv1ExecuteActions {
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	r.args = args.(*utils.ArgActionSv1ScheduleActions)
+	*rply.(*string) = utils.OK
+	return nil
+}
+
+func TestThresholdsProcessEventActionProfiles(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	mock := new(thActionSMock)
+	internalChan := make(chan rpcclient.ClientConnector, 1)
+	internalChan <- mock
+	actSConns := []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaActions)}
+	connMgr := NewConnManager(cfg, map[string]chan rpcclient.ClientConnector{
+		actSConns[0]: internalChan,
+	})
+	th := &Threshold{
+		Tenant: "cgrates.org",
+		ID:     "TH_1",
+		Hits:   1,
+		tPrfl: &ThresholdProfile{
+			Tenant:    "cgrates.org",
+			ID:        "TH_1",
+			MaxHits:   -1,
+			MinHits:   1,
+			ActionIDs: []string{"*actions:ACT_PRF_1", "*actions:ACT_PRF_2"},
+		},
+	}
+	args := &ThresholdsArgsProcessEvent{
+		CGREvent: &utils.CGREvent{
+			Tenant: "cgrates.org",
+			ID:     "ev1",
+			Event:  map[string]interface{}{utils.AccountField: "1001"},
+		},
+	}
+	if err := th.ProcessEvent(args, nil, connMgr, nil); err != utils.ErrPartiallyExecuted {
+		t.Errorf("Expected %+v, received %+v", utils.ErrPartiallyExecuted, err)
+	}
+	if err := th.ProcessEvent(args, nil, connMgr, actSConns); err != nil {
+		t.Error(err)
+	}
+	expected := &utils.ArgActionSv1ScheduleActions{
+		CGREvent:         args.CGREvent,
+		ActionProfileIDs: []string{"ACT_PRF_1", "ACT_PRF_2"},
+	}
+	if !reflect.DeepEqual(expected, mock.args) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expected), utils.ToJSON(mock.args))
+	}
+}
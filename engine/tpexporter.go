@@ -32,7 +32,7 @@ import (
 	"github.com/cgrates/cgrates/utils"
 )
 
-func NewTPExporter(storDb LoadStorage, tpID, expPath, fileFormat, sep string, compress bool) (*TPExporter, error) {
+func NewTPExporter(storDb LoadReader, tpID, expPath, fileFormat, sep string, compress bool) (*TPExporter, error) {
 	if len(tpID) == 0 {
 		return nil, errors.New("Missing TPid")
 	}
@@ -69,7 +69,7 @@ func NewTPExporter(storDb LoadStorage, tpID, expPath, fileFormat, sep string, co
 
 // Export TariffPlan to a folder
 type TPExporter struct {
-	storDb        LoadStorage   // StorDb connection handle
+	storDb        LoadReader    // StorDb connection handle or DataDBLoadReader
 	tpID          string        // Load data on this tpid
 	exportPath    string        // Directory path to export to
 	fileFormat    string        // The file format <csv>
@@ -92,8 +92,8 @@ func (self *TPExporter) Run() error {
 
 	}
 	storDataModelTimings := APItoModelTimings(storDataTimings)
-	for i, sd := range storDataModelTimings {
-		toExportMap[utils.TimingsCsv][i] = sd
+	for _, sd := range storDataModelTimings {
+		toExportMap[utils.TimingsCsv] = append(toExportMap[utils.TimingsCsv], sd)
 	}
 
 	storDataDestinations, err := self.storDb.GetTPDestinations(self.tpID, "")
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
 	"github.com/cgrates/cgrates/structmatcher"
 	"github.com/cgrates/cgrates/utils"
 )
@@ -65,7 +66,8 @@ type TpReader struct {
 	acntActionPlans    map[string][]string
 	cacheConns         []string
 	schedulerConns     []string
-	isInternalDB       bool // do not reload cache if we use intarnalDB
+	isInternalDB       bool             // do not reload cache if we use intarnalDB
+	txn                *LoadTransaction // populated while writing transactionally
 }
 
 func NewTpReader(db DataDB, lr LoadReader, tpid, timezone string,
@@ -1028,16 +1030,19 @@ func (tpr *TpReader) LoadAccountActionsFiltered(qriedAA *utils.TPAccountActions)
 				return err
 			}
 		}
-		ub, err := tpr.dm.GetAccount(id)
-		if err != nil {
-			ub = &Account{
-				ID: id,
+		if _, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+			ub, err := tpr.dm.GetAccount(id)
+			if err != nil {
+				ub = &Account{
+					ID: id,
+				}
 			}
-		}
-		ub.ActionTriggers = actionTriggers
-		// init counters
-		ub.InitCounters()
-		if err := tpr.dm.SetAccount(ub); err != nil {
+			ub.SetLockTokens(tokens)
+			ub.ActionTriggers = actionTriggers
+			// init counters
+			ub.InitCounters()
+			return nil, tpr.dm.SetAccount(ub)
+		}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.AccountPrefix+id); err != nil {
 			return err
 		}
 	}
@@ -1443,6 +1448,50 @@ func (tpr *TpReader) IsValid() (valid bool) {
 	return
 }
 
+// WriteToDatabaseTransactional writes the data like WriteToDatabase but stages the profiles
+// so they are validated and written all or none at the end of the load
+// the legacy data can not be rolled back so the load is refused if any is present
+func (tpr *TpReader) WriteToDatabaseTransactional(verbose, disableReverse bool) (err error) {
+	if unsupported := tpr.nonTransactionalData(); len(unsupported) != 0 {
+		return fmt.Errorf("transactional load not supported for: %s",
+			strings.Join(unsupported, ", "))
+	}
+	tpr.txn = NewLoadTransaction(tpr.dm)
+	defer func() { tpr.txn = nil }()
+	return tpr.WriteToDatabase(verbose, disableReverse)
+}
+
+// nonTransactionalData returns the names of the loaded data that can not be staged
+func (tpr *TpReader) nonTransactionalData() (names []string) {
+	for _, data := range []struct {
+		name   string
+		loaded int
+	}{
+		{utils.Destinations, len(tpr.destinations)},
+		{utils.Timings, len(tpr.timings)},
+		{utils.RatingPlans, len(tpr.ratingPlans)},
+		{utils.RatingProfiles, len(tpr.ratingProfiles)},
+		{utils.SharedGroups, len(tpr.sharedGroups)},
+		{utils.Actions, len(tpr.actions)},
+		{utils.ActionPlans, len(tpr.actionPlans)},
+		{utils.ActionTriggers, len(tpr.actionsTriggers)},
+		{utils.AccountActions, len(tpr.accountActions)},
+	} {
+		if data.loaded != 0 {
+			names = append(names, data.name)
+		}
+	}
+	return
+}
+
+// store writes the item or stages it in case of transactional write
+func (tpr *TpReader) store(item interface{}, store func() error) (err error) {
+	if tpr.txn == nil {
+		return store()
+	}
+	return tpr.txn.Stage(item, store)
+}
+
 func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 	if tpr.dm.dataDB == nil {
 		return errors.New("no database connection")
@@ -1595,7 +1644,10 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		log.Print("Account Actions:")
 	}
 	for _, ub := range tpr.accountActions {
-		if err = tpr.dm.SetAccount(ub); err != nil {
+		if _, err = guardian.Guardian.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+			ub.SetLockTokens(tokens)
+			return nil, tpr.dm.SetAccount(ub)
+		}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.AccountPrefix+ub.ID); err != nil {
 			return
 		}
 		if verbose {
@@ -1610,7 +1662,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if th, err = APItoFilter(tpTH, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetFilter(th, true); err != nil {
+		if err = tpr.store(th, func() error {
+			return tpr.dm.SetFilter(th, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1628,7 +1682,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if rsp, err = APItoResource(tpRsp, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetResourceProfile(rsp, true); err != nil {
+		if err = tpr.store(rsp, func() error {
+			return tpr.dm.SetResourceProfile(rsp, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1654,17 +1710,20 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		}
 		var limit float64
 		if tpr.resProfiles[*rTid].Limit != utils.EmptyString {
-			if limit, err = strconv.ParseFloat(tpr.resProfiles[*rTid].Limit, 64); err != nil {
+			if limit, _, err = parseResourceLimit(tpr.resProfiles[*rTid].Limit); err != nil {
 				return
 			}
 		}
+		rs := &Resource{
+			Tenant: rTid.Tenant,
+			ID:     rTid.ID,
+			Usages: make(map[string]*ResourceUsage),
+		}
+		stored := tpr.resProfiles[*rTid].Stored
 		// for non stored we do not save the resource
-		if err = tpr.dm.SetResource(
-			&Resource{
-				Tenant: rTid.Tenant,
-				ID:     rTid.ID,
-				Usages: make(map[string]*ResourceUsage),
-			}, ttl, limit, !tpr.resProfiles[*rTid].Stored); err != nil {
+		if err = tpr.store(rs, func() error {
+			return tpr.dm.SetResource(rs, ttl, limit, !stored)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1682,7 +1741,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if st, err = APItoStats(tpST, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetStatQueueProfile(st, true); err != nil {
+		if err = tpr.store(st, func() error {
+			return tpr.dm.SetStatQueueProfile(st, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1723,11 +1784,12 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 				return
 			}
 		}
+		sqp := tpr.sqProfiles[*sqTntID]
 		// for non stored we do not save the metrics
-		if err = tpr.dm.SetStatQueue(sq, metrics,
-			tpr.sqProfiles[*sqTntID].MinItems,
-			ttl, tpr.sqProfiles[*sqTntID].QueueLength,
-			!tpr.sqProfiles[*sqTntID].Stored); err != nil {
+		if err = tpr.store(sq, func() error {
+			return tpr.dm.SetStatQueue(sq, metrics, sqp.MinItems,
+				ttl, sqp.QueueLength, !sqp.Stored)
+		}); err != nil {
 			return err
 		}
 		if verbose {
@@ -1745,7 +1807,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if th, err = APItoThresholdProfile(tpTH, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetThresholdProfile(th, true); err != nil {
+		if err = tpr.store(th, func() error {
+			return tpr.dm.SetThresholdProfile(th, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1765,7 +1829,10 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 				return
 			}
 		}
-		if err = tpr.dm.SetThreshold(&Threshold{Tenant: thd.Tenant, ID: thd.ID}, minSleep, false); err != nil {
+		th := &Threshold{Tenant: thd.Tenant, ID: thd.ID}
+		if err = tpr.store(th, func() error {
+			return tpr.dm.SetThreshold(th, minSleep, false)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1783,7 +1850,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if th, err = APItoRouteProfile(tpTH, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetRouteProfile(th, true); err != nil {
+		if err = tpr.store(th, func() error {
+			return tpr.dm.SetRouteProfile(th, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1801,7 +1870,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if th, err = APItoAttributeProfile(tpTH, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetAttributeProfile(th, true); err != nil {
+		if err = tpr.store(th, func() error {
+			return tpr.dm.SetAttributeProfile(th, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1819,7 +1890,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if th, err = APItoChargerProfile(tpTH, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetChargerProfile(th, true); err != nil {
+		if err = tpr.store(th, func() error {
+			return tpr.dm.SetChargerProfile(th, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1837,7 +1910,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if th, err = APItoDispatcherProfile(tpTH, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetDispatcherProfile(th, true); err != nil {
+		if err = tpr.store(th, func() error {
+			return tpr.dm.SetDispatcherProfile(th, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1852,7 +1927,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 	}
 	for _, tpTH := range tpr.dispatcherHosts {
 		th := APItoDispatcherHost(tpTH)
-		if err = tpr.dm.SetDispatcherHost(th); err != nil {
+		if err = tpr.store(th, func() error {
+			return tpr.dm.SetDispatcherHost(th)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1871,7 +1948,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if th, err = APItoRateProfile(tpTH, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetRateProfile(th, true); err != nil {
+		if err = tpr.store(th, func() error {
+			return tpr.dm.SetRateProfile(th, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1890,7 +1969,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if ap, err = APItoActionProfile(tpAP, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetActionProfile(ap, true); err != nil {
+		if err = tpr.store(ap, func() error {
+			return tpr.dm.SetActionProfile(ap, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1909,7 +1990,9 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 		if ap, err = APItoAccountProfile(tpAP, tpr.timezone); err != nil {
 			return
 		}
-		if err = tpr.dm.SetAccountProfile(ap, true); err != nil {
+		if err = tpr.store(ap, func() error {
+			return tpr.dm.SetAccountProfile(ap, true)
+		}); err != nil {
 			return
 		}
 		if verbose {
@@ -1934,6 +2017,11 @@ func (tpr *TpReader) WriteToDatabase(verbose, disableReverse bool) (err error) {
 	if len(tpr.timings) != 0 {
 		loadIDs[utils.CacheTimings] = loadID
 	}
+	if tpr.txn != nil {
+		if err = tpr.txn.Commit(); err != nil {
+			return
+		}
+	}
 	if !disableReverse {
 		if len(tpr.acntActionPlans) > 0 {
 			if verbose {
@@ -0,0 +1,496 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	mathrand "math/rand"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// Tracer records the spans of the traced requests
+// it is nil(disabled) unless the trace_exporter is configured
+var Tracer *TracerS
+
+// The kinds of the spans as defined by OTLP
+const (
+	SpanKindInternal = 1
+	SpanKindServer   = 2
+	SpanKindClient   = 3
+)
+
+// maxQueuedSpans limits the spans kept in memory between two exports, the rest are dropped
+const maxQueuedSpans = 4096
+
+// NewTracerS returns the TracerS exporting the spans based on the cores config
+func NewTracerS(cfg *config.CGRConfig) (t *TracerS, err error) {
+	t = &TracerS{cfg: cfg}
+	switch cfg.CoreSCfg().TraceExporter {
+	case utils.MetaOTLP:
+		t.exporter = &otlpHTTPExporter{
+			url:    cfg.CoreSCfg().TraceExportPath,
+			client: &http.Client{Timeout: cfg.GeneralCfg().ReplyTimeout},
+		}
+	case utils.MetaFile:
+		t.exporter = &fileSpanExporter{path: cfg.CoreSCfg().TraceExportPath}
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter: <%s>", cfg.CoreSCfg().TraceExporter)
+	}
+	return
+}
+
+// TracerS collects the ended spans and exports them periodically
+type TracerS struct {
+	cfg      *config.CGRConfig
+	exporter spanExporter
+	spans    []*otlpSpan
+	dropped  int // spans dropped since the last export
+	spansLk  sync.Mutex
+	exportLk sync.Mutex // one export at a time
+}
+
+// StartSpan starts a new span as child of the traceParent
+// a new trace is started if the traceParent is empty or invalid
+func (t *TracerS) StartSpan(name string, kind int, traceParent string) (s *Span) {
+	if t == nil {
+		return
+	}
+	s = &Span{
+		tracer:    t,
+		name:      name,
+		kind:      kind,
+		startTime: time.Now(),
+	}
+	if tc, err := parseTraceParent(traceParent); err == nil {
+		s.traceID, s.parentID, s.sampled = tc.traceID, tc.spanID, tc.sampled
+	} else {
+		rand.Read(s.traceID[:])
+		s.sampled = mathrand.Float64() < t.cfg.CoreSCfg().TraceSampleRatio
+	}
+	rand.Read(s.spanID[:])
+	return
+}
+
+// StartCallSpan starts the client span of an API call
+// tracedArgs is a copy of the arguments carrying the context of the span in their options
+func (t *TracerS) StartCallSpan(method string, args interface{}) (s *Span, tracedArgs interface{}) {
+	if t == nil {
+		return nil, args
+	}
+	s = t.StartSpan(method, SpanKindClient,
+		utils.IfaceAsString(argsOpts(args)[utils.OptsTraceParent]))
+	s.SetRPCAttributes(method)
+	return s, argsWithOption(args, utils.OptsTraceParent, s.TraceParent())
+}
+
+// recordSpan queues the ended span for export
+func (t *TracerS) recordSpan(s *otlpSpan) {
+	t.spansLk.Lock()
+	if len(t.spans) < maxQueuedSpans {
+		t.spans = append(t.spans, s)
+	} else {
+		t.dropped++
+	}
+	t.spansLk.Unlock()
+}
+
+// ListenAndServe exports the spans on each trace_flush_interval, exporting the remaining ones on stop
+func (t *TracerS) ListenAndServe(stopChan <-chan struct{}) {
+	ticker := time.NewTicker(t.cfg.CoreSCfg().TraceFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			t.flush()
+			return
+		case <-ticker.C:
+			t.flush()
+		}
+	}
+}
+
+// flush exports the queued spans, logging the failures
+func (t *TracerS) flush() {
+	if err := t.Export(); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> failed exporting the spans, error: <%s>",
+			utils.CoreS, err.Error()))
+	}
+}
+
+// Export sends the queued spans to the exporter
+// the spans are dropped if the export fails
+func (t *TracerS) Export() (err error) {
+	t.exportLk.Lock()
+	defer t.exportLk.Unlock()
+	t.spansLk.Lock()
+	spans, dropped := t.spans, t.dropped
+	t.spans, t.dropped = nil, 0
+	t.spansLk.Unlock()
+	if dropped != 0 {
+		utils.Logger.Warning(fmt.Sprintf("<%s> dropped %d spans over the limit of %d",
+			utils.CoreS, dropped, maxQueuedSpans))
+	}
+	if len(spans) == 0 {
+		return
+	}
+	var body []byte
+	if body, err = json.Marshal(t.asOTLPTraces(spans)); err != nil {
+		return
+	}
+	return t.exporter.exportSpans(body)
+}
+
+// asOTLPTraces groups the spans in the OTLP request, describing this engine as their resource
+func (t *TracerS) asOTLPTraces(spans []*otlpSpan) *otlpTraces {
+	return &otlpTraces{
+		ResourceSpans: []*otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []*otlpAttribute{
+					newOTLPAttribute(utils.TraceServiceName, utils.CGRateSLwr),
+					newOTLPAttribute(utils.TraceServiceInstance, t.cfg.GeneralCfg().NodeID),
+					newOTLPAttribute(utils.TraceServiceVersion, utils.Version),
+				},
+			},
+			ScopeSpans: []*otlpScopeSpans{{
+				Scope: otlpScope{Name: utils.CGRateSLwr, Version: utils.Version},
+				Spans: spans,
+			}},
+		}},
+	}
+}
+
+// Span is a traced operation, its methods can be called on nil when the tracing is disabled
+type Span struct {
+	tracer    *TracerS
+	traceID   [16]byte
+	spanID    [8]byte
+	parentID  [8]byte // empty for the root spans
+	sampled   bool
+	name      string
+	kind      int
+	startTime time.Time
+	attrs     []*otlpAttribute
+}
+
+// TraceParent returns the context of the span in the W3C traceparent format
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return utils.EmptyString
+	}
+	return (&traceContext{
+		traceID: s.traceID,
+		spanID:  s.spanID,
+		sampled: s.sampled,
+	}).traceParent()
+}
+
+// TraceID returns the ID of the trace in hex
+func (s *Span) TraceID() string {
+	if s == nil {
+		return utils.EmptyString
+	}
+	return hex.EncodeToString(s.traceID[:])
+}
+
+// SetAttribute adds an attribute to the span
+func (s *Span) SetAttribute(key, val string) {
+	if s == nil || !s.sampled {
+		return
+	}
+	s.attrs = append(s.attrs, newOTLPAttribute(key, val))
+}
+
+// SetRPCAttributes adds the attributes of an API method, eg: SessionSv1.AuthorizeEvent
+func (s *Span) SetRPCAttributes(method string) {
+	s.SetAttribute(utils.TraceRPCSystem, utils.CGRateSLwr)
+	if idx := strings.IndexByte(method, utils.NestingSep[0]); idx != -1 {
+		s.SetAttribute(utils.TraceRPCService, method[:idx])
+		s.SetAttribute(utils.TraceRPCMethod, method[idx+1:])
+	}
+}
+
+// End ends the span, queuing it for export if sampled
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+	s.EndWithError(errMsg)
+}
+
+// EndWithError ends the span with the error message, if any
+func (s *Span) EndWithError(errMsg string) {
+	if s == nil || !s.sampled {
+		return
+	}
+	oSpan := &otlpSpan{
+		TraceID:           hex.EncodeToString(s.traceID[:]),
+		SpanID:            hex.EncodeToString(s.spanID[:]),
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.startTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(time.Now().UnixNano(), 10),
+		Attributes:        s.attrs,
+	}
+	if s.parentID != [8]byte{} {
+		oSpan.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+	if errMsg != utils.EmptyString {
+		oSpan.Status = otlpStatus{Code: otlpStatusError, Message: errMsg}
+	}
+	s.tracer.recordSpan(oSpan)
+}
+
+// traceContext is the W3C trace context propagated in the *traceparent option
+type traceContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+	sampled bool
+}
+
+// parseTraceParent decodes the W3C traceparent: version-traceid-parentid-flags
+func parseTraceParent(traceParent string) (tc *traceContext, err error) {
+	flds := strings.Split(traceParent, "-")
+	if len(flds) < 4 || len(flds[0]) != 2 || flds[0] == "ff" ||
+		len(flds[1]) != 32 || len(flds[2]) != 16 || len(flds[3]) != 2 ||
+		(flds[0] == "00" && len(flds) != 4) {
+		return nil, fmt.Errorf("invalid traceparent: <%s>", traceParent)
+	}
+	tc = new(traceContext)
+	var flags []byte
+	if _, err = hex.Decode(tc.traceID[:], []byte(flds[1])); err != nil {
+		return nil, fmt.Errorf("invalid traceparent: <%s>", traceParent)
+	}
+	if _, err = hex.Decode(tc.spanID[:], []byte(flds[2])); err != nil {
+		return nil, fmt.Errorf("invalid traceparent: <%s>", traceParent)
+	}
+	if flags, err = hex.DecodeString(flds[3]); err != nil ||
+		tc.traceID == [16]byte{} || tc.spanID == [8]byte{} {
+		return nil, fmt.Errorf("invalid traceparent: <%s>", traceParent)
+	}
+	tc.sampled = flags[0]&0x01 == 0x01
+	return
+}
+
+// traceParent encodes the context in the W3C traceparent format
+func (tc *traceContext) traceParent() string {
+	var flags byte
+	if tc.sampled {
+		flags = 0x01
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", hex.EncodeToString(tc.traceID[:]),
+		hex.EncodeToString(tc.spanID[:]), flags)
+}
+
+// argsOpts returns the options of the API call arguments, nil if they have none
+func argsOpts(args interface{}) (opts map[string]interface{}) {
+	v := reflect.ValueOf(args)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	sf, has := v.Elem().Type().FieldByName(utils.Opts)
+	if !has {
+		return
+	}
+	v = v.Elem()
+	for _, idx := range sf.Index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	if v.CanInterface() {
+		opts, _ = v.Interface().(map[string]interface{})
+	}
+	return
+}
+
+// argsWithOption returns a copy of the API call arguments with the option set
+// only the structs leading to the options are copied so the caller's arguments are not modified
+// the arguments are returned unchanged if they have no options field
+func argsWithOption(args interface{}, opt string, val interface{}) interface{} {
+	v := reflect.ValueOf(args)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return args
+	}
+	sf, has := v.Elem().Type().FieldByName(utils.Opts)
+	if !has || sf.Type != reflect.TypeOf(map[string]interface{}(nil)) {
+		return args
+	}
+	if cp, copied := copyWithOption(v, sf.Index, opt, val); copied {
+		return cp.Interface()
+	}
+	return args
+}
+
+// copyWithOption copies the value along the field index, setting the option in the copied options
+func copyWithOption(v reflect.Value, index []int, opt string, val interface{}) (cp reflect.Value, copied bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		var elem reflect.Value
+		if elem, copied = copyWithOption(v.Elem(), index, opt, val); !copied {
+			return
+		}
+		cp = reflect.New(elem.Type())
+		cp.Elem().Set(elem)
+		return
+	}
+	cp = reflect.New(v.Type()).Elem()
+	cp.Set(v)
+	fld := cp.Field(index[0])
+	if !fld.CanSet() {
+		return
+	}
+	if len(index) != 1 {
+		var fldCp reflect.Value
+		if fldCp, copied = copyWithOption(fld, index[1:], opt, val); copied {
+			fld.Set(fldCp)
+		}
+		return
+	}
+	prevOpts, _ := fld.Interface().(map[string]interface{})
+	opts := make(map[string]interface{}, len(prevOpts)+1)
+	for k, v := range prevOpts {
+		opts[k] = v
+	}
+	opts[opt] = val
+	fld.Set(reflect.ValueOf(opts))
+	return cp, true
+}
+
+// spanExporter sends the spans encoded as OTLP JSON
+type spanExporter interface {
+	exportSpans(body []byte) error
+}
+
+// otlpHTTPExporter posts the spans to an OTLP/HTTP collector
+type otlpHTTPExporter struct {
+	url    string
+	client *http.Client
+}
+
+func (e *otlpHTTPExporter) exportSpans(body []byte) (err error) {
+	var rply *http.Response
+	if rply, err = e.client.Post(e.url, "application/json", bytes.NewReader(body)); err != nil {
+		return
+	}
+	defer rply.Body.Close()
+	io.Copy(ioutil.Discard, rply.Body)
+	if rply.StatusCode < 200 || rply.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code received: %d", rply.StatusCode)
+	}
+	return
+}
+
+// fileSpanExporter appends the spans to a file, one OTLP JSON request per line
+type fileSpanExporter struct {
+	path string
+}
+
+func (e *fileSpanExporter) exportSpans(body []byte) (err error) {
+	var f *os.File
+	if f, err = os.OpenFile(e.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644); err != nil {
+		return
+	}
+	if _, err = f.Write(append(body, '\n')); err != nil {
+		f.Close()
+		return
+	}
+	return f.Close()
+}
+
+// otlpStatusError is the status code of the failed spans
+const otlpStatusError = 2
+
+// The OTLP JSON encoding of the ExportTraceServiceRequest
+type otlpTraces struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource      `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []*otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope   `json:"scope"`
+	Spans []*otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string           `json:"traceId"`
+	SpanID            string           `json:"spanId"`
+	ParentSpanID      string           `json:"parentSpanId,omitempty"`
+	Name              string           `json:"name"`
+	Kind              int              `json:"kind"`
+	StartTimeUnixNano string           `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string           `json:"endTimeUnixNano"`
+	Attributes        []*otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus       `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+func newOTLPAttribute(key, val string) *otlpAttribute {
+	return &otlpAttribute{Key: key, Value: otlpValue{StringValue: val}}
+}
@@ -0,0 +1,317 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tp := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if tc, err := parseTraceParent(tp); err != nil {
+		t.Fatal(err)
+	} else if !tc.sampled {
+		t.Error("Expected sampled")
+	} else if rcv := tc.traceParent(); rcv != tp {
+		t.Errorf("Expected %q, received %q", tp, rcv)
+	}
+	tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"
+	if tc, err := parseTraceParent(tp); err != nil {
+		t.Fatal(err)
+	} else if tc.sampled {
+		t.Error("Expected not sampled")
+	} else if rcv := tc.traceParent(); rcv != tp {
+		t.Errorf("Expected %q, received %q", tp, rcv)
+	}
+	// future versions can have more fields
+	if _, err := parseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"); err != nil {
+		t.Error(err)
+	}
+	for _, tp := range []string{
+		utils.EmptyString,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+	} {
+		if _, err := parseTraceParent(tp); err == nil {
+			t.Errorf("Expected error for %q", tp)
+		}
+	}
+}
+
+type testTracedArgs struct {
+	*utils.CGREvent
+	Flags []string
+}
+
+func TestArgsWithOption(t *testing.T) {
+	args := &testTracedArgs{
+		CGREvent: &utils.CGREvent{
+			Tenant: "cgrates.org",
+			ID:     "EV1",
+			Event:  map[string]interface{}{utils.AccountField: "1001"},
+			Opts:   map[string]interface{}{utils.OptsContext: "*sessions"},
+		},
+		Flags: []string{utils.MetaAttributes},
+	}
+	rcv, canCast := argsWithOption(args, utils.OptsTraceParent, "tp").(*testTracedArgs)
+	if !canCast {
+		t.Fatalf("Unexpected arguments: %T", rcv)
+	}
+	if _, has := args.Opts[utils.OptsTraceParent]; has {
+		t.Error("Expected the arguments not modified")
+	}
+	if exp := map[string]interface{}{
+		utils.OptsContext:     "*sessions",
+		utils.OptsTraceParent: "tp",
+	}; !reflect.DeepEqual(exp, rcv.Opts) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(exp), utils.ToJSON(rcv.Opts))
+	}
+	if rcv.ID != "EV1" || !reflect.DeepEqual(rcv.Flags, args.Flags) ||
+		!reflect.DeepEqual(rcv.Event, args.Event) {
+		t.Errorf("Unexpected arguments: %s", utils.ToJSON(rcv))
+	}
+	if rcv := argsOpts(rcv); rcv[utils.OptsTraceParent] != "tp" {
+		t.Errorf("Unexpected options: %s", utils.ToJSON(rcv))
+	}
+
+	// created if missing
+	ev := &utils.CGREvent{Tenant: "cgrates.org"}
+	if rcv := argsOpts(argsWithOption(ev, utils.OptsTraceParent, "tp")); rcv[utils.OptsTraceParent] != "tp" {
+		t.Errorf("Unexpected options: %s", utils.ToJSON(rcv))
+	} else if ev.Opts != nil {
+		t.Error("Expected the arguments not modified")
+	}
+	// unchanged without options
+	for _, args := range []interface{}{
+		&testTracedArgs{},
+		&utils.TenantID{Tenant: "cgrates.org"},
+		utils.StringPointer("value"),
+		utils.CGREvent{},
+		nil,
+	} {
+		if rcv := argsWithOption(args, utils.OptsTraceParent, "tp"); !reflect.DeepEqual(args, rcv) {
+			t.Errorf("Expected %+v, received %+v", args, rcv)
+		}
+	}
+}
+
+func TestTracerSNil(t *testing.T) {
+	var tS *TracerS
+	span := tS.StartSpan("SessionSv1.AuthorizeEvent", SpanKindServer, utils.EmptyString)
+	if span != nil {
+		t.Errorf("Expected nil span, received %+v", span)
+	}
+	span.SetAttribute(utils.TraceConnID, "*internal")
+	span.End(errors.New("failed"))
+	if tp := span.TraceParent(); tp != utils.EmptyString {
+		t.Errorf("Unexpected traceparent: %q", tp)
+	}
+	args := &utils.CGREvent{Tenant: "cgrates.org"}
+	if span, rcv := tS.StartCallSpan(utils.AttributeSv1ProcessEvent, args); span != nil || rcv != args {
+		t.Errorf("Unexpected span: %+v and arguments: %+v", span, rcv)
+	}
+}
+
+func testTracerSExportedSpans(t *testing.T, body []byte) (spans map[string]*otlpSpan) {
+	var traces otlpTraces
+	if err := json.Unmarshal(body, &traces); err != nil {
+		t.Fatal(err)
+	}
+	if len(traces.ResourceSpans) != 1 || len(traces.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Unexpected traces: %s", body)
+	}
+	if attrs := traces.ResourceSpans[0].Resource.Attributes; len(attrs) != 3 ||
+		attrs[0].Value.StringValue != utils.CGRateSLwr {
+		t.Errorf("Unexpected resource: %s", utils.ToJSON(attrs))
+	}
+	spans = make(map[string]*otlpSpan)
+	for _, span := range traces.ResourceSpans[0].ScopeSpans[0].Spans {
+		spans[span.Name] = span
+	}
+	return
+}
+
+func TestTracerSExportFile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(utils.EmptyString, "tracing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	cfg := config.NewDefaultCGRConfig()
+	cfg.CoreSCfg().TraceExporter = utils.MetaFile
+	cfg.CoreSCfg().TraceExportPath = path.Join(tmpDir, "traces.json")
+	tS, err := NewTracerS(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tS.Export(); err != nil { // nothing to export
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(cfg.CoreSCfg().TraceExportPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no file, received: %v", err)
+	}
+
+	root := tS.StartSpan("DiameterAgent.CCR", SpanKindServer, utils.EmptyString)
+	ev := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		Opts:   map[string]interface{}{utils.OptsTraceParent: root.TraceParent()},
+	}
+	span, args := tS.StartCallSpan(utils.SessionSv1AuthorizeEvent, ev)
+	if tp := argsOpts(args)[utils.OptsTraceParent]; tp != span.TraceParent() {
+		t.Errorf("Expected %q, received %q", span.TraceParent(), tp)
+	}
+	if span.TraceID() != root.TraceID() {
+		t.Errorf("Expected trace %q, received %q", root.TraceID(), span.TraceID())
+	}
+	span.SetAttribute(utils.TraceConnID, utils.MetaInternal)
+	span.End(utils.ErrNotFound)
+	root.End(nil)
+	if err = tS.Export(); err != nil {
+		t.Fatal(err)
+	}
+	tS.StartSpan("DiameterAgent.CCR", SpanKindServer, utils.EmptyString).End(nil) // appended on a new line
+	if err = tS.Export(); err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadFile(cfg.CoreSCfg().TraceExportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, received: %s", body)
+	}
+	spans := testTracerSExportedSpans(t, []byte(lines[0]))
+	if len(spans) != 2 {
+		t.Fatalf("Unexpected spans: %s", lines[0])
+	}
+	rootSpan, callSpan := spans["DiameterAgent.CCR"], spans[utils.SessionSv1AuthorizeEvent]
+	if rootSpan == nil || callSpan == nil {
+		t.Fatalf("Unexpected spans: %s", lines[0])
+	}
+	if rootSpan.ParentSpanID != utils.EmptyString || rootSpan.Kind != SpanKindServer ||
+		rootSpan.Status.Code != 0 {
+		t.Errorf("Unexpected span: %s", utils.ToJSON(rootSpan))
+	}
+	if callSpan.TraceID != rootSpan.TraceID || callSpan.ParentSpanID != rootSpan.SpanID ||
+		callSpan.Kind != SpanKindClient ||
+		callSpan.Status.Code != otlpStatusError || callSpan.Status.Message != utils.ErrNotFound.Error() {
+		t.Errorf("Unexpected span: %s", utils.ToJSON(callSpan))
+	}
+	expAttrs := []*otlpAttribute{
+		newOTLPAttribute(utils.TraceRPCSystem, utils.CGRateSLwr),
+		newOTLPAttribute(utils.TraceRPCService, utils.SessionSv1),
+		newOTLPAttribute(utils.TraceRPCMethod, "AuthorizeEvent"),
+		newOTLPAttribute(utils.TraceConnID, utils.MetaInternal),
+	}
+	if !reflect.DeepEqual(expAttrs, callSpan.Attributes) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expAttrs), utils.ToJSON(callSpan.Attributes))
+	}
+}
+
+func TestTracerSExportOTLP(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer srv.Close()
+	cfg := config.NewDefaultCGRConfig()
+	cfg.CoreSCfg().TraceExporter = utils.MetaOTLP
+	cfg.CoreSCfg().TraceExportPath = srv.URL + "/v1/traces"
+	tS, err := NewTracerS(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tS.StartSpan("HTTPAgent./cdr", SpanKindServer,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01").End(nil)
+	if err = tS.Export(); err != nil {
+		t.Fatal(err)
+	}
+	spans := testTracerSExportedSpans(t, <-bodies)
+	if span := spans["HTTPAgent./cdr"]; span == nil ||
+		span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" ||
+		span.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("Unexpected spans: %s", utils.ToJSON(spans))
+	}
+
+	cfg.CoreSCfg().TraceExportPath = srv.URL + "/invalid"
+	if tS, err = NewTracerS(cfg); err != nil {
+		t.Fatal(err)
+	}
+	tS.StartSpan("HTTPAgent./cdr", SpanKindServer, utils.EmptyString).End(nil)
+	if err = tS.Export(); err == nil || err.Error() != "unexpected status code received: 404" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestTracerSSampling(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.CoreSCfg().TraceExporter = utils.MetaFile
+	cfg.CoreSCfg().TraceExportPath = "/tmp/traces.json"
+	cfg.CoreSCfg().TraceSampleRatio = 0
+	tS, err := NewTracerS(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := tS.StartSpan("RadiusAgent.Auth", SpanKindServer, utils.EmptyString)
+	if tp := root.TraceParent(); !strings.HasSuffix(tp, "-00") {
+		t.Errorf("Unexpected traceparent: %q", tp)
+	}
+	// the decision of the caller is kept
+	child := tS.StartSpan(utils.SessionSv1AuthorizeEvent, SpanKindClient, root.TraceParent())
+	child.End(nil)
+	root.End(nil)
+	sampled := tS.StartSpan(utils.SessionSv1AuthorizeEvent, SpanKindClient,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	sampled.End(nil)
+	if len(tS.spans) != 1 {
+		t.Errorf("Expected only the sampled span, received %s", utils.ToJSON(tS.spans))
+	}
+
+	cfg.CoreSCfg().TraceExporter = utils.MetaNone
+	expErr := "unsupported trace exporter: <*none>"
+	if _, err = NewTracerS(cfg); err == nil || err.Error() != expErr {
+		t.Errorf("Expected %q, received %v", expErr, err)
+	}
+}
@@ -866,6 +866,48 @@ func TestResourceAllocateResource(t *testing.T) {
 	}
 }
 
+func TestResourceAllocateResourceLimitInterval(t *testing.T) {
+	r := &Resource{
+		Tenant: "cgrates.org",
+		ID:     "RES_CPS",
+		Usages: make(map[string]*ResourceUsage),
+		ttl:    utils.DurationPointer(0),
+		rPrf: &ResourceProfile{
+			Tenant:        "cgrates.org",
+			ID:            "RES_CPS",
+			Limit:         2,
+			LimitInterval: 50 * time.Millisecond,
+		},
+	}
+	rs := Resources{r}
+	for _, ruID := range []string{"RU1", "RU2"} {
+		if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org", ID: ruID, Units: 1}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// same usage counted only once within the interval
+	if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org", ID: "RU1", Units: 1}, false); err != nil {
+		t.Error(err)
+	}
+	if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org", ID: "RU3", Units: 1}, true); err != utils.ErrResourceUnavailable {
+		t.Errorf("Expected %+v, received %+v", utils.ErrResourceUnavailable, err)
+	}
+	// releasing does not give back the units before the end of the interval
+	if err := rs.clearUsage("RU1"); err != nil {
+		t.Error(err)
+	}
+	if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org", ID: "RU3", Units: 1}, false); err != utils.ErrResourceUnavailable {
+		t.Errorf("Expected %+v, received %+v", utils.ErrResourceUnavailable, err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org", ID: "RU3", Units: 1}, false); err != nil {
+		t.Error(err)
+	}
+	if len(r.Usages) != 1 || r.totalUsage() != 1 {
+		t.Errorf("Expected only the RU3 usage, received: %s", utils.ToJSON(r.Usages))
+	}
+}
+
 // TestRSCacheSetGet assurace the presence of private params in cached resource
 func TestRSCacheSetGet(t *testing.T) {
 	r := &Resource{
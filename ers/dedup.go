@@ -0,0 +1,81 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// dedupStore keeps the IDs of the processed events
+type dedupStore interface {
+	InitDedupIDs() error                           // called once, before storing the IDs
+	SetDedupID(id string, ttl time.Duration) error // returns utils.ErrExists for known IDs
+	RemoveDedupID(id string) error
+}
+
+// newDedupStore returns the dedupStore configured in ERs
+// the IDs kept in memory use an InternalDB of their own so they are not mixed with the DataDB ones
+func newDedupStore(cfg *config.ERsCfg, dm *engine.DataManager) dedupStore {
+	if cfg.DedupStore == utils.MetaDataDB {
+		return dm
+	}
+	return engine.NewDataManager(engine.NewInternalDB(nil, nil, false), nil, nil)
+}
+
+// dedupID builds the deduplication ID of the event out of the reader template
+func dedupID(cgrEv *utils.CGREvent, rdrCfg *config.EventReaderCfg) (id string, err error) {
+	if id, err = rdrCfg.DedupID.ParseDataProvider(utils.MapStorage{
+		utils.MetaReq:  cgrEv.Event,
+		utils.MetaOpts: cgrEv.Opts,
+	}); err != nil {
+		return
+	}
+	if id == utils.EmptyString {
+		return utils.EmptyString, utils.NewErrMandatoryIeMissing(utils.DedupIDCfg)
+	}
+	return utils.ConcatenatedKey(cgrEv.Tenant, id), nil
+}
+
+// processDuplicate counts the duplicated event and sends it to the configured exporters
+func (erS *ERService) processDuplicate(cgrEv *utils.CGREvent,
+	rdrCfg *config.EventReaderCfg, id string) (err error) {
+	erS.dupMux.Lock()
+	erS.dupCnt[rdrCfg.ID]++
+	dupCnt := erS.dupCnt[rdrCfg.ID]
+	erS.dupMux.Unlock()
+	engine.Metrics.IncCounter(utils.MetricERsDuplicates, utils.MetricReader, rdrCfg.ID)
+	utils.Logger.Info(
+		fmt.Sprintf("<%s> reader: <%s> dropped duplicated event with ID: <%s>, total duplicates: %d",
+			utils.ERs, rdrCfg.ID, id, dupCnt))
+	if len(rdrCfg.DedupEEIDs) == 0 {
+		return
+	}
+	var reply map[string]map[string]interface{}
+	return erS.connMgr.Call(erS.cfg.ERsCfg().EEsConns, nil,
+		utils.EeSv1ProcessEvent,
+		&utils.CGREventWithEeIDs{
+			EeIDs:    rdrCfg.DedupEEIDs,
+			CGREvent: cgrEv,
+		}, &reply)
+}
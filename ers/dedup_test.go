@@ -0,0 +1,56 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestInternalDedupTTL(t *testing.T) {
+	iD := newDedupStore(config.NewDefaultCGRConfig().ERsCfg(), nil)
+	if err := iD.InitDedupIDs(); err != nil {
+		t.Fatal(err)
+	}
+	if err := iD.SetDedupID("cgrates.org:sess1", 10*time.Millisecond); err != nil {
+		t.Error(err)
+	}
+	if err := iD.SetDedupID("cgrates.org:sess2", time.Hour); err != nil {
+		t.Error(err)
+	}
+	if err := iD.SetDedupID("cgrates.org:sess1", time.Hour); err != utils.ErrExists {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrExists, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := iD.SetDedupID("cgrates.org:sess1", time.Hour); err != nil { // expired after its own ttl
+		t.Error(err)
+	}
+	if err := iD.SetDedupID("cgrates.org:sess2", time.Hour); err != utils.ErrExists {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrExists, err)
+	}
+	if err := iD.RemoveDedupID("cgrates.org:sess2"); err != nil {
+		t.Error(err)
+	}
+	if err := iD.SetDedupID("cgrates.org:sess2", time.Hour); err != nil {
+		t.Error(err)
+	}
+}
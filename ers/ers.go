@@ -35,7 +35,7 @@ type erEvent struct {
 }
 
 // NewERService instantiates the ERService
-func NewERService(cfg *config.CGRConfig, filterS *engine.FilterS, connMgr *engine.ConnManager) *ERService {
+func NewERService(cfg *config.CGRConfig, dm *engine.DataManager, filterS *engine.FilterS, connMgr *engine.ConnManager) *ERService {
 	return &ERService{
 		cfg:       cfg,
 		rdrs:      make(map[string]EventReader),
@@ -45,6 +45,8 @@ func NewERService(cfg *config.CGRConfig, filterS *engine.FilterS, connMgr *engin
 		rdrErr:    make(chan error),
 		filterS:   filterS,
 		connMgr:   connMgr,
+		dedupS:    newDedupStore(cfg.ERsCfg(), dm),
+		dupCnt:    make(map[string]int64),
 	}
 }
 
@@ -60,10 +62,20 @@ type ERService struct {
 
 	filterS *engine.FilterS
 	connMgr *engine.ConnManager
+
+	dedupS dedupStore       // remembers the processed events
+	dupCnt map[string]int64 // map[rdrID]duplicatedEvents
+	dupMux sync.Mutex
 }
 
 // ListenAndServe keeps the service alive
 func (erS *ERService) ListenAndServe(stopChan, cfgRldChan chan struct{}) (err error) {
+	if err = erS.dedupS.InitDedupIDs(); err != nil {
+		utils.Logger.Crit(
+			fmt.Sprintf("<%s> initializing the dedup store got error: <%s>",
+				utils.ERs, err.Error()))
+		return
+	}
 	for cfgIdx, rdrCfg := range erS.cfg.ERsCfg().Readers {
 		if rdrCfg.Type == utils.MetaNone { // ignore *default reader
 			continue
@@ -87,11 +99,15 @@ func (erS *ERService) ListenAndServe(stopChan, cfgRldChan chan struct{}) (err er
 			erS.closeAllRdrs()
 			return
 		case erEv := <-erS.rdrEvents:
+			status := utils.MetricProcessed
 			if err := erS.processEvent(erEv.cgrEvent, erEv.rdrCfg); err != nil {
 				utils.Logger.Warning(
 					fmt.Sprintf("<%s> reading event: <%s> got error: <%s>",
 						utils.ERs, utils.ToIJSON(erEv.cgrEvent), err.Error()))
+				status = utils.MetricFailed
 			}
+			engine.Metrics.IncCounter(utils.MetricERsEvents,
+				utils.MetricReader, erEv.rdrCfg.ID, utils.MetricStatus, status)
 		case <-cfgRldChan: // handle reload
 			cfgIDs := make(map[string]int)
 			pathReloaded := make(utils.StringSet)
@@ -157,6 +173,30 @@ func (erS *ERService) processEvent(cgrEv *utils.CGREvent,
 			fmt.Sprintf("<%s> LOG, reader: <%s>, message: %s",
 				utils.ERs, rdrCfg.ID, utils.ToIJSON(cgrEv)))
 	}
+	if len(rdrCfg.DedupID) != 0 &&
+		!rdrCfg.Flags.Has(utils.MetaDryRun) {
+		var dID string
+		if dID, err = dedupID(cgrEv, rdrCfg); err != nil {
+			return
+		}
+		if err = erS.dedupS.SetDedupID(dID, erS.cfg.ERsCfg().DedupTTL); err != nil {
+			if err == utils.ErrExists {
+				return erS.processDuplicate(cgrEv, rdrCfg, dID)
+			}
+			return
+		}
+		defer func() {
+			if err == nil {
+				return
+			}
+			// forget the failed event so it can be processed when read again
+			if errRm := erS.dedupS.RemoveDedupID(dID); errRm != nil {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> reader: <%s> failed removing dedup ID: <%s>, error: <%s>",
+						utils.ERs, rdrCfg.ID, dID, errRm.Error()))
+			}
+		}()
+	}
 	// find out reqType
 	var reqType string
 	for _, typ := range []string{
@@ -38,7 +38,7 @@ func TestERsNewERService(t *testing.T) {
 		rdrEvents: make(chan *erEvent),
 		rdrErr:    make(chan error),
 	}
-	rcv := NewERService(cfg, fltrS, nil)
+	rcv := NewERService(cfg, nil, fltrS, nil)
 
 	if !reflect.DeepEqual(expected.cfg, rcv.cfg) {
 		t.Errorf("Expecting: <%+v>, received: <%+v>", expected.cfg, rcv.cfg)
@@ -50,7 +50,7 @@ func TestERsNewERService(t *testing.T) {
 func TestERsAddReader(t *testing.T) {
 	cfg := config.NewDefaultCGRConfig()
 	fltrS := &engine.FilterS{}
-	erS := NewERService(cfg, fltrS, nil)
+	erS := NewERService(cfg, nil, fltrS, nil)
 	reader := cfg.ERsCfg().Readers[0]
 	reader.Type = utils.MetaFileCSV
 	reader.ID = "file_reader"
@@ -67,3 +67,66 @@ func TestERsAddReader(t *testing.T) {
 		t.Errorf("Expecting: <%+v>, received: <%+v>", reader, erS.rdrs["file_reader"].Config())
 	}
 }
+
+func TestERsProcessEventDedup(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	erS := NewERService(cfg, nil, &engine.FilterS{}, nil)
+	rdrCfg := &config.EventReaderCfg{
+		ID:      "dedup_reader",
+		Flags:   utils.FlagsWithParamsFromSlice([]string{utils.MetaNone}),
+		DedupID: config.NewRSRParsersMustCompile("~*req.OriginID;~*req.OriginHost", utils.InfieldSep),
+	}
+	cgrEv := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "ev1",
+		Event: map[string]interface{}{
+			utils.OriginID:   "sess1",
+			utils.OriginHost: "127.0.0.1",
+		},
+	}
+	for i := 0; i < 3; i++ {
+		if err := erS.processEvent(cgrEv, rdrCfg); err != nil {
+			t.Error(err)
+		}
+	}
+	if exp, rcv := map[string]int64{"dedup_reader": 2}, erS.dupCnt; !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expecting: %+v, received: %+v", exp, rcv)
+	}
+	cgrEv.Event[utils.OriginID] = "sess2"
+	if err := erS.processEvent(cgrEv, rdrCfg); err != nil {
+		t.Error(err)
+	}
+	if exp, rcv := map[string]int64{"dedup_reader": 2}, erS.dupCnt; !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expecting: %+v, received: %+v", exp, rcv)
+	}
+}
+
+func TestERsProcessEventDedupFailed(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	erS := NewERService(cfg, nil, &engine.FilterS{}, nil)
+	rdrCfg := &config.EventReaderCfg{
+		ID:      "dedup_reader",
+		Flags:   utils.FlagsWithParamsFromSlice([]string{"*unsupported"}),
+		DedupID: config.NewRSRParsersMustCompile("~*req.OriginID", utils.InfieldSep),
+	}
+	cgrEv := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "ev1",
+		Event: map[string]interface{}{
+			utils.OriginID: "sess1",
+		},
+	}
+	expErr := "unsupported reqType: <>"
+	for i := 0; i < 2; i++ { // failed events are not considered processed
+		if err := erS.processEvent(cgrEv, rdrCfg); err == nil || err.Error() != expErr {
+			t.Errorf("Expecting: %q, received: %v", expErr, err)
+		}
+	}
+	if rcv := erS.dupCnt; len(rcv) != 0 {
+		t.Errorf("Expecting no duplicates, received: %+v", rcv)
+	}
+	delete(cgrEv.Event, utils.OriginID)
+	if err := erS.processEvent(cgrEv, rdrCfg); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
@@ -32,8 +32,16 @@ var Guardian = &GuardianLocker{
 	refs:  make(map[string][]string)}
 
 type itemLock struct {
-	lk  chan struct{}
-	cnt int64
+	lk    chan struct{}
+	cnt   int64
+	token int64 // fencing token of the distributed lock held by the local owner, 0 if none
+}
+
+// DistributedLocker shares the locks with other engines
+type DistributedLocker interface {
+	Lock(lkID string) (token int64, err error) // blocks until the lock is acquired
+	Unlock(lkID string, token int64) error     // releases the lock if still owned by the token
+	Validate(lkID string, token int64) error   // returns utils.ErrLockLost if the lock is no longer owned by the token
 }
 
 // GuardianLocker is an optimized locking system per locking key
@@ -42,6 +50,22 @@ type GuardianLocker struct {
 	lkMux   sync.Mutex          // protects the locks
 	refs    map[string][]string // used in case of remote locks
 	refsMux sync.RWMutex        // protects the map
+	dLocker DistributedLocker   // shares the locks with other engines, nil for in-process locks only
+	dlkMux  sync.RWMutex        // protects the dLocker
+}
+
+// SetDistributedLocker sets the backend sharing the locks with other engines
+func (gl *GuardianLocker) SetDistributedLocker(dLocker DistributedLocker) {
+	gl.dlkMux.Lock()
+	gl.dLocker = dLocker
+	gl.dlkMux.Unlock()
+}
+
+func (gl *GuardianLocker) distributedLocker() (dLocker DistributedLocker) {
+	gl.dlkMux.RLock()
+	dLocker = gl.dLocker
+	gl.dlkMux.RUnlock()
+	return
 }
 
 func (gl *GuardianLocker) lockItem(itmID string) {
@@ -81,6 +105,47 @@ func (gl *GuardianLocker) unlockItem(itmID string) {
 	itmLock.lk <- struct{}{}
 }
 
+// lockItems locks the items in-process, followed by the distributed lock if configured
+// on distributed locking errors the locks already acquired are released so nothing remains locked
+func (gl *GuardianLocker) lockItems(lkIDs []string) (err error) {
+	dLocker := gl.distributedLocker()
+	for i, lkID := range lkIDs {
+		gl.lockItem(lkID)
+		if dLocker == nil || lkID == "" {
+			continue
+		}
+		var token int64
+		if token, err = dLocker.Lock(lkID); err != nil {
+			gl.unlockItems(lkIDs[:i+1]) // the failed item has no token so it is only unlocked in-process
+			return fmt.Errorf("failed distributed locking of <%s>: %s", lkID, err.Error())
+		}
+		gl.lkMux.Lock()
+		gl.locks[lkID].token = token
+		gl.lkMux.Unlock()
+	}
+	return
+}
+
+// unlockItems releases the distributed locks held, followed by the in-process ones
+func (gl *GuardianLocker) unlockItems(lkIDs []string) {
+	dLocker := gl.distributedLocker()
+	for _, lkID := range lkIDs {
+		var token int64
+		gl.lkMux.Lock()
+		if itmLock, has := gl.locks[lkID]; has {
+			token = itmLock.token
+			itmLock.token = 0
+		}
+		gl.lkMux.Unlock()
+		if token != 0 && dLocker != nil {
+			if err := dLocker.Unlock(lkID, token); err != nil {
+				utils.Logger.Warning(fmt.Sprintf("<Guardian> failed distributed unlocking of <%s>: %s", lkID, err.Error()))
+			}
+		}
+		gl.unlockItem(lkID)
+	}
+}
+
 // lockWithReference will perform locks and also generate a lock reference for it (so it can be used when remotely locking)
 func (gl *GuardianLocker) lockWithReference(refID string, lkIDs []string) string {
 	var refEmpty bool
@@ -100,8 +165,13 @@ func (gl *GuardianLocker) lockWithReference(refID string, lkIDs []string) string
 	gl.refs[refID] = lkIDs
 	gl.refsMux.Unlock()
 	// execute the real locks
-	for _, lk := range lkIDs {
-		gl.lockItem(lk)
+	if err := gl.lockItems(lkIDs); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<Guardian> %s", err.Error()))
+		gl.refsMux.Lock()
+		delete(gl.refs, refID)
+		gl.refsMux.Unlock()
+		gl.unlockItem(refID)
+		return "" // no locking was done
 	}
 	gl.unlockItem(refID)
 	return refID
@@ -119,23 +189,60 @@ func (gl *GuardianLocker) unlockWithReference(refID string) (lkIDs []string) {
 	}
 	delete(gl.refs, refID)
 	gl.refsMux.Unlock()
-	for _, lk := range lkIDs {
-		gl.unlockItem(lk)
-	}
+	gl.unlockItems(lkIDs)
 	gl.unlockItem(refID)
 	return
 }
 
+// tokens returns the fencing tokens of the distributed locks held for the lkIDs
+func (gl *GuardianLocker) tokens(lkIDs []string) (tokens map[string]int64) {
+	tokens = make(map[string]int64)
+	gl.lkMux.Lock()
+	for _, lkID := range lkIDs {
+		if itmLock, has := gl.locks[lkID]; has && itmLock.token != 0 {
+			tokens[lkID] = itmLock.token
+		}
+	}
+	gl.lkMux.Unlock()
+	return
+}
+
+// CheckToken returns utils.ErrLockLost if the distributed lock on lkID is no longer owned by the token
+// the token is the one passed by GuardWithTokens to the handler doing the write, an empty token
+// is refused with distributed locking since the write is not guarded by a lock shared with the other engines
+// the check is not atomic with the write following it: the lease renewal keeps the lock owned
+// for as long as the writer is alive so the window is limited to a lease expiring during the write
+func (gl *GuardianLocker) CheckToken(lkID string, token int64) (err error) {
+	dLocker := gl.distributedLocker()
+	if dLocker == nil {
+		return
+	}
+	if token == 0 {
+		return utils.ErrLockLost
+	}
+	return dLocker.Validate(lkID, token)
+}
+
 // Guard executes the handler between locks
+// the handler is not executed if the locks could not be acquired
 func (gl *GuardianLocker) Guard(handler func() (interface{}, error), timeout time.Duration, lockIDs ...string) (reply interface{}, err error) {
-	for _, lockID := range lockIDs {
-		gl.lockItem(lockID)
+	return gl.GuardWithTokens(func(map[string]int64) (interface{}, error) {
+		return handler()
+	}, timeout, lockIDs...)
+}
+
+// GuardWithTokens executes the handler between locks, passing it the fencing tokens of the distributed locks
+// so they can be checked with CheckToken before writing
+func (gl *GuardianLocker) GuardWithTokens(handler func(tokens map[string]int64) (interface{}, error), timeout time.Duration, lockIDs ...string) (reply interface{}, err error) {
+	if err = gl.lockItems(lockIDs); err != nil {
+		return
 	}
+	tokens := gl.tokens(lockIDs)
 	rplyChan := make(chan interface{})
 	errChan := make(chan error)
 	go func(rplyChan chan interface{}, errChan chan error) {
 		// execute
-		if rply, err := handler(); err != nil {
+		if rply, err := handler(tokens); err != nil {
 			errChan <- err
 		} else {
 			rplyChan <- rply
@@ -154,9 +261,7 @@ func (gl *GuardianLocker) Guard(handler func() (interface{}, error), timeout tim
 		case reply = <-rplyChan:
 		}
 	}
-	for _, lockID := range lockIDs {
-		gl.unlockItem(lockID)
-	}
+	gl.unlockItems(lockIDs)
 	return
 }
 
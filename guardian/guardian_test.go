@@ -18,6 +18,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package guardian
 
 import (
+	"fmt"
 	"reflect"
 	"strconv"
 	"sync"
@@ -238,6 +239,124 @@ func TestGuardianGuardIDsTimeoutConcurrent(t *testing.T) {
 	Guardian.refsMux.Unlock()
 }
 
+type dLockerMock struct {
+	mux    sync.Mutex
+	held   map[string]int64
+	tokens int64
+	calls  []string
+}
+
+func (dl *dLockerMock) Lock(lkID string) (int64, error) {
+	dl.mux.Lock()
+	defer dl.mux.Unlock()
+	if _, has := dl.held[lkID]; has {
+		return 0, fmt.Errorf("%s already locked", lkID)
+	}
+	dl.tokens++
+	dl.held[lkID] = dl.tokens
+	dl.calls = append(dl.calls, "lock:"+lkID)
+	return dl.tokens, nil
+}
+
+func (dl *dLockerMock) Unlock(lkID string, token int64) error {
+	dl.mux.Lock()
+	defer dl.mux.Unlock()
+	if dl.held[lkID] != token {
+		return fmt.Errorf("%s not owned by token %d", lkID, token)
+	}
+	delete(dl.held, lkID)
+	dl.calls = append(dl.calls, "unlock:"+lkID)
+	return nil
+}
+
+func (dl *dLockerMock) Validate(lkID string, token int64) error {
+	dl.mux.Lock()
+	defer dl.mux.Unlock()
+	if dl.held[lkID] != token {
+		return utils.ErrLockLost
+	}
+	return nil
+}
+
+func TestGuardianDistributedLocker(t *testing.T) {
+	gl := &GuardianLocker{
+		locks: make(map[string]*itemLock),
+		refs:  make(map[string][]string),
+	}
+	dl := &dLockerMock{held: make(map[string]int64)}
+	gl.SetDistributedLocker(dl)
+	if _, err := gl.Guard(func() (interface{}, error) {
+		dl.mux.Lock()
+		defer dl.mux.Unlock()
+		if len(dl.held) != 2 {
+			return nil, fmt.Errorf("expected 2 distributed locks, received: %+v", dl.held)
+		}
+		return nil, nil
+	}, 0, "test1", "test2"); err != nil {
+		t.Error(err)
+	}
+	refID := gl.GuardIDs(utils.EmptyString, 0, "test1")
+	dl.mux.Lock()
+	if dl.held["test1"] != 3 {
+		t.Errorf("expected the lock with token 3, received: %+v", dl.held)
+	}
+	dl.mux.Unlock()
+	gl.UnguardIDs(refID)
+	gl.GuardIDs(utils.EmptyString, time.Millisecond, "test2")
+	time.Sleep(10 * time.Millisecond)
+	expCalls := []string{"lock:test1", "lock:test2", "unlock:test1", "unlock:test2",
+		"lock:test1", "unlock:test1", "lock:test2", "unlock:test2"}
+	dl.mux.Lock()
+	if !reflect.DeepEqual(expCalls, dl.calls) {
+		t.Errorf("expecting: %+v, received: %+v", expCalls, dl.calls)
+	}
+	dl.mux.Unlock()
+	gl.lkMux.Lock()
+	if len(gl.locks) != 0 {
+		t.Errorf("Possible memleak for locks: %+v", gl.locks)
+	}
+	gl.lkMux.Unlock()
+}
+
+func TestGuardianDistributedLockerError(t *testing.T) {
+	gl := &GuardianLocker{
+		locks: make(map[string]*itemLock),
+		refs:  make(map[string][]string),
+	}
+	dl := &dLockerMock{held: map[string]int64{"test2": 10}} // locked by other engine
+	gl.SetDistributedLocker(dl)
+	var executed bool
+	expected := "failed distributed locking of <test2>: test2 already locked"
+	if _, err := gl.Guard(func() (interface{}, error) {
+		executed = true
+		return nil, nil
+	}, 0, "test1", "test2", "test3"); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	if executed {
+		t.Error("handler executed without holding the locks")
+	}
+	if refID := gl.GuardIDs(utils.EmptyString, 0, "test1", "test2"); refID != utils.EmptyString {
+		t.Errorf("Expected no locking, received reference: %q", refID)
+	}
+	expCalls := []string{"lock:test1", "unlock:test1", "lock:test1", "unlock:test1"}
+	dl.mux.Lock()
+	if !reflect.DeepEqual(expCalls, dl.calls) {
+		t.Errorf("expecting: %+v, received: %+v", expCalls, dl.calls)
+	}
+	dl.mux.Unlock()
+	gl.lkMux.Lock()
+	if len(gl.locks) != 0 {
+		t.Errorf("Possible memleak for locks: %+v", gl.locks)
+	}
+	gl.lkMux.Unlock()
+	gl.refsMux.Lock()
+	if len(gl.refs) != 0 {
+		t.Errorf("Possible memleak for refs: %+v", gl.refs)
+	}
+	gl.refsMux.Unlock()
+}
+
 // BenchmarkGuard-8      	  200000	     13759 ns/op
 func BenchmarkGuard(b *testing.B) {
 	for n := 0; n < b.N; n++ {
@@ -278,3 +397,66 @@ func BenchmarkGuardIDs(b *testing.B) {
 		}(n)
 	}
 }
+
+func TestGuardianGuardWithTokens(t *testing.T) {
+	gl := &GuardianLocker{
+		locks: make(map[string]*itemLock),
+		refs:  make(map[string][]string),
+	}
+	if _, err := gl.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+		if len(tokens) != 0 {
+			return nil, fmt.Errorf("expected no tokens, received: %+v", tokens)
+		}
+		return nil, gl.CheckToken("test1", tokens["test1"])
+	}, 0, "test1"); err != nil {
+		t.Error(err)
+	}
+	dl := &dLockerMock{held: make(map[string]int64)}
+	gl.SetDistributedLocker(dl)
+	if err := gl.CheckToken("test1", 0); err != utils.ErrLockLost {
+		t.Errorf("expected %+v for a write without lock, received: %+v", utils.ErrLockLost, err)
+	}
+	if _, err := gl.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+		if exp := map[string]int64{"test1": 1, "test2": 2}; !reflect.DeepEqual(exp, tokens) {
+			return nil, fmt.Errorf("expected tokens: %+v, received: %+v", exp, tokens)
+		}
+		if err := gl.CheckToken("test1", tokens["test1"]); err != nil {
+			return nil, err
+		}
+		dl.mux.Lock()
+		dl.held["test2"] = 10 // lease expired and the lock was taken by other engine
+		dl.mux.Unlock()
+		if err := gl.CheckToken("test2", tokens["test2"]); err != utils.ErrLockLost {
+			return nil, fmt.Errorf("expected %+v, received: %+v", utils.ErrLockLost, err)
+		}
+		return nil, nil
+	}, 0, "test1", "test2"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGuardianGuardWithTokensTimeout(t *testing.T) {
+	gl := &GuardianLocker{
+		locks: make(map[string]*itemLock),
+		refs:  make(map[string][]string),
+	}
+	dl := &dLockerMock{held: make(map[string]int64)}
+	gl.SetDistributedLocker(dl)
+	stale := make(chan map[string]int64, 1)
+	release := make(chan struct{})
+	gl.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+		stale <- tokens
+		<-release // keeps running after the locks are timed-out
+		return nil, nil
+	}, 10*time.Millisecond, "test1")
+	staleTokens := <-stale
+	if _, err := gl.GuardWithTokens(func(tokens map[string]int64) (interface{}, error) {
+		if err := gl.CheckToken("test1", staleTokens["test1"]); err != utils.ErrLockLost {
+			return nil, fmt.Errorf("expected %+v for the stale handler, received: %+v", utils.ErrLockLost, err)
+		}
+		return nil, gl.CheckToken("test1", tokens["test1"])
+	}, 0, "test1"); err != nil {
+		t.Error(err)
+	}
+	close(release)
+}
@@ -0,0 +1,158 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package guardian
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+	"github.com/mediocregopher/radix/v3"
+)
+
+const (
+	redisLockRetry    = 5 * time.Millisecond   // first interval between the attempts to acquire a lock held by somebody else
+	redisLockRetryMax = 100 * time.Millisecond // the interval is doubled on each attempt up to this value
+)
+
+var (
+	// extends the lease only if the lock is still owned
+	redisRenewScript = radix.NewEvalScript(1, `if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0`)
+	// deletes the lock only if still owned
+	redisReleaseScript = radix.NewEvalScript(1, `if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+)
+
+// NewRedisLocker returns a DistributedLocker sharing the locks over the Redis client
+// the locks held by somebody else are waited for at most acquireTimeout
+func NewRedisLocker(client radix.Client, lease, acquireTimeout time.Duration) *RedisLocker {
+	return &RedisLocker{
+		client:         client,
+		lease:          lease,
+		acquireTimeout: acquireTimeout,
+		renews:         make(map[string]chan struct{}),
+	}
+}
+
+// RedisLocker locks the items in Redis so they are shared between the engines connected to it
+// every lock is owned by a fencing token, unique and increasing over all the locks,
+// and is kept with a lease renewed in the background while the lock is held
+type RedisLocker struct {
+	client         radix.Client
+	lease          time.Duration
+	acquireTimeout time.Duration
+
+	renews   map[string]chan struct{} // stops the lease renewal of the locks held, indexed on lkID
+	renewMux sync.Mutex
+}
+
+// Lock blocks until the lock is acquired, returning its fencing token
+// utils.ErrLockTimeout is returned if the lock is still held by somebody else after the acquire timeout
+func (rl *RedisLocker) Lock(lkID string) (token int64, err error) {
+	if err = rl.client.Do(radix.Cmd(&token, "INCR", utils.GuardianFencingKey)); err != nil {
+		return
+	}
+	key := utils.GuardianLockPrefix + lkID
+	tokenStr := strconv.FormatInt(token, 10)
+	leaseMs := strconv.FormatInt(rl.lease.Milliseconds(), 10)
+	deadline := time.Now().Add(rl.acquireTimeout)
+	for retry := redisLockRetry; ; retry *= 2 {
+		var rply string
+		mn := radix.MaybeNil{Rcv: &rply}
+		if err = rl.client.Do(radix.Cmd(&mn, "SET", key, tokenStr, "NX", "PX", leaseMs)); err != nil {
+			return
+		}
+		if !mn.Nil {
+			break
+		}
+		if retry > redisLockRetryMax {
+			retry = redisLockRetryMax
+		}
+		if time.Now().Add(retry).After(deadline) {
+			return 0, utils.ErrLockTimeout
+		}
+		time.Sleep(retry)
+	}
+	stop := make(chan struct{})
+	rl.renewMux.Lock()
+	rl.renews[lkID] = stop
+	rl.renewMux.Unlock()
+	go rl.renewLease(key, tokenStr, leaseMs, stop)
+	return
+}
+
+// renewLease extends the lease of the lock until stopped or the lock is lost
+func (rl *RedisLocker) renewLease(key, tokenStr, leaseMs string, stop chan struct{}) {
+	ticker := time.NewTicker(rl.lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			var renewed int
+			if err := rl.client.Do(redisRenewScript.Cmd(&renewed, key, tokenStr, leaseMs)); err != nil {
+				utils.Logger.Warning(fmt.Sprintf("<Guardian> failed renewing the lease of <%s>: %s", key, err.Error()))
+				continue
+			}
+			if renewed == 0 {
+				utils.Logger.Warning(fmt.Sprintf("<Guardian> lost the lock on <%s> with token %s", key, tokenStr))
+				return
+			}
+		}
+	}
+}
+
+// Unlock releases the lock if still owned by the token
+func (rl *RedisLocker) Unlock(lkID string, token int64) (err error) {
+	rl.renewMux.Lock()
+	if stop, has := rl.renews[lkID]; has {
+		close(stop)
+		delete(rl.renews, lkID)
+	}
+	rl.renewMux.Unlock()
+	var released int
+	return rl.client.Do(redisReleaseScript.Cmd(&released,
+		utils.GuardianLockPrefix+lkID, strconv.FormatInt(token, 10)))
+}
+
+// Validate returns utils.ErrLockLost if the lock is no longer owned by the token
+func (rl *RedisLocker) Validate(lkID string, token int64) (err error) {
+	var owner string
+	mn := radix.MaybeNil{Rcv: &owner}
+	if err = rl.client.Do(radix.Cmd(&mn, "GET", utils.GuardianLockPrefix+lkID)); err != nil {
+		return
+	}
+	if mn.Nil || owner != strconv.FormatInt(token, 10) {
+		return utils.ErrLockLost
+	}
+	return
+}
+
+// Close closes the connections to Redis
+func (rl *RedisLocker) Close() error {
+	return rl.client.Close()
+}
@@ -0,0 +1,119 @@
+// +build integration
+
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package guardian
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+	"github.com/mediocregopher/radix/v3"
+)
+
+// two lockers simulating two engines sharing the same Redis
+func TestRedisLockerIT(t *testing.T) {
+	client1, err := radix.NewPool(utils.TCP, "127.0.0.1:6379", 1,
+		radix.PoolConnFunc(func(network, addr string) (radix.Conn, error) {
+			return radix.Dial(network, addr, radix.DialSelectDB(10))
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl1 := NewRedisLocker(client1, 100*time.Millisecond, time.Second)
+	defer rl1.Close()
+	client2, err := radix.NewPool(utils.TCP, "127.0.0.1:6379", 1,
+		radix.PoolConnFunc(func(network, addr string) (radix.Conn, error) {
+			return radix.Dial(network, addr, radix.DialSelectDB(10))
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl2 := NewRedisLocker(client2, 100*time.Millisecond, time.Second)
+	defer rl2.Close()
+
+	token1, err := rl1.Lock("ACC1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked := make(chan int64)
+	go func() {
+		token2, err := rl2.Lock("ACC1")
+		if err != nil {
+			t.Error(err)
+		}
+		locked <- token2
+	}()
+	select {
+	case <-locked:
+		t.Fatal("lock acquired while held by the other engine")
+	case <-time.After(300 * time.Millisecond): // longer than the lease, the renewal keeping the lock
+	}
+	if err := rl1.Unlock("ACC1", token1); err != nil {
+		t.Error(err)
+	}
+	var token2 int64
+	select {
+	case token2 = <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("lock not acquired after release")
+	}
+	if token2 <= token1 {
+		t.Errorf("expecting fencing token bigger than %d, received: %d", token1, token2)
+	}
+	// the stale token does not release the lock of the new owner
+	if err := rl1.Unlock("ACC1", token1); err != nil {
+		t.Error(err)
+	}
+	go func() {
+		token, err := rl1.Lock("ACC1")
+		if err != nil {
+			t.Error(err)
+		}
+		locked <- token
+	}()
+	select {
+	case <-locked:
+		t.Fatal("lock released with a stale token")
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := rl2.Unlock("ACC1", token2); err != nil {
+		t.Error(err)
+	}
+	select {
+	case token1 = <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("lock not acquired after release")
+	}
+	if err := rl1.Unlock("ACC1", token1); err != nil {
+		t.Error(err)
+	}
+	// the lock held by the other engine is waited for at most the acquire timeout
+	if token1, err = rl1.Lock("ACC1"); err != nil {
+		t.Fatal(err)
+	}
+	rl3 := NewRedisLocker(client2, 100*time.Millisecond, 200*time.Millisecond)
+	if _, err := rl3.Lock("ACC1"); err != utils.ErrLockTimeout {
+		t.Errorf("Expected %+v, received %+v", utils.ErrLockTimeout, err)
+	}
+	if err := rl1.Unlock("ACC1", token1); err != nil {
+		t.Error(err)
+	}
+}
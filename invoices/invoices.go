@@ -0,0 +1,347 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package invoices
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/cron"
+)
+
+// invoiceNumberRetries limits the attempts of numbering one invoice
+const invoiceNumberRetries = 10
+
+// NewInvoiceS instantiates the InvoiceS
+func NewInvoiceS(cfg *config.CGRConfig, dm *engine.DataManager, storDBChan chan engine.StorDB,
+	connMgr *engine.ConnManager) *InvoiceS {
+	return &InvoiceS{
+		cfg:        cfg,
+		dm:         dm,
+		storDB:     <-storDBChan,
+		storDBChan: storDBChan,
+		connMgr:    connMgr,
+	}
+}
+
+// InvoiceS generates the invoices of the accounts, out of the CDRs and the ActionProfiles
+type InvoiceS struct {
+	cfg        *config.CGRConfig
+	dm         *engine.DataManager
+	storDB     engine.CdrStorage
+	storDBChan chan engine.StorDB
+	connMgr    *engine.ConnManager
+}
+
+// ListenAndServe keeps the service alive, listening for the StorDB reloads
+func (iS *InvoiceS) ListenAndServe(stopChan, cfgRld chan struct{}) {
+	utils.Logger.Info(fmt.Sprintf("<%s> starting <%s>",
+		utils.CoreS, utils.InvoiceS))
+	for {
+		select {
+		case <-stopChan:
+			return
+		case rld := <-cfgRld: // configuration was reloaded
+			cfgRld <- rld
+		case storDB, ok := <-iS.storDBChan:
+			if !ok { // the chanel was closed by the shutdown of stordbService
+				return
+			}
+			iS.storDB = storDB
+		}
+	}
+}
+
+// Shutdown is called to shutdown the service
+func (iS *InvoiceS) Shutdown() (err error) {
+	utils.Logger.Info(fmt.Sprintf("<%s> shutdown <%s>", utils.CoreS, utils.InvoiceS))
+	return
+}
+
+// Call implements rpcclient.ClientConnector interface for internal RPC
+func (iS *InvoiceS) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return utils.RPCCall(iS, serviceMethod, args, reply)
+}
+
+// generateInvoice builds, numbers and stores the invoice of the account for the billing cycle
+// only the numbering is locked, the invoices of the cycle generated meanwhile being rejected by the StorDB
+func (iS *InvoiceS) generateInvoice(tnt, acntID string, cycleStart, cycleEnd time.Time) (inv *engine.Invoice, err error) {
+	if _, err = iS.storDB.GetInvoices(&utils.InvoicesFilter{
+		Tenants:  []string{tnt},
+		Accounts: []string{acntID},
+		Cycle:    utils.TimeInterval{Begin: &cycleStart, End: &cycleEnd},
+	}); err == nil {
+		return nil, utils.ErrExists
+	} else if err != utils.ErrNotFound {
+		return
+	}
+	inv = &engine.Invoice{
+		Tenant:     tnt,
+		Account:    acntID,
+		CycleStart: cycleStart,
+		CycleEnd:   cycleEnd,
+	}
+	var lines []*engine.InvoiceLine
+	if lines, err = iS.usageLines(tnt, acntID, cycleStart, cycleEnd); err != nil {
+		return nil, err
+	}
+	inv.Lines = append(inv.Lines, lines...)
+	if lines, err = iS.recurringLines(tnt, acntID, cycleStart, cycleEnd); err != nil {
+		return nil, err
+	}
+	inv.Lines = append(inv.Lines, lines...)
+	if lines, err = iS.balanceLines(tnt, acntID, cycleStart, cycleEnd); err != nil {
+		return nil, err
+	}
+	inv.Lines = append(inv.Lines, lines...)
+	for _, line := range inv.Lines {
+		if line.Type != utils.MetaBalance { // the balance operations are informative
+			inv.Total += line.Amount
+		}
+	}
+	inv.Total = utils.Round(inv.Total, iS.cfg.GeneralCfg().RoundingDecimals, utils.MetaRoundingMiddle)
+	if err = iS.numberInvoice(inv); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// numberInvoice numbers and stores the invoice, with the numbering of the tenant locked meanwhile
+// the StorDB keeps the numbers and the cycles of the accounts unique within the tenant so on conflict
+// the next number is tried (ie: another engine sharing the StorDB) unless the invoice of the cycle was stored meanwhile
+func (iS *InvoiceS) numberInvoice(inv *engine.Invoice) (err error) {
+	for i := 0; i < invoiceNumberRetries; i++ {
+		var rply interface{}
+		if rply, err = guardian.Guardian.Guard(func() (interface{}, error) {
+			nr, err := iS.nextNumber(inv.Tenant)
+			if err != nil {
+				return nil, err
+			}
+			inv.Number = nr
+			inv.ID = fmt.Sprintf("%s%06d", iS.cfg.InvoiceSCfg().NumberPrefix, inv.Number)
+			inv.CreatedAt = time.Now()
+			return inv, iS.storDB.SetInvoice(inv)
+		}, iS.cfg.GeneralCfg().LockingTimeout, utils.ConcatenatedKey(utils.CacheInvoicesTBL, inv.Tenant)); err != utils.ErrExists {
+			if err == nil && rply == nil { // the lock timed out before the invoice was stored
+				err = utils.ErrTimedOut
+			}
+			return
+		}
+		if _, err = iS.storDB.GetInvoices(&utils.InvoicesFilter{
+			Tenants:  []string{inv.Tenant},
+			Accounts: []string{inv.Account},
+			Cycle:    utils.TimeInterval{Begin: &inv.CycleStart, End: &inv.CycleEnd},
+		}); err != utils.ErrNotFound {
+			if err == nil {
+				err = utils.ErrExists
+			}
+			return
+		}
+	}
+	return utils.ErrExists
+}
+
+// nextNumber returns the number following the last one of the tenant
+func (iS *InvoiceS) nextNumber(tnt string) (nr int64, err error) {
+	var invs []*engine.Invoice
+	if invs, err = iS.storDB.GetInvoices(&utils.InvoicesFilter{
+		Tenants:   []string{tnt},
+		Paginator: utils.Paginator{Limit: utils.IntPointer(1)},
+	}); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		return 1, nil
+	}
+	return invs[0].Number + 1, nil
+}
+
+// usageLines are the charges of the CDRs answered within the cycle, one line for each ToR and Category
+func (iS *InvoiceS) usageLines(tnt, acntID string, cycleStart, cycleEnd time.Time) (lines []*engine.InvoiceLine, err error) {
+	return iS.cdrsLines(&utils.CDRsFilter{
+		Tenants:         []string{tnt},
+		Accounts:        []string{acntID},
+		RunIDs:          iS.cfg.InvoiceSCfg().RunIDs,
+		NotSources:      []string{utils.MetaCdrLog},
+		AnswerTimeStart: &cycleStart,
+		AnswerTimeEnd:   &cycleEnd,
+		MinCost:         utils.Float64Pointer(0), // not the ones failed to be rated
+	}, utils.MetaUsage, utils.ToR, utils.Category)
+}
+
+// balanceLines are the balance operations logged by ActionS within the cycle, one line for each action and balance type
+func (iS *InvoiceS) balanceLines(tnt, acntID string, cycleStart, cycleEnd time.Time) (lines []*engine.InvoiceLine, err error) {
+	return iS.cdrsLines(&utils.CDRsFilter{
+		Tenants:         []string{tnt},
+		Accounts:        []string{acntID},
+		Sources:         []string{utils.MetaCdrLog},
+		AnswerTimeStart: &cycleStart,
+		AnswerTimeEnd:   &cycleEnd,
+	}, utils.MetaBalance, utils.RunID, utils.ToR)
+}
+
+// cdrsLines aggregates the CDRs in the StorDB, one line for each group
+func (iS *InvoiceS) cdrsLines(fltr *utils.CDRsFilter, lineType string, groupBy ...string) (lines []*engine.InvoiceLine, err error) {
+	var grps []*utils.CDRsGroupBy
+	if grps, err = utils.NewCDRsGroupBy(groupBy); err != nil {
+		return
+	}
+	var aggrs []*utils.CDRsAggregate
+	if aggrs, err = iS.storDB.GetCDRsAggregates(fltr, grps, nil); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	lines = make([]*engine.InvoiceLine, len(aggrs))
+	for i, aggr := range aggrs {
+		vals := make([]string, len(groupBy))
+		for j, grp := range groupBy {
+			vals[j] = aggr.Group[grp]
+		}
+		lines[i] = &engine.InvoiceLine{
+			Type:        lineType,
+			Description: utils.ConcatenatedKey(vals...),
+			Quantity:    aggr.Count,
+			Usage:       aggr.Usage,
+			Amount:      utils.Round(aggr.Cost, iS.cfg.GeneralCfg().RoundingDecimals, utils.MetaRoundingMiddle),
+		}
+	}
+	return
+}
+
+// recurringLines are the fees of the actions scheduled for the account within the cycle
+// the fee of one execution is defined by the *invoiceFee option of the action
+func (iS *InvoiceS) recurringLines(tnt, acntID string, cycleStart, cycleEnd time.Time) (lines []*engine.InvoiceLine, err error) {
+	prfx := utils.ActionProfilePrefix + tnt + utils.ConcatenatedKeySep
+	var keys []string
+	if keys, err = iS.dm.DataDB().GetKeysForPrefix(prfx); err != nil {
+		return
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var aPf *engine.ActionProfile
+		if aPf, err = iS.dm.GetActionProfile(tnt, key[len(prfx):],
+			true, true, utils.NonTransactional); err != nil {
+			if err == utils.ErrNotFound {
+				err = nil
+				continue
+			}
+			return
+		}
+		if !aPf.Targets[utils.MetaAccounts].Has(acntID) {
+			continue
+		}
+		var execs int64
+		if execs, err = scheduledExecutions(aPf, cycleStart, cycleEnd); err != nil {
+			return nil, err
+		} else if execs == 0 {
+			continue
+		}
+		for _, act := range aPf.Actions {
+			fee, has := act.Opts[utils.OptsInvoiceFee]
+			if !has {
+				continue
+			}
+			var feeVal float64
+			if feeVal, err = utils.IfaceAsFloat64(fee); err != nil {
+				return nil, fmt.Errorf("invalid %s for action <%s> of ActionProfile <%s>: %s",
+					utils.OptsInvoiceFee, act.ID, aPf.TenantID(), err.Error())
+			}
+			lines = append(lines, &engine.InvoiceLine{
+				Type:        utils.MetaRecurring,
+				Description: utils.ConcatenatedKey(aPf.ID, act.ID),
+				Quantity:    execs,
+				Amount: utils.Round(feeVal*float64(execs),
+					iS.cfg.GeneralCfg().RoundingDecimals, utils.MetaRoundingMiddle),
+			})
+		}
+	}
+	return
+}
+
+// scheduledExecutions returns how many times the ActionProfile is executed by its schedule within the cycle
+// the profiles executed *asap are not recurring
+func scheduledExecutions(aPf *engine.ActionProfile, cycleStart, cycleEnd time.Time) (execs int64, err error) {
+	if aPf.Schedule == utils.EmptyString || aPf.Schedule == utils.MetaASAP {
+		return
+	}
+	var sched cron.Schedule
+	if sched, err = cron.ParseStandard(aPf.Schedule); err != nil {
+		return 0, fmt.Errorf("invalid schedule of ActionProfile <%s>: %s", aPf.TenantID(), err.Error())
+	}
+	for next := sched.Next(cycleStart.Add(-time.Nanosecond)); !next.IsZero() && next.Before(cycleEnd); next = sched.Next(next) {
+		if aPf.ActivationInterval != nil && !aPf.ActivationInterval.IsActiveAtTime(next) {
+			continue
+		}
+		execs++
+	}
+	return
+}
+
+// V1GenerateInvoice generates the invoice of the account for the billing cycle and renders it through EEs
+func (iS *InvoiceS) V1GenerateInvoice(args *utils.ArgsGenerateInvoice, reply *engine.Invoice) (err error) {
+	if missing := utils.MissingStructFields(args, []string{utils.AccountField}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if args.CycleStart.IsZero() || !args.CycleEnd.After(args.CycleStart) {
+		return utils.NewErrMandatoryIeMissing(utils.CycleStart, utils.CycleEnd)
+	}
+	tnt := args.Tenant
+	if tnt == utils.EmptyString {
+		tnt = iS.cfg.GeneralCfg().DefaultTenant
+	}
+	var inv *engine.Invoice
+	if inv, err = iS.generateInvoice(tnt, args.Account, args.CycleStart, args.CycleEnd); err != nil {
+		return
+	}
+	if len(iS.cfg.InvoiceSCfg().EEsConns) != 0 {
+		var rply map[string]map[string]interface{}
+		if err := iS.connMgr.Call(iS.cfg.InvoiceSCfg().EEsConns, nil, utils.EeSv1ProcessEvent,
+			&utils.CGREventWithEeIDs{
+				EeIDs:    iS.cfg.InvoiceSCfg().ExporterIDs,
+				CGREvent: inv.AsCGREvent(),
+			}, &rply); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: <%s> rendering the invoice <%s>",
+					utils.InvoiceS, err.Error(), inv.TenantID()))
+		}
+	}
+	*reply = *inv
+	return
+}
+
+// V1GetInvoices returns the stored invoices matching the filter, the last numbered first
+func (iS *InvoiceS) V1GetInvoices(args *utils.InvoicesFilterWithOpts, reply *[]*engine.Invoice) (err error) {
+	fltr := new(utils.InvoicesFilter)
+	if args.InvoicesFilter != nil {
+		fltr = args.InvoicesFilter
+	}
+	var invs []*engine.Invoice
+	if invs, err = iS.storDB.GetInvoices(fltr); err != nil {
+		return
+	}
+	*reply = invs
+	return
+}
@@ -0,0 +1,247 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package invoices
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestScheduledExecutions(t *testing.T) {
+	cycleStart := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+	aPf := &engine.ActionProfile{Tenant: "cgrates.org", ID: "AP_MONTHLY", Schedule: "0 0 1 * *"}
+	if execs, err := scheduledExecutions(aPf, cycleStart, cycleEnd); err != nil {
+		t.Error(err)
+	} else if execs != 3 {
+		t.Errorf("Expected 3 executions, received: %d", execs)
+	}
+	aPf.ActivationInterval = &utils.ActivationInterval{
+		ActivationTime: time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC)}
+	if execs, err := scheduledExecutions(aPf, cycleStart, cycleEnd); err != nil {
+		t.Error(err)
+	} else if execs != 1 {
+		t.Errorf("Expected 1 execution, received: %d", execs)
+	}
+	aPf.Schedule = utils.MetaASAP
+	if execs, err := scheduledExecutions(aPf, cycleStart, cycleEnd); err != nil {
+		t.Error(err)
+	} else if execs != 0 {
+		t.Errorf("Expected no executions, received: %d", execs)
+	}
+	aPf.Schedule = "* *"
+	if _, err := scheduledExecutions(aPf, cycleStart, cycleEnd); err == nil {
+		t.Error("Expected error for the invalid schedule")
+	}
+}
+
+func TestInvoiceSV1GenerateInvoice(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	cfg.InvoiceSCfg().NumberPrefix = "INV"
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	storDBChan := make(chan engine.StorDB, 1)
+	storDBChan <- engine.NewInternalDB(nil, nil, false)
+	iS := NewInvoiceS(cfg, dm, storDBChan, nil)
+
+	for _, aPf := range []*engine.ActionProfile{
+		{Tenant: "cgrates.org", ID: "AP_SUBSCRIPTION", Schedule: "0 0 1 * *",
+			Targets: map[string]utils.StringSet{utils.MetaAccounts: utils.NewStringSet([]string{"1001", "1002"})},
+			Actions: []*engine.APAction{
+				{ID: "FEE", Type: utils.MetaLog, Opts: map[string]interface{}{utils.OptsInvoiceFee: "9.99"}},
+				{ID: "NOTIFY", Type: utils.MetaHTTPPost},
+			}},
+		{Tenant: "cgrates.org", ID: "AP_DAILY", Schedule: "0 12 * * *",
+			Targets: map[string]utils.StringSet{utils.MetaAccounts: utils.NewStringSet([]string{"1002"})},
+			Actions: []*engine.APAction{
+				{ID: "FEE", Type: utils.MetaLog, Opts: map[string]interface{}{utils.OptsInvoiceFee: 1}},
+			}},
+	} {
+		if err := dm.SetActionProfile(aPf, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	aTime := time.Date(2021, 1, 10, 10, 0, 0, 0, time.UTC)
+	for _, cdr := range []*engine.CDR{
+		{CGRID: "call1", RunID: utils.MetaDefault, Tenant: "cgrates.org", Account: "1001", ToR: utils.MetaVoice,
+			Category: "call", Source: "SessionS", AnswerTime: aTime, Usage: time.Minute, Cost: 0.1},
+		{CGRID: "call2", RunID: utils.MetaDefault, Tenant: "cgrates.org", Account: "1001", ToR: utils.MetaVoice,
+			Category: "call", Source: "SessionS", AnswerTime: aTime, Usage: 2 * time.Minute, Cost: 0.2},
+		{CGRID: "call2", RunID: "supplier", Tenant: "cgrates.org", Account: "1001", ToR: utils.MetaVoice,
+			Category: "call", Source: "SessionS", AnswerTime: aTime, Usage: 2 * time.Minute, Cost: 0.15},
+		{CGRID: "call3", RunID: utils.MetaDefault, Tenant: "cgrates.org", Account: "1001", ToR: utils.MetaVoice,
+			Category: "call", Source: "SessionS", AnswerTime: aTime, Usage: time.Minute, Cost: -1},
+		{CGRID: "sms1", RunID: utils.MetaDefault, Tenant: "cgrates.org", Account: "1001", ToR: utils.MetaSMS,
+			Category: "sms", Source: "SessionS", AnswerTime: aTime, Usage: 1, Cost: 0.05},
+		{CGRID: "call4", RunID: utils.MetaDefault, Tenant: "cgrates.org", Account: "1001", ToR: utils.MetaVoice,
+			Category: "call", Source: "SessionS", AnswerTime: aTime.AddDate(0, 1, 0), Usage: time.Minute, Cost: 0.1},
+		{CGRID: "topup1", RunID: utils.MetaTopUp, Tenant: "cgrates.org", Account: "1001", ToR: utils.MetaMonetary,
+			Source: utils.MetaCdrLog, AnswerTime: aTime, Usage: 1, Cost: 20},
+	} {
+		if err := iS.storDB.SetCDR(cdr, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var reply engine.Invoice
+	if err := iS.V1GenerateInvoice(&utils.ArgsGenerateInvoice{Account: "1001"},
+		&reply); err == nil || err.Error() != "MANDATORY_IE_MISSING: [CycleStart CycleEnd]" {
+		t.Errorf("Expected error, received: %v", err)
+	}
+	cycleStart := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	if err := iS.V1GenerateInvoice(&utils.ArgsGenerateInvoice{Account: "1001",
+		CycleStart: cycleStart, CycleEnd: cycleEnd}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	exp := engine.Invoice{
+		Tenant:     "cgrates.org",
+		ID:         "INV000001",
+		Number:     1,
+		Account:    "1001",
+		CycleStart: cycleStart,
+		CycleEnd:   cycleEnd,
+		CreatedAt:  reply.CreatedAt,
+		Lines: []*engine.InvoiceLine{
+			{Type: utils.MetaUsage, Description: "*sms:sms", Quantity: 1, Usage: 1, Amount: 0.05},
+			{Type: utils.MetaUsage, Description: "*voice:call", Quantity: 2, Usage: 3 * time.Minute, Amount: 0.3},
+			{Type: utils.MetaRecurring, Description: "AP_SUBSCRIPTION:FEE", Quantity: 1, Amount: 9.99},
+			{Type: utils.MetaBalance, Description: "*topup:*monetary", Quantity: 1, Usage: 1, Amount: 20},
+		},
+		Total: 10.34,
+	}
+	if !reflect.DeepEqual(exp, reply) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(reply))
+	}
+	if err := iS.V1GenerateInvoice(&utils.ArgsGenerateInvoice{Account: "1001",
+		CycleStart: cycleStart.Add(24 * time.Hour), CycleEnd: cycleEnd}, &reply); err != utils.ErrExists {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrExists, err)
+	}
+
+	var reply2 engine.Invoice
+	if err := iS.V1GenerateInvoice(&utils.ArgsGenerateInvoice{Account: "1002",
+		CycleStart: cycleStart, CycleEnd: cycleEnd}, &reply2); err != nil {
+		t.Fatal(err)
+	}
+	if reply2.ID != "INV000002" || reply2.Total != 40.99 || len(reply2.Lines) != 2 ||
+		reply2.Lines[0].Description != "AP_DAILY:FEE" || reply2.Lines[0].Quantity != 31 {
+		t.Errorf("Unexpected invoice: %s", utils.ToJSON(reply2))
+	}
+
+	var invs []*engine.Invoice
+	if err := iS.V1GetInvoices(&utils.InvoicesFilterWithOpts{}, &invs); err != nil {
+		t.Error(err)
+	} else if len(invs) != 2 || invs[0].ID != "INV000002" || invs[1].ID != "INV000001" {
+		t.Errorf("Unexpected invoices: %s", utils.ToJSON(invs))
+	}
+	if err := iS.V1GetInvoices(&utils.InvoicesFilterWithOpts{
+		InvoicesFilter: &utils.InvoicesFilter{Accounts: []string{"1001"}}}, &invs); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]*engine.Invoice{&exp}, invs) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON([]*engine.Invoice{&exp}), utils.ToJSON(invs))
+	}
+	if err := iS.V1GetInvoices(&utils.InvoicesFilterWithOpts{
+		InvoicesFilter: &utils.InvoicesFilter{Cycle: utils.TimeInterval{Begin: &cycleEnd}}},
+		&invs); err != utils.ErrNotFound {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+// staleNumberStorDB misses the last invoice once, as if it was stored meanwhile by another engine
+type staleNumberStorDB struct {
+	engine.CdrStorage
+	missed bool
+}
+
+func (db *staleNumberStorDB) GetInvoices(fltr *utils.InvoicesFilter) ([]*engine.Invoice, error) {
+	if !db.missed && fltr.Paginator.Limit != nil {
+		db.missed = true
+		return nil, utils.ErrNotFound
+	}
+	return db.CdrStorage.GetInvoices(fltr)
+}
+
+func TestInvoiceSNumberTaken(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	storDBChan := make(chan engine.StorDB, 1)
+	storDBChan <- engine.NewInternalDB(nil, nil, false)
+	iS := NewInvoiceS(cfg, dm, storDBChan, nil)
+	if err := iS.storDB.SetInvoice(&engine.Invoice{Tenant: "cgrates.net", ID: "000001",
+		Number: 1, Account: "1001"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := iS.storDB.SetInvoice(&engine.Invoice{Tenant: "cgrates.net", ID: "000001b",
+		Number: 1, Account: "1002"}); err != utils.ErrExists {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrExists, err)
+	}
+	iS.storDB = &staleNumberStorDB{CdrStorage: iS.storDB}
+	cycleStart := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	if inv, err := iS.generateInvoice("cgrates.net", "1002",
+		cycleStart, cycleStart.AddDate(0, 1, 0)); err != nil {
+		t.Fatal(err)
+	} else if inv.Number != 2 || inv.ID != "000002" {
+		t.Errorf("Expected the next number, received: %s", utils.ToJSON(inv))
+	}
+}
+
+// staleCycleStorDB misses the invoice of the cycle once, as if it was generated meanwhile
+type staleCycleStorDB struct {
+	engine.CdrStorage
+	missed bool
+}
+
+func (db *staleCycleStorDB) GetInvoices(fltr *utils.InvoicesFilter) ([]*engine.Invoice, error) {
+	if !db.missed && len(fltr.Accounts) != 0 {
+		db.missed = true
+		return nil, utils.ErrNotFound
+	}
+	return db.CdrStorage.GetInvoices(fltr)
+}
+
+func TestInvoiceSCycleTaken(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), cfg.CacheCfg(), nil)
+	storDBChan := make(chan engine.StorDB, 1)
+	storDBChan <- engine.NewInternalDB(nil, nil, false)
+	iS := NewInvoiceS(cfg, dm, storDBChan, nil)
+	cycleStart := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := cycleStart.AddDate(0, 1, 0)
+	if err := iS.storDB.SetInvoice(&engine.Invoice{Tenant: "cgrates.com", ID: "000001",
+		Number: 1, Account: "1001", CycleStart: cycleStart, CycleEnd: cycleEnd}); err != nil {
+		t.Fatal(err)
+	}
+	if err := iS.storDB.SetInvoice(&engine.Invoice{Tenant: "cgrates.com", ID: "000002",
+		Number: 2, Account: "1001", CycleStart: cycleStart, CycleEnd: cycleEnd}); err != utils.ErrExists {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrExists, err)
+	}
+	iS.storDB = &staleCycleStorDB{CdrStorage: iS.storDB}
+	if _, err := iS.generateInvoice("cgrates.com", "1001", cycleStart, cycleEnd); err != utils.ErrExists {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrExists, err)
+	}
+	if invs, err := iS.storDB.GetInvoices(&utils.InvoicesFilter{Tenants: []string{"cgrates.com"}}); err != nil {
+		t.Error(err)
+	} else if len(invs) != 1 || invs[0].Number != 1 {
+		t.Errorf("Expected only the first invoice, received: %s", utils.ToJSON(invs))
+	}
+}
@@ -50,7 +50,13 @@ func (ld LoaderData) GetRateIDs() ([]string, error) {
 // contained in record and processed with cfgTpl
 func (ld LoaderData) UpdateFromCSV(fileName string, record []string,
 	cfgTpl []*config.FCTemplate, tnt config.RSRParsers, filterS *engine.FilterS) (err error) {
-	csvProvider := newCsvProvider(record, fileName)
+	return ld.UpdateFromDP(newCsvProvider(record, fileName), cfgTpl, tnt, filterS)
+}
+
+// UpdateFromDP will update LoaderData with the record from csvProvider
+// processed with cfgTpl
+func (ld LoaderData) UpdateFromDP(csvProvider utils.DataProvider,
+	cfgTpl []*config.FCTemplate, tnt config.RSRParsers, filterS *engine.FilterS) (err error) {
 	tenant, err := tnt.ParseValue("")
 	if err != nil {
 		return err
@@ -116,17 +122,9 @@ func (cP *csvProvider) FieldAsInterface(fldPath []string) (data interface{}, err
 	err = nil // cancel previous err
 
 	if strings.HasPrefix(fldPath[0], utils.MetaFile+utils.FilterValStart) {
-		fileName := strings.TrimPrefix(fldPath[0], utils.MetaFile+utils.FilterValStart)
-		hasSelEnd := false
-		for _, val := range fldPath[1:] {
-			if hasSelEnd = strings.HasSuffix(val, utils.FilterValEnd); hasSelEnd {
-				fileName = fileName + utils.NestingSep + val[:len(val)-1]
-				break
-			}
-			fileName = fileName + utils.NestingSep + val
-		}
-		if !hasSelEnd {
-			return nil, fmt.Errorf("filter rule <%s> needs to end in )", fldPath)
+		var fileName string
+		if fileName, _, err = fileSelector(fldPath); err != nil {
+			return
 		}
 		if cP.fileName != fileName {
 			cP.cache.Set(fldPath, nil)
@@ -159,3 +157,80 @@ func (cP *csvProvider) FieldAsString(fldPath []string) (data string, err error)
 func (cP *csvProvider) RemoteHost() net.Addr {
 	return utils.LocalAddr()
 }
+
+// fileSelector returns the file name out of the *file(...) prefix of the path
+// and the index of the path element where the selector ends
+func fileSelector(fldPath []string) (fileName string, selEndIdx int, err error) {
+	fileName = strings.TrimPrefix(fldPath[0], utils.MetaFile+utils.FilterValStart)
+	for i, val := range fldPath[1:] {
+		if strings.HasSuffix(val, utils.FilterValEnd) {
+			return fileName + utils.NestingSep + val[:len(val)-1], i + 1, nil
+		}
+		fileName = fileName + utils.NestingSep + val
+	}
+	return utils.EmptyString, 0, fmt.Errorf("filter rule <%s> needs to end in )", fldPath)
+}
+
+// newJSONProvider constructs a DataProvider for one object of the JSON file
+func newJSONProvider(req map[string]interface{}, fileName string) (dP utils.DataProvider) {
+	dP = &jsonProvider{req: req, fileName: fileName, cache: utils.MapStorage{}}
+	return
+}
+
+// jsonProvider implements utils.DataProvider so we can pass it to filters
+type jsonProvider struct {
+	req      utils.MapStorage
+	fileName string
+	cache    utils.MapStorage
+}
+
+// String is part of utils.DataProvider interface
+// when called, it will display the already parsed values out of cache
+func (jP *jsonProvider) String() string {
+	return utils.ToJSON(jP)
+}
+
+// FieldAsInterface is part of utils.DataProvider interface
+func (jP *jsonProvider) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if data, err = jP.cache.FieldAsInterface(fldPath); err == nil ||
+		err != utils.ErrNotFound { // item found in cache
+		return
+	}
+	err = nil // cancel previous err
+	selEndIdx := 0
+	if strings.HasPrefix(fldPath[0], utils.MetaFile+utils.FilterValStart) {
+		var fileName string
+		if fileName, selEndIdx, err = fileSelector(fldPath); err != nil {
+			return
+		}
+		if jP.fileName != fileName {
+			jP.cache.Set(fldPath, nil)
+			return
+		}
+	} else if fldPath[0] != utils.MetaReq {
+		return nil, fmt.Errorf("invalid prefix for : %s", fldPath)
+	}
+	if data, err = jP.req.FieldAsInterface(fldPath[selEndIdx+1:]); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		data, err = nil, nil // missing fields are considered empty
+	}
+	jP.cache.Set(fldPath, data)
+	return
+}
+
+// FieldAsString is part of utils.DataProvider interface
+func (jP *jsonProvider) FieldAsString(fldPath []string) (data string, err error) {
+	var valIface interface{}
+	valIface, err = jP.FieldAsInterface(fldPath)
+	if err != nil {
+		return
+	}
+	return utils.IfaceAsString(valIface), nil
+}
+
+// RemoteHost is part of utils.DataProvider interface
+func (jP *jsonProvider) RemoteHost() net.Addr {
+	return utils.LocalAddr()
+}
@@ -394,3 +394,44 @@ func TestLoadersFieldAsInterfaceError(t *testing.T) {
 		t.Errorf("Expected %+v, received %+q", expected, err)
 	}
 }
+
+func TestLoadersJSONProvider(t *testing.T) {
+	jsonProv := newJSONProvider(map[string]interface{}{
+		"Tenant": "cgrates.org",
+		"Weight": 10.5,
+		"Nested": map[string]interface{}{"Field": "val"},
+	}, "Attributes.json")
+	if rcv, err := jsonProv.FieldAsString([]string{utils.MetaReq, "Tenant"}); err != nil {
+		t.Error(err)
+	} else if rcv != "cgrates.org" {
+		t.Errorf("Expected %+v, received %+v", "cgrates.org", rcv)
+	}
+	if rcv, err := jsonProv.FieldAsString([]string{utils.MetaReq, "Weight"}); err != nil {
+		t.Error(err)
+	} else if rcv != "10.5" {
+		t.Errorf("Expected %+v, received %+v", "10.5", rcv)
+	}
+	if rcv, err := jsonProv.FieldAsString([]string{"*file(Attributes", "json)", "Nested", "Field"}); err != nil {
+		t.Error(err)
+	} else if rcv != "val" {
+		t.Errorf("Expected %+v, received %+v", "val", rcv)
+	}
+	if rcv, err := jsonProv.FieldAsInterface([]string{"*file(Other", "json)", "Tenant"}); err != nil {
+		t.Error(err)
+	} else if rcv != nil {
+		t.Errorf("Expected nil, received %+v", rcv)
+	}
+	if rcv, err := jsonProv.FieldAsString([]string{utils.MetaReq, "Missing"}); err != nil {
+		t.Error(err)
+	} else if rcv != utils.EmptyString {
+		t.Errorf("Expected empty, received %+v", rcv)
+	}
+	expected := "invalid prefix for : [*opts Tenant]"
+	if _, err := jsonProv.FieldAsInterface([]string{utils.MetaOpts, "Tenant"}); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	expected = "filter rule <[*file(Attributes json]> needs to end in )"
+	if _, err := jsonProv.FieldAsInterface([]string{"*file(Attributes", "json"}); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+}
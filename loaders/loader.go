@@ -20,6 +20,7 @@ package loaders
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -33,10 +34,69 @@ import (
 	"github.com/cgrates/cgrates/utils"
 )
 
+// recordReader reads the records one by one, as the csv.Reader does
+type recordReader interface {
+	Read() (record []string, err error)
+}
+
 type openedCSVFile struct {
 	fileName string
 	rdr      io.ReadCloser // keep reference so we can close it when done
-	csvRdr   *csv.Reader
+	csvRdr   recordReader  // the csv file or the xlsx sheet
+	jsonRdr  *jsonReader   // populated instead of csvRdr for the .json files
+}
+
+// read returns the DataProvider for the next record in the file
+func (oF *openedCSVFile) read() (dP utils.DataProvider, err error) {
+	if oF.jsonRdr != nil {
+		var record map[string]interface{}
+		if record, err = oF.jsonRdr.Read(); err != nil {
+			return
+		}
+		return newJSONProvider(record, oF.fileName), nil
+	}
+	var record []string
+	if record, err = oF.csvRdr.Read(); err != nil {
+		return
+	}
+	return newCsvProvider(record, oF.fileName), nil
+}
+
+// newJSONReader returns a jsonReader for the array of objects in rdr
+func newJSONReader(rdr io.Reader) *jsonReader {
+	dec := json.NewDecoder(rdr)
+	dec.UseNumber()
+	return &jsonReader{dec: dec}
+}
+
+// jsonReader reads one by one the objects from a JSON array
+type jsonReader struct {
+	dec     *json.Decoder
+	started bool // the array start was consumed
+	invalid bool // the content is not an array so there is nothing else to read
+}
+
+// Read returns the next object in the array
+func (jR *jsonReader) Read() (record map[string]interface{}, err error) {
+	if jR.invalid {
+		return nil, io.EOF
+	}
+	if !jR.started {
+		var tkn json.Token
+		if tkn, err = jR.dec.Token(); err != nil {
+			return
+		}
+		if dlm, canCast := tkn.(json.Delim); !canCast || dlm != '[' {
+			jR.invalid = true
+			return nil, fmt.Errorf("expecting an array of objects, received: <%v>", tkn)
+		}
+		jR.started = true
+	}
+	if !jR.dec.More() {
+		return nil, io.EOF
+	}
+	err = jR.dec.Decode(&record)
+	return
 }
 
 func NewLoader(dm *engine.DataManager, cfg *config.LoaderSCfg,
@@ -46,6 +106,7 @@ func NewLoader(dm *engine.DataManager, cfg *config.LoaderSCfg,
 		enabled:       cfg.Enabled,
 		tenant:        cfg.Tenant,
 		dryRun:        cfg.DryRun,
+		transactional: cfg.Transactional,
 		ldrID:         cfg.ID,
 		tpInDir:       cfg.TpInDir,
 		tpOutDir:      cfg.TpOutDir,
@@ -89,6 +150,8 @@ type Loader struct {
 	enabled       bool
 	tenant        config.RSRParsers
 	dryRun        bool
+	transactional bool
+	txn           *loaderTransaction // populated while a transactional load is in progress
 	ldrID         string
 	tpInDir       string
 	tpOutDir      string
@@ -118,15 +181,20 @@ func (ldr *Loader) ProcessFolder(caching, loadOption string, stopOnError bool) (
 		return
 	}
 	defer ldr.unlockFolder()
-	for ldrType := range ldr.rdrs {
-		if err = ldr.processFiles(ldrType, caching, loadOption); err != nil {
-			if stopOnError {
-				return
+	if err = ldr.inTransaction(caching, func() (err error) {
+		for ldrType := range ldr.rdrs {
+			if err = ldr.processFiles(ldrType, caching, loadOption); err != nil {
+				if stopOnError || ldr.txn != nil { // in transactional mode any error aborts the load
+					return
+				}
+				utils.Logger.Warning(fmt.Sprintf("<%s-%s> loaderType: <%s> cannot open files, err: %s",
+					utils.LoaderS, ldr.ldrID, ldrType, err.Error()))
+				continue
 			}
-			utils.Logger.Warning(fmt.Sprintf("<%s-%s> loaderType: <%s> cannot open files, err: %s",
-				utils.LoaderS, ldr.ldrID, ldrType, err.Error()))
-			continue
 		}
+		return nil
+	}); err != nil {
+		return
 	}
 	return ldr.moveFiles()
 }
@@ -180,17 +248,69 @@ func (ldr *Loader) moveFiles() (err error) {
 	return
 }
 
+// openFile opens the file from tpInDir based on its extension
+// if the file is missing, the sheet named as the file(without extension) is searched in the xlsx files
+func (ldr *Loader) openFile(fName string) (oF *openedCSVFile, err error) {
+	var rdr *os.File
+	if rdr, err = os.Open(path.Join(ldr.tpInDir, fName)); err != nil {
+		if !os.IsNotExist(err) {
+			return
+		}
+		rows, has, errSheet := ldr.sheetRows(fName)
+		if errSheet != nil {
+			return nil, errSheet
+		}
+		if !has {
+			return // the original error
+		}
+		return newSheetFile(fName, rows), nil
+	}
+	if path.Ext(fName) == utils.JSNSuffix {
+		return &openedCSVFile{fileName: fName, rdr: rdr,
+			jsonRdr: newJSONReader(rdr)}, nil
+	}
+	csvReader := csv.NewReader(rdr)
+	csvReader.Comma = rune(ldr.fieldSep[0])
+	csvReader.Comment = '#'
+	return &openedCSVFile{fileName: fName, rdr: rdr, csvRdr: csvReader}, nil
+}
+
+// newSheetFile returns the opened file reading the rows of a xlsx sheet
+func newSheetFile(fName string, rows [][]string) *openedCSVFile {
+	return &openedCSVFile{fileName: fName, rdr: ioutil.NopCloser(nil),
+		csvRdr: &sheetReader{rows: rows}}
+}
+
+// sheetName returns the name of the xlsx sheet holding the file content
+func sheetName(fName string) string {
+	return strings.TrimSuffix(fName, path.Ext(fName))
+}
+
+// sheetRows returns the rows of the sheet named as the file(without extension)
+// looking in the xlsx files from tpInDir
+func (ldr *Loader) sheetRows(fName string) (rows [][]string, has bool, err error) {
+	name := sheetName(fName)
+	filesInDir, _ := ioutil.ReadDir(ldr.tpInDir)
+	for _, file := range filesInDir {
+		if file.IsDir() || path.Ext(file.Name()) != utils.XLSXSuffix {
+			continue
+		}
+		var sheets map[string][][]string
+		if sheets, err = readXLSXSheets(path.Join(ldr.tpInDir, file.Name())); err != nil {
+			return
+		}
+		if rows, has = sheets[name]; has {
+			return
+		}
+	}
+	return
+}
+
 func (ldr *Loader) processFiles(loaderType, caching, loadOption string) (err error) {
 	for fName := range ldr.rdrs[loaderType] {
-		var rdr *os.File
-		if rdr, err = os.Open(path.Join(ldr.tpInDir, fName)); err != nil {
+		if ldr.rdrs[loaderType][fName], err = ldr.openFile(fName); err != nil {
 			return err
 		}
-		csvReader := csv.NewReader(rdr)
-		csvReader.Comma = rune(ldr.fieldSep[0])
-		csvReader.Comment = '#'
-		ldr.rdrs[loaderType][fName] = &openedCSVFile{
-			fileName: fName, rdr: rdr, csvRdr: csvReader}
 		defer ldr.unreferenceFile(loaderType, fName)
 	}
 	// based on load option will store or remove the content
@@ -216,9 +336,9 @@ func (ldr *Loader) processContent(loaderType, caching string) (err error) {
 		lineNr++
 		var hasErrors bool
 		lData := make(LoaderData) // one row
-		for fName, rdr := range ldr.rdrs[loaderType] {
-			var record []string
-			if record, err = rdr.csvRdr.Read(); err != nil {
+		for _, rdr := range ldr.rdrs[loaderType] {
+			var record utils.DataProvider
+			if record, err = rdr.read(); err != nil {
 				if err == io.EOF {
 					keepLooping = false
 					break
@@ -232,7 +352,7 @@ func (ldr *Loader) processContent(loaderType, caching string) (err error) {
 				continue
 			}
 
-			if err := lData.UpdateFromCSV(fName, record,
+			if err := lData.UpdateFromDP(record,
 				ldr.dataTpls[loaderType], ldr.tenant, ldr.filterS); err != nil {
 				utils.Logger.Warning(
 					fmt.Sprintf("<%s> <%s> line: %d, error: %s",
@@ -243,6 +363,9 @@ func (ldr *Loader) processContent(loaderType, caching string) (err error) {
 			// Record from map
 			// update dataDB
 		}
+		if hasErrors && ldr.txn != nil { // do not commit a partial load
+			return fmt.Errorf("cannot process line: %d", lineNr)
+		}
 		if len(lData) == 0 { // no data, could be the last line in file
 			continue
 		}
@@ -303,7 +426,9 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, apf.TenantID())
-				if err := ldr.dm.SetAttributeProfile(apf, true); err != nil {
+				if err := ldr.store(apf, func() error {
+					return ldr.dm.SetAttributeProfile(apf, true)
+				}); err != nil {
 					return err
 				}
 			}
@@ -333,20 +458,22 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, res.TenantID())
-				if err := ldr.dm.SetResourceProfile(res, true); err != nil {
-					return err
-				}
-				var ttl *time.Duration
-				if res.UsageTTL > 0 {
-					ttl = &res.UsageTTL
-				}
-				// for non stored we do not save the resource
-				if err := ldr.dm.SetResource(
-					&engine.Resource{
-						Tenant: res.Tenant,
-						ID:     res.ID,
-						Usages: make(map[string]*engine.ResourceUsage),
-					}, ttl, res.Limit, !res.Stored); err != nil {
+				if err := ldr.store(res, func() error {
+					if err := ldr.dm.SetResourceProfile(res, true); err != nil {
+						return err
+					}
+					var ttl *time.Duration
+					if res.UsageTTL > 0 {
+						ttl = &res.UsageTTL
+					}
+					// for non stored we do not save the resource
+					return ldr.dm.SetResource(
+						&engine.Resource{
+							Tenant: res.Tenant,
+							ID:     res.ID,
+							Usages: make(map[string]*engine.ResourceUsage),
+						}, ttl, res.Limit, !res.Stored)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.ResourceProfileIDs] = ids
@@ -376,7 +503,9 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, fltrPrf.TenantID())
-				if err := ldr.dm.SetFilter(fltrPrf, true); err != nil {
+				if err := ldr.store(fltrPrf, func() error {
+					return ldr.dm.SetFilter(fltrPrf, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.FilterIDs] = ids
@@ -405,23 +534,24 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, stsPrf.TenantID())
-				if err := ldr.dm.SetStatQueueProfile(stsPrf, true); err != nil {
-					return err
-				}
-				var sq *engine.StatQueue
-				if sq, err = engine.NewStatQueue(stsPrf.Tenant, stsPrf.ID, stsPrf.Metrics,
-					stsPrf.MinItems); err != nil {
-					return utils.APIErrorHandler(err)
-				}
-				var ttl *time.Duration
-				if stsPrf.TTL > 0 {
-					ttl = &stsPrf.TTL
-				}
-
-				// for non stored we do not save the metrics
-				if err := ldr.dm.SetStatQueue(sq, stsPrf.Metrics,
-					stsPrf.MinItems, ttl, stsPrf.QueueLength,
-					!stsPrf.Stored); err != nil {
+				if err := ldr.store(stsPrf, func() error {
+					if err := ldr.dm.SetStatQueueProfile(stsPrf, true); err != nil {
+						return err
+					}
+					sq, err := engine.NewStatQueue(stsPrf.Tenant, stsPrf.ID, stsPrf.Metrics,
+						stsPrf.MinItems)
+					if err != nil {
+						return utils.APIErrorHandler(err)
+					}
+					var ttl *time.Duration
+					if stsPrf.TTL > 0 {
+						ttl = &stsPrf.TTL
+					}
+					// for non stored we do not save the metrics
+					return ldr.dm.SetStatQueue(sq, stsPrf.Metrics,
+						stsPrf.MinItems, ttl, stsPrf.QueueLength,
+						!stsPrf.Stored)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.StatsQueueProfileIDs] = ids
@@ -451,10 +581,12 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, thPrf.TenantID())
-				if err := ldr.dm.SetThresholdProfile(thPrf, true); err != nil {
-					return err
-				}
-				if err := ldr.dm.SetThreshold(&engine.Threshold{Tenant: thPrf.Tenant, ID: thPrf.ID}, thPrf.MinSleep, false); err != nil {
+				if err := ldr.store(thPrf, func() error {
+					if err := ldr.dm.SetThresholdProfile(thPrf, true); err != nil {
+						return err
+					}
+					return ldr.dm.SetThreshold(&engine.Threshold{Tenant: thPrf.Tenant, ID: thPrf.ID}, thPrf.MinSleep, false)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.ThresholdProfileIDs] = ids
@@ -485,7 +617,9 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, spPrf.TenantID())
-				if err := ldr.dm.SetRouteProfile(spPrf, true); err != nil {
+				if err := ldr.store(spPrf, func() error {
+					return ldr.dm.SetRouteProfile(spPrf, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.RouteProfileIDs] = ids
@@ -515,7 +649,9 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, cpp.TenantID())
-				if err := ldr.dm.SetChargerProfile(cpp, true); err != nil {
+				if err := ldr.store(cpp, func() error {
+					return ldr.dm.SetChargerProfile(cpp, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.ChargerProfileIDs] = ids
@@ -544,7 +680,9 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, dsp.TenantID())
-				if err := ldr.dm.SetDispatcherProfile(dsp, true); err != nil {
+				if err := ldr.store(dsp, func() error {
+					return ldr.dm.SetDispatcherProfile(dsp, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.DispatcherProfileIDs] = ids
@@ -569,12 +707,44 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, dsp.TenantID())
-				if err := ldr.dm.SetDispatcherHost(dsp); err != nil {
+				if err := ldr.store(dsp, func() error {
+					return ldr.dm.SetDispatcherHost(dsp)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.DispatcherHostIDs] = ids
 			}
 		}
+	case utils.MetaPortedNumbers:
+		for _, lDataSet := range lds {
+			pn := new(engine.PortedNumber)
+			for _, ld := range lDataSet { // the last non empty value wins
+				for fld, val := range map[string]*string{
+					utils.Tenant:        &pn.Tenant,
+					utils.ID:            &pn.ID,
+					utils.RoutingNumber: &pn.RoutingNumber,
+					utils.Carrier:       &pn.Carrier,
+				} {
+					if strVal := utils.IfaceAsString(ld[fld]); strVal != utils.EmptyString {
+						*val = strVal
+					}
+				}
+			}
+			if ldr.dryRun {
+				utils.Logger.Info(
+					fmt.Sprintf("<%s-%s> DRY_RUN: PortedNumber: %s",
+						utils.LoaderS, ldr.ldrID, utils.ToJSON(pn)))
+				continue
+			}
+			// get IDs so we can reload in cache
+			ids = append(ids, pn.TenantID())
+			if err := ldr.store(pn, func() error {
+				return ldr.dm.SetPortedNumber(pn)
+			}); err != nil {
+				return err
+			}
+			cacheArgs[utils.PortedNumberIDs] = ids
+		}
 	case utils.MetaRateProfiles:
 		cacheIDs = []string{utils.CacheRateProfilesFilterIndexes, utils.CacheRateFilterIndexes}
 		for _, lDataSet := range lds {
@@ -598,14 +768,13 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, rpl.TenantID())
-				if ldr.flagsTpls[loaderType].GetBool(utils.MetaPartial) {
-					if err := ldr.dm.SetRateProfileRates(rpl, true); err != nil {
-						return err
-					}
-				} else {
-					if err := ldr.dm.SetRateProfile(rpl, true); err != nil {
-						return err
+				if err := ldr.store(rpl, func() error {
+					if ldr.flagsTpls[loaderType].GetBool(utils.MetaPartial) {
+						return ldr.dm.SetRateProfileRates(rpl, true)
 					}
+					return ldr.dm.SetRateProfile(rpl, true)
+				}); err != nil {
+					return err
 				}
 				cacheArgs[utils.RateProfileIDs] = ids
 			}
@@ -634,7 +803,9 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, acp.TenantID())
-				if err := ldr.dm.SetActionProfile(acp, true); err != nil {
+				if err := ldr.store(acp, func() error {
+					return ldr.dm.SetActionProfile(acp, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.ActionProfileIDs] = ids
@@ -667,52 +838,20 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 				// get IDs so we can reload in cache
 				ids = append(ids, acp.TenantID())
-				if err := ldr.dm.SetAccountProfile(acp, true); err != nil {
+				if err := ldr.store(acp, func() error {
+					return ldr.dm.SetAccountProfile(acp, true)
+				}); err != nil {
 					return err
 				}
 			}
 		}
 	}
 
-	if len(ldr.cacheConns) != 0 {
-		var reply string
-		switch caching {
-		case utils.MetaNone:
-			return
-		case utils.MetaReload:
-			if err = ldr.connMgr.Call(ldr.cacheConns, nil,
-				utils.CacheSv1ReloadCache, utils.AttrReloadCacheWithOpts{
-					ArgsCache: cacheArgs}, &reply); err != nil {
-				return
-			}
-		case utils.MetaLoad:
-			if err = ldr.connMgr.Call(ldr.cacheConns, nil,
-				utils.CacheSv1LoadCache, utils.AttrReloadCacheWithOpts{
-					ArgsCache: cacheArgs}, &reply); err != nil {
-				return
-			}
-		case utils.MetaRemove:
-			if err = ldr.connMgr.Call(ldr.cacheConns, nil,
-				utils.CacheSv1RemoveItems, utils.AttrReloadCacheWithOpts{
-					ArgsCache: cacheArgs}, &reply); err != nil {
-				return
-			}
-		case utils.MetaClear:
-			if err = ldr.connMgr.Call(ldr.cacheConns, nil,
-				utils.CacheSv1Clear, new(utils.AttrCacheIDsWithOpts), &reply); err != nil {
-				return
-			}
-		}
-		if len(cacheIDs) != 0 {
-			if err = ldr.connMgr.Call(ldr.cacheConns, nil, utils.CacheSv1Clear, &utils.AttrCacheIDsWithOpts{
-				CacheIDs: cacheIDs,
-			}, &reply); err != nil {
-				return
-			}
-		}
-
+	if ldr.txn != nil { // the cache is updated after commit
+		ldr.txn.addCacheArgs(cacheArgs, cacheIDs)
+		return
 	}
-	return
+	return ldr.updateCache(caching, cacheArgs, cacheIDs)
 }
 
 //removeContent will process the content and will remove it from database
@@ -724,9 +863,9 @@ func (ldr *Loader) removeContent(loaderType, caching string) (err error) {
 		lineNr++
 		var hasErrors bool
 		lData := make(LoaderData) // one row
-		for fName, rdr := range ldr.rdrs[loaderType] {
-			var record []string
-			if record, err = rdr.csvRdr.Read(); err != nil {
+		for _, rdr := range ldr.rdrs[loaderType] {
+			var record utils.DataProvider
+			if record, err = rdr.read(); err != nil {
 				if err == io.EOF {
 					keepLooping = false
 					break
@@ -740,7 +879,7 @@ func (ldr *Loader) removeContent(loaderType, caching string) (err error) {
 				continue
 			}
 
-			if err := lData.UpdateFromCSV(fName, record,
+			if err := lData.UpdateFromDP(record,
 				ldr.dataTpls[loaderType], ldr.tenant, ldr.filterS); err != nil {
 				utils.Logger.Warning(
 					fmt.Sprintf("<%s> <%s> line: %d, error: %s",
@@ -800,8 +939,10 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveAttributeProfile(tntIDStruct.Tenant, tntIDStruct.ID,
-					utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.AttributeProfilePrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveAttributeProfile(tntIDStruct.Tenant, tntIDStruct.ID,
+						utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.AttributeProfileIDs] = ids
@@ -820,11 +961,15 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveResourceProfile(tntIDStruct.Tenant,
-					tntIDStruct.ID, utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.ResourceProfilesPrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveResourceProfile(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
-				if err := ldr.dm.RemoveResource(tntIDStruct.Tenant, tntIDStruct.ID, utils.NonTransactional); err != nil {
+				if err := ldr.remove(utils.ResourcesPrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveResource(tntIDStruct.Tenant, tntIDStruct.ID, utils.NonTransactional)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.ResourceProfileIDs] = ids
@@ -841,8 +986,10 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveFilter(tntIDStruct.Tenant, tntIDStruct.ID,
-					utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.FilterPrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveFilter(tntIDStruct.Tenant, tntIDStruct.ID,
+						utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.FilterIDs] = ids
@@ -859,11 +1006,15 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveStatQueueProfile(tntIDStruct.Tenant,
-					tntIDStruct.ID, utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.StatQueueProfilePrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveStatQueueProfile(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
-				if err := ldr.dm.RemoveStatQueue(tntIDStruct.Tenant, tntIDStruct.ID, utils.NonTransactional); err != nil {
+				if err := ldr.remove(utils.StatQueuePrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveStatQueue(tntIDStruct.Tenant, tntIDStruct.ID, utils.NonTransactional)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.StatsQueueProfileIDs] = ids
@@ -881,11 +1032,15 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveThresholdProfile(tntIDStruct.Tenant,
-					tntIDStruct.ID, utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.ThresholdProfilePrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveThresholdProfile(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
-				if err := ldr.dm.RemoveThreshold(tntIDStruct.Tenant, tntIDStruct.ID, utils.NonTransactional); err != nil {
+				if err := ldr.remove(utils.ThresholdPrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveThreshold(tntIDStruct.Tenant, tntIDStruct.ID, utils.NonTransactional)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.ThresholdProfileIDs] = ids
@@ -903,8 +1058,10 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveRouteProfile(tntIDStruct.Tenant,
-					tntIDStruct.ID, utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.RouteProfilePrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveRouteProfile(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.RouteProfileIDs] = ids
@@ -921,8 +1078,10 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveChargerProfile(tntIDStruct.Tenant,
-					tntIDStruct.ID, utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.ChargerProfilePrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveChargerProfile(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.ChargerProfileIDs] = ids
@@ -939,8 +1098,10 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveDispatcherProfile(tntIDStruct.Tenant,
-					tntIDStruct.ID, utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.DispatcherProfilePrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveDispatcherProfile(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.DispatcherProfileIDs] = ids
@@ -956,13 +1117,34 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveDispatcherHost(tntIDStruct.Tenant,
-					tntIDStruct.ID, utils.NonTransactional); err != nil {
+				if err := ldr.remove(utils.DispatcherHostPrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveDispatcherHost(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.DispatcherHostIDs] = ids
 			}
 		}
+	case utils.MetaPortedNumbers:
+		for tntID := range lds {
+			if ldr.dryRun {
+				utils.Logger.Info(
+					fmt.Sprintf("<%s-%s> DRY_RUN: PortedNumberID: %s",
+						utils.LoaderS, ldr.ldrID, tntID))
+			} else {
+				tntIDStruct := utils.NewTenantID(tntID)
+				// get IDs so we can reload in cache
+				ids = append(ids, tntID)
+				if err := ldr.remove(utils.PortedNumberPrefix, tntIDStruct, func() error {
+					return ldr.dm.RemovePortedNumber(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional)
+				}); err != nil {
+					return err
+				}
+				cacheArgs[utils.PortedNumberIDs] = ids
+			}
+		}
 	case utils.MetaRateProfiles:
 		cacheIDs = []string{utils.CacheRateProfilesFilterIndexes, utils.CacheRateFilterIndexes}
 		for tntID, ldData := range lds {
@@ -979,13 +1161,17 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 					if err != nil {
 						return err
 					}
-					if err := ldr.dm.RemoveRateProfileRates(tntIDStruct.Tenant,
-						tntIDStruct.ID, rateIDs, true); err != nil {
+					if err := ldr.remove(utils.RateProfilePrefix, tntIDStruct, func() error {
+						return ldr.dm.RemoveRateProfileRates(tntIDStruct.Tenant,
+							tntIDStruct.ID, rateIDs, true)
+					}); err != nil {
 						return err
 					}
 				} else {
-					if err := ldr.dm.RemoveRateProfile(tntIDStruct.Tenant,
-						tntIDStruct.ID, utils.NonTransactional, true); err != nil {
+					if err := ldr.remove(utils.RateProfilePrefix, tntIDStruct, func() error {
+						return ldr.dm.RemoveRateProfile(tntIDStruct.Tenant,
+							tntIDStruct.ID, utils.NonTransactional, true)
+					}); err != nil {
 						return err
 					}
 				}
@@ -1004,8 +1190,10 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveActionProfile(tntIDStruct.Tenant,
-					tntIDStruct.ID, utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.ActionProfilePrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveActionProfile(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
 				cacheArgs[utils.ActionProfileIDs] = ids
@@ -1022,14 +1210,24 @@ func (ldr *Loader) removeLoadedData(loaderType string, lds map[string][]LoaderDa
 				tntIDStruct := utils.NewTenantID(tntID)
 				// get IDs so we can reload in cache
 				ids = append(ids, tntID)
-				if err := ldr.dm.RemoveAccountProfile(tntIDStruct.Tenant,
-					tntIDStruct.ID, utils.NonTransactional, true); err != nil {
+				if err := ldr.remove(utils.AccountProfilePrefix, tntIDStruct, func() error {
+					return ldr.dm.RemoveAccountProfile(tntIDStruct.Tenant,
+						tntIDStruct.ID, utils.NonTransactional, true)
+				}); err != nil {
 					return err
 				}
 			}
 		}
 	}
+	if ldr.txn != nil { // the cache is updated after commit
+		ldr.txn.addCacheArgs(cacheArgs, cacheIDs)
+		return
+	}
+	return ldr.updateCache(caching, cacheArgs, cacheIDs)
+}
 
+// updateCache reloads the loaded items in cache based on the caching option
+func (ldr *Loader) updateCache(caching string, cacheArgs map[string][]string, cacheIDs []string) (err error) {
 	if len(ldr.cacheConns) != 0 {
 		var reply string
 		switch caching {
@@ -1101,6 +1299,9 @@ func (ldr *Loader) handleFolder(stopChan chan struct{}) {
 }
 
 func (ldr *Loader) processFile(_, itmID string) (err error) {
+	if path.Ext(itmID) == utils.XLSXSuffix {
+		return ldr.processWorkbook(itmID)
+	}
 	loaderType := ldr.getLdrType(itmID)
 	if len(loaderType) == 0 {
 		return
@@ -1112,15 +1313,9 @@ func (ldr *Loader) processFile(_, itmID string) (err error) {
 	if ldr.rdrs[loaderType][itmID] != nil {
 		ldr.unreferenceFile(loaderType, itmID)
 	}
-	var rdr *os.File
-	if rdr, err = os.Open(path.Join(ldr.tpInDir, itmID)); err != nil {
+	if ldr.rdrs[loaderType][itmID], err = ldr.openFile(itmID); err != nil {
 		return
 	}
-	csvReader := csv.NewReader(rdr)
-	csvReader.Comma = rune(ldr.fieldSep[0])
-	csvReader.Comment = '#'
-	ldr.rdrs[loaderType][itmID] = &openedCSVFile{
-		fileName: itmID, rdr: rdr, csvRdr: csvReader}
 	if !ldr.allFilesPresent(loaderType) {
 		return
 	}
@@ -1128,7 +1323,10 @@ func (ldr *Loader) processFile(_, itmID string) (err error) {
 		defer ldr.unreferenceFile(loaderType, fName)
 	}
 
-	err = ldr.processContent(loaderType, config.CgrConfig().GeneralCfg().DefaultCaching)
+	caching := config.CgrConfig().GeneralCfg().DefaultCaching
+	err = ldr.inTransaction(caching, func() error {
+		return ldr.processContent(loaderType, caching)
+	})
 
 	if ldr.tpOutDir == utils.EmptyString {
 		return
@@ -1143,6 +1341,51 @@ func (ldr *Loader) processFile(_, itmID string) (err error) {
 	return
 }
 
+// processWorkbook loads the loader types having all the files as sheets in the xlsx file
+func (ldr *Loader) processWorkbook(fName string) (err error) {
+	if err = ldr.lockFolder(); err != nil {
+		return
+	}
+	defer ldr.unlockFolder()
+	var sheets map[string][][]string
+	if sheets, err = readXLSXSheets(path.Join(ldr.tpInDir, fName)); err != nil {
+		return
+	}
+	caching := config.CgrConfig().GeneralCfg().DefaultCaching
+	if err = ldr.inTransaction(caching, func() (err error) {
+		for ldrType, rdrs := range ldr.rdrs {
+			hasSheets := len(rdrs) != 0
+			for rdrName := range rdrs {
+				if _, hasSheets = sheets[sheetName(rdrName)]; !hasSheets {
+					break
+				}
+			}
+			if !hasSheets {
+				continue
+			}
+			if err = ldr.processSheets(ldrType, caching, sheets); err != nil {
+				return
+			}
+		}
+		return
+	}); err != nil {
+		return
+	}
+	if ldr.tpOutDir == utils.EmptyString {
+		return
+	}
+	return os.Rename(path.Join(ldr.tpInDir, fName), path.Join(ldr.tpOutDir, fName))
+}
+
+// processSheets stores the content of the loader type reading its files only from the workbook sheets
+func (ldr *Loader) processSheets(loaderType, caching string, sheets map[string][][]string) (err error) {
+	for fName := range ldr.rdrs[loaderType] {
+		ldr.rdrs[loaderType][fName] = newSheetFile(fName, sheets[sheetName(fName)])
+		defer ldr.unreferenceFile(loaderType, fName)
+	}
+	return ldr.processContent(loaderType, caching)
+}
+
 func (ldr *Loader) allFilesPresent(ldrType string) bool {
 	for _, rdr := range ldr.rdrs[ldrType] {
 		if rdr == nil {
@@ -19,10 +19,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package loaders
 
 import (
+	"archive/zip"
 	"encoding/csv"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"path"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -1412,6 +1418,89 @@ func TestLoaderProcessDispatcheHosts(t *testing.T) {
 	}
 }
 
+func TestLoaderProcessPortedNumbers(t *testing.T) {
+	data := engine.NewInternalDB(nil, nil, true)
+	ldr := &Loader{
+		ldrID:         "TestLoaderProcessPortedNumbers",
+		bufLoaderData: make(map[string][]LoaderData),
+		dm:            engine.NewDataManager(data, config.CgrConfig().CacheCfg(), nil),
+		timezone:      "UTC",
+	}
+	ldr.dataTpls = map[string][]*config.FCTemplate{
+		utils.MetaPortedNumbers: {
+			{
+				Tag:       "Tenant",
+				Path:      "Tenant",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.0", utils.InfieldSep),
+				Mandatory: true,
+			},
+			{
+				Tag:       "ID",
+				Path:      "ID",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.1", utils.InfieldSep),
+				Mandatory: true,
+			},
+			{
+				Tag:   "RoutingNumber",
+				Path:  "RoutingNumber",
+				Type:  utils.MetaComposed,
+				Value: config.NewRSRParsersMustCompile("~*req.2", utils.InfieldSep),
+			},
+			{
+				Tag:   "Carrier",
+				Path:  "Carrier",
+				Type:  utils.MetaComposed,
+				Value: config.NewRSRParsersMustCompile("~*req.3", utils.InfieldSep),
+			},
+		},
+	}
+	pnCSVContent := `
+#Tenant,ID,RoutingNumber,Carrier
+cgrates.org,+4917612345678,D262+4917612345678,CARRIER2
+cgrates.org,+4917687654321,,CARRIER3
+`
+	newRdrs := func() map[string]map[string]*openedCSVFile {
+		rdr := ioutil.NopCloser(strings.NewReader(pnCSVContent))
+		csvRdr := csv.NewReader(rdr)
+		csvRdr.Comment = '#'
+		return map[string]map[string]*openedCSVFile{
+			utils.MetaPortedNumbers: {
+				utils.PortedNumbersCsv: &openedCSVFile{
+					fileName: utils.PortedNumbersCsv,
+					rdr:      rdr,
+					csvRdr:   csvRdr,
+				},
+			},
+		}
+	}
+	ldr.rdrs = newRdrs()
+	if err := ldr.processContent(utils.MetaPortedNumbers, utils.EmptyString); err != nil {
+		t.Error(err)
+	}
+	for _, ePn := range []*engine.PortedNumber{
+		{Tenant: "cgrates.org", ID: "+4917612345678", RoutingNumber: "D262+4917612345678", Carrier: "CARRIER2"},
+		{Tenant: "cgrates.org", ID: "+4917687654321", Carrier: "CARRIER3"},
+	} {
+		if rcv, err := ldr.dm.GetPortedNumber(ePn.Tenant, ePn.ID,
+			false, false, utils.NonTransactional); err != nil {
+			t.Error(err)
+		} else if !reflect.DeepEqual(ePn, rcv) {
+			t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(ePn), utils.ToJSON(rcv))
+		}
+	}
+
+	ldr.rdrs = newRdrs()
+	if err := ldr.removeContent(utils.MetaPortedNumbers, utils.EmptyString); err != nil {
+		t.Error(err)
+	}
+	if _, err := ldr.dm.GetPortedNumber("cgrates.org", "+4917612345678",
+		false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("expecting: %+v, received: %+v", utils.ErrNotFound, err)
+	}
+}
+
 func TestLoaderRemoveContentSingleFile(t *testing.T) {
 	data := engine.NewInternalDB(nil, nil, true)
 	ldr := &Loader{
@@ -2321,6 +2410,7 @@ func TestNewLoaderWithMultiFiles(t *testing.T) {
 		utils.ThresholdsCsv:         {},
 		utils.ActionProfilesCsv:     {},
 		utils.AccountProfilesCsv:    {},
+		utils.PortedNumbersCsv:      {},
 	}
 	if !reflect.DeepEqual(expected, openRdrs) {
 		t.Errorf("Expected %s,received %s", utils.ToJSON(expected), utils.ToJSON(openRdrs))
@@ -5045,3 +5135,405 @@ func TestLoaderServiceEnabled(t *testing.T) {
 		t.Errorf("Expected false, received %+v", rcv)
 	}
 }
+
+func TestLoaderProcessJSONFile(t *testing.T) {
+	data := engine.NewInternalDB(nil, nil, true)
+	tpInDir := t.TempDir()
+	ldr := &Loader{
+		ldrID:         "TestLoaderProcessJSONFile",
+		bufLoaderData: make(map[string][]LoaderData),
+		dm:            engine.NewDataManager(data, config.CgrConfig().CacheCfg(), nil),
+		tpInDir:       tpInDir,
+		fieldSep:      utils.FieldsSep,
+		timezone:      "UTC",
+	}
+	ldr.dataTpls = map[string][]*config.FCTemplate{
+		utils.MetaResources: {
+			{Tag: "Tenant",
+				Path:      "Tenant",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.Tenant", utils.InfieldSep),
+				Mandatory: true},
+			{Tag: "ID",
+				Path:      "ID",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.ID", utils.InfieldSep),
+				Mandatory: true},
+			{Tag: "Limit",
+				Path:  "Limit",
+				Type:  utils.MetaComposed,
+				Value: config.NewRSRParsersMustCompile("~*file(Resources.json).Limit", utils.InfieldSep)},
+			{Tag: "Blocker",
+				Path:  "Blocker",
+				Type:  utils.MetaComposed,
+				Value: config.NewRSRParsersMustCompile("~*req.Blocker", utils.InfieldSep)},
+		},
+	}
+	ldr.rdrs = map[string]map[string]*openedCSVFile{
+		utils.MetaResources: {"Resources.json": nil},
+	}
+	if err := ioutil.WriteFile(path.Join(tpInDir, "Resources.json"), []byte(`[
+	{"Tenant": "cgrates.org", "ID": "ResJSON1", "Limit": 2, "Blocker": true},
+	{"Tenant": "cgrates.org", "ID": "ResJSON2"}
+]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ldr.processFiles(utils.MetaResources, utils.EmptyString, utils.MetaStore); err != nil {
+		t.Fatal(err)
+	}
+	eResPrf := &engine.ResourceProfile{
+		Tenant:       "cgrates.org",
+		ID:           "ResJSON1",
+		FilterIDs:    []string{},
+		ThresholdIDs: []string{},
+		Limit:        2,
+		Blocker:      true,
+	}
+	if rcv, err := ldr.dm.GetResourceProfile("cgrates.org", "ResJSON1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eResPrf, rcv) {
+		t.Errorf("Expected %+v, received %+v", utils.ToJSON(eResPrf), utils.ToJSON(rcv))
+	}
+	if _, err := ldr.dm.GetResourceProfile("cgrates.org", "ResJSON2", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+	if ldr.rdrs[utils.MetaResources]["Resources.json"] != nil {
+		t.Error("Expected the file to be closed")
+	}
+	if err := ldr.processFiles(utils.MetaResources, utils.EmptyString, utils.MetaRemove); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ldr.dm.GetResourceProfile("cgrates.org", "ResJSON1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+
+	if err := ioutil.WriteFile(path.Join(tpInDir, "Resources.json"), []byte(`{"ID": "ResJSON1"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the reading errors are only logged
+	if err := ldr.processFiles(utils.MetaResources, utils.EmptyString, utils.MetaStore); err != nil {
+		t.Error(err)
+	}
+	if _, err := ldr.dm.GetResourceProfile("cgrates.org", "ResJSON1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	jRdr := newJSONReader(strings.NewReader(`{"ID": "ResJSON1"}`))
+	expected := "expecting an array of objects, received: <{>"
+	if _, err := jRdr.Read(); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	if _, err := jRdr.Read(); err != io.EOF {
+		t.Errorf("Expected %+v, received %+v", io.EOF, err)
+	}
+}
+
+// writeTestXLSX creates a minimal xlsx file with the given sheets
+func writeTestXLSX(fPath string, sheetNames []string, sheets [][][]string) (err error) {
+	var f *os.File
+	if f, err = os.Create(fPath); err != nil {
+		return
+	}
+	defer f.Close()
+	zWrt := zip.NewWriter(f)
+	var wb, rels strings.Builder
+	wb.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	var sst []string
+	for i, name := range sheetNames {
+		wb.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, name, i+1, i+1))
+		rels.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Target="worksheets/sheet%d.xml"/>`, i+1, i+1))
+		var ws strings.Builder
+		ws.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+		for j, row := range sheets[i] {
+			ws.WriteString(fmt.Sprintf(`<row r="%d">`, j+1))
+			for k, val := range row {
+				if val == utils.EmptyString {
+					continue
+				}
+				ref := fmt.Sprintf("%c%d", 'A'+k, j+1)
+				if _, err := strconv.ParseFloat(val, 64); err == nil {
+					ws.WriteString(fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, ref, val))
+					continue
+				}
+				ws.WriteString(fmt.Sprintf(`<c r="%s" t="s"><v>%d</v></c>`, ref, len(sst)))
+				sst = append(sst, val)
+			}
+			ws.WriteString(`</row>`)
+		}
+		ws.WriteString(`</sheetData></worksheet>`)
+		var w io.Writer
+		if w, err = zWrt.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)); err != nil {
+			return
+		}
+		if _, err = w.Write([]byte(ws.String())); err != nil {
+			return
+		}
+	}
+	wb.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`</Relationships>`)
+	var sstB strings.Builder
+	sstB.WriteString(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	for _, val := range sst {
+		sstB.WriteString(fmt.Sprintf(`<si><t>%s</t></si>`, val))
+	}
+	sstB.WriteString(`</sst>`)
+	for name, content := range map[string]string{
+		"xl/workbook.xml":            wb.String(),
+		"xl/_rels/workbook.xml.rels": rels.String(),
+		"xl/sharedStrings.xml":       sstB.String(),
+	} {
+		var w io.Writer
+		if w, err = zWrt.Create(name); err != nil {
+			return
+		}
+		if _, err = w.Write([]byte(content)); err != nil {
+			return
+		}
+	}
+	return zWrt.Close()
+}
+
+func TestLoaderProcessXLSXFile(t *testing.T) {
+	data := engine.NewInternalDB(nil, nil, true)
+	tpInDir := t.TempDir()
+	tpOutDir := t.TempDir()
+	ldr := &Loader{
+		ldrID:         "TestLoaderProcessXLSXFile",
+		bufLoaderData: make(map[string][]LoaderData),
+		dm:            engine.NewDataManager(data, config.CgrConfig().CacheCfg(), nil),
+		tpInDir:       tpInDir,
+		tpOutDir:      tpOutDir,
+		lockFilename:  utils.ResourcesCsv + ".lck",
+		fieldSep:      utils.FieldsSep,
+		timezone:      "UTC",
+	}
+	ldr.dataTpls = map[string][]*config.FCTemplate{
+		utils.MetaResources: {
+			{Tag: "Tenant",
+				Path:      "Tenant",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.0", utils.InfieldSep),
+				Mandatory: true},
+			{Tag: "ID",
+				Path:      "ID",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.1", utils.InfieldSep),
+				Mandatory: true},
+			{Tag: "Weight",
+				Path:  "Weight",
+				Type:  utils.MetaComposed,
+				Value: config.NewRSRParsersMustCompile("~*req.2", utils.InfieldSep)},
+		},
+	}
+	ldr.rdrs = map[string]map[string]*openedCSVFile{
+		utils.MetaResources: {utils.ResourcesCsv: nil},
+	}
+	if err := writeTestXLSX(path.Join(tpInDir, "TP.xlsx"), []string{"Resources", "Other"},
+		[][][]string{
+			{
+				{"#Tenant", "ID", "Weight"},
+				{"cgrates.org", "ResXLSX1", "10"},
+				{},
+				{"cgrates.org", "ResXLSX2"},
+			},
+			{{"ignored"}},
+		}); err != nil {
+		t.Fatal(err)
+	}
+	// the rows are read only from the processed workbook
+	if err := writeTestXLSX(path.Join(tpInDir, "A.xlsx"), []string{"Resources"},
+		[][][]string{{{"cgrates.org", "ResOtherXLSX"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(tpInDir, utils.ResourcesCsv),
+		[]byte("cgrates.org,ResCSV\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ldr.processFile(utils.EmptyString, "TP.xlsx"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"ResOtherXLSX", "ResCSV"} {
+		if _, err := ldr.dm.GetResourceProfile("cgrates.org", id, false, false, utils.NonTransactional); err != utils.ErrNotFound {
+			t.Errorf("Expected %+v for %s, received %+v", utils.ErrNotFound, id, err)
+		}
+	}
+	eResPrf := &engine.ResourceProfile{
+		Tenant:       "cgrates.org",
+		ID:           "ResXLSX1",
+		FilterIDs:    []string{},
+		ThresholdIDs: []string{},
+		Weight:       10,
+	}
+	if rcv, err := ldr.dm.GetResourceProfile("cgrates.org", "ResXLSX1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eResPrf, rcv) {
+		t.Errorf("Expected %+v, received %+v", utils.ToJSON(eResPrf), utils.ToJSON(rcv))
+	}
+	if _, err := ldr.dm.GetResourceProfile("cgrates.org", "ResXLSX2", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(path.Join(tpOutDir, "TP.xlsx")); err != nil {
+		t.Errorf("Expected the xlsx file to be moved, received: %v", err)
+	}
+
+	// the sheet is not found in any of the xlsx files
+	ldr.rdrs[utils.MetaResources] = map[string]*openedCSVFile{"Missing.csv": nil}
+	if err := ldr.processFiles(utils.MetaResources, utils.EmptyString, utils.MetaStore); !os.IsNotExist(err) {
+		t.Errorf("Expected not exist error, received %+v", err)
+	}
+}
+
+func TestXLSXColumnIndex(t *testing.T) {
+	if idx, err := xlsxColumnIndex("AB12"); err != nil {
+		t.Error(err)
+	} else if idx != 27 {
+		t.Errorf("Expected 27, received %d", idx)
+	}
+	expected := "invalid cell reference <12>"
+	if _, err := xlsxColumnIndex("12"); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+}
+
+func TestLoaderProcessFolderTransactional(t *testing.T) {
+	data := engine.NewInternalDB(nil, nil, true)
+	tpInDir := t.TempDir()
+	ldr := &Loader{
+		ldrID:         "TestLoaderProcessFolderTransactional",
+		bufLoaderData: make(map[string][]LoaderData),
+		dm:            engine.NewDataManager(data, config.CgrConfig().CacheCfg(), nil),
+		tpInDir:       tpInDir,
+		lockFilename:  ".cgr.lck",
+		fieldSep:      utils.FieldsSep,
+		timezone:      "UTC",
+		transactional: true,
+	}
+	ldr.dataTpls = map[string][]*config.FCTemplate{
+		utils.MetaFilters: {
+			{Tag: "Tenant",
+				Path:      "Tenant",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.0", utils.InfieldSep),
+				Mandatory: true},
+			{Tag: "ID",
+				Path:      "ID",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.1", utils.InfieldSep),
+				Mandatory: true},
+			{Tag: "Type",
+				Path:  "Type",
+				Type:  utils.MetaComposed,
+				Value: config.NewRSRParsersMustCompile("~*req.2", utils.InfieldSep)},
+			{Tag: "Element",
+				Path:  "Element",
+				Type:  utils.MetaComposed,
+				Value: config.NewRSRParsersMustCompile("~*req.3", utils.InfieldSep)},
+			{Tag: "Values",
+				Path:  "Values",
+				Type:  utils.MetaComposed,
+				Value: config.NewRSRParsersMustCompile("~*req.4", utils.InfieldSep)},
+		},
+		utils.MetaResources: {
+			{Tag: "Tenant",
+				Path:      "Tenant",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.0", utils.InfieldSep),
+				Mandatory: true},
+			{Tag: "ID",
+				Path:      "ID",
+				Type:      utils.MetaComposed,
+				Value:     config.NewRSRParsersMustCompile("~*req.1", utils.InfieldSep),
+				Mandatory: true},
+			{Tag: "FilterIDs",
+				Path:  "FilterIDs",
+				Type:  utils.MetaComposed,
+				Value: config.NewRSRParsersMustCompile("~*req.2", utils.InfieldSep)},
+		},
+	}
+	ldr.rdrs = map[string]map[string]*openedCSVFile{
+		utils.MetaFilters:   {utils.FiltersCsv: nil},
+		utils.MetaResources: {utils.ResourcesCsv: nil},
+	}
+	if err := ioutil.WriteFile(path.Join(tpInDir, utils.FiltersCsv),
+		[]byte("cgrates.org,FLTR_TXN_1,*string,~*req.Account,1001\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(tpInDir, utils.ResourcesCsv),
+		[]byte("cgrates.org,RES_TXN_1,FLTR_TXN_1\ncgrates.org,RES_TXN_2,FLTR_TXN_MISSING\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expected := "broken reference to filter: <FLTR_TXN_MISSING> for item with ID: <cgrates.org:RES_TXN_2>, error: NOT_FOUND"
+	if err := ldr.ProcessFolder(utils.EmptyString, utils.MetaStore, false); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	// nothing should be written
+	if _, err := ldr.dm.GetFilter("cgrates.org", "FLTR_TXN_1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	if _, err := ldr.dm.GetResourceProfile("cgrates.org", "RES_TXN_1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	if ldr.txn != nil {
+		t.Error("Expected the transaction to be dropped")
+	}
+
+	if err := ioutil.WriteFile(path.Join(tpInDir, utils.ResourcesCsv),
+		[]byte("cgrates.org,RES_TXN_1,FLTR_TXN_1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ldr.ProcessFolder(utils.EmptyString, utils.MetaStore, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ldr.dm.GetFilter("cgrates.org", "FLTR_TXN_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+	eRes := &engine.ResourceProfile{
+		Tenant:       "cgrates.org",
+		ID:           "RES_TXN_1",
+		FilterIDs:    []string{"FLTR_TXN_1"},
+		ThresholdIDs: []string{},
+	}
+	if rcv, err := ldr.dm.GetResourceProfile("cgrates.org", "RES_TXN_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eRes, rcv) {
+		t.Errorf("Expected %+v, received %+v", utils.ToJSON(eRes), utils.ToJSON(rcv))
+	}
+	if _, err := ldr.dm.GetResource("cgrates.org", "RES_TXN_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+
+	// the removals are staged as well
+	ldr.rdrs = map[string]map[string]*openedCSVFile{
+		utils.MetaResources: {utils.ResourcesCsv: nil},
+	}
+	if err := ioutil.WriteFile(path.Join(tpInDir, utils.ResourcesCsv),
+		[]byte("cgrates.org,RES_TXN_1\ncgrates.org,RES_TXN_MISSING\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expected = "failed removing <cgrates.org:RES_TXN_MISSING>, error: NOT_FOUND"
+	if err := ldr.ProcessFolder(utils.EmptyString, utils.MetaRemove, false); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	if rcv, err := ldr.dm.GetResourceProfile("cgrates.org", "RES_TXN_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eRes, rcv) {
+		t.Errorf("Expected %+v, received %+v", utils.ToJSON(eRes), utils.ToJSON(rcv))
+	}
+	if _, err := ldr.dm.GetResource("cgrates.org", "RES_TXN_1", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+
+	if err := ioutil.WriteFile(path.Join(tpInDir, utils.ResourcesCsv),
+		[]byte("cgrates.org,RES_TXN_1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ldr.ProcessFolder(utils.EmptyString, utils.MetaRemove, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ldr.dm.GetResourceProfile("cgrates.org", "RES_TXN_1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	if _, err := ldr.dm.GetResource("cgrates.org", "RES_TXN_1", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+}
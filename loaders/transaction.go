@@ -0,0 +1,84 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package loaders
+
+import (
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func newLoaderTransaction(dm *engine.DataManager) *loaderTransaction {
+	return &loaderTransaction{
+		LoadTransaction: engine.NewLoadTransaction(dm),
+		cacheArgs:       make(map[string][]string),
+		cacheIDs:        make(utils.StringSet),
+	}
+}
+
+// loaderTransaction keeps the profiles staged by a transactional load
+// together with the cache details used after commit
+type loaderTransaction struct {
+	*engine.LoadTransaction
+	cacheArgs map[string][]string
+	cacheIDs  utils.StringSet
+}
+
+// addCacheArgs merges the cache arguments of one storeLoadedData call
+func (txn *loaderTransaction) addCacheArgs(cacheArgs map[string][]string, cacheIDs []string) {
+	for argID, ids := range cacheArgs {
+		txn.cacheArgs[argID] = append(txn.cacheArgs[argID], ids...)
+	}
+	txn.cacheIDs.AddSlice(cacheIDs)
+}
+
+// store writes the profile or stages it in case of transactional load
+func (ldr *Loader) store(profile interface{}, store func() error) (err error) {
+	if ldr.txn == nil {
+		return store()
+	}
+	return ldr.txn.Stage(profile, store)
+}
+
+// remove deletes the item or stages its removal in case of transactional load
+func (ldr *Loader) remove(prefix string, tntID *utils.TenantID, remove func() error) (err error) {
+	if ldr.txn == nil {
+		return remove()
+	}
+	return ldr.txn.StageRemove(prefix, tntID, remove)
+}
+
+// inTransaction runs process staging the profiles and removals if the loader is transactional
+// the staged items are written and cached only if process succeeds
+func (ldr *Loader) inTransaction(caching string, process func() error) (err error) {
+	if !ldr.transactional {
+		return process()
+	}
+	ldr.txn = newLoaderTransaction(ldr.dm)
+	defer func() { ldr.txn = nil }()
+	if err = process(); err != nil {
+		return
+	}
+	if ldr.txn.Len() == 0 { // nothing staged(ie: dry run)
+		return
+	}
+	if err = ldr.txn.Commit(); err != nil {
+		return
+	}
+	return ldr.updateCache(caching, ldr.txn.cacheArgs, ldr.txn.cacheIDs.AsSlice())
+}
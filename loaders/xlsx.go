@@ -0,0 +1,206 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package loaders
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// the parts of the xlsx(Office Open XML) format needed to read the cell values
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String returns the text, joining the rich text runs
+func (xT xlsxText) String() string {
+	if len(xT.R) == 0 {
+		return xT.T
+	}
+	var sb strings.Builder
+	for _, r := range xT.R {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+type xlsxSharedStrings struct {
+	SI []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref  string   `xml:"r,attr"`
+			Type string   `xml:"t,attr"`
+			V    string   `xml:"v"`
+			IS   xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSXSheets returns the rows of all the sheets in the xlsx file indexed on the sheet name
+func readXLSXSheets(fPath string) (sheets map[string][][]string, err error) {
+	var zRdr *zip.ReadCloser
+	if zRdr, err = zip.OpenReader(fPath); err != nil {
+		return
+	}
+	defer zRdr.Close()
+	files := make(map[string]*zip.File)
+	for _, f := range zRdr.File {
+		files[f.Name] = f
+	}
+	var wb xlsxWorkbook
+	if err = decodeXLSXPart(files, "xl/workbook.xml", &wb); err != nil {
+		return
+	}
+	var rels xlsxRelationships
+	if err = decodeXLSXPart(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return
+	}
+	var sst xlsxSharedStrings
+	if _, has := files["xl/sharedStrings.xml"]; has {
+		if err = decodeXLSXPart(files, "xl/sharedStrings.xml", &sst); err != nil {
+			return
+		}
+	}
+	sheetPaths := make(map[string]string)
+	for _, rel := range rels.Relationships {
+		if strings.HasPrefix(rel.Target, "/") {
+			sheetPaths[rel.ID] = strings.TrimPrefix(rel.Target, "/")
+			continue
+		}
+		sheetPaths[rel.ID] = path.Join("xl", rel.Target)
+	}
+	sheets = make(map[string][][]string)
+	for _, sheet := range wb.Sheets {
+		var ws xlsxWorksheet
+		if err = decodeXLSXPart(files, sheetPaths[sheet.RID], &ws); err != nil {
+			return
+		}
+		rows := make([][]string, 0, len(ws.Rows))
+		for _, xRow := range ws.Rows {
+			var row []string
+			for i, cell := range xRow.Cells {
+				idx := i
+				if cell.Ref != utils.EmptyString {
+					if idx, err = xlsxColumnIndex(cell.Ref); err != nil {
+						return
+					}
+				}
+				for len(row) <= idx {
+					row = append(row, utils.EmptyString)
+				}
+				switch cell.Type {
+				case "s":
+					var sIdx int
+					if sIdx, err = strconv.Atoi(cell.V); err != nil {
+						return
+					}
+					if sIdx >= len(sst.SI) {
+						return nil, fmt.Errorf("invalid shared string index <%d> in sheet <%s>", sIdx, sheet.Name)
+					}
+					row[idx] = sst.SI[sIdx].String()
+				case "inlineStr":
+					row[idx] = cell.IS.String()
+				case "b":
+					row[idx] = strconv.FormatBool(cell.V == "1")
+				default:
+					row[idx] = cell.V
+				}
+			}
+			rows = append(rows, row)
+		}
+		sheets[sheet.Name] = rows
+	}
+	return
+}
+
+// decodeXLSXPart decodes the XML part of the xlsx archive
+func decodeXLSXPart(files map[string]*zip.File, name string, v interface{}) (err error) {
+	f, has := files[name]
+	if !has {
+		return fmt.Errorf("missing <%s> in the xlsx file", name)
+	}
+	var rdr io.ReadCloser
+	if rdr, err = f.Open(); err != nil {
+		return
+	}
+	defer rdr.Close()
+	return xml.NewDecoder(rdr).Decode(v)
+}
+
+// xlsxColumnIndex returns the column index out of the cell reference(ie: AB12)
+func xlsxColumnIndex(ref string) (idx int, err error) {
+	var col int
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A') + 1
+	}
+	if col == 0 {
+		return 0, fmt.Errorf("invalid cell reference <%s>", ref)
+	}
+	return col - 1, nil
+}
+
+// sheetReader reads the rows of a sheet the same way a csv.Reader reads the lines
+type sheetReader struct {
+	rows [][]string
+	idx  int
+}
+
+// Read returns the next row, ignoring the empty rows and the ones starting with #
+func (sR *sheetReader) Read() (record []string, err error) {
+	for sR.idx < len(sR.rows) {
+		record = sR.rows[sR.idx]
+		sR.idx++
+		if len(record) == 0 ||
+			strings.HasPrefix(record[0], "#") {
+			continue
+		}
+		return
+	}
+	return nil, io.EOF
+}
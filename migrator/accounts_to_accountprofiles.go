@@ -0,0 +1,257 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// migrateAccountsToAccountProfiles converts the legacy Accounts into AccountProfiles
+// in dry run mode only the differences against the AccountProfiles already stored are printed
+// nothing is converted if any of the accounts has ActionTriggers or balances in SharedGroups
+func (m *Migrator) migrateAccountsToAccountProfiles() (err error) {
+	var ids []string
+	if ids, err = m.dmIN.DataManager().DataDB().GetKeysForPrefix(utils.AccountPrefix); err != nil {
+		return
+	}
+	sort.Strings(ids)
+	var unsupported []string
+	for _, id := range ids {
+		var acc *engine.Account
+		if acc, err = m.dmIN.DataManager().GetAccount(strings.TrimPrefix(id, utils.AccountPrefix)); err != nil {
+			return
+		}
+		unsupported = append(unsupported, unsupportedAccountData(acc)...)
+	}
+	if len(unsupported) != 0 {
+		return fmt.Errorf("cannot convert the Accounts to AccountProfiles:\n%s",
+			strings.Join(unsupported, "\n"))
+	}
+	var report []string
+	for _, id := range ids {
+		var acc *engine.Account
+		if acc, err = m.dmIN.DataManager().GetAccount(strings.TrimPrefix(id, utils.AccountPrefix)); err != nil {
+			return
+		}
+		ap, accReport := accountAsAccountProfile(acc, time.Now())
+		report = append(report, accReport...)
+		if ap == nil {
+			continue
+		}
+		if m.dryRun {
+			var oldAp *utils.AccountProfile
+			if oldAp, err = m.dmOut.DataManager().GetAccountProfile(ap.Tenant, ap.ID,
+				false, false, utils.NonTransactional); err != nil && err != utils.ErrNotFound {
+				return
+			}
+			logDiff(utils.AccountProfilesString, ap.TenantID(), oldAp, err == nil, ap)
+			err = nil
+			continue
+		}
+		if err = m.dmOut.DataManager().SetAccountProfile(ap, true); err != nil {
+			return
+		}
+		m.stats[utils.AccountProfilesString]++
+	}
+	if len(report) != 0 {
+		log.Printf("Constructs which could not be converted to AccountProfiles:\n%s",
+			strings.Join(report, "\n"))
+	}
+	return
+}
+
+// unsupportedAccountData returns the ActionTriggers and SharedGroups of the Account
+// as they have no equivalent in the AccountProfile and dropping them would change the charging
+func unsupportedAccountData(acc *engine.Account) (unsupported []string) {
+	for _, at := range acc.ActionTriggers {
+		unsupported = append(unsupported, fmt.Sprintf("Account <%s>: ActionTrigger <%s> with threshold <%s> of <%v> not supported",
+			acc.ID, at.ID, at.ThresholdType, at.ThresholdValue))
+	}
+	blncTypes := make([]string, 0, len(acc.BalanceMap))
+	for blncType := range acc.BalanceMap {
+		blncTypes = append(blncTypes, blncType)
+	}
+	sort.Strings(blncTypes)
+	for _, blncType := range blncTypes {
+		for _, b := range acc.BalanceMap[blncType] {
+			if len(b.SharedGroups) == 0 {
+				continue
+			}
+			blncID := b.ID
+			if blncID == utils.EmptyString {
+				blncID = b.Uuid
+			}
+			sgs := b.SharedGroups.Slice()
+			sort.Strings(sgs)
+			unsupported = append(unsupported, fmt.Sprintf("Account <%s>: Balance <%s>: shared groups <%s> not supported",
+				acc.ID, blncID, strings.Join(sgs, utils.InfieldSep)))
+		}
+	}
+	return
+}
+
+// accountAsAccountProfile converts the legacy Account together with its balances
+// the balances expired at the time of conversion are not converted
+// the ActionTriggers and SharedGroups are checked with unsupportedAccountData before
+func accountAsAccountProfile(acc *engine.Account, now time.Time) (ap *utils.AccountProfile, report []string) {
+	addReport := func(format string, args ...interface{}) {
+		report = append(report, fmt.Sprintf("Account <%s>: ", acc.ID)+fmt.Sprintf(format, args...))
+	}
+	tntID := strings.SplitN(acc.ID, utils.ConcatenatedKeySep, 2)
+	if len(tntID) != 2 {
+		addReport("malformed ID")
+		return
+	}
+	if acc.Disabled {
+		addReport("disabled accounts not supported")
+	}
+	if acc.AllowNegative {
+		addReport("allow negative not supported")
+	}
+	ap = &utils.AccountProfile{
+		Tenant:   tntID[0],
+		ID:       tntID[1],
+		Balances: make(map[string]*utils.Balance),
+	}
+	blncTypes := make([]string, 0, len(acc.BalanceMap))
+	for blncType := range acc.BalanceMap {
+		blncTypes = append(blncTypes, blncType)
+	}
+	sort.Strings(blncTypes)
+	for _, blncType := range blncTypes {
+		for _, b := range acc.BalanceMap[blncType] {
+			blncID := b.ID
+			if blncID == utils.EmptyString {
+				blncID = b.Uuid
+			}
+			if b.Disabled {
+				addReport("Balance <%s>: disabled balances not supported", blncID)
+				continue
+			}
+			if b.IsExpiredAt(now) {
+				addReport("Balance <%s>: expired at <%s>", blncID, b.ExpirationDate.Format(time.RFC3339))
+				continue
+			}
+			if _, has := ap.Balances[blncID]; has {
+				addReport("Balance <%s>: duplicated ID", blncID)
+				continue
+			}
+			if b.RatingSubject != utils.EmptyString {
+				addReport("Balance <%s>: rating subject <%s> not supported", blncID, b.RatingSubject)
+			}
+			if len(b.TimingIDs) != 0 || len(b.Timings) != 0 {
+				addReport("Balance <%s>: timings not supported", blncID)
+			}
+			if blncType != utils.MetaMonetary && !legacyUnitBalanceTypes.Has(blncType) {
+				addReport("Balance <%s>: type <%s> not supported", blncID, blncType)
+				continue
+			}
+			ap.Balances[blncID] = balanceAsAccountBalance(blncID, blncType, b)
+		}
+	}
+	return
+}
+
+// legacyUnitBalanceTypes are the legacy balances of units, converted to *abstract balances
+// the *generic ones match any ToR while the others only their own
+var legacyUnitBalanceTypes = utils.NewStringSet([]string{utils.MetaVoice, utils.MetaData,
+	utils.MetaSMS, utils.MetaMMS, utils.MetaGeneric})
+
+// balanceAsAccountBalance converts one legacy balance
+// the *monetary balances become *concrete while the balances of units become *abstract ones
+// keeping their units (nanoseconds for *voice), free of charge as the legacy ones without rating subject
+// the destinations, categories and expiry become filters while the factors become unit factors
+func balanceAsAccountBalance(blncID, blncType string, b *engine.Balance) (blnc *utils.Balance) {
+	blnc = &utils.Balance{
+		ID:      blncID,
+		Weight:  b.Weight,
+		Blocker: b.Blocker,
+		Type:    utils.MetaConcrete,
+		Units:   utils.NewDecimalFromFloat64(b.Value),
+	}
+	if blncType != utils.MetaMonetary {
+		blnc.Type = utils.MetaAbstract
+		if blncType != utils.MetaGeneric {
+			blnc.FilterIDs = append(blnc.FilterIDs, utils.MetaString+utils.InInFieldSep+
+				utils.DynamicDataPrefix+utils.MetaReq+utils.NestingSep+utils.ToR+utils.InInFieldSep+blncType)
+		}
+		blnc.CostIncrements = []*utils.CostIncrement{{
+			Increment:    utils.NewDecimal(1, 0),
+			FixedFee:     utils.NewDecimal(0, 0),
+			RecurrentFee: utils.NewDecimal(0, 0),
+		}}
+	}
+	blnc.FilterIDs = append(blnc.FilterIDs, stringMapFilterIDs(utils.MetaDestinations, utils.MetaNotDestinations,
+		utils.Destination, b.DestinationIDs)...)
+	blnc.FilterIDs = append(blnc.FilterIDs, stringMapFilterIDs(utils.MetaString, utils.MetaNotString,
+		utils.Category, b.Categories)...)
+	if !b.ExpirationDate.IsZero() {
+		blnc.FilterIDs = append(blnc.FilterIDs, utils.MetaActivationInterval+utils.InInFieldSep+
+			utils.DynamicDataPrefix+utils.MetaReq+utils.NestingSep+utils.AnswerTime+utils.InInFieldSep+
+			utils.InfieldSep+b.ExpirationDate.Format(time.RFC3339))
+	}
+	tors := make([]string, 0, len(b.Factor))
+	for tor := range b.Factor {
+		tors = append(tors, tor)
+	}
+	sort.Strings(tors)
+	for _, tor := range tors {
+		blnc.UnitFactors = append(blnc.UnitFactors, &utils.UnitFactor{
+			FilterIDs: []string{utils.MetaString + utils.InInFieldSep +
+				utils.DynamicDataPrefix + utils.MetaReq + utils.NestingSep + utils.ToR + utils.InInFieldSep + tor},
+			Factor: utils.NewDecimalFromFloat64(b.Factor[tor]),
+		})
+	}
+	return
+}
+
+// stringMapFilterIDs builds the inline filters out of a legacy StringMap
+// where the false values are the negated ones
+func stringMapFilterIDs(fltrType, notFltrType, fldName string, sm utils.StringMap) (fltrIDs []string) {
+	var vals, notVals []string
+	for val, pos := range sm {
+		if val == utils.MetaAny {
+			continue
+		}
+		if pos {
+			vals = append(vals, val)
+		} else {
+			notVals = append(notVals, val)
+		}
+	}
+	fldName = utils.DynamicDataPrefix + utils.MetaReq + utils.NestingSep + fldName
+	if len(vals) != 0 {
+		sort.Strings(vals)
+		fltrIDs = append(fltrIDs, fltrType+utils.InInFieldSep+fldName+utils.InInFieldSep+
+			strings.Join(vals, utils.InfieldSep))
+	}
+	if len(notVals) != 0 {
+		sort.Strings(notVals)
+		fltrIDs = append(fltrIDs, notFltrType+utils.InInFieldSep+fldName+utils.InInFieldSep+
+			strings.Join(notVals, utils.InfieldSep))
+	}
+	return
+}
@@ -0,0 +1,220 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAccountAsAccountProfile(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	acc := &engine.Account{
+		ID: "cgrates.org:1001",
+		BalanceMap: map[string]engine.Balances{
+			utils.MetaMonetary: {
+				{
+					ID:             "MONETARY",
+					Value:          10,
+					Weight:         20,
+					DestinationIDs: utils.StringMap{"DST_1002": true, "DST_1003": false},
+					Categories:     utils.StringMap{"call": true},
+					ExpirationDate: now.AddDate(0, 1, 0),
+					SharedGroups:   utils.StringMap{"SG_2": true, "SG_1": true},
+				},
+				{ID: "EXPIRED", Value: 5, ExpirationDate: now.AddDate(0, -1, 0)},
+			},
+			utils.MetaVoice: {
+				{
+					Uuid:    "voice-uuid",
+					Value:   float64(time.Hour),
+					Blocker: true,
+					Factor:  engine.ValueFactor{utils.MetaVoice: 60},
+				},
+			},
+			"*unknown": {{ID: "UNKNOWN", Value: 1}},
+		},
+		ActionTriggers: engine.ActionTriggers{
+			{ID: "AT_1", ThresholdType: utils.TriggerMinBalance, ThresholdValue: 2},
+		},
+	}
+	expected := &utils.AccountProfile{
+		Tenant: "cgrates.org",
+		ID:     "1001",
+		Balances: map[string]*utils.Balance{
+			"MONETARY": {
+				ID: "MONETARY",
+				FilterIDs: []string{
+					"*destinations:~*req.Destination:DST_1002",
+					"*notdestinations:~*req.Destination:DST_1003",
+					"*string:~*req.Category:call",
+					"*ai:~*req.AnswerTime:;2021-02-01T00:00:00Z",
+				},
+				Weight: 20,
+				Type:   utils.MetaConcrete,
+				Units:  utils.NewDecimalFromFloat64(10),
+			},
+			"voice-uuid": {
+				ID:        "voice-uuid",
+				FilterIDs: []string{"*string:~*req.ToR:*voice"},
+				Blocker:   true,
+				Type:      utils.MetaAbstract,
+				CostIncrements: []*utils.CostIncrement{{
+					Increment:    utils.NewDecimal(1, 0),
+					FixedFee:     utils.NewDecimal(0, 0),
+					RecurrentFee: utils.NewDecimal(0, 0),
+				}},
+				UnitFactors: []*utils.UnitFactor{{
+					FilterIDs: []string{"*string:~*req.ToR:*voice"},
+					Factor:    utils.NewDecimalFromFloat64(60),
+				}},
+				Units: utils.NewDecimalFromFloat64(float64(time.Hour)),
+			},
+		},
+	}
+	expReport := []string{
+		"Account <cgrates.org:1001>: Balance <EXPIRED>: expired at <2020-12-01T00:00:00Z>",
+		"Account <cgrates.org:1001>: Balance <UNKNOWN>: type <*unknown> not supported",
+	}
+	ap, report := accountAsAccountProfile(acc, now)
+	if !reflect.DeepEqual(expected, ap) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expected), utils.ToJSON(ap))
+	}
+	if !reflect.DeepEqual(expReport, report) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expReport), utils.ToJSON(report))
+	}
+
+	expUnsupported := []string{
+		"Account <cgrates.org:1001>: ActionTrigger <AT_1> with threshold <*min_balance> of <2> not supported",
+		"Account <cgrates.org:1001>: Balance <MONETARY>: shared groups <SG_1;SG_2> not supported",
+	}
+	if rcv := unsupportedAccountData(acc); !reflect.DeepEqual(expUnsupported, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expUnsupported), utils.ToJSON(rcv))
+	}
+
+	expReport = []string{"Account <1001>: malformed ID"}
+	if ap, report := accountAsAccountProfile(&engine.Account{ID: "1001"}, now); ap != nil {
+		t.Errorf("Expected nil, received %s", utils.ToJSON(ap))
+	} else if !reflect.DeepEqual(expReport, report) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expReport), utils.ToJSON(report))
+	}
+}
+
+func TestMigrateAccountsToAccountProfiles(t *testing.T) {
+	tmpCache := engine.Cache
+	defer func() { engine.Cache = tmpCache }()
+	engine.Cache = engine.NewCacheS(config.CgrConfig(), nil, nil)
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	if err := dm.SetAccount(&engine.Account{
+		ID: "cgrates.org:1001",
+		BalanceMap: map[string]engine.Balances{
+			utils.MetaMonetary: {{ID: "MONETARY", Value: 10}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	iDBMig := newInternalMigrator(dm)
+	m := &Migrator{
+		dmIN:       iDBMig,
+		dmOut:      iDBMig,
+		sameDataDB: true,
+		dryRun:     true,
+		stats:      make(map[string]int),
+	}
+	if err := m.migrateAccountsToAccountProfiles(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dm.GetAccountProfile("cgrates.org", "1001", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	m.dryRun = false
+	if err := m.migrateAccountsToAccountProfiles(); err != nil {
+		t.Fatal(err)
+	}
+	if m.stats[utils.AccountProfilesString] != 1 {
+		t.Errorf("Expected 1 AccountProfile migrated, received: %d", m.stats[utils.AccountProfilesString])
+	}
+	expected := &utils.AccountProfile{
+		Tenant: "cgrates.org",
+		ID:     "1001",
+		Balances: map[string]*utils.Balance{
+			"MONETARY": {
+				ID:    "MONETARY",
+				Type:  utils.MetaConcrete,
+				Units: utils.NewDecimalFromFloat64(10),
+			},
+		},
+	}
+	if rcv, err := dm.GetAccountProfile("cgrates.org", "1001", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if utils.ToJSON(expected) != utils.ToJSON(rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expected), utils.ToJSON(rcv))
+	}
+}
+
+func TestMigrateAccountsToAccountProfilesUnsupported(t *testing.T) {
+	tmpCache := engine.Cache
+	defer func() { engine.Cache = tmpCache }()
+	engine.Cache = engine.NewCacheS(config.CgrConfig(), nil, nil)
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	if err := dm.SetAccount(&engine.Account{
+		ID: "cgrates.org:1001",
+		BalanceMap: map[string]engine.Balances{
+			utils.MetaMonetary: {{ID: "MONETARY", Value: 10}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetAccount(&engine.Account{
+		ID: "cgrates.org:1002",
+		BalanceMap: map[string]engine.Balances{
+			utils.MetaMonetary: {{ID: "SHARED", Value: 10, SharedGroups: utils.StringMap{"SG_1": true}}},
+		},
+		ActionTriggers: engine.ActionTriggers{
+			{ID: "AT_1", ThresholdType: utils.TriggerMaxBalance, ThresholdValue: 20},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	iDBMig := newInternalMigrator(dm)
+	m := &Migrator{
+		dmIN:       iDBMig,
+		dmOut:      iDBMig,
+		sameDataDB: true,
+		stats:      make(map[string]int),
+	}
+	expected := "cannot convert the Accounts to AccountProfiles:\n" +
+		"Account <cgrates.org:1002>: ActionTrigger <AT_1> with threshold <*max_balance> of <20> not supported\n" +
+		"Account <cgrates.org:1002>: Balance <SHARED>: shared groups <SG_1> not supported"
+	if err := m.migrateAccountsToAccountProfiles(); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	// none of the accounts is converted
+	if _, err := dm.GetAccountProfile("cgrates.org", "1001", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+	if m.stats[utils.AccountProfilesString] != 0 {
+		t.Errorf("Expected no AccountProfile migrated, received: %d", m.stats[utils.AccountProfilesString])
+	}
+}
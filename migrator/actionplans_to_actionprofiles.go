@@ -0,0 +1,332 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// migrateActionPlansToActionProfiles converts the legacy ActionPlans together with their Actions into ActionProfiles
+// in dry run mode only the differences against the ActionProfiles already stored are printed
+// nothing is written if any of the actions is using SharedGroups
+func (m *Migrator) migrateActionPlansToActionProfiles() (err error) {
+	var ids []string
+	if ids, err = m.dmIN.DataManager().DataDB().GetKeysForPrefix(utils.ActionPlanPrefix); err != nil {
+		return
+	}
+	sort.Strings(ids)
+	conv := newActionProfileConverter(m.dmIN.DataManager())
+	var aps []*engine.ActionProfile
+	for _, id := range ids {
+		var apl *engine.ActionPlan
+		if apl, err = m.dmIN.DataManager().GetActionPlan(strings.TrimPrefix(id, utils.ActionPlanPrefix),
+			true, utils.NonTransactional); err != nil {
+			return
+		}
+		var aplAps []*engine.ActionProfile
+		if aplAps, err = conv.asActionProfiles(apl); err != nil {
+			return
+		}
+		aps = append(aps, aplAps...)
+	}
+	if len(conv.unsupported) != 0 {
+		return fmt.Errorf("cannot convert the ActionPlans to ActionProfiles:\n%s",
+			strings.Join(conv.unsupported, "\n"))
+	}
+	for _, ap := range aps {
+		if m.dryRun {
+			var oldAp *engine.ActionProfile
+			if oldAp, err = m.dmOut.DataManager().GetActionProfile(ap.Tenant, ap.ID,
+				false, false, utils.NonTransactional); err != nil && err != utils.ErrNotFound {
+				return
+			}
+			logDiff(utils.ActionProfiles, ap.TenantID(), oldAp, err == nil, ap)
+			err = nil
+			continue
+		}
+		if err = m.dmOut.DataManager().SetActionProfile(ap, true); err != nil {
+			return
+		}
+		m.stats[utils.ActionProfiles]++
+	}
+	if len(conv.report) != 0 {
+		log.Printf("Constructs which could not be converted to ActionProfiles:\n%s",
+			strings.Join(conv.report, "\n"))
+	}
+	return
+}
+
+func newActionProfileConverter(dm *engine.DataManager) *actionProfileConverter {
+	return &actionProfileConverter{
+		dm:    dm,
+		tnt:   config.CgrConfig().GeneralCfg().DefaultTenant,
+		rsSep: config.CgrConfig().GeneralCfg().RSRSep,
+		acts:  make(map[string][]*engine.APAction),
+	}
+}
+
+// actionProfileConverter builds ActionProfiles out of the legacy ActionPlans
+// keeping the report of the constructs it could not convert
+type actionProfileConverter struct {
+	dm          *engine.DataManager
+	tnt         string // tenant used for the ActionPlans without accounts
+	rsSep       string
+	acts        map[string][]*engine.APAction // already converted actions
+	report      []string
+	unsupported []string // constructs which stop the migration as dropping them would change the charging
+}
+
+func (conv *actionProfileConverter) addReport(format string, args ...interface{}) {
+	conv.report = append(conv.report, fmt.Sprintf(format, args...))
+}
+
+func (conv *actionProfileConverter) addUnsupported(format string, args ...interface{}) {
+	conv.unsupported = append(conv.unsupported, fmt.Sprintf(format, args...))
+}
+
+// asActionProfiles returns one ActionProfile for each ActionTiming and tenant of the ActionPlan
+func (conv *actionProfileConverter) asActionProfiles(apl *engine.ActionPlan) (aps []*engine.ActionProfile, err error) {
+	accIDs := make(map[string]utils.StringSet) // accounts grouped by tenant
+	for accID := range apl.AccountIDs {
+		tntID := strings.SplitN(accID, utils.ConcatenatedKeySep, 2)
+		if len(tntID) != 2 {
+			conv.addReport("ActionPlan <%s>: malformed account ID <%s>", apl.Id, accID)
+			continue
+		}
+		if _, has := accIDs[tntID[0]]; !has {
+			accIDs[tntID[0]] = make(utils.StringSet)
+		}
+		accIDs[tntID[0]].Add(tntID[1])
+	}
+	if len(accIDs) == 0 {
+		accIDs[conv.tnt] = nil
+	}
+	tnts := make([]string, 0, len(accIDs))
+	for tnt := range accIDs {
+		tnts = append(tnts, tnt)
+	}
+	sort.Strings(tnts)
+	for i, at := range apl.ActionTimings {
+		apID := apl.Id
+		if len(apl.ActionTimings) > 1 {
+			apID += utils.Underline + strconv.Itoa(i+1)
+		}
+		if at.Timing == nil || at.Timing.Timing == nil {
+			conv.addReport("ActionPlan <%s>: missing timing for actions <%s>", apl.Id, at.ActionsID)
+			continue
+		}
+		var schedule string
+		if schedule, err = actionTimingSchedule(at.Timing.Timing); err != nil {
+			conv.addReport("ActionPlan <%s>: actions <%s>: %s", apl.Id, at.ActionsID, err)
+			err = nil
+			continue
+		}
+		var acts []*engine.APAction
+		if acts, err = conv.getAPActions(at.ActionsID); err != nil {
+			return
+		}
+		if len(acts) == 0 {
+			conv.addReport("ActionPlan <%s>: no actions converted out of <%s>", apl.Id, at.ActionsID)
+			continue
+		}
+		for _, tnt := range tnts {
+			ap := &engine.ActionProfile{
+				Tenant:   tnt,
+				ID:       apID,
+				Weight:   at.Weight,
+				Schedule: schedule,
+				Actions:  acts,
+			}
+			if accIDs[tnt] != nil {
+				ap.Targets = map[string]utils.StringSet{utils.MetaAccounts: accIDs[tnt]}
+			}
+			aps = append(aps, ap)
+		}
+	}
+	return
+}
+
+// getAPActions returns the converted legacy actions
+func (conv *actionProfileConverter) getAPActions(actsID string) (apActs []*engine.APAction, err error) {
+	var has bool
+	if apActs, has = conv.acts[actsID]; has {
+		return
+	}
+	var acts engine.Actions
+	if acts, err = conv.dm.GetActions(actsID, true, utils.NonTransactional); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		err = nil
+		conv.addReport("Actions <%s>: not found", actsID)
+	}
+	acts = append(engine.Actions{}, acts...)
+	acts.Sort() // the legacy actions are executed in the order of their weight
+	for _, a := range acts {
+		var aActs []*engine.APAction
+		if aActs, err = conv.asAPActions(actsID, a); err != nil {
+			return
+		}
+		for _, aAct := range aActs {
+			aAct.ID = actsID + utils.Underline + strconv.Itoa(len(apActs)+1)
+			apActs = append(apActs, aAct)
+		}
+	}
+	conv.acts[actsID] = apActs
+	return
+}
+
+// asAPActions converts one legacy action
+// the balance actions are converted in the ActionProfile format, with the balance type set separately
+func (conv *actionProfileConverter) asAPActions(actsID string, a *engine.Action) (apActs []*engine.APAction, err error) {
+	if a.Filter != utils.EmptyString {
+		conv.addReport("Actions <%s>: action <%s> with filter <%s> not supported", actsID, a.ActionType, a.Filter)
+		return
+	}
+	switch a.ActionType {
+	default:
+		conv.addReport("Actions <%s>: action <%s> not supported", actsID, a.ActionType)
+		return
+	case utils.MetaLog:
+		return []*engine.APAction{{Type: a.ActionType}}, nil
+	case utils.CDRLog:
+		if a.ExtraParameters != utils.EmptyString {
+			conv.addReport("Actions <%s>: action <%s> template <%s> not supported", actsID, a.ActionType, a.ExtraParameters)
+		}
+		return []*engine.APAction{{Type: a.ActionType}}, nil
+	case utils.MetaHTTPPost, utils.HttpPostAsync:
+		return []*engine.APAction{{Type: a.ActionType, Path: a.ExtraParameters}}, nil
+	case utils.MetaTopUp, utils.MetaTopUpReset, utils.MetaDebit, utils.MetaDebitReset, utils.MetaSetBalance:
+	}
+	b := a.Balance
+	if b == nil || (b.ID == nil && b.Uuid == nil) {
+		conv.addReport("Actions <%s>: action <%s> without balance ID not supported", actsID, a.ActionType)
+		return
+	}
+	blncID := b.GetID()
+	if blncID == utils.EmptyString {
+		blncID = b.GetUuid()
+	}
+	if b.SharedGroups != nil {
+		sgs := b.SharedGroups.Slice()
+		sort.Strings(sgs)
+		conv.addUnsupported("Actions <%s>: action <%s> with shared groups <%s> not supported",
+			actsID, a.ActionType, strings.Join(sgs, utils.InfieldSep))
+		return
+	}
+	if b.Value != nil && b.Value.Method != utils.EmptyString {
+		conv.addReport("Actions <%s>: action <%s> value formula <%s> not supported", actsID, a.ActionType, b.Value.Method)
+		return
+	}
+	if a.ExpirationString != utils.EmptyString || b.ExpirationDate != nil ||
+		b.Weight != nil || b.DestinationIDs != nil || b.RatingSubject != nil ||
+		b.Categories != nil || b.TimingIDs != nil ||
+		b.Disabled != nil || b.Factor != nil || b.Blocker != nil {
+		conv.addReport("Actions <%s>: action <%s> balance attributes other than type and value not supported",
+			actsID, a.ActionType)
+	}
+	blncPath := utils.DynamicDataPrefix + utils.MetaBalance + utils.NestingSep + blncID + utils.NestingSep
+	if b.Type != nil {
+		var typeAct *engine.APAction
+		if typeAct, err = conv.newAPAction(utils.MetaSetBalance, blncPath+utils.Type, *b.Type); err != nil {
+			return
+		}
+		apActs = append(apActs, typeAct)
+	}
+	var val float64
+	if b.Value != nil {
+		val = b.Value.Static
+	}
+	var valAct *engine.APAction
+	if valAct, err = conv.newAPAction(a.ActionType, blncPath+utils.Value,
+		strconv.FormatFloat(val, 'f', -1, 64)); err != nil {
+		return
+	}
+	return append(apActs, valAct), nil
+}
+
+func (conv *actionProfileConverter) newAPAction(actType, path, val string) (apAct *engine.APAction, err error) {
+	apAct = &engine.APAction{
+		Type: actType,
+		Path: path,
+	}
+	apAct.Value, err = config.NewRSRParsers(val, conv.rsSep)
+	return
+}
+
+// actionTimingSchedule converts the timing of the legacy ActionTiming
+// into the cron expression used as Schedule by the ActionProfiles
+func actionTimingSchedule(rit *engine.RITiming) (schedule string, err error) {
+	var min, hour string
+	switch rit.StartTime {
+	case utils.MetaASAP:
+		return utils.MetaASAP, nil
+	case utils.EmptyString, utils.MetaEveryMinute:
+		min, hour = utils.Meta, utils.Meta
+	case utils.MetaHourly:
+		min, hour = "0", utils.Meta
+	default:
+		hms := strings.Split(rit.StartTime, utils.InInFieldSep)
+		if len(hms) != 3 {
+			return utils.EmptyString, fmt.Errorf("start time <%s> not supported", rit.StartTime)
+		}
+		var h, m, s int
+		if h, err = strconv.Atoi(hms[0]); err != nil {
+			return utils.EmptyString, fmt.Errorf("start time <%s> not supported", rit.StartTime)
+		}
+		if m, err = strconv.Atoi(hms[1]); err != nil {
+			return utils.EmptyString, fmt.Errorf("start time <%s> not supported", rit.StartTime)
+		}
+		if s, err = strconv.Atoi(hms[2]); err != nil || s != 0 {
+			return utils.EmptyString, fmt.Errorf("start time <%s> not supported", rit.StartTime)
+		}
+		min, hour = strconv.Itoa(m), strconv.Itoa(h)
+	}
+	if rit.ID == utils.MetaMonthlyEstimated {
+		return utils.EmptyString, fmt.Errorf("timing <%s> not supported", rit.ID)
+	}
+	if len(rit.Years) != 0 {
+		return utils.EmptyString, fmt.Errorf("years <%s> not supported", rit.Years.Serialize(utils.InfieldSep))
+	}
+	monthDays := make([]int, len(rit.MonthDays))
+	for i, md := range rit.MonthDays {
+		if md < 1 {
+			return utils.EmptyString, fmt.Errorf("month day <%d> not supported", md)
+		}
+		monthDays[i] = md
+	}
+	months := make([]int, len(rit.Months))
+	for i, mo := range rit.Months {
+		months[i] = int(mo)
+	}
+	weekDays := make([]int, len(rit.WeekDays))
+	for i, wd := range rit.WeekDays {
+		weekDays[i] = int(wd)
+	}
+	return strings.Join([]string{min, hour,
+		cronField(monthDays), cronField(months), cronField(weekDays)}, " "), nil
+}
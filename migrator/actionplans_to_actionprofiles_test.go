@@ -0,0 +1,229 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestActionTimingSchedule(t *testing.T) {
+	for _, tc := range []struct {
+		rit      *engine.RITiming
+		expected string
+		err      string
+	}{
+		{rit: &engine.RITiming{StartTime: utils.MetaASAP}, expected: utils.MetaASAP},
+		{rit: &engine.RITiming{StartTime: utils.MetaEveryMinute}, expected: "* * * * *"},
+		{rit: &engine.RITiming{StartTime: utils.MetaHourly}, expected: "0 * * * *"},
+		{rit: &engine.RITiming{
+			MonthDays: utils.MonthDays{1},
+			StartTime: "00:00:00",
+		}, expected: "0 0 1 * *"},
+		{rit: &engine.RITiming{
+			WeekDays:  utils.WeekDays{time.Saturday, time.Sunday},
+			Months:    utils.Months{time.January, time.July},
+			StartTime: "14:30:00",
+		}, expected: "30 14 * 1,7 6,0"},
+		{rit: &engine.RITiming{StartTime: "14:30:10"},
+			err: "start time <14:30:10> not supported"},
+		{rit: &engine.RITiming{Years: utils.Years{2021}, StartTime: "00:00:00"},
+			err: "years <2021> not supported"},
+		{rit: &engine.RITiming{MonthDays: utils.MonthDays{-1}, StartTime: "00:00:00"},
+			err: "month day <-1> not supported"},
+		{rit: &engine.RITiming{ID: utils.MetaMonthlyEstimated, StartTime: "00:00:00"},
+			err: "timing <*monthly_estimated> not supported"},
+	} {
+		if rcv, err := actionTimingSchedule(tc.rit); tc.err != utils.EmptyString {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("Expected %+v, received %+v", tc.err, err)
+			}
+		} else if err != nil {
+			t.Error(err)
+		} else if rcv != tc.expected {
+			t.Errorf("Expected %q, received %q", tc.expected, rcv)
+		}
+	}
+}
+
+func TestMigrateActionPlansToActionProfiles(t *testing.T) {
+	tmpCache := engine.Cache
+	defer func() { engine.Cache = tmpCache }()
+	engine.Cache = engine.NewCacheS(config.CgrConfig(), nil, nil)
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	if err := dm.SetActions("ACT_TOPUP", engine.Actions{
+		{
+			Id:         "ACT_TOPUP",
+			ActionType: utils.MetaLog,
+			Weight:     10,
+		},
+		{
+			Id:         "ACT_TOPUP",
+			ActionType: utils.MetaTopUpReset,
+			Weight:     20,
+			Balance: &engine.BalanceFilter{
+				ID:    utils.StringPointer("MONETARY"),
+				Type:  utils.StringPointer(utils.MetaMonetary),
+				Value: &utils.ValueFormula{Static: 10},
+			},
+		},
+		{
+			Id:         "ACT_TOPUP",
+			ActionType: utils.MetaResetTriggers,
+		},
+	}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetActionPlan("AP_MONTHLY", &engine.ActionPlan{
+		Id:         "AP_MONTHLY",
+		AccountIDs: utils.StringMap{"cgrates.org:1001": true, "cgrates.org:1002": true},
+		ActionTimings: []*engine.ActionTiming{{
+			Uuid: "uuid1",
+			Timing: &engine.RateInterval{Timing: &engine.RITiming{
+				MonthDays: utils.MonthDays{1},
+				StartTime: "00:00:00",
+			}},
+			ActionsID: "ACT_TOPUP",
+			Weight:    10,
+		}},
+	}, true, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	iDBMig := newInternalMigrator(dm)
+	m := &Migrator{
+		dmIN:       iDBMig,
+		dmOut:      iDBMig,
+		sameDataDB: true,
+		dryRun:     true,
+		stats:      make(map[string]int),
+	}
+	if err := m.migrateActionPlansToActionProfiles(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dm.GetActionProfile("cgrates.org", "AP_MONTHLY", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+
+	conv := newActionProfileConverter(dm)
+	apl, err := dm.GetActionPlan("AP_MONTHLY", true, utils.NonTransactional)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conv.asActionProfiles(apl); err != nil {
+		t.Fatal(err)
+	}
+	expReport := []string{"Actions <ACT_TOPUP>: action <*reset_triggers> not supported"}
+	if !reflect.DeepEqual(expReport, conv.report) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expReport), utils.ToJSON(conv.report))
+	}
+
+	m.dryRun = false
+	if err := m.migrateActionPlansToActionProfiles(); err != nil {
+		t.Fatal(err)
+	}
+	if m.stats[utils.ActionProfiles] != 1 {
+		t.Errorf("Expected 1 ActionProfile migrated, received: %d", m.stats[utils.ActionProfiles])
+	}
+	expected := &engine.ActionProfile{
+		Tenant:   "cgrates.org",
+		ID:       "AP_MONTHLY",
+		Weight:   10,
+		Schedule: "0 0 1 * *",
+		Targets:  map[string]utils.StringSet{utils.MetaAccounts: utils.NewStringSet([]string{"1001", "1002"})},
+		Actions: []*engine.APAction{
+			{
+				ID:    "ACT_TOPUP_1",
+				Type:  utils.MetaSetBalance,
+				Path:  "~*balance.MONETARY.Type",
+				Value: config.NewRSRParsersMustCompile(utils.MetaMonetary, utils.InfieldSep),
+			},
+			{
+				ID:    "ACT_TOPUP_2",
+				Type:  utils.MetaTopUpReset,
+				Path:  "~*balance.MONETARY.Value",
+				Value: config.NewRSRParsersMustCompile("10", utils.InfieldSep),
+			},
+			{
+				ID:   "ACT_TOPUP_3",
+				Type: utils.MetaLog,
+			},
+		},
+	}
+	if rcv, err := dm.GetActionProfile("cgrates.org", "AP_MONTHLY", false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if utils.ToJSON(expected) != utils.ToJSON(rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expected), utils.ToJSON(rcv))
+	}
+}
+
+func TestMigrateActionPlansToActionProfilesSharedGroups(t *testing.T) {
+	tmpCache := engine.Cache
+	defer func() { engine.Cache = tmpCache }()
+	engine.Cache = engine.NewCacheS(config.CgrConfig(), nil, nil)
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	if err := dm.SetActions("ACT_LOG", engine.Actions{{Id: "ACT_LOG", ActionType: utils.MetaLog}},
+		utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetActions("ACT_SHARED", engine.Actions{{
+		Id:         "ACT_SHARED",
+		ActionType: utils.MetaTopUp,
+		Balance: &engine.BalanceFilter{
+			ID:           utils.StringPointer("SHARED"),
+			Value:        &utils.ValueFormula{Static: 10},
+			SharedGroups: &utils.StringMap{"SG_2": true, "SG_1": true},
+		},
+	}}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	for aplID, actsID := range map[string]string{"AP_LOG": "ACT_LOG", "AP_SHARED": "ACT_SHARED"} {
+		if err := dm.SetActionPlan(aplID, &engine.ActionPlan{
+			Id:         aplID,
+			AccountIDs: utils.StringMap{"cgrates.org:1001": true},
+			ActionTimings: []*engine.ActionTiming{{
+				Uuid:      aplID,
+				Timing:    &engine.RateInterval{Timing: &engine.RITiming{StartTime: utils.MetaASAP}},
+				ActionsID: actsID,
+			}},
+		}, true, utils.NonTransactional); err != nil {
+			t.Fatal(err)
+		}
+	}
+	iDBMig := newInternalMigrator(dm)
+	m := &Migrator{
+		dmIN:       iDBMig,
+		dmOut:      iDBMig,
+		sameDataDB: true,
+		stats:      make(map[string]int),
+	}
+	expected := "cannot convert the ActionPlans to ActionProfiles:\n" +
+		"Actions <ACT_SHARED>: action <*topup> with shared groups <SG_1;SG_2> not supported"
+	if err := m.migrateActionPlansToActionProfiles(); err == nil || err.Error() != expected {
+		t.Errorf("Expected %+v, received %+v", expected, err)
+	}
+	// nothing is written, not even the ActionPlans converted before
+	if _, err := dm.GetActionProfile("cgrates.org", "AP_LOG", false, false, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("Expected %+v, received %+v", utils.ErrNotFound, err)
+	}
+}
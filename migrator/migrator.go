@@ -148,6 +148,12 @@ func (m *Migrator) Migrate(taskIDs []string) (err error, stats map[string]int) {
 			err = m.migrateResources()
 		case utils.MetaRateProfiles:
 			err = m.migrateRateProfiles()
+		case utils.MetaRatingToRateProfiles:
+			err = m.migrateRatingToRateProfiles()
+		case utils.MetaAccountsToProfiles:
+			err = m.migrateAccountsToAccountProfiles()
+		case utils.MetaActPlansToProfiles:
+			err = m.migrateActionPlansToActionProfiles()
 		case MetaAliases:
 			err = m.migrateAlias()
 		case utils.MetaUsers:
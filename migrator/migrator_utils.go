@@ -20,6 +20,7 @@ package migrator
 
 import (
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/cgrates/cgrates/config"
@@ -85,6 +86,20 @@ func NewMigratorStorDB(db_type, host, port, name, user, pass, marshaler string,
 	}
 	return d, nil
 }
+
+// logDiff prints in dry run mode the difference between the stored item and the converted one
+func logDiff(itmType, tntID string, oldItm interface{}, hasOld bool, newItm interface{}) {
+	newJSON := utils.ToJSON(newItm)
+	if !hasOld {
+		log.Printf("+ %s <%s>: %s", itmType, tntID, newJSON)
+		return
+	}
+	if oldJSON := utils.ToJSON(oldItm); oldJSON != newJSON {
+		log.Printf("- %s <%s>: %s", itmType, tntID, oldJSON)
+		log.Printf("+ %s <%s>: %s", itmType, tntID, newJSON)
+	}
+}
+
 func (m *Migrator) getVersions(str string) (vrs engine.Versions, err error) {
 	if str == utils.CDRs || str == utils.SessionSCosts || strings.HasPrefix(str, "Tp") {
 		vrs, err = m.storDBIn.StorDB().GetVersions(utils.EmptyString)
@@ -0,0 +1,435 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	// destinations with more prefixes than this are referenced
+	// with *destinations filters instead of inline *prefix ones
+	maxInlineDstPrefixes = 10
+	// weight of the profiles built for a specific subject so they
+	// win over the ones built for the *any subject
+	subjectRateProfileWeight = 10
+	// weight added to the rates for each digit of their destination prefixes
+	// so the longest prefix wins as in the RatingPlans, the weights
+	// of the rates in the RatingPlans are expected below it
+	prefixLengthWeight = 1000
+	// layout of the activation time in the IDs of the profiles
+	activationIDLayout = "20060102T150405"
+)
+
+var dstFieldName = utils.DynamicDataPrefix + utils.MetaReq + utils.NestingSep + utils.Destination
+
+// migrateRatingToRateProfiles converts the legacy RatingProfiles together with their
+// RatingPlans, DestinationRates and Timings into RateProfiles
+func (m *Migrator) migrateRatingToRateProfiles() (err error) {
+	var ids []string
+	if ids, err = m.dmIN.DataManager().DataDB().GetKeysForPrefix(utils.RatingProfilePrefix); err != nil {
+		return
+	}
+	sort.Strings(ids)
+	conv := newRateProfileConverter(m.dmIN.DataManager())
+	for _, id := range ids {
+		var rpf *engine.RatingProfile
+		if rpf, err = m.dmIN.DataManager().GetRatingProfile(strings.TrimPrefix(id, utils.RatingProfilePrefix),
+			true, utils.NonTransactional); err != nil {
+			return
+		}
+		var rps []*engine.RateProfile
+		if rps, err = conv.asRateProfiles(rpf); err != nil {
+			return
+		}
+		if m.dryRun {
+			continue
+		}
+		for _, rp := range rps {
+			if err = m.dmOut.DataManager().SetRateProfile(rp, true); err != nil {
+				return
+			}
+			m.stats[utils.RateProfiles]++
+		}
+	}
+	if !m.dryRun && !m.sameDataDB {
+		// the destinations referenced by *destinations filters need to be found in the new DataDB
+		for _, dst := range conv.usedDsts {
+			if err = m.dmOut.DataManager().SetDestination(dst, utils.NonTransactional); err != nil {
+				return
+			}
+			if err = m.dmOut.DataManager().SetReverseDestination(dst.Id, dst.Prefixes, utils.NonTransactional); err != nil {
+				return
+			}
+		}
+	}
+	if len(conv.report) != 0 {
+		log.Printf("Constructs which could not be converted to RateProfiles:\n%s",
+			strings.Join(conv.report, "\n"))
+	}
+	return
+}
+
+func newRateProfileConverter(dm *engine.DataManager) *rateProfileConverter {
+	return &rateProfileConverter{
+		dm:       dm,
+		rPlans:   make(map[string]*engine.RatingPlan),
+		dsts:     make(map[string]*engine.Destination),
+		usedDsts: make(map[string]*engine.Destination),
+	}
+}
+
+// rateProfileConverter builds RateProfiles out of the legacy rating data
+// keeping the report of the constructs it could not convert
+type rateProfileConverter struct {
+	dm       *engine.DataManager
+	rPlans   map[string]*engine.RatingPlan  // already read rating plans
+	dsts     map[string]*engine.Destination // already read destinations, nil if missing
+	usedDsts map[string]*engine.Destination // destinations referenced with *destinations filters
+	report   []string
+}
+
+func (conv *rateProfileConverter) addReport(format string, args ...interface{}) {
+	conv.report = append(conv.report, fmt.Sprintf(format, args...))
+}
+
+// asRateProfiles returns one RateProfile for each activation of the RatingProfile
+func (conv *rateProfileConverter) asRateProfiles(rpf *engine.RatingProfile) (rps []*engine.RateProfile, err error) {
+	keys := strings.SplitN(rpf.Id, utils.ConcatenatedKeySep, 4) // *out:tenant:category:subject
+	if len(keys) != 4 {
+		conv.addReport("RatingProfile <%s>: malformed ID", rpf.Id)
+		return
+	}
+	tnt, category, subject := keys[1], keys[2], keys[3]
+	var fltrIDs []string
+	var weight float64
+	if category != utils.MetaAny {
+		fltrIDs = append(fltrIDs, utils.MetaString+utils.InInFieldSep+
+			utils.DynamicDataPrefix+utils.MetaReq+utils.NestingSep+utils.Category+utils.InInFieldSep+category)
+	}
+	if subject != utils.MetaAny {
+		fltrIDs = append(fltrIDs, utils.MetaString+utils.InInFieldSep+
+			utils.DynamicDataPrefix+utils.MetaReq+utils.NestingSep+utils.Subject+utils.InInFieldSep+subject)
+		weight = subjectRateProfileWeight
+	}
+	rpas := make(engine.RatingPlanActivations, len(rpf.RatingPlanActivations))
+	copy(rpas, rpf.RatingPlanActivations)
+	sort.Sort(rpas)
+	for i, rpa := range rpas {
+		if len(rpa.FallbackKeys) != 0 {
+			conv.addReport("RatingProfile <%s>: fallback subjects <%s> not supported",
+				rpf.Id, strings.Join(rpa.FallbackKeys, utils.InfieldSep))
+		}
+		var rPlan *engine.RatingPlan
+		if rPlan, err = conv.getRatingPlan(rpa.RatingPlanId); err != nil {
+			return
+		}
+		if rPlan == nil {
+			conv.addReport("RatingProfile <%s>: missing RatingPlan <%s>", rpf.Id, rpa.RatingPlanId)
+			continue
+		}
+		rpID := strings.Join([]string{category, subject, rpa.RatingPlanId,
+			rpa.ActivationTime.UTC().Format(activationIDLayout)}, utils.Underline) // the same plan can be activated more times
+		rp := &engine.RateProfile{
+			Tenant:    tnt,
+			ID:        rpID,
+			FilterIDs: fltrIDs,
+			ActivationInterval: &utils.ActivationInterval{
+				ActivationTime: rpa.ActivationTime,
+			},
+			Weight: weight,
+			Rates:  make(map[string]*engine.Rate),
+		}
+		if i+1 < len(rpas) {
+			rp.ActivationInterval.ExpiryTime = rpas[i+1].ActivationTime
+		}
+		if err = conv.addRates(rp, rPlan); err != nil {
+			return
+		}
+		if len(rp.Rates) == 0 {
+			conv.addReport("RatingProfile <%s>: no rates converted out of RatingPlan <%s>", rpf.Id, rpa.RatingPlanId)
+			continue
+		}
+		rps = append(rps, rp)
+	}
+	return
+}
+
+// addRates populates the RateProfile with the rates out of the RatingPlan
+func (conv *rateProfileConverter) addRates(rp *engine.RateProfile, rPlan *engine.RatingPlan) (err error) {
+	reported := make(utils.StringSet) // report the rounding only once per rating
+	dstIDs := make([]string, 0, len(rPlan.DestinationRates))
+	for dstID := range rPlan.DestinationRates {
+		dstIDs = append(dstIDs, dstID)
+	}
+	sort.Strings(dstIDs)
+	for _, dstID := range dstIDs {
+		var dstFltrs []*dstRateFilter
+		if dstFltrs, err = conv.destinationFilters(dstID); err != nil {
+			return
+		}
+		if dstFltrs == nil {
+			conv.addReport("RatingPlan <%s>: missing Destination <%s>", rPlan.Id, dstID)
+			continue
+		}
+		for _, rpr := range rPlan.DestinationRates[dstID] {
+			rit, has := rPlan.Timings[rpr.Timing]
+			if !has {
+				conv.addReport("RatingPlan <%s>: missing Timing <%s>", rPlan.Id, rpr.Timing)
+				continue
+			}
+			var aTimes string
+			if aTimes, err = timingAsActivationTimes(rit); err != nil {
+				conv.addReport("RatingPlan <%s>: Timing <%s>: %s", rPlan.Id, rpr.Timing, err)
+				err = nil
+				continue
+			}
+			rir, has := rPlan.Ratings[rpr.Rating]
+			if !has {
+				conv.addReport("RatingPlan <%s>: missing Rating <%s>", rPlan.Id, rpr.Rating)
+				continue
+			}
+			if rir.RoundingMethod != utils.EmptyString && !reported.Has(rpr.Rating) {
+				conv.addReport("RatingPlan <%s>: Rating <%s>: rounding <%s> with <%d> decimals not supported",
+					rPlan.Id, rpr.Rating, rir.RoundingMethod, rir.RoundingDecimals)
+				reported.Add(rpr.Rating)
+			}
+			if rir.MaxCost != 0 {
+				conv.setMaxCost(rp, rPlan.Id, rpr.Rating, rir)
+			}
+			rtID := strings.Join([]string{dstID, rpr.Timing, rpr.Rating}, utils.Underline)
+			for _, dstFltr := range dstFltrs {
+				dstRtID := rtID
+				if len(dstFltrs) > 1 {
+					dstRtID += utils.Underline + strconv.Itoa(dstFltr.pfxLen)
+				}
+				rp.Rates[dstRtID] = &engine.Rate{
+					ID:              dstRtID,
+					FilterIDs:       dstFltr.fltrIDs,
+					ActivationTimes: aTimes,
+					Weight:          float64(dstFltr.pfxLen*prefixLengthWeight) + rpr.Weight,
+					IntervalRates:   ratingAsIntervalRates(rir),
+				}
+			}
+		}
+	}
+	return
+}
+
+// setMaxCost moves the MaxCost of the rating on the RateProfile
+func (conv *rateProfileConverter) setMaxCost(rp *engine.RateProfile, rPlanID, ratingID string, rir *engine.RIRate) {
+	maxCost := utils.NewDecimalFromFloat64(rir.MaxCost)
+	if rp.MaxCost == nil {
+		rp.MaxCost = maxCost
+		rp.MaxCostStrategy = rir.MaxCostStrategy
+		return
+	}
+	if rp.MaxCost.Compare(maxCost) != 0 ||
+		rp.MaxCostStrategy != rir.MaxCostStrategy {
+		conv.addReport("RatingPlan <%s>: Rating <%s>: MaxCost <%v> with strategy <%s> conflicts with the one of RateProfile <%s>",
+			rPlanID, ratingID, rir.MaxCost, rir.MaxCostStrategy, rp.TenantID())
+	}
+}
+
+func (conv *rateProfileConverter) getRatingPlan(rPlanID string) (rPlan *engine.RatingPlan, err error) {
+	var has bool
+	if rPlan, has = conv.rPlans[rPlanID]; has {
+		return
+	}
+	if rPlan, err = conv.dm.GetRatingPlan(rPlanID, true, utils.NonTransactional); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		err = nil
+	}
+	conv.rPlans[rPlanID] = rPlan
+	return
+}
+
+// dstRateFilter matches the prefixes of one length of a destination
+type dstRateFilter struct {
+	fltrIDs []string
+	pfxLen  int // gives the weight of the rates
+}
+
+// destinationFilters returns the filters matching the destination, one for each length of its prefixes
+// the destinations with many prefixes are matched by one *destinations filter, weighted by their shortest prefix
+// nil is returned if the destination is missing
+func (conv *rateProfileConverter) destinationFilters(dstID string) (dstFltrs []*dstRateFilter, err error) {
+	if dstID == utils.MetaAny {
+		return []*dstRateFilter{{}}, nil
+	}
+	dst, has := conv.dsts[dstID]
+	if !has {
+		if dst, err = conv.dm.GetDestination(dstID, false, false, utils.NonTransactional); err != nil {
+			if err != utils.ErrNotFound {
+				return
+			}
+			err = nil
+		}
+		conv.dsts[dstID] = dst
+	}
+	if dst == nil {
+		return
+	}
+	pfxsByLen := make(map[int][]string)
+	pfxLens := make([]int, 0, 1)
+	for _, pfx := range dst.Prefixes {
+		if _, has := pfxsByLen[len(pfx)]; !has {
+			pfxLens = append(pfxLens, len(pfx))
+		}
+		pfxsByLen[len(pfx)] = append(pfxsByLen[len(pfx)], pfx)
+	}
+	sort.Ints(pfxLens)
+	if len(dst.Prefixes) > maxInlineDstPrefixes {
+		if _, has := conv.usedDsts[dstID]; !has && len(pfxLens) > 1 {
+			conv.addReport("Destination <%s>: prefixes of different lengths weighted as the shortest one", dstID)
+		}
+		conv.usedDsts[dstID] = dst
+		return []*dstRateFilter{{
+			fltrIDs: []string{utils.MetaDestinations + utils.InInFieldSep + dstFieldName + utils.InInFieldSep + dstID},
+			pfxLen:  pfxLens[0],
+		}}, nil
+	}
+	dstFltrs = make([]*dstRateFilter, len(pfxLens))
+	for i, pfxLen := range pfxLens {
+		dstFltrs[i] = &dstRateFilter{
+			fltrIDs: []string{utils.MetaPrefix + utils.InInFieldSep + dstFieldName + utils.InInFieldSep +
+				strings.Join(pfxsByLen[pfxLen], utils.InfieldSep)},
+			pfxLen: pfxLen,
+		}
+	}
+	return
+}
+
+// ratingAsIntervalRates converts the rate groups of a legacy rating
+// the connect fee is charged once, as FixedFee of the first interval
+func ratingAsIntervalRates(rir *engine.RIRate) (iRts []*engine.IntervalRate) {
+	rGrps := make(engine.RateGroups, len(rir.Rates))
+	copy(rGrps, rir.Rates)
+	sort.Slice(rGrps, func(i, j int) bool {
+		return rGrps[i].GroupIntervalStart < rGrps[j].GroupIntervalStart
+	})
+	iRts = make([]*engine.IntervalRate, len(rGrps))
+	for i, rg := range rGrps {
+		iRts[i] = &engine.IntervalRate{
+			IntervalStart: rg.GroupIntervalStart,
+			RecurrentFee:  utils.NewDecimalFromFloat64(rg.Value),
+			Unit:          utils.NewDecimal(int64(rg.RateUnit), 0),
+			Increment:     utils.NewDecimal(int64(rg.RateIncrement), 0),
+		}
+	}
+	if len(iRts) != 0 && rir.ConnectFee != 0 {
+		iRts[0].FixedFee = utils.NewDecimalFromFloat64(rir.ConnectFee)
+	}
+	return
+}
+
+// timingAsActivationTimes converts the legacy timing into the cron
+// expression used as ActivationTimes by the rates
+func timingAsActivationTimes(rit *engine.RITiming) (aTimes string, err error) {
+	if len(rit.Years) != 0 {
+		return utils.EmptyString, fmt.Errorf("years <%s> not supported", rit.Years.Serialize(utils.InfieldSep))
+	}
+	hours := utils.Meta
+	if rit.StartTime != utils.EmptyString || rit.EndTime != utils.EmptyString {
+		var sHour, eHour int
+		if rit.StartTime != utils.EmptyString {
+			if sHour, err = timingHour(rit.StartTime, false); err != nil {
+				return
+			}
+		}
+		eHour = 23
+		if rit.EndTime != utils.EmptyString {
+			if eHour, err = timingHour(rit.EndTime, true); err != nil {
+				return
+			}
+		}
+		if eHour < sHour {
+			return utils.EmptyString, fmt.Errorf("end time <%s> before start time <%s> not supported",
+				rit.EndTime, rit.StartTime)
+		}
+		switch {
+		case sHour == 0 && eHour == 23:
+		case sHour == eHour:
+			hours = strconv.Itoa(sHour)
+		default:
+			hours = strconv.Itoa(sHour) + "-" + strconv.Itoa(eHour)
+		}
+	}
+	monthDays := make([]int, len(rit.MonthDays))
+	for i, md := range rit.MonthDays {
+		if md < 1 {
+			return utils.EmptyString, fmt.Errorf("month day <%d> not supported", md)
+		}
+		monthDays[i] = md
+	}
+	months := make([]int, len(rit.Months))
+	for i, mo := range rit.Months {
+		months[i] = int(mo)
+	}
+	weekDays := make([]int, len(rit.WeekDays))
+	for i, wd := range rit.WeekDays {
+		weekDays[i] = int(wd)
+	}
+	return strings.Join([]string{utils.Meta, hours,
+		cronField(monthDays), cronField(months), cronField(weekDays)}, " "), nil
+}
+
+// timingHour returns the hour out of a ##:##:## formatted time
+// only full hours can be converted since the rates are activated on hour ranges
+func timingHour(hms string, end bool) (hour int, err error) {
+	if end && hms == "23:59:59" {
+		return 23, nil
+	}
+	var t time.Time
+	if t, err = time.Parse("15:04:05", hms); err != nil {
+		return 0, fmt.Errorf("time <%s> not supported", hms)
+	}
+	if t.Minute() != 0 || t.Second() != 0 {
+		return 0, fmt.Errorf("time <%s> not on the hour not supported", hms)
+	}
+	hour = t.Hour()
+	if end { // the end hour is not included
+		if hour == 0 {
+			return 0, fmt.Errorf("end time <%s> not supported", hms)
+		}
+		hour--
+	}
+	return
+}
+
+func cronField(vals []int) string {
+	if len(vals) == 0 {
+		return utils.Meta
+	}
+	strs := make([]string, len(vals))
+	for i, val := range vals {
+		strs[i] = strconv.Itoa(val)
+	}
+	return strings.Join(strs, utils.FieldsSep)
+}
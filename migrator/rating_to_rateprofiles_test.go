@@ -0,0 +1,242 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestTimingAsActivationTimes(t *testing.T) {
+	for _, tc := range []struct {
+		rit      *engine.RITiming
+		expected string
+		err      string
+	}{
+		{rit: &engine.RITiming{}, expected: "* * * * *"},
+		{rit: &engine.RITiming{StartTime: "00:00:00"}, expected: "* * * * *"},
+		{rit: &engine.RITiming{
+			WeekDays:  utils.WeekDays{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			StartTime: "08:00:00",
+			EndTime:   "19:00:00",
+		}, expected: "* 8-18 * * 1,2,3,4,5"},
+		{rit: &engine.RITiming{
+			Months:    utils.Months{time.December},
+			MonthDays: utils.MonthDays{24, 25},
+			StartTime: "19:00:00",
+		}, expected: "* 19-23 24,25 12 *"},
+		{rit: &engine.RITiming{StartTime: "07:00:00", EndTime: "08:00:00"}, expected: "* 7 * * *"},
+		{rit: &engine.RITiming{StartTime: "07:00:00", EndTime: "23:59:59"}, expected: "* 7-23 * * *"},
+		{rit: &engine.RITiming{Years: utils.Years{2020, 2021}},
+			err: "years <2020;2021> not supported"},
+		{rit: &engine.RITiming{MonthDays: utils.MonthDays{-1}},
+			err: "month day <-1> not supported"},
+		{rit: &engine.RITiming{StartTime: "08:30:00"},
+			err: "time <08:30:00> not on the hour not supported"},
+		{rit: &engine.RITiming{StartTime: utils.MetaHourly},
+			err: "time <*hourly> not supported"},
+		{rit: &engine.RITiming{StartTime: "19:00:00", EndTime: "08:00:00"},
+			err: "end time <08:00:00> before start time <19:00:00> not supported"},
+	} {
+		if rcv, err := timingAsActivationTimes(tc.rit); tc.err != utils.EmptyString {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("Expected %+v, received %+v", tc.err, err)
+			}
+		} else if err != nil {
+			t.Error(err)
+		} else if rcv != tc.expected {
+			t.Errorf("Expected %q, received %q", tc.expected, rcv)
+		}
+	}
+}
+
+func TestRatingAsIntervalRates(t *testing.T) {
+	rir := &engine.RIRate{
+		ConnectFee: 0.4,
+		Rates: engine.RateGroups{
+			{GroupIntervalStart: time.Minute, Value: 0.1, RateIncrement: time.Second, RateUnit: time.Minute},
+			{GroupIntervalStart: 0, Value: 0.2, RateIncrement: time.Minute, RateUnit: time.Minute},
+		},
+	}
+	expected := []*engine.IntervalRate{
+		{
+			IntervalStart: 0,
+			FixedFee:      utils.NewDecimalFromFloat64(0.4),
+			RecurrentFee:  utils.NewDecimalFromFloat64(0.2),
+			Unit:          utils.NewDecimal(int64(time.Minute), 0),
+			Increment:     utils.NewDecimal(int64(time.Minute), 0),
+		},
+		{
+			IntervalStart: time.Minute,
+			RecurrentFee:  utils.NewDecimalFromFloat64(0.1),
+			Unit:          utils.NewDecimal(int64(time.Minute), 0),
+			Increment:     utils.NewDecimal(int64(time.Second), 0),
+		},
+	}
+	if rcv := ratingAsIntervalRates(rir); !reflect.DeepEqual(expected, rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expected), utils.ToJSON(rcv))
+	}
+}
+
+func TestMigrateRatingToRateProfiles(t *testing.T) {
+	tmpCache := engine.Cache
+	defer func() { engine.Cache = tmpCache }()
+	engine.Cache = engine.NewCacheS(config.CgrConfig(), nil, nil)
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	if err := dm.SetDestination(&engine.Destination{Id: "DST_1002", Prefixes: []string{"1002", "+491002"}},
+		utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	dstLong := &engine.Destination{Id: "DST_DE"}
+	for i := 0; i <= maxInlineDstPrefixes; i++ {
+		dstLong.Prefixes = append(dstLong.Prefixes, fmt.Sprintf("49%02d", i))
+	}
+	dstLong.Prefixes = append(dstLong.Prefixes, "491")
+	if err := dm.SetDestination(dstLong, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetRatingPlan(&engine.RatingPlan{
+		Id: "RP_1",
+		Timings: map[string]*engine.RITiming{
+			"PEAK":     {WeekDays: utils.WeekDays{time.Monday}, StartTime: "08:00:00"},
+			"HALFHOUR": {StartTime: "08:30:00"},
+		},
+		Ratings: map[string]*engine.RIRate{
+			"RT_1": {
+				ConnectFee: 0.1,
+				MaxCost:    10,
+				Rates: engine.RateGroups{
+					{Value: 0.2, RateIncrement: time.Second, RateUnit: time.Minute},
+				},
+			},
+		},
+		DestinationRates: map[string]engine.RPRateList{
+			"DST_1002":    {{Timing: "PEAK", Rating: "RT_1", Weight: 10}, {Timing: "HALFHOUR", Rating: "RT_1"}},
+			"DST_DE":      {{Timing: "PEAK", Rating: "RT_1"}},
+			"DST_MISSING": {{Timing: "PEAK", Rating: "RT_1"}},
+		},
+	}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	aTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := dm.SetRatingProfile(&engine.RatingProfile{
+		Id: "*out:cgrates.org:call:1001",
+		RatingPlanActivations: engine.RatingPlanActivations{
+			{ActivationTime: aTime.AddDate(1, 0, 0), RatingPlanId: "RP_MISSING"},
+			{ActivationTime: aTime, RatingPlanId: "RP_1", FallbackKeys: []string{"*out:cgrates.org:call:*any"}},
+			{ActivationTime: aTime.AddDate(2, 0, 0), RatingPlanId: "RP_1"},
+		},
+	}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	iDBMig := newInternalMigrator(dm)
+	m := &Migrator{
+		dmIN:       iDBMig,
+		dmOut:      iDBMig,
+		sameDataDB: true,
+		stats:      make(map[string]int),
+	}
+
+	conv := newRateProfileConverter(dm)
+	rpf, err := dm.GetRatingProfile("*out:cgrates.org:call:1001", true, utils.NonTransactional)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conv.asRateProfiles(rpf); err != nil {
+		t.Fatal(err)
+	}
+	expReport := []string{
+		"RatingProfile <*out:cgrates.org:call:1001>: fallback subjects <*out:cgrates.org:call:*any> not supported",
+		"RatingPlan <RP_1>: Timing <HALFHOUR>: time <08:30:00> not on the hour not supported",
+		"Destination <DST_DE>: prefixes of different lengths weighted as the shortest one",
+		"RatingPlan <RP_1>: missing Destination <DST_MISSING>",
+		"RatingProfile <*out:cgrates.org:call:1001>: missing RatingPlan <RP_MISSING>",
+		"RatingPlan <RP_1>: Timing <HALFHOUR>: time <08:30:00> not on the hour not supported",
+		"RatingPlan <RP_1>: missing Destination <DST_MISSING>",
+	}
+	if !reflect.DeepEqual(expReport, conv.report) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expReport), utils.ToJSON(conv.report))
+	}
+
+	if err := m.migrateRatingToRateProfiles(); err != nil {
+		t.Fatal(err)
+	}
+	if m.stats[utils.RateProfiles] != 2 {
+		t.Errorf("Expected 2 RateProfiles migrated, received: %d", m.stats[utils.RateProfiles])
+	}
+	iRts := []*engine.IntervalRate{{
+		FixedFee:     utils.NewDecimalFromFloat64(0.1),
+		RecurrentFee: utils.NewDecimalFromFloat64(0.2),
+		Unit:         utils.NewDecimal(int64(time.Minute), 0),
+		Increment:    utils.NewDecimal(int64(time.Second), 0),
+	}}
+	expected := &engine.RateProfile{
+		Tenant:    "cgrates.org",
+		ID:        "call_1001_RP_1_20200101T000000",
+		FilterIDs: []string{"*string:~*req.Category:call", "*string:~*req.Subject:1001"},
+		ActivationInterval: &utils.ActivationInterval{
+			ActivationTime: aTime,
+			ExpiryTime:     aTime.AddDate(1, 0, 0),
+		},
+		Weight:  subjectRateProfileWeight,
+		MaxCost: utils.NewDecimalFromFloat64(10),
+		Rates: map[string]*engine.Rate{
+			"DST_1002_PEAK_RT_1_4": {
+				ID:              "DST_1002_PEAK_RT_1_4",
+				FilterIDs:       []string{"*prefix:~*req.Destination:1002"},
+				ActivationTimes: "* 8-23 * * 1",
+				Weight:          4010,
+				IntervalRates:   iRts,
+			},
+			"DST_1002_PEAK_RT_1_7": {
+				ID:              "DST_1002_PEAK_RT_1_7",
+				FilterIDs:       []string{"*prefix:~*req.Destination:+491002"},
+				ActivationTimes: "* 8-23 * * 1",
+				Weight:          7010,
+				IntervalRates:   iRts,
+			},
+			"DST_DE_PEAK_RT_1": {
+				ID:              "DST_DE_PEAK_RT_1",
+				FilterIDs:       []string{"*destinations:~*req.Destination:DST_DE"},
+				ActivationTimes: "* 8-23 * * 1",
+				Weight:          3000,
+				IntervalRates:   iRts,
+			},
+		},
+	}
+	if rcv, err := dm.GetRateProfile("cgrates.org", "call_1001_RP_1_20200101T000000", true, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if utils.ToJSON(expected) != utils.ToJSON(rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expected), utils.ToJSON(rcv))
+	}
+	// the second activation of the same RatingPlan is not overwriting the first one
+	expected.ID = "call_1001_RP_1_20220101T000000"
+	expected.ActivationInterval = &utils.ActivationInterval{ActivationTime: aTime.AddDate(2, 0, 0)}
+	if rcv, err := dm.GetRateProfile("cgrates.org", "call_1001_RP_1_20220101T000000", true, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if utils.ToJSON(expected) != utils.ToJSON(rcv) {
+		t.Errorf("Expected %s, received %s", utils.ToJSON(expected), utils.ToJSON(rcv))
+	}
+}
@@ -112,6 +112,10 @@ func (apiService *APIerSv1Service) Start() (err error) {
 	go apiService.api.ListenAndServe(apiService.stopChan)
 	runtime.Gosched()
 
+	if apiService.cfg.ApierCfg().Audit {
+		apiService.server.SetAuditS(engine.NewAuditS(apiService.cfg, datadb, stordb, apiService.connMgr))
+	}
+
 	if !apiService.cfg.DispatcherSCfg().Enabled {
 		apiService.server.RpcRegister(apiService.api)
 		apiService.server.RpcRegisterName(utils.ApierV1, apiService.api)
@@ -134,6 +138,7 @@ func (apiService *APIerSv1Service) Reload() (err error) {
 func (apiService *APIerSv1Service) Shutdown() (err error) {
 	apiService.Lock()
 	close(apiService.stopChan)
+	apiService.server.SetAuditS(nil)
 	apiService.api = nil
 	<-apiService.connChan
 	apiService.Unlock()
@@ -32,13 +32,14 @@ import (
 
 // NewCoreService returns the Core Service
 func NewCoreService(cfg *config.CGRConfig, caps *engine.Caps, server *cores.Server,
-	internalCoreSChan chan rpcclient.ClientConnector, anz *AnalyzerService,
-	srvDep map[string]*sync.WaitGroup) *CoreService {
+	internalCoreSChan chan rpcclient.ClientConnector, connMgr *engine.ConnManager,
+	anz *AnalyzerService, srvDep map[string]*sync.WaitGroup) *CoreService {
 	return &CoreService{
 		connChan: internalCoreSChan,
 		cfg:      cfg,
 		caps:     caps,
 		server:   server,
+		connMgr:  connMgr,
 		anz:      anz,
 		srvDep:   srvDep,
 	}
@@ -50,6 +51,7 @@ type CoreService struct {
 	cfg      *config.CGRConfig
 	server   *cores.Server
 	caps     *engine.Caps
+	connMgr  *engine.ConnManager
 	stopChan chan struct{}
 
 	cS       *cores.CoreService
@@ -69,11 +71,14 @@ func (cS *CoreService) Start() (err error) {
 	defer cS.Unlock()
 	utils.Logger.Info(fmt.Sprintf("<%s> starting <%s> subsystem", utils.CoreS, utils.CoreS))
 	cS.stopChan = make(chan struct{})
-	cS.cS = cores.NewCoreService(cS.cfg, cS.caps, cS.stopChan)
+	cS.cS = cores.NewCoreService(cS.cfg, cS.caps, cS.connMgr, cS.stopChan)
 	cS.rpc = v1.NewCoreSv1(cS.cS)
 	if !cS.cfg.DispatcherSCfg().Enabled {
 		cS.server.RpcRegister(cS.rpc)
 	}
+	if cS.cfg.HTTPCfg().HTTPMetricsURL != utils.EmptyString {
+		cS.server.RegisterHttpAuthFunc(cS.cfg.HTTPCfg().HTTPMetricsURL, utils.CoreSv1Metrics, cS.cS.MetricsHandler)
+	}
 	cS.connChan <- cS.anz.GetInternalCodec(cS.rpc, utils.CoreS)
 	return
 }
@@ -133,7 +133,8 @@ func (db *DataDBService) ServiceName() string {
 
 // ShouldRun returns if the service should be running
 func (db *DataDBService) ShouldRun() bool {
-	return db.mandatoryDB() || db.cfg.SessionSCfg().Enabled
+	return db.mandatoryDB() || db.cfg.SessionSCfg().Enabled ||
+		(db.cfg.ERsCfg().Enabled && db.cfg.ERsCfg().DedupStore == utils.MetaDataDB)
 }
 
 // mandatoryDB returns if the current configuration needs the DB
@@ -142,7 +143,8 @@ func (db *DataDBService) mandatoryDB() bool {
 		db.cfg.AttributeSCfg().Enabled || db.cfg.ResourceSCfg().Enabled || db.cfg.StatSCfg().Enabled ||
 		db.cfg.ThresholdSCfg().Enabled || db.cfg.RouteSCfg().Enabled || db.cfg.DispatcherSCfg().Enabled ||
 		db.cfg.LoaderCfg().Enabled() || db.cfg.ApierCfg().Enabled || db.cfg.RateSCfg().Enabled ||
-		db.cfg.AccountSCfg().Enabled || db.cfg.ActionSCfg().Enabled || db.cfg.AnalyzerSCfg().Enabled
+		db.cfg.AccountSCfg().Enabled || db.cfg.ActionSCfg().Enabled || db.cfg.AnalyzerSCfg().Enabled ||
+		db.cfg.InvoiceSCfg().Enabled || db.cfg.ConfigSCfg().ConfigDB
 }
 
 // GetDM returns the DataManager
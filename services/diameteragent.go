@@ -32,12 +32,13 @@ import (
 // NewDiameterAgent returns the Diameter Agent
 func NewDiameterAgent(cfg *config.CGRConfig, filterSChan chan *engine.FilterS,
 	shdChan *utils.SyncedChan, connMgr *engine.ConnManager,
-	srvDep map[string]*sync.WaitGroup) servmanager.Service {
+	anz *AnalyzerService, srvDep map[string]*sync.WaitGroup) servmanager.Service {
 	return &DiameterAgent{
 		cfg:         cfg,
 		filterSChan: filterSChan,
 		shdChan:     shdChan,
 		connMgr:     connMgr,
+		anz:         anz,
 		srvDep:      srvDep,
 	}
 }
@@ -52,6 +53,7 @@ type DiameterAgent struct {
 
 	da      *agents.DiameterAgent
 	connMgr *engine.ConnManager
+	anz     *AnalyzerService
 
 	lnet  string
 	laddr string
@@ -71,7 +73,7 @@ func (da *DiameterAgent) Start() (err error) {
 	da.Lock()
 	defer da.Unlock()
 
-	da.da, err = agents.NewDiameterAgent(da.cfg, filterS, da.connMgr)
+	da.da, err = agents.NewDiameterAgent(da.cfg, filterS, da.connMgr, da.anz.GetAnalyzerS())
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("<%s> error: %s!",
 			utils.DiameterAgent, err))
@@ -158,6 +158,9 @@ func (dspS *DispatcherService) Start() (err error) {
 	dspS.server.RpcRegisterName(utils.AccountSv1,
 		v1.NewDispatcherAccountSv1(dspS.dspS))
 
+	dspS.server.RpcRegisterName(utils.InvoiceSv1,
+		v1.NewDispatcherInvoiceSv1(dspS.dspS))
+
 	dspS.connChan <- dspS.anz.GetInternalCodec(dspS.dspS, utils.DispatcherS)
 
 	return
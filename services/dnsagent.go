@@ -32,12 +32,13 @@ import (
 // NewDNSAgent returns the DNS Agent
 func NewDNSAgent(cfg *config.CGRConfig, filterSChan chan *engine.FilterS,
 	shdChan *utils.SyncedChan, connMgr *engine.ConnManager,
-	srvDep map[string]*sync.WaitGroup) servmanager.Service {
+	anz *AnalyzerService, srvDep map[string]*sync.WaitGroup) servmanager.Service {
 	return &DNSAgent{
 		cfg:         cfg,
 		filterSChan: filterSChan,
 		shdChan:     shdChan,
 		connMgr:     connMgr,
+		anz:         anz,
 		srvDep:      srvDep,
 	}
 }
@@ -51,6 +52,7 @@ type DNSAgent struct {
 
 	dns     *agents.DNSAgent
 	connMgr *engine.ConnManager
+	anz     *AnalyzerService
 	srvDep  map[string]*sync.WaitGroup
 
 	oldListen string
@@ -68,7 +70,7 @@ func (dns *DNSAgent) Start() (err error) {
 	dns.Lock()
 	defer dns.Unlock()
 	dns.oldListen = dns.cfg.DNSAgentCfg().Listen
-	dns.dns, err = agents.NewDNSAgent(dns.cfg, filterS, dns.connMgr)
+	dns.dns, err = agents.NewDNSAgent(dns.cfg, filterS, dns.connMgr, dns.anz.GetAnalyzerS())
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("<%s> error: <%s>", utils.DNSAgent, err.Error()))
 		return
@@ -30,12 +30,14 @@ import (
 )
 
 // NewEventReaderService returns the EventReader Service
-func NewEventReaderService(cfg *config.CGRConfig, filterSChan chan *engine.FilterS,
+func NewEventReaderService(cfg *config.CGRConfig, dm *DataDBService,
+	filterSChan chan *engine.FilterS,
 	shdChan *utils.SyncedChan, connMgr *engine.ConnManager,
 	srvDep map[string]*sync.WaitGroup) servmanager.Service {
 	return &EventReaderService{
 		rldChan:     make(chan struct{}, 1),
 		cfg:         cfg,
+		dm:          dm,
 		filterSChan: filterSChan,
 		shdChan:     shdChan,
 		connMgr:     connMgr,
@@ -47,9 +49,11 @@ func NewEventReaderService(cfg *config.CGRConfig, filterSChan chan *engine.Filte
 type EventReaderService struct {
 	sync.RWMutex
 	cfg         *config.CGRConfig
+	dm          *DataDBService
 	filterSChan chan *engine.FilterS
 	shdChan     *utils.SyncedChan
 
+	usesDM   bool // the dedup IDs are kept in DataDB
 	ers      *ers.ERService
 	rldChan  chan struct{}
 	stopChan chan struct{}
@@ -68,6 +72,13 @@ func (erS *EventReaderService) Start() (err error) {
 
 	filterS := <-erS.filterSChan
 	erS.filterSChan <- filterS
+	var datadb *engine.DataManager
+	if erS.usesDM = erS.cfg.ERsCfg().DedupStore == utils.MetaDataDB; erS.usesDM {
+		erS.srvDep[utils.DataDB].Add(1)
+		dbchan := erS.dm.GetDMChan()
+		datadb = <-dbchan
+		dbchan <- datadb
+	}
 
 	// remake the stop chan
 	erS.stopChan = make(chan struct{})
@@ -75,7 +86,7 @@ func (erS *EventReaderService) Start() (err error) {
 	utils.Logger.Info(fmt.Sprintf("<%s> starting <%s> subsystem", utils.CoreS, utils.ERs))
 
 	// build the service
-	erS.ers = ers.NewERService(erS.cfg, filterS, erS.connMgr)
+	erS.ers = ers.NewERService(erS.cfg, datadb, filterS, erS.connMgr)
 	go func(ers *ers.ERService, stopChan, rldChan chan struct{}) {
 		if err := ers.ListenAndServe(stopChan, rldChan); err != nil {
 			utils.Logger.Err(fmt.Sprintf("<%s> error: <%s>", utils.ERs, err.Error()))
@@ -98,6 +109,9 @@ func (erS *EventReaderService) Shutdown() (err error) {
 	erS.Lock()
 	close(erS.stopChan)
 	erS.ers = nil
+	if erS.usesDM {
+		erS.srvDep[utils.DataDB].Done()
+	}
 	erS.Unlock()
 	return
 }
@@ -37,7 +37,7 @@ func TestEventReaderSCoverage(t *testing.T) {
 	filterSChan <- nil
 	shdChan := utils.NewSyncedChan()
 	srvDep := map[string]*sync.WaitGroup{utils.DataDB: new(sync.WaitGroup)}
-	srv := NewEventReaderService(cfg, filterSChan, shdChan, nil, srvDep)
+	srv := NewEventReaderService(cfg, nil, filterSChan, shdChan, nil, srvDep)
 
 	if srv.IsRunning() {
 		t.Errorf("Expected service to be down")
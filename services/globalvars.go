@@ -24,10 +24,12 @@ import (
 	"sync"
 
 	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/guardian"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/servmanager"
 	"github.com/cgrates/cgrates/utils"
+	"github.com/mediocregopher/radix/v3"
 )
 
 // NewGlobalVarS .
@@ -49,6 +51,9 @@ type GlobalVarS struct {
 func (gv *GlobalVarS) Start() (err error) {
 	engine.SetRoundingDecimals(gv.cfg.GeneralCfg().RoundingDecimals)
 	engine.SetFailedPostCacheTTL(gv.cfg.GeneralCfg().FailedPostsTTL)
+	if err = gv.initDistributedLocker(); err != nil {
+		return
+	}
 	return gv.initHTTPTransport()
 }
 
@@ -86,3 +91,23 @@ func (gv *GlobalVarS) initHTTPTransport() (err error) {
 	engine.SetHTTPPstrTransport(trsp)
 	return
 }
+
+// initDistributedLocker shares the guardian locks over the Redis DataDB when requested
+func (gv *GlobalVarS) initDistributedLocker() (err error) {
+	if gv.cfg.GeneralCfg().LockingBackend != utils.MetaRedis {
+		return
+	}
+	dbCfg := gv.cfg.DataDbCfg()
+	var client radix.Client
+	if client, err = engine.NewRedisDataDBClient(dbCfg.DataDbHost, dbCfg.DataDbPort, dbCfg.DataDbName,
+		dbCfg.DataDbUser, dbCfg.DataDbPass, dbCfg.Opts); err != nil {
+		utils.Logger.Crit(fmt.Sprintf("Could not connect to the %s locking backend: %s exiting!", utils.MetaRedis, err))
+		return
+	}
+	acquireTimeout := gv.cfg.GeneralCfg().LockingTimeout
+	if acquireTimeout == 0 { // do not wait forever for the locks held by other engines
+		acquireTimeout = gv.cfg.GeneralCfg().LockingLease
+	}
+	guardian.Guardian.SetDistributedLocker(guardian.NewRedisLocker(client, gv.cfg.GeneralCfg().LockingLease, acquireTimeout))
+	return
+}
@@ -0,0 +1,140 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	v1 "github.com/cgrates/cgrates/apier/v1"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/cores"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/invoices"
+	"github.com/cgrates/cgrates/servmanager"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+// NewInvoiceService returns the Invoice Service
+func NewInvoiceService(cfg *config.CGRConfig, dm *DataDBService,
+	storDB *StorDBService, cacheS *engine.CacheS,
+	connMgr *engine.ConnManager,
+	server *cores.Server, internalChan chan rpcclient.ClientConnector,
+	anz *AnalyzerService, srvDep map[string]*sync.WaitGroup) servmanager.Service {
+	return &InvoiceService{
+		connChan: internalChan,
+		connMgr:  connMgr,
+		cfg:      cfg,
+		dm:       dm,
+		storDB:   storDB,
+		cacheS:   cacheS,
+		server:   server,
+		anz:      anz,
+		srvDep:   srvDep,
+		rldChan:  make(chan struct{}, 1),
+	}
+}
+
+// InvoiceService implements Service interface
+type InvoiceService struct {
+	sync.RWMutex
+	cfg     *config.CGRConfig
+	dm      *DataDBService
+	storDB  *StorDBService
+	cacheS  *engine.CacheS
+	connMgr *engine.ConnManager
+	server  *cores.Server
+
+	rldChan  chan struct{}
+	stopChan chan struct{}
+
+	invS     *invoices.InvoiceS
+	rpc      *v1.InvoiceSv1                 // useful on restart
+	connChan chan rpcclient.ClientConnector // publish the internal Subsystem when available
+	anz      *AnalyzerService
+	srvDep   map[string]*sync.WaitGroup
+}
+
+// Start should handle the service start
+func (invS *InvoiceService) Start() (err error) {
+	if invS.IsRunning() {
+		return utils.ErrServiceAlreadyRunning
+	}
+
+	<-invS.cacheS.GetPrecacheChannel(utils.CacheActionProfiles)
+
+	dbchan := invS.dm.GetDMChan()
+	datadb := <-dbchan
+	dbchan <- datadb
+
+	storDBChan := make(chan engine.StorDB, 1)
+	invS.storDB.RegisterSyncChan(storDBChan)
+
+	invS.Lock()
+	defer invS.Unlock()
+	invS.invS = invoices.NewInvoiceS(invS.cfg, datadb, storDBChan, invS.connMgr)
+	invS.stopChan = make(chan struct{})
+	go invS.invS.ListenAndServe(invS.stopChan, invS.rldChan)
+
+	utils.Logger.Info(fmt.Sprintf("<%s> starting <%s> subsystem", utils.CoreS, utils.InvoiceS))
+	invS.rpc = v1.NewInvoiceSv1(invS.invS)
+	if !invS.cfg.DispatcherSCfg().Enabled {
+		invS.server.RpcRegister(invS.rpc)
+	}
+	invS.connChan <- invS.anz.GetInternalCodec(invS.rpc, utils.InvoiceS)
+	return
+}
+
+// Reload handles the change of config
+func (invS *InvoiceService) Reload() (err error) {
+	invS.rldChan <- struct{}{}
+	return // for the moment nothing to reload
+}
+
+// Shutdown stops the service
+func (invS *InvoiceService) Shutdown() (err error) {
+	invS.Lock()
+	defer invS.Unlock()
+	close(invS.stopChan)
+	if err = invS.invS.Shutdown(); err != nil {
+		return
+	}
+	invS.invS = nil
+	invS.rpc = nil
+	<-invS.connChan
+	return
+}
+
+// IsRunning returns if the service is running
+func (invS *InvoiceService) IsRunning() bool {
+	invS.RLock()
+	defer invS.RUnlock()
+	return invS != nil && invS.invS != nil
+}
+
+// ServiceName returns the service name
+func (invS *InvoiceService) ServiceName() string {
+	return utils.InvoiceS
+}
+
+// ShouldRun returns if the service should be running
+func (invS *InvoiceService) ShouldRun() bool {
+	return invS.cfg.InvoiceSCfg().Enabled
+}
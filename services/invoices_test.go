@@ -0,0 +1,66 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package services
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/cores"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/invoices"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+// TestInvoiceSCoverage for cover testing
+func TestInvoiceSCoverage(t *testing.T) {
+	cfg := config.NewDefaultCGRConfig()
+	shdChan := utils.NewSyncedChan()
+	chS := engine.NewCacheS(cfg, nil, nil)
+	filterSChan := make(chan *engine.FilterS, 1)
+	filterSChan <- nil
+	server := cores.NewServer(nil)
+	srvDep := map[string]*sync.WaitGroup{utils.DataDB: new(sync.WaitGroup)}
+	db := NewDataDBService(cfg, nil, srvDep)
+	stordb := NewStorDBService(cfg, srvDep)
+	invRPC := make(chan rpcclient.ClientConnector, 1)
+	anz := NewAnalyzerService(cfg, server, filterSChan, shdChan, make(chan rpcclient.ClientConnector, 1), srvDep)
+	srv := NewInvoiceService(cfg, db, stordb, chS, nil, server, invRPC, anz, srvDep)
+	invS, canCast := srv.(*InvoiceService)
+	if !canCast {
+		t.Fatalf("Expected *InvoiceService, received: %T", srv)
+	}
+	if invS.IsRunning() {
+		t.Errorf("Expected service to be down")
+	}
+	storDBChan := make(chan engine.StorDB, 1)
+	storDBChan <- engine.NewInternalDB(nil, nil, false)
+	invS.invS = invoices.NewInvoiceS(cfg, nil, storDBChan, nil)
+	if !invS.IsRunning() {
+		t.Errorf("Expected service to be running")
+	}
+	if rcv := invS.ServiceName(); rcv != utils.InvoiceS {
+		t.Errorf("Expected %q, received: %q", utils.InvoiceS, rcv)
+	}
+	if invS.ShouldRun() {
+		t.Errorf("Expected service to not run")
+	}
+}
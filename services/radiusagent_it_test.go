@@ -55,7 +55,7 @@ func TestRadiusAgentReload(t *testing.T) {
 	anz := NewAnalyzerService(cfg, server, filterSChan, shdChan, make(chan rpcclient.ClientConnector, 1), srvDep)
 	sS := NewSessionService(cfg, db, server, make(chan rpcclient.ClientConnector, 1),
 		shdChan, nil, nil, anz, srvDep)
-	srv := NewRadiusAgent(cfg, filterSChan, shdChan, nil, srvDep)
+	srv := NewRadiusAgent(cfg, filterSChan, shdChan, nil, anz, srvDep)
 	engine.NewConnManager(cfg, nil)
 	srvMngr.AddServices(srv, sS,
 		NewLoaderService(cfg, db, filterSChan, server, make(chan rpcclient.ClientConnector, 1), nil, anz, srvDep), db)
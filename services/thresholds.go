@@ -35,7 +35,8 @@ import (
 func NewThresholdService(cfg *config.CGRConfig, dm *DataDBService,
 	cacheS *engine.CacheS, filterSChan chan *engine.FilterS,
 	server *cores.Server, internalThresholdSChan chan rpcclient.ClientConnector,
-	anz *AnalyzerService, srvDep map[string]*sync.WaitGroup) servmanager.Service {
+	connMgr *engine.ConnManager, anz *AnalyzerService,
+	srvDep map[string]*sync.WaitGroup) servmanager.Service {
 	return &ThresholdService{
 		connChan:    internalThresholdSChan,
 		cfg:         cfg,
@@ -43,6 +44,7 @@ func NewThresholdService(cfg *config.CGRConfig, dm *DataDBService,
 		cacheS:      cacheS,
 		filterSChan: filterSChan,
 		server:      server,
+		connMgr:     connMgr,
 		anz:         anz,
 		srvDep:      srvDep,
 	}
@@ -56,6 +58,7 @@ type ThresholdService struct {
 	cacheS      *engine.CacheS
 	filterSChan chan *engine.FilterS
 	server      *cores.Server
+	connMgr     *engine.ConnManager
 
 	thrs     *engine.ThresholdService
 	rpc      *v1.ThresholdSv1
@@ -83,7 +86,7 @@ func (thrs *ThresholdService) Start() (err error) {
 
 	thrs.Lock()
 	defer thrs.Unlock()
-	thrs.thrs, err = engine.NewThresholdService(datadb, thrs.cfg, filterS)
+	thrs.thrs, err = engine.NewThresholdService(datadb, thrs.cfg, filterS, thrs.connMgr)
 	if err != nil {
 		utils.Logger.Crit(fmt.Sprintf("<%s> Could not init, error: %s", utils.ThresholdS, err.Error()))
 		return
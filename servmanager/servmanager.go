@@ -251,6 +251,8 @@ func (srvMngr *ServiceManager) handleReload() {
 			go srvMngr.reloadService(utils.AccountS)
 		case <-srvMngr.GetConfig().GetReloadChan(config.ActionSJson):
 			go srvMngr.reloadService(utils.ActionS)
+		case <-srvMngr.GetConfig().GetReloadChan(config.InvoiceSJson):
+			go srvMngr.reloadService(utils.InvoiceS)
 		}
 		// handle RPC server
 	}
@@ -19,7 +19,6 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package sessions
 
 import (
-	"runtime"
 	"sync"
 	"time"
 
@@ -79,7 +78,7 @@ type Session struct {
 	SRuns         []*SRun         // forked based on ChargerS
 	OptsStart     engine.MapEvent
 
-	debitStop   chan struct{}
+	debitLoop   *debitLoop   // automatic debits for the *prepaid runs
 	sTerminator *sTerminator // automatic timeout for the session
 }
 
@@ -236,21 +235,24 @@ func (s *Session) asCGREvents() (cgrEvs []*utils.CGREvent, err error) {
 
 // stopSTerminator clears the session terminator
 func (s *Session) stopSTerminator() {
-	if s.sTerminator == nil ||
-		s.sTerminator.endChan == nil {
+	if s.sTerminator == nil {
 		return
 	}
-	close(s.sTerminator.endChan)
-	s.sTerminator.endChan = nil
+	if s.sTerminator.timer != nil {
+		s.sTerminator.timer.stop()
+	}
+	s.sTerminator = nil
 }
 
 // stopDebitLoops will stop all the active debits on the session
 func (s *Session) stopDebitLoops() {
-	if s.debitStop != nil {
-		close(s.debitStop) // Stop automatic debits
-		runtime.Gosched()
-		s.debitStop = nil
+	if s.debitLoop == nil {
+		return
+	}
+	for _, wt := range s.debitLoop.timers {
+		wt.stop()
 	}
+	s.debitLoop = nil
 }
 
 // SRun is one billing run for the Session
@@ -687,22 +687,30 @@ func TestSessionstopSTerminator(t *testing.T) {
 		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eOut), utils.ToJSON(rcv))
 	}
 	//normal check
+	timer := sWheel.schedule(time.Hour, func() {})
 	session = &Session{
-		sTerminator: &sTerminator{endChan: make(chan struct{})},
+		sTerminator: &sTerminator{timer: timer},
 	}
 	session.stopSTerminator()
-	if session.sTerminator.endChan != nil {
-		t.Errorf("Expecting: nil, received: %s", utils.ToJSON(session.sTerminator.endChan))
+	if session.sTerminator != nil {
+		t.Errorf("Expecting: nil, received: %+v", session.sTerminator)
+	}
+	if timer.stop() {
+		t.Error("Expected the timer stopped")
 	}
 }
 
 func TestSessionstopDebitLoops(t *testing.T) {
+	timer := sWheel.schedule(time.Hour, func() {})
 	session := &Session{
-		debitStop: make(chan struct{}),
+		debitLoop: &debitLoop{timers: map[int]*wheelTimer{0: timer}},
 	}
 	session.stopDebitLoops()
-	if session.debitStop != nil {
-		t.Errorf("Expecting: nil, received: %s", utils.ToJSON(session.debitStop))
+	if session.debitLoop != nil {
+		t.Errorf("Expecting: nil, received: %+v", session.debitLoop)
+	}
+	if timer.stop() {
+		t.Error("Expected the timer stopped")
 	}
 }
 
@@ -23,7 +23,6 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
-	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -97,6 +96,9 @@ type SessionS struct {
 // ListenAndServe starts the service and binds it to the listen loop
 func (sS *SessionS) ListenAndServe(stopChan chan struct{}) {
 	utils.Logger.Info(fmt.Sprintf("<%s> starting <%s> subsystem", utils.CoreS, utils.SessionS))
+	if sS.cgrCfg.SessionSCfg().SharedRegistry {
+		go sS.runSharedRegistry(stopChan)
+	}
 	if sS.cgrCfg.SessionSCfg().ChannelSyncInterval != 0 {
 		for { // Schedule sync channels to run repeately
 			select {
@@ -111,7 +113,12 @@ func (sS *SessionS) ListenAndServe(stopChan chan struct{}) {
 }
 
 // Shutdown is called by engine to clear states
+// with the shared registry the sessions are handed over to the other nodes instead of terminated
 func (sS *SessionS) Shutdown() (err error) {
+	if sS.cgrCfg.SessionSCfg().SharedRegistry {
+		sS.releaseSharedSessions()
+		return
+	}
 	for _, s := range sS.getSessions("", false) { // Force sessions shutdown
 		sS.terminateSession(s, nil, nil, nil, false)
 	}
@@ -192,8 +199,7 @@ type riFieldNameVal struct {
 
 // sTerminator holds the info needed to force-terminate sessions based on timer
 type sTerminator struct {
-	timer        *time.Timer
-	endChan      chan struct{}
+	timer        *wheelTimer
 	ttl          time.Duration
 	ttlLastUsed  *time.Duration
 	ttlUsage     *time.Duration
@@ -299,36 +305,35 @@ func (sS *SessionS) setSTerminator(s *Session, opts engine.MapEvent) {
 		if ttlLastUsage != nil {
 			s.sTerminator.ttlLastUsage = ttlLastUsage
 		}
-		s.sTerminator.timer.Reset(s.sTerminator.ttl)
-		return
-	}
-	// new set
-	s.sTerminator = &sTerminator{
-		timer:        time.NewTimer(ttl),
-		endChan:      make(chan struct{}),
-		ttl:          ttl,
-		ttlLastUsed:  ttlLastUsed,
-		ttlUsage:     ttlUsage,
-		ttlLastUsage: ttlLastUsage,
+		if s.sTerminator.timer.reset(s.sTerminator.ttl) {
+			return
+		}
+		// expired while we were holding the lock, the pending termination is replaced by a new timer
+	} else {
+		s.sTerminator = &sTerminator{
+			ttl:          ttl,
+			ttlLastUsed:  ttlLastUsed,
+			ttlUsage:     ttlUsage,
+			ttlLastUsage: ttlLastUsage,
+		}
 	}
-
 	// schedule automatic termination
-	go func(endChan chan struct{}, timer *time.Timer) {
-		select {
-		case <-timer.C:
-			s.Lock()
-			lastUsage := s.sTerminator.ttl
-			if s.sTerminator.ttlLastUsage != nil {
-				lastUsage = *s.sTerminator.ttlLastUsage
-			}
-			sS.forceSTerminate(s, lastUsage, s.sTerminator.ttlUsage,
-				s.sTerminator.ttlLastUsed)
+	var timer *wheelTimer
+	timer = sWheel.schedule(ttl, func() {
+		s.Lock()
+		if s.sTerminator == nil || s.sTerminator.timer != timer { // stopped or replaced in the meantime
 			s.Unlock()
-		case <-endChan:
-			timer.Stop()
+			return
+		}
+		lastUsage := s.sTerminator.ttl
+		if s.sTerminator.ttlLastUsage != nil {
+			lastUsage = *s.sTerminator.ttlLastUsage
 		}
-	}(s.sTerminator.endChan, s.sTerminator.timer)
-	runtime.Gosched() // force context switching
+		sS.forceSTerminate(s, lastUsage, s.sTerminator.ttlUsage,
+			s.sTerminator.ttlLastUsed)
+		s.Unlock()
+	})
+	s.sTerminator.timer = timer
 }
 
 // forceSTerminate is called when a session times-out or it is forced from CGRateS side
@@ -505,90 +510,99 @@ func (sS *SessionS) debitSession(s *Session, sRunIdx int, dur time.Duration,
 	return
 }
 
+// debitLoop holds the automatic debits of a session, one timer on the wheel for each *prepaid SRun
+type debitLoop struct {
+	timers map[int]*wheelTimer
+}
+
+// scheduleDebit schedules the next automatic debit of the SRun
+// not thread-safe, it should be protected in another layer
+func (sS *SessionS) scheduleDebit(s *Session, sRunIdx int, dbtIvl, delay time.Duration) {
+	dl := s.debitLoop
+	dl.timers[sRunIdx] = sWheel.schedule(delay, func() {
+		sS.debitLoopSession(s, dl, sRunIdx, dbtIvl)
+	})
+}
+
 // debitLoopSession will periodically debit sessions, ie: automatic prepaid
-// threadSafe since it will run into it's own goroutine
-func (sS *SessionS) debitLoopSession(s *Session, sRunIdx int,
-	dbtIvl time.Duration) (maxDur time.Duration, err error) {
-	// NextAutoDebit works in tandem with session replication
-	now := time.Now()
-	if s.SRuns[sRunIdx].NextAutoDebit != nil &&
-		now.Before(*s.SRuns[sRunIdx].NextAutoDebit) {
-		time.Sleep(now.Sub(*s.SRuns[sRunIdx].NextAutoDebit))
-	}
-	for {
-		s.Lock()
-		if s.debitStop == nil {
-			// session already closed (most probably from sessionEnd), fixes concurrency
-			s.Unlock()
-			return
+// executes one debit and schedules the next one or the disconnect in case of low balance
+// threadSafe since it will be executed by the timer wheel
+func (sS *SessionS) debitLoopSession(s *Session, dl *debitLoop, sRunIdx int,
+	dbtIvl time.Duration) {
+	s.Lock()
+	if s.debitLoop != dl {
+		// session already closed (most probably from sessionEnd), fixes concurrency
+		s.Unlock()
+		return
+	}
+	maxDebit, err := sS.debitSession(s, sRunIdx, dbtIvl, nil)
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> could not complete debit operation on session: <%s>, error: <%s>",
+				utils.SessionS, s.cgrID(), err.Error()))
+		dscReason := utils.ErrServerError.Error()
+		if err.Error() == utils.ErrUnauthorizedDestination.Error() {
+			dscReason = err.Error()
 		}
-		var maxDebit time.Duration
-		if maxDebit, err = sS.debitSession(s, sRunIdx, dbtIvl, nil); err != nil {
+		// try to disconect the session n times before we force terminate it on our side
+		for i := 0; i < sS.cgrCfg.SessionSCfg().TerminateAttempts; i++ {
+			if err = sS.disconnectSession(s, dscReason); err == nil {
+				s.Unlock()
+				return
+			}
 			utils.Logger.Warning(
-				fmt.Sprintf("<%s> could not complete debit operation on session: <%s>, error: <%s>",
+				fmt.Sprintf("<%s> could not disconnect session: %s, error: %s",
 					utils.SessionS, s.cgrID(), err.Error()))
-			dscReason := utils.ErrServerError.Error()
-			if err.Error() == utils.ErrUnauthorizedDestination.Error() {
-				dscReason = err.Error()
-			}
-			// try to disconect the session n times before we force terminate it on our side
-			for i := 0; i < sS.cgrCfg.SessionSCfg().TerminateAttempts; i++ {
-				if err = sS.disconnectSession(s, dscReason); err == nil {
-					s.Unlock()
-					return
-				}
-				utils.Logger.Warning(
-					fmt.Sprintf("<%s> could not disconnect session: %s, error: %s",
-						utils.SessionS, s.cgrID(), err.Error()))
-			}
-			if err = sS.forceSTerminate(s, 0, nil, nil); err != nil {
-				utils.Logger.Warning(fmt.Sprintf("<%s> failed force-terminating session: <%s>, err: <%s>", utils.SessionS, s.cgrID(), err))
-			}
-			s.Unlock()
-			return
 		}
-		debitStop := s.debitStop // avoid concurrency with endSession
-		s.SRuns[sRunIdx].NextAutoDebit = utils.TimePointer(time.Now().Add(dbtIvl))
-		if maxDebit < dbtIvl && sS.cgrCfg.SessionSCfg().MinDurLowBalance != time.Duration(0) { // warn client for low balance
-			if sS.cgrCfg.SessionSCfg().MinDurLowBalance >= dbtIvl {
-				utils.Logger.Warning(fmt.Sprintf("<%s> can not run warning for the session: <%s> since the remaining time:<%s> is higher than the debit interval:<%s>.",
-					utils.SessionS, s.cgrID(), sS.cgrCfg.SessionSCfg().MinDurLowBalance, dbtIvl))
-			} else if maxDebit <= sS.cgrCfg.SessionSCfg().MinDurLowBalance {
-				go sS.warnSession(s.ClientConnID, s.EventStart.Clone())
-			}
+		if err = sS.forceSTerminate(s, 0, nil, nil); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> failed force-terminating session: <%s>, err: <%s>", utils.SessionS, s.cgrID(), err))
 		}
 		s.Unlock()
-		sS.replicateSessions(s.CGRID, false, sS.cgrCfg.SessionSCfg().ReplicationConns)
-		if maxDebit < dbtIvl { // disconnect faster
-			select {
-			case <-debitStop: // call was disconnected already
-				return
-			case <-time.After(maxDebit):
-				s.Lock()
-				defer s.Unlock()
-				// try to disconect the session n times before we force terminate it on our side
-				for i := 0; i < sS.cgrCfg.SessionSCfg().TerminateAttempts; i++ {
-					if err = sS.disconnectSession(s, utils.ErrInsufficientCredit.Error()); err == nil {
-						return
-					}
-				}
-				utils.Logger.Warning(
-					fmt.Sprintf("<%s> could not disconnect session: <%s>, error: <%s>",
-						utils.SessionS, s.cgrID(), err.Error()))
-				if err = sS.forceSTerminate(s, 0, nil, nil); err != nil {
-					utils.Logger.Warning(fmt.Sprintf("<%s> failed force-terminating session: <%s>, err: <%s>",
-						utils.SessionS, s.cgrID(), err))
-				}
-			}
-			return
+		return
+	}
+	s.SRuns[sRunIdx].NextAutoDebit = utils.TimePointer(time.Now().Add(dbtIvl))
+	if maxDebit < dbtIvl && sS.cgrCfg.SessionSCfg().MinDurLowBalance != time.Duration(0) { // warn client for low balance
+		if sS.cgrCfg.SessionSCfg().MinDurLowBalance >= dbtIvl {
+			utils.Logger.Warning(fmt.Sprintf("<%s> can not run warning for the session: <%s> since the remaining time:<%s> is higher than the debit interval:<%s>.",
+				utils.SessionS, s.cgrID(), sS.cgrCfg.SessionSCfg().MinDurLowBalance, dbtIvl))
+		} else if maxDebit <= sS.cgrCfg.SessionSCfg().MinDurLowBalance {
+			go sS.warnSession(s.ClientConnID, s.EventStart.Clone())
 		}
-		select {
-		case <-debitStop:
+	}
+	if maxDebit < dbtIvl { // disconnect faster
+		dl.timers[sRunIdx] = sWheel.schedule(maxDebit, func() {
+			sS.disconnectLowBalance(s, dl)
+		})
+	} else {
+		sS.scheduleDebit(s, sRunIdx, dbtIvl, dbtIvl)
+	}
+	s.Unlock()
+	sS.replicateSessions(s.CGRID, false, sS.cgrCfg.SessionSCfg().ReplicationConns)
+	sS.shareSession(s.CGRID)
+}
+
+// disconnectLowBalance disconnects the session once the debited usage is consumed
+// threadSafe since it will be executed by the timer wheel
+func (sS *SessionS) disconnectLowBalance(s *Session, dl *debitLoop) {
+	s.Lock()
+	defer s.Unlock()
+	if s.debitLoop != dl { // call was disconnected already
+		return
+	}
+	var err error
+	// try to disconect the session n times before we force terminate it on our side
+	for i := 0; i < sS.cgrCfg.SessionSCfg().TerminateAttempts; i++ {
+		if err = sS.disconnectSession(s, utils.ErrInsufficientCredit.Error()); err == nil {
 			return
-		case <-time.After(dbtIvl):
-			continue
 		}
 	}
+	utils.Logger.Warning(
+		fmt.Sprintf("<%s> could not disconnect session: <%s>, error: <%s>",
+			utils.SessionS, s.cgrID(), err.Error()))
+	if err = sS.forceSTerminate(s, 0, nil, nil); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> failed force-terminating session: <%s>, err: <%s>",
+			utils.SessionS, s.cgrID(), err))
+	}
 }
 
 // refundSession will refund the extra usage debitted by the end of session
@@ -1261,6 +1275,7 @@ func (sS *SessionS) transitSState(cgrID string, psv bool) (s *Session) {
 		s.stopDebitLoops()
 	}
 	s.Unlock()
+	sS.shareSession(cgrID)
 	return
 }
 
@@ -1297,6 +1312,8 @@ func (sS *SessionS) relocateSession(initOriginID, originID, originHost string) (
 	s.Unlock()
 	sS.registerSession(s, false)
 	sS.replicateSessions(initCGRID, false, sS.cgrCfg.SessionSCfg().ReplicationConns)
+	sS.shareSession(initCGRID)
+	sS.shareSession(newCGRID)
 	return
 }
 
@@ -1366,17 +1383,20 @@ func (sS *SessionS) syncSessions() {
 // initSessionDebitLoops will init the debit loops for a session
 // not thread-safe, it should be protected in another layer
 func (sS *SessionS) initSessionDebitLoops(s *Session) {
-	if s.debitStop != nil { // already initialized
+	if s.debitLoop != nil { // already initialized
 		return
 	}
 	for i, sr := range s.SRuns {
 		if s.DebitInterval != 0 &&
 			sr.Event.GetStringIgnoreErrors(utils.RequestType) == utils.MetaPrepaid {
-			if s.debitStop == nil { // init the debitStop only for the first sRun with DebitInterval and RequestType MetaPrepaid
-				s.debitStop = make(chan struct{})
+			if s.debitLoop == nil { // init the debitLoop only for the first sRun with DebitInterval and RequestType MetaPrepaid
+				s.debitLoop = &debitLoop{timers: make(map[int]*wheelTimer)}
+			}
+			var delay time.Duration // NextAutoDebit works in tandem with session replication
+			if sr.NextAutoDebit != nil {
+				delay = time.Until(*sr.NextAutoDebit)
 			}
-			go sS.debitLoopSession(s, i, s.DebitInterval)
-			runtime.Gosched() // allow the goroutine to be executed
+			sS.scheduleDebit(s, i, s.DebitInterval, delay)
 		}
 	}
 }
@@ -1431,7 +1451,9 @@ func (sS *SessionS) initSession(cgrEv *utils.CGREvent, clntConnID,
 		s.Lock() // avoid endsession before initialising
 		sS.initSessionDebitLoops(s)
 		sS.registerSession(s, false)
+		sS.publishSession(utils.MetaSessionStarted, s)
 		s.Unlock()
+		sS.shareSession(s.CGRID)
 	}
 	return
 }
@@ -1440,6 +1462,7 @@ func (sS *SessionS) initSession(cgrEv *utils.CGREvent, clntConnID,
 func (sS *SessionS) updateSession(s *Session, updtEv, opts engine.MapEvent, isMsg bool) (maxUsage map[string]time.Duration, err error) {
 	if !isMsg {
 		defer sS.replicateSessions(s.CGRID, false, sS.cgrCfg.SessionSCfg().ReplicationConns)
+		defer sS.shareSession(s.CGRID)
 		s.Lock()
 		defer s.Unlock()
 
@@ -1474,7 +1497,7 @@ func (sS *SessionS) updateSession(s *Session, updtEv, opts engine.MapEvent, isMs
 			continue
 		}
 		var rplyMaxUsage time.Duration
-		if reqType != utils.MetaPrepaid || s.debitStop != nil {
+		if reqType != utils.MetaPrepaid || s.debitLoop != nil {
 			rplyMaxUsage = reqMaxUsage
 		} else if rplyMaxUsage, err = sS.debitSession(s, i, reqMaxUsage,
 			updtEv.GetDurationPtrIgnoreErrors(utils.LastUsed)); err != nil {
@@ -1502,6 +1525,7 @@ func (sS *SessionS) endSession(s *Session, tUsage, lastUsage *time.Duration,
 	if !isMsg {
 		//check if we have replicate connection and close the session there
 		defer sS.replicateSessions(s.CGRID, true, sS.cgrCfg.SessionSCfg().ReplicationConns)
+		defer sS.shareSession(s.CGRID)
 		sS.unregisterSession(s.CGRID, false)
 		s.stopSTerminator()
 		s.stopDebitLoops()
@@ -1574,6 +1598,9 @@ func (sS *SessionS) endSession(s *Session, tUsage, lastUsage *time.Duration,
 			sr.Event[utils.AnswerTime] = *aTime
 		}
 	}
+	if !isMsg {
+		sS.publishSession(utils.MetaSessionTerminated, s)
+	}
 	if errCh := engine.Cache.Set(utils.CacheClosedSessions, s.CGRID, s,
 		nil, true, utils.NonTransactional); errCh != nil {
 		return errCh
@@ -1581,6 +1608,17 @@ func (sS *SessionS) endSession(s *Session, tUsage, lastUsage *time.Duration,
 	return
 }
 
+// publishSession sends the session to the live events stream
+// not thread safe for the Session
+func (sS *SessionS) publishSession(evType string, s *Session) {
+	if engine.EventStream == nil {
+		return
+	}
+	ev := s.EventStart.Clone()
+	ev[utils.CGRID] = s.CGRID
+	engine.EventStream.Publish(evType, s.Tenant, ev)
+}
+
 // chargeEvent will charge a single event (ie: SMS)
 func (sS *SessionS) chargeEvent(cgrEv *utils.CGREvent, forceDuration bool) (maxUsage time.Duration, err error) {
 	var s *Session
@@ -1731,6 +1769,7 @@ func (sS *SessionS) BiRPCv1SetPassiveSession(clnt rpcclient.ClientConnector,
 		aSs[0].stopSTerminator()
 		aSs[0].stopDebitLoops()
 		aSs[0].Unlock()
+		sS.shareSession(s.CGRID) // the remote node owns the session from now on
 	}
 	sS.registerSession(s, true)
 
@@ -2241,7 +2280,7 @@ func (sS *SessionS) BiRPCv1InitiateSession(clnt rpcclient.ClientConnector,
 			return err
 		}
 		s.RLock() // avoid concurrency with activeDebit
-		isPrepaid := s.debitStop != nil
+		isPrepaid := s.debitLoop != nil
 		s.RUnlock()
 		if isPrepaid { //active debit
 			rply.MaxUsage = &sS.cgrCfg.GeneralCfg().MaxCallDuration
@@ -3341,7 +3380,7 @@ func (sS *SessionS) BiRPCv1ProcessEvent(clnt rpcclient.ClientConnector,
 				}
 				sRunsMaxUsage := make(map[string]time.Duration)
 				s.RLock()
-				isPrepaid := s.debitStop != nil
+				isPrepaid := s.debitLoop != nil
 				s.RUnlock()
 				if isPrepaid { //active debit
 					for _, sr := range s.SRuns {
@@ -25,6 +25,7 @@ import (
 	"net/rpc"
 	"net/rpc/jsonrpc"
 	"path"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -247,3 +248,51 @@ func benchmarkSendInitSessionx10(b *testing.B) {
 		}
 	}
 }
+
+// benchSessions is the number of concurrent sessions armed by the timer benchmarks
+const benchSessions = 50000
+
+// the debit loop before the timer wheel, one goroutine and one timer per session
+func BenchmarkDebitLoopGoroutines(b *testing.B) {
+	b.ReportAllocs()
+	tStart := time.Now()
+	for i := 0; i < b.N; i++ {
+		stops := make([]chan struct{}, benchSessions)
+		var wg sync.WaitGroup
+		wg.Add(benchSessions)
+		for j := range stops {
+			stops[j] = make(chan struct{})
+			go func(stop chan struct{}) {
+				defer wg.Done()
+				tmr := time.NewTimer(time.Hour)
+				select {
+				case <-tmr.C:
+				case <-stop:
+					tmr.Stop()
+				}
+			}(stops[j])
+		}
+		for _, stop := range stops {
+			close(stop)
+		}
+		wg.Wait()
+	}
+	b.ReportMetric(float64(b.N*benchSessions)/time.Since(tStart).Seconds(), "sessions/s")
+}
+
+func BenchmarkDebitLoopTimerWheel(b *testing.B) {
+	b.ReportAllocs()
+	tw := newTimerWheel(wheelTick, wheelSlots, runtime.NumCPU(), wheelWorkers)
+	b.ResetTimer()
+	tStart := time.Now()
+	for i := 0; i < b.N; i++ {
+		timers := make([]*wheelTimer, benchSessions)
+		for j := range timers {
+			timers[j] = tw.schedule(time.Hour, func() {})
+		}
+		for _, wt := range timers {
+			wt.stop()
+		}
+	}
+	b.ReportMetric(float64(b.N*benchSessions)/time.Since(tStart).Seconds(), "sessions/s")
+}
@@ -0,0 +1,174 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// runSharedRegistry periodically renews the leases of the active sessions
+// and takes over the sessions of the nodes which stopped renewing theirs
+func (sS *SessionS) runSharedRegistry(stopChan chan struct{}) {
+	ticker := time.NewTicker(sS.cgrCfg.SessionSCfg().RegistryLease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			sS.aSsMux.RLock()
+			cgrIDs := make([]string, 0, len(sS.aSessions))
+			for cgrID := range sS.aSessions {
+				cgrIDs = append(cgrIDs, cgrID)
+			}
+			sS.aSsMux.RUnlock()
+			for _, cgrID := range cgrIDs {
+				sS.shareSession(cgrID)
+			}
+			sS.takeoverSessions()
+		}
+	}
+}
+
+// shareSession stores the active session in the shared registry, renewing its lease,
+// or removes it from the registry if the session is not active anymore
+// a session claimed by another node in the meantime is released locally
+func (sS *SessionS) shareSession(cgrID string) {
+	if !sS.cgrCfg.SessionSCfg().SharedRegistry {
+		return
+	}
+	ss := sS.getSessions(cgrID, false)
+	if len(ss) == 0 {
+		if err := sS.dm.RemoveSharedSession(cgrID,
+			sS.cgrCfg.GeneralCfg().NodeID); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> cannot remove session with id <%s> from the shared registry, err: %s",
+					utils.SessionS, cgrID, err.Error()))
+		}
+		return
+	}
+	s := ss[0]
+	s.RLock()
+	if s.CGRID != cgrID || !sS.isIndexed(s, false) { // ended or relocated in the meantime, the registry is updated there
+		s.RUnlock()
+		return
+	}
+	err := sS.storeSharedSession(s, time.Now().Add(sS.cgrCfg.SessionSCfg().RegistryLease))
+	s.RUnlock()
+	if err == utils.ErrExists {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> session with id <%s> was taken over by another node, releasing it",
+				utils.SessionS, cgrID))
+		sS.releaseSession(s)
+	} else if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot store session with id <%s> in the shared registry, err: %s",
+				utils.SessionS, cgrID, err.Error()))
+	}
+}
+
+// storeSharedSession writes the session in the shared registry with the given lease expiry
+// not thread safe, the session should be locked in a layer above
+func (sS *SessionS) storeSharedSession(s *Session, expiry time.Time) (err error) {
+	var sess []byte
+	if sess, err = json.Marshal(s); err != nil {
+		return
+	}
+	return sS.dm.SetSharedSession(&engine.SharedSession{
+		CGRID:   s.CGRID,
+		NodeID:  sS.cgrCfg.GeneralCfg().NodeID,
+		Expiry:  expiry,
+		Session: sess,
+	})
+}
+
+// releaseSession stops handling the session locally without terminating it
+func (sS *SessionS) releaseSession(s *Session) {
+	s.Lock()
+	sS.unregisterSession(s.CGRID, false)
+	s.stopSTerminator()
+	s.stopDebitLoops()
+	s.Unlock()
+}
+
+// takeoverSessions claims the sessions with expired leases in the shared registry,
+// activating them together with their debit loops and terminators
+func (sS *SessionS) takeoverSessions() {
+	sss, err := sS.dm.GetSharedSessions()
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot query the shared registry, err: %s",
+				utils.SessionS, err.Error()))
+		return
+	}
+	now := time.Now()
+	for _, ss := range sss {
+		if now.Before(ss.Expiry) ||
+			len(sS.getSessions(ss.CGRID, false)) != 0 {
+			continue
+		}
+		var claimed *engine.SharedSession
+		if claimed, err = sS.dm.ClaimSharedSession(ss.CGRID, sS.cgrCfg.GeneralCfg().NodeID,
+			now.Add(sS.cgrCfg.SessionSCfg().RegistryLease)); err != nil {
+			if err != utils.ErrNotFound { // utils.ErrNotFound means claimed by another node
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> cannot claim session with id <%s>, err: %s",
+						utils.SessionS, ss.CGRID, err.Error()))
+			}
+			continue
+		}
+		s := new(Session)
+		if err = json.Unmarshal(claimed.Session, s); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> cannot decode session with id <%s> from the shared registry, err: %s",
+					utils.SessionS, ss.CGRID, err.Error()))
+			continue
+		}
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> taking over session with id <%s> from node <%s>",
+				utils.SessionS, s.CGRID, ss.NodeID))
+		s.Lock()
+		sS.unregisterSession(s.CGRID, true) // replaces the passive copy received via replication
+		sS.registerSession(s, false)
+		sS.initSessionDebitLoops(s)
+		sS.setSTerminator(s, nil)
+		s.Unlock()
+	}
+}
+
+// releaseSharedSessions hands over the active sessions to the other nodes,
+// expiring their leases in the shared registry instead of terminating them
+func (sS *SessionS) releaseSharedSessions() {
+	now := time.Now()
+	for _, s := range sS.getSessions(utils.EmptyString, false) {
+		sS.releaseSession(s)
+		s.RLock()
+		if err := sS.storeSharedSession(s, now); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> cannot release session with id <%s> to the shared registry, err: %s",
+					utils.SessionS, s.CGRID, err.Error()))
+		}
+		s.RUnlock()
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestSessionSSharedRegistryTakeover(t *testing.T) {
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true), config.CgrConfig().CacheCfg(), nil)
+	newNode := func(nodeID string) *SessionS {
+		cfg := config.NewDefaultCGRConfig()
+		cfg.GeneralCfg().NodeID = nodeID
+		cfg.SessionSCfg().SharedRegistry = true
+		return NewSessionS(cfg, dm, nil)
+	}
+	sS1 := newNode("node1")
+	sS2 := newNode("node2")
+	sEv := engine.NewMapEvent(map[string]interface{}{
+		utils.OriginID:     "12345",
+		utils.AccountField: "1001",
+		utils.Destination:  "1002",
+		utils.RequestType:  utils.MetaPostpaid,
+		utils.Usage:        time.Minute,
+	})
+	s := &Session{
+		CGRID:      "session1",
+		Tenant:     "cgrates.org",
+		EventStart: sEv,
+		SRuns: []*SRun{{
+			Event:      sEv,
+			CD:         &engine.CallDescriptor{RunID: utils.MetaDefault},
+			TotalUsage: time.Minute,
+		}},
+	}
+	sS1.registerSession(s, false)
+	sS1.shareSession(s.CGRID)
+	if sss, err := dm.GetSharedSessions(); err != nil {
+		t.Fatal(err)
+	} else if len(sss) != 1 || sss[0].CGRID != s.CGRID || sss[0].NodeID != "node1" {
+		t.Errorf("Unexpected shared sessions: %s", utils.ToJSON(sss))
+	}
+	sS2.takeoverSessions() // the lease of node1 is active
+	if ss := sS2.getSessions(s.CGRID, false); len(ss) != 0 {
+		t.Errorf("Session taken over with an active lease: %s", utils.ToJSON(ss))
+	}
+
+	// node1 stops renewing its lease
+	s.RLock()
+	if err := sS1.storeSharedSession(s, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	s.RUnlock()
+	sS2.takeoverSessions()
+	if ss := sS2.getSessions(s.CGRID, false); len(ss) != 1 {
+		t.Fatalf("Expected the session taken over, received: %s", utils.ToJSON(ss))
+	} else if ss[0].SRuns[0].TotalUsage != time.Minute ||
+		ss[0].EventStart.GetStringIgnoreErrors(utils.AccountField) != "1001" {
+		t.Errorf("Unexpected session: %s", utils.ToJSON(ss[0]))
+	}
+	if sss, err := dm.GetSharedSessions(); err != nil {
+		t.Fatal(err)
+	} else if len(sss) != 1 || sss[0].NodeID != "node2" {
+		t.Errorf("Unexpected shared sessions: %s", utils.ToJSON(sss))
+	}
+
+	// node1 comes back and finds the session owned by node2
+	sS1.shareSession(s.CGRID)
+	if ss := sS1.getSessions(s.CGRID, false); len(ss) != 0 {
+		t.Errorf("Expected the session released by node1, received: %s", utils.ToJSON(ss))
+	}
+	// the session ends on node2
+	if !sS2.unregisterSession(s.CGRID, false) {
+		t.Fatal("session not registered on node2")
+	}
+	sS2.shareSession(s.CGRID)
+	if sss, err := dm.GetSharedSessions(); err != nil {
+		t.Fatal(err)
+	} else if len(sss) != 0 {
+		t.Errorf("Unexpected shared sessions: %s", utils.ToJSON(sss))
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package sessions
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	wheelTick    = 10 * time.Millisecond // resolution of the timers
+	wheelSlots   = 512                   // slots per shard, one revolution covering wheelSlots*wheelTick
+	wheelWorkers = 256                   // callbacks executed in parallel, they can block on RPC calls
+)
+
+// sWheel drives the debits and the terminators of all the sessions
+// instead of one goroutine and timer per session
+var sWheel = newTimerWheel(wheelTick, wheelSlots, runtime.NumCPU(), wheelWorkers)
+
+// newTimerWheel returns a timerWheel, its goroutines are started with the first timer
+func newTimerWheel(tick time.Duration, slots, shards, workers int) (tw *timerWheel) {
+	tw = &timerWheel{
+		tick:    tick,
+		shards:  make([]*wheelShard, shards),
+		workers: workers,
+		tasks:   make(chan func(), workers),
+	}
+	for i := range tw.shards {
+		tw.shards[i] = &wheelShard{slots: make([]*wheelTimer, slots)}
+	}
+	return
+}
+
+// timerWheel is a hashed timer wheel, sharded to reduce the lock contention between the sessions
+// a single goroutine advances all the shards and the expired timers are executed by a bounded pool of workers
+type timerWheel struct {
+	tick    time.Duration
+	shards  []*wheelShard
+	nxtShrd uint32 // round-robin over the shards
+	workers int
+	tasks   chan func() // expired callbacks waiting for a worker
+	started sync.Once
+}
+
+// wheelShard is a part of the wheel with its own lock
+type wheelShard struct {
+	sync.Mutex
+	slots []*wheelTimer // each slot is the head of a doubly linked list of timers
+	pos   int           // the slot processed on the last tick
+}
+
+// wheelTimer is a callback scheduled on the wheel
+type wheelTimer struct {
+	shard      *wheelShard
+	tick       time.Duration
+	fn         func()
+	slot       int
+	rounds     int // full revolutions of the wheel to wait before expiring
+	prev, next *wheelTimer
+	pending    bool
+}
+
+// schedule executes fn after d, with the precision of a tick
+func (tw *timerWheel) schedule(d time.Duration, fn func()) (wt *wheelTimer) {
+	tw.started.Do(tw.run)
+	wt = &wheelTimer{
+		shard: tw.shards[atomic.AddUint32(&tw.nxtShrd, 1)%uint32(len(tw.shards))],
+		tick:  tw.tick,
+		fn:    fn,
+	}
+	wt.shard.Lock()
+	wt.add(d)
+	wt.shard.Unlock()
+	return
+}
+
+// run starts the workers and the goroutine advancing the wheel
+func (tw *timerWheel) run() {
+	for i := 0; i < tw.workers; i++ {
+		go func() {
+			for fn := range tw.tasks {
+				fn()
+			}
+		}()
+	}
+	go func() {
+		start := time.Now()
+		var ticks int64
+		ticker := time.NewTicker(tw.tick)
+		for range ticker.C {
+			// catch up with the ticks dropped while waiting for the workers
+			for elapsed := int64(time.Since(start) / tw.tick); ticks < elapsed; ticks++ {
+				for _, shrd := range tw.shards {
+					for _, fn := range shrd.advance() {
+						tw.tasks <- fn
+					}
+				}
+			}
+		}
+	}()
+}
+
+// advance moves the shard with one slot, returning the callbacks of the expired timers
+func (shrd *wheelShard) advance() (fns []func()) {
+	shrd.Lock()
+	shrd.pos = (shrd.pos + 1) % len(shrd.slots)
+	for wt := shrd.slots[shrd.pos]; wt != nil; {
+		next := wt.next
+		if wt.rounds != 0 {
+			wt.rounds--
+		} else {
+			wt.remove()
+			fns = append(fns, wt.fn)
+		}
+		wt = next
+	}
+	shrd.Unlock()
+	return
+}
+
+// add links the timer into the slot expiring after d
+// not thread safe, the shard needs to be locked
+func (wt *wheelTimer) add(d time.Duration) {
+	ticks := int((d + wt.tick - 1) / wt.tick)
+	if ticks < 1 {
+		ticks = 1
+	}
+	slots := len(wt.shard.slots)
+	wt.slot = (wt.shard.pos + ticks) % slots
+	wt.rounds = (ticks - 1) / slots
+	wt.prev = nil
+	wt.next = wt.shard.slots[wt.slot]
+	if wt.next != nil {
+		wt.next.prev = wt
+	}
+	wt.shard.slots[wt.slot] = wt
+	wt.pending = true
+}
+
+// remove unlinks the timer from its slot
+// not thread safe, the shard needs to be locked
+func (wt *wheelTimer) remove() {
+	if wt.prev != nil {
+		wt.prev.next = wt.next
+	} else {
+		wt.shard.slots[wt.slot] = wt.next
+	}
+	if wt.next != nil {
+		wt.next.prev = wt.prev
+	}
+	wt.prev, wt.next = nil, nil
+	wt.pending = false
+}
+
+// stop cancels the timer, returning false if it already expired or was stopped
+func (wt *wheelTimer) stop() (stopped bool) {
+	wt.shard.Lock()
+	if stopped = wt.pending; stopped {
+		wt.remove()
+	}
+	wt.shard.Unlock()
+	return
+}
+
+// reset reschedules the pending timer to expire after d
+// returns false without rescheduling if the timer already expired or was stopped
+func (wt *wheelTimer) reset(d time.Duration) (reset bool) {
+	wt.shard.Lock()
+	if reset = wt.pending; reset {
+		wt.remove()
+		wt.add(d)
+	}
+	wt.shard.Unlock()
+	return
+}
@@ -0,0 +1,84 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package sessions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerWheelSchedule(t *testing.T) {
+	tw := newTimerWheel(time.Millisecond, 8, 2, 2)
+	fired := make(chan time.Duration, 3)
+	tStart := time.Now()
+	for _, d := range []time.Duration{5 * time.Millisecond, 20 * time.Millisecond, 0} { // 20ms needs more than one revolution
+		d := d
+		tw.schedule(d, func() { fired <- d })
+	}
+	for _, exp := range []time.Duration{0, 5 * time.Millisecond, 20 * time.Millisecond} {
+		select {
+		case d := <-fired:
+			if d != exp {
+				t.Errorf("Expecting timer: %s, received: %s", exp, d)
+			}
+			if elapsed := time.Since(tStart); elapsed < exp {
+				t.Errorf("Timer: %s fired after: %s", exp, elapsed)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timer: %s did not fire", exp)
+		}
+	}
+}
+
+func TestTimerWheelStop(t *testing.T) {
+	tw := newTimerWheel(time.Millisecond, 8, 1, 1)
+	fired := make(chan struct{}, 1)
+	wt := tw.schedule(10*time.Millisecond, func() { fired <- struct{}{} })
+	if !wt.stop() {
+		t.Error("Expected the timer stopped")
+	}
+	if wt.stop() {
+		t.Error("Expected the timer already stopped")
+	}
+	if wt.reset(time.Millisecond) {
+		t.Error("Expected stopped timer not reset")
+	}
+	select {
+	case <-fired:
+		t.Error("Stopped timer fired")
+	case <-time.After(30 * time.Millisecond):
+	}
+}
+
+func TestTimerWheelReset(t *testing.T) {
+	tw := newTimerWheel(time.Millisecond, 8, 1, 1)
+	fired := make(chan struct{}, 1)
+	wt := tw.schedule(time.Hour, func() { fired <- struct{}{} })
+	if !wt.reset(5 * time.Millisecond) {
+		t.Fatal("Expected the timer reset")
+	}
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Timer did not fire after reset")
+	}
+	if wt.reset(time.Millisecond) {
+		t.Error("Expected expired timer not reset")
+	}
+}
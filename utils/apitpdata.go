@@ -566,6 +566,7 @@ type ExportedTPStats struct {
 	ExportPath    string   // Full path to the newly generated export file
 	ExportedFiles []string // List of exported files
 	Compressed    bool
+	SkippedTables []string // tables found in DataDB that can not be exported, only for ExportDataDBToFolder
 }
 
 // CDRsFilter is a filter used to get records out of storDB
@@ -1176,6 +1177,41 @@ type SMCostFilter struct { //id cu litere mare
 	CreatedAt      TimeInterval
 }
 
+// AuditLogFilter is used to query the entries of the audit log
+type AuditLogFilter struct {
+	Methods  []string
+	Subjects []string
+	Tenants  []string
+	ItemIDs  []string // tenant:ID of the modified items
+	Time     TimeInterval
+	Paginator
+}
+
+// InvoicesFilter is used to query the generated invoices
+type InvoicesFilter struct {
+	IDs      []string
+	Tenants  []string
+	Accounts []string
+	Cycle    TimeInterval // the invoices with the billing cycle overlapping the interval
+	Paginator
+}
+
+// InvoicesFilterWithOpts is used in the InvoiceSv1.GetInvoices API
+type InvoicesFilterWithOpts struct {
+	*InvoicesFilter
+	Opts   map[string]interface{}
+	Tenant string
+}
+
+// ArgsGenerateInvoice selects the account and the billing cycle of the generated invoice
+type ArgsGenerateInvoice struct {
+	Tenant     string
+	Account    string
+	CycleStart time.Time
+	CycleEnd   time.Time // not included in the billing cycle
+	Opts       map[string]interface{}
+}
+
 func AppendToSMCostFilter(smcFilter *SMCostFilter, fieldType, fieldName string,
 	values []string, timezone string) (smcf *SMCostFilter, err error) {
 	switch fieldName {
@@ -1282,6 +1318,90 @@ type RPCCDRsFilterWithOpts struct {
 	Tenant string
 }
 
+// RPCCDRsAggregationWithOpts selects the CDRs to be aggregated and the fields grouping them
+type RPCCDRsAggregationWithOpts struct {
+	*RPCCDRsFilter
+	GroupBy   []string // <Field|Destination;$prefix_length|AnswerTime;*hourly|SetupTime;*daily|$extra_field>
+	SumFields []string // extra fields with numeric values summed for each group
+	Opts      map[string]interface{}
+	Tenant    string
+}
+
+// CDRsGroupBy is one of the fields grouping the CDRs in the aggregation
+type CDRsGroupBy struct {
+	Key        string // the GroupBy item, used as key for the values of the group
+	Field      string
+	Length     int    // length of the Destination prefix, 0 for the full Destination
+	Period     string // <*hourly|*daily> the SetupTime or AnswerTime is truncated to
+	ExtraField bool   // the Field is one of the ExtraFields
+}
+
+// NewCDRsGroupBy parses the GroupBy items of the CDRs aggregation
+func NewCDRsGroupBy(groupBy []string) (grps []*CDRsGroupBy, err error) {
+	if len(groupBy) == 0 {
+		return nil, NewErrMandatoryIeMissing("GroupBy")
+	}
+	grps = make([]*CDRsGroupBy, len(groupBy))
+	for i, key := range groupBy {
+		fldOpt := strings.SplitN(key, InfieldSep, 2)
+		grps[i] = &CDRsGroupBy{Key: key, Field: fldOpt[0]}
+		switch fldOpt[0] {
+		case Tenant, RunID, ToR, RequestType, Category, AccountField,
+			Subject, OriginHost, Source, CostSource:
+			if len(fldOpt) == 2 {
+				return nil, fmt.Errorf("GroupBy: %q not supported", key)
+			}
+		case Destination:
+			if len(fldOpt) == 2 {
+				if grps[i].Length, err = strconv.Atoi(fldOpt[1]); err != nil || grps[i].Length <= 0 {
+					return nil, fmt.Errorf("GroupBy: %q not supported", key)
+				}
+			}
+		case SetupTime, AnswerTime:
+			if len(fldOpt) != 2 ||
+				(fldOpt[1] != MetaHourly && fldOpt[1] != MetaDaily) {
+				return nil, fmt.Errorf("GroupBy: %q not supported", key)
+			}
+			grps[i].Period = fldOpt[1]
+		default: // the other main fields can not group the CDRs
+			if len(fldOpt) == 2 || MainCDRFields.Has(fldOpt[0]) ||
+				!ValidCDRsExtraField(fldOpt[0]) {
+				return nil, fmt.Errorf("GroupBy: %q not supported", key)
+			}
+			grps[i].ExtraField = true
+		}
+	}
+	return
+}
+
+// ValidCDRsExtraField returns false for the extra field names that can not be used in the StorDB queries
+func ValidCDRsExtraField(fld string) bool {
+	return fld != EmptyString && !strings.ContainsAny(fld, `'"\.$`)
+}
+
+// CheckCDRsSumFields returns an error if the fields can not be summed by the CDRs aggregation
+func CheckCDRsSumFields(sumFlds []string) (err error) {
+	for _, fld := range sumFlds {
+		if MainCDRFields.Has(fld) || !ValidCDRsExtraField(fld) {
+			return fmt.Errorf("SumField: %q not supported", fld)
+		}
+	}
+	return
+}
+
+// CDRsAggregate summarizes one group of CDRs
+type CDRsAggregate struct {
+	Group    map[string]string // the values of the GroupBy items
+	Count    int64
+	Usage    time.Duration
+	MinUsage time.Duration
+	MaxUsage time.Duration
+	Cost     float64
+	MinCost  float64
+	MaxCost  float64
+	Sums     map[string]float64 // the sums of the SumFields
+}
+
 type ArgsGetCacheItemIDsWithOpts struct {
 	Opts   map[string]interface{}
 	Tenant string
@@ -1237,3 +1237,37 @@ func TestBalanceUnitFactor(t *testing.T) {
 		t.Errorf("Expected %+v, received %+v", expected, rcv)
 	}
 }
+
+func TestNewCDRsGroupBy(t *testing.T) {
+	expected := []*CDRsGroupBy{
+		{Key: "Account", Field: AccountField},
+		{Key: "Destination;4", Field: Destination, Length: 4},
+		{Key: "AnswerTime;*daily", Field: AnswerTime, Period: MetaDaily},
+		{Key: "Supplier", Field: Supplier, ExtraField: true},
+	}
+	if rcv, err := NewCDRsGroupBy([]string{"Account", "Destination;4", "AnswerTime;*daily", "Supplier"}); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, rcv) {
+		t.Errorf("Expected %+v, received %+v", ToJSON(expected), ToJSON(rcv))
+	}
+	if _, err := NewCDRsGroupBy(nil); err == nil || err.Error() != "MANDATORY_IE_MISSING: [GroupBy]" {
+		t.Errorf("Expected error, received %+v", err)
+	}
+	for _, grpBy := range []string{"Usage", "Account;4", "Destination;0",
+		"Destination;a", "AnswerTime", "SetupTime;*monthly", "Supplier;4", "Cost.Extra", "$where"} {
+		expErr := "GroupBy: \"" + grpBy + "\" not supported"
+		if _, err := NewCDRsGroupBy([]string{grpBy}); err == nil || err.Error() != expErr {
+			t.Errorf("Expected %+v, received %+v", expErr, err)
+		}
+	}
+}
+
+func TestCheckCDRsSumFields(t *testing.T) {
+	if err := CheckCDRsSumFields([]string{CustomerCost, SupplierCost}); err != nil {
+		t.Error(err)
+	}
+	if err := CheckCDRsSumFields([]string{CustomerCost, "'Cost"}); err == nil ||
+		err.Error() != `SumField: "'Cost" not supported` {
+		t.Errorf("Expected error, received %+v", err)
+	}
+}
@@ -47,7 +47,8 @@ var (
 		CacheCDRIDs, CacheRPCConnections, CacheUCH, CacheSTIR, CacheEventCharges, MetaAPIBan,
 		CacheCapsEvents, CacheVersions})
 
-	dataDBPartition = NewStringSet([]string{CacheDestinations, CacheReverseDestinations, CacheRatingPlans,
+	// DataDBPartitions are the cache partitions used by the InternalDB as DataDB
+	DataDBPartitions = NewStringSet([]string{CacheDestinations, CacheReverseDestinations, CacheRatingPlans,
 		CacheRatingProfiles, CacheActions, CacheActionTriggers, CacheSharedGroups, CacheTimings,
 		CacheResourceProfiles, CacheResources, CacheEventResources, CacheStatQueueProfiles, CacheStatQueues,
 		CacheThresholdProfiles, CacheThresholds, CacheFilters, CacheRouteProfiles, CacheAttributeProfiles,
@@ -56,17 +57,19 @@ var (
 		CacheAttributeFilterIndexes, CacheChargerFilterIndexes, CacheDispatcherFilterIndexes, CacheLoadIDs,
 		CacheRatingProfilesTmp, CacheRateProfiles, CacheRateProfilesFilterIndexes, CacheRateFilterIndexes,
 		CacheActionProfilesFilterIndexes, CacheAccountProfilesFilterIndexes, CacheReverseFilterIndexes,
-		CacheActionPlans, CacheAccountActionPlans, CacheAccountProfiles, CacheAccounts})
+		CacheActionPlans, CacheAccountActionPlans, CacheAccountProfiles, CacheAccounts, CachePortedNumbers, CacheSharedSessions,
+		CacheConfigSections})
 
-	storDBPartition = NewStringSet([]string{CacheTBLTPTimings, CacheTBLTPDestinations, CacheTBLTPRates, CacheTBLTPDestinationRates,
+	// StorDBPartitions are the cache partitions used by the InternalDB as StorDB
+	StorDBPartitions = NewStringSet([]string{CacheTBLTPTimings, CacheTBLTPDestinations, CacheTBLTPRates, CacheTBLTPDestinationRates,
 		CacheTBLTPRatingPlans, CacheTBLTPRatingProfiles, CacheTBLTPSharedGroups, CacheTBLTPActions,
 		CacheTBLTPActionPlans, CacheTBLTPActionTriggers, CacheTBLTPAccountActions, CacheTBLTPResources,
 		CacheTBLTPStats, CacheTBLTPThresholds, CacheTBLTPFilters, CacheSessionCostsTBL, CacheCDRsTBL,
-		CacheTBLTPRoutes, CacheTBLTPAttributes, CacheTBLTPChargers, CacheTBLTPDispatchers,
+		CacheAuditLogTBL, CacheInvoicesTBL, CacheTBLTPRoutes, CacheTBLTPAttributes, CacheTBLTPChargers, CacheTBLTPDispatchers,
 		CacheTBLTPDispatcherHosts, CacheTBLTPRateProfiles, CacheTBLTPActionProfiles, CacheTBLTPAccountProfiles})
 
 	// CachePartitions enables creation of cache partitions
-	CachePartitions = Join(extraDBPartition, dataDBPartition, storDBPartition)
+	CachePartitions = Join(extraDBPartition, DataDBPartitions, StorDBPartitions)
 
 	CacheInstanceToPrefix = map[string]string{
 		CacheDestinations:                 DestinationPrefix,
@@ -94,6 +97,7 @@ var (
 		CacheRateProfiles:                 RateProfilePrefix,
 		CacheActionProfiles:               ActionProfilePrefix,
 		CacheAccountProfiles:              AccountProfilePrefix,
+		CachePortedNumbers:                PortedNumberPrefix,
 		CacheResourceFilterIndexes:        ResourceFilterIndexes,
 		CacheStatFilterIndexes:            StatFilterIndexes,
 		CacheThresholdFilterIndexes:       ThresholdFilterIndexes,
@@ -164,6 +168,8 @@ var (
 		TBLTPFilters:          CacheTBLTPFilters,
 		SessionCostsTBL:       CacheSessionCostsTBL,
 		CDRsTBL:               CacheCDRsTBL,
+		AuditLogTBL:           CacheAuditLogTBL,
+		InvoicesTBL:           CacheInvoicesTBL,
 		TBLTPRoutes:           CacheTBLTPRoutes,
 		TBLTPAttributes:       CacheTBLTPAttributes,
 		TBLTPChargers:         CacheTBLTPChargers,
@@ -197,6 +203,7 @@ var (
 		ChargerProfileIDs:     ChargerProfilePrefix,
 		DispatcherProfileIDs:  DispatcherProfilePrefix,
 		DispatcherHostIDs:     DispatcherHostPrefix,
+		PortedNumberIDs:       PortedNumberPrefix,
 		RateProfileIDs:        RateProfilePrefix,
 		ActionProfileIDs:      ActionProfilePrefix,
 
@@ -237,6 +244,7 @@ var (
 		ChargerProfileIDs:     CacheChargerProfiles,
 		DispatcherProfileIDs:  CacheDispatcherProfiles,
 		DispatcherHostIDs:     CacheDispatcherHosts,
+		PortedNumberIDs:       CachePortedNumbers,
 		RateProfileIDs:        CacheRateProfiles,
 		ActionProfileIDs:      CacheActionProfiles,
 
@@ -279,6 +287,8 @@ const (
 	MetaPseudoPrepaid        = "*pseudoprepaid"
 	MetaRated                = "*rated"
 	MetaNone                 = "*none"
+	MetaAlways               = "*always"
+	MetaInterval             = "*interval"
 	MetaNow                  = "*now"
 	MetaRoundingUp           = "*up"
 	MetaRoundingMiddle       = "*middle"
@@ -353,12 +363,14 @@ const (
 	MetaMongo               = "*mongo"
 	MetaPostgres            = "*postgres"
 	MetaInternal            = "*internal"
+	MetaRedis               = "*redis"
 	MetaLocalHost           = "*localhost"
 	MetaRatingSubjectPrefix = "*zero"
 	OK                      = "OK"
 	MetaFileXML             = "*file_xml"
 	MetaFileJSON            = "*file_json"
 	MaskChar                = "*"
+	MaskedSecret            = "*****"
 	ConcatenatedKeySep      = ":"
 	UnitTest                = "UNIT_TEST"
 	HDRValSep               = "/"
@@ -403,9 +415,13 @@ const (
 	ActionProfilePrefix       = "acp_"
 	AccountProfilePrefix      = "anp_"
 	DispatcherHostPrefix      = "dph_"
+	PortedNumberPrefix        = "lnp_"
 	ThresholdProfilePrefix    = "thp_"
 	StatQueuePrefix           = "stq_"
 	LoadIDPrefix              = "lid_"
+	DedupIDPrefix             = "ddp_"
+	SharedSessionPrefix       = "ssn_"
+	ConfigSectionPrefix       = "cfg_"
 	LoadInstKey               = "load_history"
 	CreateCDRsTablesSQL       = "create_cdrs_tables.sql"
 	CreateTariffPlanTablesSQL = "create_tariffplan_tables.sql"
@@ -422,6 +438,7 @@ const (
 	MetaSQSjsonMap            = "*sqs_json_map"
 	MetaKafkajsonMap          = "*kafka_json_map"
 	MetaSQL                   = "*sql"
+	MetaHTTP                  = "*http"
 	MetaMySQL                 = "*mysql"
 	MetaS3jsonMap             = "*s3_json_map"
 	ConfigPath                = "/etc/cgrates/"
@@ -470,6 +487,7 @@ const (
 	XML                      = "xml"
 	MetaGOB                  = "*gob"
 	MetaJSON                 = "*json"
+	MetaBiJSON               = "*birpc_json"
 	MetaMSGPACK              = "*msgpack"
 	MetaDateTime             = "*datetime"
 	MetaMaskedDestination    = "*masked_destination"
@@ -479,6 +497,8 @@ const (
 	NonTransactional         = ""
 	DataDB                   = "data_db"
 	StorDB                   = "stor_db"
+	InternalDB               = "InternalDB"
+	ConfigDB                 = "ConfigDB"
 	NotFoundCaps             = "NOT_FOUND"
 	ServerErrorCaps          = "SERVER_ERROR"
 	MandatoryIEMissingCaps   = "MANDATORY_IE_MISSING"
@@ -507,6 +527,7 @@ const (
 	MetaDispatchers          = "*dispatchers"
 	MetaDispatcherh          = "*dispatcherh"
 	MetaDispatcherHosts      = "*dispatcher_hosts"
+	MetaPortedNumbers        = "*ported_numbers"
 	MetaFilters              = "*filters"
 	MetaCDRs                 = "*cdrs"
 	MetaDC                   = "*dc"
@@ -523,21 +544,31 @@ const (
 	UndefinedVersion         = "undefined version"
 	TxtSuffix                = ".txt"
 	JSNSuffix                = ".json"
+	XLSXSuffix               = ".xlsx"
 	GOBSuffix                = ".gob"
 	XMLSuffix                = ".xml"
 	CSVSuffix                = ".csv"
 	FWVSuffix                = ".fwv"
+	HTMLSuffix               = ".html"
+	HTMSuffix                = ".htm"
+	SnapshotSuffix           = ".snapshot"
+	WALSuffix                = ".wal"
 	ContentJSON              = "json"
 	ContentForm              = "form"
 	ContentText              = "text"
 	FileLockPrefix           = "file_"
+	GuardianLockPrefix       = "glk_"
+	GuardianFencingKey       = "guardian_fencing"
+	ConfigDBChannel          = "cgr_config_db"
 	ActionsPoster            = "act"
 	CDRPoster                = "cdr"
 	MetaFileCSV              = "*file_csv"
 	MetaVirt                 = "*virt"
 	MetaElastic              = "*elastic"
 	MetaFileFWV              = "*file_fwv"
+	MetaFileTemplate         = "*file_template"
 	MetaFile                 = "*file"
+	MetaOTLP                 = "*otlp"
 	Accounts                 = "Accounts"
 	AccountService           = "AccountS"
 	AccountS                 = "AccountS"
@@ -598,6 +629,8 @@ const (
 	ResourceID            = "ResourceID"
 	TotalUsage            = "TotalUsage"
 	StatID                = "StatID"
+	ThresholdID           = "ThresholdID"
+	Hits                  = "Hits"
 	BalanceType           = "BalanceType"
 	BalanceID             = "BalanceID"
 	BalanceDestinationIds = "BalanceDestinationIds"
@@ -634,6 +667,9 @@ const (
 	RatingProfile            = "RatingProfile"
 	MetaRatingPlans          = "*rating_plans"
 	MetaRatingProfiles       = "*rating_profiles"
+	MetaRatingToRateProfiles = "*rating_to_rateprofiles"
+	MetaAccountsToProfiles   = "*accounts_to_accountprofiles"
+	MetaActPlansToProfiles   = "*actionplans_to_actionprofiles"
 	MetaUsers                = "*users"
 	MetaSubscribers          = "*subscribers"
 	MetaDerivedChargersV     = "*derivedchargers"
@@ -800,6 +836,7 @@ const (
 	ResourceUsage         = "ResourceUsage"
 	MetaDuration          = "*duration"
 	MetaLibPhoneNumber    = "*libphonenumber"
+	MetaLNP               = "*lnp"
 	MetaTimeString        = "*time_string"
 	MetaIP2Hex            = "*ip2hex"
 	MetaString2Hex        = "*string2hex"
@@ -916,6 +953,7 @@ const (
 	RateProfileMatched    = "RateProfileMatched"
 	InvalidDuration       = time.Duration(-1)
 	ActionS               = "ActionS"
+	InvoiceS              = "InvoiceS"
 	Schedule              = "Schedule"
 	ActionFilterIDs       = "ActionFilterIDs"
 	ActionBlocker         = "ActionBlocker"
@@ -939,6 +977,14 @@ const (
 	SubjectLowerCase      = "subject"
 	RatingProfileID       = "RatingProfileID"
 	Time                  = "Time"
+	Method                = "Method"
+	Role                  = "Role"
+	RemoteAddr            = "RemoteAddr"
+	ItemID                = "ItemID"
+	Args                  = "Args"
+	Before                = "Before"
+	After                 = "After"
+	Diff                  = "Diff"
 	TargetIDs             = "TargetIDs"
 	TargetType            = "TargetType"
 	MetaRow               = "*row"
@@ -986,6 +1032,8 @@ const (
 	MetaAccountProfiles     = "*account_profiles"
 	MetaLoadIDs             = "*load_ids"
 	MetaAccountS            = "*accounts"
+	MetaBalance             = "*balance"
+	MetaInvoices            = "*invoices"
 )
 
 // MetaMetrics
@@ -1245,6 +1293,7 @@ const (
 	ReplicatorSv1GetActionProfile        = "ReplicatorSv1.GetActionProfile"
 	ReplicatorSv1GetDispatcherHost       = "ReplicatorSv1.GetDispatcherHost"
 	ReplicatorSv1GetAccountProfile       = "ReplicatorSv1.GetAccountProfile"
+	ReplicatorSv1GetPortedNumber         = "ReplicatorSv1.GetPortedNumber"
 	ReplicatorSv1GetItemLoadIDs          = "ReplicatorSv1.GetItemLoadIDs"
 	ReplicatorSv1SetThresholdProfile     = "ReplicatorSv1.SetThresholdProfile"
 	ReplicatorSv1SetThreshold            = "ReplicatorSv1.SetThreshold"
@@ -1272,6 +1321,7 @@ const (
 	ReplicatorSv1SetActionProfile        = "ReplicatorSv1.SetActionProfile"
 	ReplicatorSv1SetAccountProfile       = "ReplicatorSv1.SetAccountProfile"
 	ReplicatorSv1SetDispatcherHost       = "ReplicatorSv1.SetDispatcherHost"
+	ReplicatorSv1SetPortedNumber         = "ReplicatorSv1.SetPortedNumber"
 	ReplicatorSv1SetLoadIDs              = "ReplicatorSv1.SetLoadIDs"
 	ReplicatorSv1RemoveThreshold         = "ReplicatorSv1.RemoveThreshold"
 	ReplicatorSv1RemoveDestination       = "ReplicatorSv1.RemoveDestination"
@@ -1298,6 +1348,7 @@ const (
 	ReplicatorSv1RemoveActionProfile     = "ReplicatorSv1.RemoveActionProfile"
 	ReplicatorSv1RemoveDispatcherHost    = "ReplicatorSv1.RemoveDispatcherHost"
 	ReplicatorSv1RemoveAccountProfile    = "ReplicatorSv1.RemoveAccountProfile"
+	ReplicatorSv1RemovePortedNumber      = "ReplicatorSv1.RemovePortedNumber"
 	ReplicatorSv1GetIndexes              = "ReplicatorSv1.GetIndexes"
 	ReplicatorSv1SetIndexes              = "ReplicatorSv1.SetIndexes"
 	ReplicatorSv1RemoveIndexes           = "ReplicatorSv1.RemoveIndexes"
@@ -1320,6 +1371,10 @@ const (
 	APIerSv1GetDispatcherHost           = "APIerSv1.GetDispatcherHost"
 	APIerSv1GetDispatcherHostIDs        = "APIerSv1.GetDispatcherHostIDs"
 	APIerSv1RemoveDispatcherHost        = "APIerSv1.RemoveDispatcherHost"
+	APIerSv1SetPortedNumber             = "APIerSv1.SetPortedNumber"
+	APIerSv1GetPortedNumber             = "APIerSv1.GetPortedNumber"
+	APIerSv1GetPortedNumberIDs          = "APIerSv1.GetPortedNumberIDs"
+	APIerSv1RemovePortedNumber          = "APIerSv1.RemovePortedNumber"
 	APIerSv1GetEventCost                = "APIerSv1.GetEventCost"
 	APIerSv1LoadTariffPlanFromFolder    = "APIerSv1.LoadTariffPlanFromFolder"
 	APIerSv1ExportToFolder              = "APIerSv1.ExportToFolder"
@@ -1414,6 +1469,10 @@ const (
 	APIerSv1GetTPSharedGroupIds         = "APIerSv1.GetTPSharedGroupIds"
 	APIerSv1RemoveTPSharedGroups        = "APIerSv1.RemoveTPSharedGroups"
 	APIerSv1ExportCDRs                  = "APIerSv1.ExportCDRs"
+	APIerSv1GetAuditLog                 = "APIerSv1.GetAuditLog"
+	APIerSv1SetConfigDB                 = "APIerSv1.SetConfigDB"
+	APIerSv1GetConfigDB                 = "APIerSv1.GetConfigDB"
+	APIerSv1RemoveConfigDB              = "APIerSv1.RemoveConfigDB"
 	APIerSv1GetTPRatingPlan             = "APIerSv1.GetTPRatingPlan"
 	APIerSv1SetTPRatingPlan             = "APIerSv1.SetTPRatingPlan"
 	APIerSv1GetTPRatingPlanIds          = "APIerSv1.GetTPRatingPlanIds"
@@ -1434,6 +1493,7 @@ const (
 	APIerSv1RemoveTPDestinationRate     = "APIerSv1.RemoveTPDestinationRate"
 	APIerSv1ImportTariffPlanFromFolder  = "APIerSv1.ImportTariffPlanFromFolder"
 	APIerSv1ExportTPToFolder            = "APIerSv1.ExportTPToFolder"
+	APIerSv1ExportDataDBToFolder        = "APIerSv1.ExportDataDBToFolder"
 	APIerSv1LoadRatingPlan              = "APIerSv1.LoadRatingPlan"
 	APIerSv1LoadRatingProfile           = "APIerSv1.LoadRatingProfile"
 	APIerSv1LoadAccountActions          = "APIerSv1.LoadAccountActions"
@@ -1566,11 +1626,15 @@ const (
 )
 
 const (
-	CoreS         = "CoreS"
-	CoreSv1       = "CoreSv1"
-	CoreSv1Status = "CoreSv1.Status"
-	CoreSv1Ping   = "CoreSv1.Ping"
-	CoreSv1Sleep  = "CoreSv1.Sleep"
+	CoreS               = "CoreS"
+	CoreSv1             = "CoreSv1"
+	CoreSv1Status       = "CoreSv1.Status"
+	CoreSv1Ping         = "CoreSv1.Ping"
+	CoreSv1Sleep        = "CoreSv1.Sleep"
+	CoreSv1Snapshot     = "CoreSv1.Snapshot"
+	CoreSv1Authenticate = "CoreSv1.Authenticate" // answered by the listeners, authorizing the next calls on the connection
+	CoreSv1StreamEvents = "CoreSv1.StreamEvents" // the subscription to the live events, authorized as an API call
+	CoreSv1Metrics      = "CoreSv1.Metrics"      // the HTTP metrics, authorized as an API call
 )
 
 // RouteS APIs
@@ -1775,18 +1839,21 @@ const (
 
 // Cdrs APIs
 const (
-	CDRsV1                   = "CDRsV1"
-	CDRsV1GetCDRsCount       = "CDRsV1.GetCDRsCount"
-	CDRsV1RateCDRs           = "CDRsV1.RateCDRs"
-	CDRsV1GetCDRs            = "CDRsV1.GetCDRs"
-	CDRsV1ProcessCDR         = "CDRsV1.ProcessCDR"
-	CDRsV1ProcessExternalCDR = "CDRsV1.ProcessExternalCDR"
-	CDRsV1StoreSessionCost   = "CDRsV1.StoreSessionCost"
-	CDRsV1ProcessEvent       = "CDRsV1.ProcessEvent"
-	CDRsV1Ping               = "CDRsV1.Ping"
-	CDRsV2                   = "CDRsV2"
-	CDRsV2StoreSessionCost   = "CDRsV2.StoreSessionCost"
-	CDRsV2ProcessEvent       = "CDRsV2.ProcessEvent"
+	CDRsV1                    = "CDRsV1"
+	CDRsV1GetCDRsCount        = "CDRsV1.GetCDRsCount"
+	CDRsV1RateCDRs            = "CDRsV1.RateCDRs"
+	CDRsV1GetCDRs             = "CDRsV1.GetCDRs"
+	CDRsV1ProcessCDR          = "CDRsV1.ProcessCDR"
+	CDRsV1ProcessExternalCDR  = "CDRsV1.ProcessExternalCDR"
+	CDRsV1StoreSessionCost    = "CDRsV1.StoreSessionCost"
+	CDRsV1ProcessEvent        = "CDRsV1.ProcessEvent"
+	CDRsV1Ping                = "CDRsV1.Ping"
+	CDRsV1GetWholesaleMargins = "CDRsV1.GetWholesaleMargins"
+	CDRsV1GetCDRsAggregates   = "CDRsV1.GetCDRsAggregates"
+	CDRsV1ProcessHTTPCDR      = "CDRsV1.ProcessHTTPCDR" // the CDRs posted over HTTP, authorized as an API call
+	CDRsV2                    = "CDRsV2"
+	CDRsV2StoreSessionCost    = "CDRsV2.StoreSessionCost"
+	CDRsV2ProcessEvent        = "CDRsV2.ProcessEvent"
 )
 
 // Scheduler
@@ -1856,6 +1923,7 @@ const (
 	RateProfilesCsv       = "RateProfiles.csv"
 	ActionProfilesCsv     = "ActionProfiles.csv"
 	AccountProfilesCsv    = "AccountProfiles.csv"
+	PortedNumbersCsv      = "PortedNumbers.csv"
 )
 
 // Table Name
@@ -1877,6 +1945,8 @@ const (
 	TBLTPFilters          = "tp_filters"
 	SessionCostsTBL       = "session_costs"
 	CDRsTBL               = "cdrs"
+	AuditLogTBL           = "audit_log"
+	InvoicesTBL           = "invoices"
 	TBLTPRoutes           = "tp_routes"
 	TBLTPAttributes       = "tp_attributes"
 	TBLTPChargers         = "tp_chargers"
@@ -1914,6 +1984,9 @@ const (
 	CacheChargerProfiles              = "*charger_profiles"
 	CacheDispatcherProfiles           = "*dispatcher_profiles"
 	CacheDispatcherHosts              = "*dispatcher_hosts"
+	CachePortedNumbers                = "*ported_numbers"
+	CacheSharedSessions               = "*shared_sessions"
+	CacheConfigSections               = "*config_sections"
 	CacheDispatchers                  = "*dispatchers"
 	CacheDispatcherRoutes             = "*dispatcher_routes"
 	CacheDispatcherLoads              = "*dispatcher_loads"
@@ -1966,6 +2039,8 @@ const (
 	CacheTBLTPFilters          = "*tp_filters"
 	CacheSessionCostsTBL       = "*session_costs"
 	CacheCDRsTBL               = "*cdrs"
+	CacheAuditLogTBL           = "*audit_log"
+	CacheInvoicesTBL           = "*invoices"
 	CacheTBLTPRoutes           = "*tp_routes"
 	CacheTBLTPAttributes       = "*tp_attributes"
 	CacheTBLTPChargers         = "*tp_chargers"
@@ -2043,6 +2118,8 @@ const (
 	ConnectTimeoutCfg   = "connect_timeout"
 	ReplyTimeoutCfg     = "reply_timeout"
 	LockingTimeoutCfg   = "locking_timeout"
+	LockingBackendCfg   = "locking_backend"
+	LockingLeaseCfg     = "locking_lease"
 	DigestSeparatorCfg  = "digest_separator"
 	DigestEqualCfg      = "digest_equal"
 	RSRSepCfg           = "rsr_separator"
@@ -2068,22 +2145,26 @@ const (
 
 // DataDbCfg
 const (
-	DataDbTypeCfg              = "db_type"
-	DataDbHostCfg              = "db_host"
-	DataDbPortCfg              = "db_port"
-	DataDbNameCfg              = "db_name"
-	DataDbUserCfg              = "db_user"
-	DataDbPassCfg              = "db_password"
-	RedisSentinelNameCfg       = "redis_sentinel"
-	RmtConnsCfg                = "remote_conns"
-	RplConnsCfg                = "replication_conns"
-	RedisClusterCfg            = "redis_cluster"
-	RedisClusterSyncCfg        = "redis_cluster_sync"
-	RedisClusterOnDownDelayCfg = "redis_cluster_ondown_delay"
-	RedisTLS                   = "redis_tls"
-	RedisClientCertificate     = "redis_client_certificate"
-	RedisClientKey             = "redis_client_key"
-	RedisCACertificate         = "redis_ca_certificate"
+	DataDbTypeCfg               = "db_type"
+	DataDbHostCfg               = "db_host"
+	DataDbPortCfg               = "db_port"
+	DataDbNameCfg               = "db_name"
+	DataDbUserCfg               = "db_user"
+	DataDbPassCfg               = "db_password"
+	RedisSentinelNameCfg        = "redis_sentinel"
+	RmtConnsCfg                 = "remote_conns"
+	RplConnsCfg                 = "replication_conns"
+	RedisClusterCfg             = "redis_cluster"
+	RedisClusterSyncCfg         = "redis_cluster_sync"
+	RedisClusterOnDownDelayCfg  = "redis_cluster_ondown_delay"
+	RedisTLS                    = "redis_tls"
+	RedisClientCertificate      = "redis_client_certificate"
+	RedisClientKey              = "redis_client_key"
+	RedisCACertificate          = "redis_ca_certificate"
+	InternalDumpPathCfg         = "internal_dump_path"
+	InternalFsyncCfg            = "internal_fsync"
+	InternalFsyncIntervalCfg    = "internal_fsync_interval"
+	InternalSnapshotIntervalCfg = "internal_snapshot_interval"
 )
 
 // ItemOpt
@@ -2127,6 +2208,8 @@ const (
 	HTTPCDRsURLCfg             = "http_cdrs"
 	HTTPUseBasicAuthCfg        = "use_basic_auth"
 	HTTPAuthUsersCfg           = "auth_users"
+	HTTPMetricsURLCfg          = "metrics_url"
+	HTTPEventsURLCfg           = "events_url"
 	HTTPClientOptsCfg          = "client_opts"
 	ConfigsURL                 = "configs_url"
 
@@ -2157,6 +2240,7 @@ const (
 const (
 	EnabledCfg                 = "enabled"
 	ThresholdSConnsCfg         = "thresholds_conns"
+	ActionSConnsCfg            = "actions_conns"
 	CacheSConnsCfg             = "caches_conns"
 	RpSubjectPrefixMatchingCfg = "rp_subject_prefix_matching"
 	RemoveExpiredCfg           = "remove_expired"
@@ -2182,6 +2266,8 @@ const (
 	OnlineCDRExportsCfg    = "online_cdr_exports"
 	SessionCostRetires     = "session_cost_retries"
 	RateSConnsCfg          = "rates_conns"
+	CustomerRunIDCfg       = "customer_run_id"
+	SupplierRunIDCfg       = "supplier_run_id"
 )
 
 // SessionSCfg
@@ -2207,6 +2293,8 @@ const (
 	ClientProtocolCfg      = "client_protocol"
 	ChannelSyncIntervalCfg = "channel_sync_interval"
 	TerminateAttemptsCfg   = "terminate_attempts"
+	SharedRegistryCfg      = "shared_registry"
+	RegistryLeaseCfg       = "registry_lease"
 	AlterableFieldsCfg     = "alterable_fields"
 	MinDurLowBalanceCfg    = "min_dur_low_balance"
 	STIRCfg                = "stir"
@@ -2279,6 +2367,12 @@ const (
 	IndexedSelectsCfg = "indexed_selects"
 	ProcessRunsCfg    = "process_runs"
 	NestedFieldsCfg   = "nested_fields"
+	LookupsCfg        = "lookups"
+	QueryCfg          = "query"
+	ArgsCfg           = "args"
+	TimeoutCfg        = "timeout"
+	CacheTTLCfg       = "cache_ttl"
+	FallbackCfg       = "fallback"
 
 	// ChargerSCfg
 	StoreIntervalCfg = "store_interval"
@@ -2299,11 +2393,12 @@ const (
 	AttributeIDsCfg      = "attribute_ids"
 
 	//LoaderSCfg
-	DryRunCfg       = "dry_run"
-	LockFileNameCfg = "lock_filename"
-	TpInDirCfg      = "tp_in_dir"
-	TpOutDirCfg     = "tp_out_dir"
-	DataCfg         = "data"
+	DryRunCfg        = "dry_run"
+	TransactionalCfg = "transactional"
+	LockFileNameCfg  = "lock_filename"
+	TpInDirCfg       = "tp_in_dir"
+	TpOutDirCfg      = "tp_out_dir"
+	DataCfg          = "data"
 
 	DefaultRatioCfg           = "default_ratio"
 	ReadersCfg                = "readers"
@@ -2325,16 +2420,39 @@ const (
 	RateSuffixIndexedFieldsCfg = "rate_suffix_indexed_fields"
 	Verbosity                  = "verbosity"
 
+	// ApierSCfg
+	AuditCfg            = "audit"
+	AuditAPIsCfg        = "audit_apis"
+	AuditStorageCfg     = "audit_storage"
+	AuditExporterIDsCfg = "audit_exporter_ids"
+
+	// InvoiceSCfg
+	RunIDsCfg       = "run_ids"
+	NumberPrefixCfg = "number_prefix"
+	ExporterIDsCfg  = "exporter_ids"
+
 	// AnalyzerSCfg
 	CleanupIntervalCfg = "cleanup_interval"
 	IndexTypeCfg       = "index_type"
 	DBPathCfg          = "db_path"
 
 	// CoreSCfg
-	CapsCfg              = "caps"
-	CapsStrategyCfg      = "caps_strategy"
-	CapsStatsIntervalCfg = "caps_stats_interval"
-	ShutdownTimeoutCfg   = "shutdown_timeout"
+	CapsCfg               = "caps"
+	CapsStrategyCfg       = "caps_strategy"
+	CapsStatsIntervalCfg  = "caps_stats_interval"
+	ShutdownTimeoutCfg    = "shutdown_timeout"
+	StatQueueIDsCfg       = "stat_queue_ids"
+	APIAuthCfg            = "api_auth"
+	APIKeysCfg            = "api_keys"
+	APIRolesCfg           = "api_roles"
+	JWTKeyCfg             = "jwt_key"
+	TraceExporterCfg      = "trace_exporter"
+	TraceExportPathCfg    = "trace_export_path"
+	TraceSampleRatioCfg   = "trace_sample_ratio"
+	TraceFlushIntervalCfg = "trace_flush_interval"
+	AllowCfg              = "allow"
+	DenyCfg               = "deny"
+	TenantsCfg            = "tenants"
 )
 
 // FC Template
@@ -2361,6 +2479,8 @@ const (
 // SureTax
 const (
 	RootDirCfg              = "root_dir"
+	ConfigDBCfg             = "config_db"
+	ConfigDBSyncIntervalCfg = "config_db_sync_interval"
 	URLCfg                  = "url"
 	ClientNumberCfg         = "client_number"
 	ValidationKeyCfg        = "validation_key"
@@ -2446,6 +2566,10 @@ const (
 	PartialCacheExpiryActionCfg = "partial_cache_expiry_action"
 	FieldsCfg                   = "fields"
 	CacheDumpFieldsCfg          = "cache_dump_fields"
+	DedupStoreCfg               = "dedup_store"
+	DedupTTLCfg                 = "dedup_ttl"
+	DedupIDCfg                  = "dedup_id"
+	DedupEEIDsCfg               = "dedup_ee_ids"
 )
 
 // DispatcherHCfg
@@ -2493,7 +2617,101 @@ var CGROptionsSet = NewStringSet([]string{OptsRatesStartTime, OptsRatesUsage, Op
 	OptsSessionTTLLastUsed, OptsSessionTTLLastUsage, OptsSessionTTLUsage, OptsDebitInterval, OptsStirATest,
 	OptsStirPayloadMaxDuration, OptsStirIdentity, OptsStirOriginatorTn, OptsStirOriginatorURI,
 	OptsStirDestinationTn, OptsStirDestinationURI, OptsStirPublicKeyPath, OptsStirPrivateKeyPath,
-	OptsAPIKey, OptsRouteID, OptsContext, OptsAttributesProcessRuns, OptsRoutesLimit, OptsRoutesOffset})
+	OptsAPIKey, OptsRouteID, OptsContext, OptsAttributesProcessRuns, OptsRoutesLimit, OptsRoutesOffset,
+	OptsAuthorization, OptsTraceParent})
+
+// Prometheus metrics
+const (
+	MetricCounter   = "counter"
+	MetricGauge     = "gauge"
+	MetricSummary   = "summary"
+	MetricUntyped   = "untyped"
+	MetricMethod    = "method"
+	MetricStatus    = "status"
+	MetricReader    = "reader"
+	MetricExporter  = "exporter"
+	MetricPartition = "partition"
+	MetricTenant    = "tenant"
+	MetricQueue     = "queue"
+	MetricID        = "metric"
+	MetricProcessed = "processed"
+	MetricFailed    = "failed"
+	MetricOther     = "*other"
+
+	MetricAPICallDuration  = "cgrates_api_call_duration_seconds"
+	MetricAPICallErrors    = "cgrates_api_call_errors_total"
+	MetricCapsAllocated    = "cgrates_caps_allocated"
+	MetricCapsLimit        = "cgrates_caps_limit"
+	MetricCapsPeak         = "cgrates_caps_peak"
+	MetricCapsAverage      = "cgrates_caps_average"
+	MetricCacheItems       = "cgrates_cache_items"
+	MetricCacheHits        = "cgrates_cache_hits_total"
+	MetricCacheMisses      = "cgrates_cache_misses_total"
+	MetricSessionsActive   = "cgrates_sessions_active"
+	MetricERsEvents        = "cgrates_ers_events_total"
+	MetricERsDuplicates    = "cgrates_ers_duplicates_total"
+	MetricEEsEvents        = "cgrates_ees_events_total"
+	MetricStatQueueMetric  = "cgrates_stat_queue_metric"
+	MetricGoroutines       = "cgrates_goroutines"
+	MetricMemoryHeapAllocs = "cgrates_memory_heap_alloc_bytes"
+)
+
+// Tracing span attributes, following the OpenTelemetry semantic conventions
+const (
+	TraceServiceName     = "service.name"
+	TraceServiceInstance = "service.instance.id"
+	TraceServiceVersion  = "service.version"
+	TraceRPCSystem       = "rpc.system"
+	TraceRPCService      = "rpc.service"
+	TraceRPCMethod       = "rpc.method"
+	TraceNetPeerName     = "net.peer.name"
+	TraceConnID          = "cgrates.conn_id"
+	TraceDispatcherHost  = "cgrates.dispatcher_host"
+)
+
+// Live events stream
+const (
+	MetaSessionStarted    = "*session_started"
+	MetaSessionTerminated = "*session_terminated"
+	MetaThresholdHit      = "*threshold_hit"
+	MetaStatUpdated       = "*stat_updated"
+	MetaCDRProcessed      = "*cdr_processed"
+
+	StreamTypes   = "types"
+	StreamFilters = "filters"
+	StreamTenant  = "tenant"
+)
+
+// Wholesale billing
+const (
+	Customer     = "Customer"
+	Supplier     = "Supplier"
+	CustomerCost = "CustomerCost"
+	SupplierCost = "SupplierCost"
+	Margin       = "Margin"
+
+	MetaCustomer    = "*customer"
+	MetaSupplier    = "*supplier"
+	MetaDestination = "*destination"
+)
+
+// Invoices
+const (
+	Number     = "Number"
+	CycleStart = "CycleStart"
+	CycleEnd   = "CycleEnd"
+	Lines      = "Lines"
+	Total      = "Total"
+
+	MetaUsage     = "*usage"
+	MetaRecurring = "*recurring"
+)
+
+// Number portability
+const (
+	RoutingNumber = "RoutingNumber"
+	Carrier       = "Carrier"
+)
 
 // EventExporter metrics
 const (
@@ -2538,8 +2756,13 @@ const (
 	// DispatcherS
 	OptsAPIKey  = "*apiKey"
 	OptsRouteID = "*routeID"
+	// CoreS
+	OptsAuthorization = "*authorization"
+	OptsTraceParent   = "*traceparent"
 	// EEs
 	OptsEEsVerbose = "*eesVerbose"
+	// InvoiceS
+	OptsInvoiceFee = "*invoiceFee"
 	// EEs Elasticsearch options
 	ElsIndex               = "index"
 	ElsIfPrimaryTerm       = "if_primary_term"
@@ -2561,6 +2784,8 @@ const (
 	SQLMaxIdleConns    = "maxIdleConns"
 	SQLMaxOpenConns    = "maxOpenConns"
 	SQLMaxConnLifetime = "maxConnLifetime"
+	// FileTemplateEe options
+	TemplatePath = "templatePath"
 
 	// Others
 	OptsContext               = "*context"
@@ -2605,6 +2830,7 @@ const (
 	ChargerProfileIDs             = "ChargerProfileIDs"
 	DispatcherProfileIDs          = "DispatcherProfileIDs"
 	DispatcherHostIDs             = "DispatcherHostIDs"
+	PortedNumberIDs               = "PortedNumberIDs"
 	DispatcherRoutesIDs           = "DispatcherRoutesIDs"
 	RateProfileIDs                = "RateProfileIDs"
 	ActionProfileIDs              = "ActionProfileIDs"
@@ -2675,6 +2901,13 @@ const (
 	ReplyError       = "ReplyError"
 	AnzDBDir         = "db"
 	Opts             = "Opts"
+	RawRequest       = "RawRequest"
+	RawReply         = "RawReply"
+
+	MetaDiameter = "*diameter"
+	MetaRadius   = "*radius"
+	MetaSIP      = "*sip"
+	MetaDNS      = "*dns"
 )
 
 //CMD constants
@@ -2757,6 +2990,14 @@ const (
 	ActionSv1ExecuteActions  = "ActionSv1.ExecuteActions"
 )
 
+// InvoiceS APIs
+const (
+	InvoiceSv1                = "InvoiceSv1"
+	InvoiceSv1Ping            = "InvoiceSv1.Ping"
+	InvoiceSv1GenerateInvoice = "InvoiceSv1.GenerateInvoice"
+	InvoiceSv1GetInvoices     = "InvoiceSv1.GetInvoices"
+)
+
 // Time duration suffix
 const (
 	NsSuffix = "ns"
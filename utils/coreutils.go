@@ -25,6 +25,7 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/gob"
 	"encoding/hex"
 	"encoding/json"
@@ -658,6 +659,16 @@ func (h HierarchyPath) Clone() (cln HierarchyPath) {
 	return
 }
 
+// MaskSecret hides the secret, keeping a short fingerprint to tell the secrets apart
+// the empty secret is returned as it is
+func MaskSecret(secret string) string {
+	if secret == EmptyString {
+		return secret
+	}
+	sum := sha256.Sum256([]byte(secret))
+	return MaskedSecret + hex.EncodeToString(sum[:4])
+}
+
 // Mask a number of characters in the suffix of the destination
 func MaskSuffix(dest string, maskLen int) string {
 	destLen := len(dest)
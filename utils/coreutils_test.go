@@ -970,6 +970,19 @@ func TestParseHierarchyClone(t *testing.T) {
 	}
 }
 
+func TestMaskSecret(t *testing.T) {
+	if masked := MaskSecret(EmptyString); masked != EmptyString {
+		t.Errorf("Expected empty secret, received: %q", masked)
+	}
+	masked := MaskSecret("secret")
+	if masked != "*****2bb80d53" {
+		t.Errorf("Unexpected mask applied: %q", masked)
+	}
+	if other := MaskSecret("secret2"); other == masked {
+		t.Errorf("Expected different masks, received: %q", other)
+	}
+}
+
 func TestMaskSuffix(t *testing.T) {
 	dest := "+4986517174963"
 	if destMasked := MaskSuffix(dest, 3); destMasked != "+4986517174***" {
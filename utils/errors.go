@@ -55,6 +55,7 @@ var (
 	ErrMandatoryIeMissingNoCaps      = errors.New("mandatory information missing")
 	ErrUnauthorizedApi               = errors.New("UNAUTHORIZED_API")
 	ErrUnknownApiKey                 = errors.New("UNKNOWN_API_KEY")
+	ErrNotAuthenticated              = errors.New("NOT_AUTHENTICATED")
 	ErrReqUnsynchronized             = errors.New("REQ_UNSYNCHRONIZED")
 	ErrUnsupporteServiceMethod       = errors.New("UNSUPPORTED_SERVICE_METHOD")
 	ErrDisconnected                  = errors.New("DISCONNECTED")
@@ -74,6 +75,8 @@ var (
 	ErrMaxConcurentRPCExceededNoCaps = errors.New("max concurent rpc exceeded") // on internal we return this error for concureq
 	ErrMaxConcurentRPCExceeded       = errors.New("MAX_CONCURENT_RPC_EXCEEDED") // but the codec will rewrite it with this one to be sure that we corectly dealocate the request
 	ErrMaxIterationsReached          = errors.New("maximum iterations reached")
+	ErrLockLost                      = errors.New("LOCK_LOST")
+	ErrLockTimeout                   = errors.New("LOCK_TIMEOUT")
 
 	ErrMap = map[string]error{
 		ErrNoMoreData.Error():              ErrNoMoreData,
@@ -100,6 +103,7 @@ var (
 		ErrNotConvertibleNoCaps.Error():    ErrNotConvertibleNoCaps,
 		ErrUnauthorizedApi.Error():         ErrUnauthorizedApi,
 		ErrUnknownApiKey.Error():           ErrUnknownApiKey,
+		ErrNotAuthenticated.Error():        ErrNotAuthenticated,
 		ErrReqUnsynchronized.Error():       ErrReqUnsynchronized,
 		ErrUnsupporteServiceMethod.Error(): ErrUnsupporteServiceMethod,
 		ErrDisconnected.Error():            ErrDisconnected,
@@ -113,6 +117,8 @@ var (
 		ErrIndexOutOfBounds.Error():        ErrIndexOutOfBounds,
 		ErrWrongPath.Error():               ErrWrongPath,
 		ErrHostNotFound.Error():            ErrHostNotFound,
+		ErrLockLost.Error():                ErrLockLost,
+		ErrLockTimeout.Error():             ErrLockTimeout,
 	}
 )
 
@@ -32,6 +32,7 @@ type Server interface {
 	RpcRegister(rcvr interface{})
 	RpcRegisterName(name string, rcvr interface{})
 	RegisterHttpFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	RegisterHttpAuthFunc(pattern, method string, handler func(http.ResponseWriter, *http.Request))
 	RegisterHttpHandler(pattern string, handler http.Handler)
 	BiRPCRegisterName(method string, handlerFunc interface{})
 	BiRPCRegister(rcvr interface{})